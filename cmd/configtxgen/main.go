@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-config/protolator/protoext/ordererext"
 	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
@@ -134,7 +135,10 @@ func doInspectBlock(inspectBlock string) error {
 	}
 
 	logger.Info("Parsing genesis block")
-	block, err := protoutil.UnmarshalBlock(data)
+	// The block is decoded as a blockextension.Block, which shares its
+	// encoding, so that the entries of the block extension are rendered
+	block := &blockextension.Block{}
+	err = proto.Unmarshal(data, block)
 	if err != nil {
 		return fmt.Errorf("error unmarshaling to block: %s", err)
 	}
@@ -145,6 +149,37 @@ func doInspectBlock(inspectBlock string) error {
 	return nil
 }
 
+func doInspectExtension(inspectExtension string) error {
+	logger.Info("Inspecting block extension")
+	data, err := ioutil.ReadFile(inspectExtension)
+	if err != nil {
+		return fmt.Errorf("could not read block %s", inspectExtension)
+	}
+
+	logger.Info("Parsing block")
+	block, err := protoutil.UnmarshalBlock(data)
+	if err != nil {
+		return fmt.Errorf("error unmarshaling to block: %s", err)
+	}
+	if block.Extension == nil {
+		logger.Info("Block does not carry an extension")
+		return nil
+	}
+	for i, data := range block.Extension.ExtensionData {
+		entry, err := protoutil.UnmarshalBlockExtensionEntry(data)
+		if err != nil {
+			logger.Warningf("Element %d of the block extension is not an entry: %s", i, err)
+			continue
+		}
+		logger.Infof("Entry %d of type %s, version %d", i, entry.TypeUrl, entry.Version)
+		err = protolator.DeepMarshalJSON(os.Stdout, entry)
+		if err != nil {
+			return fmt.Errorf("malformed entry %d of type %s: %s", i, entry.TypeUrl, err)
+		}
+	}
+	return nil
+}
+
 func doInspectChannelCreateTx(inspectChannelCreateTx string) error {
 	logger.Info("Inspecting transaction")
 	data, err := ioutil.ReadFile(inspectChannelCreateTx)
@@ -153,7 +188,10 @@ func doInspectChannelCreateTx(inspectChannelCreateTx string) error {
 	}
 
 	logger.Info("Parsing transaction")
-	env, err := protoutil.UnmarshalEnvelope(data)
+	// The envelope is decoded as the message returned by ProtolatorMessage, which
+	// shares its encoding, so that the block extension limits are rendered
+	env := blockextension.ProtolatorMessage(&cb.Envelope{})
+	err = proto.Unmarshal(data, env)
 	if err != nil {
 		return fmt.Errorf("Error unmarshaling envelope: %s", err)
 	}
@@ -210,7 +248,7 @@ func dirExists(path string) (bool, error) {
 }
 
 func main() {
-	var outputBlock, outputChannelCreateTx, channelCreateTxBaseProfile, profile, configPath, channelID, inspectBlock, inspectChannelCreateTx, outputAnchorPeersUpdate, asOrg, printOrg string
+	var outputBlock, outputChannelCreateTx, channelCreateTxBaseProfile, profile, configPath, channelID, inspectBlock, inspectExtension, inspectChannelCreateTx, outputAnchorPeersUpdate, asOrg, printOrg string
 
 	flag.StringVar(&outputBlock, "outputBlock", "", "The path to write the genesis block to (if set)")
 	flag.StringVar(&channelID, "channelID", "", "The channel ID to use in the configtx")
@@ -219,6 +257,7 @@ func main() {
 	flag.StringVar(&profile, "profile", "", "The profile from configtx.yaml to use for generation.")
 	flag.StringVar(&configPath, "configPath", "", "The path containing the configuration to use (if set)")
 	flag.StringVar(&inspectBlock, "inspectBlock", "", "Prints the configuration contained in the block at the specified path")
+	flag.StringVar(&inspectExtension, "inspectExtension", "", "Prints the entries of the extension of the block at the specified path, decoding their payloads by type")
 	flag.StringVar(&inspectChannelCreateTx, "inspectChannelCreateTx", "", "Prints the configuration contained in the transaction at the specified path")
 	flag.StringVar(&outputAnchorPeersUpdate, "outputAnchorPeersUpdate", "", "[DEPRECATED] Creates a config update to update an anchor peer (works only with the default channel creation, and only for the first update)")
 	flag.StringVar(&asOrg, "asOrg", "", "Performs the config generation as a particular organization (by name), only including values in the write set that org (likely) has privilege to set")
@@ -305,6 +344,12 @@ func main() {
 		}
 	}
 
+	if inspectExtension != "" {
+		if err := doInspectExtension(inspectExtension); err != nil {
+			logger.Fatalf("Error on inspectExtension: %s", err)
+		}
+	}
+
 	if inspectChannelCreateTx != "" {
 		if err := doInspectChannelCreateTx(inspectChannelCreateTx); err != nil {
 			logger.Fatalf("Error on inspectChannelCreateTx: %s", err)
@@ -17,7 +17,9 @@ import (
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var tmpDir string
@@ -47,6 +49,35 @@ func TestInspectBlock(t *testing.T) {
 	assert.NoError(t, doInspectBlock(blockDest), "Good block inspection request")
 }
 
+func TestInspectLegacyBlock(t *testing.T) {
+	blockDest := filepath.Join(tmpDir, "block")
+
+	config := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+
+	assert.NoError(t, doOutputBlock(config, "foo", blockDest), "Good block generation request")
+	data, err := ioutil.ReadFile(blockDest)
+	require.NoError(t, err)
+	block, err := protoutil.UnmarshalBlock(data)
+	require.NoError(t, err)
+	// the genesis blocks created before the entries were introduced carry the
+	// config envelope as is in their extension
+	block.Extension.ExtensionData = [][]byte{block.Data.Data[0]}
+	require.NoError(t, ioutil.WriteFile(blockDest, protoutil.MarshalOrPanic(block), 0644))
+
+	assert.NoError(t, doInspectBlock(blockDest), "Good legacy block inspection request")
+	assert.NoError(t, doInspectExtension(blockDest), "Good legacy block extension inspection request")
+}
+
+func TestInspectExtension(t *testing.T) {
+	blockDest := filepath.Join(tmpDir, "block")
+
+	config := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+
+	assert.NoError(t, doOutputBlock(config, "foo", blockDest), "Good block generation request")
+	assert.NoError(t, doInspectExtension(blockDest), "Good block extension inspection request")
+	assert.EqualError(t, doInspectExtension(""), "could not read block ")
+}
+
 func TestInspectBlockErr(t *testing.T) {
 	config := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
 
@@ -22,6 +22,7 @@ import (
 	_ "github.com/hyperledger/fabric-protos-go/orderer"
 	_ "github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	_ "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/internal/configtxlator/metadata"
 	"github.com/hyperledger/fabric/internal/configtxlator/rest"
@@ -131,7 +132,7 @@ func encodeProto(msgName string, input, output *os.File) error {
 	if msgType == nil {
 		return errors.Errorf("message of type %s unknown", msgType)
 	}
-	msg := reflect.New(msgType.Elem()).Interface().(proto.Message)
+	msg := blockextension.ProtolatorMessage(reflect.New(msgType.Elem()).Interface().(proto.Message))
 
 	err := protolator.DeepUnmarshalJSON(input, msg)
 	if err != nil {
@@ -156,7 +157,7 @@ func decodeProto(msgName string, input, output *os.File) error {
 	if msgType == nil {
 		return errors.Errorf("message of type %s unknown", msgType)
 	}
-	msg := reflect.New(msgType.Elem()).Interface().(proto.Message)
+	msg := blockextension.ProtolatorMessage(reflect.New(msgType.Elem()).Interface().(proto.Message))
 
 	in, err := ioutil.ReadAll(input)
 	if err != nil {
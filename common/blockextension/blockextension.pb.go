@@ -0,0 +1,882 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: blockextension.proto
+
+package blockextension
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Entry wraps an element of the extension of a block so that the consumers
+// of the block can tell the elements apart without out-of-band knowledge.
+type Entry struct {
+	// type_url identifies the kind of the entry, and hence the encoding of the payload
+	TypeUrl string `protobuf:"bytes,1,opt,name=type_url,json=typeUrl,proto3" json:"type_url,omitempty"`
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// version is the version of the encoding of the payload for the type_url
+	Version uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// producer is the serialized identity (msp.SerializedIdentity) of the node
+	// that produced the entry. It is empty for the entries that the orderers add
+	// when they cut a block, as every orderer must produce the same extension for
+	// the block whichever orderer cuts it, and for the entries of a genesis block
+	Producer []byte `protobuf:"bytes,4,opt,name=producer,proto3" json:"producer,omitempty"`
+	// error is the reason why the producer failed to compute the payload, which
+	// is then empty. The entry is still added so that the blocks of a channel carry
+	// the same type URLs regardless of the failures of the producers
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Entry) Reset()         { *m = Entry{} }
+func (m *Entry) String() string { return proto.CompactTextString(m) }
+func (*Entry) ProtoMessage()    {}
+func (*Entry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{0}
+}
+
+func (m *Entry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Entry.Unmarshal(m, b)
+}
+func (m *Entry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Entry.Marshal(b, m, deterministic)
+}
+func (m *Entry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Entry.Merge(m, src)
+}
+func (m *Entry) XXX_Size() int {
+	return xxx_messageInfo_Entry.Size(m)
+}
+func (m *Entry) XXX_DiscardUnknown() {
+	xxx_messageInfo_Entry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Entry proto.InternalMessageInfo
+
+func (m *Entry) GetTypeUrl() string {
+	if m != nil {
+		return m.TypeUrl
+	}
+	return ""
+}
+
+func (m *Entry) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Entry) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Entry) GetProducer() []byte {
+	if m != nil {
+		return m.Producer
+	}
+	return nil
+}
+
+func (m *Entry) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// MerkleRoot is the payload of the entries of type TxIDMerkleRootType.
+type MerkleRoot struct {
+	Root                 []byte   `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MerkleRoot) Reset()         { *m = MerkleRoot{} }
+func (m *MerkleRoot) String() string { return proto.CompactTextString(m) }
+func (*MerkleRoot) ProtoMessage()    {}
+func (*MerkleRoot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{1}
+}
+
+func (m *MerkleRoot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MerkleRoot.Unmarshal(m, b)
+}
+func (m *MerkleRoot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MerkleRoot.Marshal(b, m, deterministic)
+}
+func (m *MerkleRoot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MerkleRoot.Merge(m, src)
+}
+func (m *MerkleRoot) XXX_Size() int {
+	return xxx_messageInfo_MerkleRoot.Size(m)
+}
+func (m *MerkleRoot) XXX_DiscardUnknown() {
+	xxx_messageInfo_MerkleRoot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MerkleRoot proto.InternalMessageInfo
+
+func (m *MerkleRoot) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+// BloomFilter is the payload of the entries of type NamespaceBloomFilterType.
+type BloomFilter struct {
+	Bits                 []byte   `protobuf:"bytes,1,opt,name=bits,proto3" json:"bits,omitempty"`
+	HashFunctions        uint32   `protobuf:"varint,2,opt,name=hash_functions,json=hashFunctions,proto3" json:"hash_functions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BloomFilter) Reset()         { *m = BloomFilter{} }
+func (m *BloomFilter) String() string { return proto.CompactTextString(m) }
+func (*BloomFilter) ProtoMessage()    {}
+func (*BloomFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{2}
+}
+
+func (m *BloomFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BloomFilter.Unmarshal(m, b)
+}
+func (m *BloomFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BloomFilter.Marshal(b, m, deterministic)
+}
+func (m *BloomFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BloomFilter.Merge(m, src)
+}
+func (m *BloomFilter) XXX_Size() int {
+	return xxx_messageInfo_BloomFilter.Size(m)
+}
+func (m *BloomFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_BloomFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BloomFilter proto.InternalMessageInfo
+
+func (m *BloomFilter) GetBits() []byte {
+	if m != nil {
+		return m.Bits
+	}
+	return nil
+}
+
+func (m *BloomFilter) GetHashFunctions() uint32 {
+	if m != nil {
+		return m.HashFunctions
+	}
+	return 0
+}
+
+// BatchTimestamp is the payload of the entries of type BatchTimestampType.
+type BatchTimestamp struct {
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BatchTimestamp) Reset()         { *m = BatchTimestamp{} }
+func (m *BatchTimestamp) String() string { return proto.CompactTextString(m) }
+func (*BatchTimestamp) ProtoMessage()    {}
+func (*BatchTimestamp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{3}
+}
+
+func (m *BatchTimestamp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchTimestamp.Unmarshal(m, b)
+}
+func (m *BatchTimestamp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchTimestamp.Marshal(b, m, deterministic)
+}
+func (m *BatchTimestamp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchTimestamp.Merge(m, src)
+}
+func (m *BatchTimestamp) XXX_Size() int {
+	return xxx_messageInfo_BatchTimestamp.Size(m)
+}
+func (m *BatchTimestamp) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchTimestamp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchTimestamp proto.InternalMessageInfo
+
+func (m *BatchTimestamp) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+// ExtensionLimits is the value of the BlockExtensionLimits key of the orderer
+// config group, which bounds the extension of the blocks of the channel.
+// A limit of zero means that the extension is not bounded in that respect.
+type ExtensionLimits struct {
+	// max_bytes is the maximum total size of the entries of the extension of a block
+	MaxBytes uint32 `protobuf:"varint,1,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// max_entries is the maximum number of entries of the extension of a block
+	MaxEntries           uint32   `protobuf:"varint,2,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExtensionLimits) Reset()         { *m = ExtensionLimits{} }
+func (m *ExtensionLimits) String() string { return proto.CompactTextString(m) }
+func (*ExtensionLimits) ProtoMessage()    {}
+func (*ExtensionLimits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{4}
+}
+
+func (m *ExtensionLimits) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExtensionLimits.Unmarshal(m, b)
+}
+func (m *ExtensionLimits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExtensionLimits.Marshal(b, m, deterministic)
+}
+func (m *ExtensionLimits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtensionLimits.Merge(m, src)
+}
+func (m *ExtensionLimits) XXX_Size() int {
+	return xxx_messageInfo_ExtensionLimits.Size(m)
+}
+func (m *ExtensionLimits) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtensionLimits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExtensionLimits proto.InternalMessageInfo
+
+func (m *ExtensionLimits) GetMaxBytes() uint32 {
+	if m != nil {
+		return m.MaxBytes
+	}
+	return 0
+}
+
+func (m *ExtensionLimits) GetMaxEntries() uint32 {
+	if m != nil {
+		return m.MaxEntries
+	}
+	return 0
+}
+
+// Block shares the encoding of common.Block, and is used in its place by
+// protolator in order to render the entries of the block extension.
+type Block struct {
+	Header               *common.BlockHeader   `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Data                 *common.BlockData     `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Metadata             *common.BlockMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Extension            *BlockExtension       `protobuf:"bytes,4,opt,name=extension,proto3" json:"extension,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{5}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetHeader() *common.BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Block) GetData() *common.BlockData {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Block) GetMetadata() *common.BlockMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Block) GetExtension() *BlockExtension {
+	if m != nil {
+		return m.Extension
+	}
+	return nil
+}
+
+// BlockExtension shares the encoding of the extension of common.Block.
+type BlockExtension struct {
+	ExtensionData        [][]byte `protobuf:"bytes,1,rep,name=extension_data,json=extensionData,proto3" json:"extension_data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockExtension) Reset()         { *m = BlockExtension{} }
+func (m *BlockExtension) String() string { return proto.CompactTextString(m) }
+func (*BlockExtension) ProtoMessage()    {}
+func (*BlockExtension) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{6}
+}
+
+func (m *BlockExtension) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockExtension.Unmarshal(m, b)
+}
+func (m *BlockExtension) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockExtension.Marshal(b, m, deterministic)
+}
+func (m *BlockExtension) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockExtension.Merge(m, src)
+}
+func (m *BlockExtension) XXX_Size() int {
+	return xxx_messageInfo_BlockExtension.Size(m)
+}
+func (m *BlockExtension) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockExtension.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockExtension proto.InternalMessageInfo
+
+func (m *BlockExtension) GetExtensionData() [][]byte {
+	if m != nil {
+		return m.ExtensionData
+	}
+	return nil
+}
+
+// BlockNumbers is the result of the lookup of the blocks whose extension carries
+// an entry with a given key.
+type BlockNumbers struct {
+	Numbers              []uint64 `protobuf:"varint,1,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockNumbers) Reset()         { *m = BlockNumbers{} }
+func (m *BlockNumbers) String() string { return proto.CompactTextString(m) }
+func (*BlockNumbers) ProtoMessage()    {}
+func (*BlockNumbers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{7}
+}
+
+func (m *BlockNumbers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockNumbers.Unmarshal(m, b)
+}
+func (m *BlockNumbers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockNumbers.Marshal(b, m, deterministic)
+}
+func (m *BlockNumbers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockNumbers.Merge(m, src)
+}
+func (m *BlockNumbers) XXX_Size() int {
+	return xxx_messageInfo_BlockNumbers.Size(m)
+}
+func (m *BlockNumbers) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockNumbers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockNumbers proto.InternalMessageInfo
+
+func (m *BlockNumbers) GetNumbers() []uint64 {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
+// ExtensionFilter selects the entries of the block extensions that are sent by
+// DeliverExtensions. It is carried, marshaled, in the extension of the channel
+// header of the request. All the entries are selected if type_urls is empty.
+type ExtensionFilter struct {
+	TypeUrls             []string `protobuf:"bytes,1,rep,name=type_urls,json=typeUrls,proto3" json:"type_urls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExtensionFilter) Reset()         { *m = ExtensionFilter{} }
+func (m *ExtensionFilter) String() string { return proto.CompactTextString(m) }
+func (*ExtensionFilter) ProtoMessage()    {}
+func (*ExtensionFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{8}
+}
+
+func (m *ExtensionFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExtensionFilter.Unmarshal(m, b)
+}
+func (m *ExtensionFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExtensionFilter.Marshal(b, m, deterministic)
+}
+func (m *ExtensionFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtensionFilter.Merge(m, src)
+}
+func (m *ExtensionFilter) XXX_Size() int {
+	return xxx_messageInfo_ExtensionFilter.Size(m)
+}
+func (m *ExtensionFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtensionFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExtensionFilter proto.InternalMessageInfo
+
+func (m *ExtensionFilter) GetTypeUrls() []string {
+	if m != nil {
+		return m.TypeUrls
+	}
+	return nil
+}
+
+// VerifiableBlockExtension carries the extension of a block along with the parts of the
+// block that its consumers need to verify it without the data of the block: the header of
+// the block and the entries of the block metadata that carry the orderer signatures and
+// the hash of the extension, which the orderers sign along with the header.
+type VerifiableBlockExtension struct {
+	Header    *common.BlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Extension *BlockExtension     `protobuf:"bytes,2,opt,name=extension,proto3" json:"extension,omitempty"`
+	// signatures is the SIGNATURES entry of the block metadata, a marshaled common.Metadata
+	Signatures []byte `protobuf:"bytes,3,opt,name=signatures,proto3" json:"signatures,omitempty"`
+	// extension_hash is the entry of the block metadata that records the hash of the
+	// extension, a marshaled common.Metadata. It is empty for the blocks created before
+	// the orderers started committing to the extension, whose extension cannot be verified
+	ExtensionHash        []byte   `protobuf:"bytes,4,opt,name=extension_hash,json=extensionHash,proto3" json:"extension_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifiableBlockExtension) Reset()         { *m = VerifiableBlockExtension{} }
+func (m *VerifiableBlockExtension) String() string { return proto.CompactTextString(m) }
+func (*VerifiableBlockExtension) ProtoMessage()    {}
+func (*VerifiableBlockExtension) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{9}
+}
+
+func (m *VerifiableBlockExtension) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifiableBlockExtension.Unmarshal(m, b)
+}
+func (m *VerifiableBlockExtension) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifiableBlockExtension.Marshal(b, m, deterministic)
+}
+func (m *VerifiableBlockExtension) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifiableBlockExtension.Merge(m, src)
+}
+func (m *VerifiableBlockExtension) XXX_Size() int {
+	return xxx_messageInfo_VerifiableBlockExtension.Size(m)
+}
+func (m *VerifiableBlockExtension) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifiableBlockExtension.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifiableBlockExtension proto.InternalMessageInfo
+
+func (m *VerifiableBlockExtension) GetHeader() *common.BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *VerifiableBlockExtension) GetExtension() *BlockExtension {
+	if m != nil {
+		return m.Extension
+	}
+	return nil
+}
+
+func (m *VerifiableBlockExtension) GetSignatures() []byte {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+func (m *VerifiableBlockExtension) GetExtensionHash() []byte {
+	if m != nil {
+		return m.ExtensionHash
+	}
+	return nil
+}
+
+// FilteredBlockExtension carries the selected entries of the extension of a block, and the
+// extension they were selected from along with what is needed to verify it.
+type FilteredBlockExtension struct {
+	ChannelId            string                    `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Number               uint64                    `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	Entries              []*Entry                  `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	VerifiableExtension  *VerifiableBlockExtension `protobuf:"bytes,4,opt,name=verifiable_extension,json=verifiableExtension,proto3" json:"verifiable_extension,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *FilteredBlockExtension) Reset()         { *m = FilteredBlockExtension{} }
+func (m *FilteredBlockExtension) String() string { return proto.CompactTextString(m) }
+func (*FilteredBlockExtension) ProtoMessage()    {}
+func (*FilteredBlockExtension) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{10}
+}
+
+func (m *FilteredBlockExtension) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilteredBlockExtension.Unmarshal(m, b)
+}
+func (m *FilteredBlockExtension) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilteredBlockExtension.Marshal(b, m, deterministic)
+}
+func (m *FilteredBlockExtension) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilteredBlockExtension.Merge(m, src)
+}
+func (m *FilteredBlockExtension) XXX_Size() int {
+	return xxx_messageInfo_FilteredBlockExtension.Size(m)
+}
+func (m *FilteredBlockExtension) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilteredBlockExtension.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilteredBlockExtension proto.InternalMessageInfo
+
+func (m *FilteredBlockExtension) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *FilteredBlockExtension) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *FilteredBlockExtension) GetEntries() []*Entry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *FilteredBlockExtension) GetVerifiableExtension() *VerifiableBlockExtension {
+	if m != nil {
+		return m.VerifiableExtension
+	}
+	return nil
+}
+
+// DeliverExtensionsResponse is the response of DeliverExtensions.
+type DeliverExtensionsResponse struct {
+	// Types that are valid to be assigned to Type:
+	//	*DeliverExtensionsResponse_Status
+	//	*DeliverExtensionsResponse_FilteredBlockExtension
+	Type                 isDeliverExtensionsResponse_Type `protobuf_oneof:"Type"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *DeliverExtensionsResponse) Reset()         { *m = DeliverExtensionsResponse{} }
+func (m *DeliverExtensionsResponse) String() string { return proto.CompactTextString(m) }
+func (*DeliverExtensionsResponse) ProtoMessage()    {}
+func (*DeliverExtensionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_53084bd80abeb35e, []int{11}
+}
+
+func (m *DeliverExtensionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliverExtensionsResponse.Unmarshal(m, b)
+}
+func (m *DeliverExtensionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliverExtensionsResponse.Marshal(b, m, deterministic)
+}
+func (m *DeliverExtensionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliverExtensionsResponse.Merge(m, src)
+}
+func (m *DeliverExtensionsResponse) XXX_Size() int {
+	return xxx_messageInfo_DeliverExtensionsResponse.Size(m)
+}
+func (m *DeliverExtensionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliverExtensionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliverExtensionsResponse proto.InternalMessageInfo
+
+type isDeliverExtensionsResponse_Type interface {
+	isDeliverExtensionsResponse_Type()
+}
+
+type DeliverExtensionsResponse_Status struct {
+	Status common.Status `protobuf:"varint,1,opt,name=status,proto3,enum=common.Status,oneof"`
+}
+
+type DeliverExtensionsResponse_FilteredBlockExtension struct {
+	FilteredBlockExtension *FilteredBlockExtension `protobuf:"bytes,2,opt,name=filtered_block_extension,json=filteredBlockExtension,proto3,oneof"`
+}
+
+func (*DeliverExtensionsResponse_Status) isDeliverExtensionsResponse_Type() {}
+
+func (*DeliverExtensionsResponse_FilteredBlockExtension) isDeliverExtensionsResponse_Type() {}
+
+func (m *DeliverExtensionsResponse) GetType() isDeliverExtensionsResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *DeliverExtensionsResponse) GetStatus() common.Status {
+	if x, ok := m.GetType().(*DeliverExtensionsResponse_Status); ok {
+		return x.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+func (m *DeliverExtensionsResponse) GetFilteredBlockExtension() *FilteredBlockExtension {
+	if x, ok := m.GetType().(*DeliverExtensionsResponse_FilteredBlockExtension); ok {
+		return x.FilteredBlockExtension
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*DeliverExtensionsResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*DeliverExtensionsResponse_Status)(nil),
+		(*DeliverExtensionsResponse_FilteredBlockExtension)(nil),
+	}
+}
+
+func init() {
+	proto.RegisterType((*Entry)(nil), "blockextension.Entry")
+	proto.RegisterType((*MerkleRoot)(nil), "blockextension.MerkleRoot")
+	proto.RegisterType((*BloomFilter)(nil), "blockextension.BloomFilter")
+	proto.RegisterType((*BatchTimestamp)(nil), "blockextension.BatchTimestamp")
+	proto.RegisterType((*ExtensionLimits)(nil), "blockextension.ExtensionLimits")
+	proto.RegisterType((*Block)(nil), "blockextension.Block")
+	proto.RegisterType((*BlockExtension)(nil), "blockextension.BlockExtension")
+	proto.RegisterType((*BlockNumbers)(nil), "blockextension.BlockNumbers")
+	proto.RegisterType((*ExtensionFilter)(nil), "blockextension.ExtensionFilter")
+	proto.RegisterType((*VerifiableBlockExtension)(nil), "blockextension.VerifiableBlockExtension")
+	proto.RegisterType((*FilteredBlockExtension)(nil), "blockextension.FilteredBlockExtension")
+	proto.RegisterType((*DeliverExtensionsResponse)(nil), "blockextension.DeliverExtensionsResponse")
+}
+
+func init() { proto.RegisterFile("blockextension.proto", fileDescriptor_53084bd80abeb35e) }
+
+var fileDescriptor_53084bd80abeb35e = []byte{
+	// 750 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xcb, 0x6e, 0xe3, 0x36,
+	0x14, 0x8d, 0xe2, 0x47, 0xec, 0xeb, 0x47, 0x13, 0x26, 0x0d, 0x14, 0x07, 0x4d, 0x0c, 0x01, 0x29,
+	0x54, 0x14, 0xb0, 0x5b, 0x07, 0x45, 0xbb, 0xe8, 0xca, 0x88, 0x03, 0xb7, 0x68, 0x5a, 0x80, 0x4d,
+	0xbb, 0x98, 0x59, 0x08, 0x94, 0x75, 0x6d, 0x09, 0x91, 0x44, 0x81, 0xa2, 0x0c, 0xfb, 0x0f, 0xe6,
+	0x77, 0xe6, 0x3f, 0xe6, 0x17, 0xe6, 0x5f, 0x06, 0xa2, 0x1e, 0x8e, 0x35, 0xc9, 0x22, 0x2b, 0xeb,
+	0x9e, 0x7b, 0x48, 0x1d, 0x9e, 0x7b, 0x28, 0xc3, 0x99, 0xed, 0xf3, 0xc5, 0x13, 0x6e, 0x24, 0x86,
+	0xb1, 0xc7, 0xc3, 0x51, 0x24, 0xb8, 0xe4, 0xa4, 0xbf, 0x8f, 0x0e, 0x4e, 0x17, 0x3c, 0x08, 0x78,
+	0x38, 0xce, 0x7e, 0x32, 0xd2, 0xe0, 0x7a, 0xc5, 0xf9, 0xca, 0xc7, 0xb1, 0xaa, 0xec, 0x64, 0x39,
+	0x96, 0x5e, 0x80, 0xb1, 0x64, 0x41, 0x94, 0x11, 0x8c, 0x0f, 0x1a, 0x34, 0x66, 0xa1, 0x14, 0x5b,
+	0x72, 0x01, 0x2d, 0xb9, 0x8d, 0xd0, 0x4a, 0x84, 0xaf, 0x6b, 0x43, 0xcd, 0x6c, 0xd3, 0xa3, 0xb4,
+	0xfe, 0x4f, 0xf8, 0x44, 0x87, 0xa3, 0x88, 0x6d, 0x7d, 0xce, 0x1c, 0xfd, 0x70, 0xa8, 0x99, 0x5d,
+	0x5a, 0x94, 0x69, 0x67, 0x8d, 0x22, 0x7d, 0xbf, 0x5e, 0x1b, 0x6a, 0x66, 0x8f, 0x16, 0x25, 0x19,
+	0x40, 0x2b, 0x12, 0xdc, 0x49, 0x16, 0x28, 0xf4, 0xba, 0x5a, 0x54, 0xd6, 0xe4, 0x0c, 0x1a, 0x28,
+	0x04, 0x17, 0x7a, 0x43, 0xbd, 0x27, 0x2b, 0x8c, 0x21, 0xc0, 0x03, 0x8a, 0x27, 0x1f, 0x29, 0xe7,
+	0x92, 0x10, 0xa8, 0x0b, 0xce, 0xa5, 0x92, 0xd2, 0xa5, 0xea, 0xd9, 0x98, 0x43, 0x67, 0xea, 0x73,
+	0x1e, 0xdc, 0x7b, 0xbe, 0x44, 0x91, 0x52, 0x6c, 0x4f, 0xc6, 0x05, 0x25, 0x7d, 0x26, 0x37, 0xd0,
+	0x77, 0x59, 0xec, 0x5a, 0xcb, 0x24, 0x5c, 0x48, 0x8f, 0x87, 0xb1, 0x52, 0xdc, 0xa3, 0xbd, 0x14,
+	0xbd, 0x2f, 0x40, 0xe3, 0x4f, 0xe8, 0x4f, 0x99, 0x5c, 0xb8, 0x8f, 0x85, 0x1d, 0xe4, 0x37, 0x68,
+	0x97, 0xde, 0xa8, 0x1d, 0x3b, 0x93, 0xc1, 0x28, 0x73, 0x6f, 0x54, 0xb8, 0x37, 0x2a, 0xe9, 0x74,
+	0x47, 0x36, 0xfe, 0x81, 0x6f, 0x66, 0xc5, 0x14, 0xfe, 0xf2, 0x82, 0x54, 0xc5, 0x25, 0xb4, 0x03,
+	0xb6, 0xb1, 0xec, 0xad, 0xc4, 0x4c, 0x5e, 0x8f, 0xb6, 0x02, 0xb6, 0x99, 0xa6, 0x35, 0xb9, 0x86,
+	0x4e, 0xda, 0xc4, 0x50, 0x0a, 0x0f, 0x0b, 0x7d, 0x10, 0xb0, 0xcd, 0x2c, 0x43, 0x8c, 0x4f, 0x1a,
+	0x34, 0xa6, 0xe9, 0x70, 0xc9, 0x8f, 0xd0, 0x74, 0x91, 0x39, 0x28, 0x72, 0x45, 0xa7, 0xa3, 0x7c,
+	0xba, 0xaa, 0x3d, 0x57, 0x2d, 0x9a, 0x53, 0xc8, 0x0d, 0xd4, 0x1d, 0x26, 0x99, 0xda, 0xb0, 0x33,
+	0x39, 0xd9, 0xa3, 0xde, 0x31, 0xc9, 0xa8, 0x6a, 0x93, 0x9f, 0xa1, 0x15, 0xa0, 0x64, 0x8a, 0x5a,
+	0x53, 0xd4, 0x6f, 0xf7, 0xa8, 0x0f, 0x79, 0x93, 0x96, 0x34, 0xf2, 0x3b, 0xb4, 0xcb, 0x9c, 0xa9,
+	0x61, 0x76, 0x26, 0x57, 0xa3, 0x4a, 0x28, 0xd5, 0xda, 0xd2, 0x07, 0xba, 0x5b, 0x60, 0xfc, 0x0a,
+	0xfd, 0xfd, 0x66, 0x3a, 0xa4, 0xb2, 0x6d, 0x29, 0x21, 0xda, 0xb0, 0x66, 0x76, 0x69, 0xaf, 0x44,
+	0x53, 0xbd, 0x86, 0x09, 0x5d, 0xb5, 0xf0, 0xef, 0x24, 0xb0, 0x51, 0xc4, 0x69, 0xd8, 0xc2, 0xec,
+	0x51, 0xf1, 0xeb, 0xb4, 0x28, 0x8d, 0xd1, 0xb3, 0x11, 0xe4, 0xe1, 0xb8, 0x84, 0x76, 0x11, 0xe7,
+	0x8c, 0xde, 0xa6, 0xad, 0x3c, 0xcf, 0xca, 0x61, 0xfd, 0x7f, 0x14, 0xde, 0xd2, 0x63, 0xb6, 0x8f,
+	0x15, 0x75, 0x6f, 0x32, 0x7d, 0xcf, 0x9a, 0xc3, 0x37, 0x5a, 0x43, 0xae, 0x00, 0x62, 0x6f, 0x15,
+	0x32, 0x99, 0x08, 0x8c, 0xd5, 0x34, 0xba, 0xf4, 0x19, 0xb2, 0x6f, 0x54, 0x9a, 0xe0, 0xfc, 0x2a,
+	0xed, 0x8c, 0x9a, 0xb3, 0xd8, 0x35, 0x3e, 0x6b, 0x70, 0x9e, 0x1d, 0x1b, 0x9d, 0xca, 0x61, 0xbe,
+	0x03, 0x58, 0xb8, 0x2c, 0x0c, 0xd1, 0xb7, 0x3c, 0x27, 0xbf, 0xd7, 0xed, 0x1c, 0xf9, 0xc3, 0x21,
+	0xe7, 0xd0, 0xcc, 0x3c, 0x54, 0xda, 0xeb, 0x34, 0xaf, 0xc8, 0x18, 0x8e, 0x8a, 0x7c, 0xd6, 0x86,
+	0x35, 0x95, 0x91, 0xca, 0xa1, 0xd4, 0x47, 0x83, 0x16, 0x2c, 0xf2, 0x1e, 0xce, 0xd6, 0xa5, 0xa1,
+	0x56, 0x35, 0x2d, 0x66, 0x75, 0xf5, 0x6b, 0xe6, 0xd3, 0xd3, 0xdd, 0x2e, 0x25, 0x68, 0x7c, 0xd4,
+	0xe0, 0xe2, 0x0e, 0x7d, 0x6f, 0x8d, 0xa2, 0x04, 0x63, 0x8a, 0x71, 0xc4, 0xc3, 0x18, 0x89, 0x09,
+	0xcd, 0x58, 0x32, 0x99, 0x64, 0x37, 0xad, 0x3f, 0xe9, 0x17, 0xf3, 0xfa, 0x57, 0xa1, 0xf3, 0x03,
+	0x9a, 0xf7, 0x89, 0x0d, 0xfa, 0x32, 0xb7, 0xc9, 0x52, 0x82, 0xac, 0xea, 0xec, 0xbe, 0xaf, 0x0a,
+	0x7d, 0xd9, 0xd6, 0xf9, 0x01, 0x3d, 0x5f, 0xbe, 0xd8, 0x99, 0x36, 0xa1, 0xfe, 0xb8, 0x8d, 0x70,
+	0xe2, 0xc2, 0x71, 0x09, 0xe6, 0xda, 0xc9, 0x23, 0x9c, 0x7c, 0x75, 0x0c, 0x72, 0x5c, 0xc8, 0x9d,
+	0x85, 0x6b, 0xf4, 0x79, 0x84, 0x83, 0x1f, 0xaa, 0x22, 0x5e, 0x3d, 0xbb, 0xa9, 0xfd, 0xa4, 0x4d,
+	0x7f, 0x79, 0x77, 0xbb, 0xf2, 0xa4, 0x9b, 0xd8, 0xe9, 0x46, 0x63, 0x77, 0x1b, 0xa1, 0xf0, 0xd1,
+	0x59, 0xa1, 0x18, 0x2f, 0x99, 0x2d, 0xbc, 0x45, 0xfe, 0x8f, 0x30, 0xde, 0xdf, 0xd4, 0x6e, 0xaa,
+	0xaf, 0xda, 0xed, 0x97, 0x01, 0x00, 0x7d, 0x04, 0x82, 0x07, 0x5e, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ExtensionDeliverClient is the client API for ExtensionDeliver service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ExtensionDeliverClient interface {
+	// DeliverExtensions first requires an Envelope of type DELIVER_SEEK_INFO with
+	// Payload data as a marshaled orderer.SeekInfo message, and optionally an
+	// ExtensionFilter in the extension of its channel header, then a stream of
+	// the selected entries of the extensions of the blocks is replied.
+	DeliverExtensions(ctx context.Context, opts ...grpc.CallOption) (ExtensionDeliver_DeliverExtensionsClient, error)
+}
+
+type extensionDeliverClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtensionDeliverClient(cc grpc.ClientConnInterface) ExtensionDeliverClient {
+	return &extensionDeliverClient{cc}
+}
+
+func (c *extensionDeliverClient) DeliverExtensions(ctx context.Context, opts ...grpc.CallOption) (ExtensionDeliver_DeliverExtensionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ExtensionDeliver_serviceDesc.Streams[0], "/blockextension.ExtensionDeliver/DeliverExtensions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &extensionDeliverDeliverExtensionsClient{stream}
+	return x, nil
+}
+
+type ExtensionDeliver_DeliverExtensionsClient interface {
+	Send(*common.Envelope) error
+	Recv() (*DeliverExtensionsResponse, error)
+	grpc.ClientStream
+}
+
+type extensionDeliverDeliverExtensionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *extensionDeliverDeliverExtensionsClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *extensionDeliverDeliverExtensionsClient) Recv() (*DeliverExtensionsResponse, error) {
+	m := new(DeliverExtensionsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ExtensionDeliverServer is the server API for ExtensionDeliver service.
+type ExtensionDeliverServer interface {
+	// DeliverExtensions first requires an Envelope of type DELIVER_SEEK_INFO with
+	// Payload data as a marshaled orderer.SeekInfo message, and optionally an
+	// ExtensionFilter in the extension of its channel header, then a stream of
+	// the selected entries of the extensions of the blocks is replied.
+	DeliverExtensions(ExtensionDeliver_DeliverExtensionsServer) error
+}
+
+// UnimplementedExtensionDeliverServer can be embedded to have forward compatible implementations.
+type UnimplementedExtensionDeliverServer struct {
+}
+
+func (*UnimplementedExtensionDeliverServer) DeliverExtensions(srv ExtensionDeliver_DeliverExtensionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeliverExtensions not implemented")
+}
+
+func RegisterExtensionDeliverServer(s *grpc.Server, srv ExtensionDeliverServer) {
+	s.RegisterService(&_ExtensionDeliver_serviceDesc, srv)
+}
+
+func _ExtensionDeliver_DeliverExtensions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ExtensionDeliverServer).DeliverExtensions(&extensionDeliverDeliverExtensionsServer{stream})
+}
+
+type ExtensionDeliver_DeliverExtensionsServer interface {
+	Send(*DeliverExtensionsResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type extensionDeliverDeliverExtensionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *extensionDeliverDeliverExtensionsServer) Send(m *DeliverExtensionsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *extensionDeliverDeliverExtensionsServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _ExtensionDeliver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "blockextension.ExtensionDeliver",
+	HandlerType: (*ExtensionDeliverServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DeliverExtensions",
+			Handler:       _ExtensionDeliver_DeliverExtensions_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "blockextension.proto",
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// NewBloomFilter creates an empty bloom filter of the given size, rounded up to a multiple
+// of 8 bits, which sets the given number of bits for each namespace.
+func NewBloomFilter(bits, hashFunctions uint32) *BloomFilter {
+	return &BloomFilter{
+		Bits:          make([]byte, (bits+7)/8),
+		HashFunctions: hashFunctions,
+	}
+}
+
+// Add adds the namespace to the filter.
+func (m *BloomFilter) Add(namespace string) {
+	for _, pos := range m.positions(namespace) {
+		m.Bits[pos/8] |= 1 << (pos % 8)
+	}
+}
+
+// MayContain returns false if the namespace was certainly not added to the filter.
+func (m *BloomFilter) MayContain(namespace string) bool {
+	if len(m.Bits) == 0 {
+		return true
+	}
+	for _, pos := range m.positions(namespace) {
+		if m.Bits[pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// positions returns the bits of the namespace, which are derived from the SHA-256
+// hash of the index of each hash function, in big endian, followed by the namespace.
+func (m *BloomFilter) positions(namespace string) []uint64 {
+	size := uint64(len(m.Bits)) * 8
+	if size == 0 {
+		return nil
+	}
+	positions := make([]uint64, m.HashFunctions)
+	for i := range positions {
+		h := sha256.New()
+		binary.Write(h, binary.BigEndian, uint32(i))
+		h.Write([]byte(namespace))
+		positions[i] = binary.BigEndian.Uint64(h.Sum(nil)) % size
+	}
+	return positions
+}
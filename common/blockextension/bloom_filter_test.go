@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	filter := NewBloomFilter(1023, 4)
+	assert.Len(t, filter.Bits, 128)
+	assert.Equal(t, uint32(4), filter.HashFunctions)
+	assert.False(t, filter.MayContain("ns0"))
+
+	for i := 0; i < 50; i++ {
+		filter.Add(fmt.Sprintf("ns%d", i))
+	}
+	for i := 0; i < 50; i++ {
+		assert.True(t, filter.MayContain(fmt.Sprintf("ns%d", i)))
+	}
+
+	assert.True(t, (&BloomFilter{}).MayContain("anything"))
+}
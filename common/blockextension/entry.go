@@ -0,0 +1,334 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"unicode"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// TypeURLPrefix prefixes the type URLs of the entries defined by Fabric.
+const TypeURLPrefix = "hyperledger.org/fabric/blockextension/"
+
+const (
+	// ConfigEnvelopeType is the type URL of the entries whose payload is the
+	// marshaled config Envelope of a genesis block.
+	ConfigEnvelopeType = TypeURLPrefix + "ConfigEnvelope"
+
+	// TxIDMerkleRootType is the type URL of the entries whose payload is a
+	// marshaled MerkleRoot over the transaction IDs of the block.
+	TxIDMerkleRootType = TypeURLPrefix + "TxIDMerkleRoot"
+
+	// NamespaceBloomFilterType is the type URL of the entries whose payload is a
+	// marshaled BloomFilter of the chaincode namespaces touched by the transactions
+	// of the block.
+	NamespaceBloomFilterType = TypeURLPrefix + "NamespaceBloomFilter"
+
+	// BatchTimestampType is the type URL of the entries whose payload is a marshaled
+	// BatchTimestamp with the timestamp of the batch of the block.
+	BatchTimestampType = TypeURLPrefix + "BatchTimestamp"
+)
+
+var (
+	payloadTypesLock sync.RWMutex
+	payloadTypes     = map[string]func() proto.Message{
+		// The config envelope is decoded with the upstream message, which
+		// shares its encoding and is understood by protolator.
+		ConfigEnvelopeType:       func() proto.Message { return &common.Envelope{} },
+		TxIDMerkleRootType:       func() proto.Message { return &MerkleRoot{} },
+		NamespaceBloomFilterType: func() proto.Message { return &BloomFilter{} },
+		BatchTimestampType:       func() proto.Message { return &BatchTimestamp{} },
+	}
+)
+
+// RegisterPayloadType registers the message into which the payload of the entries with the
+// given type URL is unmarshaled, so that protolator renders it as JSON. The payloads of the
+// type URLs that are not registered are rendered as base64 and cannot be encoded back once
+// their rendering is edited. It panics if the type URL is already registered.
+func RegisterPayloadType(typeURL string, newPayload func() proto.Message) {
+	payloadTypesLock.Lock()
+	defer payloadTypesLock.Unlock()
+
+	if _, exists := payloadTypes[typeURL]; exists {
+		panic(fmt.Sprintf("payload type of %s is already registered", typeURL))
+	}
+	payloadTypes[typeURL] = newPayload
+}
+
+// PayloadType returns a newly allocated message for the payload of the entries with the given
+// type URL, or nil if the type URL is not registered.
+func PayloadType(typeURL string) proto.Message {
+	payloadTypesLock.RLock()
+	defer payloadTypesLock.RUnlock()
+
+	newPayload, exists := payloadTypes[typeURL]
+	if !exists {
+		return nil
+	}
+	return newPayload()
+}
+
+// StaticallyOpaqueFields is used by protolator to render the producer identity.
+func (m *Entry) StaticallyOpaqueFields() []string {
+	return []string{"producer"}
+}
+
+// StaticallyOpaqueFieldProto is used by protolator to render the producer identity.
+func (m *Entry) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != m.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &msp.SerializedIdentity{}, nil
+}
+
+// VariablyOpaqueFields is used by protolator to render the payload of the entries
+// whose type URL is registered. When encoding, the type URL is not known yet. The
+// missing payload of the entries of the producers that failed is rendered as null,
+// whatever the type URL, so that these entries can be encoded back.
+func (m *Entry) VariablyOpaqueFields() []string {
+	if m.Error == "" && m.TypeUrl != "" && PayloadType(m.TypeUrl) == nil {
+		return nil
+	}
+	return []string{"payload"}
+}
+
+// VariablyOpaqueFieldProto is used by protolator to render the payload of the entries
+// whose type URL is registered.
+func (m *Entry) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "payload" {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	payload := PayloadType(m.TypeUrl)
+	if payload == nil {
+		return nil, fmt.Errorf("unknown payload type of %s", m.TypeUrl)
+	}
+	return decorate(payload), nil
+}
+
+// UnmarshalEntry unmarshals an element of the extension of a block into an Entry.
+// An error is returned if the element is not an entry, as is the case of the config
+// envelope carried as is by the genesis blocks created before the entries were
+// introduced. Such an envelope cannot be mistaken for an entry, as its first field,
+// which overlaps the type URL, is a marshaled message starting with a control character.
+func UnmarshalEntry(data []byte) (*Entry, error) {
+	entry := &Entry{}
+	if err := proto.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	if !isTypeURL(entry.TypeUrl) {
+		return nil, fmt.Errorf("invalid type URL %q", entry.TypeUrl)
+	}
+	return entry, nil
+}
+
+func isTypeURL(typeURL string) bool {
+	if typeURL == "" {
+		return false
+	}
+	for _, r := range typeURL {
+		if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// unmarshalLegacyConfigEnvelope unmarshals an element of the extension of a genesis
+// block created before the entries were introduced, which is the config envelope.
+func unmarshalLegacyConfigEnvelope(data []byte) (*common.Envelope, bool) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, false
+	}
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil || payload.Header == nil {
+		return nil, false
+	}
+	return envelope, true
+}
+
+// rawField is the field of the rendering of an element of the block extension that carries
+// the bytes of the element, so that an element that is not edited is encoded back as is.
+const rawField = "raw"
+
+// MarshalJSONPB is used by protolator to render the elements of the block extension.
+// The entries are rendered as such and the config envelope of the genesis blocks created
+// before the entries were introduced is rendered as an Envelope. Any other element is
+// rendered as base64. The rendering of the entries and of the config envelope also carries
+// the bytes of the element as base64 in the field "raw", as encoding the decoded element
+// back does not necessarily yield the same bytes, which are covered by the extension hash.
+func (m *BlockExtension) MarshalJSONPB(*jsonpb.Marshaler) ([]byte, error) {
+	elements := make([]json.RawMessage, len(m.ExtensionData))
+	for i, data := range m.ExtensionData {
+		element, decoded, err := marshalElement(data)
+		if err != nil {
+			return nil, fmt.Errorf("element %d of extension_data: %s", i, err)
+		}
+		if decoded {
+			if element, err = withRawField(element, data); err != nil {
+				return nil, fmt.Errorf("element %d of extension_data: %s", i, err)
+			}
+		}
+		elements[i] = element
+	}
+	return json.Marshal(map[string][]json.RawMessage{"extension_data": elements})
+}
+
+// marshalElement renders an element of the block extension, and tells whether the element
+// was decoded, which is the case of the entries and of the legacy config envelope.
+func marshalElement(data []byte) (json.RawMessage, bool, error) {
+	var msg proto.Message
+	if entry, err := UnmarshalEntry(data); err == nil {
+		msg = entry
+	} else if envelope, ok := unmarshalLegacyConfigEnvelope(data); ok {
+		msg = decorate(envelope)
+	} else {
+		element, err := json.Marshal(data)
+		return element, false, err
+	}
+	var buffer bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buffer, msg); err != nil {
+		return nil, false, err
+	}
+	return buffer.Bytes(), true, nil
+}
+
+// withRawField adds the field carrying the bytes of the element to the rendered object.
+func withRawField(element json.RawMessage, data []byte) (json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	element = bytes.TrimRight(element, " \t\r\n")
+	if len(element) < 2 || element[len(element)-1] != '}' {
+		return nil, fmt.Errorf("rendered element is not an object")
+	}
+	separator := ","
+	if bytes.Equal(bytes.TrimSpace(element[:len(element)-1]), []byte("{")) {
+		separator = ""
+	}
+	field := fmt.Sprintf("%s%q:%s}", separator, rawField, raw)
+	return append(element[:len(element)-1:len(element)-1], field...), nil
+}
+
+// UnmarshalJSONPB is used by protolator to encode back the elements of the block extension
+// rendered by MarshalJSONPB. An element that carries its bytes, and whose rendering is the
+// same as the rendering of these bytes, is encoded back as these bytes, unchanged. Otherwise,
+// as is the case of an edited element, the elements that carry a type URL are encoded as
+// entries, the other objects as envelopes, and the strings as base64.
+func (m *BlockExtension) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, b []byte) error {
+	var tree struct {
+		ExtensionData []json.RawMessage `json:"extension_data"`
+	}
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return err
+	}
+	m.ExtensionData = nil
+	for i, element := range tree.ExtensionData {
+		var data []byte
+		if err := json.Unmarshal(element, &data); err == nil {
+			m.ExtensionData = append(m.ExtensionData, data)
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(element, &fields); err != nil {
+			return fmt.Errorf("element %d of extension_data is neither an object nor base64: %s", i, err)
+		}
+		if raw, ok := fields[rawField]; ok {
+			delete(fields, rawField)
+			stripped, err := json.Marshal(fields)
+			if err != nil {
+				return fmt.Errorf("element %d of extension_data: %s", i, err)
+			}
+			data, err := unchangedElement(raw, stripped)
+			if err != nil {
+				return fmt.Errorf("element %d of extension_data: %s", i, err)
+			}
+			if data != nil {
+				m.ExtensionData = append(m.ExtensionData, data)
+				continue
+			}
+			element = stripped
+		}
+		msg := decorate(&common.Envelope{})
+		if _, ok := fields["type_url"]; ok {
+			msg = &Entry{}
+		}
+		if err := protolator.DeepUnmarshalJSON(bytes.NewReader(element), msg); err != nil {
+			return fmt.Errorf("element %d of extension_data: %s", i, err)
+		}
+		data, err := protolator.MostlyDeterministicMarshal(msg)
+		if err != nil {
+			return fmt.Errorf("element %d of extension_data: %s", i, err)
+		}
+		m.ExtensionData = append(m.ExtensionData, data)
+	}
+	return nil
+}
+
+// unchangedElement returns the bytes carried by the raw field of an element if the rest of
+// the element is the same as the rendering of these bytes, and nil if the element was edited.
+func unchangedElement(raw json.RawMessage, element json.RawMessage) ([]byte, error) {
+	var data []byte
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("field %s is not base64: %s", rawField, err)
+	}
+	rendered, _, err := marshalElement(data)
+	if err != nil {
+		return nil, nil
+	}
+	var renderedTree, elementTree interface{}
+	if err := json.Unmarshal(rendered, &renderedTree); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(element, &elementTree); err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(renderedTree, elementTree) {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// ProtolatorMessage returns the message that protolator should use in place of the newly
+// allocated message msg. Blocks are replaced by a Block, which shares their encoding and
+// renders the elements of the block extension. The messages that carry a config are
+// replaced by messages that render the ExtensionLimits value of the orderer config group,
+// which fabric-config does not know. Other messages are returned as is.
+func ProtolatorMessage(msg proto.Message) proto.Message {
+	switch msg.(type) {
+	case *cb.Block, *common.Block:
+		return &Block{}
+	case *cb.BlockData, *common.BlockData:
+		return decorate(&common.BlockData{})
+	case *cb.Envelope, *common.Envelope:
+		return decorate(&common.Envelope{})
+	case *cb.Payload, *common.Payload:
+		return decorate(&common.Payload{})
+	case *cb.ConfigEnvelope, *common.ConfigEnvelope:
+		return decorate(&common.ConfigEnvelope{})
+	case *cb.ConfigUpdateEnvelope, *common.ConfigUpdateEnvelope:
+		return decorate(&common.ConfigUpdateEnvelope{})
+	case *cb.Config, *common.Config:
+		return decorate(&common.Config{})
+	case *cb.ConfigUpdate, *common.ConfigUpdate:
+		return decorate(&common.ConfigUpdate{})
+	default:
+		return msg
+	}
+}
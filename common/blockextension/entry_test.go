@@ -0,0 +1,245 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadType(t *testing.T) {
+	assert.IsType(t, &blockextension.MerkleRoot{}, blockextension.PayloadType(blockextension.TxIDMerkleRootType))
+	assert.IsType(t, &blockextension.BloomFilter{}, blockextension.PayloadType(blockextension.NamespaceBloomFilterType))
+	assert.IsType(t, &blockextension.BatchTimestamp{}, blockextension.PayloadType(blockextension.BatchTimestampType))
+	assert.IsType(t, &common.Envelope{}, blockextension.PayloadType(blockextension.ConfigEnvelopeType))
+	assert.Nil(t, blockextension.PayloadType("test/unregistered"))
+
+	blockextension.RegisterPayloadType("test/registered", func() proto.Message { return &common.LastConfig{} })
+	assert.IsType(t, &common.LastConfig{}, blockextension.PayloadType("test/registered"))
+	assert.Panics(t, func() {
+		blockextension.RegisterPayloadType("test/registered", func() proto.Message { return &common.LastConfig{} })
+	})
+}
+
+func TestProtolatorMessage(t *testing.T) {
+	assert.IsType(t, &blockextension.Block{}, blockextension.ProtolatorMessage(&cb.Block{}))
+	assert.IsType(t, &blockextension.Block{}, blockextension.ProtolatorMessage(&common.Block{}))
+	channelHeader := &cb.ChannelHeader{}
+	assert.Equal(t, channelHeader, blockextension.ProtolatorMessage(channelHeader))
+}
+
+func addEntry(t *testing.T, block *cb.Block, typeURL string, payload proto.Message) {
+	payloadBytes, err := proto.Marshal(payload)
+	require.NoError(t, err)
+	err = protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+		TypeUrl:  typeURL,
+		Version:  1,
+		Producer: protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: []byte("cert")}),
+		Payload:  payloadBytes,
+	})
+	require.NoError(t, err)
+}
+
+func TestProtolatorRoundTrip(t *testing.T) {
+	block := protoutil.NewBlock(3, []byte("previous hash"))
+	addEntry(t, block, blockextension.ConfigEnvelopeType, &common.Envelope{Signature: []byte("signature")})
+	addEntry(t, block, blockextension.TxIDMerkleRootType, &blockextension.MerkleRoot{Root: []byte("root")})
+	addEntry(t, block, blockextension.BatchTimestampType, &blockextension.BatchTimestamp{
+		Timestamp: &timestamp.Timestamp{Seconds: 42},
+	})
+	err := protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+		TypeUrl: "test/failing",
+		Version: 1,
+		Error:   "producer failed",
+	})
+	require.NoError(t, err)
+	blockBytes := protoutil.MarshalOrPanic(block)
+
+	msg := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, proto.Unmarshal(blockBytes, msg))
+	var buffer bytes.Buffer
+	require.NoError(t, protolator.DeepMarshalJSON(&buffer, msg))
+
+	var tree struct {
+		Extension struct {
+			ExtensionData []struct {
+				TypeURL  string                 `json:"type_url"`
+				Version  int                    `json:"version"`
+				Producer map[string]interface{} `json:"producer"`
+				Payload  map[string]interface{} `json:"payload"`
+				Error    string                 `json:"error"`
+			} `json:"extension_data"`
+		} `json:"extension"`
+	}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &tree))
+	entries := tree.Extension.ExtensionData
+	require.Len(t, entries, 4)
+	assert.Equal(t, blockextension.ConfigEnvelopeType, entries[0].TypeURL)
+	assert.Equal(t, 1, entries[0].Version)
+	assert.Equal(t, "OrdererMSP", entries[0].Producer["mspid"])
+	assert.Equal(t, "c2lnbmF0dXJl", entries[0].Payload["signature"])
+	assert.Equal(t, blockextension.TxIDMerkleRootType, entries[1].TypeURL)
+	assert.Equal(t, "cm9vdA==", entries[1].Payload["root"])
+	assert.Equal(t, blockextension.BatchTimestampType, entries[2].TypeURL)
+	assert.Equal(t, "1970-01-01T00:00:42Z", entries[2].Payload["timestamp"])
+	assert.Equal(t, "test/failing", entries[3].TypeURL)
+	assert.Equal(t, "producer failed", entries[3].Error)
+	assert.Nil(t, entries[3].Payload)
+
+	encoded := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), encoded))
+	decoded := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(encoded), decoded))
+	assert.Equal(t, block.Extension.ExtensionData, decoded.Extension.ExtensionData)
+	assert.Equal(t, block.Header.PreviousHash, decoded.Header.PreviousHash)
+}
+
+func TestProtolatorUnregisteredType(t *testing.T) {
+	block := protoutil.NewBlock(3, nil)
+	addEntry(t, block, "test/unregistered", &common.LastConfig{Index: 7})
+
+	msg := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(block), msg))
+	var buffer bytes.Buffer
+	require.NoError(t, protolator.DeepMarshalJSON(&buffer, msg))
+	assert.Contains(t, buffer.String(), `"payload": "CAc="`)
+
+	encoded := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), encoded))
+	decoded := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(encoded), decoded))
+	assert.Equal(t, block.Extension.ExtensionData, decoded.Extension.ExtensionData)
+
+	edited := bytes.Replace(buffer.Bytes(), []byte(`"payload": "CAc="`), []byte(`"payload": "CAg="`), 1)
+	err := protolator.DeepUnmarshalJSON(bytes.NewReader(edited), blockextension.ProtolatorMessage(&cb.Block{}))
+	assert.Error(t, err)
+}
+
+func TestProtolatorRoundTripKeepsBytes(t *testing.T) {
+	// The payload carries a field unknown to MerkleRoot and the type URL of the entry is
+	// encoded after its version, so that encoding the decoded entry yields other bytes.
+	payload := append(protoutil.MarshalOrPanic(&blockextension.MerkleRoot{Root: []byte("root")}), 120, 1)
+	entry := append(
+		protoutil.MarshalOrPanic(&blockextension.Entry{Version: 1, Payload: payload}),
+		protoutil.MarshalOrPanic(&blockextension.Entry{TypeUrl: blockextension.TxIDMerkleRootType})...,
+	)
+	decodedEntry, err := blockextension.UnmarshalEntry(entry)
+	require.NoError(t, err)
+	require.NotEqual(t, entry, protoutil.MarshalOrPanic(decodedEntry))
+
+	block := legacyGenesisBlock()
+	block.Extension.ExtensionData = append(block.Extension.ExtensionData, entry)
+	hash := protoutil.BlockExtensionHash(block.Extension)
+
+	msg := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(block), msg))
+	var buffer bytes.Buffer
+	require.NoError(t, protolator.DeepMarshalJSON(&buffer, msg))
+
+	encoded := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), encoded))
+	decoded := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(encoded), decoded))
+	assert.Equal(t, block.Extension.ExtensionData, decoded.Extension.ExtensionData)
+	assert.Equal(t, hash, protoutil.BlockExtensionHash(decoded.Extension))
+
+	// An edited entry is encoded from its rendering.
+	edited := bytes.Replace(buffer.Bytes(), []byte(`"root": "cm9vdA=="`), []byte(`"root": "dHJlZQ=="`), 1)
+	require.NotEqual(t, buffer.Bytes(), edited)
+	encoded = blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(edited), encoded))
+	decoded = &cb.Block{}
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(encoded), decoded))
+	assert.Equal(t, block.Extension.ExtensionData[0], decoded.Extension.ExtensionData[0])
+	editedEntry, err := blockextension.UnmarshalEntry(decoded.Extension.ExtensionData[1])
+	require.NoError(t, err)
+	root := &blockextension.MerkleRoot{}
+	require.NoError(t, proto.Unmarshal(editedEntry.Payload, root))
+	assert.Equal(t, []byte("tree"), root.Root)
+	assert.NotEqual(t, hash, protoutil.BlockExtensionHash(decoded.Extension))
+}
+
+func TestUnmarshalEntry(t *testing.T) {
+	entry, err := blockextension.UnmarshalEntry(protoutil.MarshalOrPanic(&blockextension.Entry{TypeUrl: "test/type", Version: 1}))
+	require.NoError(t, err)
+	assert.Equal(t, "test/type", entry.TypeUrl)
+
+	_, err = blockextension.UnmarshalEntry([]byte{10, 10})
+	assert.Error(t, err)
+	_, err = blockextension.UnmarshalEntry(protoutil.MarshalOrPanic(&blockextension.Entry{Version: 1}))
+	assert.EqualError(t, err, `invalid type URL ""`)
+
+	block := legacyGenesisBlock()
+	_, err = blockextension.UnmarshalEntry(block.Extension.ExtensionData[0])
+	assert.Error(t, err)
+}
+
+// legacyGenesisBlock returns a genesis block like the ones created before the entries were
+// introduced, whose extension carries the config envelope as is.
+func legacyGenesisBlock() *cb.Block {
+	channelGroup := &cb.ConfigGroup{
+		Values: map[string]*cb.ConfigValue{
+			"Consortium": {
+				Value: protoutil.MarshalOrPanic(&cb.Consortium{Name: "SampleConsortium"}),
+			},
+		},
+	}
+	block := genesis.NewFactoryImplWithExtension(channelGroup, genesis.ExtensionConfig{OmitConfigEnvelope: true}).Block("mychannel")
+	block.Extension = &cb.BlockExtension{ExtensionData: [][]byte{block.Data.Data[0]}}
+	return block
+}
+
+func TestProtolatorLegacyGenesisBlock(t *testing.T) {
+	block := legacyGenesisBlock()
+	block.Extension.ExtensionData = append(block.Extension.ExtensionData, []byte("raw"))
+	addEntry(t, block, blockextension.TxIDMerkleRootType, &blockextension.MerkleRoot{Root: []byte("root")})
+
+	msg := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(block), msg))
+	var buffer bytes.Buffer
+	require.NoError(t, protolator.DeepMarshalJSON(&buffer, msg))
+
+	var tree struct {
+		Extension struct {
+			ExtensionData []interface{} `json:"extension_data"`
+		} `json:"extension"`
+	}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &tree))
+	elements := tree.Extension.ExtensionData
+	require.Len(t, elements, 3)
+	envelope, ok := elements[0].(map[string]interface{})
+	require.True(t, ok)
+	payload := envelope["payload"].(map[string]interface{})
+	channelHeader := payload["header"].(map[string]interface{})["channel_header"].(map[string]interface{})
+	assert.Equal(t, "mychannel", channelHeader["channel_id"])
+	config := payload["data"].(map[string]interface{})["config"].(map[string]interface{})
+	consortium := config["channel_group"].(map[string]interface{})["values"].(map[string]interface{})["Consortium"].(map[string]interface{})
+	assert.Equal(t, "SampleConsortium", consortium["value"].(map[string]interface{})["name"])
+	assert.Equal(t, "cmF3", elements[1])
+	entry, ok := elements[2].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, blockextension.TxIDMerkleRootType, entry["type_url"])
+
+	encoded := blockextension.ProtolatorMessage(&cb.Block{})
+	require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), encoded))
+	decoded := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(protoutil.MarshalOrPanic(encoded), decoded))
+	assert.Equal(t, block.Extension.ExtensionData, decoded.Extension.ExtensionData)
+}
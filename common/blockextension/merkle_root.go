@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension
+
+import "crypto/sha256"
+
+var (
+	merkleLeafPrefix = []byte{0}
+	merkleNodePrefix = []byte{1}
+)
+
+// TxIDMerkleRoot computes the root of the Merkle tree whose leaves are the given transaction
+// IDs, in order. Leaves and inner nodes are hashed with SHA-256 and distinct prefixes,
+// and the last node of a level with an odd number of nodes is promoted to the next level.
+// The root of a tree without leaves is the SHA-256 hash of no data.
+func TxIDMerkleRoot(txIDs []string) []byte {
+	if len(txIDs) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+
+	level := make([][]byte, len(txIDs))
+	for i, txID := range txIDs {
+		level[i] = merkleHash(merkleLeafPrefix, []byte(txID))
+	}
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, merkleHash(merkleNodePrefix, level[i], level[i+1]))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+func merkleHash(prefix []byte, data ...[]byte) []byte {
+	h := sha256.New()
+	h.Write(prefix)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxIDMerkleRoot(t *testing.T) {
+	leaf := func(txID string) []byte {
+		return merkleHash(merkleLeafPrefix, []byte(txID))
+	}
+	node := func(left, right []byte) []byte {
+		return merkleHash(merkleNodePrefix, left, right)
+	}
+	empty := sha256.Sum256(nil)
+
+	assert.Equal(t, empty[:], TxIDMerkleRoot(nil))
+	assert.Equal(t, leaf("a"), TxIDMerkleRoot([]string{"a"}))
+	assert.Equal(t, node(leaf("a"), leaf("b")), TxIDMerkleRoot([]string{"a", "b"}))
+	assert.Equal(t, node(node(leaf("a"), leaf("b")), leaf("c")), TxIDMerkleRoot([]string{"a", "b", "c"}))
+	assert.Equal(t,
+		node(node(node(leaf("a"), leaf("b")), node(leaf("c"), leaf("d"))), leaf("e")),
+		TxIDMerkleRoot([]string{"a", "b", "c", "d", "e"}),
+	)
+	assert.NotEqual(t, TxIDMerkleRoot([]string{"a", "b"}), TxIDMerkleRoot([]string{"b", "a"}))
+}
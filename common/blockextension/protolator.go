@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator/protoext/commonext"
+	"github.com/hyperledger/fabric-config/protolator/protoext/ordererext"
+	"github.com/hyperledger/fabric-protos-go/common"
+)
+
+// extensionLimitsKey is the key of the ExtensionLimits value of the orderer config group,
+// which is defined by channelconfig.BlockExtensionLimitsKey.
+const extensionLimitsKey = "BlockExtensionLimits"
+
+// The decoration of fabric-config, which protolator applies to the messages it renders,
+// does not know the ExtensionLimits value of the orderer config group. The messages
+// below extend that decoration along the paths from the messages that carry a config
+// down to the orderer config group, and render the ExtensionLimits value.
+
+// decorate returns the message that extends the decoration of fabric-config for msg,
+// or msg itself if msg does not lead to a config.
+func decorate(msg proto.Message) proto.Message {
+	switch m := msg.(type) {
+	case *common.BlockData:
+		return &blockData{BlockData: &commonext.BlockData{BlockData: m}}
+	case *common.Envelope:
+		return &envelope{Envelope: &commonext.Envelope{Envelope: m}}
+	case *common.Payload:
+		return &payload{Payload: &commonext.Payload{Payload: m}}
+	case *common.ConfigEnvelope:
+		return &configEnvelope{ConfigEnvelope: m}
+	case *common.ConfigUpdateEnvelope:
+		return &configUpdateEnvelope{ConfigUpdateEnvelope: &commonext.ConfigUpdateEnvelope{ConfigUpdateEnvelope: m}}
+	case *common.Config:
+		return &config{Config: &commonext.Config{Config: m}}
+	case *common.ConfigUpdate:
+		return &configUpdate{ConfigUpdate: &commonext.ConfigUpdate{ConfigUpdate: m}}
+	default:
+		return msg
+	}
+}
+
+// DynamicFields is used by protolator to render the config carried by the transactions.
+func (m *Block) DynamicFields() []string {
+	return []string{"data"}
+}
+
+// DynamicFieldProto is used by protolator to render the config carried by the transactions.
+func (m *Block) DynamicFieldProto(name string, base proto.Message) (proto.Message, error) {
+	if name != m.DynamicFields()[0] {
+		return nil, fmt.Errorf("not a dynamic field: %s", name)
+	}
+	return decorate(base), nil
+}
+
+type blockData struct{ *commonext.BlockData }
+
+func (bd *blockData) StaticallyOpaqueSliceFieldProto(name string, index int) (proto.Message, error) {
+	msg, err := bd.BlockData.StaticallyOpaqueSliceFieldProto(name, index)
+	return decorate(msg), err
+}
+
+type envelope struct{ *commonext.Envelope }
+
+func (e *envelope) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	msg, err := e.Envelope.StaticallyOpaqueFieldProto(name)
+	return decorate(msg), err
+}
+
+type payload struct{ *commonext.Payload }
+
+func (p *payload) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	msg, err := p.Payload.VariablyOpaqueFieldProto(name)
+	return decorate(msg), err
+}
+
+type configEnvelope struct{ *common.ConfigEnvelope }
+
+func (ce *configEnvelope) Underlying() proto.Message {
+	return ce.ConfigEnvelope
+}
+
+func (ce *configEnvelope) DynamicFields() []string {
+	return []string{"config", "last_update"}
+}
+
+func (ce *configEnvelope) DynamicFieldProto(name string, base proto.Message) (proto.Message, error) {
+	if name != ce.DynamicFields()[0] && name != ce.DynamicFields()[1] {
+		return nil, fmt.Errorf("not a dynamic field: %s", name)
+	}
+	return decorate(base), nil
+}
+
+type configUpdateEnvelope struct {
+	*commonext.ConfigUpdateEnvelope
+}
+
+func (cue *configUpdateEnvelope) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	msg, err := cue.ConfigUpdateEnvelope.StaticallyOpaqueFieldProto(name)
+	return decorate(msg), err
+}
+
+type config struct{ *commonext.Config }
+
+func (c *config) DynamicFieldProto(name string, base proto.Message) (proto.Message, error) {
+	msg, err := c.Config.DynamicFieldProto(name, base)
+	return decorateChannelGroup(msg), err
+}
+
+type configUpdate struct{ *commonext.ConfigUpdate }
+
+func (c *configUpdate) DynamicFieldProto(name string, base proto.Message) (proto.Message, error) {
+	msg, err := c.ConfigUpdate.DynamicFieldProto(name, base)
+	return decorateChannelGroup(msg), err
+}
+
+func decorateChannelGroup(msg proto.Message) proto.Message {
+	if cg, ok := msg.(*commonext.DynamicChannelGroup); ok {
+		return &channelGroup{DynamicChannelGroup: cg}
+	}
+	return msg
+}
+
+type channelGroup struct{ *commonext.DynamicChannelGroup }
+
+func (cg *channelGroup) DynamicMapFieldProto(name string, key string, base proto.Message) (proto.Message, error) {
+	msg, err := cg.DynamicChannelGroup.DynamicMapFieldProto(name, key, base)
+	if og, ok := msg.(*ordererext.DynamicOrdererGroup); ok {
+		return &ordererGroup{DynamicOrdererGroup: og}, err
+	}
+	return msg, err
+}
+
+type ordererGroup struct {
+	*ordererext.DynamicOrdererGroup
+}
+
+func (og *ordererGroup) DynamicMapFieldProto(name string, key string, base proto.Message) (proto.Message, error) {
+	if name == "values" && key == extensionLimitsKey {
+		cv, ok := base.(*common.ConfigValue)
+		if !ok {
+			return nil, fmt.Errorf("ConfigGroup values can only contain ConfigValue messages")
+		}
+		return &extensionLimitsValue{ConfigValue: cv}, nil
+	}
+	return og.DynamicOrdererGroup.DynamicMapFieldProto(name, key, base)
+}
+
+type extensionLimitsValue struct{ *common.ConfigValue }
+
+func (elv *extensionLimitsValue) Underlying() proto.Message {
+	return elv.ConfigValue
+}
+
+func (elv *extensionLimitsValue) StaticallyOpaqueFields() []string {
+	return []string{"value"}
+}
+
+func (elv *extensionLimitsValue) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != elv.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &ExtensionLimits{}, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockextension_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lookup returns the element of the JSON tree at the path of object keys and array indexes
+func lookup(t *testing.T, tree interface{}, path ...string) interface{} {
+	for _, key := range path {
+		switch node := tree.(type) {
+		case map[string]interface{}:
+			tree = node[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			require.NoError(t, err)
+			require.True(t, index < len(node), "no element %d in %v", index, node)
+			tree = node[index]
+		default:
+			require.Failf(t, "cannot lookup", "no key %s in %v", key, tree)
+		}
+	}
+	return tree
+}
+
+func TestProtolatorExtensionLimits(t *testing.T) {
+	channelGroup := &cb.ConfigGroup{
+		Groups: map[string]*cb.ConfigGroup{
+			"Orderer": {
+				Values: map[string]*cb.ConfigValue{
+					"BlockExtensionLimits": {
+						Value:     protoutil.MarshalOrPanic(&blockextension.ExtensionLimits{MaxBytes: 1024, MaxEntries: 4}),
+						ModPolicy: "Admins",
+					},
+				},
+			},
+		},
+	}
+	limitsPath := []string{"groups", "Orderer", "values", "BlockExtensionLimits", "value"}
+	configPath := append([]string{"payload", "data", "config", "channel_group"}, limitsPath...)
+
+	tests := []struct {
+		name        string
+		msg         proto.Message
+		newMsg      func() proto.Message
+		limitsPaths [][]string
+	}{
+		{
+			name:   "block",
+			msg:    genesis.NewFactoryImpl(channelGroup).Block("mychannel"),
+			newMsg: func() proto.Message { return &cb.Block{} },
+			limitsPaths: [][]string{
+				append([]string{"data", "data", "0"}, configPath...),
+				append([]string{"extension", "extension_data", "0", "payload"}, configPath...),
+			},
+		},
+		{
+			name:        "config",
+			msg:         &cb.Config{ChannelGroup: channelGroup},
+			newMsg:      func() proto.Message { return &cb.Config{} },
+			limitsPaths: [][]string{append([]string{"channel_group"}, limitsPath...)},
+		},
+		{
+			name:        "config update",
+			msg:         &cb.ConfigUpdate{ChannelId: "mychannel", WriteSet: channelGroup},
+			newMsg:      func() proto.Message { return &cb.ConfigUpdate{} },
+			limitsPaths: [][]string{append([]string{"write_set"}, limitsPath...)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgBytes := protoutil.MarshalOrPanic(tt.msg)
+			msg := blockextension.ProtolatorMessage(tt.newMsg())
+			require.NoError(t, proto.Unmarshal(msgBytes, msg))
+			var buffer bytes.Buffer
+			require.NoError(t, protolator.DeepMarshalJSON(&buffer, msg))
+
+			var tree interface{}
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &tree))
+			for _, path := range tt.limitsPaths {
+				limits := lookup(t, tree, path...)
+				assert.Equal(t, map[string]interface{}{"max_bytes": float64(1024), "max_entries": float64(4)}, limits)
+			}
+
+			encoded := blockextension.ProtolatorMessage(tt.newMsg())
+			require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), encoded))
+			assert.Equal(t, msgBytes, protoutil.MarshalOrPanic(encoded))
+		})
+	}
+}
@@ -28,16 +28,21 @@ const (
 
 	// ChannelV2_0 is the capabilities string for standard new non-backwards compatible fabric v2.0 channel capabilities.
 	ChannelV2_0 = "V2_0"
+
+	// ChannelBlockExtensionHash is the capabilities string for the non-backwards compatible
+	// commitment of the ordering service to the hash of the block extension.
+	ChannelBlockExtensionHash = "BLOCK_EXTENSION_HASH"
 )
 
 // ChannelProvider provides capabilities information for channel level config.
 type ChannelProvider struct {
 	*registry
-	v11  bool
-	v13  bool
-	v142 bool
-	v143 bool
-	v20  bool
+	v11                bool
+	v13                bool
+	v142               bool
+	v143               bool
+	v20                bool
+	blockExtensionHash bool
 }
 
 // NewChannelProvider creates a channel capabilities provider.
@@ -49,6 +54,7 @@ func NewChannelProvider(capabilities map[string]*cb.Capability) *ChannelProvider
 	_, cp.v142 = capabilities[ChannelV1_4_2]
 	_, cp.v143 = capabilities[ChannelV1_4_3]
 	_, cp.v20 = capabilities[ChannelV2_0]
+	_, cp.blockExtensionHash = capabilities[ChannelBlockExtensionHash]
 	return cp
 }
 
@@ -61,6 +67,8 @@ func (cp *ChannelProvider) Type() string {
 func (cp *ChannelProvider) HasCapability(capability string) bool {
 	switch capability {
 	// Add new capability names here
+	case ChannelBlockExtensionHash:
+		return true
 	case ChannelV2_0:
 		return true
 	case ChannelV1_4_3:
@@ -99,3 +107,9 @@ func (cp *ChannelProvider) ConsensusTypeMigration() bool {
 func (cp *ChannelProvider) OrgSpecificOrdererEndpoints() bool {
 	return cp.v142 || cp.v143 || cp.v20
 }
+
+// BlockExtensionHash returns true if the ordering service records the hash of the block
+// extension in the block metadata and covers it by the block signature.
+func (cp *ChannelProvider) BlockExtensionHash() bool {
+	return cp.blockExtensionHash
+}
@@ -100,6 +100,21 @@ func TestChannelV20(t *testing.T) {
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
 }
 
+func TestChannelBlockExtensionHash(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0: {},
+	})
+	assert.False(t, cp.BlockExtensionHash())
+
+	cp = NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:               {},
+		ChannelBlockExtensionHash: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
+	assert.True(t, cp.BlockExtensionHash())
+}
+
 func TestChannelNotSupported(t *testing.T) {
 	cp := NewChannelProvider(map[string]*cb.Capability{
 		ChannelV1_1:           {},
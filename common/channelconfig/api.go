@@ -12,6 +12,7 @@ import (
 	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/msp"
@@ -119,6 +120,9 @@ type Orderer interface {
 
 	// Capabilities defines the capabilities for the orderer portion of a channel
 	Capabilities() OrdererCapabilities
+
+	// BlockExtensionLimits returns the limits on the extension of the blocks of the channel
+	BlockExtensionLimits() *blockextension.ExtensionLimits
 }
 
 // ChannelCapabilities defines the capabilities for a channel
@@ -135,6 +139,9 @@ type ChannelCapabilities interface {
 
 	// OrgSpecificOrdererEndpoints return true if the channel config processing allows orderer orgs to specify their own endpoints
 	OrgSpecificOrdererEndpoints() bool
+	// BlockExtensionHash returns true if the orderer commits to the hash of the block extension
+	// by recording it in the block metadata and including it in the block signature.
+	BlockExtensionHash() bool
 }
 
 // ApplicationCapabilities defines the capabilities for the application portion of a channel
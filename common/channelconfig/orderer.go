@@ -15,6 +15,7 @@ import (
 
 	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/pkg/errors"
 )
@@ -42,16 +43,20 @@ const (
 
 	// EndpointsKey is the cb.COnfigValue key name for the Endpoints message in the OrdererOrgGroup.
 	EndpointsKey = "Endpoints"
+
+	// BlockExtensionLimitsKey is the cb.ConfigItem type key name for the ExtensionLimits message.
+	BlockExtensionLimitsKey = "BlockExtensionLimits"
 )
 
 // OrdererProtos is used as the source of the OrdererConfig.
 type OrdererProtos struct {
-	ConsensusType       *ab.ConsensusType
-	BatchSize           *ab.BatchSize
-	BatchTimeout        *ab.BatchTimeout
-	KafkaBrokers        *ab.KafkaBrokers
-	ChannelRestrictions *ab.ChannelRestrictions
-	Capabilities        *cb.Capabilities
+	ConsensusType        *ab.ConsensusType
+	BatchSize            *ab.BatchSize
+	BatchTimeout         *ab.BatchTimeout
+	KafkaBrokers         *ab.KafkaBrokers
+	ChannelRestrictions  *ab.ChannelRestrictions
+	Capabilities         *cb.Capabilities
+	BlockExtensionLimits *blockextension.ExtensionLimits
 }
 
 // OrdererConfig holds the orderer configuration information.
@@ -121,6 +126,12 @@ func (ooc *OrdererOrgConfig) Validate() error {
 
 // NewOrdererConfig creates a new instance of the orderer config.
 func NewOrdererConfig(ordererGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler, channelCapabilities ChannelCapabilities) (*OrdererConfig, error) {
+	if !channelCapabilities.BlockExtensionHash() {
+		if _, ok := ordererGroup.Values[BlockExtensionLimitsKey]; ok {
+			return nil, errors.Errorf("Orderer config cannot contain block extension limits until the %s capability has been enabled", capabilities.ChannelBlockExtensionHash)
+		}
+	}
+
 	oc := &OrdererConfig{
 		protos: &OrdererProtos{},
 		orgs:   make(map[string]OrdererOrg),
@@ -180,6 +191,12 @@ func (oc *OrdererConfig) MaxChannelsCount() uint64 {
 	return oc.protos.ChannelRestrictions.MaxCount
 }
 
+// BlockExtensionLimits returns the limits on the extension of the blocks of the channel.
+// A limit of zero means that the extension is not bounded in that respect.
+func (oc *OrdererConfig) BlockExtensionLimits() *blockextension.ExtensionLimits {
+	return oc.protos.BlockExtensionLimits
+}
+
 // Organizations returns a map of the orgs in the channel.
 func (oc *OrdererConfig) Organizations() map[string]OrdererOrg {
 	return oc.orgs
@@ -80,3 +80,52 @@ func TestOrgSpecificOrdererEndpoints(t *testing.T) {
 	})
 
 }
+
+func TestBlockExtensionLimits(t *testing.T) {
+	t.Run("Without_Capability", func(t *testing.T) {
+		conf := genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		conf.Orderer.BlockExtensionLimits = &genesisconfig.BlockExtensionLimits{MaxBytes: 1024, MaxEntries: 4}
+
+		cg, err := encoder.NewChannelGroup(conf)
+		assert.NoError(t, err)
+
+		cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+		assert.NoError(t, err)
+		_, err = channelconfig.NewChannelConfig(cg, cryptoProvider)
+		assert.EqualError(t, err, "could not create channel Orderer sub-group config: Orderer config cannot contain block extension limits until the BLOCK_EXTENSION_HASH capability has been enabled")
+	})
+
+	t.Run("With_Capability", func(t *testing.T) {
+		conf := genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		conf.Capabilities = map[string]bool{"V2_0": true, "BLOCK_EXTENSION_HASH": true}
+		conf.Orderer.BlockExtensionLimits = &genesisconfig.BlockExtensionLimits{MaxBytes: 1024, MaxEntries: 4}
+
+		cg, err := encoder.NewChannelGroup(conf)
+		assert.NoError(t, err)
+
+		cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+		assert.NoError(t, err)
+		cc, err := channelconfig.NewChannelConfig(cg, cryptoProvider)
+		assert.NoError(t, err)
+
+		limits := cc.OrdererConfig().BlockExtensionLimits()
+		assert.Equal(t, uint32(1024), limits.MaxBytes)
+		assert.Equal(t, uint32(4), limits.MaxEntries)
+	})
+
+	t.Run("Without_Limits", func(t *testing.T) {
+		conf := genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+
+		cg, err := encoder.NewChannelGroup(conf)
+		assert.NoError(t, err)
+
+		cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+		assert.NoError(t, err)
+		cc, err := channelconfig.NewChannelConfig(cg, cryptoProvider)
+		assert.NoError(t, err)
+
+		limits := cc.OrdererConfig().BlockExtensionLimits()
+		assert.Zero(t, limits.MaxBytes)
+		assert.Zero(t, limits.MaxEntries)
+	})
+}
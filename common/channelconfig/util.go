@@ -19,6 +19,7 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
@@ -154,6 +155,18 @@ func ChannelRestrictionsValue(maxChannelCount uint64) *StandardConfigValue {
 	}
 }
 
+// BlockExtensionLimitsValue returns the config definition for the limits on the extension of the blocks.
+// It is a value for the /Channel/Orderer group.
+func BlockExtensionLimitsValue(maxBytes, maxEntries uint32) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: BlockExtensionLimitsKey,
+		value: &blockextension.ExtensionLimits{
+			MaxBytes:   maxBytes,
+			MaxEntries: maxEntries,
+		},
+	}
+}
+
 // KafkaBrokersValue returns the config definition for the addresses of the ordering service's Kafka brokers.
 // It is a value for the /Channel/Orderer group.
 func KafkaBrokersValue(brokers []string) *StandardConfigValue {
@@ -8,6 +8,7 @@ package genesis
 
 import (
 	gurkhaB "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/protoutil"
 )
 
@@ -24,8 +25,20 @@ type Factory interface {
 	Block(channelID string) *gurkhaB.Block
 }
 
+// ExtensionConfig controls the extension of the genesis blocks.
+type ExtensionConfig struct {
+	// OmitConfigEnvelope prevents the config envelope of the genesis block
+	// from being duplicated into the block extension.
+	OmitConfigEnvelope bool
+
+	// Entries are added to the block extension, in order, after the
+	// config envelope.
+	Entries []*blockextension.Entry
+}
+
 type factory struct {
-	channelGroup *gurkhaB.ConfigGroup
+	channelGroup    *gurkhaB.ConfigGroup
+	extensionConfig ExtensionConfig
 }
 
 // NewFactoryImpl creates a new Factory.
@@ -33,6 +46,15 @@ func NewFactoryImpl(channelGroup *gurkhaB.ConfigGroup) Factory {
 	return &factory{channelGroup: channelGroup}
 }
 
+// NewFactoryImplWithExtension creates a new Factory whose genesis blocks
+// carry the extension described by the given config.
+func NewFactoryImplWithExtension(channelGroup *gurkhaB.ConfigGroup, extensionConfig ExtensionConfig) Factory {
+	return &factory{
+		channelGroup:    channelGroup,
+		extensionConfig: extensionConfig,
+	}
+}
+
 // Block constructs and returns a genesis block for a given channel ID.
 func (f *factory) Block(channelID string) *gurkhaB.Block {
 	payloadChannelHeader := protoutil.MakeChannelHeader(gurkhaB.HeaderType_CONFIG, msgVersion, channelID, epoch)
@@ -44,7 +66,21 @@ func (f *factory) Block(channelID string) *gurkhaB.Block {
 
 	block := protoutil.NewBlock(0, nil)
 	block.Data = &gurkhaB.BlockData{Data: [][]byte{protoutil.MarshalOrPanic(envelope)}}
-	block.Extension = &gurkhaB.BlockExtension{ExtensionData: [][]byte{protoutil.MarshalOrPanic(envelope)}}
+	if !f.extensionConfig.OmitConfigEnvelope {
+		err := protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+			TypeUrl: blockextension.ConfigEnvelopeType,
+			Version: 1,
+			Payload: protoutil.MarshalOrPanic(envelope),
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+	for _, entry := range f.extensionConfig.Entries {
+		if err := protoutil.AddBlockExtensionEntry(block, entry); err != nil {
+			panic(err)
+		}
+	}
 	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
 	block.Metadata.Metadata[gurkhaB.BlockMetadataIndex_LAST_CONFIG] = protoutil.MarshalOrPanic(&gurkhaB.Metadata{
 		Value: protoutil.MarshalOrPanic(&gurkhaB.LastConfig{Index: 0}),
@@ -11,6 +11,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,4 +45,45 @@ func TestFactory(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(0), lastConfig.Index)
 	})
+	t.Run("test for config envelope in block extension", func(t *testing.T) {
+		configEnv := &cb.Envelope{}
+		entry, err := protoutil.GetBlockExtensionPayload(block.Extension, blockextension.ConfigEnvelopeType, configEnv)
+		assert.NoError(t, err)
+		assert.NotNil(t, entry)
+		assert.Equal(t, uint32(1), entry.Version)
+		assert.Empty(t, entry.Producer)
+		assert.Equal(t, block.Data.Data[0], entry.Payload)
+	})
+}
+
+func TestFactoryWithExtension(t *testing.T) {
+	entry := &blockextension.Entry{
+		TypeUrl: "example.com/Payload",
+		Version: 2,
+		Payload: []byte("payload"),
+	}
+
+	t.Run("with config envelope", func(t *testing.T) {
+		block := NewFactoryImplWithExtension(protoutil.NewConfigGroup(), ExtensionConfig{
+			Entries: []*blockextension.Entry{entry},
+		}).Block("testchannelid")
+		assert.Len(t, block.Extension.ExtensionData, 2)
+		configEnvEntry, err := protoutil.UnmarshalBlockExtensionEntry(block.Extension.ExtensionData[0])
+		assert.NoError(t, err)
+		assert.Equal(t, blockextension.ConfigEnvelopeType, configEnvEntry.TypeUrl)
+		assert.Equal(t, block.Data.Data[0], configEnvEntry.Payload)
+		found := protoutil.FindBlockExtensionEntries(block.Extension, "example.com/Payload")
+		assert.Len(t, found, 1)
+		assert.True(t, proto.Equal(entry, found[0]))
+	})
+
+	t.Run("without config envelope", func(t *testing.T) {
+		block := NewFactoryImplWithExtension(protoutil.NewConfigGroup(), ExtensionConfig{
+			OmitConfigEnvelope: true,
+			Entries:            []*blockextension.Entry{entry},
+		}).Block("testchannelid")
+		assert.Len(t, block.Extension.ExtensionData, 1)
+		assert.Empty(t, protoutil.FindBlockExtensionEntries(block.Extension, blockextension.ConfigEnvelopeType))
+		assert.Len(t, protoutil.FindBlockExtensionEntries(block.Extension, "example.com/Payload"), 1)
+	})
 }
@@ -0,0 +1,223 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/snapshot"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// blockArchiveFileFormat is the data format of the block archives. A block archive carries
+// the ID of the ledger, the number of the first block, the number of blocks, and then
+// the marshaled blocks, including their extension, in order.
+const blockArchiveFileFormat = byte(1)
+
+// ExportBlocks writes the blocks in the range [startNum, endNum] to a block archive created
+// at archivePath and returns the hash of the archive. The blocks are written as they are
+// stored, including their metadata and their extension.
+func (store *BlockStore) ExportBlocks(archivePath string, startNum, endNum uint64, newHashFunc snapshot.NewHashFunc) ([]byte, error) {
+	if startNum > endNum {
+		return nil, errors.Errorf("start block number [%d] should not be greater than end block number [%d]", startNum, endNum)
+	}
+	bcInfo, err := store.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if endNum >= bcInfo.Height {
+		return nil, errors.Errorf("end block number [%d] should be less than the ledger height [%d]", endNum, bcInfo.Height)
+	}
+
+	itr, err := store.RetrieveBlocks(startNum)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	archive, err := snapshot.CreateFile(archivePath, blockArchiveFileFormat, newHashFunc)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	if err := archive.EncodeString(store.id); err != nil {
+		return nil, err
+	}
+	if err := archive.EncodeUVarint(startNum); err != nil {
+		return nil, err
+	}
+	if err := archive.EncodeUVarint(endNum - startNum + 1); err != nil {
+		return nil, err
+	}
+	for blockNum := startNum; blockNum <= endNum; blockNum++ {
+		result, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		blockBytes, err := proto.Marshal(result.(*common.Block))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error while marshaling block [%d]", blockNum)
+		}
+		if err := archive.EncodeBytes(blockBytes); err != nil {
+			return nil, err
+		}
+	}
+	logger.Infof("Exported blocks [%d] to [%d] of ledger [%s] to [%s]", startNum, endNum, store.id, archivePath)
+	return archive.Done()
+}
+
+// ImportBlocks appends the blocks of the block archive at archivePath to the block store.
+// The archive has to be exported from the same ledger and its first block has to follow the
+// last block of the store. All the blocks are verified before any of them is added, that is,
+// each block has to be chained to the previous one by the hash of its header, and has to be
+// consistent with the data hash and, if any, the extension hash it carries. The signatures of
+// the blocks are not verified, as the block store has no access to the channel configuration,
+// hence the archive is expected to come from a trusted source.
+//
+// The archive is first copied to a private file in the block storage directory of the ledger,
+// which is then both verified and imported, so that the imported blocks are the verified ones
+// even if the archive at archivePath is modified while it is being imported.
+func (store *BlockStore) ImportBlocks(archivePath string) error {
+	privateArchivePath, err := copyBlockArchive(archivePath, store.fileMgr.rootDir)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(privateArchivePath)
+
+	if err := store.verifyBlockArchive(privateArchivePath); err != nil {
+		return err
+	}
+
+	archive, _, numBlocks, err := openBlockArchive(privateArchivePath, store.id)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for i := uint64(0); i < numBlocks; i++ {
+		block, err := decodeArchivedBlock(archive)
+		if err != nil {
+			return err
+		}
+		if err := store.AddBlock(block); err != nil {
+			return errors.WithMessagef(err, "error while importing block [%d]", block.Header.Number)
+		}
+	}
+	logger.Infof("Imported %d blocks from [%s] to ledger [%s]", numBlocks, archivePath, store.id)
+	return nil
+}
+
+// copyBlockArchive copies the block archive at archivePath to a new file in dir, which is
+// only accessible by the peer, and returns the path of the copy.
+func copyBlockArchive(archivePath, dir string) (string, error) {
+	source, err := os.Open(archivePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error while opening the block archive [%s]", archivePath)
+	}
+	defer source.Close()
+
+	file, err := ioutil.TempFile(dir, "importing_blockarchive_")
+	if err != nil {
+		return "", errors.Wrapf(err, "error while creating a temporary file in dir [%s]", dir)
+	}
+	if _, err := io.Copy(file, source); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", errors.Wrapf(err, "error while copying the block archive [%s] to [%s]", archivePath, file.Name())
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", errors.Wrapf(err, "error while closing the file [%s]", file.Name())
+	}
+	return file.Name(), nil
+}
+
+func (store *BlockStore) verifyBlockArchive(archivePath string) error {
+	bcInfo, err := store.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+
+	archive, startNum, numBlocks, err := openBlockArchive(archivePath, store.id)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	if startNum != bcInfo.Height {
+		return errors.Errorf("block archive starts at block [%d] but the ledger height is [%d]", startNum, bcInfo.Height)
+	}
+
+	previousHash := bcInfo.CurrentBlockHash
+	for i := uint64(0); i < numBlocks; i++ {
+		block, err := decodeArchivedBlock(archive)
+		if err != nil {
+			return err
+		}
+		expectedNum := startNum + i
+		if block.Header.Number != expectedNum {
+			return errors.Errorf("block archive carries block [%d] in place of block [%d]", block.Header.Number, expectedNum)
+		}
+		if !bytes.Equal(block.Header.PreviousHash, previousHash) {
+			return errors.Errorf("previous hash [%x] of block [%d] does not match the hash of the previous block [%x]",
+				block.Header.PreviousHash, expectedNum, previousHash)
+		}
+		if !bytes.Equal(block.Header.DataHash, protoutil.BlockDataHash(block.Data)) {
+			return errors.Errorf("data hash of block [%d] does not match the hash of its data", expectedNum)
+		}
+		if _, err := protoutil.VerifyBlockExtensionHash(block); err != nil {
+			return errors.WithMessagef(err, "invalid extension of block [%d]", expectedNum)
+		}
+		previousHash = protoutil.BlockHeaderHash(block.Header)
+	}
+	return nil
+}
+
+func openBlockArchive(archivePath, ledgerID string) (*snapshot.FileReader, uint64, uint64, error) {
+	archive, err := snapshot.OpenFile(archivePath, blockArchiveFileFormat)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	archivedLedgerID, err := archive.DecodeString()
+	if err != nil {
+		archive.Close()
+		return nil, 0, 0, err
+	}
+	if archivedLedgerID != ledgerID {
+		archive.Close()
+		return nil, 0, 0, errors.Errorf("block archive was exported from ledger [%s], not from ledger [%s]", archivedLedgerID, ledgerID)
+	}
+	startNum, err := archive.DecodeUVarInt()
+	if err != nil {
+		archive.Close()
+		return nil, 0, 0, err
+	}
+	numBlocks, err := archive.DecodeUVarInt()
+	if err != nil {
+		archive.Close()
+		return nil, 0, 0, err
+	}
+	return archive, startNum, numBlocks, nil
+}
+
+func decodeArchivedBlock(archive *snapshot.FileReader) (*common.Block, error) {
+	block := &common.Block{}
+	if err := archive.DecodeProtoMessage(block); err != nil {
+		return nil, errors.Wrap(err, "error while decoding block from the block archive")
+	}
+	if block.Header == nil || block.Data == nil {
+		return nil, errors.New("block archive carries a block without header or data")
+	}
+	return block, nil
+}
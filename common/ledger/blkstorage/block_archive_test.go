@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportBlocks(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "blockarchive")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	newHashFunc := func() (hash.Hash, error) {
+		return sha256.New(), nil
+	}
+
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	for _, block := range blocks {
+		require.NoError(t, protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+			TypeUrl: "tag",
+			Payload: []byte("payload"),
+		}))
+		protoutil.SetExtensionHashInBlock(block)
+	}
+
+	sourceEnv := newTestEnv(t, NewConf(testPath(), 0))
+	defer sourceEnv.Cleanup()
+	source, err := sourceEnv.provider.Open("testLedger")
+	require.NoError(t, err)
+	defer source.Shutdown()
+	for _, block := range blocks {
+		require.NoError(t, source.AddBlock(block))
+	}
+
+	targetEnv := newTestEnv(t, NewConf(testPath(), 0))
+	defer targetEnv.Cleanup()
+	target, err := targetEnv.provider.Open("testLedger")
+	require.NoError(t, err)
+	defer target.Shutdown()
+	for _, block := range blocks[:3] {
+		require.NoError(t, target.AddBlock(block))
+	}
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := source.ExportBlocks(filepath.Join(testDir, "invalid-range"), 5, 4, newHashFunc)
+		require.EqualError(t, err, "start block number [5] should not be greater than end block number [4]")
+		_, err = source.ExportBlocks(filepath.Join(testDir, "invalid-range"), 5, 10, newHashFunc)
+		require.EqualError(t, err, "end block number [10] should be less than the ledger height [10]")
+	})
+
+	t.Run("archive not following the target", func(t *testing.T) {
+		archivePath := filepath.Join(testDir, "not-following")
+		_, err := source.ExportBlocks(archivePath, 4, 9, newHashFunc)
+		require.NoError(t, err)
+		require.EqualError(t, target.ImportBlocks(archivePath), "block archive starts at block [4] but the ledger height is [3]")
+	})
+
+	t.Run("archive of another ledger", func(t *testing.T) {
+		otherEnv := newTestEnv(t, NewConf(testPath(), 0))
+		defer otherEnv.Cleanup()
+		other, err := otherEnv.provider.Open("otherLedger")
+		require.NoError(t, err)
+		defer other.Shutdown()
+		require.NoError(t, other.AddBlock(blocks[0]))
+
+		archivePath := filepath.Join(testDir, "other-ledger")
+		_, err = other.ExportBlocks(archivePath, 0, 0, newHashFunc)
+		require.NoError(t, err)
+		require.EqualError(t, target.ImportBlocks(archivePath), "block archive was exported from ledger [otherLedger], not from ledger [testLedger]")
+	})
+
+	t.Run("broken hash chain", func(t *testing.T) {
+		otherBlocks := testutil.ConstructTestBlocks(t, 4)
+		otherEnv := newTestEnv(t, NewConf(testPath(), 0))
+		defer otherEnv.Cleanup()
+		other, err := otherEnv.provider.Open("testLedger")
+		require.NoError(t, err)
+		defer other.Shutdown()
+		for _, block := range otherBlocks {
+			require.NoError(t, other.AddBlock(block))
+		}
+
+		archivePath := filepath.Join(testDir, "broken-chain")
+		_, err = other.ExportBlocks(archivePath, 3, 3, newHashFunc)
+		require.NoError(t, err)
+		err = target.ImportBlocks(archivePath)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "previous hash")
+		require.Contains(t, err.Error(), "of block [3] does not match the hash of the previous block")
+	})
+
+	t.Run("forged extension", func(t *testing.T) {
+		forgedBlocks := make([]*common.Block, len(blocks))
+		for i, block := range blocks {
+			forgedBlocks[i] = proto.Clone(block).(*common.Block)
+		}
+		forgedBlocks[4].Extension.ExtensionData = [][]byte{[]byte("forged extension")}
+		otherEnv := newTestEnv(t, NewConf(testPath(), 0))
+		defer otherEnv.Cleanup()
+		other, err := otherEnv.provider.Open("testLedger")
+		require.NoError(t, err)
+		defer other.Shutdown()
+		for _, block := range forgedBlocks {
+			require.NoError(t, other.AddBlock(block))
+		}
+
+		archivePath := filepath.Join(testDir, "forged-extension")
+		_, err = other.ExportBlocks(archivePath, 3, 9, newHashFunc)
+		require.NoError(t, err)
+		err = target.ImportBlocks(archivePath)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid extension of block [4]")
+		require.True(t, protoutil.IsBlockExtensionMismatch(err))
+
+		// none of the blocks of the archive is imported
+		bcInfo, err := target.GetBlockchainInfo()
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), bcInfo.Height)
+	})
+
+	t.Run("export and import", func(t *testing.T) {
+		archivePath := filepath.Join(testDir, "blocks")
+		archiveHash, err := source.ExportBlocks(archivePath, 3, 9, newHashFunc)
+		require.NoError(t, err)
+		require.Equal(t, computeSha256(t, archivePath), archiveHash)
+
+		require.NoError(t, target.ImportBlocks(archivePath))
+		bcInfo, err := target.GetBlockchainInfo()
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), bcInfo.Height)
+		for _, block := range blocks {
+			importedBlock, err := target.RetrieveBlockByNumber(block.Header.Number)
+			require.NoError(t, err)
+			require.True(t, proto.Equal(block, importedBlock))
+			require.Equal(t, block.Extension, importedBlock.Extension)
+		}
+		_, err = target.RetrieveTxByBlockNumTranNum(7, 0)
+		require.NoError(t, err)
+
+		// the private copy of the archive is removed once imported
+		files, err := ioutil.ReadDir(target.fileMgr.rootDir)
+		require.NoError(t, err)
+		for _, file := range files {
+			require.True(t, isBlockFileName(file.Name()), "unexpected file [%s] in the block storage dir", file.Name())
+		}
+	})
+
+	t.Run("missing archive", func(t *testing.T) {
+		err := target.ImportBlocks(filepath.Join(testDir, "missing"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error while opening the block archive")
+	})
+}
+
+func TestCopyBlockArchive(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "blockarchive")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	archivePath := filepath.Join(testDir, "archive")
+	require.NoError(t, ioutil.WriteFile(archivePath, []byte("archived blocks"), 0644))
+	copyPath, err := copyBlockArchive(archivePath, testDir)
+	require.NoError(t, err)
+	require.NotEqual(t, archivePath, copyPath)
+
+	// the copy is not affected by the changes to the archive
+	require.NoError(t, ioutil.WriteFile(archivePath, []byte("other blocks"), 0644))
+	data, err := ioutil.ReadFile(copyPath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("archived blocks"), data)
+	info, err := os.Stat(copyPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func computeSha256(t *testing.T, file string) []byte {
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	sha := sha256.Sum256(data)
+	return sha[:]
+}
@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// CompressionCodec identifies the codec with which the blocks are compressed in the block files
+type CompressionCodec byte
+
+const (
+	// NoCompression stores the blocks uncompressed
+	NoCompression CompressionCodec = iota
+	// SnappyCompression compresses each block with snappy
+	SnappyCompression
+)
+
+// compressedBlockMarker is the first byte of the record of a compressed block in a block file.
+// The record of an uncompressed block starts with the varint encoded length of the block bytes,
+// which is never zero. The record of a compressed block is laid out as
+// `compressedBlockMarker:codec:varint(len(compressedBytes)):compressedBytes` so that the block
+// files that contain both kinds of records remain readable.
+const compressedBlockMarker = byte(0)
+
+// maxBlockRecordHeaderLen is the number of bytes that are peeked for decoding the header of
+// a block record. Assumption is that a block size would be small enough to be represented in 8 bytes varint
+const maxBlockRecordHeaderLen = 2 + 8
+
+// ParseCompressionCodec returns the codec for the given name, which is one of "none" and "snappy".
+// An empty name is treated as "none"
+func ParseCompressionCodec(name string) (CompressionCodec, error) {
+	switch name {
+	case "", "none":
+		return NoCompression, nil
+	case "snappy":
+		return SnappyCompression, nil
+	default:
+		return NoCompression, errors.Errorf("unsupported block compression codec [%s]", name)
+	}
+}
+
+func (c CompressionCodec) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case SnappyCompression:
+		return "snappy"
+	default:
+		return "unknown"
+	}
+}
+
+// encodeBlockRecord returns the header and the payload of the record under which the serialized block
+// is appended to a block file. The block is stored uncompressed if the codec does not reduce its size
+func encodeBlockRecord(blockBytes []byte, codec CompressionCodec) ([]byte, []byte, CompressionCodec) {
+	if codec == SnappyCompression {
+		compressedBytes := snappy.Encode(nil, blockBytes)
+		if len(compressedBytes) < len(blockBytes) {
+			header := append([]byte{compressedBlockMarker, byte(codec)}, proto.EncodeVarint(uint64(len(compressedBytes)))...)
+			return header, compressedBytes, codec
+		}
+	}
+	return proto.EncodeVarint(uint64(len(blockBytes))), blockBytes, NoCompression
+}
+
+// decodeBlockRecordHeader decodes the header of a block record from the given bytes and returns the codec
+// of the block, the length of the payload, and the number of bytes in the header. Zero is returned as the
+// number of bytes in the header if the given bytes carry only a part of the header
+func decodeBlockRecordHeader(b []byte) (CompressionCodec, uint64, int) {
+	codec := NoCompression
+	markerLen := 0
+	if len(b) > 0 && b[0] == compressedBlockMarker {
+		if len(b) < 2 {
+			return NoCompression, 0, 0
+		}
+		codec = CompressionCodec(b[1])
+		markerLen = 2
+	}
+	length, n := proto.DecodeVarint(b[markerLen:])
+	if n == 0 {
+		return NoCompression, 0, 0
+	}
+	return codec, length, markerLen + n
+}
+
+// readBlockRecord reads a block record from the reader and returns the serialized block, decompressed if needed
+func readBlockRecord(reader *bufio.Reader) ([]byte, error) {
+	codec := NoCompression
+	marker, err := reader.Peek(1)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the block record")
+	}
+	if marker[0] == compressedBlockMarker {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return nil, errors.Wrap(err, "error reading the compression codec of the block record")
+		}
+		codec = CompressionCodec(header[1])
+	}
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the length of the block record")
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, errors.Wrapf(err, "error reading [%d] bytes of the block record", length)
+	}
+	return decompressBlockBytes(payload, codec)
+}
+
+// decompressBlockBytes returns the serialized block from the payload of a block record
+func decompressBlockBytes(payload []byte, codec CompressionCodec) ([]byte, error) {
+	switch codec {
+	case NoCompression:
+		return payload, nil
+	case SnappyCompression:
+		blockBytes, err := snappy.Decode(nil, payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decompressing the block bytes with snappy")
+		}
+		return blockBytes, nil
+	default:
+		return nil, errors.Errorf("unexpected block compression codec [%d]", codec)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompressionCodec(t *testing.T) {
+	for name, expectedCodec := range map[string]CompressionCodec{
+		"":       NoCompression,
+		"none":   NoCompression,
+		"snappy": SnappyCompression,
+	} {
+		codec, err := ParseCompressionCodec(name)
+		require.NoError(t, err)
+		require.Equal(t, expectedCodec, codec)
+	}
+
+	_, err := ParseCompressionCodec("lz4")
+	require.EqualError(t, err, "unsupported block compression codec [lz4]")
+}
+
+func TestBlockRecordEncoding(t *testing.T) {
+	compressibleBytes := bytes.Repeat([]byte("compressible-block-bytes"), 100)
+	incompressibleBytes := testutil.ConstructRandomBytes(t, 100)
+
+	testCases := []struct {
+		name          string
+		blockBytes    []byte
+		codec         CompressionCodec
+		expectedCodec CompressionCodec
+	}{
+		{"uncompressed", compressibleBytes, NoCompression, NoCompression},
+		{"compressed", compressibleBytes, SnappyCompression, SnappyCompression},
+		{"incompressible", incompressibleBytes, SnappyCompression, NoCompression},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header, payload, codec := encodeBlockRecord(tc.blockBytes, tc.codec)
+			require.Equal(t, tc.expectedCodec, codec)
+			if codec == NoCompression {
+				require.Equal(t, tc.blockBytes, payload)
+			} else {
+				require.True(t, len(payload) < len(tc.blockBytes))
+			}
+
+			decodedCodec, length, n := decodeBlockRecordHeader(append(header, payload...))
+			require.Equal(t, tc.expectedCodec, decodedCodec)
+			require.Equal(t, uint64(len(payload)), length)
+			require.Equal(t, len(header), n)
+
+			blockBytes, err := readBlockRecord(bufio.NewReader(bytes.NewReader(append(header, payload...))))
+			require.NoError(t, err)
+			require.Equal(t, tc.blockBytes, blockBytes)
+		})
+	}
+
+	t.Run("partial-header", func(t *testing.T) {
+		_, _, n := decodeBlockRecordHeader([]byte{compressedBlockMarker})
+		require.Equal(t, 0, n)
+		_, _, n = decodeBlockRecordHeader([]byte{compressedBlockMarker, byte(SnappyCompression), 0x80})
+		require.Equal(t, 0, n)
+	})
+
+	t.Run("unknown-codec", func(t *testing.T) {
+		_, err := decompressBlockBytes([]byte("payload"), CompressionCodec(100))
+		require.EqualError(t, err, "unexpected block compression codec [100]")
+	})
+}
+
+func TestCompressedBlockStore(t *testing.T) {
+	blockStoreDir := testPath()
+	env := newTestEnv(t, NewConf(blockStoreDir, 0))
+	defer env.Cleanup()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	blocks := append([]*common.Block{gb}, testutilConstructCompressibleBlocks(bg, 10)...)
+
+	// the first half of the blocks is added uncompressed and the second half compressed
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, block := range blocks[:5] {
+		require.NoError(t, store.AddBlock(block))
+	}
+	store.Shutdown()
+	env.provider.Close()
+
+	env = newTestEnv(t, NewConf(blockStoreDir, 0).WithCompression(SnappyCompression))
+	store, err = env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, block := range blocks[5:] {
+		require.NoError(t, store.AddBlock(block))
+	}
+
+	stream, err := newBlockfileStream(env.provider.conf.getLedgerBlockDir("testLedger"), 0, 0)
+	require.NoError(t, err)
+	for i := range blocks {
+		_, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		require.NoError(t, err)
+		require.Equal(t, i >= 5, placementInfo.compressed)
+	}
+	stream.close()
+
+	verifyBlocks := func(t *testing.T, store *BlockStore) {
+		for _, block := range blocks {
+			b, err := store.RetrieveBlockByNumber(block.Header.Number)
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+
+			b, err = store.RetrieveBlockByHash(protoutil.BlockHeaderHash(block.Header))
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+
+			for txNum := range block.Data.Data {
+				expectedTxEnv := protoutil.ExtractEnvelopeOrPanic(block, txNum)
+				txID, err := protoutil.GetOrComputeTxIDFromEnvelope(block.Data.Data[txNum])
+				require.NoError(t, err)
+
+				txEnv, err := store.RetrieveTxByID(txID)
+				require.NoError(t, err)
+				require.Equal(t, expectedTxEnv, txEnv)
+
+				txEnv, err = store.RetrieveTxByBlockNumTranNum(block.Header.Number, uint64(txNum))
+				require.NoError(t, err)
+				require.Equal(t, expectedTxEnv, txEnv)
+
+				b, err = store.RetrieveBlockByTxID(txID)
+				require.NoError(t, err)
+				require.Equal(t, block, b)
+			}
+		}
+
+		itr, err := store.RetrieveBlocks(0)
+		require.NoError(t, err)
+		defer itr.Close()
+		for _, block := range blocks {
+			b, err := itr.Next()
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+		}
+	}
+
+	t.Run("blocks-from-mixed-block-files", func(t *testing.T) {
+		verifyBlocks(t, store)
+	})
+
+	t.Run("index-rebuilt-from-mixed-block-files", func(t *testing.T) {
+		store.Shutdown()
+		env.provider.Close()
+		require.NoError(t, os.RemoveAll(env.provider.conf.getIndexDir()))
+
+		env = newTestEnv(t, NewConf(blockStoreDir, 0))
+		store, err = env.provider.Open("testLedger")
+		require.NoError(t, err)
+		verifyBlocks(t, store)
+	})
+}
+
+func TestCompressedBlockLocationPointer(t *testing.T) {
+	flp := newCompressedBlockLocationPointer(2, 1000, &locPointer{offset: 20, bytesLength: 300})
+	b, err := flp.marshal()
+	require.NoError(t, err)
+
+	unmarshaled := &fileLocPointer{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	require.Equal(t, flp, unmarshaled)
+
+	// the location pointers of uncompressed blocks marshal as they did prior to the introduction of compression
+	flp = newFileLocationPointer(2, 1000, &locPointer{offset: 20, bytesLength: 300})
+	b, err = flp.marshal()
+	require.NoError(t, err)
+	require.Equal(t, []byte{2, 0xfc, 0x07, 0xac, 0x02}, b)
+
+	unmarshaled = &fileLocPointer{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	require.Equal(t, flp, unmarshaled)
+}
+
+func TestStatsBlockCompressionRatio(t *testing.T) {
+	testMetricProvider := testutilConstructMetricProvider()
+	env := newTestEnvWithMetricsProvider(t, NewConf(testPath(), 0).WithCompression(SnappyCompression), testMetricProvider.fakeProvider)
+	defer env.Cleanup()
+
+	store, err := env.provider.Open("ledger-stats")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testchannelid", false)
+	require.NoError(t, store.AddBlock(gb))
+	require.NoError(t, store.AddBlock(testutilConstructCompressibleBlocks(bg, 1)[0]))
+
+	fakeBlockCompressionRatioHist := testMetricProvider.fakeBlockCompressionRatioHist
+	require.Equal(t, 2, fakeBlockCompressionRatioHist.ObserveCallCount())
+	require.Equal(t, []string{"channel", "ledger-stats"}, fakeBlockCompressionRatioHist.WithArgsForCall(1))
+	require.True(t, fakeBlockCompressionRatioHist.ObserveArgsForCall(1) > 1)
+}
+
+func testutilConstructCompressibleBlocks(bg *testutil.BlockGenerator, numBlocks int) []*common.Block {
+	blocks := []*common.Block{}
+	numTx := 3
+	for i := 0; i < numBlocks; i++ {
+		simulationResults := [][]byte{}
+		for j := 0; j < numTx; j++ {
+			simulationResults = append(simulationResults, bytes.Repeat([]byte(fmt.Sprintf(`{"key":"key-%d-%d","value":"value"}`, i, j)), 50))
+		}
+		block := bg.NextBlock(simulationResults)
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txflags.NewWithValues(numTx, peer.TxValidationCode_VALID)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
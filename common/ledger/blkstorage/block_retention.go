@@ -0,0 +1,449 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var archiveIndexBatchSize = 1000
+
+// ArchiveSink stores the block files that are pruned from the block store. The implementations
+// are expected to be safe for concurrent use. A block file is stored under a key of the form
+// `<ledgerID>/blockfile_<fileNum>` and the content of a block file is never modified once stored.
+type ArchiveSink interface {
+	// Put stores the content read from r under the key, replacing the content previously stored under the key
+	Put(key string, r io.Reader) error
+	// Get returns a reader for the content stored under the key
+	Get(key string) (io.ReadCloser, error)
+}
+
+// DirArchiveSink is an `ArchiveSink` that stores the block files in a local directory, which may
+// be a mount of a remote filesystem. It also serves as a stand-in for an object store.
+type DirArchiveSink struct {
+	dir string
+}
+
+// NewDirArchiveSink constructs a `DirArchiveSink` that stores the block files under dir
+func NewDirArchiveSink(dir string) (*DirArchiveSink, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error while creating the archive dir [%s]", dir)
+	}
+	return &DirArchiveSink{dir: dir}, nil
+}
+
+// Put implements the function in the interface `ArchiveSink`
+func (s *DirArchiveSink) Put(key string, r io.Reader) error {
+	filePath := filepath.Join(s.dir, filepath.FromSlash(key))
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "error while creating dir [%s]", dir)
+	}
+	file, err := ioutil.TempFile(dir, filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return errors.Wrapf(err, "error while creating a temporary file in dir [%s]", dir)
+	}
+	tempFilePath := file.Name()
+	defer os.Remove(tempFilePath)
+
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error while writing to file [%s]", tempFilePath)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error while synching the file [%s]", tempFilePath)
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(err, "error while closing the file [%s]", tempFilePath)
+	}
+	if err := os.Rename(tempFilePath, filePath); err != nil {
+		return errors.Wrapf(err, "error while renaming the file [%s] to [%s]", tempFilePath, filePath)
+	}
+	return syncDir(dir)
+}
+
+// Get implements the function in the interface `ArchiveSink`
+func (s *DirArchiveSink) Get(key string) (io.ReadCloser, error) {
+	filePath := filepath.Join(s.dir, filepath.FromSlash(key))
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while opening the archived block file [%s]", filePath)
+	}
+	return file, nil
+}
+
+// PruneBlocks prunes the block files that contain only the blocks with a number less than blockNum.
+// The block files are archived to the sink of the retention policy, if any, before they are removed.
+// The pruned blocks are removed from the index, except for their transaction IDs, which are retained
+// along with the validation codes for detecting the duplicate transactions
+func (store *BlockStore) PruneBlocks(blockNum uint64) error {
+	return store.fileMgr.pruneBlockfiles(blockNum)
+}
+
+func (mgr *blockfileMgr) retainBlocks() uint64 {
+	if mgr.conf.retentionPolicy == nil {
+		return 0
+	}
+	return mgr.conf.retentionPolicy.RetainBlocks
+}
+
+func (mgr *blockfileMgr) fetchesArchivedBlocks() bool {
+	policy := mgr.conf.retentionPolicy
+	return policy != nil && policy.FetchArchived && policy.Sink != nil
+}
+
+// firstBlockfileNum returns the number of the first block file that is not pruned
+func (mgr *blockfileMgr) firstBlockfileNum() int {
+	mgr.archiveLock.RLock()
+	defer mgr.archiveLock.RUnlock()
+	files := mgr.archivedBlockfiles.Files
+	if len(files) == 0 {
+		return 0
+	}
+	return int(files[len(files)-1].FileNum) + 1
+}
+
+// firstBlockNumInBlockfiles returns the number of the first block that is present in the block files
+func (mgr *blockfileMgr) firstBlockNumInBlockfiles() uint64 {
+	mgr.archiveLock.RLock()
+	files := mgr.archivedBlockfiles.Files
+	mgr.archiveLock.RUnlock()
+	if len(files) == 0 {
+		return mgr.firstPossibleBlockNumberInBlockFiles()
+	}
+	return files[len(files)-1].LastBlockNum + 1
+}
+
+// pruneBlockfiles archives and removes the block files, except for the current one, whose last block
+// is below the given block number. The archived block files are recorded before the block files are
+// removed so that a pruning interrupted by a crash is completed when the block store is opened next
+func (mgr *blockfileMgr) pruneBlockfiles(belowBlockNum uint64) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	mgr.blkfilesInfoCond.L.Lock()
+	latestFileNum := mgr.blockfilesInfo.latestFileNumber
+	mgr.blkfilesInfoCond.L.Unlock()
+
+	mgr.archiveLock.RLock()
+	archived := &ArchivedBlockfiles{
+		Files: append([]*ArchivedBlockfile(nil), mgr.archivedBlockfiles.Files...),
+	}
+	mgr.archiveLock.RUnlock()
+
+	firstBlockNum := mgr.firstBlockNumInBlockfiles()
+	var pruned []*ArchivedBlockfile
+	for fileNum := mgr.firstBlockfileNum(); fileNum < latestFileNum; fileNum++ {
+		nextFirstBlockNum, err := retrieveFirstBlockNumFromFile(mgr.rootDir, fileNum+1)
+		if err != nil {
+			return err
+		}
+		if nextFirstBlockNum > belowBlockNum {
+			break
+		}
+		if nextFirstBlockNum == firstBlockNum {
+			// the first block file is left empty if the first block does not fit in it. Such a block
+			// file is recorded only along with the next block file so that the recorded ranges are never empty
+			continue
+		}
+		if err := mgr.archiveBlockfile(fileNum); err != nil {
+			return err
+		}
+		pruned = append(pruned, &ArchivedBlockfile{
+			FileNum:       uint64(fileNum),
+			FirstBlockNum: firstBlockNum,
+			LastBlockNum:  nextFirstBlockNum - 1,
+		})
+		firstBlockNum = nextFirstBlockNum
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	archived.Files = append(archived.Files, pruned...)
+	if err := saveArchivedBlockfiles(mgr.rootDir, archived); err != nil {
+		return err
+	}
+	mgr.archiveLock.Lock()
+	mgr.archivedBlockfiles = archived
+	mgr.archiveLock.Unlock()
+	logger.Infof("[%s] Pruning block files [%d] to [%d] containing blocks [%d] to [%d]",
+		mgr.ledgerID, pruned[0].FileNum, pruned[len(pruned)-1].FileNum, pruned[0].FirstBlockNum, firstBlockNum-1)
+	if err := mgr.removePrunedBlockfiles(pruned); err != nil {
+		return err
+	}
+	return mgr.removeEmptyBlockfile()
+}
+
+// removeEmptyBlockfile removes the first block file if it is pruned and is empty
+func (mgr *blockfileMgr) removeEmptyBlockfile() error {
+	if mgr.firstBlockfileNum() == 0 {
+		return nil
+	}
+	filePath := deriveBlockfilePath(mgr.rootDir, 0)
+	exists, size, err := util.FileExists(filePath)
+	if err != nil || !exists || size != 0 {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		return errors.Wrapf(err, "error removing the block file [%s]", filePath)
+	}
+	return nil
+}
+
+func (mgr *blockfileMgr) archiveBlockfile(fileNum int) error {
+	sink := mgr.archiveSink()
+	if sink == nil {
+		return nil
+	}
+	filePath := deriveBlockfilePath(mgr.rootDir, fileNum)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening block file %s", filePath)
+	}
+	defer file.Close()
+	if err := sink.Put(archiveKey(mgr.ledgerID, uint64(fileNum)), file); err != nil {
+		return errors.WithMessagef(err, "error while archiving the block file [%s]", filePath)
+	}
+	return nil
+}
+
+func (mgr *blockfileMgr) archiveSink() ArchiveSink {
+	if mgr.conf.retentionPolicy == nil {
+		return nil
+	}
+	return mgr.conf.retentionPolicy.Sink
+}
+
+// removePrunedBlockfiles removes the given block files, if present, after removing the blocks in them from the index
+func (mgr *blockfileMgr) removePrunedBlockfiles(files []*ArchivedBlockfile) error {
+	removed := false
+	for _, f := range files {
+		filePath := deriveBlockfilePath(mgr.rootDir, int(f.FileNum))
+		exists, _, err := util.FileExists(filePath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := mgr.archiveIndexEntries(int(f.FileNum)); err != nil {
+			return err
+		}
+		if err := os.Remove(filePath); err != nil {
+			return errors.Wrapf(err, "error removing the block file [%s]", filePath)
+		}
+		removed = true
+	}
+	if !removed {
+		return nil
+	}
+	return syncDir(mgr.rootDir)
+}
+
+func (mgr *blockfileMgr) archiveIndexEntries(fileNum int) error {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	batch := mgr.index.db.NewUpdateBatch()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		blockInfo, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		if err := addIndexEntriesToBeArchived(batch, blockInfo, mgr.index); err != nil {
+			return err
+		}
+		if batch.Len() >= archiveIndexBatchSize {
+			if err := mgr.index.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = mgr.index.db.NewUpdateBatch()
+		}
+	}
+	return mgr.index.db.WriteBatch(batch, true)
+}
+
+// addIndexEntriesToBeArchived adds to the batch the removal of the index entries of the block, except for the
+// transaction IDs, which are updated to carry only the validation codes. The extension index entries are retained
+func addIndexEntriesToBeArchived(batch *leveldbhelper.UpdateBatch, blockInfo *serializedBlockInfo, indexStore *blockIndex) error {
+	if indexStore.isAttributeIndexed(IndexableAttrBlockHash) {
+		batch.Delete(constructBlockHashKey(protoutil.BlockHeaderHash(blockInfo.blockHeader)))
+	}
+
+	if indexStore.isAttributeIndexed(IndexableAttrBlockNum) {
+		batch.Delete(constructBlockNumKey(blockInfo.blockHeader.Number))
+	}
+
+	if indexStore.isAttributeIndexed(IndexableAttrBlockNumTranNum) {
+		for txIndex := range blockInfo.txOffsets {
+			batch.Delete(constructBlockNumTranNumKey(blockInfo.blockHeader.Number, uint64(txIndex)))
+		}
+	}
+
+	if indexStore.isAttributeIndexed(IndexableAttrTxID) {
+		txsfltr := txflags.ValidationFlags(blockInfo.metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		for i, txOffset := range blockInfo.txOffsets {
+			indexValBytes, err := proto.Marshal(&TxIDIndexValue{
+				TxValidationCode: int32(txsfltr.Flag(i)),
+				Archived:         true,
+			})
+			if err != nil {
+				return errors.Wrap(err, "unexpected error while marshaling TxIDIndexValProto message")
+			}
+			batch.Put(constructTxIDKey(txOffset.txID, blockInfo.blockHeader.Number, uint64(i)), indexValBytes)
+		}
+	}
+	return nil
+}
+
+// archivedBlockfileFor returns the archived block file that contains the given block, or nil
+// if the block is not in any of the archived block files
+func (mgr *blockfileMgr) archivedBlockfileFor(blockNum uint64) *ArchivedBlockfile {
+	mgr.archiveLock.RLock()
+	defer mgr.archiveLock.RUnlock()
+	files := mgr.archivedBlockfiles.Files
+	i := sort.Search(len(files), func(i int) bool {
+		return files[i].LastBlockNum >= blockNum
+	})
+	if i == len(files) || files[i].FirstBlockNum > blockNum {
+		return nil
+	}
+	return files[i]
+}
+
+// openArchivedBlockStream opens the archived block file that contains the given block and skips the
+// blocks that precede the given block. A `ledger.BlockArchivedErr` is returned if the retention policy
+// does not allow the fetching of the archived blocks
+func (mgr *blockfileMgr) openArchivedBlockStream(blockNum uint64) (*archivedBlockStream, error) {
+	file := mgr.archivedBlockfileFor(blockNum)
+	if file == nil || !mgr.fetchesArchivedBlocks() {
+		return nil, &ledger.BlockArchivedErr{
+			BlockNum:               blockNum,
+			FirstAvailableBlockNum: mgr.firstBlockNumInBlockfiles(),
+		}
+	}
+	r, err := mgr.archiveSink().Get(archiveKey(mgr.ledgerID, file.FileNum))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while fetching the archived block [%d]", blockNum)
+	}
+	s := &archivedBlockStream{file: file, readCloser: r, reader: bufio.NewReader(r)}
+	for n := file.FirstBlockNum; n < blockNum; n++ {
+		if _, err := s.nextBlockBytes(); err != nil {
+			s.close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (mgr *blockfileMgr) retrieveArchivedBlock(blockNum uint64) (*common.Block, error) {
+	s, err := mgr.openArchivedBlockStream(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+	blockBytes, err := s.nextBlockBytes()
+	if err != nil {
+		return nil, err
+	}
+	return deserializeBlock(blockBytes)
+}
+
+func (mgr *blockfileMgr) retrieveArchivedTransaction(blockNum, txNum uint64) (*common.Envelope, error) {
+	block, err := mgr.retrieveArchivedBlock(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	return protoutil.ExtractEnvelope(block, int(txNum))
+}
+
+// archivedBlockStream reads blocks sequentially from an archived block file
+type archivedBlockStream struct {
+	file       *ArchivedBlockfile
+	readCloser io.ReadCloser
+	reader     *bufio.Reader
+}
+
+func (s *archivedBlockStream) nextBlockBytes() ([]byte, error) {
+	blockBytes, err := readBlockRecord(s.reader)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error reading a block from the archived block file [%d]", s.file.FileNum)
+	}
+	return blockBytes, nil
+}
+
+func (s *archivedBlockStream) close() error {
+	return s.readCloser.Close()
+}
+
+// archivedTxErr is returned by the index for a transaction whose block is pruned from the block files
+type archivedTxErr struct {
+	blockNum uint64
+	txNum    uint64
+}
+
+func (e *archivedTxErr) Error() string {
+	return fmt.Sprintf("transaction [%d] of block [%d] is archived", e.txNum, e.blockNum)
+}
+
+func archiveKey(ledgerID string, fileNum uint64) string {
+	return ledgerID + "/" + blockfilePrefix + fmt.Sprintf("%06d", fileNum)
+}
+
+func loadArchivedBlockfiles(rootDir string) (*ArchivedBlockfiles, error) {
+	b, err := ioutil.ReadFile(filepath.Join(rootDir, archivedBlockfilesInfoFile))
+	if os.IsNotExist(err) {
+		return &ArchivedBlockfiles{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading archivedBlockfiles file")
+	}
+	archived := &ArchivedBlockfiles{}
+	if err := proto.Unmarshal(b, archived); err != nil {
+		return nil, errors.Wrapf(err, "error while unmarshalling archivedBlockfiles")
+	}
+	return archived, nil
+}
+
+func saveArchivedBlockfiles(rootDir string, archived *ArchivedBlockfiles) error {
+	b, err := proto.Marshal(archived)
+	if err != nil {
+		return errors.Wrap(err, "error while marshalling archivedBlockfiles")
+	}
+	// a temporary file may be left behind by a crash in an earlier attempt
+	if err := os.RemoveAll(filepath.Join(rootDir, archivedBlockfilesInfoTempFile)); err != nil {
+		return errors.Wrapf(err, "error while removing the temporary archivedBlockfiles file")
+	}
+	return createAndSyncFileAtomically(rootDir, archivedBlockfilesInfoTempFile, archivedBlockfilesInfoFile, b)
+}
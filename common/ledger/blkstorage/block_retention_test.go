@@ -0,0 +1,306 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRetention(t *testing.T) {
+	archiveDir, err := ioutil.TempDir("", "blkstorage-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(archiveDir)
+	sink, err := NewDirArchiveSink(archiveDir)
+	require.NoError(t, err)
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	blocks := append([]*common.Block{gb}, bg.NextTestBlocks(99)...)
+	maxFileSize := int(0.1 * float64(testutilEstimateTotalSizeOnDisk(t, blocks)))
+	policy := &RetentionPolicy{RetainBlocks: 30, Sink: sink}
+	blockStoreDir := testPath()
+	env := newTestEnv(t, NewConfWithRetentionPolicy(blockStoreDir, maxFileSize, policy))
+	defer env.Cleanup()
+
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, store.AddBlock(block))
+	}
+
+	firstBlockNum := store.fileMgr.firstBlockNumInBlockfiles()
+	require.True(t, firstBlockNum > 0)
+	require.True(t, firstBlockNum <= 70)
+	firstFileNum := store.fileMgr.firstBlockfileNum()
+	ledgerDir := env.provider.conf.getLedgerBlockDir("testLedger")
+	for fileNum := 0; fileNum < firstFileNum; fileNum++ {
+		exists, _, err := util.FileExists(deriveBlockfilePath(ledgerDir, fileNum))
+		require.NoError(t, err)
+		require.False(t, exists)
+		exists, _, err = util.FileExists(filepath.Join(archiveDir, "testLedger", filepath.Base(deriveBlockfilePath(ledgerDir, fileNum))))
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	verifyArchivedErr := func(err error, blockNum uint64) {
+		require.Equal(t, &ledger.BlockArchivedErr{BlockNum: blockNum, FirstAvailableBlockNum: firstBlockNum}, err)
+	}
+
+	t.Run("archived-blocks-not-served", func(t *testing.T) {
+		_, err := store.RetrieveBlockByNumber(0)
+		verifyArchivedErr(err, 0)
+		_, err = store.RetrieveTxByBlockNumTranNum(firstBlockNum-1, 0)
+		verifyArchivedErr(err, firstBlockNum-1)
+		_, err = store.RetrieveBlocks(1)
+		verifyArchivedErr(err, 1)
+		_, err = store.RetrieveBlockByHash(protoutil.BlockHeaderHash(blocks[1].Header))
+		require.Equal(t, ErrNotFoundInIndex, err)
+
+		txID, err := protoutil.GetOrComputeTxIDFromEnvelope(blocks[1].Data.Data[0])
+		require.NoError(t, err)
+		_, err = store.RetrieveTxByID(txID)
+		verifyArchivedErr(err, 1)
+		_, err = store.RetrieveBlockByTxID(txID)
+		verifyArchivedErr(err, 1)
+		validationCode, err := store.RetrieveTxValidationCodeByTxID(txID)
+		require.NoError(t, err)
+		require.Equal(t, peer.TxValidationCode_VALID, validationCode)
+
+		for _, block := range blocks[firstBlockNum:] {
+			b, err := store.RetrieveBlockByNumber(block.Header.Number)
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+		}
+	})
+
+	t.Run("archived-blocks-fetched", func(t *testing.T) {
+		store.Shutdown()
+		env.provider.Close()
+		policy.FetchArchived = true
+		env = newTestEnv(t, NewConfWithRetentionPolicy(blockStoreDir, maxFileSize, policy))
+		store, err = env.provider.Open("testLedger")
+		require.NoError(t, err)
+
+		for _, block := range blocks {
+			b, err := store.RetrieveBlockByNumber(block.Header.Number)
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+
+			txID, err := protoutil.GetOrComputeTxIDFromEnvelope(block.Data.Data[0])
+			require.NoError(t, err)
+			b, err = store.RetrieveBlockByTxID(txID)
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+			txEnv, err := store.RetrieveTxByID(txID)
+			require.NoError(t, err)
+			require.Equal(t, protoutil.ExtractEnvelopeOrPanic(block, 0), txEnv)
+			txEnv, err = store.RetrieveTxByBlockNumTranNum(block.Header.Number, 0)
+			require.NoError(t, err)
+			require.Equal(t, protoutil.ExtractEnvelopeOrPanic(block, 0), txEnv)
+		}
+
+		itr, err := store.RetrieveBlocks(1)
+		require.NoError(t, err)
+		defer itr.Close()
+		for _, block := range blocks[1:] {
+			b, err := itr.Next()
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+		}
+	})
+
+	t.Run("pruning-continues-with-new-blocks", func(t *testing.T) {
+		moreBlocks := bg.NextTestBlocks(99)
+		for _, block := range moreBlocks {
+			require.NoError(t, store.AddBlock(block))
+		}
+		require.True(t, store.fileMgr.firstBlockNumInBlockfiles() > firstBlockNum)
+		require.True(t, store.fileMgr.firstBlockNumInBlockfiles() <= 169)
+		b, err := store.RetrieveBlockByNumber(0)
+		require.NoError(t, err)
+		require.Equal(t, blocks[0], b)
+		b, err = store.RetrieveBlockByNumber(198)
+		require.NoError(t, err)
+		require.Equal(t, moreBlocks[len(moreBlocks)-1], b)
+	})
+}
+
+func TestPruneBlocks(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	maxFileSize := int(0.1 * float64(testutilEstimateTotalSizeOnDisk(t, blocks)))
+	blockStoreDir := testPath()
+	env := newTestEnv(t, NewConf(blockStoreDir, maxFileSize))
+	defer env.Cleanup()
+
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, store.AddBlock(block))
+	}
+	require.Equal(t, uint64(0), store.fileMgr.firstBlockNumInBlockfiles())
+
+	require.NoError(t, store.PruneBlocks(50))
+	firstBlockNum := store.fileMgr.firstBlockNumInBlockfiles()
+	require.True(t, firstBlockNum > 30)
+	require.True(t, firstBlockNum <= 50)
+	_, err = store.RetrieveBlockByNumber(firstBlockNum - 1)
+	require.Equal(t, &ledger.BlockArchivedErr{BlockNum: firstBlockNum - 1, FirstAvailableBlockNum: firstBlockNum}, err)
+
+	// pruning below the first available block is a no-op
+	require.NoError(t, store.PruneBlocks(firstBlockNum))
+	require.Equal(t, firstBlockNum, store.fileMgr.firstBlockNumInBlockfiles())
+
+	// the current block file is never pruned
+	require.NoError(t, store.PruneBlocks(1000))
+	lastFileFirstBlockNum := store.fileMgr.firstBlockNumInBlockfiles()
+	require.Equal(t, store.fileMgr.firstBlockfileNum(), store.fileMgr.blockfilesInfo.latestFileNumber)
+	b, err := store.RetrieveBlockByNumber(99)
+	require.NoError(t, err)
+	require.Equal(t, blocks[99], b)
+
+	t.Run("reopen", func(t *testing.T) {
+		store.Shutdown()
+		env.provider.Close()
+		env = newTestEnv(t, NewConf(blockStoreDir, maxFileSize))
+		store, err = env.provider.Open("testLedger")
+		require.NoError(t, err)
+		require.Equal(t, lastFileFirstBlockNum, store.fileMgr.firstBlockNumInBlockfiles())
+		bcInfo, err := store.GetBlockchainInfo()
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), bcInfo.Height)
+
+		itr, err := store.RetrieveBlocks(lastFileFirstBlockNum)
+		require.NoError(t, err)
+		defer itr.Close()
+		for _, block := range blocks[lastFileFirstBlockNum:] {
+			b, err := itr.Next()
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+		}
+	})
+
+	t.Run("rollback-and-reset", func(t *testing.T) {
+		err := ValidateRollbackParams(blockStoreDir, "testLedger", lastFileFirstBlockNum-1)
+		require.EqualError(t, err, fmt.Sprintf(
+			"target block number [%d] should be greater than the last pruned block number [%d]",
+			lastFileFirstBlockNum-1, lastFileFirstBlockNum-1,
+		))
+
+		ledgerDir := env.provider.conf.getLedgerBlockDir("testLedger")
+		require.Error(t, resetToGenesisBlk(ledgerDir))
+	})
+}
+
+func TestPruningCompletedOnOpen(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	maxFileSize := int(0.1 * float64(testutilEstimateTotalSizeOnDisk(t, blocks)))
+	blockStoreDir := testPath()
+	env := newTestEnv(t, NewConf(blockStoreDir, maxFileSize))
+	defer env.Cleanup()
+
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, store.AddBlock(block))
+	}
+	store.Shutdown()
+	env.provider.Close()
+
+	// simulate a crash after recording the pruning of the first block file and before removing the block file
+	ledgerDir := env.provider.conf.getLedgerBlockDir("testLedger")
+	firstBlockNumInFile1, err := retrieveFirstBlockNumFromFile(ledgerDir, 1)
+	require.NoError(t, err)
+	require.NoError(t, saveArchivedBlockfiles(ledgerDir, &ArchivedBlockfiles{
+		Files: []*ArchivedBlockfile{{FileNum: 0, FirstBlockNum: 0, LastBlockNum: firstBlockNumInFile1 - 1}},
+	}))
+
+	env = newTestEnv(t, NewConf(blockStoreDir, maxFileSize))
+	store, err = env.provider.Open("testLedger")
+	require.NoError(t, err)
+	exists, _, err := util.FileExists(deriveBlockfilePath(ledgerDir, 0))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = store.RetrieveBlockByNumber(0)
+	require.Equal(t, &ledger.BlockArchivedErr{BlockNum: 0, FirstAvailableBlockNum: firstBlockNumInFile1}, err)
+	_, err = store.RetrieveBlockByHash(protoutil.BlockHeaderHash(blocks[0].Header))
+	require.Equal(t, ErrNotFoundInIndex, err)
+	b, err := store.RetrieveBlockByNumber(firstBlockNumInFile1)
+	require.NoError(t, err)
+	require.Equal(t, blocks[firstBlockNumInFile1], b)
+}
+
+func TestPruneEmptyFirstBlockfile(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	// the genesis block does not fit in the first block file, which is left empty
+	maxFileSize := int(0.1 * float64(testutilEstimateTotalSizeOnDisk(t, blocks)))
+	env := newTestEnv(t, NewConf(testPath(), maxFileSize))
+	defer env.Cleanup()
+
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, store.AddBlock(block))
+	}
+	ledgerDir := env.provider.conf.getLedgerBlockDir("testLedger")
+	_, size, err := util.FileExists(deriveBlockfilePath(ledgerDir, 0))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), size)
+
+	// the genesis block alone is in the second block file
+	require.NoError(t, store.PruneBlocks(1))
+	require.Equal(t, 2, store.fileMgr.firstBlockfileNum())
+	require.True(t, proto.Equal(
+		&ArchivedBlockfiles{Files: []*ArchivedBlockfile{{FileNum: 1, FirstBlockNum: 0, LastBlockNum: 0}}},
+		store.fileMgr.archivedBlockfiles,
+	))
+	exists, _, err := util.FileExists(deriveBlockfilePath(ledgerDir, 0))
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = store.RetrieveBlockByNumber(0)
+	require.Equal(t, &ledger.BlockArchivedErr{BlockNum: 0, FirstAvailableBlockNum: 1}, err)
+	b, err := store.RetrieveBlockByNumber(1)
+	require.NoError(t, err)
+	require.Equal(t, blocks[1], b)
+}
+
+func TestDirArchiveSink(t *testing.T) {
+	archiveDir, err := ioutil.TempDir("", "blkstorage-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(archiveDir)
+	sink, err := NewDirArchiveSink(filepath.Join(archiveDir, "sink"))
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Put("ledger/key", strings.NewReader("content-1")))
+	require.NoError(t, sink.Put("ledger/key", strings.NewReader("content-2")))
+	r, err := sink.Get("ledger/key")
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "content-2", string(content))
+
+	files, err := ioutil.ReadDir(filepath.Join(archiveDir, "sink", "ledger"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	_, err = sink.Get("ledger/missing-key")
+	require.Error(t, err)
+}
@@ -13,10 +13,16 @@ import (
 	"github.com/pkg/errors"
 )
 
+// extensionSectionV1 marks the beginning of the optional block extension section
+// that follows the metadata section in the serialized block bytes. Blocks serialized
+// before the introduction of this section end after the metadata section.
+const extensionSectionV1 = uint64(1)
+
 type serializedBlockInfo struct {
 	blockHeader *common.BlockHeader
 	txOffsets   []*txindexInfo
 	metadata    *common.BlockMetadata
+	extension   *common.BlockExtension
 }
 
 //The order of the transactions must be maintained for history
@@ -31,6 +37,7 @@ func serializeBlock(block *common.Block) ([]byte, *serializedBlockInfo, error) {
 	info := &serializedBlockInfo{}
 	info.blockHeader = block.Header
 	info.metadata = block.Metadata
+	info.extension = block.Extension
 	if err = addHeaderBytes(block.Header, buf); err != nil {
 		return nil, nil, err
 	}
@@ -40,6 +47,9 @@ func serializeBlock(block *common.Block) ([]byte, *serializedBlockInfo, error) {
 	if err = addMetadataBytes(block.Metadata, buf); err != nil {
 		return nil, nil, err
 	}
+	if err = addExtensionBytes(block.Extension, buf); err != nil {
+		return nil, nil, err
+	}
 	return buf.Bytes(), info, nil
 }
 
@@ -56,6 +66,9 @@ func deserializeBlock(serializedBlockBytes []byte) (*common.Block, error) {
 	if block.Metadata, err = extractMetadata(b); err != nil {
 		return nil, err
 	}
+	if block.Extension, err = extractExtension(b); err != nil {
+		return nil, err
+	}
 	return block, nil
 }
 
@@ -76,6 +89,11 @@ func extractSerializedBlockInfo(serializedBlockBytes []byte) (*serializedBlockIn
 	if err != nil {
 		return nil, err
 	}
+
+	info.extension, err = extractExtension(b)
+	if err != nil {
+		return nil, err
+	}
 	return info, nil
 }
 
@@ -130,6 +148,27 @@ func addMetadataBytes(blockMetadata *common.BlockMetadata, buf *proto.Buffer) er
 	return nil
 }
 
+// addExtensionBytes appends the extension section to the serialized block. Nothing is
+// appended for a block that carries no extension so that such a block serializes
+// exactly as it did prior to the introduction of the extension section.
+func addExtensionBytes(blockExtension *common.BlockExtension, buf *proto.Buffer) error {
+	if blockExtension == nil {
+		return nil
+	}
+	if err := buf.EncodeVarint(extensionSectionV1); err != nil {
+		return errors.Wrap(err, "error encoding the version of block extension")
+	}
+	if err := buf.EncodeVarint(uint64(len(blockExtension.ExtensionData))); err != nil {
+		return errors.Wrap(err, "error encoding the length of block extension")
+	}
+	for _, b := range blockExtension.ExtensionData {
+		if err := buf.EncodeRawBytes(b); err != nil {
+			return errors.Wrap(err, "error encoding the block extension")
+		}
+	}
+	return nil
+}
+
 func extractHeader(buf *buffer) (*common.BlockHeader, error) {
 	header := &common.BlockHeader{}
 	var err error
@@ -192,3 +231,32 @@ func extractMetadata(buf *buffer) (*common.BlockMetadata, error) {
 	}
 	return metadata, nil
 }
+
+// extractExtension decodes the extension section, if present. A nil extension is returned
+// for the blocks that were serialized without the extension section
+func extractExtension(buf *buffer) (*common.BlockExtension, error) {
+	if buf.IsEOF() {
+		return nil, nil
+	}
+	var version uint64
+	var numItems uint64
+	var extensionEntry []byte
+	var err error
+	if version, err = buf.DecodeVarint(); err != nil {
+		return nil, errors.Wrap(err, "error decoding the version of block extension")
+	}
+	if version != extensionSectionV1 {
+		return nil, errors.Errorf("unexpected version of block extension [%d]", version)
+	}
+	if numItems, err = buf.DecodeVarint(); err != nil {
+		return nil, errors.Wrap(err, "error decoding the length of block extension")
+	}
+	extension := &common.BlockExtension{}
+	for i := uint64(0); i < numItems; i++ {
+		if extensionEntry, err = buf.DecodeRawBytes(false); err != nil {
+			return nil, errors.Wrap(err, "error decoding the block extension")
+		}
+		extension.ExtensionData = append(extension.ExtensionData, extensionEntry)
+	}
+	return extension, nil
+}
@@ -9,8 +9,8 @@ package blkstorage
 import (
 	"testing"
 
+	"github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
@@ -42,6 +42,67 @@ func TestBlockSerialization(t *testing.T) {
 	require.Equal(t, block, deserializedBlock)
 }
 
+func TestBlockSerializationWithExtension(t *testing.T) {
+	block := testutil.ConstructTestBlock(t, 1, 10, 100)
+	block.Extension = &common.BlockExtension{
+		ExtensionData: [][]byte{[]byte("extension-entry-1"), {}, []byte("extension-entry-3")},
+	}
+
+	bb, info, err := serializeBlock(block)
+	require.NoError(t, err)
+	require.Equal(t, block.Extension, info.extension)
+	deserializedBlock, err := deserializeBlock(bb)
+	require.NoError(t, err)
+	require.Equal(t, block.Extension.ExtensionData, deserializedBlock.Extension.ExtensionData)
+	require.Equal(t, block.Header, deserializedBlock.Header)
+	require.Equal(t, block.Data, deserializedBlock.Data)
+	require.Equal(t, block.Metadata, deserializedBlock.Metadata)
+
+	// the extension section is appended at the end and hence the offsets of the
+	// transactions should be the same as that of the block without extension
+	blockWithoutExtension := proto.Clone(block).(*common.Block)
+	blockWithoutExtension.Extension = nil
+	bbWithoutExtension, infoWithoutExtension, err := serializeBlock(blockWithoutExtension)
+	require.NoError(t, err)
+	require.Equal(t, infoWithoutExtension.txOffsets, info.txOffsets)
+	require.Equal(t, bbWithoutExtension, bb[:len(bbWithoutExtension)])
+	testSerializedBlockInfo(t, block, &testutilTxIDComputator{t: t, malformedTxNums: map[int]struct{}{}})
+}
+
+func TestDeserializeBlockWithoutExtensionSection(t *testing.T) {
+	block := testutil.ConstructTestBlock(t, 1, 10, 100)
+	block.Extension = nil
+
+	// serialize in the format that predates the extension section
+	buf := proto.NewBuffer(nil)
+	require.NoError(t, addHeaderBytes(block.Header, buf))
+	_, err := addDataBytesAndConstructTxIndexInfo(block.Data, buf)
+	require.NoError(t, err)
+	require.NoError(t, addMetadataBytes(block.Metadata, buf))
+
+	deserializedBlock, err := deserializeBlock(buf.Bytes())
+	require.NoError(t, err)
+	require.Nil(t, deserializedBlock.Extension)
+	require.Equal(t, block, deserializedBlock)
+
+	info, err := extractSerializedBlockInfo(buf.Bytes())
+	require.NoError(t, err)
+	require.Nil(t, info.extension)
+}
+
+func TestDeserializeBlockWithUnknownExtensionVersion(t *testing.T) {
+	block := testutil.ConstructTestBlock(t, 1, 10, 100)
+	block.Extension = nil
+	bb, _, err := serializeBlock(block)
+	require.NoError(t, err)
+
+	bb = append(bb, proto.EncodeVarint(extensionSectionV1+1)...)
+	_, err = deserializeBlock(bb)
+	require.EqualError(t, err, "unexpected version of block extension [2]")
+	_, err = extractSerializedBlockInfo(bb)
+	require.EqualError(t, err, "unexpected version of block extension [2]")
+}
+
 func TestSerializedBlockInfo(t *testing.T) {
 	c := &testutilTxIDComputator{
 		t:               t,
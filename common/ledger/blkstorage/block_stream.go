@@ -12,7 +12,6 @@ import (
 	"io"
 	"os"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 )
 
@@ -46,6 +45,9 @@ type blockPlacementInfo struct {
 	fileNum          int
 	blockStartOffset int64
 	blockBytesOffset int64
+	// compressed is set if the block is stored compressed, in which case the
+	// blockBytesOffset is the offset of the compressed bytes
+	compressed bool
 }
 
 ///////////////////////////////////
@@ -94,9 +96,8 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 		return nil, nil, nil
 	}
 	remainingBytes := fileInfo.Size() - s.currentOffset
-	// Peek 8 or smaller number of bytes (if remaining bytes are less than 8)
-	// Assumption is that a block size would be small enough to be represented in 8 bytes varint
-	peekBytes := 8
+	// Peek maxBlockRecordHeaderLen or smaller number of bytes (if remaining bytes are less than maxBlockRecordHeaderLen)
+	peekBytes := maxBlockRecordHeaderLen
 	if remainingBytes < int64(peekBytes) {
 		peekBytes = int(remainingBytes)
 		moreContentAvailable = false
@@ -105,14 +106,14 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 	if lenBytes, err = s.reader.Peek(peekBytes); err != nil {
 		return nil, nil, errors.Wrapf(err, "error peeking [%d] bytes from block file", peekBytes)
 	}
-	length, n := proto.DecodeVarint(lenBytes)
+	codec, length, n := decodeBlockRecordHeader(lenBytes)
 	if n == 0 {
-		// proto.DecodeVarint did not consume any byte at all which means that the bytes
-		// representing the size of the block are partial bytes
+		// decodeBlockRecordHeader did not consume any byte at all which means that the bytes
+		// representing the header of the block are partial bytes
 		if !moreContentAvailable {
 			return nil, nil, ErrUnexpectedEndOfBlockfile
 		}
-		panic(errors.Errorf("Error in decoding varint bytes [%#v]", lenBytes))
+		panic(errors.Errorf("Error in decoding block record header bytes [%#v]", lenBytes))
 	}
 	bytesExpected := int64(n) + int64(length)
 	if bytesExpected > remainingBytes {
@@ -120,7 +121,7 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 			bytesExpected, remainingBytes, ErrUnexpectedEndOfBlockfile)
 		return nil, nil, ErrUnexpectedEndOfBlockfile
 	}
-	// skip the bytes representing the block size
+	// skip the bytes representing the block header
 	if _, err = s.reader.Discard(n); err != nil {
 		return nil, nil, errors.Wrapf(err, "error discarding [%d] bytes", n)
 	}
@@ -129,10 +130,15 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 		logger.Errorf("Error reading [%d] bytes from file number [%d], error: %s", length, s.fileNum, err)
 		return nil, nil, errors.Wrapf(err, "error reading [%d] bytes from file number [%d]", length, s.fileNum)
 	}
+	if blockBytes, err = decompressBlockBytes(blockBytes, codec); err != nil {
+		return nil, nil, errors.WithMessagef(err, "error reading block at offset [%d] in file number [%d]", s.currentOffset, s.fileNum)
+	}
 	blockPlacementInfo := &blockPlacementInfo{
 		fileNum:          s.fileNum,
 		blockStartOffset: s.currentOffset,
-		blockBytesOffset: s.currentOffset + int64(n)}
+		blockBytesOffset: s.currentOffset + int64(n),
+		compressed:       codec != NoCompression,
+	}
 	s.currentOffset += int64(n) + int64(length)
 	logger.Debugf("Returning blockbytes - length=[%d], placementInfo={%s}", len(blockBytes), blockPlacementInfo)
 	return blockBytes, blockPlacementInfo, nil
@@ -194,6 +200,6 @@ func (s *blockStream) close() error {
 }
 
 func (i *blockPlacementInfo) String() string {
-	return fmt.Sprintf("fileNum=[%d], startOffset=[%d], bytesOffset=[%d]",
-		i.fileNum, i.blockStartOffset, i.blockBytesOffset)
+	return fmt.Sprintf("fileNum=[%d], startOffset=[%d], bytesOffset=[%d], compressed=[%t]",
+		i.fileNum, i.blockStartOffset, i.blockBytesOffset, i.compressed)
 }
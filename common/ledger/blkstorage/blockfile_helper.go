@@ -93,7 +93,14 @@ func binarySearchFileNumForBlock(rootDir string, blockNum uint64) (int, error) {
 		return -1, err
 	}
 
+	archived, err := loadArchivedBlockfiles(rootDir)
+	if err != nil {
+		return -1, err
+	}
 	beginFile := 0
+	if n := len(archived.Files); n > 0 {
+		beginFile = int(archived.Files[n-1].FileNum) + 1
+	}
 	endFile := blkfilesInfo.latestFileNumber
 
 	for endFile != beginFile {
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
@@ -27,6 +28,8 @@ const (
 	blockfilePrefix                   = "blockfile_"
 	bootstrappingSnapshotInfoFile     = "bootstrappingSnapshot.info"
 	bootstrappingSnapshotInfoTempFile = "bootstrappingSnapshotTemp.info"
+	archivedBlockfilesInfoFile        = "archivedBlockfiles.info"
+	archivedBlockfilesInfoTempFile    = "archivedBlockfilesTemp.info"
 )
 
 var (
@@ -34,6 +37,7 @@ var (
 )
 
 type blockfileMgr struct {
+	ledgerID                  string
 	rootDir                   string
 	conf                      *Conf
 	db                        *leveldbhelper.DBHandle
@@ -43,6 +47,10 @@ type blockfileMgr struct {
 	blkfilesInfoCond          *sync.Cond
 	currentFileWriter         *blockfileWriter
 	bcInfo                    atomic.Value
+	archivedBlockfiles        *ArchivedBlockfiles
+	stats                     *ledgerStats
+	archiveLock               sync.RWMutex
+	pruneLock                 sync.Mutex
 }
 
 /*
@@ -93,7 +101,7 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore
 	if err != nil {
 		panic(fmt.Sprintf("Error creating block storage root dir [%s]: %s", rootDir, err))
 	}
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{ledgerID: id, rootDir: rootDir, conf: conf, db: indexStore}
 
 	blockfilesInfo, err := mgr.loadBlkfilesInfo()
 	if err != nil {
@@ -132,12 +140,23 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore
 		return nil, err
 	}
 	mgr.bootstrappingSnapshotInfo = bsi
+	if mgr.archivedBlockfiles, err = loadArchivedBlockfiles(rootDir); err != nil {
+		return nil, err
+	}
 	mgr.currentFileWriter = currentFileWriter
 	mgr.blkfilesInfoCond = sync.NewCond(&sync.Mutex{})
 
+	// the block files that are recorded as pruned may still be present if the peer
+	// stopped while pruning them
+	if err := mgr.removePrunedBlockfiles(mgr.archivedBlockfiles.Files); err != nil {
+		return nil, err
+	}
 	if err := mgr.syncIndex(); err != nil {
 		return nil, err
 	}
+	if err := mgr.syncExtensionIndex(); err != nil {
+		return nil, err
+	}
 
 	bcInfo := &common.BlockchainInfo{}
 
@@ -296,21 +315,22 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	txOffsets := info.txOffsets
 	currentOffset := mgr.blockfilesInfo.latestFileSize
 
-	blockBytesLen := len(blockBytes)
-	blockBytesEncodedLen := proto.EncodeVarint(uint64(blockBytesLen))
-	totalBytesToAppend := blockBytesLen + len(blockBytesEncodedLen)
+	blockRecordHeader, blockRecordPayload, codec := encodeBlockRecord(blockBytes, mgr.conf.compressionCodec)
+	totalBytesToAppend := len(blockRecordHeader) + len(blockRecordPayload)
 
 	//Determine if we need to start a new file since the size of this block
 	//exceeds the amount of space left in the current file
+	movedToNextFile := false
 	if currentOffset+totalBytesToAppend > mgr.conf.maxBlockfileSize {
 		mgr.moveToNextFile()
 		currentOffset = 0
+		movedToNextFile = true
 	}
-	//append blockBytesEncodedLen to the file
-	err = mgr.currentFileWriter.append(blockBytesEncodedLen, false)
+	//append blockRecordHeader to the file
+	err = mgr.currentFileWriter.append(blockRecordHeader, false)
 	if err == nil {
-		//append the actual block bytes to the file
-		err = mgr.currentFileWriter.append(blockBytes, true)
+		//append the actual block bytes, compressed if needed, to the file
+		err = mgr.currentFileWriter.append(blockRecordPayload, true)
 	}
 	if err != nil {
 		truncateErr := mgr.currentFileWriter.truncateFile(mgr.blockfilesInfo.latestFileSize)
@@ -339,20 +359,36 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//Index block file location pointer updated with file suffex and offset for the new block
 	blockFLP := &fileLocPointer{fileSuffixNum: newBlkfilesInfo.latestFileNumber}
 	blockFLP.offset = currentOffset
-	// shift the txoffset because we prepend length of bytes before block bytes
-	for _, txOffset := range txOffsets {
-		txOffset.loc.offset += len(blockBytesEncodedLen)
+	// shift the txoffset because we prepend length of bytes before block bytes. The txoffsets
+	// of a compressed block remain relative to the uncompressed block bytes
+	compressed := codec != NoCompression
+	if !compressed {
+		for _, txOffset := range txOffsets {
+			txOffset.loc.offset += len(blockRecordHeader)
+		}
 	}
 	//save the index in the database
 	if err = mgr.index.indexBlock(&blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}); err != nil {
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata,
+		extension: block.Extension, compressed: compressed}); err != nil {
 		return err
 	}
+	if mgr.conf.compressionCodec != NoCompression && mgr.stats != nil {
+		mgr.stats.updateBlockCompressionRatio(len(blockBytes), len(blockRecordPayload))
+	}
 
 	//update the blockfilesInfo (for storage) and the blockchain info (for APIs) in the manager
 	mgr.updateBlockfilesInfo(newBlkfilesInfo)
 	mgr.updateBlockchainInfo(blockHash, block)
+
+	// the block is committed regardless of a failure in pruning the block files, which is retried
+	// when the next block file is started
+	if retainBlocks := mgr.retainBlocks(); movedToNextFile && retainBlocks > 0 && block.Header.Number+1 > retainBlocks {
+		if err := mgr.pruneBlockfiles(block.Header.Number + 1 - retainBlocks); err != nil {
+			logger.Errorf("Error while pruning the block files of ledger [%s]: %s", mgr.ledgerID, err)
+		}
+	}
 	return nil
 }
 
@@ -386,16 +422,23 @@ func (mgr *blockfileMgr) syncIndex() error {
 		return nil
 	}
 
-	startFileNum := 0
+	startFileNum := mgr.firstBlockfileNum()
 	startOffset := 0
 	skipFirstBlock := false
 	endFileNum := mgr.blockfilesInfo.latestFileNumber
 
-	firstAvailableBlkNum, err := retrieveFirstBlockNumFromFile(mgr.rootDir, 0)
+	firstAvailableBlkNum, err := retrieveFirstBlockNumFromFile(mgr.rootDir, startFileNum)
 	if err != nil {
 		return err
 	}
 
+	if nextIndexableBlock < firstAvailableBlkNum && startFileNum > 0 {
+		logger.Warningf(
+			"Blocks [%d] to [%d] are pruned from the block files and cannot be indexed. Transactions with duplicate IDs in these blocks will not be detected",
+			nextIndexableBlock, firstAvailableBlkNum-1,
+		)
+	}
+
 	if nextIndexableBlock > firstAvailableBlkNum {
 		logger.Debugf("Last block indexed [%d], Last block present in block files [%d]", lastBlockIndexed, mgr.blockfilesInfo.lastPersistedBlock)
 		var flp *fileLocPointer
@@ -444,10 +487,13 @@ func (mgr *blockfileMgr) syncIndex() error {
 		}
 
 		//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
-		//therefore just shift by the difference between blockBytesOffset and blockStartOffset
-		numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
-		for _, offset := range info.txOffsets {
-			offset.loc.offset += numBytesToShift
+		//therefore just shift by the difference between blockBytesOffset and blockStartOffset.
+		//The txOffsets of a compressed block remain relative to the uncompressed block bytes
+		if !blockPlacementInfo.compressed {
+			numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
+			for _, offset := range info.txOffsets {
+				offset.loc.offset += numBytesToShift
+			}
 		}
 
 		//Update the blockIndexInfo with what was actually stored in file system
@@ -457,6 +503,8 @@ func (mgr *blockfileMgr) syncIndex() error {
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
 		blockIdxInfo.txOffsets = info.txOffsets
 		blockIdxInfo.metadata = info.metadata
+		blockIdxInfo.extension = info.extension
+		blockIdxInfo.compressed = blockPlacementInfo.compressed
 
 		logger.Debugf("syncIndex() indexing block [%d]", blockIdxInfo.blockNum)
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
@@ -470,6 +518,54 @@ func (mgr *blockfileMgr) syncIndex() error {
 	return nil
 }
 
+// syncExtensionIndex rebuilds the index of the block extension entries from the block files
+// if the type URLs of the indexed entries, or their key fields, have changed since the index was built.
+func (mgr *blockfileMgr) syncExtensionIndex() error {
+	stale, err := mgr.index.isExtensionIndexStale()
+	if err != nil || !stale {
+		return err
+	}
+
+	logger.Infof("Rebuilding the index of block extension entries of types %s", mgr.index.extensionIndexTypes())
+	if err := mgr.index.dropExtensionIndex(); err != nil {
+		return err
+	}
+	if !mgr.blockfilesInfo.noBlockFiles && len(mgr.index.extensionKeys) != 0 {
+		stream, err := newBlockStream(mgr.rootDir, mgr.firstBlockfileNum(), 0, mgr.blockfilesInfo.latestFileNumber)
+		if err != nil {
+			return err
+		}
+		defer stream.close()
+
+		batch := mgr.index.db.NewUpdateBatch()
+		for {
+			blockBytes, _, err := stream.nextBlockBytesAndPlacementInfo()
+			if err != nil {
+				return err
+			}
+			if blockBytes == nil {
+				break
+			}
+			info, err := extractSerializedBlockInfo(blockBytes)
+			if err != nil {
+				return err
+			}
+			mgr.index.addExtensionIndexEntries(batch, info.blockHeader.Number, info.extension)
+			if batch.Len() >= rebuildExtensionIndexBatchSize {
+				if err := mgr.index.db.WriteBatch(batch, true); err != nil {
+					return err
+				}
+				batch = mgr.index.db.NewUpdateBatch()
+			}
+		}
+		if err := mgr.index.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+	}
+	logger.Info("Finished rebuilding the index of block extension entries")
+	return mgr.index.saveExtensionIndexTypes()
+}
+
 func (mgr *blockfileMgr) getBlockchainInfo() *common.BlockchainInfo {
 	return mgr.bcInfo.Load().(*common.BlockchainInfo)
 }
@@ -514,6 +610,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if blockNum < mgr.firstBlockNumInBlockfiles() {
+		return mgr.retrieveArchivedBlock(blockNum)
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -521,6 +620,11 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 	return mgr.fetchBlock(loc)
 }
 
+func (mgr *blockfileMgr) retrieveBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error) {
+	logger.Debugf("retrieveBlockNumsByExtensionKey() - typeURL = [%s], key = [%x]", typeURL, key)
+	return mgr.index.getBlockNumsByExtensionKey(typeURL, key)
+}
+
 func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error) {
 	logger.Debugf("retrieveBlockByTxID() - txID = [%s]", txID)
 	loc, err := mgr.index.getBlockLocByTxID(txID)
@@ -529,6 +633,9 @@ func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error)
 			"details for the TXID [%s] not available. Ledger bootstrapped from a snapshot. First available block = [%d]",
 			txID, mgr.firstPossibleBlockNumberInBlockFiles())
 	}
+	if archivedErr, ok := err.(*archivedTxErr); ok {
+		return mgr.retrieveArchivedBlock(archivedErr.blockNum)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -554,6 +661,13 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if blockNum < mgr.firstBlockNumInBlockfiles() {
+		block, err := mgr.retrieveArchivedBlock(blockNum)
+		if err != nil {
+			return nil, err
+		}
+		return block.Header, nil
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -576,6 +690,9 @@ func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
 			startNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if firstBlockNum := mgr.firstBlockNumInBlockfiles(); startNum < firstBlockNum && !mgr.fetchesArchivedBlocks() {
+		return nil, &ledger.BlockArchivedErr{BlockNum: startNum, FirstAvailableBlockNum: firstBlockNum}
+	}
 	return newBlockItr(mgr, startNum), nil
 }
 
@@ -587,6 +704,9 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 			"details for the TXID [%s] not available. Ledger bootstrapped from a snapshot. First available block = [%d]",
 			txID, mgr.firstPossibleBlockNumberInBlockFiles())
 	}
+	if archivedErr, ok := err.(*archivedTxErr); ok {
+		return mgr.retrieveArchivedTransaction(archivedErr.blockNum, archivedErr.txNum)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -601,6 +721,9 @@ func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, t
 			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if blockNum < mgr.firstBlockNumInBlockfiles() {
+		return mgr.retrieveArchivedTransaction(blockNum, tranNum)
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -624,7 +747,12 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 	logger.Debugf("Entering fetchTransactionEnvelope() %v\n", lp)
 	var err error
 	var txEnvelopeBytes []byte
-	if txEnvelopeBytes, err = mgr.fetchRawBytes(lp); err != nil {
+	if lp.inCompressedBlock {
+		txEnvelopeBytes, err = mgr.fetchRawBytesFromCompressedBlock(lp)
+	} else {
+		txEnvelopeBytes, err = mgr.fetchRawBytes(lp)
+	}
+	if err != nil {
 		return nil, err
 	}
 	_, n := proto.DecodeVarint(txEnvelopeBytes)
@@ -658,6 +786,22 @@ func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
 	return b, nil
 }
 
+// fetchRawBytesFromCompressedBlock returns the bytes at the location within the uncompressed bytes
+// of the compressed block that the location pointer refers to
+func (mgr *blockfileMgr) fetchRawBytesFromCompressedBlock(lp *fileLocPointer) ([]byte, error) {
+	blockBytes, err := mgr.fetchBlockBytes(&fileLocPointer{
+		fileSuffixNum: lp.fileSuffixNum,
+		locPointer:    locPointer{offset: lp.blockOffset},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if lp.offset+lp.bytesLength > len(blockBytes) {
+		return nil, errors.Errorf("location [%s] is beyond the bytes of the compressed block of length [%d]", lp, len(blockBytes))
+	}
+	return blockBytes[lp.offset : lp.offset+lp.bytesLength], nil
+}
+
 //Get the current blockfilesInfo information that is stored in the database
 func (mgr *blockfileMgr) loadBlkfilesInfo() (*blockfilesInfo, error) {
 	var b []byte
@@ -12,8 +12,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
@@ -155,6 +155,33 @@ func testBlockfileMgrBlockIterator(t *testing.T, blockfileMgr *blockfileMgr,
 	require.Equal(t, lastBlockNum-firstBlockNum+1, numBlocksItrated)
 }
 
+func TestBlockfileMgrBlockExtension(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	for i, block := range blocks[1:] {
+		block.Extension = &common.BlockExtension{
+			ExtensionData: [][]byte{[]byte(fmt.Sprintf("extension-for-block-%d", i+1))},
+		}
+	}
+
+	// index only the first half of the blocks so that the remaining blocks
+	// get indexed from the block files during index sync
+	originalIndexStore := blkfileMgr.index.db
+	blkfileMgrWrapper.addBlocks(blocks[:5])
+	blkfileMgr.index.db = env.provider.leveldbProvider.GetDBHandle("someRandomPlace")
+	blkfileMgrWrapper.addBlocks(blocks[5:])
+	blkfileMgr.index.db = originalIndexStore
+	require.NoError(t, blkfileMgr.syncIndex())
+
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+	blkfileMgrWrapper.testGetBlockByHash(blocks, nil)
+	testBlockfileMgrBlockIterator(t, blkfileMgr, 0, 9, blocks)
+}
+
 func TestBlockfileMgrBlockchainInfo(t *testing.T) {
 	env := newTestEnv(t, NewConf(testPath(), 0))
 	defer env.Cleanup()
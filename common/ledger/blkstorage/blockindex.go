@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"unicode/utf8"
 
 	"github.com/arogyaGurkha/fabric-protos-go/common"
@@ -19,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
@@ -27,7 +29,9 @@ const (
 	blockHashIdxKeyPrefix       = 'h'
 	txIDIdxKeyPrefix            = 't'
 	blockNumTranNumIdxKeyPrefix = 'a'
+	extensionIdxKeyPrefix       = 'e'
 	indexSavePointKeyStr        = "indexCheckpointKey"
+	extensionIndexTypesKeyStr   = "indexedExtensionTypesKey"
 
 	snapshotFileFormat       = byte(1)
 	snapshotDataFileName     = "txids.data"
@@ -36,9 +40,11 @@ const (
 
 var (
 	indexSavePointKey              = []byte(indexSavePointKeyStr)
+	extensionIndexTypesKey         = []byte(extensionIndexTypesKeyStr)
 	errIndexSavePointKeyNotPresent = errors.New("NoBlockIndexed")
 	errNilValue                    = errors.New("")
 	importTxIDsBatchSize           = uint64(1000) // txID is 64 bytes, so batch size roughly translates to 64KB
+	rebuildExtensionIndexBatchSize = 1000
 )
 
 type blockIdxInfo struct {
@@ -47,11 +53,17 @@ type blockIdxInfo struct {
 	flp       *fileLocPointer
 	txOffsets []*txindexInfo
 	metadata  *common.BlockMetadata
+	extension *common.BlockExtension
+	// compressed is set if the block is stored compressed, in which case the
+	// txOffsets are relative to the uncompressed block bytes
+	compressed bool
 }
 
 type blockIndex struct {
-	indexItemsMap map[IndexableAttr]bool
-	db            *leveldbhelper.DBHandle
+	indexItemsMap      map[IndexableAttr]bool
+	extensionKeys      map[string]extensionKeyFunc
+	extensionKeyFields map[string]string
+	db                 *leveldbhelper.DBHandle
 }
 
 func newBlockIndex(indexConfig *IndexConfig, db *leveldbhelper.DBHandle) (*blockIndex, error) {
@@ -61,9 +73,23 @@ func newBlockIndex(indexConfig *IndexConfig, db *leveldbhelper.DBHandle) (*block
 	for _, indexItem := range indexItems {
 		indexItemsMap[indexItem] = true
 	}
+	extensionKeys := make(map[string]extensionKeyFunc)
+	extensionKeyFields := make(map[string]string)
+	if indexItemsMap[IndexableAttrExtension] {
+		for typeURL, field := range indexConfig.ExtensionKeys {
+			keyFunc, err := newExtensionKeyFunc(typeURL, field)
+			if err != nil {
+				return nil, err
+			}
+			extensionKeys[typeURL] = keyFunc
+			extensionKeyFields[typeURL] = field
+		}
+	}
 	return &blockIndex{
-		indexItemsMap: indexItemsMap,
-		db:            db,
+		indexItemsMap:      indexItemsMap,
+		extensionKeys:      extensionKeys,
+		extensionKeyFields: extensionKeyFields,
+		db:                 db,
 	}, nil
 }
 
@@ -110,7 +136,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index3 Used to find a transaction by its transaction id
 	if index.isAttributeIndexed(IndexableAttrTxID) {
 		for i, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLocationPointer(txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to txid-index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -136,7 +162,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index4 - Store BlockNumTranNum will be used to query history data
 	if index.isAttributeIndexed(IndexableAttrBlockNumTranNum) {
 		for i, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLocationPointer(txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx number:[%d] ID: [%s] to blockNumTranNum index", txFlp, i, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -146,6 +172,11 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 		}
 	}
 
+	//Index5 - Used to find the blocks whose extension carries an entry with a given key
+	if index.isAttributeIndexed(IndexableAttrExtension) {
+		index.addExtensionIndexEntries(batch, blkNum, blockIdxInfo.extension)
+	}
+
 	batch.Put(indexSavePointKey, encodeBlockNum(blockIdxInfo.blockNum))
 	// Setting snyc to true as a precaution, false may be an ok optimization after further testing.
 	if err := index.db.WriteBatch(batch, true); err != nil {
@@ -154,6 +185,34 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
 
+// addExtensionIndexEntries adds to the batch the keys of the entries of the block extension
+// whose type URLs are indexed. An entry whose keys cannot be computed is not indexed, as the
+// block must be committed regardless.
+func (index *blockIndex) addExtensionIndexEntries(batch *leveldbhelper.UpdateBatch, blockNum uint64, extension *common.BlockExtension) {
+	if extension == nil {
+		return
+	}
+	for i, data := range extension.ExtensionData {
+		entry, err := protoutil.UnmarshalBlockExtensionEntry(data)
+		if err != nil {
+			logger.Debugf("Block extension data [%d] of block [%d] is not an entry, skipping", i, blockNum)
+			continue
+		}
+		keyFunc, ok := index.extensionKeys[entry.TypeUrl]
+		if !ok {
+			continue
+		}
+		keys, err := keyFunc(entry)
+		if err != nil {
+			logger.Warningf("Failed computing the index keys of block extension entry [%d] of type [%s] of block [%d]: %s", i, entry.TypeUrl, blockNum, err)
+			continue
+		}
+		for _, key := range keys {
+			batch.Put(constructExtensionKey(entry.TypeUrl, key, blockNum), []byte{})
+		}
+	}
+}
+
 func (index *blockIndex) isAttributeIndexed(attribute IndexableAttr) bool {
 	_, ok := index.indexItemsMap[attribute]
 	return ok
@@ -192,10 +251,13 @@ func (index *blockIndex) getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer
 }
 
 func (index *blockIndex) getTxLoc(txID string) (*fileLocPointer, error) {
-	v, err := index.getTxIDVal(txID)
+	v, blkNum, txNum, err := index.getTxIDValAndNums(txID)
 	if err != nil {
 		return nil, err
 	}
+	if v.Archived {
+		return nil, &archivedTxErr{blockNum: blkNum, txNum: txNum}
+	}
 	txFLP := &fileLocPointer{}
 	if err = txFLP.unmarshal(v.TxLocation); err != nil {
 		return nil, err
@@ -204,10 +266,13 @@ func (index *blockIndex) getTxLoc(txID string) (*fileLocPointer, error) {
 }
 
 func (index *blockIndex) getBlockLocByTxID(txID string) (*fileLocPointer, error) {
-	v, err := index.getTxIDVal(txID)
+	v, blkNum, txNum, err := index.getTxIDValAndNums(txID)
 	if err != nil {
 		return nil, err
 	}
+	if v.Archived {
+		return nil, &archivedTxErr{blockNum: blkNum, txNum: txNum}
+	}
 	blkFLP := &fileLocPointer{}
 	if err = blkFLP.unmarshal(v.BlkLocation); err != nil {
 		return nil, err
@@ -224,32 +289,43 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 }
 
 func (index *blockIndex) getTxIDVal(txID string) (*TxIDIndexValue, error) {
+	val, _, _, err := index.getTxIDValAndNums(txID)
+	return val, err
+}
+
+// getTxIDValAndNums returns the index value of the first transaction with the given txID, along with
+// the number of the block and the number of the transaction within the block
+func (index *blockIndex) getTxIDValAndNums(txID string) (*TxIDIndexValue, uint64, uint64, error) {
 	if !index.isAttributeIndexed(IndexableAttrTxID) {
-		return nil, ErrAttrNotIndexed
+		return nil, 0, 0, ErrAttrNotIndexed
 	}
 	rangeScan := constructTxIDRangeScan(txID)
 	itr, err := index.db.GetIterator(rangeScan.startKey, rangeScan.stopKey)
 	if err != nil {
-		return nil, errors.WithMessagef(err, "error while trying to retrieve transaction info by TXID [%s]", txID)
+		return nil, 0, 0, errors.WithMessagef(err, "error while trying to retrieve transaction info by TXID [%s]", txID)
 	}
 	defer itr.Release()
 
 	present := itr.Next()
 	if err := itr.Error(); err != nil {
-		return nil, errors.Wrapf(err, "error while trying to retrieve transaction info by TXID [%s]", txID)
+		return nil, 0, 0, errors.Wrapf(err, "error while trying to retrieve transaction info by TXID [%s]", txID)
 	}
 	if !present {
-		return nil, ErrNotFoundInIndex
+		return nil, 0, 0, ErrNotFoundInIndex
 	}
 	valBytes := itr.Value()
 	if len(valBytes) == 0 {
-		return nil, errNilValue
+		return nil, 0, 0, errNilValue
 	}
 	val := &TxIDIndexValue{}
 	if err := proto.Unmarshal(valBytes, val); err != nil {
-		return nil, errors.Wrapf(err, "unexpected error while unmarshaling bytes [%#v] into TxIDIndexValProto", valBytes)
+		return nil, 0, 0, errors.Wrapf(err, "unexpected error while unmarshaling bytes [%#v] into TxIDIndexValProto", valBytes)
 	}
-	return val, nil
+	blkNum, txNum, err := retrieveTxNums(itr.Key())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return val, blkNum, txNum, nil
 }
 
 func (index *blockIndex) getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error) {
@@ -268,6 +344,117 @@ func (index *blockIndex) getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint
 	return txFLP, nil
 }
 
+func (index *blockIndex) getBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error) {
+	if _, ok := index.extensionKeys[typeURL]; !ok {
+		return nil, ErrAttrNotIndexed
+	}
+	rangeScan := constructExtensionKeyRangeScan(typeURL, key)
+	itr, err := index.db.GetIterator(rangeScan.startKey, rangeScan.stopKey)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while trying to retrieve blocks by extension key of type [%s]", typeURL)
+	}
+	defer itr.Release()
+
+	var blockNums []uint64
+	for itr.Next() {
+		blockNum, _, err := util.DecodeOrderPreservingVarUint64(itr.Key()[len(rangeScan.startKey):])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid extension key {%x}", itr.Key())
+		}
+		blockNums = append(blockNums, blockNum)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrapf(err, "error while trying to retrieve blocks by extension key of type [%s]", typeURL)
+	}
+	if len(blockNums) == 0 {
+		return nil, ErrNotFoundInIndex
+	}
+	return blockNums, nil
+}
+
+// isExtensionIndexStale returns true if the type URLs of the indexed block extension
+// entries, or the fields under which they are indexed, differ from the ones the index
+// was built for.
+func (index *blockIndex) isExtensionIndexStale() (bool, error) {
+	b, err := index.db.Get(extensionIndexTypesKey)
+	if err != nil {
+		return false, err
+	}
+	indexedTypes := &ExtensionIndexTypes{}
+	if err := proto.Unmarshal(b, indexedTypes); err != nil {
+		return false, errors.Wrapf(err, "unexpected error while unmarshaling bytes [%#v] into ExtensionIndexTypes", b)
+	}
+	typeURLs := index.extensionIndexTypes()
+	if len(typeURLs) != len(indexedTypes.TypeUrls) {
+		return true, nil
+	}
+	for i, typeURL := range typeURLs {
+		if indexedTypes.TypeUrls[i] != typeURL {
+			return true, nil
+		}
+		// the indexes built before the fields were recorded index the entries under their payload
+		indexedField := ""
+		if i < len(indexedTypes.KeyFields) {
+			indexedField = indexedTypes.KeyFields[i]
+		}
+		if indexedField != index.extensionKeyFields[typeURL] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// extensionIndexTypes returns the sorted type URLs of the indexed block extension entries
+func (index *blockIndex) extensionIndexTypes() []string {
+	typeURLs := make([]string, 0, len(index.extensionKeys))
+	for typeURL := range index.extensionKeys {
+		typeURLs = append(typeURLs, typeURL)
+	}
+	sort.Strings(typeURLs)
+	return typeURLs
+}
+
+// dropExtensionIndex deletes the index of the block extension entries, along with
+// the type URLs it was built for
+func (index *blockIndex) dropExtensionIndex() error {
+	itr, err := index.db.GetIterator([]byte{extensionIdxKeyPrefix}, []byte{extensionIdxKeyPrefix + 1})
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+
+	batch := index.db.NewUpdateBatch()
+	for itr.Next() {
+		batch.Delete(itr.Key())
+		if batch.Len() >= rebuildExtensionIndexBatchSize {
+			if err := index.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = index.db.NewUpdateBatch()
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while iterating for extension index entries")
+	}
+	batch.Delete(extensionIndexTypesKey)
+	return index.db.WriteBatch(batch, true)
+}
+
+// saveExtensionIndexTypes records the type URLs the index of the block extension entries is built
+// for, along with the fields under which they are indexed
+func (index *blockIndex) saveExtensionIndexTypes() error {
+	typeURLs := index.extensionIndexTypes()
+	keyFields := make([]string, len(typeURLs))
+	for i, typeURL := range typeURLs {
+		keyFields[i] = index.extensionKeyFields[typeURL]
+	}
+	b, err := proto.Marshal(&ExtensionIndexTypes{TypeUrls: typeURLs, KeyFields: keyFields})
+	if err != nil {
+		return errors.Wrap(err, "unexpected error while marshaling ExtensionIndexTypes")
+	}
+	return index.db.Put(extensionIndexTypesKey, b, true)
+}
+
 func (index *blockIndex) exportUniqueTxIDs(dir string, newHashFunc snapshot.NewHashFunc) (map[string][]byte, error) {
 	if !index.isAttributeIndexed(IndexableAttrTxID) {
 		return nil, ErrAttrNotIndexed
@@ -420,6 +607,25 @@ func retrieveTxID(encodedTxIDKey []byte) (string, error) {
 	return string(remainingBytes[:int(txIDLen)]), nil
 }
 
+// retrieveTxNums takes input an encoded txid key of the format `prefix:len(TxID):TxID:BlkNum:TxNum`
+// and returns the BlkNum and the TxNum from this
+func retrieveTxNums(encodedTxIDKey []byte) (uint64, uint64, error) {
+	txID, err := retrieveTxID(encodedTxIDKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	remainingBytes := encodedTxIDKey[len(constructTxIDRangeScan(txID).startKey):]
+	blkNum, n, err := util.DecodeOrderPreservingVarUint64(remainingBytes)
+	if err != nil {
+		return 0, 0, errors.WithMessagef(err, "invalid txIDKey {%x}", encodedTxIDKey)
+	}
+	txNum, _, err := util.DecodeOrderPreservingVarUint64(remainingBytes[n:])
+	if err != nil {
+		return 0, 0, errors.WithMessagef(err, "invalid txIDKey {%x}", encodedTxIDKey)
+	}
+	return blkNum, txNum, nil
+}
+
 type rangeScan struct {
 	startKey []byte
 	stopKey  []byte
@@ -444,6 +650,25 @@ func constructBlockNumTranNumKey(blockNum uint64, txNum uint64) []byte {
 	return append([]byte{blockNumTranNumIdxKeyPrefix}, key...)
 }
 
+func constructExtensionKey(typeURL string, key []byte, blockNum uint64) []byte {
+	k := constructExtensionKeyRangeScan(typeURL, key).startKey
+	return append(k, util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+func constructExtensionKeyRangeScan(typeURL string, key []byte) *rangeScan {
+	sk := append(
+		[]byte{extensionIdxKeyPrefix},
+		util.EncodeOrderPreservingVarUint64(uint64(len(typeURL)))...,
+	)
+	sk = append(sk, typeURL...)
+	sk = append(sk, util.EncodeOrderPreservingVarUint64(uint64(len(key)))...)
+	sk = append(sk, key...)
+	return &rangeScan{
+		startKey: sk,
+		stopKey:  append(sk, 0xff),
+	}
+}
+
 func encodeBlockNum(blockNum uint64) []byte {
 	return proto.EncodeVarint(blockNum)
 }
@@ -467,6 +692,10 @@ func (lp *locPointer) String() string {
 type fileLocPointer struct {
 	fileSuffixNum int
 	locPointer
+	// inCompressedBlock is set for the location of a transaction in a compressed block, in which
+	// case the locPointer is relative to the uncompressed bytes of the block that starts at blockOffset
+	inCompressedBlock bool
+	blockOffset       int
 }
 
 func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *locPointer) *fileLocPointer {
@@ -476,6 +705,25 @@ func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *
 	return flp
 }
 
+// newCompressedBlockLocationPointer returns the pointer to a location within the uncompressed bytes of
+// the compressed block that starts at blockOffset in the block file
+func newCompressedBlockLocationPointer(fileSuffixNum int, blockOffset int, relativeLP *locPointer) *fileLocPointer {
+	return &fileLocPointer{
+		fileSuffixNum:     fileSuffixNum,
+		locPointer:        *relativeLP,
+		inCompressedBlock: true,
+		blockOffset:       blockOffset,
+	}
+}
+
+func (blockIdxInfo *blockIdxInfo) txLocationPointer(relativeLP *locPointer) *fileLocPointer {
+	flp := blockIdxInfo.flp
+	if blockIdxInfo.compressed {
+		return newCompressedBlockLocationPointer(flp.fileSuffixNum, flp.offset, relativeLP)
+	}
+	return newFileLocationPointer(flp.fileSuffixNum, flp.offset, relativeLP)
+}
+
 func (flp *fileLocPointer) marshal() ([]byte, error) {
 	buffer := proto.NewBuffer([]byte{})
 	e := buffer.EncodeVarint(uint64(flp.fileSuffixNum))
@@ -490,11 +738,19 @@ func (flp *fileLocPointer) marshal() ([]byte, error) {
 	if e != nil {
 		return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
 	}
+	// the offset of the compressed block is appended only for the locations within a compressed block
+	// so that the other locations marshal exactly as they did prior to the introduction of compression
+	if flp.inCompressedBlock {
+		e = buffer.EncodeVarint(uint64(flp.blockOffset))
+		if e != nil {
+			return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
 func (flp *fileLocPointer) unmarshal(b []byte) error {
-	buffer := proto.NewBuffer(b)
+	buffer := newBuffer(b)
 	i, e := buffer.DecodeVarint()
 	if e != nil {
 		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
@@ -511,10 +767,22 @@ func (flp *fileLocPointer) unmarshal(b []byte) error {
 		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
 	}
 	flp.bytesLength = int(i)
+	if buffer.IsEOF() {
+		return nil
+	}
+	i, e = buffer.DecodeVarint()
+	if e != nil {
+		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
+	}
+	flp.inCompressedBlock = true
+	flp.blockOffset = int(i)
 	return nil
 }
 
 func (flp *fileLocPointer) String() string {
+	if flp.inCompressedBlock {
+		return fmt.Sprintf("fileSuffixNum=%d, blockOffset=%d, %s", flp.fileSuffixNum, flp.blockOffset, flp.locPointer.String())
+	}
 	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
 }
 
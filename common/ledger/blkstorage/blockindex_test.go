@@ -15,7 +15,10 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/ledger/snapshot"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/ledger/util"
@@ -206,6 +209,102 @@ func containsAttr(indexItems []IndexableAttr, attr IndexableAttr) bool {
 	return false
 }
 
+func TestExtensionIndex(t *testing.T) {
+	conf := NewConf(testPath(), 0)
+	defer os.RemoveAll(conf.blockStorageDir)
+	ledgerid := "testledger"
+
+	openStore := func(extensionKeys map[string]string) (*BlockStoreProvider, *BlockStore) {
+		indexConfig := &IndexConfig{
+			AttrsToIndex:  append([]IndexableAttr{IndexableAttrExtension}, attrsToIndex...),
+			ExtensionKeys: extensionKeys,
+		}
+		provider, err := NewProvider(conf, indexConfig, &disabled.Provider{})
+		require.NoError(t, err)
+		store, err := provider.Open(ledgerid)
+		require.NoError(t, err)
+		return provider, store
+	}
+
+	addEntry := func(block *common.Block, typeURL, payload string) {
+		err := protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+			TypeUrl: typeURL,
+			Payload: []byte(payload),
+		})
+		require.NoError(t, err)
+	}
+
+	blocks := testutil.ConstructTestBlocks(t, 4)
+	addEntry(blocks[1], "tag", "X")
+	addEntry(blocks[2], "tag", "Y")
+	addEntry(blocks[2], "other", "X")
+	addEntry(blocks[3], "tag", "X")
+	timestampPayload, err := proto.Marshal(&blockextension.BatchTimestamp{Timestamp: &timestamp.Timestamp{Seconds: 42}})
+	require.NoError(t, err)
+	addEntry(blocks[3], blockextension.BatchTimestampType, string(timestampPayload))
+	blocks[3].Extension.ExtensionData = append(blocks[3].Extension.ExtensionData, []byte("not an entry"))
+
+	provider, store := openStore(map[string]string{"tag": ""})
+	for _, block := range blocks {
+		require.NoError(t, store.AddBlock(block))
+	}
+
+	blockNums, err := store.RetrieveBlockNumsByExtensionKey("tag", []byte("X"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3}, blockNums)
+	blockNums, err = store.RetrieveBlockNumsByExtensionKey("tag", []byte("Y"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, blockNums)
+	_, err = store.RetrieveBlockNumsByExtensionKey("tag", []byte("Z"))
+	require.Exactly(t, ErrNotFoundInIndex, err)
+	_, err = store.RetrieveBlockNumsByExtensionKey("other", []byte("X"))
+	require.Exactly(t, ErrAttrNotIndexed, err)
+	provider.Close()
+
+	// the index is rebuilt when an indexed type is added
+	provider, store = openStore(map[string]string{"tag": "", "other": "", blockextension.BatchTimestampType: ""})
+	blockNums, err = store.RetrieveBlockNumsByExtensionKey("other", []byte("X"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, blockNums)
+	blockNums, err = store.RetrieveBlockNumsByExtensionKey("tag", []byte("X"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3}, blockNums)
+	blockNums, err = store.RetrieveBlockNumsByExtensionKey(blockextension.BatchTimestampType, timestampPayload)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, blockNums)
+	provider.Close()
+
+	// the index is rebuilt when the key field of an indexed type changes
+	provider, store = openStore(map[string]string{"tag": "", "other": "", blockextension.BatchTimestampType: "timestamp.seconds"})
+	blockNums, err = store.RetrieveBlockNumsByExtensionKey(blockextension.BatchTimestampType, []byte("42"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, blockNums)
+	_, err = store.RetrieveBlockNumsByExtensionKey(blockextension.BatchTimestampType, timestampPayload)
+	require.Exactly(t, ErrNotFoundInIndex, err)
+	blockNums, err = store.RetrieveBlockNumsByExtensionKey("tag", []byte("X"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3}, blockNums)
+	provider.Close()
+
+	// a key field that does not resolve against the payload type is rejected
+	indexConfig := &IndexConfig{
+		AttrsToIndex:  append([]IndexableAttr{IndexableAttrExtension}, attrsToIndex...),
+		ExtensionKeys: map[string]string{blockextension.BatchTimestampType: "timestamp.unknown"},
+	}
+	_, err = NewProvider(conf, indexConfig, &disabled.Provider{})
+	require.EqualError(t, err, "cannot index the block extension entries of type [hyperledger.org/fabric/blockextension/BatchTimestamp] by field [timestamp.unknown]: message Timestamp has no field [unknown]")
+
+	// the index is dropped when no type is indexed
+	provider, store = openStore(nil)
+	defer provider.Close()
+	_, err = store.RetrieveBlockNumsByExtensionKey("tag", []byte("X"))
+	require.Exactly(t, ErrAttrNotIndexed, err)
+	itr, err := store.fileMgr.index.db.GetIterator([]byte{extensionIdxKeyPrefix}, []byte{extensionIdxKeyPrefix + 1})
+	require.NoError(t, err)
+	defer itr.Release()
+	require.False(t, itr.Next())
+}
+
 func TestTxIDKeyEncodingDecoding(t *testing.T) {
 	testcases := []struct {
 		txid   string
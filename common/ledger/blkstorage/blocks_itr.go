@@ -18,6 +18,7 @@ type blocksItr struct {
 	maxBlockNumAvailable uint64
 	blockNumToRetrieve   uint64
 	stream               *blockStream
+	archivedStream       *archivedBlockStream
 	closeMarker          bool
 	closeMarkerLock      *sync.Mutex
 }
@@ -25,7 +26,7 @@ type blocksItr struct {
 func newBlockItr(mgr *blockfileMgr, startBlockNum uint64) *blocksItr {
 	mgr.blkfilesInfoCond.L.Lock()
 	defer mgr.blkfilesInfoCond.L.Unlock()
-	return &blocksItr{mgr, mgr.blockfilesInfo.lastPersistedBlock, startBlockNum, nil, false, &sync.Mutex{}}
+	return &blocksItr{mgr, mgr.blockfilesInfo.lastPersistedBlock, startBlockNum, nil, nil, false, &sync.Mutex{}}
 }
 
 func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
@@ -52,6 +53,23 @@ func (itr *blocksItr) initStream() error {
 	return nil
 }
 
+// nextArchivedBlockBytes reads the next block from the archived block files, opening the archived
+// block file that contains the next block when the blocks of the previous one are exhausted
+func (itr *blocksItr) nextArchivedBlockBytes() ([]byte, error) {
+	if itr.archivedStream != nil && itr.blockNumToRetrieve > itr.archivedStream.file.LastBlockNum {
+		itr.archivedStream.close()
+		itr.archivedStream = nil
+	}
+	if itr.archivedStream == nil {
+		s, err := itr.mgr.openArchivedBlockStream(itr.blockNumToRetrieve)
+		if err != nil {
+			return nil, err
+		}
+		itr.archivedStream = s
+	}
+	return itr.archivedStream.nextBlockBytes()
+}
+
 func (itr *blocksItr) shouldClose() bool {
 	itr.closeMarkerLock.Lock()
 	defer itr.closeMarkerLock.Unlock()
@@ -68,6 +86,18 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 	if itr.closeMarker {
 		return nil, nil
 	}
+	if itr.stream == nil && itr.blockNumToRetrieve < itr.mgr.firstBlockNumInBlockfiles() {
+		nextBlockBytes, err := itr.nextArchivedBlockBytes()
+		if err != nil {
+			return nil, err
+		}
+		itr.blockNumToRetrieve++
+		return deserializeBlock(nextBlockBytes)
+	}
+	if itr.archivedStream != nil {
+		itr.archivedStream.close()
+		itr.archivedStream = nil
+	}
 	if itr.stream == nil {
 		logger.Debugf("Initializing block stream for iterator. itr.maxBlockNumAvailable=%d", itr.maxBlockNumAvailable)
 		if err := itr.initStream(); err != nil {
@@ -93,4 +123,7 @@ func (itr *blocksItr) Close() {
 	if itr.stream != nil {
 		itr.stream.close()
 	}
+	if itr.archivedStream != nil {
+		itr.archivedStream.close()
+	}
 }
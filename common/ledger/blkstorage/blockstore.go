@@ -36,6 +36,7 @@ func newBlockStore(id string, conf *Conf, indexConfig *IndexConfig,
 	ledgerStats := stats.ledgerStats(id)
 	info := fileMgr.getBlockchainInfo()
 	ledgerStats.updateBlockchainHeight(info.Height)
+	fileMgr.stats = ledgerStats
 
 	return &BlockStore{id, conf, fileMgr, ledgerStats}, nil
 }
@@ -87,6 +88,12 @@ func (store *BlockStore) RetrieveBlockByTxID(txID string) (*common.Block, error)
 	return store.fileMgr.retrieveBlockByTxID(txID)
 }
 
+// RetrieveBlockNumsByExtensionKey returns the numbers of the blocks, in ascending order, whose
+// extension carries an entry of the given type URL that is indexed under the given key
+func (store *BlockStore) RetrieveBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error) {
+	return store.fileMgr.retrieveBlockNumsByExtensionKey(typeURL, key)
+}
+
 // RetrieveTxValidationCodeByTxID returns the validation code for the specified txID
 func (store *BlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
@@ -29,11 +29,17 @@ const (
 	IndexableAttrBlockHash       = IndexableAttr("BlockHash")
 	IndexableAttrTxID            = IndexableAttr("TxID")
 	IndexableAttrBlockNumTranNum = IndexableAttr("BlockNumTranNum")
+	IndexableAttrExtension       = IndexableAttr("Extension")
 )
 
 // IndexConfig - a configuration that includes a list of attributes that should be indexed
 type IndexConfig struct {
 	AttrsToIndex []IndexableAttr
+	// ExtensionKeys maps the type URLs of the block extension entries that are indexed, if
+	// IndexableAttrExtension is indexed, to the fields of their payloads under which they
+	// are indexed, given as paths of proto field names separated by dots. An empty field
+	// indexes the entries under their whole payload
+	ExtensionKeys map[string]string
 }
 
 // SnapshotInfo captures some of the details about the snapshot
@@ -72,6 +78,12 @@ type BlockStoreProvider struct {
 
 // NewProvider constructs a filesystem based block store provider
 func NewProvider(conf *Conf, indexConfig *IndexConfig, metricsProvider metrics.Provider) (*BlockStoreProvider, error) {
+	for typeURL, field := range indexConfig.ExtensionKeys {
+		if _, err := newExtensionKeyFunc(typeURL, field); err != nil {
+			return nil, err
+		}
+	}
+
 	dbConf := &leveldbhelper.Conf{
 		DBPath:         conf.getIndexDir(),
 		ExpectedFormat: dataFormatVersion(indexConfig),
@@ -20,15 +20,46 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	retentionPolicy  *RetentionPolicy
+	compressionCodec CompressionCodec
+}
+
+// RetentionPolicy configures the pruning of the block files. A block file is pruned only when
+// all of its blocks are below the retained range, and the current block file is never pruned.
+type RetentionPolicy struct {
+	// RetainBlocks is the number of the most recent blocks that are retained in the block files.
+	// The older block files are pruned when a new block file is started. Zero disables the pruning
+	// on new block files, in which case the block files are pruned only on `BlockStore.PruneBlocks`.
+	RetainBlocks uint64
+	// Sink receives the block files before they are pruned. If nil, the block files are deleted
+	// without being archived.
+	Sink ArchiveSink
+	// FetchArchived, if true, serves the requests for the pruned blocks from the Sink.
+	FetchArchived bool
 }
 
 // NewConf constructs new `Conf`.
 // blockStorageDir is the top level folder under which `BlockStore` manages its data
 func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
+	return NewConfWithRetentionPolicy(blockStorageDir, maxBlockfileSize, nil)
+}
+
+// NewConfWithRetentionPolicy constructs new `Conf` that prunes the block files as per the retentionPolicy.
+// A nil retentionPolicy retains all the block files.
+func NewConfWithRetentionPolicy(blockStorageDir string, maxBlockfileSize int, retentionPolicy *RetentionPolicy) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir, maxBlockfileSize, retentionPolicy, NoCompression}
+}
+
+// WithCompression returns a copy of the conf that compresses the blocks with the given codec
+// when they are added to the block files. The blocks that are already present in the block
+// files remain readable irrespective of the codec
+func (conf *Conf) WithCompression(codec CompressionCodec) *Conf {
+	c := *conf
+	c.compressionCodec = codec
+	return &c
 }
 
 func (conf *Conf) getIndexDir() string {
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/pkg/errors"
+)
+
+// extensionKeyFunc returns the keys under which an entry of a block extension is indexed
+type extensionKeyFunc func(entry *blockextension.Entry) ([][]byte, error)
+
+// newExtensionKeyFunc returns the function that computes the keys of the block extension
+// entries with the given type URL. An empty field indexes the entries under their whole
+// payload. Otherwise, the field is a path of proto field names separated by dots, which
+// is resolved against the payload type registered for the type URL, and the entries are
+// indexed under the value of that field:
+//   - bytes and strings are used as is
+//   - integers and enums are formatted in decimal, booleans as "true" or "false"
+//   - messages are marshaled
+//   - repeated fields yield one key per element
+//
+// The path can only go through singular message fields. The entries of the producers
+// that failed are not indexed.
+func newExtensionKeyFunc(typeURL, field string) (extensionKeyFunc, error) {
+	if field == "" {
+		return payloadKey, nil
+	}
+	payload := blockextension.PayloadType(typeURL)
+	if payload == nil {
+		return nil, errors.Errorf("cannot index the block extension entries of type [%s] by field [%s]: no payload type is registered for the type", typeURL, field)
+	}
+	fieldIndexes, err := resolveFieldPath(reflect.TypeOf(payload), strings.Split(field, "."))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "cannot index the block extension entries of type [%s] by field [%s]", typeURL, field)
+	}
+
+	return func(entry *blockextension.Entry) ([][]byte, error) {
+		if entry.Error != "" {
+			return nil, nil
+		}
+		payload := blockextension.PayloadType(typeURL)
+		if err := proto.Unmarshal(entry.Payload, payload); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling the payload of type [%s]", typeURL)
+		}
+		v := reflect.ValueOf(payload)
+		for _, i := range fieldIndexes {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem().Field(i)
+		}
+		return fieldKeys(v)
+	}, nil
+}
+
+// payloadKey indexes an entry of a block extension under its payload. The entries
+// of the producers that failed are not indexed
+func payloadKey(entry *blockextension.Entry) ([][]byte, error) {
+	if entry.Error != "" {
+		return nil, nil
+	}
+	return [][]byte{entry.Payload}, nil
+}
+
+// resolveFieldPath returns the indexes of the struct fields along the path of proto field
+// names, starting from the message type t
+func resolveFieldPath(t reflect.Type, path []string) ([]int, error) {
+	fieldIndexes := make([]int, 0, len(path))
+	for depth, name := range path {
+		if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			return nil, errors.Errorf("field [%s] is not a message", strings.Join(path[:depth], "."))
+		}
+		index := -1
+		for i, prop := range proto.GetProperties(t.Elem()).Prop {
+			if prop.Tag > 0 && prop.OrigName == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, errors.Errorf("message %s has no field [%s]", t.Elem().Name(), name)
+		}
+		fieldIndexes = append(fieldIndexes, index)
+		t = t.Elem().Field(index).Type
+	}
+	if !isKeyType(t) && !(t.Kind() == reflect.Slice && isKeyType(t.Elem())) {
+		return nil, errors.Errorf("field [%s] of type %s cannot be used as a key", strings.Join(path, "."), t)
+	}
+	return fieldIndexes, nil
+}
+
+func isKeyType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Ptr:
+		return t.Elem().Kind() == reflect.Struct
+	default:
+		return false
+	}
+}
+
+// fieldKeys returns the keys for the value of a field, one for each element if the field is repeated
+func fieldKeys(v reflect.Value) ([][]byte, error) {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		keys := make([][]byte, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			key, err := fieldKey(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+		return keys, nil
+	}
+	key, err := fieldKey(v)
+	if err != nil || key == nil {
+		return nil, err
+	}
+	return [][]byte{key}, nil
+}
+
+// fieldKey returns the key for the value of a singular field, nil if the field is an unset message
+func fieldKey(v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return []byte(v.String()), nil
+	case reflect.Bool:
+		return []byte(strconv.FormatBool(v.Bool())), nil
+	case reflect.Int32, reflect.Int64:
+		return []byte(strconv.FormatInt(v.Int(), 10)), nil
+	case reflect.Uint32, reflect.Uint64:
+		return []byte(strconv.FormatUint(v.Uint(), 10)), nil
+	case reflect.Slice:
+		return v.Bytes(), nil
+	default:
+		if v.IsNil() {
+			return nil, nil
+		}
+		key, err := proto.Marshal(v.Interface().(proto.Message))
+		return key, errors.Wrap(err, "error marshaling the key")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionKeyFunc(t *testing.T) {
+	blockextension.RegisterPayloadType("test/block-numbers", func() proto.Message { return &blockextension.BlockNumbers{} })
+
+	entry := func(typeURL string, payload proto.Message) *blockextension.Entry {
+		payloadBytes, err := proto.Marshal(payload)
+		require.NoError(t, err)
+		return &blockextension.Entry{TypeUrl: typeURL, Payload: payloadBytes}
+	}
+	batchTimestamp := entry(blockextension.BatchTimestampType, &blockextension.BatchTimestamp{Timestamp: &timestamp.Timestamp{Seconds: 42, Nanos: 7}})
+	timestampBytes, err := proto.Marshal(&timestamp.Timestamp{Seconds: 42, Nanos: 7})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		entry        *blockextension.Entry
+		field        string
+		expectedKeys [][]byte
+	}{
+		{
+			name:         "payload",
+			entry:        &blockextension.Entry{TypeUrl: "test/unregistered", Payload: []byte("payload")},
+			expectedKeys: [][]byte{[]byte("payload")},
+		},
+		{
+			name:         "bytes",
+			entry:        entry(blockextension.TxIDMerkleRootType, &blockextension.MerkleRoot{Root: []byte("root")}),
+			field:        "root",
+			expectedKeys: [][]byte{[]byte("root")},
+		},
+		{
+			name:         "unsigned integer",
+			entry:        entry(blockextension.NamespaceBloomFilterType, &blockextension.BloomFilter{HashFunctions: 3}),
+			field:        "hash_functions",
+			expectedKeys: [][]byte{[]byte("3")},
+		},
+		{
+			name:         "nested integer",
+			entry:        batchTimestamp,
+			field:        "timestamp.seconds",
+			expectedKeys: [][]byte{[]byte("42")},
+		},
+		{
+			name:         "message",
+			entry:        batchTimestamp,
+			field:        "timestamp",
+			expectedKeys: [][]byte{timestampBytes},
+		},
+		{
+			name:  "unset message",
+			entry: entry(blockextension.BatchTimestampType, &blockextension.BatchTimestamp{}),
+			field: "timestamp.seconds",
+		},
+		{
+			name:         "repeated",
+			entry:        entry("test/block-numbers", &blockextension.BlockNumbers{Numbers: []uint64{5, 1}}),
+			field:        "numbers",
+			expectedKeys: [][]byte{[]byte("5"), []byte("1")},
+		},
+		{
+			name:  "failed producer",
+			entry: &blockextension.Entry{TypeUrl: blockextension.TxIDMerkleRootType, Error: "oops"},
+			field: "root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyFunc, err := newExtensionKeyFunc(tt.entry.TypeUrl, tt.field)
+			require.NoError(t, err)
+			keys, err := keyFunc(tt.entry)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedKeys, keys)
+		})
+	}
+
+	t.Run("invalid payload", func(t *testing.T) {
+		keyFunc, err := newExtensionKeyFunc(blockextension.TxIDMerkleRootType, "root")
+		require.NoError(t, err)
+		_, err = keyFunc(&blockextension.Entry{TypeUrl: blockextension.TxIDMerkleRootType, Payload: []byte("garbage")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error unmarshaling the payload of type [hyperledger.org/fabric/blockextension/TxIDMerkleRoot]")
+	})
+}
+
+func TestExtensionKeyFuncInvalidField(t *testing.T) {
+	tests := []struct {
+		typeURL     string
+		field       string
+		expectedErr string
+	}{
+		{
+			typeURL:     "test/unregistered",
+			field:       "root",
+			expectedErr: "cannot index the block extension entries of type [test/unregistered] by field [root]: no payload type is registered for the type",
+		},
+		{
+			typeURL:     blockextension.TxIDMerkleRootType,
+			field:       "unknown",
+			expectedErr: "cannot index the block extension entries of type [hyperledger.org/fabric/blockextension/TxIDMerkleRoot] by field [unknown]: message MerkleRoot has no field [unknown]",
+		},
+		{
+			typeURL:     blockextension.TxIDMerkleRootType,
+			field:       "root.length",
+			expectedErr: "cannot index the block extension entries of type [hyperledger.org/fabric/blockextension/TxIDMerkleRoot] by field [root.length]: field [root] is not a message",
+		},
+		{
+			typeURL:     blockextension.BatchTimestampType,
+			field:       "timestamp.XXX_sizecache",
+			expectedErr: "cannot index the block extension entries of type [hyperledger.org/fabric/blockextension/BatchTimestamp] by field [timestamp.XXX_sizecache]: message Timestamp has no field [XXX_sizecache]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			_, err := newExtensionKeyFunc(tt.typeURL, tt.field)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
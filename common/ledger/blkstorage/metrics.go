@@ -15,12 +15,14 @@ import (
 type stats struct {
 	blockchainHeight       metrics.Gauge
 	blockstorageCommitTime metrics.Histogram
+	blockCompressionRatio  metrics.Histogram
 }
 
 func newStats(metricsProvider metrics.Provider) *stats {
 	stats := &stats{}
 	stats.blockchainHeight = metricsProvider.NewGauge(blockchainHeightOpts)
 	stats.blockstorageCommitTime = metricsProvider.NewHistogram(blockstorageCommitTimeOpts)
+	stats.blockCompressionRatio = metricsProvider.NewHistogram(blockCompressionRatioOpts)
 	return stats
 }
 
@@ -46,6 +48,10 @@ func (s *ledgerStats) updateBlockstorageCommitTime(timeTaken time.Duration) {
 	s.stats.blockstorageCommitTime.With("channel", s.ledgerid).Observe(timeTaken.Seconds())
 }
 
+func (s *ledgerStats) updateBlockCompressionRatio(blockBytesLen, storedBytesLen int) {
+	s.stats.blockCompressionRatio.With("channel", s.ledgerid).Observe(float64(blockBytesLen) / float64(storedBytesLen))
+}
+
 var (
 	blockchainHeightOpts = metrics.GaugeOpts{
 		Namespace:    "ledger",
@@ -65,4 +71,14 @@ var (
 		StatsdFormat: "%{#fqname}.%{channel}",
 		Buckets:      []float64{0.005, 0.01, 0.015, 0.05, 0.1, 1, 10},
 	}
+
+	blockCompressionRatioOpts = metrics.HistogramOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "blockstorage_compression_ratio",
+		Help:         "Ratio of the size of the serialized block to the size stored in the block file.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+		Buckets:      []float64{1, 1.5, 2, 3, 4, 6, 8, 12},
+	}
 )
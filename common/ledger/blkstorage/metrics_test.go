@@ -119,12 +119,14 @@ type testMetricProvider struct {
 	fakeProvider                   *metricsfakes.Provider
 	fakeBlockchainHeightGauge      *metricsfakes.Gauge
 	fakeBlockstorageCommitTimeHist *metricsfakes.Histogram
+	fakeBlockCompressionRatioHist  *metricsfakes.Histogram
 }
 
 func testutilConstructMetricProvider() *testMetricProvider {
 	fakeProvider := &metricsfakes.Provider{}
 	fakeBlockchainHeightGauge := testutilConstructGauge()
 	fakeBlockstorageCommitTimeHist := testutilConstructHist()
+	fakeBlockCompressionRatioHist := testutilConstructHist()
 	fakeProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
 		switch opts.Name {
 		case blockchainHeightOpts.Name:
@@ -137,6 +139,8 @@ func testutilConstructMetricProvider() *testMetricProvider {
 		switch opts.Name {
 		case blockstorageCommitTimeOpts.Name:
 			return fakeBlockstorageCommitTimeHist
+		case blockCompressionRatioOpts.Name:
+			return fakeBlockCompressionRatioHist
 		default:
 			return nil
 		}
@@ -146,6 +150,7 @@ func testutilConstructMetricProvider() *testMetricProvider {
 		fakeProvider,
 		fakeBlockchainHeightGauge,
 		fakeBlockstorageCommitTimeHist,
+		fakeBlockCompressionRatioHist,
 	}
 }
 
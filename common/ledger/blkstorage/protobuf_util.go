@@ -49,3 +49,8 @@ func (b *buffer) DecodeRawBytes(alloc bool) ([]byte, error) {
 func (b *buffer) GetBytesConsumed() int {
 	return b.position
 }
+
+// IsEOF returns true if all the bytes in the underlying []byte have been consumed
+func (b *buffer) IsEOF() bool {
+	return b.position >= len(b.buf.Bytes())
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/pkg/errors"
 )
 
 // ResetBlockStore drops the block storage index and truncates the blocks files for all channels/ledgers to genesis blocks
@@ -71,6 +72,14 @@ func resetToGenesisBlk(ledgerDir string) error {
 	if lastFileNum < 0 {
 		return nil
 	}
+	archived, err := loadArchivedBlockfiles(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if len(archived.Files) > 0 {
+		return errors.Errorf("cannot reset the ledger [%s] to genesis block as the blocks up to [%d] are pruned",
+			ledgerDir, archived.Files[len(archived.Files)-1].LastBlockNum)
+	}
 	zeroFilePath, genesisBlkEndOffset, err := retrieveGenesisBlkOffsetAndMakeACopy(ledgerDir)
 	if err != nil {
 		return err
@@ -12,9 +12,9 @@ import (
 	"path"
 	"testing"
 
+	"github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
@@ -85,6 +85,11 @@ func TestResetBlockStore(t *testing.T) {
 	blocks1 := testutil.ConstructTestBlocks(t, 20) // 20 blocks persisted in ~5 block files
 	blocks2 := testutil.ConstructTestBlocks(t, 40) // 40 blocks persisted in ~5 block files
 	maxFileSie := int(0.2 * float64(testutilEstimateTotalSizeOnDisk(t, blocks1)))
+	// the genesis block carries the config envelope in its extension as well and
+	// should still fit in the first block file
+	if genesisBlockSize := testutilEstimateTotalSizeOnDisk(t, blocks1[:1]); maxFileSie < genesisBlockSize {
+		maxFileSie = genesisBlockSize
+	}
 
 	env := newTestEnv(t, NewConf(blockStoreRootDir, maxFileSie))
 	defer env.Cleanup()
@@ -41,6 +41,13 @@ func Rollback(blockStorageDir, ledgerID string, targetBlockNum uint64, indexConf
 		return err
 	}
 
+	// the index of the block extension entries is not keyed by block number, so it
+	// is dropped as a whole and rebuilt from the block files when the store is opened
+	logger.Info("Dropping the index of block extension entries")
+	if err := r.indexStore.dropExtensionIndex(); err != nil {
+		return err
+	}
+
 	logger.Infof("Rolling back block files to block number [%d]", targetBlockNum)
 	if err := r.rollbackBlockFiles(); err != nil {
 		return err
@@ -266,5 +273,13 @@ func validateTargetBlkNum(ledgerDir string, targetBlockNum uint64) error {
 		return errors.Errorf("target block number [%d] should be less than the biggest block number [%d]",
 			targetBlockNum, blkfilesInfo.lastPersistedBlock)
 	}
+	archived, err := loadArchivedBlockfiles(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if n := len(archived.Files); n > 0 && targetBlockNum <= archived.Files[n-1].LastBlockNum {
+		return errors.Errorf("target block number [%d] should be greater than the last pruned block number [%d]",
+			targetBlockNum, archived.Files[n-1].LastBlockNum)
+	}
 	return nil
 }
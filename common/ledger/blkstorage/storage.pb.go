@@ -21,9 +21,12 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type TxIDIndexValue struct {
-	BlkLocation          []byte   `protobuf:"bytes,1,opt,name=blk_location,json=blkLocation,proto3" json:"blk_location,omitempty"`
-	TxLocation           []byte   `protobuf:"bytes,2,opt,name=tx_location,json=txLocation,proto3" json:"tx_location,omitempty"`
-	TxValidationCode     int32    `protobuf:"varint,3,opt,name=tx_validation_code,json=txValidationCode,proto3" json:"tx_validation_code,omitempty"`
+	BlkLocation      []byte `protobuf:"bytes,1,opt,name=blk_location,json=blkLocation,proto3" json:"blk_location,omitempty"`
+	TxLocation       []byte `protobuf:"bytes,2,opt,name=tx_location,json=txLocation,proto3" json:"tx_location,omitempty"`
+	TxValidationCode int32  `protobuf:"varint,3,opt,name=tx_validation_code,json=txValidationCode,proto3" json:"tx_validation_code,omitempty"`
+	// archived is set when the block of the transaction has been archived
+	// and pruned from the block files
+	Archived             bool     `protobuf:"varint,4,opt,name=archived,proto3" json:"archived,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -75,6 +78,13 @@ func (m *TxIDIndexValue) GetTxValidationCode() int32 {
 	return 0
 }
 
+func (m *TxIDIndexValue) GetArchived() bool {
+	if m != nil {
+		return m.Archived
+	}
+	return false
+}
+
 type BootstrappingSnapshotInfo struct {
 	LastBlockNum         uint64   `protobuf:"varint,1,opt,name=lastBlockNum,proto3" json:"lastBlockNum,omitempty"`
 	LastBlockHash        []byte   `protobuf:"bytes,2,opt,name=lastBlockHash,proto3" json:"lastBlockHash,omitempty"`
@@ -130,30 +140,186 @@ func (m *BootstrappingSnapshotInfo) GetPreviousBlockHash() []byte {
 	return nil
 }
 
+type ExtensionIndexTypes struct {
+	TypeUrls []string `protobuf:"bytes,1,rep,name=type_urls,json=typeUrls,proto3" json:"type_urls,omitempty"`
+	// key_fields are the fields of the payloads under which the entries with
+	// the type URLs at the same positions are indexed, empty for the whole payload
+	KeyFields            []string `protobuf:"bytes,2,rep,name=key_fields,json=keyFields,proto3" json:"key_fields,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExtensionIndexTypes) Reset()         { *m = ExtensionIndexTypes{} }
+func (m *ExtensionIndexTypes) String() string { return proto.CompactTextString(m) }
+func (*ExtensionIndexTypes) ProtoMessage()    {}
+func (*ExtensionIndexTypes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{2}
+}
+
+func (m *ExtensionIndexTypes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExtensionIndexTypes.Unmarshal(m, b)
+}
+func (m *ExtensionIndexTypes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExtensionIndexTypes.Marshal(b, m, deterministic)
+}
+func (m *ExtensionIndexTypes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtensionIndexTypes.Merge(m, src)
+}
+func (m *ExtensionIndexTypes) XXX_Size() int {
+	return xxx_messageInfo_ExtensionIndexTypes.Size(m)
+}
+func (m *ExtensionIndexTypes) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtensionIndexTypes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExtensionIndexTypes proto.InternalMessageInfo
+
+func (m *ExtensionIndexTypes) GetTypeUrls() []string {
+	if m != nil {
+		return m.TypeUrls
+	}
+	return nil
+}
+
+func (m *ExtensionIndexTypes) GetKeyFields() []string {
+	if m != nil {
+		return m.KeyFields
+	}
+	return nil
+}
+
+type ArchivedBlockfiles struct {
+	Files                []*ArchivedBlockfile `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ArchivedBlockfiles) Reset()         { *m = ArchivedBlockfiles{} }
+func (m *ArchivedBlockfiles) String() string { return proto.CompactTextString(m) }
+func (*ArchivedBlockfiles) ProtoMessage()    {}
+func (*ArchivedBlockfiles) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{3}
+}
+
+func (m *ArchivedBlockfiles) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchivedBlockfiles.Unmarshal(m, b)
+}
+func (m *ArchivedBlockfiles) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchivedBlockfiles.Marshal(b, m, deterministic)
+}
+func (m *ArchivedBlockfiles) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchivedBlockfiles.Merge(m, src)
+}
+func (m *ArchivedBlockfiles) XXX_Size() int {
+	return xxx_messageInfo_ArchivedBlockfiles.Size(m)
+}
+func (m *ArchivedBlockfiles) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchivedBlockfiles.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchivedBlockfiles proto.InternalMessageInfo
+
+func (m *ArchivedBlockfiles) GetFiles() []*ArchivedBlockfile {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+type ArchivedBlockfile struct {
+	FileNum              uint64   `protobuf:"varint,1,opt,name=file_num,json=fileNum,proto3" json:"file_num,omitempty"`
+	FirstBlockNum        uint64   `protobuf:"varint,2,opt,name=first_block_num,json=firstBlockNum,proto3" json:"first_block_num,omitempty"`
+	LastBlockNum         uint64   `protobuf:"varint,3,opt,name=last_block_num,json=lastBlockNum,proto3" json:"last_block_num,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchivedBlockfile) Reset()         { *m = ArchivedBlockfile{} }
+func (m *ArchivedBlockfile) String() string { return proto.CompactTextString(m) }
+func (*ArchivedBlockfile) ProtoMessage()    {}
+func (*ArchivedBlockfile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{4}
+}
+
+func (m *ArchivedBlockfile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchivedBlockfile.Unmarshal(m, b)
+}
+func (m *ArchivedBlockfile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchivedBlockfile.Marshal(b, m, deterministic)
+}
+func (m *ArchivedBlockfile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchivedBlockfile.Merge(m, src)
+}
+func (m *ArchivedBlockfile) XXX_Size() int {
+	return xxx_messageInfo_ArchivedBlockfile.Size(m)
+}
+func (m *ArchivedBlockfile) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchivedBlockfile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchivedBlockfile proto.InternalMessageInfo
+
+func (m *ArchivedBlockfile) GetFileNum() uint64 {
+	if m != nil {
+		return m.FileNum
+	}
+	return 0
+}
+
+func (m *ArchivedBlockfile) GetFirstBlockNum() uint64 {
+	if m != nil {
+		return m.FirstBlockNum
+	}
+	return 0
+}
+
+func (m *ArchivedBlockfile) GetLastBlockNum() uint64 {
+	if m != nil {
+		return m.LastBlockNum
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*TxIDIndexValue)(nil), "msgs.txIDIndexValue")
 	proto.RegisterType((*BootstrappingSnapshotInfo)(nil), "msgs.bootstrappingSnapshotInfo")
+	proto.RegisterType((*ExtensionIndexTypes)(nil), "msgs.extensionIndexTypes")
+	proto.RegisterType((*ArchivedBlockfiles)(nil), "msgs.archivedBlockfiles")
+	proto.RegisterType((*ArchivedBlockfile)(nil), "msgs.archivedBlockfile")
 }
 
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 271 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0x41, 0x4b, 0xf3, 0x40,
-	0x10, 0x86, 0xc9, 0xd7, 0x7e, 0x1e, 0xa6, 0xa9, 0xe8, 0x9e, 0xea, 0xc9, 0x1a, 0x3c, 0xf4, 0x50,
-	0x9a, 0x83, 0x20, 0x9e, 0xab, 0x07, 0x03, 0xe2, 0x21, 0x82, 0x07, 0x2f, 0x61, 0x77, 0xb3, 0x4d,
-	0x96, 0x6c, 0x76, 0x96, 0xdd, 0x49, 0x89, 0x57, 0xff, 0x82, 0x7f, 0x58, 0x0c, 0xa1, 0xa5, 0x78,
-	0x9c, 0xe7, 0x7d, 0x60, 0x5e, 0x5e, 0x98, 0x07, 0x42, 0xcf, 0x2b, 0xb5, 0x71, 0x1e, 0x09, 0xd9,
-	0xb4, 0x0d, 0x55, 0x48, 0xbe, 0x22, 0x38, 0xa7, 0x3e, 0x7b, 0xca, 0x6c, 0xa9, 0xfa, 0x77, 0x6e,
-	0x3a, 0xc5, 0x6e, 0x20, 0x16, 0xa6, 0x29, 0x0c, 0x4a, 0x4e, 0x1a, 0xed, 0x22, 0x5a, 0x46, 0xab,
-	0x38, 0x9f, 0x09, 0xd3, 0xbc, 0x8c, 0x88, 0x5d, 0xc3, 0x8c, 0xfa, 0xa3, 0xf1, 0x6f, 0x30, 0x80,
-	0xfa, 0x83, 0xb0, 0x06, 0x46, 0x7d, 0xb1, 0xe7, 0x46, 0x97, 0x03, 0x28, 0x24, 0x96, 0x6a, 0x31,
-	0x59, 0x46, 0xab, 0xff, 0xf9, 0x05, 0xfd, 0x3e, 0x1a, 0x83, 0x47, 0x2c, 0x55, 0xf2, 0x1d, 0xc1,
-	0x95, 0x40, 0xa4, 0x40, 0x9e, 0x3b, 0xa7, 0x6d, 0xf5, 0x66, 0xb9, 0x0b, 0x35, 0x52, 0x66, 0x77,
-	0xc8, 0x12, 0x88, 0x0d, 0x0f, 0xb4, 0x35, 0x28, 0x9b, 0xd7, 0xae, 0x1d, 0xfa, 0x4c, 0xf3, 0x13,
-	0xc6, 0x6e, 0x61, 0x7e, 0xb8, 0x9f, 0x79, 0xa8, 0xc7, 0x4a, 0xa7, 0x90, 0xad, 0xe1, 0xd2, 0x79,
-	0xb5, 0xd7, 0xd8, 0x85, 0xa3, 0x39, 0x19, 0xcc, 0xbf, 0xc1, 0xf6, 0xe1, 0xe3, 0xbe, 0xd2, 0x54,
-	0x77, 0x62, 0x23, 0xb1, 0x4d, 0xeb, 0x4f, 0xa7, 0xbc, 0x51, 0x65, 0xa5, 0x7c, 0xba, 0xe3, 0xc2,
-	0x6b, 0x99, 0x4a, 0x6c, 0x5b, 0xb4, 0xe9, 0x08, 0x85, 0x69, 0xc6, 0x81, 0xc5, 0xd9, 0xb0, 0xf0,
-	0xdd, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd8, 0x44, 0x15, 0xf9, 0x72, 0x01, 0x00, 0x00,
+	// 419 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x51, 0x8b, 0xd3, 0x4c,
+	0x14, 0x86, 0x49, 0xdb, 0xfd, 0xbe, 0xf6, 0xb4, 0x5d, 0xdd, 0xf1, 0xc2, 0xac, 0x22, 0xd6, 0x20,
+	0xd2, 0x8b, 0xb5, 0x05, 0x05, 0xf1, 0x7a, 0x57, 0xc4, 0x82, 0x08, 0x46, 0xdd, 0x0b, 0x6f, 0xc2,
+	0x24, 0x39, 0x49, 0x86, 0x4c, 0xe6, 0x84, 0x99, 0x49, 0x49, 0xc0, 0x7f, 0xe1, 0xbd, 0xbf, 0x55,
+	0x32, 0xd6, 0xac, 0xa5, 0x57, 0xc9, 0x79, 0xde, 0x87, 0x64, 0xf2, 0xe6, 0xc0, 0xd2, 0x58, 0xd2,
+	0x3c, 0xc7, 0x4d, 0xad, 0xc9, 0x12, 0x9b, 0x54, 0x26, 0x37, 0xc1, 0x2f, 0x0f, 0xce, 0x6d, 0xbb,
+	0x7b, 0xb7, 0x53, 0x29, 0xb6, 0xb7, 0x5c, 0x36, 0xc8, 0x9e, 0xc1, 0x22, 0x96, 0x65, 0x24, 0x29,
+	0xe1, 0x56, 0x90, 0xf2, 0xbd, 0x95, 0xb7, 0x5e, 0x84, 0xf3, 0x58, 0x96, 0x1f, 0x0f, 0x88, 0x3d,
+	0x85, 0xb9, 0x6d, 0xef, 0x8c, 0x91, 0x33, 0xc0, 0xb6, 0x83, 0x70, 0x05, 0xcc, 0xb6, 0xd1, 0x9e,
+	0x4b, 0x91, 0x3a, 0x10, 0x25, 0x94, 0xa2, 0x3f, 0x5e, 0x79, 0xeb, 0xb3, 0xf0, 0xbe, 0x6d, 0x6f,
+	0x87, 0xe0, 0x86, 0x52, 0x64, 0x8f, 0x60, 0xca, 0x75, 0x52, 0x88, 0x3d, 0xa6, 0xfe, 0x64, 0xe5,
+	0xad, 0xa7, 0xe1, 0x30, 0x07, 0x3f, 0x3d, 0xb8, 0x8c, 0x89, 0xac, 0xb1, 0x9a, 0xd7, 0xb5, 0x50,
+	0xf9, 0x17, 0xc5, 0x6b, 0x53, 0x90, 0xdd, 0xa9, 0x8c, 0x58, 0x00, 0x0b, 0xc9, 0x8d, 0xbd, 0x96,
+	0x94, 0x94, 0x9f, 0x9a, 0xca, 0x9d, 0x75, 0x12, 0x1e, 0x31, 0xf6, 0x1c, 0x96, 0xc3, 0xfc, 0x81,
+	0x9b, 0xe2, 0x70, 0xdc, 0x63, 0xc8, 0xae, 0xe0, 0xa2, 0xd6, 0xb8, 0x17, 0xd4, 0x98, 0x3b, 0x73,
+	0xec, 0xcc, 0xd3, 0x20, 0xf8, 0x0c, 0x0f, 0xb0, 0xb5, 0xa8, 0x8c, 0x20, 0xe5, 0xaa, 0xfb, 0xda,
+	0xd5, 0x68, 0xd8, 0x63, 0x98, 0xd9, 0xae, 0xc6, 0xa8, 0xd1, 0xd2, 0xf8, 0xde, 0x6a, 0xbc, 0x9e,
+	0x85, 0xd3, 0x1e, 0x7c, 0xd3, 0xd2, 0xb0, 0x27, 0x00, 0x25, 0x76, 0x51, 0x26, 0x50, 0xa6, 0xc6,
+	0x1f, 0xb9, 0x74, 0x56, 0x62, 0xf7, 0xde, 0x81, 0xe0, 0x06, 0xd8, 0xdf, 0x8f, 0x76, 0xef, 0xc9,
+	0x84, 0x44, 0xc3, 0x5e, 0xc2, 0x99, 0xbb, 0x71, 0x4f, 0x9b, 0xbf, 0x7a, 0xb8, 0xe9, 0xff, 0xda,
+	0xe6, 0x44, 0x0c, 0xff, 0x58, 0xc1, 0x0f, 0xb8, 0x38, 0xc9, 0xd8, 0x25, 0x4c, 0xfb, 0x6b, 0xa4,
+	0x86, 0x82, 0xfe, 0xef, 0xe7, 0xbe, 0x9b, 0x17, 0x70, 0x2f, 0x13, 0xda, 0xd8, 0x28, 0xee, 0x6d,
+	0x67, 0x8c, 0x9c, 0xb1, 0x74, 0xf8, 0x9f, 0x0e, 0xcf, 0x25, 0x3f, 0xd2, 0xc6, 0xa7, 0x4d, 0x5f,
+	0xbf, 0xfd, 0xfe, 0x26, 0x17, 0xb6, 0x68, 0xe2, 0x4d, 0x42, 0xd5, 0xb6, 0xe8, 0x6a, 0xd4, 0x12,
+	0xd3, 0x1c, 0xf5, 0x36, 0xe3, 0xb1, 0x16, 0xc9, 0x36, 0xa1, 0xaa, 0x22, 0xb5, 0x3d, 0xc0, 0x58,
+	0x96, 0x87, 0x95, 0x8c, 0xff, 0x73, 0x3b, 0xf9, 0xfa, 0xf7, 0x00, 0x33, 0x0d, 0x06, 0x72, 0xa4,
+	0x02, 0x00, 0x00,
 }
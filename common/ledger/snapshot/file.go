@@ -206,7 +206,8 @@ func (r *FileReader) decodeBytes() ([]byte, error) {
 	if len(r.reusableByteSlice) < size {
 		r.reusableByteSlice = make([]byte, size)
 	}
-	if _, err := r.bufReader.Read(r.reusableByteSlice[0:size]); err != nil {
+	// A single Read may return fewer bytes than requested, when the data spans beyond the buffer
+	if _, err := io.ReadFull(r.bufReader, r.reusableByteSlice[0:size]); err != nil {
 		return nil, errors.Wrapf(err, "error while reading from snapshot file: %s", r.file.Name())
 	}
 	return r.reusableByteSlice[0:size], nil
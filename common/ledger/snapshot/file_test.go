@@ -8,6 +8,7 @@ package snapshot
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/sha256"
 	"errors"
 	"hash"
@@ -100,6 +101,31 @@ func TestFileCreateAndRead(t *testing.T) {
 	require.Equal(t, []byte{}, b)
 }
 
+func TestFileCreateAndReadLargeBytes(t *testing.T) {
+	testDir := testPath(t)
+	defer os.RemoveAll(testDir)
+
+	// bytes larger than the buffer of the reader are read in full
+	largeBytes := bytes.Repeat([]byte("large-bytes"), 10000)
+	filePath := path.Join(testDir, "snapshot-data")
+	fileCreator, err := CreateFile(filePath, byte(1), testNewHashFunc)
+	require.NoError(t, err)
+	require.NoError(t, fileCreator.EncodeBytes(largeBytes))
+	require.NoError(t, fileCreator.EncodeString("next"))
+	_, err = fileCreator.Done()
+	require.NoError(t, err)
+
+	fileReader, err := OpenFile(filePath, byte(1))
+	require.NoError(t, err)
+	defer fileReader.Close()
+	b, err := fileReader.DecodeBytes()
+	require.NoError(t, err)
+	require.Equal(t, largeBytes, b)
+	str, err := fileReader.DecodeString()
+	require.NoError(t, err)
+	require.Equal(t, "next", str)
+}
+
 func TestFileCreatorErrorPropagation(t *testing.T) {
 	testPath := testPath(t)
 	defer os.RemoveAll(testPath)
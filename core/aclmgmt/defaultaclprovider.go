@@ -83,10 +83,14 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlockExtension] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlockNumsByExtensionKey] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetStateCheckpoint] = CHANNELREADERS
 
 	//--------------- CSCC resources -----------
 	//p resources (implemented by the chaincode currently)
 	d.pResourcePolicyMap[resources.Cscc_JoinChain] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Cscc_JoinChainBySnapshot] = mgmt.Admins
 	d.pResourcePolicyMap[resources.Cscc_GetChannels] = mgmt.Members
 
 	//c resources
@@ -100,6 +104,16 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	//Event resources
 	d.cResourcePolicyMap[resources.Event_Block] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Event_FilteredBlock] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Event_BlockExtension] = CHANNELREADERS
+
+	//Snapshot resources
+	d.pResourcePolicyMap[resources.Snapshot_submitrequest] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Snapshot_cancelrequest] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Snapshot_listpending] = mgmt.Admins
+
+	//Reconciliation resources
+	d.pResourcePolicyMap[resources.Reconciliation_reconcile] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Reconciliation_getstatus] = mgmt.Admins
 
 	return d
 }
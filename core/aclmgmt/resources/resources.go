@@ -35,22 +35,36 @@ const (
 	Lscc_GetCollectionsConfig      = "lscc/GetCollectionsConfig"
 
 	//Qscc resources
-	Qscc_GetChainInfo       = "qscc/GetChainInfo"
-	Qscc_GetBlockByNumber   = "qscc/GetBlockByNumber"
-	Qscc_GetBlockByHash     = "qscc/GetBlockByHash"
-	Qscc_GetTransactionByID = "qscc/GetTransactionByID"
-	Qscc_GetBlockByTxID     = "qscc/GetBlockByTxID"
+	Qscc_GetChainInfo               = "qscc/GetChainInfo"
+	Qscc_GetBlockByNumber           = "qscc/GetBlockByNumber"
+	Qscc_GetBlockByHash             = "qscc/GetBlockByHash"
+	Qscc_GetTransactionByID         = "qscc/GetTransactionByID"
+	Qscc_GetBlockByTxID             = "qscc/GetBlockByTxID"
+	Qscc_GetBlockExtension          = "qscc/GetBlockExtension"
+	Qscc_GetBlockNumsByExtensionKey = "qscc/GetBlockNumsByExtensionKey"
+	Qscc_GetStateCheckpoint         = "qscc/GetStateCheckpoint"
 
 	//Cscc resources
-	Cscc_JoinChain      = "cscc/JoinChain"
-	Cscc_GetConfigBlock = "cscc/GetConfigBlock"
-	Cscc_GetChannels    = "cscc/GetChannels"
+	Cscc_JoinChain           = "cscc/JoinChain"
+	Cscc_JoinChainBySnapshot = "cscc/JoinChainBySnapshot"
+	Cscc_GetConfigBlock      = "cscc/GetConfigBlock"
+	Cscc_GetChannels         = "cscc/GetChannels"
 
 	//Peer resources
 	Peer_Propose              = "peer/Propose"
 	Peer_ChaincodeToChaincode = "peer/ChaincodeToChaincode"
 
 	//Events
-	Event_Block         = "event/Block"
-	Event_FilteredBlock = "event/FilteredBlock"
+	Event_Block          = "event/Block"
+	Event_FilteredBlock  = "event/FilteredBlock"
+	Event_BlockExtension = "event/BlockExtension"
+
+	//Snapshot resources
+	Snapshot_submitrequest = "snapshot/submitrequest"
+	Snapshot_cancelrequest = "snapshot/cancelrequest"
+	Snapshot_listpending   = "snapshot/listpending"
+
+	//Reconciliation resources
+	Reconciliation_reconcile = "reconciliation/reconcile"
+	Reconciliation_getstatus = "reconciliation/getstatus"
 )
@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/historyquery"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -876,21 +878,39 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 	iterID := h.UUIDGenerator.New()
 	namespaceID := txContext.NamespaceID
 
-	getHistoryForKey := &pb.GetHistoryForKey{}
+	// the payload is either a pb.GetHistoryForKey or a GetHistoryForKeyWithOptions, the latter being a wire compatible extension
+	getHistoryForKey := &historyquery.GetHistoryForKeyWithOptions{}
 	err := proto.Unmarshal(msg.Payload, getHistoryForKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
-	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKey(namespaceID, getHistoryForKey.Key)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
+	var historyIter commonledger.ResultsIterator
+	isPaginated := false
 	totalReturnLimit := h.calculateTotalReturnLimit(nil)
 
+	if getHistoryForKey.Options == nil {
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKey(namespaceID, getHistoryForKey.Key)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	} else {
+		options, err := historyQueryOptionsFromProto(getHistoryForKey.Options)
+		if err != nil {
+			return nil, err
+		}
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKeyWithOptions(namespaceID, getHistoryForKey.Key, options)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if options.PageSize > 0 {
+			isPaginated = true
+			totalReturnLimit = h.calculateTotalReturnLimit(&pb.QueryMetadata{PageSize: options.PageSize})
+		}
+	}
+
 	txContext.InitializeQueryContext(iterID, historyIter)
-	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, false, totalReturnLimit)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, isPaginated, totalReturnLimit)
 	if err != nil {
 		txContext.CleanupQueryContext(iterID)
 		return nil, errors.WithStack(err)
@@ -906,6 +926,29 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+func historyQueryOptionsFromProto(o *historyquery.HistoryQueryOptions) (*ledger.HistoryQueryOptions, error) {
+	options := &ledger.HistoryQueryOptions{
+		StartBlock:  o.StartBlock,
+		EndBlock:    o.EndBlock,
+		HasEndBlock: o.HasEndBlock,
+		Ascending:   o.Ascending,
+		PageSize:    o.PageSize,
+		Bookmark:    o.Bookmark,
+	}
+	var err error
+	if o.StartTime != nil {
+		if options.StartTime, err = ptypes.Timestamp(o.StartTime); err != nil {
+			return nil, errors.Wrap(err, "invalid start time")
+		}
+	}
+	if o.EndTime != nil {
+		if options.EndTime, err = ptypes.Timestamp(o.EndTime); err != nil {
+			return nil, errors.Wrap(err, "invalid end time")
+		}
+	}
+	return options, nil
+}
+
 func isCollectionSet(collection string) bool {
 	return collection != ""
 }
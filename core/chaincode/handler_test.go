@@ -7,19 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode_test
 
 import (
+	"fmt"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/util"
 	ar "github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/fake"
+	"github.com/hyperledger/fabric/core/chaincode/historyquery"
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/scc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -2108,6 +2115,197 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the request carries history query options", func() {
+			var startTime, endTime time.Time
+
+			BeforeEach(func() {
+				startTime = time.Unix(1000, 0).UTC()
+				endTime = time.Unix(2000, 0).UTC()
+				requestWithOptions := &historyquery.GetHistoryForKeyWithOptions{
+					Key: "history-key",
+					Options: &historyquery.HistoryQueryOptions{
+						StartBlock:  5,
+						EndBlock:    10,
+						HasEndBlock: true,
+						StartTime:   &timestamp.Timestamp{Seconds: 1000},
+						EndTime:     &timestamp.Timestamp{Seconds: 2000},
+						Ascending:   true,
+						PageSize:    3,
+						Bookmark:    "history-bookmark",
+					},
+				}
+				payload, err := proto.Marshal(requestWithOptions)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+
+				fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(fakeIterator, nil)
+			})
+
+			It("calls GetHistoryForKeyWithOptions on the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyCallCount()).To(Equal(0))
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(1))
+				ccname, key, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(key).To(Equal("history-key"))
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{
+					StartBlock:  5,
+					EndBlock:    10,
+					HasEndBlock: true,
+					StartTime:   startTime,
+					EndTime:     endTime,
+					Ascending:   true,
+					PageSize:    3,
+					Bookmark:    "history-bookmark",
+				}))
+			})
+
+			It("builds a paginated query response", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+				_, iter, _, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(iter).To(Equal(fakeIterator))
+				Expect(isPaginated).To(BeTrue())
+			})
+
+			Context("when the page size is not set", func() {
+				BeforeEach(func() {
+					requestWithOptions := &historyquery.GetHistoryForKeyWithOptions{
+						Key:     "history-key",
+						Options: &historyquery.HistoryQueryOptions{Ascending: true},
+					}
+					payload, err := proto.Marshal(requestWithOptions)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("builds a query response without pagination", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+					_, _, _, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+					Expect(isPaginated).To(BeFalse())
+				})
+			})
+
+			Context("when the start time is invalid", func() {
+				BeforeEach(func() {
+					requestWithOptions := &historyquery.GetHistoryForKeyWithOptions{
+						Key:     "history-key",
+						Options: &historyquery.HistoryQueryOptions{StartTime: &timestamp.Timestamp{Nanos: -1}},
+					}
+					payload, err := proto.Marshal(requestWithOptions)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError(ContainSubstring("invalid start time")))
+				})
+			})
+
+			Context("when the history query executor fails", func() {
+				BeforeEach(func() {
+					fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(nil, errors.New("anchovies"))
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("anchovies"))
+				})
+			})
+
+			Context("when the pages of the history are retrieved with the returned bookmarks", func() {
+				var history []*queryresult.KeyModification
+
+				BeforeEach(func() {
+					for i := 0; i < 5; i++ {
+						history = append(history, &queryresult.KeyModification{TxId: fmt.Sprintf("tx-%d", i), Value: []byte(fmt.Sprintf("value-%d", i))})
+					}
+					// the history query executor serves a page of the history, starting at the index in the bookmark
+					fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsStub = func(_, _ string, options *ledger.HistoryQueryOptions) (commonledger.QueryResultsIterator, error) {
+						next := 0
+						if options.Bookmark != "" {
+							var err error
+							if next, err = strconv.Atoi(options.Bookmark); err != nil {
+								return nil, err
+							}
+						}
+						pageEnd := next + int(options.PageSize)
+						iter := &mock.QueryResultsIterator{}
+						iter.NextStub = func() (commonledger.QueryResult, error) {
+							if next >= len(history) || next >= pageEnd {
+								return nil, nil
+							}
+							next++
+							return history[next-1], nil
+						}
+						iter.GetBookmarkAndCloseStub = func() string {
+							if next >= len(history) {
+								return ""
+							}
+							return strconv.Itoa(next)
+						}
+						return iter, nil
+					}
+
+					handler.QueryResponseBuilder = &chaincode.QueryResponseGenerator{MaxResultLimit: 10}
+					handler.TotalQueryLimit = 100
+				})
+
+				It("returns the entire history, a page at a time", func() {
+					var retrieved []*queryresult.KeyModification
+					bookmark := ""
+					for numPages := 1; ; numPages++ {
+						payload, err := proto.Marshal(&historyquery.GetHistoryForKeyWithOptions{
+							Key:     "history-key",
+							Options: &historyquery.HistoryQueryOptions{PageSize: 2, Bookmark: bookmark},
+						})
+						Expect(err).NotTo(HaveOccurred())
+						incomingMessage.Payload = payload
+
+						resp, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resp.Type).To(Equal(pb.ChaincodeMessage_RESPONSE))
+
+						queryResponse := &pb.QueryResponse{}
+						Expect(proto.Unmarshal(resp.Payload, queryResponse)).To(Succeed())
+						Expect(queryResponse.HasMore).To(BeFalse())
+						Expect(len(queryResponse.Results)).To(BeNumerically("<=", 2))
+						for _, result := range queryResponse.Results {
+							kmod := &queryresult.KeyModification{}
+							Expect(proto.Unmarshal(result.ResultBytes, kmod)).To(Succeed())
+							retrieved = append(retrieved, kmod)
+						}
+						responseMetadata := &pb.QueryResponseMetadata{}
+						Expect(proto.Unmarshal(queryResponse.Metadata, responseMetadata)).To(Succeed())
+						Expect(responseMetadata.FetchedRecordsCount).To(Equal(int32(len(queryResponse.Results))))
+						Expect(txContext.GetQueryIterator("generated-query-id")).To(BeNil())
+
+						bookmark = responseMetadata.Bookmark
+						if bookmark == "" {
+							Expect(numPages).To(Equal(3))
+							break
+						}
+					}
+
+					Expect(retrieved).To(HaveLen(len(history)))
+					for i, kmod := range retrieved {
+						Expect(proto.Equal(kmod, history[i])).To(BeTrue())
+					}
+					Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(3))
+					_, _, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(2)
+					Expect(options.Bookmark).To(Equal("4"))
+				})
+			})
+		})
+
 		Context("when HistoryQueryExecutor is nil", func() {
 			BeforeEach(func() {
 				txContext.HistoryQueryExecutor = nil
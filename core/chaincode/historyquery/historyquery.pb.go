@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: historyquery.proto
+
+package historyquery
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// GetHistoryForKeyWithOptions is the payload of a GET_HISTORY_FOR_KEY message. It is wire
+// compatible with protos.GetHistoryForKey so that chaincodes that only set the key continue
+// to receive the entire history of the key, in the order of newest to oldest
+type GetHistoryForKeyWithOptions struct {
+	Key                  string               `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Options              *HistoryQueryOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetHistoryForKeyWithOptions) Reset()         { *m = GetHistoryForKeyWithOptions{} }
+func (m *GetHistoryForKeyWithOptions) String() string { return proto.CompactTextString(m) }
+func (*GetHistoryForKeyWithOptions) ProtoMessage()    {}
+func (*GetHistoryForKeyWithOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fb176470fd082583, []int{0}
+}
+
+func (m *GetHistoryForKeyWithOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHistoryForKeyWithOptions.Unmarshal(m, b)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHistoryForKeyWithOptions.Marshal(b, m, deterministic)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHistoryForKeyWithOptions.Merge(m, src)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_Size() int {
+	return xxx_messageInfo_GetHistoryForKeyWithOptions.Size(m)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHistoryForKeyWithOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHistoryForKeyWithOptions proto.InternalMessageInfo
+
+func (m *GetHistoryForKeyWithOptions) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetHistoryForKeyWithOptions) GetOptions() *HistoryQueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// HistoryQueryOptions bounds and orders the results of a history query
+type HistoryQueryOptions struct {
+	// The first block to include, inclusive
+	StartBlock uint64 `protobuf:"varint,1,opt,name=start_block,json=startBlock,proto3" json:"start_block,omitempty"`
+	// The last block to include, inclusive. It is used only if has_end_block is set
+	EndBlock uint64 `protobuf:"varint,2,opt,name=end_block,json=endBlock,proto3" json:"end_block,omitempty"`
+	// The earliest transaction timestamp to include, inclusive
+	StartTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// The transaction timestamp to stop at, exclusive
+	EndTime *timestamp.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Return the results in the order of oldest to newest
+	Ascending bool `protobuf:"varint,5,opt,name=ascending,proto3" json:"ascending,omitempty"`
+	// The maximum number of results in a page. Zero denotes no pagination
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The bookmark returned with the previous page
+	Bookmark string `protobuf:"bytes,7,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	// Whether end_block bounds the query, so that the block zero can be the last block to include
+	HasEndBlock          bool     `protobuf:"varint,8,opt,name=has_end_block,json=hasEndBlock,proto3" json:"has_end_block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryQueryOptions) Reset()         { *m = HistoryQueryOptions{} }
+func (m *HistoryQueryOptions) String() string { return proto.CompactTextString(m) }
+func (*HistoryQueryOptions) ProtoMessage()    {}
+func (*HistoryQueryOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fb176470fd082583, []int{1}
+}
+
+func (m *HistoryQueryOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryQueryOptions.Unmarshal(m, b)
+}
+func (m *HistoryQueryOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryQueryOptions.Marshal(b, m, deterministic)
+}
+func (m *HistoryQueryOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryQueryOptions.Merge(m, src)
+}
+func (m *HistoryQueryOptions) XXX_Size() int {
+	return xxx_messageInfo_HistoryQueryOptions.Size(m)
+}
+func (m *HistoryQueryOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryQueryOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryQueryOptions proto.InternalMessageInfo
+
+func (m *HistoryQueryOptions) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryOptions) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryOptions) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryOptions) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryOptions) GetAscending() bool {
+	if m != nil {
+		return m.Ascending
+	}
+	return false
+}
+
+func (m *HistoryQueryOptions) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *HistoryQueryOptions) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
+func (m *HistoryQueryOptions) GetHasEndBlock() bool {
+	if m != nil {
+		return m.HasEndBlock
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*GetHistoryForKeyWithOptions)(nil), "historyquery.GetHistoryForKeyWithOptions")
+	proto.RegisterType((*HistoryQueryOptions)(nil), "historyquery.HistoryQueryOptions")
+}
+
+func init() { proto.RegisterFile("historyquery.proto", fileDescriptor_fb176470fd082583) }
+
+var fileDescriptor_fb176470fd082583 = []byte{
+	// 351 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x51, 0x49, 0x6b, 0xe3, 0x30,
+	0x14, 0xc6, 0x59, 0x6d, 0x65, 0x06, 0x06, 0xcd, 0xc5, 0x24, 0x03, 0xf1, 0xe4, 0xe4, 0x93, 0x0d,
+	0x2d, 0x3d, 0x84, 0xdc, 0x02, 0x5d, 0xa0, 0x87, 0x52, 0xb7, 0x50, 0xe8, 0xc5, 0xc8, 0xf6, 0x8b,
+	0x2d, 0xbc, 0xc8, 0x95, 0x94, 0x83, 0xf3, 0xeb, 0xfa, 0xd3, 0x8a, 0x24, 0xa7, 0x49, 0xa1, 0xd0,
+	0x9b, 0xde, 0xb7, 0xe9, 0x7d, 0x12, 0xc2, 0x05, 0x15, 0x92, 0xf1, 0xee, 0x6d, 0x0f, 0xbc, 0x0b,
+	0x5a, 0xce, 0x24, 0xc3, 0xbf, 0xce, 0xb1, 0xf9, 0x32, 0x67, 0x2c, 0xaf, 0x20, 0xd4, 0x5c, 0xb2,
+	0xdf, 0x85, 0x92, 0xd6, 0x20, 0x24, 0xa9, 0x5b, 0x23, 0x5f, 0x55, 0x68, 0x71, 0x0b, 0xf2, 0xce,
+	0x78, 0x6e, 0x18, 0xbf, 0x87, 0xee, 0x85, 0xca, 0xe2, 0xa1, 0x95, 0x94, 0x35, 0x02, 0xff, 0x41,
+	0xc3, 0x12, 0x3a, 0xd7, 0xf2, 0x2c, 0xdf, 0x89, 0xd4, 0x11, 0x6f, 0xd0, 0x94, 0x19, 0xd2, 0x1d,
+	0x78, 0x96, 0x3f, 0xbb, 0xf8, 0x1f, 0x7c, 0xd9, 0xa2, 0x8f, 0x7a, 0x54, 0x43, 0x9f, 0x12, 0x1d,
+	0x1d, 0xab, 0xf7, 0x01, 0xfa, 0xfb, 0x8d, 0x00, 0x2f, 0xd1, 0x4c, 0x48, 0xc2, 0x65, 0x9c, 0x54,
+	0x2c, 0x2d, 0xf5, 0x75, 0xa3, 0x08, 0x69, 0x68, 0xab, 0x10, 0xbc, 0x40, 0x0e, 0x34, 0x59, 0x4f,
+	0x0f, 0x34, 0x6d, 0x43, 0x93, 0x19, 0x72, 0x8d, 0x8c, 0x34, 0x56, 0xe5, 0xdc, 0xa1, 0xde, 0x6a,
+	0x1e, 0x98, 0xe6, 0xc1, 0xb1, 0x79, 0xf0, 0x7c, 0x6c, 0x1e, 0x39, 0x5a, 0xad, 0x66, 0x7c, 0x85,
+	0x54, 0x8c, 0x31, 0x8e, 0x7e, 0x34, 0x4e, 0xa1, 0xc9, 0xb4, 0xed, 0x1f, 0x72, 0x88, 0x48, 0xa1,
+	0xc9, 0x68, 0x93, 0xbb, 0x63, 0xcf, 0xf2, 0xed, 0xe8, 0x04, 0xa8, 0x65, 0x5b, 0x92, 0x43, 0x2c,
+	0xe8, 0x01, 0xdc, 0x89, 0x67, 0xf9, 0xe3, 0xc8, 0x56, 0xc0, 0x13, 0x3d, 0x00, 0x9e, 0x23, 0x3b,
+	0x61, 0xac, 0xac, 0x09, 0x2f, 0xdd, 0xa9, 0x7e, 0xd6, 0xcf, 0x19, 0xaf, 0xd0, 0xef, 0x82, 0x88,
+	0xf8, 0xd4, 0xd4, 0xd6, 0xd1, 0xb3, 0x82, 0x88, 0xeb, 0xbe, 0xec, 0x76, 0xf3, 0xba, 0xce, 0xa9,
+	0x2c, 0xf6, 0x49, 0x90, 0xb2, 0x3a, 0x2c, 0xba, 0x16, 0x78, 0x05, 0x59, 0x0e, 0x3c, 0xdc, 0x91,
+	0x84, 0xd3, 0x34, 0x4c, 0x19, 0x87, 0x30, 0x2d, 0x08, 0x6d, 0x52, 0x96, 0x41, 0x78, 0xfe, 0x39,
+	0xc9, 0x44, 0x97, 0xba, 0xfc, 0x18, 0x00, 0x7f, 0x08, 0x94, 0x7d, 0x39, 0x02, 0x00, 0x00,
+}
@@ -592,6 +592,27 @@ func (c *Cache) RegisterListener(channelID string, listener ledger.ChaincodeLife
 	c.eventBroker.RegisterListener(channelID, listener)
 }
 
+// ProcessInstalledChaincodes invokes the event listeners registered for the channel for each of
+// the installed chaincodes that is invokable on the channel, as if the chaincode were just installed.
+// This is used for recreating the statedb artifacts, such as indexes, in a new state database
+func (c *Cache) ProcessInstalledChaincodes(channelID string) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, localChaincode := range c.localChaincodes {
+		channelCache, ok := localChaincode.References[channelID]
+		if !ok || localChaincode.Info == nil {
+			continue
+		}
+		c.eventBroker.ProcessInstallEvent(&LocalChaincode{
+			Info: localChaincode.Info,
+			References: map[string]map[string]*CachedChaincodeDefinition{
+				channelID: channelCache,
+			},
+		})
+	}
+}
+
 func (c *Cache) InitializeMetadata(channel string) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -944,6 +944,44 @@ var _ = Describe("Cache", func() {
 			})
 		})
 
+		Context("when processing the installed chaincodes", func() {
+			BeforeEach(func() {
+				channelCache.Chaincodes["chaincode-name"].InstallInfo = &lifecycle.ChaincodeInstallInfo{
+					Label:     "chaincode-label",
+					PackageID: "packageID",
+				}
+			})
+
+			It("invokes the listener for the invokable chaincodes on the channel", func() {
+				c.ProcessInstalledChaincodes("channel-id")
+				Expect(fakeListener.HandleChaincodeDeployCallCount()).To(Equal(1))
+				Expect(fakeListener.ChaincodeDeployDoneCallCount()).To(Equal(1))
+				ccdef, dbArtifacts := fakeListener.HandleChaincodeDeployArgsForCall(0)
+				Expect(ccdef).To(Equal(&ledger.ChaincodeDefinition{
+					Name:              "chaincode-name",
+					Version:           "chaincode-version",
+					Hash:              []byte("packageID"),
+					CollectionConfigs: &pb.CollectionConfigPackage{},
+				}))
+				Expect(dbArtifacts).To(Equal([]byte("db-artifacts")))
+				Expect(fakeListener.ChaincodeDeployDoneArgsForCall(0)).To(BeTrue())
+			})
+
+			It("does not invoke the listener for the chaincodes on other channels", func() {
+				anotherListener := &ledgermock.ChaincodeLifecycleEventListener{}
+				c.RegisterListener("another-channel-id", anotherListener)
+				c.ProcessInstalledChaincodes("channel-id")
+				Expect(anotherListener.HandleChaincodeDeployCallCount()).To(Equal(0))
+				Expect(anotherListener.ChaincodeDeployDoneCallCount()).To(Equal(0))
+			})
+
+			It("does not invoke the listener when no chaincode is invokable on the channel", func() {
+				c.ProcessInstalledChaincodes("unknown-channel-id")
+				Expect(fakeListener.HandleChaincodeDeployCallCount()).To(Equal(0))
+				Expect(fakeListener.ChaincodeDeployDoneCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when new chaincode becomes available", func() {
 			var (
 				definitionTrigger *ledger.StateUpdateTrigger
@@ -64,6 +64,13 @@ type Committer interface {
 	// GetMissingPvtDataTracker return the MissingPvtDataTracker
 	GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error)
 
+	// GetStateCheckpoint returns the state checkpoint recorded at the given block,
+	// or nil if no state checkpoint is recorded at the block
+	GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error)
+
+	// GetLatestStateCheckpoint returns the latest recorded state checkpoint, or nil if none
+	GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error)
+
 	// Closes committing service
 	Close()
 }
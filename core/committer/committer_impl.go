@@ -40,6 +40,10 @@ type PeerLedgerSupport interface {
 
 	GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error)
 
+	GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error)
+
+	GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error)
+
 	Close()
 }
 
@@ -45,6 +45,31 @@ func (m *mockLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return args.Get(0).(bool), args.Error(1)
 }
 
+func (m *mockLedger) SubmitSnapshotRequest(blockNumber uint64) error {
+	args := m.Called(blockNumber)
+	return args.Error(0)
+}
+
+func (m *mockLedger) CancelSnapshotRequest(blockNumber uint64) error {
+	args := m.Called(blockNumber)
+	return args.Error(0)
+}
+
+func (m *mockLedger) PendingSnapshotRequests() ([]uint64, error) {
+	args := m.Called()
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *mockLedger) GetStateCheckpoint(blockNumber uint64) (*ledger2.StateCheckpoint, error) {
+	args := m.Called(blockNumber)
+	return args.Get(0).(*ledger2.StateCheckpoint), args.Error(1)
+}
+
+func (m *mockLedger) GetLatestStateCheckpoint() (*ledger2.StateCheckpoint, error) {
+	args := m.Called()
+	return args.Get(0).(*ledger2.StateCheckpoint), args.Error(1)
+}
+
 func (m *mockLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
 	args := m.Called(blockNumber)
 	return args.Get(0).(*common.Block), args.Error(1)
@@ -74,6 +99,11 @@ func (m *mockLedger) GetBlockByTxID(txID string) (*common.Block, error) {
 	return args.Get(0).(*common.Block), args.Error(1)
 }
 
+func (m *mockLedger) GetBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error) {
+	args := m.Called(typeURL, key)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
 func (m *mockLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
 	args := m.Called(txID)
 	return args.Get(0).(peer.TxValidationCode), args.Error(1)
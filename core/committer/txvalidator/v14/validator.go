@@ -463,6 +463,13 @@ func (v *TxValidator) checkTxIdDupsLedger(tIdx int, chdr *common.ChannelHeader,
 			tIdx:           tIdx,
 			validationCode: peer.TxValidationCode_DUPLICATE_TXID,
 		}
+	case *ledger.BlockArchivedErr:
+		// invalid case, the tx with the same id is in a block that is pruned from the block store
+		logger.Error("Duplicate transaction found in an archived block, ", txID, ", skipping")
+		return &blockValidationResult{
+			tIdx:           tIdx,
+			validationCode: peer.TxValidationCode_DUPLICATE_TXID,
+		}
 	case ledger.NotFoundInIndexErr:
 		// valid case, returned error is of type NotFoundInIndexErr.
 		// It means that no tx with the same id is found in the ledger
@@ -1444,6 +1444,12 @@ func (m *mockLedger) GetBlockByTxID(txID string) (*common.Block, error) {
 	return args.Get(0).(*common.Block), nil
 }
 
+// GetBlockNumsByExtensionKey returns the numbers of the blocks whose extension carries the key
+func (m *mockLedger) GetBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error) {
+	args := m.Called(typeURL, key)
+	return args.Get(0).([]uint64), nil
+}
+
 // GetTxValidationCodeByTxID returns validation code of give tx
 func (m *mockLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
 	args := m.Called(txID)
@@ -1505,6 +1511,31 @@ func (m *mockLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return args.Get(0).(bool), args.Error(1)
 }
 
+func (m *mockLedger) SubmitSnapshotRequest(blockNumber uint64) error {
+	args := m.Called(blockNumber)
+	return args.Error(0)
+}
+
+func (m *mockLedger) CancelSnapshotRequest(blockNumber uint64) error {
+	args := m.Called(blockNumber)
+	return args.Error(0)
+}
+
+func (m *mockLedger) PendingSnapshotRequests() ([]uint64, error) {
+	args := m.Called()
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *mockLedger) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	args := m.Called(blockNumber)
+	return args.Get(0).(*ledger.StateCheckpoint), args.Error(1)
+}
+
+func (m *mockLedger) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	args := m.Called()
+	return args.Get(0).(*ledger.StateCheckpoint), args.Error(1)
+}
+
 func (m *mockLedger) Close() {
 
 }
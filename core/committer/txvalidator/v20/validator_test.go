@@ -1059,6 +1059,33 @@ func TestDuplicateTxId(t *testing.T) {
 	assertion.True(txsfltr.Flag(0) == peer.TxValidationCode_DUPLICATE_TXID)
 }
 
+func TestDuplicateTxIdInArchivedBlock(t *testing.T) {
+	ccID := "mycc"
+
+	v, _, _, _ := setupValidator()
+
+	mockLedger := &txvalidatormocks.LedgerResources{}
+	v.LedgerResources = mockLedger
+	mockLedger.On("GetTransactionByID", mock.Anything).Return(nil, &ledger.BlockArchivedErr{BlockNum: 1, FirstAvailableBlockNum: 5})
+
+	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
+
+	b := &common.Block{
+		Data:   &common.BlockData{Data: [][]byte{protoutil.MarshalOrPanic(tx)}},
+		Header: &common.BlockHeader{},
+	}
+
+	err := v.Validate(b)
+
+	assertion := assert.New(t)
+	// We expect no validation error because the tx is found in the ledger, although its block is archived
+	assertion.NoError(err)
+
+	txsfltr := txflags.ValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assertion.True(txsfltr.IsInvalid(0))
+	assertion.True(txsfltr.Flag(0) == peer.TxValidationCode_DUPLICATE_TXID)
+}
+
 func TestValidationInvalidEndorsing(t *testing.T) {
 	ccID := "mycc"
 
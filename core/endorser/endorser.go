@@ -36,7 +36,9 @@ var endorserLogger = flogging.MustGetLogger("endorser")
 //go:generate counterfeiter -o fake/prvt_data_distributor.go --fake-name PrivateDataDistributor . PrivateDataDistributor
 
 type PrivateDataDistributor interface {
-	DistributePrivateData(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error
+	// DistributePrivateData distributes the private data of a transaction and persists it in the transient
+	// store, accounted against the quota of the client identified by the given creator
+	DistributePrivateData(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64, creator []byte) error
 }
 
 // Support contains functions that the endorser requires to execute its tasks
@@ -231,7 +233,7 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, chai
 		// manage transient store purge for orphaned private writesets (4th parameter in distributePrivateData), this works for now.
 		// Ideally, ledger should add support in the simulator as a first class function `GetHeight()`.
 		pvtDataWithConfig.EndorsedAt = endorsedAt
-		if err := e.PrivateDataDistributor.DistributePrivateData(txParams.ChannelID, txParams.TxID, pvtDataWithConfig, endorsedAt); err != nil {
+		if err := e.PrivateDataDistributor.DistributePrivateData(txParams.ChannelID, txParams.TxID, pvtDataWithConfig, endorsedAt, proposalCreator(txParams.Proposal)); err != nil {
 			e.Metrics.SimulationFailure.With(meterLabels...).Add(1)
 			return nil, nil, nil, err
 		}
@@ -462,6 +464,23 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 	}, nil
 }
 
+// proposalCreator returns the serialized identity of the creator of the proposal,
+// or nil if it cannot be extracted
+func proposalCreator(proposal *pb.Proposal) []byte {
+	if proposal == nil {
+		return nil
+	}
+	header, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return nil
+	}
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil
+	}
+	return signatureHeader.Creator
+}
+
 // determine whether or not a transaction simulator should be
 // obtained for a proposal.
 func acquireTxSimulator(chainID string, chaincodeName string) bool {
@@ -494,10 +494,13 @@ var _ = Describe("Endorser", func() {
 		_, err := e.ProcessProposal(context.Background(), signedProposal)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(fakePrivateDataDistributor.DistributePrivateDataCallCount()).To(Equal(1))
-		cid, txid, privateData, blkHt := fakePrivateDataDistributor.DistributePrivateDataArgsForCall(0)
+		cid, txid, privateData, blkHt, creator := fakePrivateDataDistributor.DistributePrivateDataArgsForCall(0)
 		Expect(cid).To(Equal("channel-id"))
 		Expect(txid).To(Equal("6f142589e4ef6a1e62c9c816e2074f70baa9f7cf67c2f0c287d4ef907d6d2015"))
 		Expect(blkHt).To(Equal(uint64(7)))
+		Expect(creator).To(Equal(protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{
+			Mspid: "msp-id",
+		})))
 
 		// TODO, this deserves a better test, but there was none before and this logic,
 		// really seems far too jumbled to be in the endorser package.  There are separate
@@ -9,13 +9,14 @@ import (
 )
 
 type PrivateDataDistributor struct {
-	DistributePrivateDataStub        func(string, string, *transientstore.TxPvtReadWriteSetWithConfigInfo, uint64) error
+	DistributePrivateDataStub        func(string, string, *transientstore.TxPvtReadWriteSetWithConfigInfo, uint64, []byte) error
 	distributePrivateDataMutex       sync.RWMutex
 	distributePrivateDataArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 *transientstore.TxPvtReadWriteSetWithConfigInfo
 		arg4 uint64
+		arg5 []byte
 	}
 	distributePrivateDataReturns struct {
 		result1 error
@@ -27,7 +28,12 @@ type PrivateDataDistributor struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PrivateDataDistributor) DistributePrivateData(arg1 string, arg2 string, arg3 *transientstore.TxPvtReadWriteSetWithConfigInfo, arg4 uint64) error {
+func (fake *PrivateDataDistributor) DistributePrivateData(arg1 string, arg2 string, arg3 *transientstore.TxPvtReadWriteSetWithConfigInfo, arg4 uint64, arg5 []byte) error {
+	var arg5Copy []byte
+	if arg5 != nil {
+		arg5Copy = make([]byte, len(arg5))
+		copy(arg5Copy, arg5)
+	}
 	fake.distributePrivateDataMutex.Lock()
 	ret, specificReturn := fake.distributePrivateDataReturnsOnCall[len(fake.distributePrivateDataArgsForCall)]
 	fake.distributePrivateDataArgsForCall = append(fake.distributePrivateDataArgsForCall, struct {
@@ -35,11 +41,12 @@ func (fake *PrivateDataDistributor) DistributePrivateData(arg1 string, arg2 stri
 		arg2 string
 		arg3 *transientstore.TxPvtReadWriteSetWithConfigInfo
 		arg4 uint64
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("DistributePrivateData", []interface{}{arg1, arg2, arg3, arg4})
+		arg5 []byte
+	}{arg1, arg2, arg3, arg4, arg5Copy})
+	fake.recordInvocation("DistributePrivateData", []interface{}{arg1, arg2, arg3, arg4, arg5Copy})
 	fake.distributePrivateDataMutex.Unlock()
 	if fake.DistributePrivateDataStub != nil {
-		return fake.DistributePrivateDataStub(arg1, arg2, arg3, arg4)
+		return fake.DistributePrivateDataStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -54,17 +61,17 @@ func (fake *PrivateDataDistributor) DistributePrivateDataCallCount() int {
 	return len(fake.distributePrivateDataArgsForCall)
 }
 
-func (fake *PrivateDataDistributor) DistributePrivateDataCalls(stub func(string, string, *transientstore.TxPvtReadWriteSetWithConfigInfo, uint64) error) {
+func (fake *PrivateDataDistributor) DistributePrivateDataCalls(stub func(string, string, *transientstore.TxPvtReadWriteSetWithConfigInfo, uint64, []byte) error) {
 	fake.distributePrivateDataMutex.Lock()
 	defer fake.distributePrivateDataMutex.Unlock()
 	fake.DistributePrivateDataStub = stub
 }
 
-func (fake *PrivateDataDistributor) DistributePrivateDataArgsForCall(i int) (string, string, *transientstore.TxPvtReadWriteSetWithConfigInfo, uint64) {
+func (fake *PrivateDataDistributor) DistributePrivateDataArgsForCall(i int) (string, string, *transientstore.TxPvtReadWriteSetWithConfigInfo, uint64, []byte) {
 	fake.distributePrivateDataMutex.RLock()
 	defer fake.distributePrivateDataMutex.RUnlock()
 	argsForCall := fake.distributePrivateDataArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *PrivateDataDistributor) DistributePrivateDataReturns(result1 error) {
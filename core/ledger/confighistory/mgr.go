@@ -29,9 +29,12 @@ const (
 	collectionConfigNamespace = "lscc" // lscc namespace was introduced in version 1.2 and we continue to use this in order to be compatible with existing data
 	snapshotFileFormat        = byte(1)
 	snapshotDataFileName      = "confighistory.data"
-	snapshotMetadataFileName  = "confighistory.metadata"
 )
 
+// SnapshotMetadataFileName is the name of the metadata file that is included in a snapshot when the
+// config history is not empty
+const SnapshotMetadataFileName = "confighistory.metadata"
+
 // Mgr manages the history of configurations such as chaincode's collection configurations.
 // It should be registered as a state listener. The state listener builds the history.
 type Mgr struct {
@@ -122,7 +125,7 @@ func (m *Mgr) ImportConfigHistory(ledgerID string, dir string) error {
 		))
 	}
 
-	configMetadata, err := snapshot.OpenFile(filepath.Join(dir, snapshotMetadataFileName), snapshotFileFormat)
+	configMetadata, err := snapshot.OpenFile(filepath.Join(dir, SnapshotMetadataFileName), snapshotFileFormat)
 	if err != nil {
 		return err
 	}
@@ -168,6 +171,11 @@ func (m *Mgr) GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetrie
 	}
 }
 
+// Drop drops the config history of the given ledger. It is not an error if the config history does not exist
+func (m *Mgr) Drop(ledgerID string) error {
+	return m.dbProvider.getDB(ledgerID).DeleteAll()
+}
+
 // Close implements the function in the interface 'Mgr'
 func (m *Mgr) Close() {
 	m.dbProvider.Close()
@@ -266,7 +274,7 @@ func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHash
 	if err != nil {
 		return nil, err
 	}
-	metadataFileWriter, err := snapshot.CreateFile(filepath.Join(dir, snapshotMetadataFileName), snapshotFileFormat, newHashFunc)
+	metadataFileWriter, err := snapshot.CreateFile(filepath.Join(dir, SnapshotMetadataFileName), snapshotFileFormat, newHashFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +289,7 @@ func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHash
 
 	return map[string][]byte{
 		snapshotDataFileName:     dataHash,
-		snapshotMetadataFileName: metadataHash,
+		SnapshotMetadataFileName: metadataHash,
 	}, nil
 }
 
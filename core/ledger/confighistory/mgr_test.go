@@ -396,7 +396,7 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 
 	cleanup := func() {
 		require.NoError(t, os.RemoveAll(filepath.Join(env.testSnapshotDir, snapshotDataFileName)))
-		require.NoError(t, os.RemoveAll(filepath.Join(env.testSnapshotDir, snapshotMetadataFileName)))
+		require.NoError(t, os.RemoveAll(filepath.Join(env.testSnapshotDir, SnapshotMetadataFileName)))
 	}
 
 	t.Run("confighistory is empty", func(t *testing.T) {
@@ -479,11 +479,11 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 		require.Contains(t, err.Error(), "error while reading from the snapshot file")
 		require.Contains(t, err.Error(), "confighistory.data: EOF")
 
-		require.NoError(t, os.RemoveAll(filepath.Join(env.testSnapshotDir, snapshotMetadataFileName)))
+		require.NoError(t, os.RemoveAll(filepath.Join(env.testSnapshotDir, SnapshotMetadataFileName)))
 		err = env.mgr.ImportConfigHistory("ledger8", env.testSnapshotDir)
 		require.Contains(t, err.Error(), "confighistory.metadata: no such file or directory")
 
-		dataFileWriter, err = snapshot.CreateFile(filepath.Join(env.testSnapshotDir, snapshotMetadataFileName), snapshotFileFormat, testNewHashFunc)
+		dataFileWriter, err = snapshot.CreateFile(filepath.Join(env.testSnapshotDir, SnapshotMetadataFileName), snapshotFileFormat, testNewHashFunc)
 		require.NoError(t, err)
 		defer dataFileWriter.Close()
 		require.NoError(t, dataFileWriter.EncodeBytes([]byte("junk")))
@@ -500,7 +500,7 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 func verifyExportedConfigHistory(t *testing.T, dir string, fileHashes map[string][]byte, expectedCollectionConfigs []*compositeKV) {
 	require.Len(t, fileHashes, 2)
 	require.Contains(t, fileHashes, snapshotDataFileName)
-	require.Contains(t, fileHashes, snapshotMetadataFileName)
+	require.Contains(t, fileHashes, SnapshotMetadataFileName)
 
 	dataFile := filepath.Join(dir, snapshotDataFileName)
 	dataFileContent, err := ioutil.ReadFile(dataFile)
@@ -508,11 +508,11 @@ func verifyExportedConfigHistory(t *testing.T, dir string, fileHashes map[string
 	dataFileHash := sha256.Sum256(dataFileContent)
 	require.Equal(t, dataFileHash[:], fileHashes[snapshotDataFileName])
 
-	metadataFile := filepath.Join(dir, snapshotMetadataFileName)
+	metadataFile := filepath.Join(dir, SnapshotMetadataFileName)
 	metadataFileContent, err := ioutil.ReadFile(metadataFile)
 	require.NoError(t, err)
 	metadataFileHash := sha256.Sum256(metadataFileContent)
-	require.Equal(t, metadataFileHash[:], fileHashes[snapshotMetadataFileName])
+	require.Equal(t, metadataFileHash[:], fileHashes[SnapshotMetadataFileName])
 
 	metadataReader, err := snapshot.OpenFile(metadataFile, snapshotFileFormat)
 	require.NoError(t, err)
@@ -583,7 +583,7 @@ func TestExportConfigHistoryErrorCase(t *testing.T) {
 
 	// error during metadata file creation
 	require.NoError(t, os.MkdirAll(env.testSnapshotDir, 0700))
-	metadataFilePath := filepath.Join(env.testSnapshotDir, snapshotMetadataFileName)
+	metadataFilePath := filepath.Join(env.testSnapshotDir, SnapshotMetadataFileName)
 	_, err = os.Create(metadataFilePath)
 	require.NoError(t, err)
 	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
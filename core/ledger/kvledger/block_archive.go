@@ -0,0 +1,233 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// ExportBlocks exports the blocks in the range [startBlockNum, endBlockNum] of a ledger to a
+// block archive created at archivePath and returns the SHA256 hash of the archive.
+// The peer is expected to be offline, as the block store is opened exclusively.
+func ExportBlocks(config *ledger.Config, ledgerID string, startBlockNum, endBlockNum uint64, archivePath string) ([]byte, error) {
+	fileLock, err := lockLedgerData(config.RootFSPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fileLock.Unlock()
+
+	blkStoreProvider, blockStore, err := openBlockStore(config, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer blkStoreProvider.Close()
+
+	newHashFunc := func() (hash.Hash, error) {
+		return sha256.New(), nil
+	}
+	return blockStore.ExportBlocks(archivePath, startBlockNum, endBlockNum, newHashFunc)
+}
+
+// ImportBlocks appends the blocks of the block archive at archivePath to a ledger. The first block
+// of the archive has to follow the last block of the ledger. The signatures of the blocks are not
+// verified, so the archive has to come from a trusted source. The state and history databases are
+// brought up to date with the imported blocks when the peer is started. The private data of the
+// imported blocks is not carried by the archive and is recorded as missing when the peer is started,
+// so that it is fetched from the other peers by the reconciler.
+// The peer is expected to be offline, as the block store is opened exclusively.
+func ImportBlocks(config *ledger.Config, ledgerID, archivePath string) error {
+	fileLock, err := lockLedgerData(config.RootFSPath)
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	blkStoreProvider, blockStore, err := openBlockStore(config, ledgerID)
+	if err != nil {
+		return err
+	}
+	defer blkStoreProvider.Close()
+
+	pvtdataStoreProvider, err := pvtdatastorage.NewProvider(
+		&pvtdatastorage.PrivateDataConfig{
+			PrivateDataConfig: config.PrivateDataConfig,
+			StorePath:         PvtDataStorePath(config.RootFSPath),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer pvtdataStoreProvider.Close()
+	pvtdataStore, err := pvtdataStoreProvider.OpenStore(ledgerID)
+	if err != nil {
+		return err
+	}
+
+	bcInfo, err := blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	pvtdataStoreHeight, err := pvtdataStore.LastCommittedBlockHeight()
+	if err != nil {
+		return err
+	}
+	if pvtdataStoreHeight != bcInfo.Height {
+		return errors.Errorf("height of the private data store [%d] does not match the height of the block store [%d], "+
+			"start the peer to recover the ledger before importing blocks", pvtdataStoreHeight, bcInfo.Height)
+	}
+
+	// The imported blocks, even partially imported, are committed to the private data store
+	// when the peer is started, as the collections of the channel are not known offline
+	if err := blockStore.ImportBlocks(archivePath); err != nil {
+		return err
+	}
+	newBCInfo, err := blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	logger.Infof("Imported blocks [%d] to [%d] to the channel [%s]", bcInfo.Height, newBCInfo.Height-1, ledgerID)
+	return nil
+}
+
+// commitImportedBlockToPvtdataStore commits a block imported from a block archive to the pvtdata
+// store, if it is not committed yet, with all the private data of its valid transactions recorded
+// as missing. It is expected to be called during the recovery, before the block is committed
+// to the state DB, so that the collection configurations are the ones the block was validated with
+func (l *kvLedger) commitImportedBlockToPvtdataStore(blockNum uint64) error {
+	pvtdataStoreHeight, err := l.pvtdataStore.LastCommittedBlockHeight()
+	if err != nil {
+		return err
+	}
+	if blockNum < pvtdataStoreHeight {
+		return nil
+	}
+	block, err := l.blockStore.RetrieveBlockByNumber(blockNum)
+	if err != nil {
+		return err
+	}
+	missingPvtData, err := l.missingPvtDataOfImportedBlock(block)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Recording the private data of [%d] transactions of the imported block [%d] as missing", len(missingPvtData), blockNum)
+	return l.pvtdataStore.Commit(blockNum, nil, missingPvtData)
+}
+
+// missingPvtDataOfImportedBlock returns the collections which the valid transactions of the block
+// write to. The eligibility of the peer is evaluated against the member orgs policies of the
+// collections as of the current state
+func (l *kvLedger) missingPvtDataOfImportedBlock(block *common.Block) (ledger.TxMissingPvtDataMap, error) {
+	qe, err := l.txmgr.NewQueryExecutorNoCollChecks()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+
+	missingPvtData := make(ledger.TxMissingPvtDataMap)
+	txsFilter := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for txNum, envBytes := range block.Data.Data {
+		if txsFilter.IsInvalid(txNum) {
+			continue
+		}
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		respPayload, err := protoutil.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			return nil, err
+		}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				hashedRWSet := collHashedRWSet.HashedRwSet
+				if hashedRWSet == nil || (len(hashedRWSet.HashedWrites) == 0 && len(hashedRWSet.MetadataWrites) == 0) {
+					continue
+				}
+				collConfig, err := l.ccInfoProvider.CollectionInfo(l.ledgerID, nsRWSet.NameSpace, collHashedRWSet.CollectionName, qe)
+				if err != nil {
+					return nil, err
+				}
+				if collConfig == nil {
+					return nil, errors.Errorf("no configuration found for the collection [%s:%s] written by the transaction [%d] of the imported block [%d]",
+						nsRWSet.NameSpace, collHashedRWSet.CollectionName, txNum, block.Header.Number)
+				}
+				isEligible, err := l.membershipInfoProvider.AmMemberOf(l.ledgerID, collConfig.MemberOrgsPolicy)
+				if err != nil {
+					return nil, err
+				}
+				missingPvtData.Add(uint64(txNum), nsRWSet.NameSpace, collHashedRWSet.CollectionName, isEligible)
+			}
+		}
+	}
+	return missingPvtData, nil
+}
+
+func lockLedgerData(rootFSPath string) (*leveldbhelper.FileLock, error) {
+	fileLock := leveldbhelper.NewFileLock(fileLockPath(rootFSPath))
+	if err := fileLock.Lock(); err != nil {
+		return nil, errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	return fileLock, nil
+}
+
+func openBlockStore(config *ledger.Config, ledgerID string) (*blkstorage.BlockStoreProvider, *blkstorage.BlockStore, error) {
+	blkStoreConf, err := blockStoreConf(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	blkStoreProvider, err := blkstorage.NewProvider(
+		blkStoreConf,
+		blockStoreIndexConfig(config.ExtensionIndexConfig),
+		&disabled.Provider{},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	exists, err := blkStoreProvider.Exists(ledgerID)
+	if err != nil {
+		blkStoreProvider.Close()
+		return nil, nil, err
+	}
+	if !exists {
+		blkStoreProvider.Close()
+		return nil, nil, errors.Errorf("ledgerID [%s] does not exist", ledgerID)
+	}
+	blockStore, err := blkStoreProvider.Open(ledgerID)
+	if err != nil {
+		blkStoreProvider.Close()
+		return nil, nil, err
+	}
+	return blkStoreProvider, blockStore, nil
+}
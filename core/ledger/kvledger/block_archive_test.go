@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportBlocks(t *testing.T) {
+	sourceConf, sourceCleanup := testConfig(t)
+	defer sourceCleanup()
+	targetConf, targetCleanup := testConfig(t)
+	defer targetCleanup()
+	archivePath := filepath.Join(sourceConf.RootFSPath, "blocks")
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	sourceProvider := testutilNewProvider(sourceConf, t, &mock.DeployedChaincodeInfoProvider{})
+	sourceLedger, err := sourceProvider.Create(gb)
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		simulator, err := sourceLedger.NewTxSimulator(util.GenerateUUID())
+		require.NoError(t, err)
+		require.NoError(t, simulator.SetState("ns1", "key1", []byte(fmt.Sprintf("value%d", i))))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		block := bg.NextBlock([][]byte{pubSimBytes})
+		require.NoError(t, sourceLedger.CommitLegacy(&lgr.BlockAndPvtData{Block: block}, &lgr.CommitOptions{}))
+	}
+
+	// export should fail when provider is still open
+	_, err = ExportBlocks(sourceConf, "testLedger", 1, 3, archivePath)
+	require.Contains(t, err.Error(), "as another peer node command is executing")
+	sourceProvider.Close()
+
+	_, err = ExportBlocks(sourceConf, "nonExistingLedger", 1, 3, archivePath)
+	require.EqualError(t, err, "ledgerID [nonExistingLedger] does not exist")
+	archiveHash, err := ExportBlocks(sourceConf, "testLedger", 1, 3, archivePath)
+	require.NoError(t, err)
+	require.NotEmpty(t, archiveHash)
+
+	targetProvider := testutilNewProvider(targetConf, t, &mock.DeployedChaincodeInfoProvider{})
+	_, err = targetProvider.Create(gb)
+	require.NoError(t, err)
+	targetProvider.Close()
+
+	require.NoError(t, ImportBlocks(targetConf, "testLedger", archivePath))
+	err = ImportBlocks(targetConf, "testLedger", archivePath)
+	require.EqualError(t, err, "height of the private data store [1] does not match the height of the block store [4], "+
+		"start the peer to recover the ledger before importing blocks")
+
+	// the state is recovered from the imported blocks when the ledger is opened
+	targetProvider = testutilNewProvider(targetConf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer targetProvider.Close()
+	targetLedger, err := targetProvider.Open("testLedger")
+	require.NoError(t, err)
+	defer targetLedger.Close()
+	bcInfo, err := targetLedger.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), bcInfo.Height)
+	qe, err := targetLedger.NewQueryExecutor()
+	require.NoError(t, err)
+	defer qe.Done()
+	value, err := qe.GetState("ns1", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3"), value)
+}
+
+func TestImportBlocksRecordsMissingPvtData(t *testing.T) {
+	sourceConf, sourceCleanup := testConfig(t)
+	defer sourceCleanup()
+	targetConf, targetCleanup := testConfig(t)
+	defer targetCleanup()
+	archivePath := filepath.Join(sourceConf.RootFSPath, "blocks")
+	nsCollBtlConfs := []*nsCollBtlConfig{
+		{
+			namespace: "ns",
+			btlConfig: map[string]uint64{"coll": 0},
+		},
+	}
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	sourceProvider := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, sourceConf)
+	sourceLedger, err := sourceProvider.Create(gb)
+	require.NoError(t, err)
+	blockAndPvtdata1 := prepareNextBlockForTest(t, sourceLedger, bg, "txid-1",
+		map[string]string{"key1": "value1"}, map[string]string{"key1": "pvtValue1"})
+	require.NoError(t, sourceLedger.CommitLegacy(blockAndPvtdata1, &lgr.CommitOptions{}))
+	blockAndPvtdata2 := prepareNextBlockForTest(t, sourceLedger, bg, "txid-2",
+		map[string]string{"key2": "value2"}, nil)
+	require.NoError(t, sourceLedger.CommitLegacy(blockAndPvtdata2, &lgr.CommitOptions{}))
+	blockAndPvtdata3 := prepareNextBlockForTest(t, sourceLedger, bg, "txid-3",
+		map[string]string{"key3": "value3"}, map[string]string{"key3": "pvtValue3"})
+	require.NoError(t, sourceLedger.CommitLegacy(blockAndPvtdata3, &lgr.CommitOptions{}))
+	sourceProvider.Close()
+	_, err = ExportBlocks(sourceConf, "testLedger", 1, 3, archivePath)
+	require.NoError(t, err)
+
+	targetProvider := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, targetConf)
+	_, err = targetProvider.Create(gb)
+	require.NoError(t, err)
+	targetProvider.Close()
+	require.NoError(t, ImportBlocks(targetConf, "testLedger", archivePath))
+
+	// the private data of the imported blocks is recorded as missing when the ledger is opened
+	membershipInfoProvider := &mock.MembershipInfoProvider{}
+	membershipInfoProvider.AmMemberOfReturns(true, nil)
+	targetProvider = testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, targetConf)
+	targetProvider.initializer.MembershipInfoProvider = membershipInfoProvider
+	defer targetProvider.Close()
+	targetLedger, err := targetProvider.Open("testLedger")
+	require.NoError(t, err)
+	defer targetLedger.Close()
+
+	missingPvtDataTracker, err := targetLedger.GetMissingPvtDataTracker()
+	require.NoError(t, err)
+	missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	require.NoError(t, err)
+	expectedMissingPvtDataInfo := make(lgr.MissingPvtDataInfo)
+	expectedMissingPvtDataInfo.Add(1, 0, "ns", "coll")
+	expectedMissingPvtDataInfo.Add(3, 0, "ns", "coll")
+	require.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+	require.Equal(t, 2, membershipInfoProvider.AmMemberOfCallCount())
+
+	pvtdata, err := targetLedger.GetPvtDataByNum(3, nil)
+	require.NoError(t, err)
+	require.Empty(t, pvtdata)
+	qe, err := targetLedger.NewQueryExecutor()
+	require.NoError(t, err)
+	defer qe.Done()
+	value, err := qe.GetState("ns", "key3")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3"), value)
+}
@@ -20,12 +20,20 @@ const (
 	PvtdataExpiry Category = iota
 	// MetadataPresenceIndicator maintains the bookkeeping about whether metadata is ever set for a namespace
 	MetadataPresenceIndicator
+	// SnapshotRequest maintains the bookkeeping about the snapshots requested for the ledger
+	SnapshotRequest
+	// StateCheckpoint maintains the bookkeeping about the rolling commitment over the state and the state checkpoints
+	StateCheckpoint
 )
 
+var categories = []Category{PvtdataExpiry, MetadataPresenceIndicator, SnapshotRequest, StateCheckpoint}
+
 // Provider provides handle to different bookkeepers for the given ledger
 type Provider interface {
 	// GetDBHandle returns a db handle that can be used for maintaining the bookkeeping of a given category
 	GetDBHandle(ledgerID string, cat Category) *leveldbhelper.DBHandle
+	// Drop drops the bookkeeping of all the categories for the given ledger
+	Drop(ledgerID string) error
 	// Close closes the BookkeeperProvider
 	Close()
 }
@@ -48,6 +56,16 @@ func (provider *provider) GetDBHandle(ledgerID string, cat Category) *leveldbhel
 	return provider.dbProvider.GetDBHandle(fmt.Sprintf(ledgerID+"/%d", cat))
 }
 
+// Drop implements the function in the interface 'BookkeeperProvider'
+func (provider *provider) Drop(ledgerID string) error {
+	for _, cat := range categories {
+		if err := provider.GetDBHandle(ledgerID, cat).DeleteAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close implements the function in the interface 'BookKeeperProvider'
 func (provider *provider) Close() {
 	provider.dbProvider.Close()
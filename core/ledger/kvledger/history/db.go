@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	protoutil "github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 )
 
 var logger = flogging.MustGetLogger("history")
@@ -52,6 +53,24 @@ func (p *DBProvider) GetDBHandle(name string) (*DB, error) {
 		nil
 }
 
+// MarkStartingSavepoint records the savepoint for the history database of a ledger that is created from a
+// snapshot. The history is maintained only for the blocks that are committed after the snapshot
+func (p *DBProvider) MarkStartingSavepoint(name string, savepoint *version.Height) error {
+	db := p.leveldbProvider.GetDBHandle(name)
+	if err := db.Put(savePointKey, savepoint.ToBytes(), true); err != nil {
+		return errors.WithMessagef(err, "error while writing the starting savepoint for the history database of channel [%s]", name)
+	}
+	return nil
+}
+
+// Drop drops the history database of a ledger. It is not an error if the history database does not exist
+func (p *DBProvider) Drop(name string) error {
+	if err := p.leveldbProvider.GetDBHandle(name).DeleteAll(); err != nil {
+		return errors.WithMessagef(err, "error while dropping the history database of channel [%s]", name)
+	}
+	return nil
+}
+
 // Close closes the underlying db
 func (p *DBProvider) Close() {
 	p.leveldbProvider.Close()
@@ -123,6 +142,15 @@ func (d *DB) Commit(block *common.Block) error {
 					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
 					dbBatch.Put(dataKey, emptyValue)
 				}
+
+				// add a history record for each hashed write of private data, keyed by the key hash
+				for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+					hashedNs := hashedDataNs(ns, collHashedRWSet.CollectionName)
+					for _, hashedWrite := range collHashedRWSet.HashedRwSet.HashedWrites {
+						dataKey := constructDataKey(hashedNs, string(hashedWrite.KeyHash), blockNo, tranNo)
+						dbBatch.Put(dataKey, emptyValue)
+					}
+				}
 			}
 
 		} else {
@@ -9,11 +9,13 @@ package history
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
@@ -24,6 +26,9 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,6 +103,27 @@ func TestSavepoint(t *testing.T) {
 	assert.Equal(t, uint64(3), blockNum)
 }
 
+func TestMarkStartingSavepoint(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+
+	require.NoError(t, env.testHistoryDBProvider.MarkStartingSavepoint("testLedger", version.NewHeight(25, 30)))
+	db, err := env.testHistoryDBProvider.GetDBHandle("testLedger")
+	require.NoError(t, err)
+	savepoint, err := db.GetLastSavepoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(25, 30), savepoint)
+
+	status, blockNum, err := db.ShouldRecover(25)
+	require.NoError(t, err)
+	require.False(t, status)
+	require.Equal(t, uint64(26), blockNum)
+
+	env.testHistoryDBProvider.Close()
+	err = env.testHistoryDBProvider.MarkStartingSavepoint("testLedger", version.NewHeight(25, 30))
+	require.Contains(t, err.Error(), "error while writing the starting savepoint for the history database of channel [testLedger]")
+}
+
 func TestHistory(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -406,6 +432,230 @@ func TestHistoryWithBlockNumber256(t *testing.T) {
 	testutilVerifyResults(t, qhistory, "ns1", "key", expectedHistoryResults)
 }
 
+func TestHistoryWithOptions(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.Open(ledger1id)
+	require.NoError(t, err)
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	require.NoError(t, store1.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// add 10 blocks, each block has 1 transaction setting state for "ns1" and "key", value is "value<blockNum>"
+	for i := 1; i <= 10; i++ {
+		simulator, err := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		require.NoError(t, err)
+		require.NoError(t, simulator.SetState("ns1", "key", []byte(fmt.Sprintf("value%d", i))))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimResBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		require.NoError(t, store1.AddBlock(block))
+		require.NoError(t, env.testHistoryDB.Commit(block))
+	}
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
+	require.NoError(t, err)
+
+	values := func(from, to int) []string {
+		vals := []string{}
+		if from <= to {
+			for i := from; i <= to; i++ {
+				vals = append(vals, fmt.Sprintf("value%d", i))
+			}
+			return vals
+		}
+		for i := from; i >= to; i-- {
+			vals = append(vals, fmt.Sprintf("value%d", i))
+		}
+		return vals
+	}
+
+	t.Run("zero-options", func(t *testing.T) {
+		kmods, bookmark := testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{})
+		require.Equal(t, values(10, 1), kmodValues(kmods))
+		require.Equal(t, "", bookmark)
+	})
+
+	t.Run("block-range", func(t *testing.T) {
+		kmods, _ := testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 6, HasEndBlock: true})
+		require.Equal(t, values(6, 3), kmodValues(kmods))
+
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 6, HasEndBlock: true, Ascending: true})
+		require.Equal(t, values(3, 6), kmodValues(kmods))
+
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 8})
+		require.Equal(t, values(10, 8), kmodValues(kmods))
+
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{EndBlock: 2, HasEndBlock: true, Ascending: true})
+		require.Equal(t, values(1, 2), kmodValues(kmods))
+
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 11})
+		require.Empty(t, kmods)
+
+		// the genesis block does not carry the key, so the block zero as the end block selects nothing
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{EndBlock: 0, HasEndBlock: true})
+		require.Empty(t, kmods)
+
+		// the end block is ignored unless it is marked as set
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{EndBlock: 2})
+		require.Equal(t, values(10, 1), kmodValues(kmods))
+
+		kmods, _ = testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 9, EndBlock: math.MaxUint64, HasEndBlock: true})
+		require.Equal(t, values(10, 9), kmodValues(kmods))
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		for _, ascending := range []bool{true, false} {
+			options := &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 9, HasEndBlock: true, Ascending: ascending, PageSize: 3}
+			retrievedVals := []string{}
+			for numPages := 1; ; numPages++ {
+				kmods, bookmark := testutilQueryWithOptions(t, qhistory, "ns1", "key", options)
+				require.True(t, len(kmods) <= 3)
+				retrievedVals = append(retrievedVals, kmodValues(kmods)...)
+				if bookmark == "" {
+					require.Equal(t, 3, numPages)
+					break
+				}
+				options.Bookmark = bookmark
+			}
+			if ascending {
+				require.Equal(t, values(2, 9), retrievedVals)
+			} else {
+				require.Equal(t, values(9, 2), retrievedVals)
+			}
+		}
+	})
+
+	t.Run("time-bounds", func(t *testing.T) {
+		allKmods, _ := testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{Ascending: true})
+		startTime, err := ptypes.Timestamp(allKmods[2].Timestamp)
+		require.NoError(t, err)
+		endTime, err := ptypes.Timestamp(allKmods[6].Timestamp)
+		require.NoError(t, err)
+
+		expectedVals := []string{}
+		for _, kmod := range allKmods {
+			txTime, err := ptypes.Timestamp(kmod.Timestamp)
+			require.NoError(t, err)
+			if !txTime.Before(startTime) && txTime.Before(endTime) {
+				expectedVals = append(expectedVals, string(kmod.Value))
+			}
+		}
+		require.Contains(t, expectedVals, "value3")
+		require.NotContains(t, expectedVals, "value7")
+
+		kmods, _ := testutilQueryWithOptions(t, qhistory, "ns1", "key", &ledger.HistoryQueryOptions{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Ascending: true,
+		})
+		require.Equal(t, expectedVals, kmodValues(kmods))
+	})
+
+	t.Run("invalid-options", func(t *testing.T) {
+		_, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{StartBlock: 5, EndBlock: 4, HasEndBlock: true})
+		require.EqualError(t, err, "start block [5] is greater than end block [4]")
+
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{PageSize: -1})
+		require.EqualError(t, err, "page size [-1] cannot be negative")
+
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{Bookmark: "not-hex"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid bookmark [not-hex]")
+
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key", &ledger.HistoryQueryOptions{Bookmark: "ff"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid bookmark [ff]")
+	})
+}
+
+func TestPrivateDataHistoryForKeyHash(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.Open(ledger1id)
+	require.NoError(t, err)
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	require.NoError(t, store1.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb))
+
+	commitBlock := func(populateRWSet func(b *rwsetutil.RWSetBuilder)) {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		populateRWSet(rwsetBuilder)
+		simRes, err := rwsetBuilder.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimResBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		require.NoError(t, store1.AddBlock(block))
+		require.NoError(t, env.testHistoryDB.Commit(block))
+	}
+
+	// block1 writes the private key and a public key with the same name
+	commitBlock(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToWriteSet("ns1", "key1", []byte("public-value1"))
+		b.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("value1"))
+	})
+	// block2 updates the private key and writes the same key in another collection
+	commitBlock(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("value2"))
+		b.AddToPvtAndHashedWriteSet("ns1", "coll2", "key1", []byte("value3"))
+	})
+	// block3 deletes the private key
+	commitBlock(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", nil)
+	})
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
+	require.NoError(t, err)
+
+	keyHash := util.ComputeStringHash("key1")
+	retrieveKmods := func(ns, coll string, keyHash []byte) []*queryresult.KeyModification {
+		itr, err := qhistory.GetPrivateDataHistoryForKeyHash(ns, coll, keyHash)
+		require.NoError(t, err)
+		defer itr.Close()
+		kmods := []*queryresult.KeyModification{}
+		for {
+			kmod, err := itr.Next()
+			require.NoError(t, err)
+			if kmod == nil {
+				return kmods
+			}
+			kmods = append(kmods, kmod.(*queryresult.KeyModification))
+		}
+	}
+
+	kmods := retrieveKmods("ns1", "coll1", keyHash)
+	require.Len(t, kmods, 3)
+	require.True(t, kmods[0].IsDelete)
+	require.Nil(t, kmods[0].Value)
+	require.False(t, kmods[1].IsDelete)
+	require.Equal(t, util.ComputeHash([]byte("value2")), kmods[1].Value)
+	require.False(t, kmods[2].IsDelete)
+	require.Equal(t, util.ComputeHash([]byte("value1")), kmods[2].Value)
+
+	kmods = retrieveKmods("ns1", "coll2", keyHash)
+	require.Len(t, kmods, 1)
+	require.Equal(t, util.ComputeHash([]byte("value3")), kmods[0].Value)
+
+	require.Empty(t, retrieveKmods("ns1", "coll3", keyHash))
+	require.Empty(t, retrieveKmods("ns2", "coll1", keyHash))
+	require.Empty(t, retrieveKmods("ns1", "coll1", util.ComputeStringHash("key2")))
+
+	// the hashed writes do not appear in the history of the public key
+	testutilVerifyResults(t, qhistory, "ns1", "key1", []string{"public-value1"})
+}
+
 func TestName(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -502,3 +752,26 @@ func testutilCheckKeyNotInRange(t *testing.T, hqe ledger.HistoryQueryExecutor, n
 		}
 	}
 }
+
+func testutilQueryWithOptions(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, key string, options *ledger.HistoryQueryOptions) ([]*queryresult.KeyModification, string) {
+	itr, err := hqe.GetHistoryForKeyWithOptions(ns, key, options)
+	require.NoError(t, err)
+	kmods := []*queryresult.KeyModification{}
+	for {
+		kmod, err := itr.Next()
+		require.NoError(t, err)
+		if kmod == nil {
+			break
+		}
+		kmods = append(kmods, kmod.(*queryresult.KeyModification))
+	}
+	return kmods, itr.GetBookmarkAndClose()
+}
+
+func kmodValues(kmods []*queryresult.KeyModification) []string {
+	vals := []string{}
+	for _, kmod := range kmods {
+		vals = append(vals, string(kmod.Value))
+	}
+	return vals
+}
@@ -8,6 +8,7 @@ package history
 
 import (
 	"bytes"
+	"encoding/hex"
 
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/pkg/errors"
@@ -39,6 +40,13 @@ func constructDataKey(ns string, key string, blocknum uint64, trannum uint64) da
 	return dataKey(k)
 }
 
+// hashedDataNs returns the namespace under which the history of the key hashes of a collection is indexed.
+// This follows the convention of the statedb for the hashed data of a collection and cannot collide with
+// a chaincode namespace as chaincode names do not contain '$'
+func hashedDataNs(ns, coll string) string {
+	return ns + "$$h" + coll
+}
+
 // constructRangescanKeys returns start and endKey for performing a range scan
 // that covers all the keys for <ns, key>.
 // startKey = namespace~len(key)~key~
@@ -74,3 +82,31 @@ func (r *rangeScan) decodeBlockNumTranNum(dataKey dataKey) (uint64, uint64, erro
 	}
 	return blockNum, tranNum, nil
 }
+
+// blockNumBoundKey returns the key namespace~len(key)~key~blocknum, which sorts before all
+// the keys for <ns, key> at block blocknum or higher and after all the keys at a lower block
+func (r *rangeScan) blockNumBoundKey(blockNum uint64) []byte {
+	k := make([]byte, 0, len(r.startKey)+9)
+	k = append(k, r.startKey...)
+	return append(k, util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+// encodeBookmark returns the blocknum~trannum suffix of the dataKey in hex as an opaque bookmark
+func (r *rangeScan) encodeBookmark(dataKey dataKey) string {
+	return hex.EncodeToString(bytes.TrimPrefix(dataKey, r.startKey))
+}
+
+// decodeBookmark returns the dataKey for <ns, key> that the bookmark points to
+func (r *rangeScan) decodeBookmark(bookmark string) (dataKey, error) {
+	blockNumTranNumBytes, err := hex.DecodeString(bookmark)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid bookmark [%s]", bookmark)
+	}
+	k := make([]byte, 0, len(r.startKey)+len(blockNumTranNumBytes)+1)
+	k = append(k, r.startKey...)
+	k = append(k, blockNumTranNumBytes...)
+	if _, _, err := r.decodeBlockNumTranNum(k); err != nil {
+		return nil, errors.WithMessagef(err, "invalid bookmark [%s]", bookmark)
+	}
+	return k, nil
+}
@@ -7,11 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package history
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	protoutil "github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -26,122 +34,292 @@ type QueryExecutor struct {
 
 // GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
 func (q *QueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
-	rangeScan := constructRangeScan(namespace, key)
-	dbItr, err := q.levelDB.GetIterator(rangeScan.startKey, rangeScan.endKey)
+	return q.GetHistoryForKeyWithOptions(namespace, key, &ledger.HistoryQueryOptions{})
+}
+
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+func (q *QueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string, options *ledger.HistoryQueryOptions) (commonledger.QueryResultsIterator, error) {
+	return q.newHistoryScanner(
+		constructRangeScan(namespace, key),
+		namespace,
+		key,
+		options,
+		func(tranEnvelope *common.Envelope) (commonledger.QueryResult, error) {
+			return getKeyModificationFromTran(tranEnvelope, namespace, key)
+		},
+	)
+}
+
+// GetPrivateDataHistoryForKeyHash implements method in interface `ledger.HistoryQueryExecutor`
+func (q *QueryExecutor) GetPrivateDataHistoryForKeyHash(namespace, collection string, keyHash []byte) (commonledger.ResultsIterator, error) {
+	return q.newHistoryScanner(
+		constructRangeScan(hashedDataNs(namespace, collection), string(keyHash)),
+		namespace,
+		fmt.Sprintf("%s:%x", collection, keyHash),
+		&ledger.HistoryQueryOptions{},
+		func(tranEnvelope *common.Envelope) (commonledger.QueryResult, error) {
+			return getKeyHashModificationFromTran(tranEnvelope, namespace, collection, keyHash)
+		},
+	)
+}
+
+func (q *QueryExecutor) newHistoryScanner(
+	rangeScan *rangeScan,
+	namespace string,
+	key string,
+	options *ledger.HistoryQueryOptions,
+	getModificationFromTran func(*common.Envelope) (commonledger.QueryResult, error),
+) (*historyScanner, error) {
+	if options.HasEndBlock && options.StartBlock > options.EndBlock {
+		return nil, errors.Errorf("start block [%d] is greater than end block [%d]", options.StartBlock, options.EndBlock)
+	}
+	if options.PageSize < 0 {
+		return nil, errors.Errorf("page size [%d] cannot be negative", options.PageSize)
+	}
+	startKey, endKey := rangeScan.startKey, rangeScan.endKey
+	if options.StartBlock != 0 {
+		startKey = rangeScan.blockNumBoundKey(options.StartBlock)
+	}
+	if options.HasEndBlock && options.EndBlock != math.MaxUint64 {
+		endKey = rangeScan.blockNumBoundKey(options.EndBlock + 1)
+	}
+	if options.Bookmark != "" {
+		bookmarkKey, err := rangeScan.decodeBookmark(options.Bookmark)
+		if err != nil {
+			return nil, err
+		}
+		// the bookmark points to the next result to be returned and hence it is included in the scan
+		if options.Ascending && bytes.Compare(bookmarkKey, startKey) > 0 {
+			startKey = bookmarkKey
+		}
+		if !options.Ascending {
+			if k := append(bookmarkKey, 0x00); bytes.Compare(k, endKey) < 0 {
+				endKey = k
+			}
+		}
+	}
+
+	dbItr, err := q.levelDB.GetIterator(startKey, endKey)
 	if err != nil {
 		return nil, err
 	}
 
-	// By default, dbItr is in the orderer of oldest to newest and its cursor is at the beginning of the entries.
-	// Need to call Last() and Next() to move the cursor to the end of the entries so that we can iterate
-	// the entries in the order of newest to oldest.
-	if dbItr.Last() {
-		dbItr.Next()
+	if !options.Ascending {
+		// By default, dbItr is in the orderer of oldest to newest and its cursor is at the beginning of the entries.
+		// Need to call Last() and Next() to move the cursor to the end of the entries so that we can iterate
+		// the entries in the order of newest to oldest.
+		if dbItr.Last() {
+			dbItr.Next()
+		}
 	}
-	return &historyScanner{rangeScan, namespace, key, dbItr, q.blockStore}, nil
+	return &historyScanner{
+		rangeScan:               rangeScan,
+		namespace:               namespace,
+		key:                     key,
+		dbItr:                   dbItr,
+		blockStore:              q.blockStore,
+		getModificationFromTran: getModificationFromTran,
+		ascending:               options.Ascending,
+		startTime:               options.StartTime,
+		endTime:                 options.EndTime,
+		pageSize:                options.PageSize,
+	}, nil
 }
 
 //historyScanner implements ResultsIterator for iterating through history results
 type historyScanner struct {
-	rangeScan  *rangeScan
-	namespace  string
-	key        string
-	dbItr      iterator.Iterator
-	blockStore *blkstorage.BlockStore
+	rangeScan               *rangeScan
+	namespace               string
+	key                     string
+	dbItr                   iterator.Iterator
+	blockStore              *blkstorage.BlockStore
+	getModificationFromTran func(*common.Envelope) (commonledger.QueryResult, error)
+	ascending               bool
+	startTime               time.Time
+	endTime                 time.Time
+	pageSize                int32
+	numResults              int32
 }
 
-// Next iterates to the next key, in the order of newest to oldest, from history scanner.
-// It decodes blockNumTranNumBytes to get blockNum and tranNum,
+// Next iterates to the next key, in the order of newest to oldest (or oldest to newest, for an ascending scan), from
+// history scanner. It decodes blockNumTranNumBytes to get blockNum and tranNum,
 // loads the block:tran from block storage, finds the key and returns the result.
+// The results whose transaction timestamp does not fall within the time bounds of the scan are skipped
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
-	// call Prev because history query result is returned from newest to oldest
-	if !scanner.dbItr.Prev() {
-		return nil, nil
-	}
+	for {
+		if scanner.pageSize > 0 && scanner.numResults >= scanner.pageSize {
+			return nil, nil
+		}
+		if !scanner.move() {
+			return nil, nil
+		}
 
-	historyKey := scanner.dbItr.Key()
-	blockNum, tranNum, err := scanner.rangeScan.decodeBlockNumTranNum(historyKey)
-	if err != nil {
-		return nil, err
+		historyKey := scanner.dbItr.Key()
+		blockNum, tranNum, err := scanner.rangeScan.decodeBlockNumTranNum(historyKey)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
+			scanner.namespace, scanner.key, blockNum, tranNum)
+
+		// Get the transaction from block storage that is associated with this history record
+		tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
+		queryResult, err := scanner.getModificationFromTran(tranEnvelope)
+		if err != nil {
+			return nil, err
+		}
+		if queryResult == nil {
+			// should not happen, but make sure there is inconsistency between historydb and statedb
+			logger.Errorf("No namespace or key is found for namespace %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.key, blockNum, tranNum)
+			return nil, errors.Errorf("no namespace or key is found for namespace %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.key, blockNum, tranNum)
+		}
+		keyModification := queryResult.(*queryresult.KeyModification)
+		if !scanner.withinTimeBounds(keyModification) {
+			continue
+		}
+		logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s",
+			scanner.namespace, scanner.key, keyModification.TxId)
+		scanner.numResults++
+		return queryResult, nil
 	}
-	logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
-		scanner.namespace, scanner.key, blockNum, tranNum)
+}
 
-	// Get the transaction from block storage that is associated with this history record
-	tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
-	if err != nil {
-		return nil, err
+// move moves the cursor to the next entry in the order of the scan
+func (scanner *historyScanner) move() bool {
+	if scanner.ascending {
+		return scanner.dbItr.Next()
 	}
+	// call Prev because history query result is returned from newest to oldest
+	return scanner.dbItr.Prev()
+}
 
-	// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
-	queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+func (scanner *historyScanner) withinTimeBounds(keyModification *queryresult.KeyModification) bool {
+	if scanner.startTime.IsZero() && scanner.endTime.IsZero() {
+		return true
+	}
+	txTime, err := ptypes.Timestamp(keyModification.Timestamp)
 	if err != nil {
-		return nil, err
+		logger.Warningf("Skipping the transaction %s with invalid timestamp in the time bounded history query for namespace %s and key %s: %s",
+			keyModification.TxId, scanner.namespace, scanner.key, err)
+		return false
+	}
+	if !scanner.startTime.IsZero() && txTime.Before(scanner.startTime) {
+		return false
 	}
-	if queryResult == nil {
-		// should not happen, but make sure there is inconsistency between historydb and statedb
-		logger.Errorf("No namespace or key is found for namespace %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.key, blockNum, tranNum)
-		return nil, errors.Errorf("no namespace or key is found for namespace %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.key, blockNum, tranNum)
+	if !scanner.endTime.IsZero() && !txTime.Before(scanner.endTime) {
+		return false
 	}
-	logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s",
-		scanner.namespace, scanner.key, queryResult.(*queryresult.KeyModification).TxId)
-	return queryResult, nil
+	return true
 }
 
 func (scanner *historyScanner) Close() {
 	scanner.dbItr.Release()
 }
 
+// GetBookmarkAndClose returns a bookmark that points to the next entry in the scan and releases the iterator.
+// An empty bookmark is returned if the scan is exhausted
+func (scanner *historyScanner) GetBookmarkAndClose() string {
+	bookmark := ""
+	if scanner.move() {
+		bookmark = scanner.rangeScan.encodeBookmark(scanner.dbItr.Key())
+	}
+	scanner.Close()
+	return bookmark
+}
+
 // getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key
 func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
 	logger.Debugf("Entering getKeyModificationFromTran()\n", namespace, key)
 
+	txID, timestamp, txRWSet, err := getTxRWSetFromTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	// look for the namespace and key by looping through the transaction's ReadWriteSets
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace == namespace {
+			// got the correct namespace, now find the key write
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				if kvWrite.Key == key {
+					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
+						Timestamp: timestamp, IsDelete: rwsetutil.IsKVWriteDelete(kvWrite)}, nil
+				}
+			} // end keys loop
+			logger.Debugf("key [%s] not found in namespace [%s]'s writeset", key, namespace)
+			return nil, nil
+		} // end if
+	} //end namespaces loop
+	logger.Debugf("namespace [%s] not found in transaction's ReadWriteSets", namespace)
+	return nil, nil
+}
+
+// getKeyHashModificationFromTran inspects a transaction for hashed writes to a given key hash of a collection.
+// The value in the returned KeyModification is the hash of the private value
+func getKeyHashModificationFromTran(tranEnvelope *common.Envelope, namespace, collection string, keyHash []byte) (commonledger.QueryResult, error) {
+	txID, timestamp, txRWSet, err := getTxRWSetFromTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace {
+			continue
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashedRWSet.CollectionName != collection {
+				continue
+			}
+			for _, hashedWrite := range collHashedRWSet.HashedRwSet.HashedWrites {
+				if bytes.Equal(hashedWrite.KeyHash, keyHash) {
+					return &queryresult.KeyModification{TxId: txID, Value: hashedWrite.ValueHash,
+						Timestamp: timestamp, IsDelete: hashedWrite.IsDelete}, nil
+				}
+			}
+			logger.Debugf("key hash [%x] not found in the hashed writeset of collection [%s] in namespace [%s]", keyHash, collection, namespace)
+			return nil, nil
+		}
+		logger.Debugf("collection [%s] not found in namespace [%s]'s hashed writesets", collection, namespace)
+		return nil, nil
+	}
+	logger.Debugf("namespace [%s] not found in transaction's ReadWriteSets", namespace)
+	return nil, nil
+}
+
+// getTxRWSetFromTran returns the txID, the timestamp, and the read-write set of a transaction
+func getTxRWSetFromTran(tranEnvelope *common.Envelope) (string, *timestamp.Timestamp, *rwsetutil.TxRwSet, error) {
 	// extract action from the envelope
 	payload, err := protoutil.UnmarshalPayload(tranEnvelope.Payload)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
 	tx, err := protoutil.UnmarshalTransaction(payload.Data)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
 	_, respPayload, err := protoutil.GetPayloads(tx.Actions[0])
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
 	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
-	txID := chdr.TxId
-	timestamp := chdr.Timestamp
-
 	txRWSet := &rwsetutil.TxRwSet{}
 
 	// Get the Result from the Action and then Unmarshal
 	// it into a TxReadWriteSet using custom unmarshalling
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
-
-	// look for the namespace and key by looping through the transaction's ReadWriteSets
-	for _, nsRWSet := range txRWSet.NsRwSets {
-		if nsRWSet.NameSpace == namespace {
-			// got the correct namespace, now find the key write
-			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
-				if kvWrite.Key == key {
-					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
-						Timestamp: timestamp, IsDelete: rwsetutil.IsKVWriteDelete(kvWrite)}, nil
-				}
-			} // end keys loop
-			logger.Debugf("key [%s] not found in namespace [%s]'s writeset", key, namespace)
-			return nil, nil
-		} // end if
-	} //end namespaces loop
-	logger.Debugf("namespace [%s] not found in transaction's ReadWriteSets", namespace)
-	return nil, nil
+	return chdr.TxId, chdr.Timestamp, txRWSet, nil
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
+	"encoding/hex"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -56,6 +57,17 @@ type kvLedger struct {
 	commitHash             []byte
 	hashProvider           ledger.HashProvider
 	snapshotsConfig        *ledger.SnapshotsConfig
+	pruneAtSnapshot        bool
+	commitPipelineEnabled  bool
+	snapshotMgr            *snapshotMgr
+	stateCheckpointMgr     *stateCheckpointMgr
+	// bootSnapshotMetadata is the metadata of the snapshot from which the ledger was created.
+	// It is nil for a ledger that was created from a genesis block
+	bootSnapshotMetadata *snapshotMetadata
+	// ccInfoProvider and membershipInfoProvider are used to record the private data
+	// of the blocks imported from a block archive as missing
+	ccInfoProvider         ledger.DeployedChaincodeInfoProvider
+	membershipInfoProvider ledger.MembershipInfoProvider
 	// isPvtDataStoreAheadOfBlockStore is read during missing pvtData
 	// reconciliation and may be updated during a regular block commit.
 	// Hence, we use atomic value to ensure consistent read.
@@ -72,24 +84,46 @@ type lgrInitializer struct {
 	stateListeners           []ledger.StateListener
 	bookkeeperProvider       bookkeeping.Provider
 	ccInfoProvider           ledger.DeployedChaincodeInfoProvider
+	membershipInfoProvider   ledger.MembershipInfoProvider
 	ccLifecycleEventProvider ledger.ChaincodeLifecycleEventProvider
 	stats                    *ledgerStats
 	customTxProcessors       map[common.HeaderType]ledger.CustomTxProcessor
 	hashProvider             ledger.HashProvider
 	snapshotsConfig          *ledger.SnapshotsConfig
+	stateCheckpointsConfig   *ledger.StateCheckpointsConfig
+	blockRetentionConfig     *ledger.BlockRetentionConfig
+	commitPipelineConfig     *ledger.CommitPipelineConfig
+	bootSnapshotMetadata     *snapshotMetadata
 }
 
 func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 	ledgerID := initializer.ledgerID
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 	l := &kvLedger{
-		ledgerID:        ledgerID,
-		blockStore:      initializer.blockStore,
-		pvtdataStore:    initializer.pvtdataStore,
-		historyDB:       initializer.historyDB,
-		hashProvider:    initializer.hashProvider,
-		snapshotsConfig: initializer.snapshotsConfig,
-		blockAPIsRWLock: &sync.RWMutex{},
+		ledgerID:               ledgerID,
+		blockStore:             initializer.blockStore,
+		pvtdataStore:           initializer.pvtdataStore,
+		historyDB:              initializer.historyDB,
+		hashProvider:           initializer.hashProvider,
+		snapshotsConfig:        initializer.snapshotsConfig,
+		pruneAtSnapshot:        initializer.blockRetentionConfig != nil && initializer.blockRetentionConfig.PruneAtSnapshot,
+		commitPipelineEnabled:  initializer.commitPipelineConfig != nil && initializer.commitPipelineConfig.Enabled,
+		blockAPIsRWLock:        &sync.RWMutex{},
+		bootSnapshotMetadata:   initializer.bootSnapshotMetadata,
+		ccInfoProvider:         initializer.ccInfoProvider,
+		membershipInfoProvider: initializer.membershipInfoProvider,
+		snapshotMgr: &snapshotMgr{
+			commitLock: &sync.Mutex{},
+			snapshotRequestBookkeeper: newSnapshotRequestBookkeeper(
+				initializer.bookkeeperProvider.GetDBHandle(ledgerID, bookkeeping.SnapshotRequest),
+			),
+		},
+		stateCheckpointMgr: newStateCheckpointMgr(
+			ledgerID,
+			initializer.stateCheckpointsConfig,
+			initializer.stateDB,
+			initializer.bookkeeperProvider.GetDBHandle(ledgerID, bookkeeping.StateCheckpoint),
+		),
 	}
 
 	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(&collectionInfoRetriever{ledgerID, l, initializer.ccInfoProvider})
@@ -115,6 +149,9 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		CustomTxProcessors:  initializer.customTxProcessors,
 		HashFunc:            rwsetHashFunc,
 	}
+	if l.stateCheckpointMgr.enabled() {
+		txmgrInitializer.UpdatesObserver = l.stateCheckpointMgr
+	}
 	if err := l.initTxMgr(txmgrInitializer); err != nil {
 		return nil, err
 	}
@@ -145,6 +182,12 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		initializer.ccLifecycleEventProvider.RegisterListener(ledgerID, &ccEventListenerAdaptor{ccEventListener})
 	}
 
+	// The state commitment is initialized at the savepoint of the state DB so that it is
+	// rolled forward with the blocks that are recommitted during the recovery
+	if err := l.stateCheckpointMgr.init(); err != nil {
+		return nil, err
+	}
+
 	//Recover both state DB and history DB if they are out of sync with block storage
 	if err := l.recoverDBs(); err != nil {
 		return nil, err
@@ -152,6 +195,9 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 	l.configHistoryRetriever = initializer.configHistoryMgr.GetRetriever(ledgerID, l)
 
 	l.stats = initializer.stats
+	if err := l.resumeSnapshotGeneration(); err != nil {
+		return nil, err
+	}
 	return l, nil
 }
 
@@ -187,6 +233,18 @@ func (l *kvLedger) lastPersistedCommitHash() ([]byte, error) {
 		return nil, nil
 	}
 
+	if l.bootSnapshotMetadata != nil && l.bootSnapshotMetadata.ChannelHeight == bcInfo.Height {
+		logger.Debugf("Ledger is starting first time from a snapshot. Retrieving the currentCommitHash from the snapshot metadata")
+		commitHash, err := hex.DecodeString(l.bootSnapshotMetadata.LastBlockCommitHashInHex)
+		if err != nil {
+			return nil, errors.Wrap(err, "error while decoding the last block commit hash from the snapshot metadata")
+		}
+		if len(commitHash) == 0 {
+			return nil, nil
+		}
+		return commitHash, nil
+	}
+
 	logger.Debugf("Fetching block [%d] to retrieve the currentCommitHash", bcInfo.Height-1)
 	block, err := l.GetBlockByNumber(bcInfo.Height - 1)
 	if err != nil {
@@ -331,13 +389,18 @@ func (l *kvLedger) filterYetToCommitBlocks(blocksPvtData map[uint64][]*ledger.Tx
 	return nil
 }
 
-//recommitLostBlocks retrieves blocks in specified range and commit the write set to either
-//state DB or history DB or both
+// recommitLostBlocks retrieves blocks in specified range and commit the write set to either
+// state DB or history DB or both
 func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
 	logger.Infof("Recommitting lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
 	var err error
 	var blockAndPvtdata *ledger.BlockAndPvtData
 	for blockNumber := firstBlockNum; blockNumber <= lastBlockNum; blockNumber++ {
+		// The blocks imported from a block archive are not in the pvtdata store yet. As the
+		// state DB is never ahead of the pvtdata store, they are all recommitted here
+		if err = l.commitImportedBlockToPvtdataStore(blockNumber); err != nil {
+			return err
+		}
 		if blockAndPvtdata, err = l.GetPvtDataAndBlockByNum(blockNumber, nil); err != nil {
 			return err
 		}
@@ -411,6 +474,14 @@ func (l *kvLedger) GetBlockByTxID(txID string) (*common.Block, error) {
 	return block, err
 }
 
+// GetBlockNumsByExtensionKey returns the numbers of the blocks whose extension carries
+// an entry of the given type URL that is indexed under the given key
+func (l *kvLedger) GetBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error) {
+	l.blockAPIsRWLock.RLock()
+	defer l.blockAPIsRWLock.RUnlock()
+	return l.blockStore.RetrieveBlockNumsByExtensionKey(typeURL, key)
+}
+
 func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
 	l.blockAPIsRWLock.RLock()
 	defer l.blockAPIsRWLock.RUnlock()
@@ -441,8 +512,23 @@ func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error
 	return nil, nil
 }
 
-// CommitLegacy commits the block and the corresponding pvt data in an atomic operation
+// CommitLegacy commits the block and the corresponding pvt data in an atomic operation.
+// If a snapshot is requested for the block, the snapshot generation is started once the
+// block is committed and the commit of the next block waits for the generation to finish
 func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	l.snapshotMgr.commitLock.Lock()
+	if err := l.commit(pvtdataAndBlock, commitOpts); err != nil {
+		l.snapshotMgr.commitLock.Unlock()
+		return err
+	}
+	started, err := l.processSnapshotRequest(pvtdataAndBlock.Block.Header.Number)
+	if !started {
+		l.snapshotMgr.commitLock.Unlock()
+	}
+	return err
+}
+
+func (l *kvLedger) commit(pvtdataAndBlock *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
 	var err error
 	block := pvtdataAndBlock.Block
 	blockNo := pvtdataAndBlock.Block.Header.Number
@@ -493,36 +579,47 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 	elapsedBlockstorageAndPvtdataCommit := time.Since(startBlockstorageAndPvtdataCommit)
 
 	startCommitState := time.Now()
+	commitHash := l.commitHash
 	logger.Debugf("[%s] Committing block [%d] transactions to state database", l.ledgerID, blockNo)
-	if err = l.txmgr.Commit(); err != nil {
-		panic(errors.WithMessage(err, "error during commit to txmgr"))
-	}
-	elapsedCommitState := time.Since(startCommitState)
-
-	// History database could be written in parallel with state and/or async as a future optimization,
-	// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
-	if l.historyDB != nil {
-		logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, blockNo)
-		if err := l.historyDB.Commit(block); err != nil {
-			panic(errors.WithMessage(err, "Error during commit to history db"))
+	stateCommitDone := func(err error) {
+		if err != nil {
+			panic(errors.WithMessage(err, "error during commit to txmgr"))
+		}
+		elapsedCommitState := time.Since(startCommitState)
+
+		// History database could be written in parallel with state and/or async as a future optimization,
+		// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
+		if l.historyDB != nil {
+			logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, blockNo)
+			if err := l.historyDB.Commit(block); err != nil {
+				panic(errors.WithMessage(err, "Error during commit to history db"))
+			}
 		}
-	}
 
-	logger.Infof("[%s] Committed block [%d] with %d transaction(s) in %dms (state_validation=%dms block_and_pvtdata_commit=%dms state_commit=%dms)"+
-		" commitHash=[%x]",
-		l.ledgerID, block.Header.Number, len(block.Data.Data),
-		time.Since(startBlockProcessing)/time.Millisecond,
-		elapsedBlockProcessing/time.Millisecond,
-		elapsedBlockstorageAndPvtdataCommit/time.Millisecond,
-		elapsedCommitState/time.Millisecond,
-		l.commitHash,
-	)
-	l.updateBlockStats(
-		elapsedBlockProcessing,
-		elapsedBlockstorageAndPvtdataCommit,
-		elapsedCommitState,
-		txstatsInfo,
-	)
+		logger.Infof("[%s] Committed block [%d] with %d transaction(s) in %dms (state_validation=%dms block_and_pvtdata_commit=%dms state_commit=%dms)"+
+			" commitHash=[%x]",
+			l.ledgerID, block.Header.Number, len(block.Data.Data),
+			time.Since(startBlockProcessing)/time.Millisecond,
+			elapsedBlockProcessing/time.Millisecond,
+			elapsedBlockstorageAndPvtdataCommit/time.Millisecond,
+			elapsedCommitState/time.Millisecond,
+			commitHash,
+		)
+		l.updateBlockStats(
+			elapsedBlockProcessing,
+			elapsedBlockstorageAndPvtdataCommit,
+			elapsedCommitState,
+			txstatsInfo,
+		)
+	}
+
+	if !l.commitPipelineEnabled {
+		stateCommitDone(l.txmgr.Commit())
+		return nil
+	}
+	// The block is already in the block store and hence, if the peer stops before the background commit finishes,
+	// the state and history databases are brought in sync with the block store during the recovery
+	l.txmgr.StartCommit(stateCommitDone)
 	return nil
 }
 
@@ -654,9 +751,12 @@ func (l *kvLedger) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilte
 // DoesPvtDataInfoExist returns true when
 // (1) the ledger has pvtdata associated with the given block number (or)
 // (2) a few or all pvtdata associated with the given block number is missing but the
-//     missing info is recorded in the ledger (or)
+//
+//	missing info is recorded in the ledger (or)
+//
 // (3) the block is committed but it does not contain even a single
-//     transaction with pvtData.
+//
+//	transaction with pvtData.
 func (l *kvLedger) DoesPvtDataInfoExist(blockNum uint64) (bool, error) {
 	pvtStoreHt, err := l.pvtdataStore.LastCommittedBlockHeight()
 	if err != nil {
@@ -673,6 +773,10 @@ func (l *kvLedger) CommitPvtDataOfOldBlocks(reconciledPvtdata []*ledger.Reconcil
 	logger.Debugf("[%s:] Comparing pvtData of [%d] old blocks against the hashes in transaction's rwset to find valid and invalid data",
 		l.ledgerID, len(reconciledPvtdata))
 
+	// the state is not updated while a snapshot is being generated
+	l.snapshotMgr.commitLock.Lock()
+	defer l.snapshotMgr.commitLock.Unlock()
+
 	hashVerifiedPvtData, hashMismatches, err := constructValidAndInvalidPvtData(reconciledPvtdata, l.blockStore)
 	if err != nil {
 		return nil, err
@@ -719,6 +823,10 @@ func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, err
 
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
+	l.snapshotMgr.generationWG.Wait()
+	if err := l.txmgr.WaitForPendingCommit(); err != nil {
+		logger.Errorw("Failed to commit block to state database", "channelID", l.ledgerID, "error", err)
+	}
 	l.blockStore.Shutdown()
 	l.txmgr.Shutdown()
 }
@@ -8,7 +8,9 @@ package kvledger
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
+	"math"
 	"os"
 	"path"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/msgs"
@@ -129,6 +132,9 @@ func NewProvider(initializer *ledger.Initializer) (pr *Provider, e error) {
 	}
 	p.initLedgerStatistics()
 	p.recoverUnderConstructionLedger()
+	if err := p.deleteUnderConstructionLedgers(); err != nil {
+		return nil, err
+	}
 	if err := p.initSnapshotDir(); err != nil {
 		return nil, err
 	}
@@ -145,13 +151,13 @@ func (p *Provider) initLedgerIDInventory() error {
 }
 
 func (p *Provider) initBlockStoreProvider() error {
-	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	blkStoreConf, err := blockStoreConf(p.initializer.Config)
+	if err != nil {
+		return err
+	}
 	blkStoreProvider, err := blkstorage.NewProvider(
-		blkstorage.NewConf(
-			BlockStorePath(p.initializer.Config.RootFSPath),
-			maxBlockFileSize,
-		),
-		indexConfig,
+		blkStoreConf,
+		blockStoreIndexConfig(p.initializer.Config.ExtensionIndexConfig),
 		p.initializer.MetricsProvider,
 	)
 	if err != nil {
@@ -161,6 +167,52 @@ func (p *Provider) initBlockStoreProvider() error {
 	return nil
 }
 
+// blockStoreConf returns the configuration of the block store, which prunes the block files
+// as per the block retention config, if any, and compresses the blocks as per the block compression config
+func blockStoreConf(config *ledger.Config) (*blkstorage.Conf, error) {
+	codec := blkstorage.NoCompression
+	if config.BlockCompressionConfig != nil {
+		var err error
+		if codec, err = blkstorage.ParseCompressionCodec(config.BlockCompressionConfig.Codec); err != nil {
+			return nil, err
+		}
+	}
+	retentionConfig := config.BlockRetentionConfig
+	if retentionConfig == nil || (retentionConfig.RetainBlocks == 0 && !retentionConfig.PruneAtSnapshot) {
+		return blkstorage.NewConf(BlockStorePath(config.RootFSPath), maxBlockFileSize).WithCompression(codec), nil
+	}
+	if retentionConfig.FetchArchived && retentionConfig.ArchiveDir == "" {
+		return nil, errors.New("the archive dir is required for fetching the pruned blocks")
+	}
+	retentionPolicy := &blkstorage.RetentionPolicy{
+		RetainBlocks:  retentionConfig.RetainBlocks,
+		FetchArchived: retentionConfig.FetchArchived,
+	}
+	if retentionConfig.ArchiveDir != "" {
+		sink, err := blkstorage.NewDirArchiveSink(retentionConfig.ArchiveDir)
+		if err != nil {
+			return nil, err
+		}
+		retentionPolicy.Sink = sink
+	}
+	return blkstorage.NewConfWithRetentionPolicy(BlockStorePath(config.RootFSPath), maxBlockFileSize, retentionPolicy).WithCompression(codec), nil
+}
+
+// blockStoreIndexConfig returns the index config of the block store, which indexes
+// the entries of the block extension with the type URLs configured for indexing under
+// their configured key fields
+func blockStoreIndexConfig(extensionIndexConfig *ledger.ExtensionIndexConfig) *blkstorage.IndexConfig {
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	if extensionIndexConfig != nil && len(extensionIndexConfig.TypeURLs) != 0 {
+		indexConfig.AttrsToIndex = append([]blkstorage.IndexableAttr{blkstorage.IndexableAttrExtension}, attrsToIndex...)
+		indexConfig.ExtensionKeys = make(map[string]string)
+		for _, typeURL := range extensionIndexConfig.TypeURLs {
+			indexConfig.ExtensionKeys[typeURL] = extensionIndexConfig.KeyFields[typeURL]
+		}
+	}
+	return indexConfig
+}
+
 func (p *Provider) initPvtDataStoreProvider() error {
 	privateDataConfig := &pvtdatastorage.PrivateDataConfig{
 		PrivateDataConfig: p.initializer.Config.PrivateDataConfig,
@@ -238,7 +290,13 @@ func (p *Provider) initStateDBProvider() error {
 		stateDB,
 		sysNamespaces,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if p.initializer.OperationsHandlerRegistry != nil {
+		p.dbProvider.RegisterOperationsHandlers(p.initializer.OperationsHandlerRegistry)
+	}
+	return nil
 }
 
 func (p *Provider) initLedgerStatistics() {
@@ -286,7 +344,7 @@ func (p *Provider) Create(genesisBlock *common.Block) (ledger.PeerLedger, error)
 	if err = p.idStore.setUnderConstructionFlag(ledgerID); err != nil {
 		return nil, err
 	}
-	lgr, err := p.open(ledgerID)
+	lgr, err := p.open(ledgerID, nil, false)
 	if err != nil {
 		logger.Errorf("Error opening a new empty ledger. Unsetting under construction flag. Error: %+v", err)
 		panicOnErr(p.runCleanup(ledgerID), "Error running cleanup for ledger id [%s]", ledgerID)
@@ -315,10 +373,122 @@ func (p *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	if !active {
 		return nil, ErrInactiveLedger
 	}
-	return p.open(ledgerID)
+	bootSnapshotMetadata, err := p.idStore.getBootSnapshotMetadata(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	return p.open(ledgerID, bootSnapshotMetadata, false)
+}
+
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// This function verifies the snapshot files against the hashes recorded in the snapshot metadata and then
+// bootstraps the block store, the statedb, the history db, the config history, and the pvtdata store
+// from the snapshot. The private data of the hashes in the snapshot is recorded as missing in the pvtdata
+// store, so that the reconciler fetches it, and the expiry of the hashes is recorded in the purge manager,
+// so that they are purged as per the BTL policy. Before bootstrapping, the ledger is marked as under construction in the idStore and
+// the mark is replaced by an entry in the created ledgers list only after all of these steps succeed. If a
+// failure happens in between, the partially bootstrapped data is dropped. If a crash happens in between, the
+// 'deleteUnderConstructionLedgers' function drops the data at the next start of the provider
+func (p *Provider) CreateFromSnapshot(snapshotDir string) (ledger.PeerLedger, string, error) {
+	metadataJSONs, err := loadSnapshotMetadataJSONs(snapshotDir)
+	if err != nil {
+		return nil, "", err
+	}
+	metadata, err := metadataJSONs.toMetadata()
+	if err != nil {
+		return nil, "", err
+	}
+	ledgerID := metadata.ChannelName
+	if metadata.ChannelHeight == 0 {
+		return nil, "", errors.Errorf("invalid snapshot metadata for channel [%s]: channel height is zero", ledgerID)
+	}
+	exists, err := p.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return nil, "", ErrLedgerIDExists
+	}
+	if err := verifySnapshot(snapshotDir, metadataJSONs, metadata, p.initializer.HashProvider); err != nil {
+		return nil, "", err
+	}
+
+	bootSnapshotMetadata := &msgs.BootSnapshotMetadata{
+		SignableMetadata:   metadataJSONs.signableMetadata,
+		AdditionalMetadata: metadataJSONs.additionalMetadata,
+	}
+	if err := p.idStore.markLedgerUnderConstruction(ledgerID, bootSnapshotMetadata); err != nil {
+		return nil, "", err
+	}
+	lgr, err := p.bootstrapFromSnapshot(snapshotDir, metadata)
+	if err != nil {
+		return nil, "", p.deleteUnderConstructionLedger(ledgerID, err)
+	}
+	if err := p.idStore.createLedgerIDFromSnapshot(ledgerID, bootSnapshotMetadata); err != nil {
+		lgr.Close()
+		return nil, "", p.deleteUnderConstructionLedger(ledgerID, err)
+	}
+	return lgr, ledgerID, nil
+}
+
+// bootstrapFromSnapshot bootstraps the stores of the ledger from the snapshot and opens the ledger
+func (p *Provider) bootstrapFromSnapshot(snapshotDir string, metadata *snapshotMetadata) (ledger.PeerLedger, error) {
+	ledgerID := metadata.ChannelName
+	logger.Infof("Creating ledger [%s] from snapshot at height [%d]", ledgerID, metadata.ChannelHeight)
+	lastBlockNum := metadata.ChannelHeight - 1
+	lastBlockHash, err := hex.DecodeString(metadata.LastBlockHashInHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decoding the last block hash from snapshot metadata")
+	}
+	previousBlockHash, err := hex.DecodeString(metadata.PreviousBlockHashInHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decoding the previous block hash from snapshot metadata")
+	}
+	blockStore, err := p.blkStoreProvider.BootstrapFromSnapshottedTxIDs(
+		snapshotDir,
+		&blkstorage.SnapshotInfo{
+			LedgerID:          ledgerID,
+			LastBlockNum:      lastBlockNum,
+			LastBlockHash:     lastBlockHash,
+			PreviousBlockHash: previousBlockHash,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	blockStore.Shutdown()
+
+	if _, ok := metadata.FilesAndHashes[confighistory.SnapshotMetadataFileName]; ok {
+		if err := p.configHistoryMgr.ImportConfigHistory(ledgerID, snapshotDir); err != nil {
+			return nil, err
+		}
+	}
+
+	savepoint := version.NewHeight(lastBlockNum, math.MaxUint64)
+	if err := p.dbProvider.ImportFromSnapshot(ledgerID, savepoint, snapshotDir); err != nil {
+		return nil, err
+	}
+	if p.historydbProvider != nil {
+		if err := p.historydbProvider.MarkStartingSavepoint(ledgerID, savepoint); err != nil {
+			return nil, err
+		}
+	}
+	pvtdataHashesCollections, err := privacyenabledstate.LoadSnapshotPvtdataHashesCollections(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	lgr, err := p.open(ledgerID, metadata, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := lgr.(*kvLedger).recordPvtdataHashesOfSnapshot(pvtdataHashesCollections); err != nil {
+		lgr.Close()
+		return nil, err
+	}
+	return lgr, nil
 }
 
-func (p *Provider) open(ledgerID string) (ledger.PeerLedger, error) {
+func (p *Provider) open(ledgerID string, bootSnapshotMetadata *snapshotMetadata, initializingFromSnapshot bool) (ledger.PeerLedger, error) {
 	// Get the block store for a chain/ledger
 	blockStore, err := p.blkStoreProvider.Open(ledgerID)
 	if err != nil {
@@ -329,6 +499,11 @@ func (p *Provider) open(ledgerID string) (ledger.PeerLedger, error) {
 	if err != nil {
 		return nil, err
 	}
+	if initializingFromSnapshot {
+		if err := pvtdataStore.InitLastCommittedBlock(bootSnapshotMetadata.ChannelHeight - 1); err != nil {
+			return nil, err
+		}
+	}
 
 	p.collElgNotifier.registerListener(ledgerID, pvtdataStore)
 
@@ -358,11 +533,16 @@ func (p *Provider) open(ledgerID string) (ledger.PeerLedger, error) {
 		stateListeners:           p.stateListeners,
 		bookkeeperProvider:       p.bookkeepingProvider,
 		ccInfoProvider:           p.initializer.DeployedChaincodeInfoProvider,
+		membershipInfoProvider:   p.initializer.MembershipInfoProvider,
 		ccLifecycleEventProvider: p.initializer.ChaincodeLifecycleEventProvider,
 		stats:                    p.stats.ledgerStats(ledgerID),
 		customTxProcessors:       p.initializer.CustomTxProcessors,
 		hashProvider:             p.initializer.HashProvider,
 		snapshotsConfig:          p.initializer.Config.SnapshotsConfig,
+		stateCheckpointsConfig:   p.initializer.Config.StateCheckpointsConfig,
+		blockRetentionConfig:     p.initializer.Config.BlockRetentionConfig,
+		commitPipelineConfig:     p.initializer.Config.CommitPipelineConfig,
+		bootSnapshotMetadata:     bootSnapshotMetadata,
 	}
 
 	l, err := newKVLedger(initializer)
@@ -423,7 +603,7 @@ func (p *Provider) recoverUnderConstructionLedger() {
 		return
 	}
 	logger.Infof("ledger [%s] found as under construction", ledgerID)
-	ledger, err := p.open(ledgerID)
+	ledger, err := p.open(ledgerID, nil, false)
 	panicOnErr(err, "Error while opening under construction ledger [%s]", ledgerID)
 	bcInfo, err := ledger.GetBlockchainInfo()
 	panicOnErr(err, "Error while getting blockchain info for the under construction ledger [%s]", ledgerID)
@@ -446,6 +626,58 @@ func (p *Provider) recoverUnderConstructionLedger() {
 	}
 }
 
+// deleteUnderConstructionLedgers drops the data of the ledgers that are marked as under construction - this would be
+// the case if a crash had happened during the creation of a ledger from a snapshot, and removes them from the idStore
+func (p *Provider) deleteUnderConstructionLedgers() error {
+	ledgerIDs, err := p.idStore.getUnderConstructionLedgerIDs()
+	if err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		logger.Infof("ledger [%s] found as under construction, dropping the data of the ledger", ledgerID)
+		if err := p.dropUnderConstructionLedger(ledgerID); err != nil {
+			return errors.WithMessagef(err, "error while deleting the under construction ledger [%s]", ledgerID)
+		}
+	}
+	return nil
+}
+
+// deleteUnderConstructionLedger drops the data of a ledger whose creation from a snapshot failed with the error
+// creationErr and removes the ledger from the idStore. If the cleanup fails, the ledger remains marked as under
+// construction and the cleanup is retried at the next start of the provider. The error creationErr is returned
+func (p *Provider) deleteUnderConstructionLedger(ledgerID string, creationErr error) error {
+	if err := p.dropUnderConstructionLedger(ledgerID); err != nil {
+		logger.Errorf("Error while deleting the under construction ledger [%s], the deletion is retried at the next start: %+v", ledgerID, err)
+	}
+	return creationErr
+}
+
+// dropUnderConstructionLedger drops the data of an under construction ledger from all the stores
+// and then removes the ledger from the idStore
+func (p *Provider) dropUnderConstructionLedger(ledgerID string) error {
+	if err := p.blkStoreProvider.Remove(ledgerID); err != nil {
+		return errors.WithMessage(err, "error while dropping the block store")
+	}
+	if err := p.pvtdataStoreProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error while dropping the pvtdata store")
+	}
+	if err := p.dbProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error while dropping the statedb")
+	}
+	if p.historydbProvider != nil {
+		if err := p.historydbProvider.Drop(ledgerID); err != nil {
+			return err
+		}
+	}
+	if err := p.configHistoryMgr.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error while dropping the config history")
+	}
+	if err := p.bookkeepingProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error while dropping the bookkeeping")
+	}
+	return p.idStore.deleteLedgerID(ledgerID)
+}
+
 // runCleanup cleans up blockstorage, statedb, and historydb for what
 // may have got created during in-complete ledger creation
 func (p *Provider) runCleanup(ledgerID string) error {
@@ -596,31 +828,81 @@ func (s *idStore) getUnderConstructionFlag() (string, error) {
 	return string(val), nil
 }
 
+// markLedgerUnderConstruction records in the ledger metadata that the ledger is being created from a snapshot.
+// The ledger key is not added until the creation completes
+func (s *idStore) markLedgerUnderConstruction(ledgerID string, bootSnapshotMetadata *msgs.BootSnapshotMetadata) error {
+	metadata, err := protoutil.Marshal(&msgs.LedgerMetadata{
+		Status:               msgs.Status_UNDER_CONSTRUCTION,
+		BootSnapshotMetadata: bootSnapshotMetadata,
+	})
+	if err != nil {
+		return err
+	}
+	return s.db.Put(s.encodeLedgerKey(ledgerID, metadataKeyPrefix), metadata, true)
+}
+
+// deleteLedgerID removes the ledger key and the ledger metadata of the ledger
+func (s *idStore) deleteLedgerID(ledgerID string) error {
+	batch := &leveldb.Batch{}
+	batch.Delete(s.encodeLedgerKey(ledgerID, ledgerKeyPrefix))
+	batch.Delete(s.encodeLedgerKey(ledgerID, metadataKeyPrefix))
+	return s.db.WriteBatch(batch, true)
+}
+
 func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
-	gbKey := s.encodeLedgerKey(ledgerID, ledgerKeyPrefix)
+	gbBytes, err := proto.Marshal(gb)
+	if err != nil {
+		return err
+	}
+	return s.addLedgerID(ledgerID, gbBytes, &msgs.LedgerMetadata{Status: msgs.Status_ACTIVE})
+}
+
+// createLedgerIDFromSnapshot adds a ledger that is created from a snapshot to the created ledgers list.
+// As the genesis block is not available for such a ledger, an empty value is stored against the ledger key
+// and the snapshot metadata is preserved in the ledger metadata instead. Hence, the existence of a ledger
+// is determined by its ledger metadata rather than by the value of its ledger key
+func (s *idStore) createLedgerIDFromSnapshot(ledgerID string, bootSnapshotMetadata *msgs.BootSnapshotMetadata) error {
+	return s.addLedgerID(ledgerID, []byte{}, &msgs.LedgerMetadata{
+		Status:               msgs.Status_ACTIVE,
+		BootSnapshotMetadata: bootSnapshotMetadata,
+	})
+}
+
+func (s *idStore) addLedgerID(ledgerID string, ledgerKeyVal []byte, ledgerMetadata *msgs.LedgerMetadata) error {
+	ledgerKey := s.encodeLedgerKey(ledgerID, ledgerKeyPrefix)
 	metadataKey := s.encodeLedgerKey(ledgerID, metadataKeyPrefix)
-	var val []byte
-	var metadata []byte
-	var err error
-	if val, err = s.db.Get(gbKey); err != nil {
+	existingMetadata, err := s.getLedgerMetadata(ledgerID)
+	if err != nil {
 		return err
 	}
-	if val != nil {
+	if existingMetadata != nil && existingMetadata.Status != msgs.Status_UNDER_CONSTRUCTION {
 		return ErrLedgerIDExists
 	}
-	if val, err = proto.Marshal(gb); err != nil {
-		return err
-	}
-	if metadata, err = protoutil.Marshal(&msgs.LedgerMetadata{Status: msgs.Status_ACTIVE}); err != nil {
+	metadata, err := protoutil.Marshal(ledgerMetadata)
+	if err != nil {
 		return err
 	}
 	batch := &leveldb.Batch{}
-	batch.Put(gbKey, val)
+	batch.Put(ledgerKey, ledgerKeyVal)
 	batch.Put(metadataKey, metadata)
 	batch.Delete(underConstructionLedgerKey)
 	return s.db.WriteBatch(batch, true)
 }
 
+// getBootSnapshotMetadata returns the metadata of the snapshot from which the ledger was created.
+// It returns nil if the ledger was created from a genesis block
+func (s *idStore) getBootSnapshotMetadata(ledgerID string) (*snapshotMetadata, error) {
+	metadata, err := s.getLedgerMetadata(ledgerID)
+	if err != nil || metadata == nil || metadata.BootSnapshotMetadata == nil {
+		return nil, err
+	}
+	jsons := &snapshotMetadataJSONs{
+		signableMetadata:   metadata.BootSnapshotMetadata.SignableMetadata,
+		additionalMetadata: metadata.BootSnapshotMetadata.AdditionalMetadata,
+	}
+	return jsons.toMetadata()
+}
+
 func (s *idStore) updateLedgerStatus(ledgerID string, newStatus msgs.Status) error {
 	metadata, err := s.getLedgerMetadata(ledgerID)
 	if err != nil {
@@ -630,6 +912,9 @@ func (s *idStore) updateLedgerStatus(ledgerID string, newStatus msgs.Status) err
 		logger.Errorf("LedgerID [%s] does not exist", ledgerID)
 		return ErrNonExistingLedgerID
 	}
+	if metadata.Status == msgs.Status_UNDER_CONSTRUCTION {
+		return errors.Errorf("cannot update the status of ledger [%s] as it is under construction", ledgerID)
+	}
 	if metadata.Status == newStatus {
 		logger.Infof("Ledger [%s] is already in [%s] status, nothing to do", ledgerID, newStatus)
 		return nil
@@ -658,8 +943,9 @@ func (s *idStore) getLedgerMetadata(ledgerID string) (*msgs.LedgerMetadata, erro
 	return metadata, nil
 }
 
+// ledgerIDExists returns if a ledger exists, including a ledger that is under construction
 func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
-	key := s.encodeLedgerKey(ledgerID, ledgerKeyPrefix)
+	key := s.encodeLedgerKey(ledgerID, metadataKeyPrefix)
 	val, err := s.db.Get(key)
 	if err != nil {
 		return false, err
@@ -677,6 +963,15 @@ func (s *idStore) ledgerIDActive(ledgerID string) (bool, bool, error) {
 }
 
 func (s *idStore) getActiveLedgerIDs() ([]string, error) {
+	return s.getLedgerIDs(msgs.Status_ACTIVE)
+}
+
+func (s *idStore) getUnderConstructionLedgerIDs() ([]string, error) {
+	return s.getLedgerIDs(msgs.Status_UNDER_CONSTRUCTION)
+}
+
+// getLedgerIDs returns the ids of the ledgers with the given status
+func (s *idStore) getLedgerIDs(status msgs.Status) ([]string, error) {
 	var ids []string
 	itr := s.db.GetIterator(metadataKeyPrefix, metadataKeyStop)
 	defer itr.Release()
@@ -686,7 +981,7 @@ func (s *idStore) getActiveLedgerIDs() ([]string, error) {
 			logger.Errorf("Error unmarshalling ledger metadata: %s", err)
 			return nil, errors.Wrapf(err, "error unmarshalling ledger metadata")
 		}
-		if metadata.Status == msgs.Status_ACTIVE {
+		if metadata.Status == status {
 			id := s.decodeLedgerID(itr.Key(), metadataKeyPrefix)
 			ids = append(ids, id)
 		}
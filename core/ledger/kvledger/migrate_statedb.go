@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"github.com/pkg/errors"
+)
+
+// ChaincodeDeployEventsReplayer replays the chaincode deploy events for a channel. This is used for creating
+// the statedb artifacts, such as the CouchDB indexes, in a migrated state database
+type ChaincodeDeployEventsReplayer interface {
+	// ReplayChaincodeDeployEvents invokes the listener for each of the chaincodes that are deployed on the channel
+	// and installed on the peer, in the same manner as the listener gets invoked upon the deployment of a chaincode
+	ReplayChaincodeDeployEvents(channelID string, qe ledger.SimpleQueryExecutor, listener ledger.ChaincodeLifecycleEventListener) error
+}
+
+// MigrateStateDB migrates the state databases of all the active channels from the state database that is configured in
+// config.StateDBConfig to the state database that is configured in targetStateDBConfig. For each channel, the public data,
+// the private data, and the hashes of the private data are copied along with the metadata and the savepoint, the digests
+// of the data in the two databases are compared, and, if the deployEventsReplayer is not nil, the chaincode deploy events
+// are replayed for creating the indexes in the target database. The built-in target databases are dropped before the
+// migration so that a failed migration can be retried. When the command is executed, the peer must be offline.
+// The peer is expected to be started with the target database configured, after the migration
+func MigrateStateDB(config *ledger.Config, targetStateDBConfig *ledger.StateDBConfig, deployEventsReplayer ChaincodeDeployEventsReplayer) error {
+	sourceStateDatabase := config.StateDBConfig.StateDatabase
+	targetStateDatabase := targetStateDBConfig.StateDatabase
+	if sourceStateDatabase == targetStateDatabase {
+		return errors.Errorf("the state database is already [%s]", targetStateDatabase)
+	}
+
+	rootFSPath := config.RootFSPath
+	fileLock := leveldbhelper.NewFileLock(fileLockPath(rootFSPath))
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	idStore, err := openIDStore(LedgerProviderPath(rootFSPath))
+	if err != nil {
+		return err
+	}
+	ledgerIDs, err := idStore.getActiveLedgerIDs()
+	idStore.close()
+	if err != nil {
+		return err
+	}
+
+	if err := dropTargetStateDB(rootFSPath, targetStateDBConfig); err != nil {
+		return err
+	}
+
+	bookkeepingProvider, err := bookkeeping.NewProvider(BookkeeperDBPath(rootFSPath))
+	if err != nil {
+		return err
+	}
+	defer bookkeepingProvider.Close()
+
+	sourceProvider, err := newStateDBProviderForMigration(rootFSPath, bookkeepingProvider, config.StateDBConfig)
+	if err != nil {
+		return errors.WithMessagef(err, "error while opening the source state database [%s]", sourceStateDatabase)
+	}
+	defer sourceProvider.Close()
+	targetProvider, err := newStateDBProviderForMigration(rootFSPath, bookkeepingProvider, targetStateDBConfig)
+	if err != nil {
+		return errors.WithMessagef(err, "error while opening the target state database [%s]", targetStateDatabase)
+	}
+	defer targetProvider.Close()
+
+	for _, ledgerID := range ledgerIDs {
+		if err := migrateChannelStateDB(ledgerID, sourceProvider, targetProvider, deployEventsReplayer); err != nil {
+			return errors.WithMessagef(err, "error while migrating the state database for channel [%s]", ledgerID)
+		}
+	}
+	logger.Infof("Migrated the state databases of %d channels from [%s] to [%s]. Set ledger.state.stateDatabase to [%s] before starting the peer",
+		len(ledgerIDs), sourceStateDatabase, targetStateDatabase, targetStateDatabase)
+	return nil
+}
+
+func migrateChannelStateDB(
+	ledgerID string,
+	sourceProvider, targetProvider *privacyenabledstate.DBProvider,
+	deployEventsReplayer ChaincodeDeployEventsReplayer,
+) error {
+	sourceDB, err := sourceProvider.GetDBHandle(ledgerID, nil)
+	if err != nil {
+		return err
+	}
+	savepoint, err := sourceDB.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if savepoint == nil {
+		logger.Infof("Skipping the migration for channel [%s] as its state database is empty", ledgerID)
+		return nil
+	}
+	targetDB, err := targetProvider.GetDBHandle(ledgerID, nil)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Migrating the state database for channel [%s] at savepoint [%s]", ledgerID, savepoint)
+	sourceDigest, err := sourceDB.MigrateTo(targetDB)
+	if err != nil {
+		return err
+	}
+	targetDigest, err := targetDB.ComputeDigest()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sourceDigest, targetDigest) {
+		return errors.Errorf("digest of the migrated data [%x] does not match the digest of the source data [%x]", targetDigest, sourceDigest)
+	}
+	logger.Infof("Migrated the state database for channel [%s], digest of the data = [%x]", ledgerID, targetDigest)
+
+	if deployEventsReplayer == nil {
+		return nil
+	}
+	return deployEventsReplayer.ReplayChaincodeDeployEvents(
+		ledgerID,
+		&migratedStateQueryExecutor{
+			simpleQueryExecutor: simpleQueryExecutor{targetDB},
+			db:                  targetDB,
+		},
+		&ccEventListenerAdaptor{targetDB},
+	)
+}
+
+func newStateDBProviderForMigration(
+	rootFSPath string,
+	bookkeepingProvider bookkeeping.Provider,
+	stateDBConfig *ledger.StateDBConfig,
+) (*privacyenabledstate.DBProvider, error) {
+	return privacyenabledstate.NewDBProvider(
+		bookkeepingProvider,
+		&disabled.Provider{},
+		&noopHealthCheckRegistry{},
+		&privacyenabledstate.StateDBConfig{
+			StateDBConfig: stateDBConfig,
+			LevelDBPath:   StateDBPath(rootFSPath),
+		},
+		nil,
+	)
+}
+
+// dropTargetStateDB drops the built-in state database that is the target of a migration. A registered
+// state database is expected to be empty, which is verified during the migration
+func dropTargetStateDB(rootFSPath string, stateDBConfig *ledger.StateDBConfig) error {
+	switch stateDBConfig.StateDatabase {
+	case "CouchDB":
+		return statecouchdb.DropApplicationDBs(stateDBConfig.CouchDB)
+	case "goleveldb", "":
+		return dropStateLevelDB(rootFSPath)
+	default:
+		return nil
+	}
+}
+
+// migratedStateQueryExecutor implements ledger.SimpleQueryExecutor interface over a migrated state database
+type migratedStateQueryExecutor struct {
+	simpleQueryExecutor
+	db *privacyenabledstate.DB
+}
+
+func (qe *migratedStateQueryExecutor) GetPrivateDataHash(namespace, collection, key string) ([]byte, error) {
+	vv, err := qe.db.GetPrivateDataHash(namespace, collection, key)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Value, nil
+}
+
+type noopHealthCheckRegistry struct{}
+
+func (r *noopHealthCheckRegistry) RegisterChecker(string, healthz.HealthChecker) error {
+	return nil
+}
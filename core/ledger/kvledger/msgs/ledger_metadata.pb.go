@@ -24,18 +24,21 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type Status int32
 
 const (
-	Status_ACTIVE   Status = 0
-	Status_INACTIVE Status = 1
+	Status_ACTIVE             Status = 0
+	Status_INACTIVE           Status = 1
+	Status_UNDER_CONSTRUCTION Status = 2
 )
 
 var Status_name = map[int32]string{
 	0: "ACTIVE",
 	1: "INACTIVE",
+	2: "UNDER_CONSTRUCTION",
 }
 
 var Status_value = map[string]int32{
-	"ACTIVE":   0,
-	"INACTIVE": 1,
+	"ACTIVE":             0,
+	"INACTIVE":           1,
+	"UNDER_CONSTRUCTION": 2,
 }
 
 func (x Status) String() string {
@@ -48,10 +51,11 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 
 // LedgerMetadata specifies the metadata of a ledger
 type LedgerMetadata struct {
-	Status               Status   `protobuf:"varint,1,opt,name=status,proto3,enum=msgs.Status" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status               Status                `protobuf:"varint,1,opt,name=status,proto3,enum=msgs.Status" json:"status,omitempty"`
+	BootSnapshotMetadata *BootSnapshotMetadata `protobuf:"bytes,2,opt,name=boot_snapshot_metadata,json=bootSnapshotMetadata,proto3" json:"boot_snapshot_metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *LedgerMetadata) Reset()         { *m = LedgerMetadata{} }
@@ -86,24 +90,88 @@ func (m *LedgerMetadata) GetStatus() Status {
 	return Status_ACTIVE
 }
 
+func (m *LedgerMetadata) GetBootSnapshotMetadata() *BootSnapshotMetadata {
+	if m != nil {
+		return m.BootSnapshotMetadata
+	}
+	return nil
+}
+
+// BootSnapshotMetadata captures the metadata of the snapshot from which a ledger is bootstrapped.
+// It is absent for the ledgers that are created from a genesis block
+type BootSnapshotMetadata struct {
+	SignableMetadata     string   `protobuf:"bytes,1,opt,name=signable_metadata,json=signableMetadata,proto3" json:"signable_metadata,omitempty"`
+	AdditionalMetadata   string   `protobuf:"bytes,2,opt,name=additional_metadata,json=additionalMetadata,proto3" json:"additional_metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BootSnapshotMetadata) Reset()         { *m = BootSnapshotMetadata{} }
+func (m *BootSnapshotMetadata) String() string { return proto.CompactTextString(m) }
+func (*BootSnapshotMetadata) ProtoMessage()    {}
+func (*BootSnapshotMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8173a53a47b026a1, []int{1}
+}
+
+func (m *BootSnapshotMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BootSnapshotMetadata.Unmarshal(m, b)
+}
+func (m *BootSnapshotMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BootSnapshotMetadata.Marshal(b, m, deterministic)
+}
+func (m *BootSnapshotMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BootSnapshotMetadata.Merge(m, src)
+}
+func (m *BootSnapshotMetadata) XXX_Size() int {
+	return xxx_messageInfo_BootSnapshotMetadata.Size(m)
+}
+func (m *BootSnapshotMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_BootSnapshotMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BootSnapshotMetadata proto.InternalMessageInfo
+
+func (m *BootSnapshotMetadata) GetSignableMetadata() string {
+	if m != nil {
+		return m.SignableMetadata
+	}
+	return ""
+}
+
+func (m *BootSnapshotMetadata) GetAdditionalMetadata() string {
+	if m != nil {
+		return m.AdditionalMetadata
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("msgs.Status", Status_name, Status_value)
 	proto.RegisterType((*LedgerMetadata)(nil), "msgs.LedgerMetadata")
+	proto.RegisterType((*BootSnapshotMetadata)(nil), "msgs.BootSnapshotMetadata")
 }
 
 func init() { proto.RegisterFile("ledger_metadata.proto", fileDescriptor_8173a53a47b026a1) }
 
 var fileDescriptor_8173a53a47b026a1 = []byte{
-	// 165 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcd, 0x49, 0x4d, 0x49,
-	0x4f, 0x2d, 0x8a, 0xcf, 0x4d, 0x2d, 0x49, 0x4c, 0x49, 0x2c, 0x49, 0xd4, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0x62, 0xc9, 0x2d, 0x4e, 0x2f, 0x56, 0x32, 0xe3, 0xe2, 0xf3, 0x01, 0x4b, 0xfb, 0x42,
-	0x65, 0x85, 0x54, 0xb8, 0xd8, 0x8a, 0x4b, 0x12, 0x4b, 0x4a, 0x8b, 0x25, 0x18, 0x15, 0x18, 0x35,
-	0xf8, 0x8c, 0x78, 0xf4, 0x40, 0x0a, 0xf5, 0x82, 0xc1, 0x62, 0x41, 0x50, 0x39, 0x2d, 0x25, 0x2e,
-	0x36, 0x88, 0x88, 0x10, 0x17, 0x17, 0x9b, 0xa3, 0x73, 0x88, 0x67, 0x98, 0xab, 0x00, 0x83, 0x10,
-	0x0f, 0x17, 0x87, 0xa7, 0x1f, 0x94, 0xc7, 0xe8, 0x64, 0x19, 0x65, 0x9e, 0x9e, 0x59, 0x92, 0x51,
-	0x9a, 0xa4, 0x97, 0x9c, 0x9f, 0xab, 0x9f, 0x51, 0x59, 0x90, 0x5a, 0x04, 0x71, 0x8a, 0x7e, 0x5a,
-	0x62, 0x52, 0x51, 0x66, 0xb2, 0x7e, 0x72, 0x7e, 0x51, 0xaa, 0x3e, 0x54, 0x28, 0xbb, 0x0c, 0xca,
-	0x00, 0xd9, 0x96, 0xc4, 0x06, 0x76, 0xa3, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0xb2, 0x6b, 0xab,
-	0x30, 0xbc, 0x00, 0x00, 0x00,
+	// 282 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xc1, 0x4b, 0xc3, 0x30,
+	0x14, 0xc6, 0xed, 0x90, 0xe2, 0x9e, 0x63, 0xd4, 0x38, 0xc7, 0xf0, 0x34, 0x86, 0x87, 0xa1, 0xd0,
+	0xc2, 0x3c, 0x88, 0xde, 0x5c, 0xdd, 0xa1, 0xa0, 0x9d, 0xa4, 0x9d, 0x07, 0x2f, 0x25, 0x69, 0x63,
+	0x5b, 0x6c, 0x9b, 0xd2, 0x64, 0x82, 0xff, 0x81, 0x7f, 0xb6, 0xb4, 0x4d, 0x37, 0x06, 0xbb, 0x25,
+	0xdf, 0xef, 0x7b, 0x79, 0x2f, 0xdf, 0x83, 0xab, 0x8c, 0x45, 0x31, 0xab, 0x82, 0x9c, 0x49, 0x12,
+	0x11, 0x49, 0xcc, 0xb2, 0xe2, 0x92, 0xa3, 0xd3, 0x5c, 0xc4, 0x62, 0xf6, 0xa7, 0xc1, 0xf0, 0xb5,
+	0xe1, 0x6f, 0x0a, 0xa3, 0x1b, 0xd0, 0x85, 0x24, 0x72, 0x2b, 0x26, 0xda, 0x54, 0x9b, 0x0f, 0x17,
+	0x03, 0xb3, 0x76, 0x9a, 0x5e, 0xa3, 0x61, 0xc5, 0xd0, 0x3b, 0x8c, 0x29, 0xe7, 0x32, 0x10, 0x05,
+	0x29, 0x45, 0xc2, 0xe5, 0xee, 0xf9, 0x49, 0x6f, 0xaa, 0xcd, 0xcf, 0x17, 0xd7, 0x6d, 0xd5, 0x92,
+	0x73, 0xe9, 0x29, 0x4b, 0xd7, 0x01, 0x8f, 0xe8, 0x11, 0x75, 0x26, 0x61, 0x74, 0xcc, 0x8d, 0xee,
+	0xe0, 0x42, 0xa4, 0x71, 0x41, 0x68, 0xc6, 0xf6, 0x4d, 0xea, 0xd1, 0xfa, 0xd8, 0xe8, 0xc0, 0xce,
+	0x6c, 0xc1, 0x25, 0x89, 0xa2, 0x54, 0xa6, 0xbc, 0x20, 0xd9, 0xe1, 0x4c, 0x7d, 0x8c, 0xf6, 0xa8,
+	0x2b, 0xb8, 0x7d, 0x02, 0xbd, 0xfd, 0x19, 0x02, 0xd0, 0x9f, 0x6d, 0xdf, 0xf9, 0x58, 0x19, 0x27,
+	0x68, 0x00, 0x67, 0x8e, 0xab, 0x6e, 0x1a, 0x1a, 0x03, 0xda, 0xb8, 0x2f, 0x2b, 0x1c, 0xd8, 0x6b,
+	0xd7, 0xf3, 0xf1, 0xc6, 0xf6, 0x9d, 0xb5, 0x6b, 0xf4, 0x96, 0x8f, 0x9f, 0x0f, 0x71, 0x2a, 0x93,
+	0x2d, 0x35, 0x43, 0x9e, 0x5b, 0xc9, 0x6f, 0xc9, 0xaa, 0x36, 0x6b, 0xeb, 0x8b, 0xd0, 0x2a, 0x0d,
+	0xad, 0x90, 0x57, 0xcc, 0x52, 0xd2, 0xf7, 0x8f, 0x3a, 0xd4, 0xb9, 0x50, 0xbd, 0x59, 0xc2, 0xfd,
+	0xff, 0x00, 0x85, 0x0e, 0x4b, 0x2d, 0x9d, 0x01, 0x00, 0x00,
 }
@@ -45,3 +45,21 @@ func pauseOrResumeChannel(rootFSPath, ledgerID string, status msgs.Status) error
 	defer idStore.db.Close()
 	return idStore.updateLedgerStatus(ledgerID, status)
 }
+
+// LedgerExists returns whether the ledger of the given channel exists on the peer, irrespective of its status.
+// Like PauseChannel and ResumeChannel, it is meant to be invoked when the peer is offline
+func LedgerExists(rootFSPath, ledgerID string) (bool, error) {
+	fileLock := leveldbhelper.NewFileLock(fileLockPath(rootFSPath))
+	if err := fileLock.Lock(); err != nil {
+		return false, errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	idStore, err := openIDStore(LedgerProviderPath(rootFSPath))
+	if err != nil {
+		return false, err
+	}
+	defer idStore.db.Close()
+	return idStore.ledgerIDExists(ledgerID)
+}
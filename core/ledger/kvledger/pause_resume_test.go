@@ -107,6 +107,35 @@ func TestPauseAndResumeErrors(t *testing.T) {
 	require.EqualError(t, err, "error unmarshalling ledger metadata: unexpected EOF")
 }
 
+func TestLedgerExists(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	conf.HistoryDBConfig.Enabled = false
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+
+	genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(0))
+	provider.Create(genesisBlock)
+
+	// fail if provider is open (e.g., peer is up running)
+	_, err := LedgerExists(conf.RootFSPath, constructTestLedgerID(0))
+	require.Error(t, err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying")
+	provider.Close()
+
+	exists, err := LedgerExists(conf.RootFSPath, constructTestLedgerID(0))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// a paused ledger still exists
+	require.NoError(t, PauseChannel(conf.RootFSPath, constructTestLedgerID(0)))
+	exists, err = LedgerExists(conf.RootFSPath, constructTestLedgerID(0))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = LedgerExists(conf.RootFSPath, "dummy")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
 // verify status for paused ledgers and non-paused ledgers
 func assertLedgerStatus(t *testing.T, provider *Provider, genesisBlocks []*common.Block, numLedgers int, pausedLedgers []int) {
 	s := provider.idStore
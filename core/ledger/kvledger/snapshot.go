@@ -11,10 +11,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/pkg/errors"
 )
 
@@ -28,10 +32,11 @@ const (
 // can be signed by the peer. Hashsum of the resultant JSON is intended to be used as a single
 // hash of the snapshot, if need be.
 type snapshotSignableMetadata struct {
-	ChannelName        string            `json:"channel_name"`
-	ChannelHeight      uint64            `json:"channel_height"`
-	LastBlockHashInHex string            `json:"last_block_hash"`
-	FilesAndHashes     map[string]string `json:"snapshot_files_raw_hashes"`
+	ChannelName            string            `json:"channel_name"`
+	ChannelHeight          uint64            `json:"channel_height"`
+	LastBlockHashInHex     string            `json:"last_block_hash"`
+	PreviousBlockHashInHex string            `json:"previous_block_hash"`
+	FilesAndHashes         map[string]string `json:"snapshot_files_raw_hashes"`
 }
 
 type snapshotAdditionalInfo struct {
@@ -42,6 +47,10 @@ type snapshotAdditionalInfo struct {
 // generateSnapshot generates a snapshot. This function should be invoked when commit on the kvledger are paused
 // after committing the last block fully and further the commits should not be resumed till this function finishes
 func (l *kvLedger) generateSnapshot() error {
+	// the commit of the last block to the state database may still be in progress
+	if err := l.txmgr.WaitForPendingCommit(); err != nil {
+		return err
+	}
 	snapshotsRootDir := l.snapshotsConfig.RootDir
 	bcInfo, err := l.GetBlockchainInfo()
 	if err != nil {
@@ -115,10 +124,11 @@ func (l *kvLedger) generateSnapshotMetadataFiles(
 	}
 	metadata, err := json.MarshalIndent(
 		&snapshotSignableMetadata{
-			ChannelName:        l.ledgerID,
-			ChannelHeight:      bcInfo.Height,
-			LastBlockHashInHex: hex.EncodeToString(bcInfo.CurrentBlockHash),
-			FilesAndHashes:     filesAndHashes,
+			ChannelName:            l.ledgerID,
+			ChannelHeight:          bcInfo.Height,
+			LastBlockHashInHex:     hex.EncodeToString(bcInfo.CurrentBlockHash),
+			PreviousBlockHashInHex: hex.EncodeToString(bcInfo.PreviousBlockHash),
+			FilesAndHashes:         filesAndHashes,
 		},
 		"",
 		jsonFileIndent,
@@ -152,6 +162,91 @@ func (l *kvLedger) generateSnapshotMetadataFiles(
 	return createAndSyncFile(filepath.Join(dir, snapshotMetadataHashFileName), metadataAdditionalInfo)
 }
 
+// snapshotMetadataJSONs holds the contents of the two metadata files of a snapshot as is. These are preserved
+// verbatim in the ledger that is created from the snapshot, so that the snapshot hash remains verifiable
+type snapshotMetadataJSONs struct {
+	signableMetadata   string
+	additionalMetadata string
+}
+
+type snapshotMetadata struct {
+	*snapshotSignableMetadata
+	*snapshotAdditionalInfo
+}
+
+func loadSnapshotMetadataJSONs(snapshotDir string) (*snapshotMetadataJSONs, error) {
+	signableMetadata, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading the snapshot metadata file from dir [%s]", snapshotDir)
+	}
+	additionalMetadata, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataHashFileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading the snapshot additional info file from dir [%s]", snapshotDir)
+	}
+	return &snapshotMetadataJSONs{
+		signableMetadata:   string(signableMetadata),
+		additionalMetadata: string(additionalMetadata),
+	}, nil
+}
+
+func (j *snapshotMetadataJSONs) toMetadata() (*snapshotMetadata, error) {
+	signableMetadata := &snapshotSignableMetadata{}
+	if err := json.Unmarshal([]byte(j.signableMetadata), signableMetadata); err != nil {
+		return nil, errors.Wrap(err, "error while unmarshalling snapshot metadata from JSON")
+	}
+	additionalMetadata := &snapshotAdditionalInfo{}
+	if err := json.Unmarshal([]byte(j.additionalMetadata), additionalMetadata); err != nil {
+		return nil, errors.Wrap(err, "error while unmarshalling snapshot additional info from JSON")
+	}
+	return &snapshotMetadata{
+		snapshotSignableMetadata: signableMetadata,
+		snapshotAdditionalInfo:   additionalMetadata,
+	}, nil
+}
+
+// verifySnapshot checks that the hash of the signable metadata matches the snapshot hash recorded in the additional
+// info and that each of the files listed in the signable metadata is present in the snapshot dir with the listed hash
+func verifySnapshot(snapshotDir string, jsons *snapshotMetadataJSONs, metadata *snapshotMetadata, hashProvider ledger.HashProvider) error {
+	hash, err := hashProvider.GetHash(snapshotHashOpts)
+	if err != nil {
+		return err
+	}
+	if _, err := hash.Write([]byte(jsons.signableMetadata)); err != nil {
+		return err
+	}
+	if computed := hex.EncodeToString(hash.Sum(nil)); computed != metadata.SnapshotHashInHex {
+		return errors.Errorf("hash mismatch for the snapshot metadata file [%s]. expected hash = [%s], computed hash = [%s]",
+			snapshotMetadataFileName, metadata.SnapshotHashInHex, computed)
+	}
+
+	for fileName, expectedHash := range metadata.FilesAndHashes {
+		if err := verifyFileHash(filepath.Join(snapshotDir, fileName), expectedHash, hashProvider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyFileHash(filePath string, expectedHashInHex string, hashProvider ledger.HashProvider) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "error while opening the snapshot file [%s]", filePath)
+	}
+	defer file.Close()
+	hash, err := hashProvider.GetHash(snapshotHashOpts)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(hash, file); err != nil {
+		return errors.Wrapf(err, "error while computing the hash of the snapshot file [%s]", filePath)
+	}
+	if computed := hex.EncodeToString(hash.Sum(nil)); computed != expectedHashInHex {
+		return errors.Errorf("hash mismatch for the snapshot file [%s]. expected hash = [%s], computed hash = [%s]",
+			filePath, expectedHashInHex, computed)
+	}
+	return nil
+}
+
 func createAndSyncFile(filePath string, content []byte) error {
 	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0444)
 	if err != nil {
@@ -190,3 +285,58 @@ func syncDir(dirPath string) error {
 	}
 	return err
 }
+
+// recordPvtdataHashesOfSnapshot records the private data of the hashes imported from the snapshot, from which
+// the ledger is created, as missing in the pvtdata store, and the expiry of the hashes in the purge manager, as
+// the commit of the blocks that precede the snapshot would have done. The collections are the ones for which
+// the snapshot carries private data hashes, by namespace. The eligibility of the peer is evaluated against the
+// member orgs policies of the collections as of the state in the snapshot
+func (l *kvLedger) recordPvtdataHashesOfSnapshot(collections map[string][]string) error {
+	qe, err := l.txmgr.NewQueryExecutorNoCollChecks()
+	if err != nil {
+		return err
+	}
+	defer qe.Done()
+
+	for ns, colls := range collections {
+		for _, coll := range colls {
+			collConfig, err := l.ccInfoProvider.CollectionInfo(l.ledgerID, ns, coll, qe)
+			if err != nil {
+				return err
+			}
+			isEligible := false
+			if collConfig != nil {
+				if isEligible, err = l.membershipInfoProvider.AmMemberOf(l.ledgerID, collConfig.MemberOrgsPolicy); err != nil {
+					return err
+				}
+			}
+
+			missingPvtData := map[uint64]ledger.TxMissingPvtDataMap{}
+			hashedUpdates := privacyenabledstate.NewHashedUpdateBatch()
+			if err := l.txmgr.ForEachValueHash(ns, coll, func(keyHash []byte, vv *statedb.VersionedValue) error {
+				blkNum, txNum := vv.Version.BlockNum, vv.Version.TxNum
+				txMissingPvtData, ok := missingPvtData[blkNum]
+				if !ok {
+					txMissingPvtData = ledger.TxMissingPvtDataMap{}
+					missingPvtData[blkNum] = txMissingPvtData
+				}
+				if len(txMissingPvtData[txNum]) == 0 {
+					txMissingPvtData.Add(txNum, ns, coll, isEligible)
+				}
+				hashedUpdates.Put(ns, coll, keyHash, vv.Value, vv.Version)
+				return nil
+			}); err != nil {
+				return err
+			}
+			logger.Debugf("[%s] recording the private data of %d blocks of collection [%s:%s] imported from snapshot as missing, eligible=%t",
+				l.ledgerID, len(missingPvtData), ns, coll, isEligible)
+			if err := l.pvtdataStore.CommitMissingPvtdataOfSnapshot(missingPvtData); err != nil {
+				return err
+			}
+			if err := l.txmgr.UpdateExpiryInfoOfImportedHashes(hashedUpdates); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
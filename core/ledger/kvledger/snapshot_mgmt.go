@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// snapshotMgr generates the snapshots that are requested for a ledger. A snapshot is generated
+// while the commits on the ledger are paused. This is ensured by holding the commitLock both
+// during the commit of a block and during the generation of a snapshot
+type snapshotMgr struct {
+	commitLock                *sync.Mutex
+	snapshotRequestBookkeeper *snapshotRequestBookkeeper
+	generationWG              sync.WaitGroup
+}
+
+// SubmitSnapshotRequest submits a request for generating a snapshot when the block with the given
+// number is committed. A block number 0 is interpreted as the last committed block. If the block
+// is already committed, the snapshot is generated right away
+func (l *kvLedger) SubmitSnapshotRequest(blockNumber uint64) error {
+	l.snapshotMgr.commitLock.Lock()
+	lastCommittedBlockNum, err := l.lastCommittedBlockNum()
+	if err != nil {
+		l.snapshotMgr.commitLock.Unlock()
+		return err
+	}
+	if blockNumber == 0 {
+		blockNumber = lastCommittedBlockNum
+	}
+	if blockNumber < lastCommittedBlockNum {
+		l.snapshotMgr.commitLock.Unlock()
+		return errors.Errorf("requested snapshot for block number %d cannot be less than the last committed block number %d",
+			blockNumber, lastCommittedBlockNum)
+	}
+	if err := l.snapshotMgr.snapshotRequestBookkeeper.add(blockNumber); err != nil {
+		l.snapshotMgr.commitLock.Unlock()
+		return err
+	}
+	if blockNumber == lastCommittedBlockNum {
+		// the commitLock is released when the snapshot generation finishes
+		l.generateSnapshotAsync(blockNumber)
+		return nil
+	}
+	l.snapshotMgr.commitLock.Unlock()
+	return nil
+}
+
+// CancelSnapshotRequest cancels a pending request for generating a snapshot at the given block number.
+// A request for which the snapshot generation has already started cannot be cancelled
+func (l *kvLedger) CancelSnapshotRequest(blockNumber uint64) error {
+	l.snapshotMgr.commitLock.Lock()
+	defer l.snapshotMgr.commitLock.Unlock()
+	return l.snapshotMgr.snapshotRequestBookkeeper.delete(blockNumber)
+}
+
+// PendingSnapshotRequests returns the block numbers of the pending snapshot requests in ascending order
+func (l *kvLedger) PendingSnapshotRequests() ([]uint64, error) {
+	return l.snapshotMgr.snapshotRequestBookkeeper.list()
+}
+
+// processSnapshotRequest is invoked after the block with the given number is committed, while holding the
+// commitLock. It returns true if a snapshot generation is started for the block, in which case the ownership
+// of the commitLock is handed over to the snapshot generation
+func (l *kvLedger) processSnapshotRequest(blockNumber uint64) (bool, error) {
+	exists, err := l.snapshotMgr.snapshotRequestBookkeeper.exist(blockNumber)
+	if err != nil || !exists {
+		return false, err
+	}
+	l.generateSnapshotAsync(blockNumber)
+	return true, nil
+}
+
+// generateSnapshotAsync generates the snapshot for the given block number in a separate goroutine and
+// releases the commitLock, which is expected to be held by the caller, once the generation finishes.
+// The request is removed from the bookkeeper irrespective of the outcome, as a failed generation cannot
+// be retried after the subsequent blocks are committed
+func (l *kvLedger) generateSnapshotAsync(blockNumber uint64) {
+	l.snapshotMgr.generationWG.Add(1)
+	go func() {
+		defer l.snapshotMgr.generationWG.Done()
+		defer l.snapshotMgr.commitLock.Unlock()
+
+		logger.Infof("[%s] Generating snapshot for block [%d]", l.ledgerID, blockNumber)
+		if err := l.generateSnapshot(); err != nil {
+			logger.Errorw("Failed to generate snapshot", "channelID", l.ledgerID, "blockNumber", blockNumber, "error", err)
+		} else {
+			logger.Infof("[%s] Generated snapshot for block [%d]", l.ledgerID, blockNumber)
+			if l.pruneAtSnapshot {
+				if err := l.blockStore.PruneBlocks(blockNumber + 1); err != nil {
+					logger.Errorw("Failed to prune blocks", "channelID", l.ledgerID, "blockNumber", blockNumber, "error", err)
+				}
+			}
+		}
+		if err := l.snapshotMgr.snapshotRequestBookkeeper.delete(blockNumber); err != nil {
+			logger.Errorw("Failed to remove snapshot request", "channelID", l.ledgerID, "blockNumber", blockNumber, "error", err)
+		}
+	}()
+}
+
+// resumeSnapshotGeneration generates the snapshot for the last committed block if it was requested but
+// the peer stopped before the generation finished
+func (l *kvLedger) resumeSnapshotGeneration() error {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil || bcInfo.Height == 0 {
+		return err
+	}
+	l.snapshotMgr.commitLock.Lock()
+	started, err := l.processSnapshotRequest(bcInfo.Height - 1)
+	if !started {
+		l.snapshotMgr.commitLock.Unlock()
+	}
+	return err
+}
+
+func (l *kvLedger) lastCommittedBlockNum() (uint64, error) {
+	bcInfo, err := l.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	if bcInfo.Height == 0 {
+		return 0, errors.New("ledger has no committed block")
+	}
+	return bcInfo.Height - 1, nil
+}
+
+// snapshotRequestBookkeeper persists the block numbers for which the snapshots are requested
+type snapshotRequestBookkeeper struct {
+	dbHandle *leveldbhelper.DBHandle
+}
+
+func newSnapshotRequestBookkeeper(dbHandle *leveldbhelper.DBHandle) *snapshotRequestBookkeeper {
+	return &snapshotRequestBookkeeper{dbHandle: dbHandle}
+}
+
+func (k *snapshotRequestBookkeeper) add(blockNumber uint64) error {
+	exists, err := k.exist(blockNumber)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.Errorf("duplicate snapshot request for block number %d", blockNumber)
+	}
+	return k.dbHandle.Put(encodeSnapshotRequestKey(blockNumber), []byte{}, true)
+}
+
+func (k *snapshotRequestBookkeeper) delete(blockNumber uint64) error {
+	exists, err := k.exist(blockNumber)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("no snapshot request exists for block number %d", blockNumber)
+	}
+	return k.dbHandle.Delete(encodeSnapshotRequestKey(blockNumber), true)
+}
+
+func (k *snapshotRequestBookkeeper) exist(blockNumber uint64) (bool, error) {
+	val, err := k.dbHandle.Get(encodeSnapshotRequestKey(blockNumber))
+	if err != nil {
+		return false, err
+	}
+	return val != nil, nil
+}
+
+func (k *snapshotRequestBookkeeper) list() ([]uint64, error) {
+	itr, err := k.dbHandle.GetIterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+
+	blockNumbers := []uint64{}
+	for itr.Next() {
+		blockNumber, _, err := util.DecodeOrderPreservingVarUint64(itr.Key())
+		if err != nil {
+			return nil, err
+		}
+		blockNumbers = append(blockNumbers, blockNumber)
+	}
+	return blockNumbers, itr.Error()
+}
+
+func encodeSnapshotRequestKey(blockNumber uint64) []byte {
+	return util.EncodeOrderPreservingVarUint64(blockNumber)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRequestBookkeeper(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "snapshotrequests")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	dbProvider, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: testDir})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+
+	bookkeeper := newSnapshotRequestBookkeeper(dbProvider.GetDBHandle("testLedger"))
+	requests, err := bookkeeper.list()
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	for _, blockNumber := range []uint64{1000, 5, 300} {
+		require.NoError(t, bookkeeper.add(blockNumber))
+	}
+	require.EqualError(t, bookkeeper.add(300), "duplicate snapshot request for block number 300")
+	requests, err = bookkeeper.list()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5, 300, 1000}, requests)
+
+	require.NoError(t, bookkeeper.delete(300))
+	require.EqualError(t, bookkeeper.delete(300), "no snapshot request exists for block number 300")
+	exists, err := bookkeeper.exist(300)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// the requests are persisted
+	bookkeeper = newSnapshotRequestBookkeeper(dbProvider.GetDBHandle("testLedger"))
+	requests, err = bookkeeper.list()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5, 1000}, requests)
+}
+
+func TestSnapshotRequests(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	snapshotRootDir := conf.SnapshotsConfig.RootDir
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	commitNextBlock := func(key string) {
+		blockAndPvtdata := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateFor"+key,
+			map[string]string{key: "value"},
+			nil,
+		)
+		require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata, &ledger.CommitOptions{}))
+	}
+	commitNextBlock("key1")
+
+	// a request for block number 0 generates the snapshot at the last committed block right away
+	require.NoError(t, kvlgr.SubmitSnapshotRequest(0))
+	kvlgr.snapshotMgr.generationWG.Wait()
+	require.DirExists(t, SnapshotDirForLedgerHeight(snapshotRootDir, kvlgr.ledgerID, 2))
+
+	require.NoError(t, kvlgr.SubmitSnapshotRequest(3))
+	require.NoError(t, kvlgr.SubmitSnapshotRequest(5))
+	require.EqualError(t, kvlgr.SubmitSnapshotRequest(5), "duplicate snapshot request for block number 5")
+	requests, err := kvlgr.PendingSnapshotRequests()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 5}, requests)
+
+	commitNextBlock("key2")
+	require.EqualError(t, kvlgr.SubmitSnapshotRequest(1), "requested snapshot for block number 1 cannot be less than the last committed block number 2")
+	require.NoError(t, kvlgr.CancelSnapshotRequest(5))
+	require.EqualError(t, kvlgr.CancelSnapshotRequest(5), "no snapshot request exists for block number 5")
+
+	// the snapshot is generated when the requested block is committed
+	commitNextBlock("key3")
+	commitNextBlock("key4")
+	kvlgr.snapshotMgr.generationWG.Wait()
+	require.DirExists(t, SnapshotDirForLedgerHeight(snapshotRootDir, kvlgr.ledgerID, 4))
+	require.NoDirExists(t, SnapshotDirForLedgerHeight(snapshotRootDir, kvlgr.ledgerID, 5))
+	requests, err = kvlgr.PendingSnapshotRequests()
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
@@ -13,8 +13,12 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -22,6 +26,8 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/msgs"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
@@ -56,6 +62,7 @@ func TestGenerateSnapshot(t *testing.T) {
 		kvlgr.ledgerID,
 		1,
 		protoutil.BlockHeaderHash(genesisBlk.Header),
+		nil,
 		kvlgr.commitHash,
 		"txids.data", "txids.metadata",
 	)
@@ -72,6 +79,7 @@ func TestGenerateSnapshot(t *testing.T) {
 		kvlgr.ledgerID,
 		2,
 		protoutil.BlockHeaderHash(blockAndPvtdata1.Block.Header),
+		protoutil.BlockHeaderHash(genesisBlk.Header),
 		kvlgr.commitHash,
 		"txids.data", "txids.metadata",
 		"public_state.data", "public_state.metadata",
@@ -89,6 +97,7 @@ func TestGenerateSnapshot(t *testing.T) {
 		kvlgr.ledgerID,
 		3,
 		protoutil.BlockHeaderHash(blockAndPvtdata2.Block.Header),
+		protoutil.BlockHeaderHash(blockAndPvtdata1.Block.Header),
 		kvlgr.commitHash,
 		"txids.data", "txids.metadata",
 		"public_state.data", "public_state.metadata",
@@ -108,6 +117,7 @@ func TestGenerateSnapshot(t *testing.T) {
 		kvlgr.ledgerID,
 		4,
 		protoutil.BlockHeaderHash(blockAndPvtdata3.Block.Header),
+		protoutil.BlockHeaderHash(blockAndPvtdata2.Block.Header),
 		kvlgr.commitHash,
 		"txids.data", "txids.metadata",
 		"public_state.data", "public_state.metadata",
@@ -294,6 +304,7 @@ func verifySnapshotOutput(
 	ledgerID string,
 	ledgerHeight uint64,
 	lastBlockHash []byte,
+	previousBlockHash []byte,
 	lastCommitHash []byte,
 	expectedBinaryFiles ...string,
 ) {
@@ -321,10 +332,11 @@ func verifySnapshotOutput(
 	require.NoError(t, json.Unmarshal(mJSON, m))
 	require.Equal(t,
 		&snapshotSignableMetadata{
-			ChannelName:        ledgerID,
-			ChannelHeight:      ledgerHeight,
-			LastBlockHashInHex: hex.EncodeToString(lastBlockHash),
-			FilesAndHashes:     filesAndHashes,
+			ChannelName:            ledgerID,
+			ChannelHeight:          ledgerHeight,
+			LastBlockHashInHex:     hex.EncodeToString(lastBlockHash),
+			PreviousBlockHashInHex: hex.EncodeToString(previousBlockHash),
+			FilesAndHashes:         filesAndHashes,
 		},
 		m,
 	)
@@ -381,3 +393,469 @@ func addDummyEntryInCollectionConfigHistory(t *testing.T, provider *Provider, le
 		},
 	)
 }
+
+func TestCreateFromSnapshot(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	nsCollBtlConfs := []*nsCollBtlConfig{
+		{
+			namespace: "ns",
+			btlConfig: map[string]uint64{"coll": 0},
+		},
+	}
+	provider := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, conf)
+	defer provider.Close()
+
+	// create a ledger with public and private data and config history and generate the snapshot
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	blockAndPvtdata1 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1", "key2": "value2.1"},
+		map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1"},
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata1, &ledger.CommitOptions{}))
+	addDummyEntryInCollectionConfigHistory(t, provider, kvlgr.ledgerID)
+	blockAndPvtdata2 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata2, &ledger.CommitOptions{}))
+	require.NoError(t, kvlgr.generateSnapshot())
+	snapshotDir := SnapshotDirForLedgerHeight(conf.SnapshotsConfig.RootDir, kvlgr.ledgerID, 3)
+
+	// create another ledger from the snapshot in a different provider
+	destConf, destCleanup := testConfig(t)
+	defer destCleanup()
+	destProvider := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, destConf)
+	defer func() {
+		destProvider.Close()
+	}()
+	destLgr, ledgerID, err := destProvider.CreateFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	require.Equal(t, "testLedgerid", ledgerID)
+	destKVLgr := destLgr.(*kvLedger)
+
+	verifyLedgerCreatedFromSnapshot := func(destKVLgr *kvLedger) {
+		bcInfo, err := kvlgr.GetBlockchainInfo()
+		require.NoError(t, err)
+		destBCInfo, err := destKVLgr.GetBlockchainInfo()
+		require.NoError(t, err)
+		require.Equal(t, bcInfo, destBCInfo)
+		require.Equal(t, kvlgr.commitHash, destKVLgr.commitHash)
+		require.NotNil(t, destKVLgr.bootSnapshotMetadata)
+		require.Equal(t, uint64(3), destKVLgr.bootSnapshotMetadata.ChannelHeight)
+
+		qe, err := destKVLgr.NewQueryExecutor()
+		require.NoError(t, err)
+		defer qe.Done()
+		val, err := qe.GetState("ns", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1.2"), val)
+		val, err = qe.GetState("ns", "key2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2.1"), val)
+		pvtValHash, err := qe.GetPrivateDataHash("ns", "coll", "key1")
+		require.NoError(t, err)
+		require.Equal(t, util.ComputeSHA256([]byte("pvtValue1.1")), pvtValHash)
+		// only the hashes of the private data are present in a snapshot
+		_, err = qe.GetPrivateData("ns", "coll", "key1")
+		require.IsType(t, &txmgr.ErrPvtdataNotAvailable{}, err)
+
+		retriever, err := destKVLgr.GetConfigHistoryRetriever()
+		require.NoError(t, err)
+		collConfigInfo, err := retriever.MostRecentCollectionConfigBelow(10, "ns")
+		require.NoError(t, err)
+		require.NotNil(t, collConfigInfo)
+		require.Equal(t, uint64(2), collConfigInfo.CommittingBlockNum)
+	}
+	verifyLedgerCreatedFromSnapshot(destKVLgr)
+
+	// the ledger created from the snapshot is listed and can be reopened
+	destProvider.Close()
+	destProvider = testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, destConf)
+	ledgerIDs, err := destProvider.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"testLedgerid"}, ledgerIDs)
+	destLgr, err = destProvider.Open("testLedgerid")
+	require.NoError(t, err)
+	destKVLgr = destLgr.(*kvLedger)
+	verifyLedgerCreatedFromSnapshot(destKVLgr)
+
+	// the next block is committed in the same way in both the ledgers
+	blockAndPvtdata3 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk3",
+		map[string]string{"key1": "value1.3", "key3": "value3.3"},
+		nil,
+	)
+	destBlockAndPvtdata3 := &ledger.BlockAndPvtData{
+		Block: proto.Clone(blockAndPvtdata3.Block).(*common.Block),
+	}
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata3, &ledger.CommitOptions{}))
+	require.NoError(t, destKVLgr.CommitLegacy(destBlockAndPvtdata3, &ledger.CommitOptions{}))
+	require.Equal(t, kvlgr.commitHash, destKVLgr.commitHash)
+	destBCInfo, err := destKVLgr.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), destBCInfo.Height)
+	qe, err := destKVLgr.NewQueryExecutor()
+	require.NoError(t, err)
+	val, err := qe.GetState("ns", "key3")
+	qe.Done()
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3.3"), val)
+	hqe, err := destKVLgr.NewHistoryQueryExecutor()
+	require.NoError(t, err)
+	itr, err := hqe.GetHistoryForKey("ns", "key1")
+	require.NoError(t, err)
+	defer itr.Close()
+	historyEntry, err := itr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "SimulateForBlk3", historyEntry.(*queryresult.KeyModification).TxId)
+	historyEntry, err = itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, historyEntry) // history is maintained only for the blocks committed after the snapshot
+
+	// a ledger with an existing id cannot be created again
+	_, _, err = destProvider.CreateFromSnapshot(snapshotDir)
+	require.Equal(t, ErrLedgerIDExists, err)
+}
+
+func TestCreateFromSnapshotPvtdataHashes(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	nsCollBtlConfs := []*nsCollBtlConfig{
+		{
+			namespace: "ns",
+			btlConfig: map[string]uint64{"coll": 2},
+		},
+	}
+	provider := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, conf)
+	defer provider.Close()
+
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	blockAndPvtdata1 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1"},
+		map[string]string{"key1": "pvtValue1.1"},
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata1, &ledger.CommitOptions{}))
+	blockAndPvtdata2 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk2",
+		map[string]string{"key2": "value2.2"},
+		map[string]string{"key2": "pvtValue2.2"},
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata2, &ledger.CommitOptions{}))
+	require.NoError(t, kvlgr.generateSnapshot())
+	snapshotDir := SnapshotDirForLedgerHeight(conf.SnapshotsConfig.RootDir, kvlgr.ledgerID, 3)
+
+	destConf, destCleanup := testConfig(t)
+	defer destCleanup()
+	membershipInfoProvider := &mock.MembershipInfoProvider{}
+	membershipInfoProvider.AmMemberOfReturns(true, nil)
+	destProvider := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, destConf)
+	destProvider.initializer.MembershipInfoProvider = membershipInfoProvider
+	defer destProvider.Close()
+	destLgr, _, err := destProvider.CreateFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	defer destLgr.Close()
+	destKVLgr := destLgr.(*kvLedger)
+
+	verifyMissingPvtdata := func(blkNums ...uint64) {
+		missingPvtDataTracker, err := destKVLgr.GetMissingPvtDataTracker()
+		require.NoError(t, err)
+		missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+		require.NoError(t, err)
+		if len(blkNums) == 0 {
+			require.Empty(t, missingPvtDataInfo)
+			return
+		}
+		expectedMissingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+		for _, blkNum := range blkNums {
+			expectedMissingPvtDataInfo.Add(blkNum, 0, "ns", "coll")
+		}
+		require.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+	}
+	verifyPvtdataHashes := func(expectedHashes map[string][]byte) {
+		qe, err := destKVLgr.NewQueryExecutor()
+		require.NoError(t, err)
+		defer qe.Done()
+		for key, expectedHash := range expectedHashes {
+			hash, err := qe.GetPrivateDataHash("ns", "coll", key)
+			require.NoError(t, err)
+			require.Equal(t, expectedHash, hash)
+		}
+	}
+	commitNextBlock := func(txid string) {
+		blockAndPvtdata := prepareNextBlockForTest(t, kvlgr, blkGenerator, txid,
+			map[string]string{"key3": txid},
+			nil,
+		)
+		destBlockAndPvtdata := &ledger.BlockAndPvtData{
+			Block: proto.Clone(blockAndPvtdata.Block).(*common.Block),
+		}
+		require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata, &ledger.CommitOptions{}))
+		require.NoError(t, destKVLgr.CommitLegacy(destBlockAndPvtdata, &ledger.CommitOptions{}))
+	}
+
+	// the private data of the hashes in the snapshot is asked for by the reconciler
+	verifyMissingPvtdata(1, 2)
+	require.Equal(t, 1, membershipInfoProvider.AmMemberOfCallCount())
+	verifyPvtdataHashes(map[string][]byte{
+		"key1": util.ComputeSHA256([]byte("pvtValue1.1")),
+		"key2": util.ComputeSHA256([]byte("pvtValue2.2")),
+	})
+
+	// the hashes committed by block 1 expire at block 4 and the ones committed by block 2 at block 5
+	commitNextBlock("SimulateForBlk3")
+	verifyMissingPvtdata(1, 2)
+	commitNextBlock("SimulateForBlk4")
+	verifyMissingPvtdata(2)
+	verifyPvtdataHashes(map[string][]byte{
+		"key1": nil,
+		"key2": util.ComputeSHA256([]byte("pvtValue2.2")),
+	})
+	commitNextBlock("SimulateForBlk5")
+	verifyMissingPvtdata()
+	verifyPvtdataHashes(map[string][]byte{
+		"key1": nil,
+		"key2": nil,
+	})
+}
+
+func TestCreateFromSnapshotErrors(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	blockAndPvtdata1 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata1, &ledger.CommitOptions{}))
+	require.NoError(t, kvlgr.generateSnapshot())
+	snapshotDir := SnapshotDirForLedgerHeight(conf.SnapshotsConfig.RootDir, kvlgr.ledgerID, 2)
+
+	// copySnapshot copies the snapshot into a new dir and applies the given modification to one of the files
+	copySnapshot := func(t *testing.T, fileToModify string, modify func(content []byte) []byte) string {
+		destDir, err := ioutil.TempDir("", "snapshotcopy")
+		require.NoError(t, err)
+		files, err := ioutil.ReadDir(snapshotDir)
+		require.NoError(t, err)
+		for _, f := range files {
+			content, err := ioutil.ReadFile(filepath.Join(snapshotDir, f.Name()))
+			require.NoError(t, err)
+			if f.Name() == fileToModify {
+				content = modify(content)
+			}
+			if content != nil {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(destDir, f.Name()), content, 0644))
+			}
+		}
+		return destDir
+	}
+
+	testCases := []struct {
+		name          string
+		fileToModify  string
+		modify        func([]byte) []byte
+		expectedError string
+	}{
+		{
+			name:          "missing-metadata-file",
+			fileToModify:  snapshotMetadataFileName,
+			modify:        func([]byte) []byte { return nil },
+			expectedError: "error while reading the snapshot metadata file from dir",
+		},
+		{
+			name:          "missing-additional-info-file",
+			fileToModify:  snapshotMetadataHashFileName,
+			modify:        func([]byte) []byte { return nil },
+			expectedError: "error while reading the snapshot additional info file from dir",
+		},
+		{
+			name:          "corrupted-metadata-file",
+			fileToModify:  snapshotMetadataFileName,
+			modify:        func([]byte) []byte { return []byte("not-a-json") },
+			expectedError: "error while unmarshalling snapshot metadata from JSON",
+		},
+		{
+			name:         "tampered-metadata-file",
+			fileToModify: snapshotMetadataFileName,
+			modify: func(content []byte) []byte {
+				return []byte(strings.Replace(string(content), `"channel_height": 2`, `"channel_height": 20`, 1))
+			},
+			expectedError: "hash mismatch for the snapshot metadata file [_snapshot_signable_metadata.json]",
+		},
+		{
+			name:          "tampered-data-file",
+			fileToModify:  "public_state.data",
+			modify:        func(content []byte) []byte { return append(content, byte(0)) },
+			expectedError: "hash mismatch for the snapshot file",
+		},
+		{
+			name:          "missing-data-file",
+			fileToModify:  "txids.data",
+			modify:        func([]byte) []byte { return nil },
+			expectedError: "error while opening the snapshot file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := copySnapshot(t, tc.fileToModify, tc.modify)
+			defer os.RemoveAll(dir)
+			destConf, destCleanup := testConfig(t)
+			defer destCleanup()
+			destProvider := testutilNewProvider(destConf, t, &mock.DeployedChaincodeInfoProvider{})
+			defer destProvider.Close()
+
+			_, _, err := destProvider.CreateFromSnapshot(dir)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedError)
+			exists, err := destProvider.Exists("testLedgerid")
+			require.NoError(t, err)
+			require.False(t, exists)
+		})
+	}
+
+	t.Run("ledger-id-exists", func(t *testing.T) {
+		_, _, err := provider.CreateFromSnapshot(snapshotDir)
+		require.Equal(t, ErrLedgerIDExists, err)
+	})
+}
+
+func TestCreateFromSnapshotCrashRecovery(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	snapshotDir, _ := createLedgerAndSnapshotForTest(t, provider, conf)
+
+	destConf, destCleanup := testConfig(t)
+	defer destCleanup()
+	destProvider := testutilNewProvider(destConf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer func() {
+		destProvider.Close()
+	}()
+
+	// simulate a crash after the ledger is bootstrapped from the snapshot and before it is added to the created ledgers list
+	metadataJSONs, err := loadSnapshotMetadataJSONs(snapshotDir)
+	require.NoError(t, err)
+	metadata, err := metadataJSONs.toMetadata()
+	require.NoError(t, err)
+	require.NoError(t, destProvider.idStore.markLedgerUnderConstruction("testLedgerid", &msgs.BootSnapshotMetadata{}))
+	lgr, err := destProvider.bootstrapFromSnapshot(snapshotDir, metadata)
+	require.NoError(t, err)
+	lgr.Close()
+
+	// an under construction ledger exists, but is not listed and cannot be created again
+	exists, err := destProvider.Exists("testLedgerid")
+	require.NoError(t, err)
+	require.True(t, exists)
+	ledgerIDs, err := destProvider.List()
+	require.NoError(t, err)
+	require.Empty(t, ledgerIDs)
+	_, _, err = destProvider.CreateFromSnapshot(snapshotDir)
+	require.Equal(t, ErrLedgerIDExists, err)
+	destProvider.Close()
+
+	// the under construction ledger is dropped at the next start of the provider
+	destProvider = testutilNewProvider(destConf, t, &mock.DeployedChaincodeInfoProvider{})
+	exists, err = destProvider.Exists("testLedgerid")
+	require.NoError(t, err)
+	require.False(t, exists)
+	underConstructionIDs, err := destProvider.idStore.getUnderConstructionLedgerIDs()
+	require.NoError(t, err)
+	require.Empty(t, underConstructionIDs)
+	blkStoreExists, err := destProvider.blkStoreProvider.Exists("testLedgerid")
+	require.NoError(t, err)
+	require.False(t, blkStoreExists)
+
+	// the ledger can be created from the snapshot again
+	destLgr, ledgerID, err := destProvider.CreateFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	defer destLgr.Close()
+	require.Equal(t, "testLedgerid", ledgerID)
+	bcInfo, err := destLgr.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), bcInfo.Height)
+	qe, err := destLgr.NewQueryExecutor()
+	require.NoError(t, err)
+	defer qe.Done()
+	val, err := qe.GetState("ns", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1.1"), val)
+}
+
+func TestLedgerCreatedFromSnapshotWithEmptyLedgerKeyValue(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	snapshotDir, genesisBlk := createLedgerAndSnapshotForTest(t, provider, conf)
+
+	destConf, destCleanup := testConfig(t)
+	defer destCleanup()
+	destProvider := testutilNewProvider(destConf, t, &mock.DeployedChaincodeInfoProvider{})
+	destLgr, _, err := destProvider.CreateFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	destLgr.Close()
+
+	// the ledger key of a ledger created from a snapshot carries an empty value, as no genesis block is available
+	val, err := destProvider.idStore.db.Get(destProvider.idStore.encodeLedgerKey("testLedgerid", ledgerKeyPrefix))
+	require.NoError(t, err)
+	require.Empty(t, val)
+
+	exists, err := destProvider.Exists("testLedgerid")
+	require.NoError(t, err)
+	require.True(t, exists)
+	_, err = destProvider.Create(genesisBlk)
+	require.Equal(t, ErrLedgerIDExists, err)
+	_, _, err = destProvider.CreateFromSnapshot(snapshotDir)
+	require.Equal(t, ErrLedgerIDExists, err)
+	destProvider.Close()
+
+	// the ledger can be paused and resumed
+	require.NoError(t, PauseChannel(destConf.RootFSPath, "testLedgerid"))
+	destProvider = testutilNewProvider(destConf, t, &mock.DeployedChaincodeInfoProvider{})
+	exists, err = destProvider.Exists("testLedgerid")
+	require.NoError(t, err)
+	require.True(t, exists)
+	_, err = destProvider.Open("testLedgerid")
+	require.Equal(t, ErrInactiveLedger, err)
+	destProvider.Close()
+
+	require.NoError(t, ResumeChannel(destConf.RootFSPath, "testLedgerid"))
+	destProvider = testutilNewProvider(destConf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer destProvider.Close()
+	destLgr, err = destProvider.Open("testLedgerid")
+	require.NoError(t, err)
+	defer destLgr.Close()
+	require.NotNil(t, destLgr.(*kvLedger).bootSnapshotMetadata)
+}
+
+// createLedgerAndSnapshotForTest creates a ledger with the id "testLedgerid" and a block on top of the genesis block
+// and generates a snapshot of the ledger
+func createLedgerAndSnapshotForTest(t *testing.T, provider *Provider, conf *lgr.Config) (string, *common.Block) {
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	blockAndPvtdata1 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata1, &ledger.CommitOptions{}))
+	require.NoError(t, kvlgr.generateSnapshot())
+	return SnapshotDirForLedgerHeight(conf.SnapshotsConfig.RootDir, kvlgr.ledgerID, 2), genesisBlk
+}
@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/pkg/errors"
+)
+
+var (
+	stateCommitmentKey        = []byte{'s'}
+	stateCheckpointKeyPrefix  = []byte{'c'}
+	stateCheckpointKeysEndKey = []byte{'c' + 1}
+)
+
+// stateCheckpointMgr maintains a rolling commitment over the state of a ledger and records the commitment as a state
+// checkpoint when a block with a number that is a multiple of the configured interval is committed. The commitment is
+// rolled forward with the updates of each block, just before the updates are applied to the state database, and it is
+// persisted in the bookkeeping along with the height of the state that it represents. If the persisted commitment does
+// not match the savepoint of the state database, for instance, after a crash or after the state database is rebuilt,
+// the commitment is recomputed over the entire state
+type stateCheckpointMgr struct {
+	ledgerID   string
+	interval   uint64
+	db         *privacyenabledstate.DB
+	dbHandle   *leveldbhelper.DBHandle
+	commitment *privacyenabledstate.StateCommitment
+}
+
+func newStateCheckpointMgr(
+	ledgerID string,
+	config *ledger.StateCheckpointsConfig,
+	db *privacyenabledstate.DB,
+	dbHandle *leveldbhelper.DBHandle,
+) *stateCheckpointMgr {
+	m := &stateCheckpointMgr{
+		ledgerID: ledgerID,
+		db:       db,
+		dbHandle: dbHandle,
+	}
+	if config != nil {
+		m.interval = config.Interval
+	}
+	return m
+}
+
+func (m *stateCheckpointMgr) enabled() bool {
+	return m.interval > 0
+}
+
+// init loads the persisted commitment, or recomputes the commitment if the persisted one does not match the savepoint of
+// the state database. The checkpoints above the savepoint, which may be present after a rollback, are removed. This is
+// expected to be invoked after the state database is opened and before any block is committed
+func (m *stateCheckpointMgr) init() error {
+	if !m.enabled() {
+		return nil
+	}
+	savepoint, err := m.db.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+
+	commitment, commitmentHeight, err := m.loadCommitment()
+	if err != nil {
+		return err
+	}
+
+	batch := m.dbHandle.NewUpdateBatch()
+	switch {
+	case savepoint == nil:
+		commitment = privacyenabledstate.NewStateCommitment()
+		batch.Delete(stateCommitmentKey)
+	case commitment == nil || savepoint.Compare(commitmentHeight) != 0:
+		logger.Infof("[%s] Computing the state commitment at savepoint [%s]", m.ledgerID, savepoint)
+		if commitment, err = m.db.ComputeStateCommitment(); err != nil {
+			return err
+		}
+		batch.Put(stateCommitmentKey, encodeStateCommitment(commitment, savepoint))
+		if savepoint.BlockNum%m.interval == 0 {
+			batch.Put(encodeStateCheckpointKey(savepoint.BlockNum), commitment.Hash())
+		}
+	}
+
+	itr, err := m.dbHandle.GetIterator(stateCheckpointKeyPrefix, stateCheckpointKeysEndKey)
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		blockNum, err := decodeStateCheckpointKey(itr.Key())
+		if err != nil {
+			return err
+		}
+		if savepoint == nil || blockNum > savepoint.BlockNum {
+			batch.Delete(encodeStateCheckpointKey(blockNum))
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while iterating over the state checkpoints")
+	}
+
+	if err := m.dbHandle.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	m.commitment = commitment
+	return nil
+}
+
+// HandleUpdates implements the function in the interface txmgr.UpdatesObserver. It rolls the commitment forward
+// with the updates of a block and records a checkpoint if the number of the block is a multiple of the interval
+func (m *stateCheckpointMgr) HandleUpdates(updates *privacyenabledstate.UpdateBatch, height *version.Height) error {
+	commitment, err := m.db.UpdateStateCommitment(m.commitment, updates)
+	if err != nil {
+		return err
+	}
+	batch := m.dbHandle.NewUpdateBatch()
+	batch.Put(stateCommitmentKey, encodeStateCommitment(commitment, height))
+	isCheckpoint := height.BlockNum%m.interval == 0
+	if isCheckpoint {
+		batch.Put(encodeStateCheckpointKey(height.BlockNum), commitment.Hash())
+	}
+	// a loss of the commitment in a crash is detected at the restart, whereas a lost checkpoint cannot be recovered
+	// after the subsequent blocks are committed; hence, only the batches that carry a checkpoint are synced
+	if err := m.dbHandle.WriteBatch(batch, isCheckpoint); err != nil {
+		return err
+	}
+	m.commitment = commitment
+	if isCheckpoint {
+		logger.Infof("[%s] Recorded state checkpoint for block [%d], commitment=[%x]", m.ledgerID, height.BlockNum, commitment.Hash())
+	}
+	return nil
+}
+
+func (m *stateCheckpointMgr) checkpoint(blockNum uint64) (*ledger.StateCheckpoint, error) {
+	commitment, err := m.dbHandle.Get(encodeStateCheckpointKey(blockNum))
+	if err != nil || commitment == nil {
+		return nil, err
+	}
+	return &ledger.StateCheckpoint{
+		BlockNumber: blockNum,
+		Commitment:  commitment,
+	}, nil
+}
+
+func (m *stateCheckpointMgr) latestCheckpoint() (*ledger.StateCheckpoint, error) {
+	itr, err := m.dbHandle.GetIterator(stateCheckpointKeyPrefix, stateCheckpointKeysEndKey)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	if !itr.Last() {
+		return nil, errors.Wrap(itr.Error(), "internal leveldb error while iterating over the state checkpoints")
+	}
+	blockNum, err := decodeStateCheckpointKey(itr.Key())
+	if err != nil {
+		return nil, err
+	}
+	return &ledger.StateCheckpoint{
+		BlockNumber: blockNum,
+		Commitment:  append([]byte(nil), itr.Value()...),
+	}, nil
+}
+
+func (m *stateCheckpointMgr) loadCommitment() (*privacyenabledstate.StateCommitment, *version.Height, error) {
+	b, err := m.dbHandle.Get(stateCommitmentKey)
+	if err != nil || b == nil {
+		return nil, nil, err
+	}
+	return decodeStateCommitment(b)
+}
+
+// GetStateCheckpoint implements the corresponding method in interface ledger.PeerLedger
+func (l *kvLedger) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	return l.stateCheckpointMgr.checkpoint(blockNumber)
+}
+
+// GetLatestStateCheckpoint implements the corresponding method in interface ledger.PeerLedger
+func (l *kvLedger) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	return l.stateCheckpointMgr.latestCheckpoint()
+}
+
+func encodeStateCommitment(commitment *privacyenabledstate.StateCommitment, height *version.Height) []byte {
+	commitmentBytes := commitment.ToBytes()
+	return append(util.EncodeOrderPreservingVarUint64(uint64(len(commitmentBytes))), append(commitmentBytes, height.ToBytes()...)...)
+}
+
+func decodeStateCommitment(b []byte) (*privacyenabledstate.StateCommitment, *version.Height, error) {
+	commitmentLen, n, err := util.DecodeOrderPreservingVarUint64(b)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error while decoding the persisted state commitment")
+	}
+	if uint64(len(b)-n) < commitmentLen {
+		return nil, nil, errors.New("the persisted state commitment is truncated")
+	}
+	commitment, err := privacyenabledstate.NewStateCommitmentFromBytes(b[n : n+int(commitmentLen)])
+	if err != nil {
+		return nil, nil, err
+	}
+	height, _, err := version.NewHeightFromBytes(b[n+int(commitmentLen):])
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error while decoding the height of the persisted state commitment")
+	}
+	return commitment, height, nil
+}
+
+func encodeStateCheckpointKey(blockNum uint64) []byte {
+	return append(append([]byte(nil), stateCheckpointKeyPrefix...), util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+func decodeStateCheckpointKey(key []byte) (uint64, error) {
+	if !bytes.HasPrefix(key, stateCheckpointKeyPrefix) {
+		return 0, errors.Errorf("unexpected state checkpoint key [%x]", key)
+	}
+	blockNum, _, err := util.DecodeOrderPreservingVarUint64(key[len(stateCheckpointKeyPrefix):])
+	return blockNum, err
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateCheckpoints(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	conf.StateCheckpointsConfig = &lgr.StateCheckpointsConfig{Interval: 2}
+	provider := testutilNewProviderWithCollectionConfig(
+		t,
+		[]*nsCollBtlConfig{
+			{
+				namespace: "ns",
+				btlConfig: map[string]uint64{"coll": 0},
+			},
+		},
+		conf,
+	)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	kvl := l.(*kvLedger)
+
+	commitBlock := func(l lgr.PeerLedger, blockNum int) {
+		blkAndPvtdata := prepareNextBlockForTest(t, l, bg, util.GenerateUUID(),
+			map[string]string{
+				"key1":                          fmt.Sprintf("value1-%d", blockNum),
+				fmt.Sprintf("key-%d", blockNum): "value",
+			},
+			map[string]string{
+				"pvtkey1": fmt.Sprintf("pvtvalue1-%d", blockNum),
+			},
+		)
+		require.NoError(t, l.CommitLegacy(blkAndPvtdata, &lgr.CommitOptions{}))
+	}
+
+	verifyCheckpoint := func(kvl *kvLedger, blockNum uint64) {
+		checkpoint, err := kvl.GetStateCheckpoint(blockNum)
+		require.NoError(t, err)
+		commitment, err := kvl.stateCheckpointMgr.db.ComputeStateCommitment()
+		require.NoError(t, err)
+		require.Equal(t,
+			&lgr.StateCheckpoint{
+				BlockNumber: blockNum,
+				Commitment:  commitment.Hash(),
+			},
+			checkpoint,
+		)
+		latestCheckpoint, err := kvl.GetLatestStateCheckpoint()
+		require.NoError(t, err)
+		require.Equal(t, checkpoint, latestCheckpoint)
+	}
+
+	for i := 1; i <= 4; i++ {
+		commitBlock(kvl, i)
+	}
+	verifyCheckpoint(kvl, 4)
+	checkpoint, err := kvl.GetStateCheckpoint(3)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+	checkpoint2, err := kvl.GetStateCheckpoint(2)
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint2)
+	require.NotEqual(t, checkpoint2.Commitment, kvl.stateCheckpointMgr.commitment.Hash())
+
+	t.Run("commitment-continues-after-reopen", func(t *testing.T) {
+		kvl.Close()
+		l, err := provider.Open("testLedger")
+		require.NoError(t, err)
+		kvl = l.(*kvLedger)
+		commitBlock(kvl, 5)
+		commitBlock(kvl, 6)
+		verifyCheckpoint(kvl, 6)
+	})
+
+	t.Run("commitment-is-recomputed-when-not-in-sync", func(t *testing.T) {
+		commitBlock(kvl, 7)
+		kvl.Close()
+		dbHandle := provider.bookkeepingProvider.GetDBHandle("testLedger", bookkeeping.StateCheckpoint)
+		require.NoError(t, dbHandle.Delete(stateCommitmentKey, true))
+
+		l, err := provider.Open("testLedger")
+		require.NoError(t, err)
+		kvl = l.(*kvLedger)
+		commitBlock(kvl, 8)
+		verifyCheckpoint(kvl, 8)
+	})
+
+	t.Run("checkpoints-above-savepoint-are-removed", func(t *testing.T) {
+		kvl.Close()
+		dbHandle := provider.bookkeepingProvider.GetDBHandle("testLedger", bookkeeping.StateCheckpoint)
+		require.NoError(t, dbHandle.Put(encodeStateCheckpointKey(10), []byte("stale-checkpoint"), true))
+
+		l, err := provider.Open("testLedger")
+		require.NoError(t, err)
+		kvl = l.(*kvLedger)
+		verifyCheckpoint(kvl, 8)
+		kvl.Close()
+	})
+}
+
+func TestStateCheckpointsDisabled(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer l.Close()
+
+	blkAndPvtdata := prepareNextBlockForTest(t, l, bg, util.GenerateUUID(), map[string]string{"key1": "value1"}, nil)
+	require.NoError(t, l.CommitLegacy(blkAndPvtdata, &lgr.CommitOptions{}))
+
+	checkpoint, err := l.GetLatestStateCheckpoint()
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+}
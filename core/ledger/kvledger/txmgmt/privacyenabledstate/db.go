@@ -8,6 +8,8 @@ package privacyenabledstate
 
 import (
 	"encoding/base64"
+	"net/http"
+	"path/filepath"
 	"strings"
 
 	"github.com/hyperledger/fabric-lib-go/healthz"
@@ -31,7 +33,6 @@ const (
 	nsJoiner       = "$$"
 	pvtDataPrefix  = "p"
 	hashDataPrefix = "h"
-	couchDB        = "CouchDB"
 )
 
 // StateDBConfig encapsulates the configuration for stateDB on the ledger.
@@ -41,6 +42,7 @@ type StateDBConfig struct {
 	// LevelDBPath is the filesystem path when statedb type is "goleveldb".
 	// It is internally computed by the ledger component,
 	// so it is not in ledger.StateDBConfig and not exposed to other components.
+	// The same path is offered to a registered state database for maintaining local files, if any.
 	LevelDBPath string
 }
 
@@ -61,17 +63,9 @@ func NewDBProvider(
 	sysNamespaces []string,
 ) (*DBProvider, error) {
 
-	var vdbProvider statedb.VersionedDBProvider
-	var err error
-
-	if stateDBConf != nil && stateDBConf.StateDatabase == couchDB {
-		if vdbProvider, err = statecouchdb.NewVersionedDBProvider(stateDBConf.CouchDB, metricsProvider, sysNamespaces); err != nil {
-			return nil, err
-		}
-	} else {
-		if vdbProvider, err = stateleveldb.NewVersionedDBProvider(stateDBConf.LevelDBPath); err != nil {
-			return nil, err
-		}
+	vdbProvider, err := newVersionedDBProvider(stateDBConf, metricsProvider, sysNamespaces)
+	if err != nil {
+		return nil, err
 	}
 
 	dbProvider := &DBProvider{vdbProvider, healthCheckRegistry, bookkeeperProvider}
@@ -84,6 +78,35 @@ func NewDBProvider(
 	return dbProvider, nil
 }
 
+// newVersionedDBProvider constructs the VersionedDBProvider for the configured state database. Apart from the
+// built-in goleveldb and CouchDB, a state database that is registered via statedb.RegisterVersionedDBProviderFactory
+// can be configured by the name under which it is registered
+func newVersionedDBProvider(
+	stateDBConf *StateDBConfig,
+	metricsProvider metrics.Provider,
+	sysNamespaces []string,
+) (statedb.VersionedDBProvider, error) {
+	switch stateDBConf.StateDatabase {
+	case statedb.CouchDB:
+		return statecouchdb.NewVersionedDBProvider(stateDBConf.CouchDB, metricsProvider, sysNamespaces)
+	case statedb.GoLevelDB, "":
+		return stateleveldb.NewVersionedDBProvider(stateDBConf.LevelDBPath)
+	default:
+		factory, ok := statedb.GetVersionedDBProviderFactory(stateDBConf.StateDatabase)
+		if !ok {
+			return nil, errors.Errorf("unsupported state database [%s]", stateDBConf.StateDatabase)
+		}
+		return factory(
+			&statedb.VersionedDBProviderConfig{
+				DBPath:          stateDBConf.LevelDBPath,
+				PluginConfig:    stateDBConf.PluginConfig,
+				MetricsProvider: metricsProvider,
+				SysNamespaces:   sysNamespaces,
+			},
+		)
+	}
+}
+
 // RegisterHealthChecker registers the underlying stateDB with the healthChecker.
 // For now, we register only the CouchDB as it runs as a separate process but not
 // for the GoLevelDB as it is an embedded database.
@@ -94,6 +117,19 @@ func (p *DBProvider) RegisterHealthChecker() error {
 	return nil
 }
 
+// queryPlanReporter is implemented by the stateDB that reports the query plans of the rich queries
+type queryPlanReporter interface {
+	QueryPlanReportsHandler() http.Handler
+}
+
+// RegisterOperationsHandlers registers the handlers that the underlying stateDB serves on the operations
+// endpoint of the peer. For now, only the CouchDB serves the reports of the query plans of the rich queries.
+func (p *DBProvider) RegisterOperationsHandlers(registry ledger.OperationsHandlerRegistry) {
+	if reporter, ok := p.VersionedDBProvider.(queryPlanReporter); ok {
+		registry.RegisterHandler("/couchdb/queryplans", reporter.QueryPlanReportsHandler())
+	}
+}
+
 // GetDBHandle gets a handle to DB for a given id, i.e., a channel
 func (p *DBProvider) GetDBHandle(id string, chInfoProvider channelInfoProvider) (*DB, error) {
 	vdb, err := p.VersionedDBProvider.GetDBHandle(id, &namespaceProvider{chInfoProvider})
@@ -108,6 +144,17 @@ func (p *DBProvider) GetDBHandle(id string, chInfoProvider channelInfoProvider)
 	return NewDB(vdb, id, metadataHint)
 }
 
+// Drop drops the VersionedDB for a given id, i.e., a channel. It returns an error if the underlying stateDB
+// cannot drop the data of a channel, which is the case for a registered state database that does not
+// implement the interface statedb.Droppable
+func (p *DBProvider) Drop(id string) error {
+	droppable, ok := p.VersionedDBProvider.(statedb.Droppable)
+	if !ok {
+		return errors.Errorf("the state database does not support dropping the statedb for channel [%s]", id)
+	}
+	return droppable.Drop(id)
+}
+
 // Close closes all the VersionedDB instances and releases any resources held by VersionedDBProvider
 func (p *DBProvider) Close() {
 	p.VersionedDBProvider.Close()
@@ -223,6 +270,35 @@ func (s *DB) GetCachedKeyHashVersion(namespace, collection string, keyHash []byt
 	return bulkOptimizable.GetCachedVersion(deriveHashedDataNs(namespace, collection), keyHashStr)
 }
 
+// ForEachValueHash invokes the given function with the key hash and the versioned value hash of each of
+// the private data hashes of the collection <namespace, collection>
+func (s *DB) ForEachValueHash(namespace, collection string, f func(keyHash []byte, vv *statedb.VersionedValue) error) error {
+	itr, err := s.GetStateRangeScanIterator(deriveHashedDataNs(namespace, collection), "", "")
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for {
+		queryResult, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if queryResult == nil {
+			return nil
+		}
+		kv := queryResult.(*statedb.VersionedKV)
+		keyHash := []byte(kv.Key)
+		if !s.BytesKeySupported() {
+			if keyHash, err = base64.StdEncoding.DecodeString(kv.Key); err != nil {
+				return errors.Wrapf(err, "error while decoding the key hash [%s] of collection [%s:%s]", kv.Key, namespace, collection)
+			}
+		}
+		if err := f(keyHash, &kv.VersionedValue); err != nil {
+			return err
+		}
+	}
+}
+
 // GetPrivateDataMultipleKeys gets the values for the multiple private data items in a single call
 func (s *DB) GetPrivateDataMultipleKeys(namespace, collection string, keys []string) ([]*statedb.VersionedValue, error) {
 	return s.GetStateMultipleKeys(derivePvtDataNs(namespace, collection), keys)
@@ -312,6 +388,12 @@ func (s *DB) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt.ChaincodeDef
 		return nil
 	}
 
+	// the partitioning determines the layout of the database of a namespace and hence, it is processed
+	// before the indexes, as the processing of the indexes creates the database of the namespace
+	if partitionCapable, ok := s.VersionedDB.(statedb.PartitionCapable); ok {
+		processPartitioning(partitionCapable, chaincodeDefinition.Name, collectionConfigMap, dbArtifacts)
+	}
+
 	for directoryPath, indexFiles := range dbArtifacts {
 		indexFilesData := make(map[string][]byte)
 		for _, f := range indexFiles {
@@ -341,6 +423,37 @@ func (s *DB) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt.ChaincodeDef
 	return nil
 }
 
+// processPartitioning processes the partitioning declared for the chaincode and its collections. Similar to the
+// indexes, the errors are logged and suppressed, in which case the database of the namespace is not partitioned
+func processPartitioning(partitionCapable statedb.PartitionCapable, chaincodeName string,
+	collectionConfigMap map[string]bool, dbArtifacts map[string][]*ccprovider.TarFileEntry) {
+	for directoryPath, files := range dbArtifacts {
+		indexInfo := getIndexInfo(directoryPath)
+		var namespace string
+		switch {
+		case indexInfo.hasPartitioningForChaincode:
+			namespace = chaincodeName
+		case indexInfo.hasPartitioningForCollection:
+			if !collectionConfigMap[indexInfo.collectionName] {
+				logger.Errorf("Error processing partitioning for chaincode [%s]: cannot partition an undefined collection=[%s]",
+					chaincodeName, indexInfo.collectionName)
+				continue
+			}
+			namespace = derivePvtDataNs(chaincodeName, indexInfo.collectionName)
+		default:
+			continue
+		}
+		for _, f := range files {
+			if filepath.Base(f.FileHeader.Name) != partitioningFileName {
+				continue
+			}
+			if err := partitionCapable.ProcessPartitioningForChaincodeDeploy(namespace, f.FileContent); err != nil {
+				logger.Errorf("Error processing partitioning file [%s] for chaincode [%s]: %s", f.FileHeader.Name, chaincodeName, err)
+			}
+		}
+	}
+}
+
 // ChaincodeDeployDone is a noop for couchdb state impl
 func (s *DB) ChaincodeDeployDone(succeeded bool) {
 	// NOOP
@@ -401,9 +514,11 @@ func extractCollectionNames(chaincodeDefinition *cceventmgmt.ChaincodeDefinition
 }
 
 type indexInfo struct {
-	hasIndexForChaincode  bool
-	hasIndexForCollection bool
-	collectionName        string
+	hasIndexForChaincode         bool
+	hasIndexForCollection        bool
+	hasPartitioningForChaincode  bool
+	hasPartitioningForCollection bool
+	collectionName               string
 }
 
 const (
@@ -416,6 +531,11 @@ const (
 	collectionDirDepth      = 3
 	collectionNameDepth     = 4
 	collectionIndexDirDepth = 5
+
+	// Example for chaincode and collection scoped partitioning:
+	// "META-INF/statedb/couchdb/partitioning/partitioning.json"
+	// "META-INF/statedb/couchdb/collections/collectionMarbles/partitioning/partitioning.json"
+	partitioningFileName = "partitioning.json"
 )
 
 // Note previous functions will have ensured that the path starts
@@ -432,6 +552,11 @@ func getIndexInfo(indexPath string) *indexInfo {
 	case pathDepth > collectionIndexDirDepth && pathParts[collectionDirDepth] == "collections" && pathParts[collectionIndexDirDepth] == "indexes":
 		indexInfo.hasIndexForCollection = true
 		indexInfo.collectionName = pathParts[collectionNameDepth]
+	case pathDepth > chaincodeIndexDirDepth && pathParts[chaincodeIndexDirDepth] == "partitioning":
+		indexInfo.hasPartitioningForChaincode = true
+	case pathDepth > collectionIndexDirDepth && pathParts[collectionDirDepth] == "collections" && pathParts[collectionIndexDirDepth] == "partitioning":
+		indexInfo.hasPartitioningForCollection = true
+		indexInfo.collectionName = pathParts[collectionNameDepth]
 	}
 	return indexInfo
 }
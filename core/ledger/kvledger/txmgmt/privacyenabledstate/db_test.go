@@ -87,6 +87,13 @@ func TestGetIndexInfo(t *testing.T) {
 	incorrectIndexPath := "META-INF/statedb"
 	actualIndexInfo = getIndexInfo(incorrectIndexPath)
 	require.Equal(t, expectedIndexInfo, actualIndexInfo)
+	chaincodePartitioningPath := "META-INF/statedb/couchdb/partitioning"
+	actualIndexInfo = getIndexInfo(chaincodePartitioningPath)
+	require.Equal(t, &indexInfo{hasPartitioningForChaincode: true}, actualIndexInfo)
+
+	collectionPartitioningPath := "META-INF/statedb/couchdb/collections/collectionMarbles/partitioning"
+	actualIndexInfo = getIndexInfo(collectionPartitioningPath)
+	require.Equal(t, &indexInfo{hasPartitioningForCollection: true, collectionName: "collectionMarbles"}, actualIndexInfo)
 }
 
 func TestDB(t *testing.T) {
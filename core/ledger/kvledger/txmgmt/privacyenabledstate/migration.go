@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"encoding/base64"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+// maxKeysPerMigrationBatch is the maximum number of keys that are read from the source DB and
+// written to the target DB in a single batch during the migration
+const maxKeysPerMigrationBatch = 1000
+
+// MigrateTo copies the public data, the private data, and the hashes of the private data, along with the metadata
+// and the versions, to the target DB and, in the end, sets the savepoint of the target DB to the savepoint of this DB.
+// The target DB is expected to be empty and may be backed by a different type of state database; for instance,
+// the keys of the hashed data are converted if only one of the two databases supports bytes as keys.
+// The function returns the digest of the data that is copied, which is expected to be the same as the digest
+// returned by the function ComputeDigest on the target DB, after the migration
+func (s *DB) MigrateTo(target *DB) ([]byte, error) {
+	savepoint, err := s.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	if savepoint == nil {
+		return nil, errors.New("the source statedb is empty")
+	}
+	targetSavepoint, err := target.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	if targetSavepoint != nil {
+		return nil, errors.Errorf("the target statedb is not empty, it has the savepoint [%s]", targetSavepoint)
+	}
+
+	digest := newStateDigest()
+	err = s.scanState(noNamespaceSkipped, func(kvs []*statedb.VersionedKV) error {
+		batch := statedb.NewUpdateBatch()
+		for _, kv := range kvs {
+			digest.add(kv)
+			key := kv.Key
+			if isHashedDataNs(kv.Namespace) && !target.BytesKeySupported() {
+				key = base64.StdEncoding.EncodeToString([]byte(key))
+			}
+			if err := target.ValidateKeyValue(key, kv.Value); err != nil {
+				return errors.WithMessagef(err, "key [%s] in namespace [%s] cannot be migrated", key, kv.Namespace)
+			}
+			vv := kv.VersionedValue
+			batch.Update(kv.Namespace, key, &vv)
+		}
+		// the savepoint is not set until all the data is copied so that an incomplete
+		// migration can be detected via a missing savepoint
+		return target.VersionedDB.ApplyUpdates(batch, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := target.VersionedDB.ApplyUpdates(statedb.NewUpdateBatch(), savepoint); err != nil {
+		return nil, err
+	}
+	return digest.bytes(), nil
+}
+
+// ComputeDigest computes a digest of all the data, including the private data, the hashes of the private data,
+// the metadata, and the versions. The digest does not depend on the type of the underlying state database
+// and hence can be used for comparing the data maintained in two different types of state databases
+func (s *DB) ComputeDigest() ([]byte, error) {
+	digest := newStateDigest()
+	err := s.scanState(noNamespaceSkipped, func(kvs []*statedb.VersionedKV) error {
+		for _, kv := range kvs {
+			digest.add(kv)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return digest.bytes(), nil
+}
+
+// scanState invokes the function process on batches of the data in the db, excluding the namespaces for which the function
+// skipNamespace returns true. The keys in a batch belong to a single namespace.
+// The keys of the hashed data are passed as the raw hashes, irrespective of the encoding used by the underlying db.
+// As the value format of the FullScanIterator differs across the types of state databases, the FullScanIterator is used
+// only for enumerating the keys and the values are loaded via the function GetStateMultipleKeys
+func (s *DB) scanState(skipNamespace func(string) bool, process func(kvs []*statedb.VersionedKV) error) error {
+	itr, _, err := s.GetFullScanIterator(skipNamespace)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	var namespace string
+	var keys []string
+
+	processKeys := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		vvs, err := s.GetStateMultipleKeys(namespace, keys)
+		if err != nil {
+			return err
+		}
+		kvs := make([]*statedb.VersionedKV, 0, len(keys))
+		for i, vv := range vvs {
+			key := keys[i]
+			if vv == nil {
+				return errors.Errorf("key [%s] in namespace [%s] is returned by the full scan but not found in the statedb", key, namespace)
+			}
+			if isHashedDataNs(namespace) && !s.BytesKeySupported() {
+				keyHash, err := base64.StdEncoding.DecodeString(key)
+				if err != nil {
+					return errors.Wrapf(err, "error while decoding the key hash [%s] in namespace [%s]", key, namespace)
+				}
+				key = string(keyHash)
+			}
+			kvs = append(kvs, &statedb.VersionedKV{
+				CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+				VersionedValue: *vv,
+			})
+		}
+		keys = nil
+		return process(kvs)
+	}
+
+	for {
+		compositeKey, _, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if compositeKey == nil {
+			break
+		}
+		if compositeKey.Namespace != namespace || len(keys) == maxKeysPerMigrationBatch {
+			if err := processKeys(); err != nil {
+				return err
+			}
+			namespace = compositeKey.Namespace
+		}
+		keys = append(keys, compositeKey.Key)
+	}
+	return processKeys()
+}
+
+func noNamespaceSkipped(string) bool {
+	return false
+}
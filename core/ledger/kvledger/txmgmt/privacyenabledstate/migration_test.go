@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateTo(t *testing.T) {
+	testCases := []struct {
+		sourceEnv TestEnv
+		targetEnv TestEnv
+	}{
+		{&LevelDBTestEnv{}, &CouchDBTestEnv{}},
+		{&CouchDBTestEnv{}, &LevelDBTestEnv{}},
+		{&LevelDBTestEnv{}, &LevelDBTestEnv{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s-to-%s", tc.sourceEnv.GetName(), tc.targetEnv.GetName()), func(t *testing.T) {
+			testMigrateTo(t, tc.sourceEnv, tc.targetEnv)
+		})
+	}
+}
+
+func testMigrateTo(t *testing.T, sourceEnv, targetEnv TestEnv) {
+	sourceEnv.Init(t)
+	defer sourceEnv.StopExternalResource()
+	defer sourceEnv.Cleanup()
+	targetEnv.Init(t)
+	defer targetEnv.StopExternalResource()
+	defer targetEnv.Cleanup()
+
+	ledgerID := generateLedgerID(t)
+	sourceDB := sourceEnv.GetDBHandle(ledgerID)
+	targetDB := targetEnv.GetDBHandle(ledgerID)
+
+	t.Run("empty-source", func(t *testing.T) {
+		_, err := sourceDB.MigrateTo(targetDB)
+		require.EqualError(t, err, "the source statedb is empty")
+	})
+
+	updates := NewUpdateBatch()
+	for i := 0; i < 2*maxKeysPerMigrationBatch+10; i++ {
+		updates.PubUpdates.Put("ns1", fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("value-%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	updates.PubUpdates.PutValAndMetadata("ns2", "key1", []byte(`{"color":"blue"}`), []byte("metadata1"), version.NewHeight(2, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(2, 2))
+	putPvtUpdatesWithMetadata(t, updates, "ns2", "coll1", "key2", []byte("pvt_value2"), []byte("metadata2"), version.NewHeight(2, 3))
+	require.NoError(t, sourceDB.ApplyPrivacyAwareUpdates(updates, version.NewHeight(2, 3)))
+
+	sourceDigest, err := sourceDB.ComputeDigest()
+	require.NoError(t, err)
+	migratedDigest, err := sourceDB.MigrateTo(targetDB)
+	require.NoError(t, err)
+	require.Equal(t, sourceDigest, migratedDigest)
+	targetDigest, err := targetDB.ComputeDigest()
+	require.NoError(t, err)
+	require.Equal(t, sourceDigest, targetDigest)
+
+	savepoint, err := targetDB.GetLatestSavePoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(2, 3), savepoint)
+
+	vv, err := targetDB.GetState("ns1", fmt.Sprintf("key-%d", 2*maxKeysPerMigrationBatch))
+	require.NoError(t, err)
+	require.Equal(t,
+		&statedb.VersionedValue{
+			Value:   []byte(fmt.Sprintf("value-%d", 2*maxKeysPerMigrationBatch)),
+			Version: version.NewHeight(1, uint64(2*maxKeysPerMigrationBatch)),
+		},
+		vv,
+	)
+	vv, err = targetDB.GetState("ns2", "key1")
+	require.NoError(t, err)
+	require.Equal(t, &statedb.VersionedValue{Value: []byte(`{"color":"blue"}`), Metadata: []byte("metadata1"), Version: version.NewHeight(2, 1)}, vv)
+	vv, err = targetDB.GetPrivateData("ns1", "coll1", "key1")
+	require.NoError(t, err)
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("pvt_value1"), Version: version.NewHeight(2, 2)}, vv)
+	vv, err = targetDB.GetPrivateDataHash("ns2", "coll1", "key2")
+	require.NoError(t, err)
+	require.Equal(t, &statedb.VersionedValue{Value: util.ComputeStringHash("pvt_value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(2, 3)}, vv)
+
+	t.Run("non-empty-target", func(t *testing.T) {
+		_, err := sourceDB.MigrateTo(targetDB)
+		require.EqualError(t, err, "the target statedb is not empty, it has the savepoint [{BlockNum: 2, TxNum: 3}]")
+	})
+}
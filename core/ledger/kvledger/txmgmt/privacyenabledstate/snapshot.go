@@ -8,10 +8,15 @@ package privacyenabledstate
 
 import (
 	"hash"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/ledger/snapshot"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -193,3 +198,241 @@ func (w *snapshotWriter) close() {
 	w.dataFile.Close()
 	w.metadataFile.Close()
 }
+
+// ImportFromSnapshot loads the public state and the private state hashes from the snapshot files in the
+// given dir into the empty statedb for the given id and records the given savepoint. As the snapshot does
+// not carry the information about which keys have metadata, all the imported namespaces are marked as
+// possibly having metadata
+func (p *DBProvider) ImportFromSnapshot(dbName string, savepoint *version.Height, snapshotDir string) error {
+	worldStateSnapshotReader, err := newWorldStateSnapshotReader(snapshotDir)
+	if err != nil {
+		return err
+	}
+	defer worldStateSnapshotReader.Close()
+
+	if err := p.VersionedDBProvider.ImportFromSnapshot(
+		dbName,
+		savepoint,
+		worldStateSnapshotReader,
+		worldStateSnapshotReader.dbValueFormat,
+	); err != nil {
+		return errors.WithMessagef(err, "error while importing the statedb for channel [%s] from snapshot", dbName)
+	}
+
+	bookkeeper := p.bookkeepingProvider.GetDBHandle(dbName, bookkeeping.MetadataPresenceIndicator)
+	batch := bookkeeper.NewUpdateBatch()
+	for ns := range worldStateSnapshotReader.namespaces() {
+		batch.Put([]byte(ns), []byte{})
+	}
+	return bookkeeper.WriteBatch(batch, true)
+}
+
+// LoadSnapshotPvtdataHashesCollections returns the collections, by namespace, for which the snapshot files in
+// the given dir carry private data hashes
+func LoadSnapshotPvtdataHashesCollections(snapshotDir string) (map[string][]string, error) {
+	metadataFilePath := filepath.Join(snapshotDir, pvtStateHashesMetadataFileName)
+	if _, err := os.Stat(metadataFilePath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	metadata, err := loadSnapshotMetadata(metadataFilePath)
+	if err != nil {
+		return nil, err
+	}
+	collections := map[string][]string{}
+	for _, m := range metadata {
+		nsColl := strings.SplitN(m.namespace, nsJoiner+hashDataPrefix, 2)
+		if len(nsColl) != 2 {
+			return nil, errors.Errorf("unexpected namespace [%s] in the private state hashes of the snapshot", m.namespace)
+		}
+		collections[nsColl[0]] = append(collections[nsColl[0]], nsColl[1])
+	}
+	return collections, nil
+}
+
+// worldStateSnapshotReader reads the public state and the private state hashes from the snapshot
+// files, in that order. It implements the interface statedb.FullScanIterator
+type worldStateSnapshotReader struct {
+	pubState       *snapshotReader
+	pvtStateHashes *snapshotReader
+	dbValueFormat  byte
+}
+
+func newWorldStateSnapshotReader(dir string) (*worldStateSnapshotReader, error) {
+	var pubState, pvtStateHashes *snapshotReader
+	var err error
+	defer func() {
+		if err != nil {
+			pubState.Close()
+			pvtStateHashes.Close()
+		}
+	}()
+
+	if pubState, err = newSnapshotReader(
+		filepath.Join(dir, pubStateDataFileName),
+		filepath.Join(dir, pubStateMetadataFileName),
+	); err != nil {
+		return nil, err
+	}
+	if pvtStateHashes, err = newSnapshotReader(
+		filepath.Join(dir, pvtStateHashesFileName),
+		filepath.Join(dir, pvtStateHashesMetadataFileName),
+	); err != nil {
+		return nil, err
+	}
+
+	r := &worldStateSnapshotReader{
+		pubState:       pubState,
+		pvtStateHashes: pvtStateHashes,
+	}
+	switch {
+	case pubState != nil && pvtStateHashes != nil && pubState.dbValueFormat != pvtStateHashes.dbValueFormat:
+		err = errors.Errorf("the db value format [%d] of the public state does not match the db value format [%d] of the private state hashes",
+			pubState.dbValueFormat, pvtStateHashes.dbValueFormat)
+		return nil, err
+	case pubState != nil:
+		r.dbValueFormat = pubState.dbValueFormat
+	case pvtStateHashes != nil:
+		r.dbValueFormat = pvtStateHashes.dbValueFormat
+	}
+	return r, nil
+}
+
+// Next implements the method from the interface statedb.FullScanIterator
+func (r *worldStateSnapshotReader) Next() (*statedb.CompositeKey, []byte, error) {
+	for _, s := range []*snapshotReader{r.pubState, r.pvtStateHashes} {
+		if !s.hasMore() {
+			continue
+		}
+		return s.next()
+	}
+	return nil, nil, nil
+}
+
+// Close implements the method from the interface statedb.FullScanIterator
+func (r *worldStateSnapshotReader) Close() {
+	r.pubState.Close()
+	r.pvtStateHashes.Close()
+}
+
+// namespaces returns the chaincode namespaces for which the snapshot contains either the
+// public state or the private state hashes
+func (r *worldStateSnapshotReader) namespaces() map[string]struct{} {
+	namespaces := map[string]struct{}{}
+	for _, s := range []*snapshotReader{r.pubState, r.pvtStateHashes} {
+		if s == nil {
+			continue
+		}
+		for _, m := range s.metadata {
+			namespaces[strings.SplitN(m.namespace, nsJoiner, 2)[0]] = struct{}{}
+		}
+	}
+	return namespaces
+}
+
+// snapshotReader reads the tuples <key, dbValue> from a data file generated by the snapshotWriter
+// and associates them with the namespaces as per the corresponding metadata file
+type snapshotReader struct {
+	dataFile      *snapshot.FileReader
+	dbValueFormat byte
+	metadata      []*namespaceEntriesCount
+	cursor        int
+	numRead       uint64
+}
+
+type namespaceEntriesCount struct {
+	namespace  string
+	numEntries uint64
+}
+
+// newSnapshotReader returns a nil snapshotReader if the files do not exist, as the snapshotWriter
+// does not generate the files if there is no data to export
+func newSnapshotReader(dataFilePath, metadataFilePath string) (*snapshotReader, error) {
+	if _, err := os.Stat(dataFilePath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	metadata, err := loadSnapshotMetadata(metadataFilePath)
+	if err != nil {
+		return nil, err
+	}
+	dataFile, err := snapshot.OpenFile(dataFilePath, snapshotFileFormat)
+	if err != nil {
+		return nil, err
+	}
+	dbValueFormat, err := dataFile.DecodeBytes()
+	if err != nil {
+		dataFile.Close()
+		return nil, err
+	}
+	if len(dbValueFormat) != 1 {
+		dataFile.Close()
+		return nil, errors.Errorf("unexpected db value format bytes %#v in the snapshot file %s", dbValueFormat, dataFilePath)
+	}
+	return &snapshotReader{
+		dataFile:      dataFile,
+		dbValueFormat: dbValueFormat[0],
+		metadata:      metadata,
+	}, nil
+}
+
+func loadSnapshotMetadata(metadataFilePath string) ([]*namespaceEntriesCount, error) {
+	metadataFile, err := snapshot.OpenFile(metadataFilePath, snapshotFileFormat)
+	if err != nil {
+		return nil, err
+	}
+	defer metadataFile.Close()
+
+	numNamespaces, err := metadataFile.DecodeUVarInt()
+	if err != nil {
+		return nil, err
+	}
+	metadata := make([]*namespaceEntriesCount, numNamespaces)
+	for i := uint64(0); i < numNamespaces; i++ {
+		namespace, err := metadataFile.DecodeString()
+		if err != nil {
+			return nil, err
+		}
+		numEntries, err := metadataFile.DecodeUVarInt()
+		if err != nil {
+			return nil, err
+		}
+		metadata[i] = &namespaceEntriesCount{namespace: namespace, numEntries: numEntries}
+	}
+	return metadata, nil
+}
+
+func (r *snapshotReader) hasMore() bool {
+	if r == nil {
+		return false
+	}
+	for r.cursor < len(r.metadata) && r.numRead == r.metadata[r.cursor].numEntries {
+		r.cursor++
+		r.numRead = 0
+	}
+	return r.cursor < len(r.metadata)
+}
+
+func (r *snapshotReader) next() (*statedb.CompositeKey, []byte, error) {
+	key, err := r.dataFile.DecodeString()
+	if err != nil {
+		return nil, nil, err
+	}
+	dbValue, err := r.dataFile.DecodeBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	r.numRead++
+	return &statedb.CompositeKey{
+			Namespace: r.metadata[r.cursor].namespace,
+			Key:       key,
+		},
+		dbValue,
+		nil
+}
+
+func (r *snapshotReader) Close() {
+	if r == nil {
+		return
+	}
+	r.dataFile.Close()
+}
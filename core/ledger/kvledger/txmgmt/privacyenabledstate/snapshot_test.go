@@ -164,6 +164,37 @@ func testSnapshotWithSampleData(t *testing.T, env TestEnv,
 		require.Equal(t, pvtStateHashes, pvtStateHashesFromSnapshot)
 	}
 	require.Len(t, filesAndHashes, numFilesExpected)
+
+	// import the snapshot files into a new statedb and verify the contents
+	importedLedgerID := generateLedgerID(t)
+	dbProvider := env.(*LevelDBTestEnv).provider
+	require.NoError(t, dbProvider.ImportFromSnapshot(importedLedgerID, version.NewHeight(10, 10), snapshotDir))
+	importedDB := env.GetDBHandle(importedLedgerID)
+	savepoint, err := importedDB.GetLatestSavePoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(10, 10), savepoint)
+	for _, s := range publicState {
+		vv, err := importedDB.GetState(s.Namespace, s.Key)
+		require.NoError(t, err)
+		require.Equal(t, &s.VersionedValue, vv)
+		require.True(t, importedDB.metadataHint.metadataEverUsedFor(s.Namespace))
+	}
+	for _, s := range pvtStateHashes {
+		nsColl := strings.Split(s.Namespace, nsJoiner+hashDataPrefix)
+		vv, err := importedDB.GetValueHash(nsColl[0], nsColl[1], []byte(s.Key))
+		require.NoError(t, err)
+		require.Equal(t, &s.VersionedValue, vv)
+		require.True(t, importedDB.metadataHint.metadataEverUsedFor(nsColl[0]))
+	}
+	for _, s := range pvtState {
+		nsColl := strings.Split(s.Namespace, nsJoiner+pvtDataPrefix)
+		vv, err := importedDB.GetPrivateData(nsColl[0], nsColl[1], s.Key)
+		require.NoError(t, err)
+		require.Nil(t, vv)
+	}
+
+	err = dbProvider.ImportFromSnapshot(importedLedgerID, version.NewHeight(10, 10), snapshotDir)
+	require.Contains(t, err.Error(), "is not empty. Import from snapshot is supported only for an empty statedb")
 }
 
 func sha256ForFileForTest(t *testing.T, file string) []byte {
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+// StateCommitment is a commitment over the public data and the hashes of the private data, along with their
+// metadata and versions. The private data is excluded so that the commitment is the same on all the peers of
+// a channel, irrespective of the collections that a peer is eligible for. The commitment does not depend on
+// the type of the underlying state database and, as it does not depend on the order in which the data is added,
+// it can be rolled forward with the updates of a block instead of being recomputed over the entire state
+type StateCommitment struct {
+	digest *stateDigest
+}
+
+// NewStateCommitment returns the commitment over an empty state
+func NewStateCommitment() *StateCommitment {
+	return &StateCommitment{digest: newStateDigest()}
+}
+
+// NewStateCommitmentFromBytes constructs the commitment from the bytes returned by the function ToBytes
+func NewStateCommitmentFromBytes(b []byte) (*StateCommitment, error) {
+	if len(b) != stateDigestSumLen+8 {
+		return nil, errors.Errorf("unexpected length [%d] of the state commitment bytes", len(b))
+	}
+	d := newStateDigest()
+	d.sum.SetBytes(b[:stateDigestSumLen])
+	d.count = binary.BigEndian.Uint64(b[stateDigestSumLen:])
+	return &StateCommitment{digest: d}, nil
+}
+
+// ToBytes serializes the commitment such that it can be rolled forward after being deserialized
+func (c *StateCommitment) ToBytes() []byte {
+	b := make([]byte, stateDigestSumLen+8)
+	copy(b, c.digest.sumBytes())
+	binary.BigEndian.PutUint64(b[stateDigestSumLen:], c.digest.count)
+	return b
+}
+
+// Hash returns the hash that represents the commitment. Two states have the same hash only if they
+// have the same public data and the same hashes of the private data
+func (c *StateCommitment) Hash() []byte {
+	return c.digest.bytes()
+}
+
+// ComputeStateCommitment computes the commitment over the entire state
+func (s *DB) ComputeStateCommitment() (*StateCommitment, error) {
+	c := NewStateCommitment()
+	err := s.scanState(isPvtdataNs, func(kvs []*statedb.VersionedKV) error {
+		for _, kv := range kvs {
+			c.digest.add(kv)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateStateCommitment returns the commitment that results from applying the updates to the state over which the
+// commitment c is computed. This is expected to be invoked before the updates are applied to the db, as the current
+// values of the updated keys are removed from the commitment. The commitment c is not modified
+func (s *DB) UpdateStateCommitment(c *StateCommitment, updates *UpdateBatch) (*StateCommitment, error) {
+	updated := &StateCommitment{
+		digest: &stateDigest{
+			sum:   new(big.Int).Set(c.digest.sum),
+			count: c.digest.count,
+		},
+	}
+
+	for _, ns := range updates.PubUpdates.GetUpdatedNamespaces() {
+		nsUpdates := updates.PubUpdates.GetUpdates(ns)
+		keys := make([]string, 0, len(nsUpdates))
+		for key := range nsUpdates {
+			keys = append(keys, key)
+		}
+		if err := s.updateDigest(updated.digest, ns, keys, keys, nsUpdates); err != nil {
+			return nil, err
+		}
+	}
+
+	for ns, nsBatch := range updates.HashUpdates.UpdateMap {
+		for _, coll := range nsBatch.GetCollectionNames() {
+			collUpdates := nsBatch.GetUpdates(coll)
+			keyHashes := make([]string, 0, len(collUpdates))
+			dbKeys := make([]string, 0, len(collUpdates))
+			for keyHash := range collUpdates {
+				keyHashes = append(keyHashes, keyHash)
+				dbKey := keyHash
+				if !s.BytesKeySupported() {
+					dbKey = base64.StdEncoding.EncodeToString([]byte(keyHash))
+				}
+				dbKeys = append(dbKeys, dbKey)
+			}
+			if err := s.updateDigest(updated.digest, deriveHashedDataNs(ns, coll), keyHashes, dbKeys, collUpdates); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return updated, nil
+}
+
+// updateDigest removes the current values of the keys from the digest and adds the updated values. The keys are the ones
+// added to the digest and the dbKeys are the corresponding keys in the db, which differ only for the hashed data
+func (s *DB) updateDigest(d *stateDigest, ns string, keys, dbKeys []string, updates map[string]*statedb.VersionedValue) error {
+	currentValues, err := s.VersionedDB.GetStateMultipleKeys(ns, dbKeys)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if currentValue := currentValues[i]; currentValue != nil {
+			d.remove(&statedb.VersionedKV{
+				CompositeKey:   statedb.CompositeKey{Namespace: ns, Key: key},
+				VersionedValue: *currentValue,
+			})
+		}
+		if updatedValue := updates[key]; !updatedValue.IsDelete() {
+			d.add(&statedb.VersionedKV{
+				CompositeKey:   statedb.CompositeKey{Namespace: ns, Key: key},
+				VersionedValue: *updatedValue,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateCommitment(t *testing.T) {
+	for _, env := range testEnvs {
+		t.Run(env.GetName(), func(t *testing.T) {
+			testStateCommitment(t, env)
+		})
+	}
+}
+
+func testStateCommitment(t *testing.T, env TestEnv) {
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle(generateLedgerID(t))
+
+	emptyCommitment, err := db.ComputeStateCommitment()
+	require.NoError(t, err)
+	require.Equal(t, NewStateCommitment().Hash(), emptyCommitment.Hash())
+
+	applyAndVerify := func(commitment *StateCommitment, updates *UpdateBatch, height *version.Height) *StateCommitment {
+		updatedCommitment, err := db.UpdateStateCommitment(commitment, updates)
+		require.NoError(t, err)
+		require.NoError(t, db.ApplyPrivacyAwareUpdates(updates, height))
+		computedCommitment, err := db.ComputeStateCommitment()
+		require.NoError(t, err)
+		require.Equal(t, computedCommitment.Hash(), updatedCommitment.Hash())
+		return updatedCommitment
+	}
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	updates.PubUpdates.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata2"), version.NewHeight(1, 2))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key3", []byte("pvt_value3"), version.NewHeight(1, 3))
+	putPvtUpdatesWithMetadata(t, updates, "ns2", "coll1", "key4", []byte("pvt_value4"), []byte("metadata4"), version.NewHeight(1, 4))
+	commitment1 := applyAndVerify(emptyCommitment, updates, version.NewHeight(1, 4))
+	require.NotEqual(t, emptyCommitment.Hash(), commitment1.Hash())
+
+	updates = NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1_new"), version.NewHeight(2, 1))
+	updates.PubUpdates.Delete("ns1", "key2", version.NewHeight(2, 2))
+	updates.PubUpdates.Put("ns2", "key5", []byte("value5"), version.NewHeight(2, 3))
+	updates.HashUpdates.Delete("ns2", "coll1", util.ComputeStringHash("key4"), version.NewHeight(2, 4))
+	updates.PvtUpdates.Delete("ns2", "coll1", "key4", version.NewHeight(2, 4))
+	commitment2 := applyAndVerify(commitment1, updates, version.NewHeight(2, 4))
+	require.NotEqual(t, commitment1.Hash(), commitment2.Hash())
+
+	t.Run("commitment-is-not-modified-by-update", func(t *testing.T) {
+		updates := NewUpdateBatch()
+		updates.PubUpdates.Put("ns1", "key6", []byte("value6"), version.NewHeight(3, 1))
+		hashBeforeUpdate := commitment2.Hash()
+		_, err := db.UpdateStateCommitment(commitment2, updates)
+		require.NoError(t, err)
+		require.Equal(t, hashBeforeUpdate, commitment2.Hash())
+	})
+
+	t.Run("private-data-is-excluded", func(t *testing.T) {
+		updates := NewUpdateBatch()
+		updates.PvtUpdates.Put("ns1", "coll1", "key3", []byte("pvt_value3_new"), version.NewHeight(1, 3))
+		require.NoError(t, db.ApplyPrivacyAwareUpdates(updates, nil))
+		computedCommitment, err := db.ComputeStateCommitment()
+		require.NoError(t, err)
+		require.Equal(t, commitment2.Hash(), computedCommitment.Hash())
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		deserialized, err := NewStateCommitmentFromBytes(commitment2.ToBytes())
+		require.NoError(t, err)
+		require.Equal(t, commitment2.Hash(), deserialized.Hash())
+
+		updates := NewUpdateBatch()
+		updates.PubUpdates.Put("ns1", "key7", []byte("value7"), version.NewHeight(3, 1))
+		applyAndVerify(deserialized, updates, version.NewHeight(3, 1))
+
+		_, err = NewStateCommitmentFromBytes([]byte("bad-bytes"))
+		require.EqualError(t, err, "unexpected length [9] of the state commitment bytes")
+	})
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+)
+
+const stateDigestSumLen = 32
+
+var stateDigestModulus = new(big.Int).Lsh(big.NewInt(1), 8*stateDigestSumLen)
+
+// stateDigest accumulates an order independent digest of the data. The digest is the hash of the number of the
+// keys and the sum (modulo 2^256) of the hashes of the individual keys along with their values, metadata, and versions.
+// The order independence is required because different types of state databases return the data in different orders.
+// In addition, as the sum can be reversed, a key can be removed from the digest without recomputing the digest
+type stateDigest struct {
+	sum   *big.Int
+	count uint64
+}
+
+func newStateDigest() *stateDigest {
+	return &stateDigest{
+		sum: big.NewInt(0),
+	}
+}
+
+func (d *stateDigest) add(kv *statedb.VersionedKV) {
+	d.sum.Add(d.sum, hashVersionedKV(kv))
+	d.sum.Mod(d.sum, stateDigestModulus)
+	d.count++
+}
+
+func (d *stateDigest) remove(kv *statedb.VersionedKV) {
+	d.sum.Sub(d.sum, hashVersionedKV(kv))
+	d.sum.Mod(d.sum, stateDigestModulus)
+	d.count--
+}
+
+func (d *stateDigest) sumBytes() []byte {
+	sumBytes := make([]byte, stateDigestSumLen)
+	b := d.sum.Bytes()
+	copy(sumBytes[stateDigestSumLen-len(b):], b)
+	return sumBytes
+}
+
+func (d *stateDigest) bytes() []byte {
+	countBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(countBytes, d.count)
+	h := sha256.New()
+	h.Write(countBytes)
+	h.Write(d.sumBytes())
+	return h.Sum(nil)
+}
+
+func hashVersionedKV(kv *statedb.VersionedKV) *big.Int {
+	var versionBytes []byte
+	if kv.Version != nil {
+		versionBytes = kv.Version.ToBytes()
+	}
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte(kv.Namespace),
+		[]byte(kv.Key),
+		kv.Value,
+		kv.Metadata,
+		versionBytes,
+	} {
+		lenBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(lenBytes, uint64(len(field)))
+		h.Write(lenBytes)
+		h.Write(field)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
@@ -17,6 +17,49 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// TestVersionedDBProviderConformance runs the tests that every implementation of statedb.VersionedDBProvider
+// is expected to pass, irrespective of whether the state database is built-in or registered via function
+// statedb.RegisterVersionedDBProviderFactory. The function newProvider is expected to return a fresh provider
+// and a function that cleans up the provider. The valueFormat and dbValueDeserializer are the ones that the
+// FullScanIterator of the state database uses
+func TestVersionedDBProviderConformance(
+	t *testing.T,
+	newProvider func(t *testing.T) (dbProvider statedb.VersionedDBProvider, cleanup func()),
+	valueFormat byte,
+	dbValueDeserializer func(b []byte) (*statedb.VersionedValue, error)) {
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, dbProvider statedb.VersionedDBProvider)
+	}{
+		{"GetStateMultipleKeys", TestGetStateMultipleKeys},
+		{"BasicRW", TestBasicRW},
+		{"MultiDBBasicRW", TestMultiDBBasicRW},
+		{"Deletes", TestDeletes},
+		{"Iterator", TestIterator},
+		{"GetVersion", TestGetVersion},
+		{"ValueAndMetadataWrites", TestValueAndMetadataWrites},
+		{"PaginatedRangeQuery", TestPaginatedRangeQuery},
+		{"RangeQuerySpecialCharacters", TestRangeQuerySpecialCharacters},
+		{"ApplyUpdatesWithNilHeight", TestApplyUpdatesWithNilHeight},
+		{
+			"FullScanIterator",
+			func(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+				TestFullScanIterator(t, dbProvider, valueFormat, dbValueDeserializer)
+			},
+		},
+		{"ImportFromSnapshot", TestImportFromSnapshot},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbProvider, cleanup := newProvider(t)
+			defer cleanup()
+			tc.test(t, dbProvider)
+		})
+	}
+}
+
 // TestGetStateMultipleKeys tests read for given multiple keys
 func TestGetStateMultipleKeys(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	db, err := dbProvider.GetDBHandle("testgetmultiplekeys", nil)
@@ -1076,6 +1119,89 @@ func TestFullScanIterator(
 	}
 }
 
+// TestImportFromSnapshot tests importing the data exported by a FullScanIterator into another db
+func TestImportFromSnapshot(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	sourceDB, err := dbProvider.GetDBHandle("test-import-source", nil)
+	require.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.PutValAndMetadata("", "key1", []byte("value1"), []byte("metadata1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 2))
+	batch.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata2"), version.NewHeight(2, 1))
+	batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(2, 2))
+	require.NoError(t, sourceDB.ApplyUpdates(batch, version.NewHeight(2, 2)))
+
+	fullScanItr, dbValueFormat, err := sourceDB.GetFullScanIterator(func(string) bool { return false })
+	require.NoError(t, err)
+	require.NoError(t, dbProvider.ImportFromSnapshot("test-import-target", version.NewHeight(2, 2), fullScanItr, dbValueFormat))
+	fullScanItr.Close()
+
+	targetDB, err := dbProvider.GetDBHandle("test-import-target", nil)
+	require.NoError(t, err)
+	savepoint, err := targetDB.GetLatestSavePoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(2, 2), savepoint)
+	for _, ns := range []string{"", "ns1", "ns2"} {
+		for key, expectedVV := range batch.GetUpdates(ns) {
+			vv, err := targetDB.GetState(ns, key)
+			require.NoError(t, err)
+			require.Equal(t, expectedVV, vv)
+		}
+	}
+
+	// import into a non-empty db
+	fullScanItr, dbValueFormat, err = sourceDB.GetFullScanIterator(func(string) bool { return false })
+	require.NoError(t, err)
+	defer fullScanItr.Close()
+	err = dbProvider.ImportFromSnapshot("test-import-target", version.NewHeight(2, 2), fullScanItr, dbValueFormat)
+	require.EqualError(t, err, "statedb for channel [test-import-target] is not empty. Import from snapshot is supported only for an empty statedb")
+
+	// import with an unsupported value format
+	fullScanItr, _, err = sourceDB.GetFullScanIterator(func(string) bool { return false })
+	require.NoError(t, err)
+	defer fullScanItr.Close()
+	err = dbProvider.ImportFromSnapshot("test-import-unsupported-format", version.NewHeight(2, 2), fullScanItr, byte(100))
+	require.Contains(t, err.Error(), "unsupported db value format [100] for importing the snapshot data")
+}
+
+// TestDrop tests dropping the data of a db, which should not affect the other dbs
+func TestDrop(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	droppable, ok := dbProvider.(statedb.Droppable)
+	require.True(t, ok)
+
+	for _, dbName := range []string{"test-drop", "test-drop-other"} {
+		db, err := dbProvider.GetDBHandle(dbName, nil)
+		require.NoError(t, err)
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+		batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(1, 2))
+		require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)))
+	}
+
+	require.NoError(t, droppable.Drop("test-drop"))
+	db, err := dbProvider.GetDBHandle("test-drop", nil)
+	require.NoError(t, err)
+	savepoint, err := db.GetLatestSavePoint()
+	require.NoError(t, err)
+	require.Nil(t, savepoint)
+	for _, ns := range []string{"ns1", "ns2"} {
+		vv, err := db.GetState(ns, "key1")
+		require.NoError(t, err)
+		require.Nil(t, vv)
+	}
+
+	otherDB, err := dbProvider.GetDBHandle("test-drop-other", nil)
+	require.NoError(t, err)
+	savepoint, err = otherDB.GetLatestSavePoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(1, 2), savepoint)
+	vv, err := otherDB.GetState("ns1", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), vv.Value)
+
+	// dropping a db that does not exist
+	require.NoError(t, droppable.Drop("test-drop-non-existing"))
+}
+
 type stringset []string
 
 func (universe stringset) contains(str string) bool {
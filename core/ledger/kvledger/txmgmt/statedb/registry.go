@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+const (
+	// GoLevelDB is the name of the built-in state database that is embedded in the peer
+	GoLevelDB = "goleveldb"
+	// CouchDB is the name of the built-in state database that is maintained in an external CouchDB
+	CouchDB = "CouchDB"
+)
+
+// VersionedDBProviderConfig encapsulates the inputs that are passed to a VersionedDBProviderFactory
+type VersionedDBProviderConfig struct {
+	// DBPath is a filesystem path on the peer that is reserved for the state database,
+	// in case the implementation needs to maintain local files
+	DBPath string
+	// PluginConfig is the configuration of the state database, as specified in ledger.StateDBConfig
+	PluginConfig map[string]interface{}
+	// MetricsProvider is the provider for the metrics that the state database may want to emit
+	MetricsProvider metrics.Provider
+	// SysNamespaces are the namespaces of the system chaincodes
+	SysNamespaces []string
+}
+
+// VersionedDBProviderFactory constructs a VersionedDBProvider for a state database other than the
+// built-in ones. A factory is made available to the ledger via function RegisterVersionedDBProviderFactory
+type VersionedDBProviderFactory func(conf *VersionedDBProviderConfig) (VersionedDBProvider, error)
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]VersionedDBProviderFactory
+}{
+	factories: map[string]VersionedDBProviderFactory{},
+}
+
+// RegisterVersionedDBProviderFactory registers a factory for a state database under the given name.
+// The ledger uses the factory when the name is configured as the state database in ledger.StateDBConfig.
+// The registration is expected to happen before the ledger is initialized, for instance in an init function
+// of the package that implements the state database
+func RegisterVersionedDBProviderFactory(name string, factory VersionedDBProviderFactory) error {
+	if name == "" {
+		return errors.New("name of the state database cannot be empty")
+	}
+	if factory == nil {
+		return errors.Errorf("factory for the state database [%s] cannot be nil", name)
+	}
+	if name == GoLevelDB || name == CouchDB {
+		return errors.Errorf("state database [%s] is built-in and cannot be registered", name)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.factories[name]; ok {
+		return errors.Errorf("state database [%s] is already registered", name)
+	}
+	registry.factories[name] = factory
+	return nil
+}
+
+// GetVersionedDBProviderFactory returns the factory registered under the given name
+func GetVersionedDBProviderFactory(name string) (VersionedDBProviderFactory, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	factory, ok := registry.factories[name]
+	return factory, ok
+}
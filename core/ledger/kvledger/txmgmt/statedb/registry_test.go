@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterVersionedDBProviderFactory(t *testing.T) {
+	factory := func(conf *VersionedDBProviderConfig) (VersionedDBProvider, error) {
+		return nil, nil
+	}
+	defer func() {
+		registry.Lock()
+		delete(registry.factories, "test-statedb")
+		registry.Unlock()
+	}()
+
+	_, ok := GetVersionedDBProviderFactory("test-statedb")
+	require.False(t, ok)
+
+	require.NoError(t, RegisterVersionedDBProviderFactory("test-statedb", factory))
+	registeredFactory, ok := GetVersionedDBProviderFactory("test-statedb")
+	require.True(t, ok)
+	require.NotNil(t, registeredFactory)
+
+	require.EqualError(t,
+		RegisterVersionedDBProviderFactory("test-statedb", factory),
+		"state database [test-statedb] is already registered",
+	)
+	require.EqualError(t,
+		RegisterVersionedDBProviderFactory(GoLevelDB, factory),
+		"state database [goleveldb] is built-in and cannot be registered",
+	)
+	require.EqualError(t,
+		RegisterVersionedDBProviderFactory(CouchDB, factory),
+		"state database [CouchDB] is built-in and cannot be registered",
+	)
+	require.EqualError(t,
+		RegisterVersionedDBProviderFactory("", factory),
+		"name of the state database cannot be empty",
+	)
+	require.EqualError(t,
+		RegisterVersionedDBProviderFactory("another-statedb", nil),
+		"factory for the state database [another-statedb] cannot be nil",
+	)
+}
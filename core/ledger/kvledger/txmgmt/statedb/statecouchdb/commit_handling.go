@@ -8,7 +8,6 @@ package statecouchdb
 
 import (
 	"fmt"
-	"math"
 	"sync"
 
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -103,17 +102,18 @@ func (vdb *VersionedDB) buildCommittersForNs(ns string, nsUpdates map[string]*st
 	if err != nil {
 		return nil, err
 	}
-	// for each namespace, build mutiple committers based on the maxBatchSize
-	maxBatchSize := db.couchInstance.maxBatchUpdateSize()
-	numCommitters := 1
-	if maxBatchSize > 0 {
-		numCommitters = int(math.Ceil(float64(len(nsUpdates)) / float64(maxBatchSize)))
+	// for each namespace, build mutiple committers based on the maxBatchSize. For a partitioned
+	// database, the keys of a partition are preferably assigned to the same committer
+	keys := make([]string, 0, len(nsUpdates))
+	for key := range nsUpdates {
+		keys = append(keys, key)
 	}
-	committers := make([]*committer, numCommitters)
+	keyGroups := db.groupKeysByPartition(keys, db.couchInstance.maxBatchUpdateSize())
+	committers := make([]*committer, len(keyGroups))
 
 	cacheEnabled := vdb.cache.enabled(ns)
 
-	for i := 0; i < numCommitters; i++ {
+	for i := range keyGroups {
 		committers[i] = &committer{
 			db:             db,
 			batchUpdateMap: make(map[string]*batchableDocument),
@@ -130,17 +130,17 @@ func (vdb *VersionedDB) buildCommittersForNs(ns string, nsUpdates map[string]*st
 		return nil, err
 	}
 
-	i := 0
-	for key, vv := range nsUpdates {
-		kv := &keyValue{key: key, revision: revisions[key], VersionedValue: vv}
-		couchDoc, err := keyValToCouchDoc(kv)
-		if err != nil {
-			return nil, err
-		}
-		committers[i].batchUpdateMap[key] = &batchableDocument{CouchDoc: *couchDoc, Deleted: vv.Value == nil}
-		committers[i].addToCacheUpdate(kv)
-		if maxBatchSize > 0 && len(committers[i].batchUpdateMap) == maxBatchSize {
-			i++
+	for i, keys := range keyGroups {
+		for _, key := range keys {
+			vv := nsUpdates[key]
+			kv := &keyValue{key: key, revision: revisions[key], VersionedValue: vv}
+			docID := db.docID(key)
+			couchDoc, err := keyValToCouchDoc(&keyValue{key: docID, revision: kv.revision, VersionedValue: vv})
+			if err != nil {
+				return nil, err
+			}
+			committers[i].batchUpdateMap[docID] = &batchableDocument{CouchDoc: *couchDoc, Deleted: vv.Value == nil}
+			committers[i].addToCacheUpdate(kv)
 		}
 	}
 	return committers, nil
@@ -188,7 +188,7 @@ func (c *committer) commitUpdates() error {
 	for _, resp := range responses {
 		// If the document returned an error, retry the individual document
 		if resp.Ok {
-			c.updateRevisionInCacheUpdate(resp.ID, resp.Rev)
+			c.updateRevisionInCacheUpdate(c.db.keyFromDocID(resp.ID), resp.Rev)
 			continue
 		}
 		doc := c.batchUpdateMap[resp.ID]
@@ -216,7 +216,7 @@ func (c *committer) commitUpdates() error {
 			// Note that this will do retries as needed
 			var revision string
 			revision, err = c.db.saveDoc(resp.ID, "", &doc.CouchDoc)
-			c.updateRevisionInCacheUpdate(resp.ID, revision)
+			c.updateRevisionInCacheUpdate(c.db.keyFromDocID(resp.ID), revision)
 		}
 
 		// If the single document update or delete returns an error, then throw the error
@@ -55,6 +55,9 @@ type dbInfo struct {
 	Other struct {
 		DataSize int `json:"data_size"`
 	} `json:"other"`
+	Props struct {
+		Partitioned bool `json:"partitioned"`
+	} `json:"props"`
 	DocDelCount       int    `json:"doc_del_count"`
 	DocCount          int    `json:"doc_count"`
 	DiskSize          int    `json:"disk_size"`
@@ -94,6 +97,17 @@ type queryResponse struct {
 	Bookmark string            `json:"bookmark"`
 }
 
+// queryPlan is used for processing the response of the _explain endpoint of CouchDB
+type queryPlan struct {
+	Index struct {
+		DesignDocument string          `json:"ddoc"`
+		Name           string          `json:"name"`
+		Type           string          `json:"type"`
+		Definition     json.RawMessage `json:"def"`
+	} `json:"index"`
+	Selector json.RawMessage `json:"selector"`
+}
+
 // docMetadata is used for capturing CouchDB document header info,
 // used to capture id, version, rev and attachments returned in the query from CouchDB
 type docMetadata struct {
@@ -122,6 +136,7 @@ type couchDatabase struct {
 	couchInstance    *couchInstance //connection configuration
 	dbName           string
 	indexWarmCounter int
+	partitioning     *partitioning // nil unless the database is a partitioned database
 }
 
 //dbReturn contains an error reported by CouchDB
@@ -246,8 +261,15 @@ func (dbclient *couchDatabase) createDatabaseIfNotExist() error {
 		//get the number of retries
 		maxRetries := dbclient.couchInstance.conf.MaxRetries
 
+		//create a partitioned database if a partitioning is defined
+		var queryParms *url.Values
+		if dbclient.partitioning != nil {
+			queryParms = &url.Values{}
+			queryParms.Set("partitioned", "true")
+		}
+
 		//process the URL with a PUT, creates the database
-		resp, _, err := dbclient.handleRequest(http.MethodPut, "CreateDatabaseIfNotExist", connectURL, nil, "", "", maxRetries, true, nil)
+		resp, _, err := dbclient.handleRequest(http.MethodPut, "CreateDatabaseIfNotExist", connectURL, nil, "", "", maxRetries, true, queryParms)
 		if err != nil {
 			// Check to see if the database exists
 			// Even though handleRequest() returned an error, the
@@ -263,6 +285,9 @@ func (dbclient *couchDatabase) createDatabaseIfNotExist() error {
 		couchdbLogger.Infof("Created state database %s", dbclient.dbName)
 	} else {
 		couchdbLogger.Debugf("[%s] Database already exists", dbclient.dbName)
+		if dbclient.partitioning != nil && !dbInfo.Props.Partitioned {
+			return errors.Errorf("database [%s] already exists and is not a partitioned database", dbclient.dbName)
+		}
 	}
 
 	if dbclient.dbName != "_users" {
@@ -849,6 +874,9 @@ func (dbclient *couchDatabase) readDocRange(startKey, endKey string, limit int32
 
 	var results []*queryResult
 
+	//scan a single partition if the range does not span partitions
+	partition := dbclient.rangePartition(startKey, endKey)
+
 	rangeURL, err := url.Parse(dbclient.couchInstance.url())
 	if err != nil {
 		couchdbLogger.Errorf("URL parse error: %s", err)
@@ -882,7 +910,7 @@ func (dbclient *couchDatabase) readDocRange(startKey, endKey string, limit int32
 	//get the number of retries
 	maxRetries := dbclient.couchInstance.conf.MaxRetries
 
-	resp, _, err := dbclient.handleRequest(http.MethodGet, "RangeDocRange", rangeURL, nil, "", "", maxRetries, true, &queryParms, "_all_docs")
+	resp, _, err := dbclient.handleRequest(http.MethodGet, "RangeDocRange", rangeURL, nil, "", "", maxRetries, true, &queryParms, partitionPath(partition, "_all_docs")...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -1004,9 +1032,15 @@ func (dbclient *couchDatabase) deleteDoc(id, rev string) error {
 
 //queryDocuments method provides function for processing a query
 func (dbclient *couchDatabase) queryDocuments(query string) ([]*queryResult, string, error) {
+	return dbclient.queryDocumentsInPartition("", query)
+}
+
+//queryDocumentsInPartition method processes a query on the given partition of a partitioned
+//database, or on the whole database if the partition is empty
+func (dbclient *couchDatabase) queryDocumentsInPartition(partition, query string) ([]*queryResult, string, error) {
 	dbName := dbclient.dbName
 
-	couchdbLogger.Debugf("[%s] Entering QueryDocuments()  query=%s", dbName, query)
+	couchdbLogger.Debugf("[%s] Entering QueryDocuments()  partition=%s  query=%s", dbName, partition, query)
 
 	var results []*queryResult
 
@@ -1019,7 +1053,7 @@ func (dbclient *couchDatabase) queryDocuments(query string) ([]*queryResult, str
 	//get the number of retries
 	maxRetries := dbclient.couchInstance.conf.MaxRetries
 
-	resp, _, err := dbclient.handleRequest(http.MethodPost, "QueryDocuments", queryURL, []byte(query), "", "", maxRetries, true, nil, "_find")
+	resp, _, err := dbclient.handleRequest(http.MethodPost, "QueryDocuments", queryURL, []byte(query), "", "", maxRetries, true, nil, partitionPath(partition, "_find")...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -1087,6 +1121,44 @@ func (dbclient *couchDatabase) queryDocuments(query string) ([]*queryResult, str
 
 }
 
+// explainQuery method returns the plan that CouchDB would use for executing the query, including the index
+// that CouchDB selects for the query. The "_all_docs" index is selected if no usable index exists for the query.
+// The query is explained for the given partition of a partitioned database, or for the whole database if the
+// partition is empty
+func (dbclient *couchDatabase) explainQuery(partition, query string) (*queryPlan, error) {
+	dbName := dbclient.dbName
+
+	couchdbLogger.Debugf("[%s] Entering ExplainQuery()  partition=%s  query=%s", dbName, partition, query)
+
+	queryURL, err := url.Parse(dbclient.couchInstance.url())
+	if err != nil {
+		couchdbLogger.Errorf("URL parse error: %s", err)
+		return nil, errors.Wrapf(err, "error parsing CouchDB URL: %s", dbclient.couchInstance.url())
+	}
+
+	//get the number of retries
+	maxRetries := dbclient.couchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.handleRequest(http.MethodPost, "ExplainQuery", queryURL, []byte(query), "", "", maxRetries, true, nil, partitionPath(partition, "_explain")...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body")
+	}
+
+	plan := &queryPlan{}
+	if err := json.Unmarshal(jsonResponseRaw, plan); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling json data")
+	}
+
+	couchdbLogger.Debugf("[%s] Exiting ExplainQuery()", dbName)
+	return plan, nil
+}
+
 // listIndex method lists the defined indexes for a database
 func (dbclient *couchDatabase) listIndex() ([]*indexResult, error) {
 
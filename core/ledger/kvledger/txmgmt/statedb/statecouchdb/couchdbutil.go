@@ -113,6 +113,12 @@ func checkCouchDBVersion(version string) error {
 
 //createCouchDatabase creates a CouchDB database object, as well as the underlying database if it does not exist
 func createCouchDatabase(couchInstance *couchInstance, dbName string) (*couchDatabase, error) {
+	return createCouchDatabaseWithPartitioning(couchInstance, dbName, nil)
+}
+
+//createCouchDatabaseWithPartitioning creates a CouchDB database object, as well as the underlying database if it
+//does not exist. The database is created as a partitioned database if the partitioning is not nil
+func createCouchDatabaseWithPartitioning(couchInstance *couchInstance, dbName string, partitioning *partitioning) (*couchDatabase, error) {
 
 	databaseName, err := mapAndValidateDatabaseName(dbName)
 	if err != nil {
@@ -120,7 +126,7 @@ func createCouchDatabase(couchInstance *couchInstance, dbName string) (*couchDat
 		return nil, err
 	}
 
-	couchDBDatabase := couchDatabase{couchInstance: couchInstance, dbName: databaseName, indexWarmCounter: 1}
+	couchDBDatabase := couchDatabase{couchInstance: couchInstance, dbName: databaseName, indexWarmCounter: 1, partitioning: partitioning}
 
 	// Create CouchDB database upon ledger startup, if it doesn't already exist
 	err = couchDBDatabase.createDatabaseIfNotExist()
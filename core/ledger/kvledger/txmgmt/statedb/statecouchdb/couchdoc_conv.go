@@ -200,6 +200,8 @@ type channelMetadata struct {
 type namespaceDBInfo struct {
 	Namespace string `json:"Namespace"`
 	DBName    string `json:"DBName"`
+	// Partitioning is set for a namespace whose database is a partitioned database
+	Partitioning *partitioning `json:"Partitioning,omitempty"`
 }
 
 func encodeSavepoint(height *version.Height) (*couchDoc, error) {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/pkg/errors"
 )
 
@@ -60,3 +61,20 @@ func decodeValueVersionMetadata(encodedMsg []byte) (*ValueVersionMetadata, error
 	}
 	return val, nil
 }
+
+// decodeSnapshotValue decodes the value bytes returned by the FullScanIterator
+func decodeSnapshotValue(encodedMsg []byte) (*statedb.VersionedValue, error) {
+	val, err := decodeValueVersionMetadata(encodedMsg)
+	if err != nil {
+		return nil, err
+	}
+	ver, metadata, err := decodeVersionAndMetadata(string(val.VersionAndMetadata))
+	if err != nil {
+		return nil, err
+	}
+	value := val.Value
+	if value == nil {
+		value = []byte{}
+	}
+	return &statedb.VersionedValue{Value: value, Metadata: metadata, Version: ver}, nil
+}
@@ -50,12 +50,19 @@ func (vdb *VersionedDB) retrieveMetadata(nsKeysMap map[string][]string) (map[str
 	return executionResults, nil
 }
 
-// retrieveNsMetadata retrieves metadata for a given namespace
+// retrieveNsMetadata retrieves metadata for a given namespace. The ids in the returned metadata are the keys
 func retrieveNsMetadata(db *couchDatabase, keys []string) ([]*docMetadata, error) {
+	docIDs := keys
+	if db.partitioning != nil {
+		docIDs = make([]string, len(keys))
+		for i, key := range keys {
+			docIDs[i] = db.docID(key)
+		}
+	}
 	// construct one batch per group of keys based on maxBatchSize
 	maxBatchSize := db.couchInstance.maxBatchUpdateSize()
 	batches := []batch{}
-	remainingKeys := keys
+	remainingKeys := docIDs
 	for {
 		numKeys := minimum(maxBatchSize, len(remainingKeys))
 		if numKeys == 0 {
@@ -73,6 +80,9 @@ func retrieveNsMetadata(db *couchDatabase, keys []string) ([]*docMetadata, error
 	for _, b := range batches {
 		executionResults = append(executionResults, b.(*subNsMetadataRetriever).executionResult...)
 	}
+	for _, metadata := range executionResults {
+		metadata.ID = db.keyFromDocID(metadata.ID)
+	}
 	return executionResults, nil
 }
 
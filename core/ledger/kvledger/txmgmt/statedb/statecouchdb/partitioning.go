@@ -0,0 +1,392 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// compositeKeyNamespace is the first character of every composite key and
+	// compositeKeyTerminator ends each of the components of a composite key
+	compositeKeyNamespace  = "\x00"
+	compositeKeyTerminator = '\x00'
+	// simpleKeysPartition holds all the keys that are not composite keys
+	simpleKeysPartition = "simplekeys"
+	// partitionSeparator separates the partition from the rest of the document id. CouchDB
+	// requires the document ids in a partitioned database to be of the form "partition:id"
+	partitionSeparator = ":"
+	// compositeKeyPartitionSuffix ends the partitions of the composite keys. The suffix sorts
+	// before the hex digits so that the order of the document ids follows the order of the keys
+	compositeKeyPartitionSuffix = "-"
+	// partitionedIndexSuffix is appended to the design document of an index to name the
+	// partitioned variant of the index in a partitioned database
+	partitionedIndexSuffix = "-partitioned"
+	// partitionKeyQueryOption is the fabric specific option of a rich query that restricts the
+	// query to the composite keys that begin with the given components
+	partitionKeyQueryOption = "partition_key"
+)
+
+// partitioning describes how the documents of a namespace database are spread over the partitions
+// of a CouchDB partitioned database. The partition of a composite key is derived from its first
+// CompositeKeyPrefixLength components, including the object type. All the simple keys are held in
+// a single partition.
+//
+// The partition of a key is encoded in the document id of the key. The encoding preserves the order
+// of the keys so that the range queries on all the documents of the database keep returning the keys
+// in the same order as the range queries on an unpartitioned database:
+//
+//	simple key:    "simplekeys:<key>"
+//	composite key: "<hex of the key prefix up to the last partition component>-:<key>"
+type partitioning struct {
+	CompositeKeyPrefixLength int `json:"compositeKeyPrefixLength"`
+}
+
+// parsePartitioning parses the partitioning declared by a chaincode under
+// "META-INF/statedb/couchdb/partitioning/partitioning.json"
+func parsePartitioning(partitioningData []byte) (*partitioning, error) {
+	p := &partitioning{}
+	decoder := json.NewDecoder(bytes.NewReader(partitioningData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(p); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the partitioning definition")
+	}
+	if p.CompositeKeyPrefixLength < 1 {
+		return nil, errors.Errorf("invalid compositeKeyPrefixLength [%d] in the partitioning definition, it must be at least 1",
+			p.CompositeKeyPrefixLength)
+	}
+	return p, nil
+}
+
+func (p *partitioning) equal(other *partitioning) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return p.CompositeKeyPrefixLength == other.CompositeKeyPrefixLength
+}
+
+// docID returns the document id for the given key, which may also be the bound of a range query.
+// The empty bound of a range query is retained as it stands for the first or the last document
+func (p *partitioning) docID(key string) string {
+	if key == "" {
+		return ""
+	}
+	if !strings.HasPrefix(key, compositeKeyNamespace) {
+		return simpleKeysPartition + partitionSeparator + key
+	}
+	return hex.EncodeToString([]byte(p.partitionPrefix(key))) + compositeKeyPartitionSuffix + partitionSeparator + key
+}
+
+// partitionPrefix returns the prefix of the composite key that ends with the last component of
+// the partition. A key with fewer components than the partition is a prefix by itself
+func (p *partitioning) partitionPrefix(compositeKey string) string {
+	numComponents := 0
+	for i := len(compositeKeyNamespace); i < len(compositeKey); i++ {
+		if compositeKey[i] != compositeKeyTerminator {
+			continue
+		}
+		numComponents++
+		if numComponents == p.CompositeKeyPrefixLength {
+			return compositeKey[:i+1]
+		}
+	}
+	return compositeKey
+}
+
+// keyFromDocID returns the key encoded in the given document id
+func keyFromDocID(docID string) string {
+	i := strings.Index(docID, partitionSeparator)
+	if i < 0 {
+		return docID
+	}
+	return docID[i+1:]
+}
+
+// partitionOf returns the partition of the given document id
+func partitionOf(docID string) string {
+	i := strings.Index(docID, partitionSeparator)
+	if i < 0 {
+		return ""
+	}
+	return docID[:i]
+}
+
+// docID returns the id of the document that holds the given key
+func (dbclient *couchDatabase) docID(key string) string {
+	if dbclient.partitioning == nil {
+		return key
+	}
+	return dbclient.partitioning.docID(key)
+}
+
+// keyFromDocID returns the key that is held by the document with the given id
+func (dbclient *couchDatabase) keyFromDocID(docID string) string {
+	if dbclient.partitioning == nil {
+		return docID
+	}
+	return keyFromDocID(docID)
+}
+
+// rangePartition returns the partition that holds all the documents in the range [startID, endID) of the
+// document ids, or an empty string if the database is not partitioned or the range spans several partitions
+func (dbclient *couchDatabase) rangePartition(startID, endID string) string {
+	if dbclient.partitioning == nil || startID == "" || endID == "" {
+		return ""
+	}
+	partition := partitionOf(startID)
+	if partition == "" || partition != partitionOf(endID) {
+		return ""
+	}
+	return partition
+}
+
+// partitionPath returns the path elements of the given endpoint, scoped to the given partition if any
+func partitionPath(partition, endpoint string) []string {
+	if partition == "" {
+		return []string{endpoint}
+	}
+	return []string{"_partition", partition, endpoint}
+}
+
+// indexDefinitionsForPartitionedDB returns the index definitions to create in a partitioned database for the
+// given index of a chaincode. The index itself is created as a global index so that it serves the queries of the
+// chaincode as it does in an unpartitioned database. A partitioned variant of the index is created in a design
+// document with the suffix "-partitioned" for the queries that are restricted to a partition with the option
+// "partition_key"
+func indexDefinitionsForPartitionedDB(indexDefinition string) ([]string, error) {
+	globalIndex := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(indexDefinition), &globalIndex); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the index definition")
+	}
+	partitionedIndex := make(map[string]interface{}, len(globalIndex))
+	for k, v := range globalIndex {
+		partitionedIndex[k] = v
+	}
+	globalIndex["partitioned"] = false
+	partitionedIndex["partitioned"] = true
+	ddoc, _ := globalIndex["ddoc"].(string)
+	partitionedIndex["ddoc"] = partitionedDesignDocument(ddoc)
+
+	var indexDefinitions []string
+	for _, index := range []map[string]interface{}{globalIndex, partitionedIndex} {
+		indexJSON, err := json.Marshal(index)
+		if err != nil {
+			return nil, errors.Wrap(err, "error marshalling the index definition")
+		}
+		indexDefinitions = append(indexDefinitions, string(indexJSON))
+	}
+	return indexDefinitions, nil
+}
+
+// partitionedDesignDocument returns the design document of the partitioned variant of the
+// indexes of the given design document
+func partitionedDesignDocument(ddoc string) string {
+	if ddoc == "" {
+		return "partitioned"
+	}
+	return ddoc + partitionedIndexSuffix
+}
+
+// scopeQueryToPartitionKey processes the option "partition_key" of a rich query. The option lists the leading
+// components of the composite keys that the query is restricted to. The option is removed from the query and the
+// selector of the query is extended to match only the documents of such keys. If all such keys belong to a
+// single partition of the database, the partition is returned so that the query runs on that partition only, in
+// which case the use_index of the query is redirected to the partitioned variant of the index.
+// The query is returned as is if it does not have the option
+func (dbclient *couchDatabase) scopeQueryToPartitionKey(query string) (string, string, error) {
+	jsonQueryMap := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewBufferString(query))
+	decoder.UseNumber()
+	if err := decoder.Decode(&jsonQueryMap); err != nil {
+		return "", "", errors.Wrap(err, "error unmarshalling the query")
+	}
+	partitionKey, ok := jsonQueryMap[partitionKeyQueryOption]
+	if !ok {
+		return query, "", nil
+	}
+	delete(jsonQueryMap, partitionKeyQueryOption)
+
+	components, ok := partitionKey.([]interface{})
+	if !ok || len(components) == 0 {
+		return "", "", errors.Errorf("the %s of the query must be a non-empty array of strings", partitionKeyQueryOption)
+	}
+	keyPrefix := compositeKeyNamespace
+	for _, c := range components {
+		component, ok := c.(string)
+		if !ok || !validCompositeKeyComponent(component) {
+			return "", "", errors.Errorf("invalid component [%v] in the %s of the query", c, partitionKeyQueryOption)
+		}
+		keyPrefix += component + string(compositeKeyTerminator)
+	}
+
+	// the documents of the keys that begin with the prefix are matched by the key that follows the
+	// partition, as the partition of a key with fewer components than the partitioning varies
+	docIDPattern := "^" + quoteDocIDPattern(keyPrefix)
+	if dbclient.partitioning != nil {
+		docIDPattern = "^[^" + partitionSeparator + "]*" + partitionSeparator + quoteDocIDPattern(keyPrefix)
+	}
+	docIDSelector := map[string]interface{}{
+		idField: map[string]interface{}{"$regex": docIDPattern},
+	}
+	if selector, ok := jsonQueryMap["selector"]; ok {
+		jsonQueryMap["selector"] = map[string]interface{}{"$and": []interface{}{selector, docIDSelector}}
+	} else {
+		jsonQueryMap["selector"] = docIDSelector
+	}
+
+	partition := dbclient.rangePartition(dbclient.docID(keyPrefix), dbclient.docID(keyPrefix+string(utf8.MaxRune)))
+	if partition != "" {
+		if useIndex, ok := jsonQueryMap["use_index"]; ok {
+			jsonQueryMap["use_index"] = partitionedUseIndex(useIndex)
+		}
+	}
+
+	scopedQuery, err := json.Marshal(jsonQueryMap)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error marshalling the query")
+	}
+	return string(scopedQuery), partition, nil
+}
+
+// validCompositeKeyComponent mirrors the validation of the attributes of a composite key by the chaincode shim
+func validCompositeKeyComponent(component string) bool {
+	return utf8.ValidString(component) &&
+		!strings.ContainsRune(component, rune(compositeKeyTerminator)) &&
+		!strings.ContainsRune(component, utf8.MaxRune)
+}
+
+// quoteDocIDPattern escapes the given string for a regular expression of CouchDB. The terminators of the
+// composite key components are escaped as well so that the pattern does not carry the NUL character
+func quoteDocIDPattern(s string) string {
+	return strings.Replace(regexp.QuoteMeta(s), string(compositeKeyTerminator), `\x00`, -1)
+}
+
+// partitionedUseIndex redirects the given use_index of a query to the partitioned variant of the index
+func partitionedUseIndex(useIndex interface{}) interface{} {
+	redirect := func(ddoc string) string {
+		if strings.HasPrefix(ddoc, "_design/") {
+			return "_design/" + partitionedDesignDocument(strings.TrimPrefix(ddoc, "_design/"))
+		}
+		return partitionedDesignDocument(ddoc)
+	}
+	switch useIndex := useIndex.(type) {
+	case string:
+		return redirect(useIndex)
+	case []interface{}:
+		if len(useIndex) == 0 {
+			return useIndex
+		}
+		if ddoc, ok := useIndex[0].(string); ok {
+			return append([]interface{}{redirect(ddoc)}, useIndex[1:]...)
+		}
+	}
+	return useIndex
+}
+
+// groupKeysByPartition splits the given keys into groups of at most maxBatchSize keys each (or into a
+// single group if maxBatchSize is not positive). If the database is partitioned, the keys of a partition
+// are kept in the same group unless the partition alone has more than maxBatchSize keys. The keys of
+// a group are then committed by a single bulk update that touches as few partitions as possible
+func (dbclient *couchDatabase) groupKeysByPartition(keys []string, maxBatchSize int) [][]string {
+	if maxBatchSize <= 0 {
+		return [][]string{keys}
+	}
+	if dbclient.partitioning == nil {
+		var groups [][]string
+		for len(keys) > 0 {
+			n := minimum(maxBatchSize, len(keys))
+			groups = append(groups, keys[:n])
+			keys = keys[n:]
+		}
+		return groups
+	}
+
+	docIDs := make(map[string]string, len(keys))
+	for _, key := range keys {
+		docIDs[key] = dbclient.docID(key)
+	}
+	sortedKeys := append([]string{}, keys...)
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		return docIDs[sortedKeys[i]] < docIDs[sortedKeys[j]]
+	})
+
+	var groups [][]string
+	var group []string
+	for len(sortedKeys) > 0 {
+		// the keys of the next partition are contiguous in the sorted keys
+		partition := partitionOf(docIDs[sortedKeys[0]])
+		n := 1
+		for n < len(sortedKeys) && partitionOf(docIDs[sortedKeys[n]]) == partition {
+			n++
+		}
+		partitionKeys := sortedKeys[:n]
+		sortedKeys = sortedKeys[n:]
+
+		if len(group) > 0 && len(group)+len(partitionKeys) > maxBatchSize {
+			groups = append(groups, group)
+			group = nil
+		}
+		for len(partitionKeys) > maxBatchSize {
+			groups = append(groups, partitionKeys[:maxBatchSize])
+			partitionKeys = partitionKeys[maxBatchSize:]
+		}
+		group = append(group, partitionKeys...)
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// ProcessPartitioningForChaincodeDeploy implements method in statedb.PartitionCapable interface. It creates the
+// database of the namespace as a CouchDB partitioned database with the given partitioning. The partitioning is
+// recorded in the channel metadata, so that the documents of the namespace keep being mapped to the same partitions.
+// The partitioning applies only to a namespace whose database does not exist yet. The database of a namespace that
+// already has data keeps its layout, as the layout of the database does not alter the results of the queries
+func (vdb *VersionedDB) ProcessPartitioningForChaincodeDeploy(namespace string, partitioningData []byte) error {
+	p, err := parsePartitioning(partitioningData)
+	if err != nil {
+		return err
+	}
+
+	vdb.mux.Lock()
+	defer vdb.mux.Unlock()
+	if nsDBInfo, ok := vdb.channelMetadata.NamespaceDBsInfo[namespace]; ok {
+		if !nsDBInfo.Partitioning.equal(p) {
+			logger.Warnf("The database [%s] of namespace [%s] on channel [%s] already exists, retaining its partitioning [%+v] instead of [%+v]",
+				nsDBInfo.DBName, namespace, vdb.chainName, nsDBInfo.Partitioning, p)
+		}
+		return nil
+	}
+
+	namespaceDBName := constructNamespaceDBName(vdb.chainName, namespace)
+	db, err := createCouchDatabaseWithPartitioning(vdb.couchInstance, namespaceDBName, p)
+	if err != nil {
+		return err
+	}
+	vdb.channelMetadata.NamespaceDBsInfo[namespace] = &namespaceDBInfo{
+		Namespace:    namespace,
+		DBName:       namespaceDBName,
+		Partitioning: p,
+	}
+	if err := vdb.writeChannelMetadata(); err != nil {
+		delete(vdb.channelMetadata.NamespaceDBsInfo, namespace)
+		return err
+	}
+	vdb.namespaceDBs[namespace] = db
+	logger.Infof("Created the partitioned database [%s] for namespace [%s] on channel [%s] with partitioning [%+v]",
+		namespaceDBName, namespace, vdb.chainName, p)
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func compositeKey(objectType string, attributes ...string) string {
+	return "\x00" + objectType + "\x00" + strings.Join(append(attributes, ""), "\x00")
+}
+
+func TestParsePartitioning(t *testing.T) {
+	p, err := parsePartitioning([]byte(`{"compositeKeyPrefixLength":2}`))
+	require.NoError(t, err)
+	require.Equal(t, &partitioning{CompositeKeyPrefixLength: 2}, p)
+
+	_, err = parsePartitioning([]byte(`{"compositeKeyPrefixLength":0}`))
+	require.EqualError(t, err, "invalid compositeKeyPrefixLength [0] in the partitioning definition, it must be at least 1")
+	_, err = parsePartitioning([]byte(`{"partitions":2}`))
+	require.EqualError(t, err, `error unmarshalling the partitioning definition: json: unknown field "partitions"`)
+}
+
+func TestPartitioningDocIDs(t *testing.T) {
+	p := &partitioning{CompositeKeyPrefixLength: 2}
+	// the keys and the bounds of the range queries in their expected order
+	keys := []string{
+		"\x00",
+		compositeKey("asset"),
+		compositeKey("asset", "a"),
+		compositeKey("asset", "a", "1"),
+		compositeKey("asset", "a", "2"),
+		compositeKey("asset", "a") + "\U0010FFFF",
+		compositeKey("asset", "ab"),
+		compositeKey("asset", "b", "1"),
+		compositeKey("asset") + "\U0010FFFF",
+		compositeKey("assets", "a", "1"),
+		compositeKey("owner", "tom"),
+		"\x01",
+		"_",
+		"asset",
+		"asset:1",
+		"key1",
+		"key2",
+	}
+	var docIDs []string
+	for _, key := range keys {
+		docID := p.docID(key)
+		require.Equal(t, key, keyFromDocID(docID))
+		require.NotEmpty(t, partitionOf(docID))
+		require.False(t, strings.HasPrefix(docID, "_"))
+		docIDs = append(docIDs, docID)
+	}
+	require.True(t, sort.StringsAreSorted(docIDs), "%q", docIDs)
+
+	require.Equal(t, "simplekeys:key1", p.docID("key1"))
+	require.Equal(t, "006173736574006100-:"+compositeKey("asset", "a", "1"), p.docID(compositeKey("asset", "a", "1")))
+	require.Equal(t, "", p.docID(""))
+
+	db := &couchDatabase{partitioning: p}
+	require.Equal(t, "006173736574006100-", db.rangePartition(db.docID(compositeKey("asset", "a")), db.docID(compositeKey("asset", "a")+"\U0010FFFF")))
+	require.Equal(t, "", db.rangePartition(db.docID(compositeKey("asset")), db.docID(compositeKey("asset")+"\U0010FFFF")))
+	require.Equal(t, "simplekeys", db.rangePartition(db.docID("key1"), db.docID("key9")))
+	require.Equal(t, "", db.rangePartition(db.docID("key1"), ""))
+
+	unpartitionedDB := &couchDatabase{}
+	require.Equal(t, compositeKey("asset", "a"), unpartitionedDB.docID(compositeKey("asset", "a")))
+	require.Equal(t, "key:1", unpartitionedDB.keyFromDocID("key:1"))
+	require.Equal(t, "", unpartitionedDB.rangePartition("key1", "key9"))
+}
+
+func TestScopeQueryToPartitionKey(t *testing.T) {
+	db := &couchDatabase{partitioning: &partitioning{CompositeKeyPrefixLength: 2}}
+
+	query, partition, err := db.scopeQueryToPartitionKey(`{"selector":{"owner":"tom"}}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"selector":{"owner":"tom"}}`, query)
+	require.Equal(t, "", partition)
+
+	query, partition, err = db.scopeQueryToPartitionKey(`{"selector":{"owner":"tom"},"partition_key":["asset","a"],"use_index":["indexOwnerDoc","indexOwner"]}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"selector":{"$and":[{"owner":"tom"},{"_id":{"$regex":"^[^:]*:\\x00asset\\x00a\\x00"}}]},"use_index":["indexOwnerDoc-partitioned","indexOwner"]}`, query)
+	require.Equal(t, "006173736574006100-", partition)
+
+	query, partition, err = db.scopeQueryToPartitionKey(`{"partition_key":["asset"],"use_index":"_design/indexOwnerDoc"}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"selector":{"_id":{"$regex":"^[^:]*:\\x00asset\\x00"}},"use_index":"_design/indexOwnerDoc"}`, query)
+	require.Equal(t, "", partition)
+
+	unpartitionedDB := &couchDatabase{}
+	query, partition, err = unpartitionedDB.scopeQueryToPartitionKey(`{"selector":{"owner":"tom"},"partition_key":["asset","a.b"]}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"selector":{"$and":[{"owner":"tom"},{"_id":{"$regex":"^\\x00asset\\x00a\\.b\\x00"}}]}}`, query)
+	require.Equal(t, "", partition)
+
+	for _, partitionKey := range []string{`[]`, `"asset"`, `[1]`, `["asset\u0000a"]`, `["\udbff\udfff"]`} {
+		_, _, err = db.scopeQueryToPartitionKey(`{"selector":{"owner":"tom"},"partition_key":` + partitionKey + `}`)
+		require.Error(t, err, partitionKey)
+	}
+}
+
+func TestIndexDefinitionsForPartitionedDB(t *testing.T) {
+	indexDefinitions, err := indexDefinitionsForPartitionedDB(`{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`)
+	require.NoError(t, err)
+	require.Len(t, indexDefinitions, 2)
+	require.JSONEq(t, `{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json","partitioned":false}`, indexDefinitions[0])
+	require.JSONEq(t, `{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc-partitioned","name":"indexOwner","type":"json","partitioned":true}`, indexDefinitions[1])
+
+	indexDefinitions, err = indexDefinitionsForPartitionedDB(`{"index":{"fields":["owner"]}}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"index":{"fields":["owner"]},"ddoc":"partitioned","partitioned":true}`, indexDefinitions[1])
+
+	_, err = indexDefinitionsForPartitionedDB(`not-json`)
+	require.Error(t, err)
+}
+
+func TestGroupKeysByPartition(t *testing.T) {
+	db := &couchDatabase{partitioning: &partitioning{CompositeKeyPrefixLength: 1}}
+	keys := []string{
+		compositeKey("b", "1"), "key1", compositeKey("a", "1"), compositeKey("b", "2"),
+		compositeKey("c", "1"), compositeKey("b", "3"), compositeKey("a", "2"), "key2",
+	}
+	require.Equal(t, [][]string{
+		{compositeKey("a", "1"), compositeKey("a", "2")},
+		{compositeKey("b", "1"), compositeKey("b", "2"), compositeKey("b", "3")},
+		{compositeKey("c", "1"), "key1", "key2"},
+	}, db.groupKeysByPartition(keys, 3))
+	require.Equal(t, [][]string{
+		{compositeKey("a", "1"), compositeKey("a", "2")},
+		{compositeKey("b", "1"), compositeKey("b", "2")},
+		{compositeKey("b", "3"), compositeKey("c", "1")},
+		{"key1", "key2"},
+	}, db.groupKeysByPartition(keys, 2))
+	require.Equal(t, [][]string{keys}, db.groupKeysByPartition(keys, 0))
+
+	unpartitionedDB := &couchDatabase{}
+	require.Equal(t, [][]string{keys[:3], keys[3:6], keys[6:]}, unpartitionedDB.groupKeysByPartition(keys, 3))
+}
+
+func TestPartitionedNamespace(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+
+	db, err := vdbEnv.DBProvider.GetDBHandle("testpartitionednamespace", nil)
+	require.NoError(t, err)
+	partitionCapable, ok := db.(statedb.PartitionCapable)
+	require.True(t, ok)
+	require.NoError(t, partitionCapable.ProcessPartitioningForChaincodeDeploy("ns1", []byte(`{"compositeKeyPrefixLength":2}`)))
+	require.EqualError(t, partitionCapable.ProcessPartitioningForChaincodeDeploy("ns2", []byte(`{}`)),
+		"invalid compositeKeyPrefixLength [0] in the partitioning definition, it must be at least 1")
+
+	indexCapable := db.(statedb.IndexCapable)
+	require.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json": []byte(`{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`),
+	}))
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", compositeKey("asset", "a", "1"), []byte(`{"owner":"tom"}`), version.NewHeight(1, 1))
+	batch.Put("ns1", compositeKey("asset", "a", "2"), []byte(`{"owner":"jerry"}`), version.NewHeight(1, 2))
+	batch.Put("ns1", compositeKey("asset", "b", "1"), []byte(`{"owner":"tom"}`), version.NewHeight(1, 3))
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 4))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)))
+
+	checkPartitionedNamespace := func(db statedb.VersionedDB) {
+		vv, err := db.GetState("ns1", compositeKey("asset", "a", "1"))
+		require.NoError(t, err)
+		require.Equal(t, version.NewHeight(1, 1), vv.Version)
+		vv, err = db.GetState("ns1", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), vv.Value)
+
+		scanKeys := func(startKey, endKey string) []string {
+			itr, err := db.GetStateRangeScanIterator("ns1", startKey, endKey)
+			require.NoError(t, err)
+			defer itr.Close()
+			var keys []string
+			for {
+				result, err := itr.Next()
+				require.NoError(t, err)
+				if result == nil {
+					return keys
+				}
+				keys = append(keys, result.(*statedb.VersionedKV).Key)
+			}
+		}
+		require.Equal(t, []string{compositeKey("asset", "a", "1"), compositeKey("asset", "a", "2"), compositeKey("asset", "b", "1"), "key1"}, scanKeys("", ""))
+		require.Equal(t, []string{compositeKey("asset", "a", "1"), compositeKey("asset", "a", "2")}, scanKeys(compositeKey("asset", "a"), compositeKey("asset", "a")+"\U0010FFFF"))
+		require.Equal(t, []string{"key1"}, scanKeys("key", "key9"))
+
+		queryKeys := func(query string) []string {
+			itr, err := db.ExecuteQuery("ns1", query)
+			require.NoError(t, err)
+			defer itr.Close()
+			var keys []string
+			for {
+				result, err := itr.Next()
+				require.NoError(t, err)
+				if result == nil {
+					return keys
+				}
+				keys = append(keys, result.(*statedb.VersionedKV).Key)
+			}
+		}
+		require.ElementsMatch(t, []string{compositeKey("asset", "a", "1"), compositeKey("asset", "b", "1")}, queryKeys(`{"selector":{"owner":"tom"}}`))
+		require.Equal(t, []string{compositeKey("asset", "a", "1")}, queryKeys(`{"selector":{"owner":"tom"},"partition_key":["asset","a"]}`))
+		require.Equal(t, []string{compositeKey("asset", "a", "1")}, queryKeys(`{"selector":{"owner":"tom"},"partition_key":["asset","a"],"use_index":"indexOwnerDoc"}`))
+		require.ElementsMatch(t, []string{compositeKey("asset", "a", "1"), compositeKey("asset", "b", "1")}, queryKeys(`{"selector":{"owner":"tom"},"partition_key":["asset"]}`))
+	}
+	checkPartitionedNamespace(db)
+
+	// the existing layout of a namespace database is retained
+	require.NoError(t, partitionCapable.ProcessPartitioningForChaincodeDeploy("ns1", []byte(`{"compositeKeyPrefixLength":1}`)))
+	require.Equal(t, &partitioning{CompositeKeyPrefixLength: 2}, db.(*VersionedDB).channelMetadata.NamespaceDBsInfo["ns1"].Partitioning)
+
+	// the updates and the deletes find the documents of the keys
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", compositeKey("asset", "a", "2"), []byte(`{"owner":"fred"}`), version.NewHeight(2, 1))
+	batch.Delete("ns1", compositeKey("asset", "b", "1"), version.NewHeight(2, 2))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 2)))
+	vv, err := db.GetState("ns1", compositeKey("asset", "a", "2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"owner":"fred"}`), vv.Value)
+	vv, err = db.GetState("ns1", compositeKey("asset", "b", "1"))
+	require.NoError(t, err)
+	require.Nil(t, vv)
+
+	// the partitioning is retained by a reopened db
+	vdbEnv.closeAndReopen()
+	db, err = vdbEnv.DBProvider.GetDBHandle("testpartitionednamespace", nil)
+	require.NoError(t, err)
+	nsDB, err := db.(*VersionedDB).getNamespaceDBHandle("ns1")
+	require.NoError(t, err)
+	require.Equal(t, &partitioning{CompositeKeyPrefixLength: 2}, nsDB.partitioning)
+	dbInfo, _, err := nsDB.getDatabaseInfo()
+	require.NoError(t, err)
+	require.True(t, dbInfo.Props.Partitioned)
+
+	// the full scan returns the keys in order
+	fullScanItr, _, err := db.GetFullScanIterator(func(ns string) bool { return ns != "ns1" })
+	require.NoError(t, err)
+	defer fullScanItr.Close()
+	var scannedKeys []string
+	for {
+		ck, _, err := fullScanItr.Next()
+		require.NoError(t, err)
+		if ck == nil {
+			break
+		}
+		scannedKeys = append(scannedKeys, ck.Key)
+	}
+	require.Equal(t, []string{compositeKey("asset", "a", "1"), compositeKey("asset", "a", "2"), "key1"}, scannedKeys)
+}
@@ -0,0 +1,414 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// queryPlanCheckAllow reports the query plans without acting upon the queries that run without a usable index
+	queryPlanCheckAllow = "allow"
+	// queryPlanCheckWarn additionally logs a warning for each query that runs without a usable index
+	queryPlanCheckWarn = "warn"
+	// queryPlanCheckReject fails the queries that would run without a usable index
+	queryPlanCheckReject = "reject"
+
+	// maxQueryPlanReports bounds the number of reports that are retained. When the bound is reached,
+	// the report of the least recently executed query is dropped
+	maxQueryPlanReports = 1000
+)
+
+func validateQueryPlanCheck(mode string) error {
+	switch mode {
+	case "", queryPlanCheckAllow, queryPlanCheckWarn, queryPlanCheckReject:
+		return nil
+	default:
+		return errors.Errorf("invalid query plan check [%s], expected one of [%s, %s, %s]",
+			mode, queryPlanCheckAllow, queryPlanCheckWarn, queryPlanCheckReject)
+	}
+}
+
+// queryPlanReport describes how CouchDB executes a rich query issued by a chaincode. The query is carried in
+// its redacted form (see redactQuery) as the literals of a query may carry the data of the channel, including the
+// private data. For a query that CouchDB executes without a usable index, the report carries an index definition
+// that would serve the query. The suggested definition can be packaged with the chaincode under
+// "META-INF/statedb/couchdb/indexes"
+type queryPlanReport struct {
+	Channel        string          `json:"channel"`
+	Namespace      string          `json:"namespace"`
+	Query          string          `json:"query"`
+	Index          string          `json:"index"`
+	FullScan       bool            `json:"full_scan"`
+	SuggestedIndex json.RawMessage `json:"suggested_index,omitempty"`
+	Executions     uint64          `json:"executions"`
+	Rejections     uint64          `json:"rejections"`
+	LastExecuted   time.Time       `json:"last_executed"`
+}
+
+func newQueryPlanReport(channel, namespace, query string, plan *queryPlan) (*queryPlanReport, error) {
+	report := &queryPlanReport{
+		Channel:   channel,
+		Namespace: namespace,
+		Query:     query,
+		Index:     plan.Index.Name,
+		FullScan:  plan.Index.Type == "special",
+	}
+	if plan.Index.DesignDocument != "" {
+		report.Index = plan.Index.DesignDocument + "/" + plan.Index.Name
+	}
+	if !report.FullScan {
+		return report, nil
+	}
+	suggestedIndex, err := suggestIndex(query)
+	if err != nil {
+		return nil, err
+	}
+	report.SuggestedIndex = suggestedIndex
+	return report, nil
+}
+
+// suggestIndex returns the definition of a json index for the fields that the query sorts on, followed by
+// the remaining fields that the selector of the query constrains. Nil is returned if the query constrains
+// no field that an index can serve, for instance, when all the fields appear only under an "$or" operator
+func suggestIndex(query string) (json.RawMessage, error) {
+	q := struct {
+		Selector map[string]interface{} `json:"selector"`
+		Sort     []interface{}          `json:"sort"`
+	}{}
+	decoder := json.NewDecoder(bytes.NewBufferString(query))
+	decoder.UseNumber()
+	if err := decoder.Decode(&q); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the query")
+	}
+
+	var fields []string
+	added := map[string]bool{}
+	for _, s := range q.Sort {
+		switch s := s.(type) {
+		case string:
+			fields = append(fields, s)
+			added[s] = true
+		case map[string]interface{}:
+			for field := range s {
+				fields = append(fields, field)
+				added[field] = true
+			}
+		}
+	}
+	selectorFields := map[string]bool{}
+	collectSelectorFields(q.Selector, "", selectorFields)
+	var remainingFields []string
+	for field := range selectorFields {
+		if !added[field] {
+			remainingFields = append(remainingFields, field)
+		}
+	}
+	sort.Strings(remainingFields)
+	fields = append(fields, remainingFields...)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	name := "index-" + strings.Join(fields, "-")
+	indexDef := struct {
+		Index struct {
+			Fields []string `json:"fields"`
+		} `json:"index"`
+		DesignDocument string `json:"ddoc"`
+		Name           string `json:"name"`
+		Type           string `json:"type"`
+	}{
+		DesignDocument: name + "-doc",
+		Name:           name,
+		Type:           "json",
+	}
+	indexDef.Index.Fields = fields
+	return json.Marshal(indexDef)
+}
+
+// redactQuery returns the shape of the query, i.e., the query in which each literal of the selector is replaced
+// by the placeholder "?" and the bookmark, if any, is dropped. The field names, the operators, and the other
+// options of the query are retained so that the shape still identifies the index that would serve the query.
+// The queries that differ only in their literals have the same shape
+func redactQuery(query string) (string, error) {
+	q := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewBufferString(query))
+	decoder.UseNumber()
+	if err := decoder.Decode(&q); err != nil {
+		return "", errors.Wrap(err, "error unmarshalling the query")
+	}
+	if selector, ok := q["selector"]; ok {
+		q["selector"] = redactValue(selector)
+	}
+	delete(q, "bookmark")
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(q); err != nil {
+		return "", errors.Wrap(err, "error marshalling the redacted query")
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			redacted[k] = redactValue(v)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, v := range value {
+			redacted[i] = redactValue(v)
+		}
+		return redacted
+	default:
+		return "?"
+	}
+}
+
+// collectSelectorFields adds to the given set the fields that are constrained by the selector via either an
+// implicit equality or a condition operator. The fields combined under "$or", "$nor", and "$not" are skipped
+// as CouchDB does not use a json index for such combinations
+func collectSelectorFields(selector map[string]interface{}, prefix string, fields map[string]bool) {
+	for key, value := range selector {
+		if key == "$and" {
+			if subSelectors, ok := value.([]interface{}); ok {
+				for _, subSelector := range subSelectors {
+					if subSelector, ok := subSelector.(map[string]interface{}); ok {
+						collectSelectorFields(subSelector, prefix, fields)
+					}
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		subSelector, ok := value.(map[string]interface{})
+		if !ok || isOperatorObject(subSelector) {
+			fields[field] = true
+			continue
+		}
+		collectSelectorFields(subSelector, field, fields)
+	}
+}
+
+func isOperatorObject(m map[string]interface{}) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+type queryPlanReportKey struct {
+	channel, namespace, query string
+}
+
+// queryPlanReports retains the query plan reports of the rich queries that are executed on the databases of a
+// VersionedDBProvider. A retained report also serves as the verdict for the subsequent executions of the same
+// query so that the plan of a query is obtained from CouchDB only once, until the indexes of the namespace change
+type queryPlanReports struct {
+	mutex   sync.Mutex
+	reports map[queryPlanReportKey]*queryPlanReport
+}
+
+func newQueryPlanReports() *queryPlanReports {
+	return &queryPlanReports{
+		reports: make(map[queryPlanReportKey]*queryPlanReport),
+	}
+}
+
+func (r *queryPlanReports) get(channel, namespace, query string) *queryPlanReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.reports[queryPlanReportKey{channel, namespace, query}]
+}
+
+// recordExecution records an execution of the query covered by the given report, adding the report if it is
+// not yet retained, and returns a copy of the updated report
+func (r *queryPlanReports) recordExecution(report *queryPlanReport, rejected bool) queryPlanReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	key := queryPlanReportKey{report.Channel, report.Namespace, report.Query}
+	if existing, ok := r.reports[key]; ok {
+		report = existing
+	} else {
+		if len(r.reports) >= maxQueryPlanReports {
+			r.evictLeastRecentlyExecuted()
+		}
+		r.reports[key] = report
+	}
+	report.Executions++
+	if rejected {
+		report.Rejections++
+	}
+	report.LastExecuted = time.Now()
+	return *report
+}
+
+func (r *queryPlanReports) evictLeastRecentlyExecuted() {
+	var evictKey queryPlanReportKey
+	var evictTime time.Time
+	first := true
+	for key, report := range r.reports {
+		if first || report.LastExecuted.Before(evictTime) {
+			evictKey, evictTime, first = key, report.LastExecuted, false
+		}
+	}
+	delete(r.reports, evictKey)
+}
+
+// clear drops the reports of the given namespace as the plans of its queries may change with its indexes
+func (r *queryPlanReports) clear(channel, namespace string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key := range r.reports {
+		if key.channel == channel && key.namespace == namespace {
+			delete(r.reports, key)
+		}
+	}
+}
+
+// list returns copies of the reports that match the given channel and namespace, sorted by channel, namespace,
+// and query. An empty channel or namespace matches all. If fullScanOnly is set, only the reports of the queries
+// that run without a usable index are returned
+func (r *queryPlanReports) list(channel, namespace string, fullScanOnly bool) []queryPlanReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	reports := []queryPlanReport{}
+	for key, report := range r.reports {
+		if (channel != "" && key.channel != channel) ||
+			(namespace != "" && key.namespace != namespace) ||
+			(fullScanOnly && !report.FullScan) {
+			continue
+		}
+		reports = append(reports, *report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Channel != reports[j].Channel {
+			return reports[i].Channel < reports[j].Channel
+		}
+		if reports[i].Namespace != reports[j].Namespace {
+			return reports[i].Namespace < reports[j].Namespace
+		}
+		return reports[i].Query < reports[j].Query
+	})
+	return reports
+}
+
+// checkQueryPlan checks the plan of the query against the configured query plan check. The query is the
+// one issued by the chaincode, whereas the queryString carries the additional options that are applied
+// by the statedb. The plan is obtained for the partition that the query runs on, if any. An error in
+// obtaining the plan is logged and does not fail the query. The query is reported, logged, and included
+// in the errors only in its redacted form, and the plan is obtained only once for all the queries of
+// the same shape
+func (vdb *VersionedDB) checkQueryPlan(namespace string, db *couchDatabase, partition, query, queryString string) error {
+	mode := vdb.couchInstance.conf.QueryPlanCheck
+	if mode == "" {
+		return nil
+	}
+	redactedQuery, err := redactQuery(query)
+	if err != nil {
+		logger.Warnf("Skipping the query plan check of a query on namespace [%s] of channel [%s]: %s",
+			namespace, vdb.chainName, err)
+		return nil
+	}
+	report := vdb.queryPlans.get(vdb.chainName, namespace, redactedQuery)
+	if report == nil {
+		plan, err := db.explainQuery(partition, queryString)
+		if err != nil {
+			logger.Warnf("Skipping the query plan check of the query [%s] on namespace [%s] of channel [%s]: %s",
+				redactedQuery, namespace, vdb.chainName, err)
+			return nil
+		}
+		if report, err = newQueryPlanReport(vdb.chainName, namespace, redactedQuery, plan); err != nil {
+			logger.Warnf("Skipping the query plan check of the query [%s] on namespace [%s] of channel [%s]: %s",
+				redactedQuery, namespace, vdb.chainName, err)
+			return nil
+		}
+	}
+
+	reject := report.FullScan && mode == queryPlanCheckReject
+	r := vdb.queryPlans.recordExecution(report, reject)
+	if !r.FullScan {
+		return nil
+	}
+	switch mode {
+	case queryPlanCheckWarn:
+		logger.Warnf("The query [%s] on namespace [%s] of channel [%s] runs without a usable index. Suggested index: %s",
+			r.Query, namespace, vdb.chainName, r.SuggestedIndex)
+	case queryPlanCheckReject:
+		return errors.Errorf("the query [%s] on namespace [%s] of channel [%s] is rejected as it would run without a usable index. Suggested index: %s",
+			r.Query, namespace, vdb.chainName, r.SuggestedIndex)
+	}
+	return nil
+}
+
+// QueryPlanReportsHandler returns the handler that serves the query plan reports of the rich queries that are
+// executed on the databases of this provider
+func (provider *VersionedDBProvider) QueryPlanReportsHandler() http.Handler {
+	return &queryPlanReportsHandler{reports: provider.queryPlans}
+}
+
+// queryPlanReportsHandler serves the query plan reports as JSON. The optional request parameters
+// "channel" and "namespace" restrict the reports to the given channel and namespace and the parameter
+// "fullscan=true" restricts the reports to the queries that run without a usable index
+type queryPlanReportsHandler struct {
+	reports *queryPlanReports
+}
+
+type queryPlanReportsResponse struct {
+	Reports []queryPlanReport `json:"reports"`
+}
+
+type queryPlanErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *queryPlanReportsHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		h.sendResponse(resp, http.StatusBadRequest, fmt.Errorf("invalid request method: %s", req.Method))
+		return
+	}
+	params := req.URL.Query()
+	reports := h.reports.list(params.Get("channel"), params.Get("namespace"), params.Get("fullscan") == "true")
+	h.sendResponse(resp, http.StatusOK, &queryPlanReportsResponse{Reports: reports})
+}
+
+func (h *queryPlanReportsHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	if err, ok := payload.(error); ok {
+		payload = &queryPlanErrorResponse{Error: err.Error()}
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
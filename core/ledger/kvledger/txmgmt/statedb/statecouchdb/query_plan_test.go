@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateQueryPlanCheck(t *testing.T) {
+	for _, mode := range []string{"", "allow", "warn", "reject"} {
+		require.NoError(t, validateQueryPlanCheck(mode))
+	}
+	require.EqualError(t, validateQueryPlanCheck("deny"), "invalid query plan check [deny], expected one of [allow, warn, reject]")
+}
+
+func TestSuggestIndex(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedIndex string
+	}{
+		{
+			name:          "equality",
+			query:         `{"selector":{"owner":"fred","docType":"marble"}}`,
+			expectedIndex: `{"index":{"fields":["docType","owner"]},"ddoc":"index-docType-owner-doc","name":"index-docType-owner","type":"json"}`,
+		},
+		{
+			name:          "sort-fields-first",
+			query:         `{"selector":{"owner":"fred","size":{"$gt":10}},"sort":[{"size":"desc"}]}`,
+			expectedIndex: `{"index":{"fields":["size","owner"]},"ddoc":"index-size-owner-doc","name":"index-size-owner","type":"json"}`,
+		},
+		{
+			name:          "nested-and-combined",
+			query:         `{"selector":{"$and":[{"asset":{"color":"red"}},{"size":{"$lt":5}}],"$or":[{"owner":"fred"},{"owner":"tom"}]}}`,
+			expectedIndex: `{"index":{"fields":["asset.color","size"]},"ddoc":"index-asset.color-size-doc","name":"index-asset.color-size","type":"json"}`,
+		},
+		{
+			name:          "no-indexable-field",
+			query:         `{"selector":{"$or":[{"owner":"fred"},{"owner":"tom"}]}}`,
+			expectedIndex: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			index, err := suggestIndex(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedIndex, string(index))
+		})
+	}
+
+	_, err := suggestIndex("not-a-query")
+	require.EqualError(t, err, "error unmarshalling the query: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name, query, expectedQuery string
+	}{
+		{
+			name:          "implicit-equality",
+			query:         `{"selector":{"owner":"tom","size":35}}`,
+			expectedQuery: `{"selector":{"owner":"?","size":"?"}}`,
+		},
+		{
+			name:          "operators-and-subfields",
+			query:         `{"selector":{"$or":[{"owner":{"$eq":"tom"}},{"size":{"$in":[35,40]}}],"asset":{"color":"<blue>"},"found":{"$exists":true}}}`,
+			expectedQuery: `{"selector":{"$or":[{"owner":{"$eq":"?"}},{"size":{"$in":["?","?"]}}],"asset":{"color":"?"},"found":{"$exists":"?"}}}`,
+		},
+		{
+			name:          "options-retained-and-bookmark-dropped",
+			query:         `{"selector":{"owner":"tom"},"sort":[{"size":"desc"}],"fields":["owner"],"limit":10,"bookmark":"g1AAAABHeJzLYWBg"}`,
+			expectedQuery: `{"fields":["owner"],"limit":10,"selector":{"owner":"?"},"sort":[{"size":"desc"}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			redactedQuery, err := redactQuery(test.query)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedQuery, redactedQuery)
+		})
+	}
+
+	_, err := redactQuery("not-a-query")
+	require.EqualError(t, err, "error unmarshalling the query: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestQueryPlanReports(t *testing.T) {
+	reports := newQueryPlanReports()
+	fullScanPlan := &queryPlan{}
+	fullScanPlan.Index.Name = "_all_docs"
+	fullScanPlan.Index.Type = "special"
+
+	report, err := newQueryPlanReport("ch1", "ns1", `{"selector":{"owner":"fred"}}`, fullScanPlan)
+	require.NoError(t, err)
+	r := reports.recordExecution(report, true)
+	require.Equal(t, uint64(1), r.Executions)
+	require.Equal(t, uint64(1), r.Rejections)
+
+	// recording a new report for a retained query updates the retained report
+	report, err = newQueryPlanReport("ch1", "ns1", `{"selector":{"owner":"fred"}}`, fullScanPlan)
+	require.NoError(t, err)
+	r = reports.recordExecution(report, false)
+	require.Equal(t, uint64(2), r.Executions)
+	require.Equal(t, uint64(1), r.Rejections)
+	require.Equal(t, r, *reports.get("ch1", "ns1", `{"selector":{"owner":"fred"}}`))
+
+	indexedPlan := &queryPlan{}
+	indexedPlan.Index.DesignDocument = "_design/indexOwnerDoc"
+	indexedPlan.Index.Name = "indexOwner"
+	indexedPlan.Index.Type = "json"
+	report, err = newQueryPlanReport("ch1", "ns2", `{"selector":{"owner":"tom"}}`, indexedPlan)
+	require.NoError(t, err)
+	require.Equal(t, "_design/indexOwnerDoc/indexOwner", report.Index)
+	require.Nil(t, report.SuggestedIndex)
+	reports.recordExecution(report, false)
+
+	report, err = newQueryPlanReport("ch2", "ns1", `{"selector":{"size":1}}`, fullScanPlan)
+	require.NoError(t, err)
+	reports.recordExecution(report, false)
+
+	listQueries := func(channel, namespace string, fullScanOnly bool) []string {
+		var queries []string
+		for _, r := range reports.list(channel, namespace, fullScanOnly) {
+			queries = append(queries, r.Channel+":"+r.Namespace+":"+r.Query)
+		}
+		return queries
+	}
+	require.Equal(t,
+		[]string{
+			`ch1:ns1:{"selector":{"owner":"fred"}}`,
+			`ch1:ns2:{"selector":{"owner":"tom"}}`,
+			`ch2:ns1:{"selector":{"size":1}}`,
+		},
+		listQueries("", "", false),
+	)
+	require.Equal(t,
+		[]string{
+			`ch1:ns1:{"selector":{"owner":"fred"}}`,
+			`ch2:ns1:{"selector":{"size":1}}`,
+		},
+		listQueries("", "", true),
+	)
+	require.Equal(t, []string{`ch1:ns2:{"selector":{"owner":"tom"}}`}, listQueries("ch1", "ns2", false))
+
+	reports.clear("ch1", "ns1")
+	require.Nil(t, reports.get("ch1", "ns1", `{"selector":{"owner":"fred"}}`))
+	require.Len(t, reports.list("", "", false), 2)
+
+	t.Run("eviction", func(t *testing.T) {
+		reports := newQueryPlanReports()
+		for i := 0; i <= maxQueryPlanReports; i++ {
+			report, err := newQueryPlanReport("ch1", "ns1", fmt.Sprintf(`{"selector":{"size":%d}}`, i), indexedPlan)
+			require.NoError(t, err)
+			reports.recordExecution(report, false)
+		}
+		require.Len(t, reports.list("", "", false), maxQueryPlanReports)
+		require.Nil(t, reports.get("ch1", "ns1", `{"selector":{"size":0}}`))
+		require.NotNil(t, reports.get("ch1", "ns1", `{"selector":{"size":1}}`))
+	})
+}
+
+func TestQueryPlanReportsHandler(t *testing.T) {
+	reports := newQueryPlanReports()
+	plan := &queryPlan{}
+	plan.Index.Name = "_all_docs"
+	plan.Index.Type = "special"
+	report, err := newQueryPlanReport("ch1", "ns1", `{"selector":{"owner":"fred"}}`, plan)
+	require.NoError(t, err)
+	reports.recordExecution(report, false)
+	handler := &queryPlanReportsHandler{reports: reports}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/couchdb/queryplans?channel=ch1&fullscan=true", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	response := &queryPlanReportsResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), response))
+	require.Len(t, response.Reports, 1)
+	require.Equal(t, "_all_docs", response.Reports[0].Index)
+	require.True(t, response.Reports[0].FullScan)
+	require.JSONEq(t,
+		`{"index":{"fields":["owner"]},"ddoc":"index-owner-doc","name":"index-owner","type":"json"}`,
+		string(response.Reports[0].SuggestedIndex),
+	)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/couchdb/queryplans?channel=ch2", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"reports":[]}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/couchdb/queryplans", nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.JSONEq(t, `{"error":"invalid request method: DELETE"}`, resp.Body.String())
+}
+
+func TestQueryPlanCheck(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+	vdbEnv.config.QueryPlanCheck = queryPlanCheckReject
+
+	db, err := vdbEnv.DBProvider.GetDBHandle("testqueryplancheck", nil)
+	require.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"asset_name":"marble1","color":"blue","size":35,"owner":"tom"}`), version.NewHeight(1, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+
+	queryString := `{"selector":{"owner":"tom"}}`
+	_, err = db.ExecuteQuery("ns1", queryString)
+	require.EqualError(t, err, `the query [{"selector":{"owner":"?"}}] on namespace [ns1] of channel [testqueryplancheck] `+
+		`is rejected as it would run without a usable index. Suggested index: `+
+		`{"index":{"fields":["owner"]},"ddoc":"index-owner-doc","name":"index-owner","type":"json"}`)
+
+	indexCapable, ok := db.(statedb.IndexCapable)
+	require.True(t, ok)
+	indexData := map[string][]byte{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json": []byte(`{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`),
+	}
+	require.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", indexData))
+
+	itr, err := db.ExecuteQuery("ns1", queryString)
+	require.NoError(t, err)
+	defer itr.Close()
+	result, err := itr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "key1", result.(*statedb.VersionedKV).Key)
+
+	// the queries that differ only in their literals share a report that carries the redacted query
+	itr, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"fred"}}`)
+	require.NoError(t, err)
+	defer itr.Close()
+
+	provider := vdbEnv.DBProvider.(*VersionedDBProvider)
+	reports := provider.queryPlans.list("testqueryplancheck", "ns1", false)
+	require.Len(t, reports, 1)
+	require.Equal(t, `{"selector":{"owner":"?"}}`, reports[0].Query)
+	require.Equal(t, "_design/indexOwnerDoc/indexOwner", reports[0].Index)
+	require.False(t, reports[0].FullScan)
+	require.Equal(t, uint64(2), reports[0].Executions)
+}
@@ -44,6 +44,10 @@ func (p *redoLoggerProvider) newRedoLogger(dbName string) *redoLogger {
 	}
 }
 
+func (p *redoLoggerProvider) drop(dbName string) error {
+	return p.leveldbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 func (p *redoLoggerProvider) close() {
 	p.leveldbProvider.Close()
 }
@@ -35,8 +35,11 @@ const (
 	// a double underscore ensures that the dbname does not clash with the dbnames created for the chaincodes
 	fabricInternalDBName = "fabric__internal"
 	// dataformatVersionDocID is used as a key for maintaining version of the data format (maintained in fabric internal db)
-	dataformatVersionDocID      = "dataformatVersion"
-	fullScanIteratorValueFormat = byte(1)
+	dataformatVersionDocID = "dataformatVersion"
+	// fullScanIteratorValueFormat differs from the format used by the stateleveldb so that the
+	// snapshot data exported from one kind of statedb is not misinterpreted by the other
+	fullScanIteratorValueFormat = byte(2)
+	maxDataImportBatchSize      = 4 * 1024 * 1024
 )
 
 // VersionedDBProvider implements interface VersionedDBProvider
@@ -47,11 +50,15 @@ type VersionedDBProvider struct {
 	openCounts         uint64
 	redoLoggerProvider *redoLoggerProvider
 	cache              *cache
+	queryPlans         *queryPlanReports
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider(config *ledger.CouchDBConfig, metricsProvider metrics.Provider, sysNamespaces []string) (*VersionedDBProvider, error) {
 	logger.Debugf("constructing CouchDB VersionedDBProvider")
+	if err := validateQueryPlanCheck(config.QueryPlanCheck); err != nil {
+		return nil, err
+	}
 	couchInstance, err := createCouchInstance(config, metricsProvider)
 	if err != nil {
 		return nil, err
@@ -72,6 +79,7 @@ func NewVersionedDBProvider(config *ledger.CouchDBConfig, metricsProvider metric
 			openCounts:         0,
 			redoLoggerProvider: p,
 			cache:              cache,
+			queryPlans:         newQueryPlanReports(),
 		},
 		nil
 }
@@ -138,6 +146,7 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string, nsProvider state
 			provider.redoLoggerProvider.newRedoLogger(dbName),
 			dbName,
 			provider.cache,
+			provider.queryPlans,
 			nsProvider,
 		)
 		if err != nil {
@@ -148,6 +157,109 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string, nsProvider state
 	return vdb, nil
 }
 
+// ImportFromSnapshot loads the data exported by the FullScanIterator of a statecouchdb into the empty
+// databases for the given id and records the given savepoint
+func (provider *VersionedDBProvider) ImportFromSnapshot(
+	dbName string,
+	savepoint *version.Height,
+	itr statedb.FullScanIterator,
+	dbValueFormat byte,
+) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	vdb, err := newVersionedDB(
+		provider.couchInstance,
+		provider.redoLoggerProvider.newRedoLogger(dbName),
+		dbName,
+		provider.cache,
+		provider.queryPlans,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	existingSavepoint, err := vdb.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if existingSavepoint != nil {
+		return errors.Errorf("statedb for channel [%s] is not empty. Import from snapshot is supported only for an empty statedb", dbName)
+	}
+
+	batch := statedb.NewUpdateBatch()
+	batchSize := 0
+	for {
+		compositeKey, dbValue, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if compositeKey == nil {
+			break
+		}
+		if dbValueFormat != fullScanIteratorValueFormat {
+			return errors.Errorf("unsupported db value format [%d] for importing the snapshot data in couchdb", dbValueFormat)
+		}
+		vv, err := decodeSnapshotValue(dbValue)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to decode the snapshot value for key [%s] in namespace [%s]",
+				compositeKey.Key, compositeKey.Namespace)
+		}
+		batch.PutValAndMetadata(compositeKey.Namespace, compositeKey.Key, vv.Value, vv.Metadata, vv.Version)
+		batchSize += len(compositeKey.Key) + len(dbValue)
+		if batchSize >= maxDataImportBatchSize {
+			if err := vdb.applyUpdates(batch, nil); err != nil {
+				return err
+			}
+			batch = statedb.NewUpdateBatch()
+			batchSize = 0
+		}
+	}
+	if err := vdb.applyUpdates(batch, savepoint); err != nil {
+		return err
+	}
+	provider.databases[dbName] = vdb
+	return nil
+}
+
+// Drop drops the databases of the channel with the given name, that is the namespace databases recorded in the
+// channel metadata and the metadata database, and removes the redo log of the channel. It is not an error if the
+// databases do not exist
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+
+	metadataDBName := constructMetadataDBName(dbName)
+	metadataDB := &couchDatabase{couchInstance: provider.couchInstance, dbName: metadataDBName}
+	_, couchDBReturn, err := metadataDB.getDatabaseInfo()
+	if couchDBReturn != nil && couchDBReturn.StatusCode == 404 {
+		delete(provider.databases, dbName)
+		return provider.redoLoggerProvider.drop(dbName)
+	}
+	if err != nil {
+		return err
+	}
+
+	vdb := &VersionedDB{couchInstance: provider.couchInstance, metadataDB: metadataDB, chainName: dbName}
+	channelMetadata, err := vdb.readChannelMetadata()
+	if err != nil {
+		return err
+	}
+	if channelMetadata != nil {
+		for _, nsDBInfo := range channelMetadata.NamespaceDBsInfo {
+			if _, err := dropDB(provider.couchInstance, nsDBInfo.DBName); err != nil {
+				logger.Errorf("Error dropping CouchDB database %s", nsDBInfo.DBName)
+				return err
+			}
+		}
+	}
+	if _, err := dropDB(provider.couchInstance, metadataDBName); err != nil {
+		logger.Errorf("Error dropping CouchDB database %s", metadataDBName)
+		return err
+	}
+	delete(provider.databases, dbName)
+	return provider.redoLoggerProvider.drop(dbName)
+}
+
 // Close closes the underlying db instance
 func (provider *VersionedDBProvider) Close() {
 	// No close needed on Couch
@@ -171,10 +283,11 @@ type VersionedDB struct {
 	mux                sync.RWMutex
 	redoLogger         *redoLogger
 	cache              *cache
+	queryPlans         *queryPlanReports
 }
 
 // newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(couchInstance *couchInstance, redoLogger *redoLogger, dbName string, cache *cache, nsProvider statedb.NamespaceProvider) (*VersionedDB, error) {
+func newVersionedDB(couchInstance *couchInstance, redoLogger *redoLogger, dbName string, cache *cache, queryPlans *queryPlanReports, nsProvider statedb.NamespaceProvider) (*VersionedDB, error) {
 	// CreateCouchDatabase creates a CouchDB database object, as well as the underlying database if it does not exist
 	chainName := dbName
 	dbName = constructMetadataDBName(dbName)
@@ -192,6 +305,7 @@ func newVersionedDB(couchInstance *couchInstance, redoLogger *redoLogger, dbName
 		committedDataCache: newVersionCache(),
 		redoLogger:         redoLogger,
 		cache:              cache,
+		queryPlans:         queryPlans,
 	}
 
 	logger.Debugf("chain [%s]: checking for redolog record", chainName)
@@ -243,17 +357,19 @@ func (vdb *VersionedDB) getNamespaceDBHandle(namespace string) (*couchDatabase,
 	db = vdb.namespaceDBs[namespace]
 	if db == nil {
 		var err error
-		if _, ok := vdb.channelMetadata.NamespaceDBsInfo[namespace]; !ok {
+		nsDBInfo, ok := vdb.channelMetadata.NamespaceDBsInfo[namespace]
+		if !ok {
 			logger.Debugf("[%s] add namespaceDBInfo for namespace %s", vdb.chainName, namespace)
-			vdb.channelMetadata.NamespaceDBsInfo[namespace] = &namespaceDBInfo{
+			nsDBInfo = &namespaceDBInfo{
 				Namespace: namespace,
 				DBName:    namespaceDBName,
 			}
+			vdb.channelMetadata.NamespaceDBsInfo[namespace] = nsDBInfo
 			if err = vdb.writeChannelMetadata(); err != nil {
 				return nil, err
 			}
 		}
-		db, err = createCouchDatabase(vdb.couchInstance, namespaceDBName)
+		db, err = createCouchDatabaseWithPartitioning(vdb.couchInstance, namespaceDBName, nsDBInfo.Partitioning)
 		if err != nil {
 			return nil, err
 		}
@@ -278,13 +394,23 @@ func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, index
 	// To satisfy R1, we log the error and continue to process the next index file.
 	// To satisfy R2, we sort the indexFilesData map based on the filenames and process
 	// each index as per the sorted order.
+	// In a partitioned database, each index is created as a global index along with a
+	// partitioned variant of the index.
 	var indexFilesName []string
 	for fileName := range indexFilesData {
 		indexFilesName = append(indexFilesName, fileName)
 	}
 	sort.Strings(indexFilesName)
+	// the plans of the queries on the namespace may change with the new indexes
+	defer vdb.queryPlans.clear(vdb.chainName, namespace)
 	for _, fileName := range indexFilesName {
-		_, err = db.createIndex(string(indexFilesData[fileName]))
+		indexDefinitions := []string{string(indexFilesData[fileName])}
+		if db.partitioning != nil {
+			indexDefinitions, err = indexDefinitionsForPartitionedDB(indexDefinitions[0])
+		}
+		for i := 0; err == nil && i < len(indexDefinitions); i++ {
+			_, err = db.createIndex(indexDefinitions[i])
+		}
 		switch {
 		case err != nil:
 			logger.Errorf("error creating index from file [%s] for chaincode [%s] on channel [%s]: %+v",
@@ -443,7 +569,7 @@ func (vdb *VersionedDB) readFromDB(namespace, key string) (*keyValue, error) {
 	if err := validateKey(key); err != nil {
 		return nil, err
 	}
-	couchDoc, _, err := db.readDoc(key)
+	couchDoc, _, err := db.readDoc(db.docID(key))
 	if err != nil {
 		return nil, err
 	}
@@ -454,6 +580,7 @@ func (vdb *VersionedDB) readFromDB(namespace, key string) (*keyValue, error) {
 	if err != nil {
 		return nil, err
 	}
+	kv.key = key
 	return kv, nil
 }
 
@@ -488,7 +615,7 @@ func (vdb *VersionedDB) GetStateRangeScanIteratorWithPagination(namespace string
 	if err != nil {
 		return nil, err
 	}
-	return newQueryScanner(namespace, db, "", internalQueryLimit, pageSize, "", startKey, endKey)
+	return newQueryScanner(namespace, db, "", "", internalQueryLimit, pageSize, "", db.docID(startKey), db.docID(endKey))
 }
 
 func (scanner *queryScanner) getNextStateRangeScanResults() error {
@@ -570,16 +697,23 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 func (vdb *VersionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
 	logger.Debugf("Entering ExecuteQueryWithPagination namespace: %s,  query: %s,  bookmark: %s, pageSize: %d", namespace, query, bookmark, pageSize)
 	internalQueryLimit := vdb.couchInstance.internalQueryLimit()
-	queryString, err := applyAdditionalQueryOptions(query, internalQueryLimit, bookmark)
+	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
-		logger.Errorf("Error calling applyAdditionalQueryOptions(): %s", err.Error())
 		return nil, err
 	}
-	db, err := vdb.getNamespaceDBHandle(namespace)
+	scopedQuery, partition, err := db.scopeQueryToPartitionKey(query)
+	if err != nil {
+		return nil, err
+	}
+	queryString, err := applyAdditionalQueryOptions(scopedQuery, internalQueryLimit, bookmark)
 	if err != nil {
+		logger.Errorf("Error calling applyAdditionalQueryOptions(): %s", err.Error())
+		return nil, err
+	}
+	if err := vdb.checkQueryPlan(namespace, db, partition, query, queryString); err != nil {
 		return nil, err
 	}
-	return newQueryScanner(namespace, db, queryString, internalQueryLimit, pageSize, bookmark, "", "")
+	return newQueryScanner(namespace, db, partition, queryString, internalQueryLimit, pageSize, bookmark, "", "")
 }
 
 // executeQueryWithBookmark executes a "paging" query with a bookmark, this method allows a
@@ -597,7 +731,7 @@ func (scanner *queryScanner) executeQueryWithBookmark() error {
 		logger.Debugf("Error calling applyAdditionalQueryOptions(): %s\n", err.Error())
 		return err
 	}
-	queryResult, bookmark, err := scanner.db.queryDocuments(queryString)
+	queryResult, bookmark, err := scanner.db.queryDocumentsInPartition(scanner.queryDefinition.partition, queryString)
 	if err != nil {
 		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
 		return err
@@ -924,6 +1058,7 @@ type queryScanner struct {
 type queryDefinition struct {
 	startKey           string
 	endKey             string
+	partition          string
 	query              string
 	internalQueryLimit int32
 }
@@ -939,9 +1074,12 @@ type resultsInfo struct {
 	results              []*queryResult
 }
 
-func newQueryScanner(namespace string, db *couchDatabase, query string, internalQueryLimit,
+// newQueryScanner returns a scanner of the documents that match the query or, if the query is empty, of the documents in
+// the range of the document ids [startKey, endKey). A query is executed on the given partition of a partitioned database,
+// or on the whole database if the partition is empty
+func newQueryScanner(namespace string, db *couchDatabase, partition, query string, internalQueryLimit,
 	limit int32, bookmark, startKey, endKey string) (*queryScanner, error) {
-	scanner := &queryScanner{namespace, db, &queryDefinition{startKey, endKey, query, internalQueryLimit}, &paginationInfo{-1, limit, bookmark}, &resultsInfo{0, nil}, false}
+	scanner := &queryScanner{namespace, db, &queryDefinition{startKey, endKey, partition, query, internalQueryLimit}, &paginationInfo{-1, limit, bookmark}, &resultsInfo{0, nil}, false}
 	var err error
 	// query is defined, then execute the query and return the records and bookmark
 	if scanner.queryDefinition.query != "" {
@@ -972,7 +1110,7 @@ func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
 	return &statedb.VersionedKV{
 		CompositeKey: statedb.CompositeKey{
 			Namespace: scanner.namespace,
-			Key:       kv.key,
+			Key:       scanner.db.keyFromDocID(kv.key),
 		},
 		VersionedValue: *kv.VersionedValue,
 	}, nil
@@ -1017,7 +1155,7 @@ func (scanner *queryScanner) GetBookmarkAndClose() string {
 	if scanner.queryDefinition.query != "" {
 		retval = scanner.paginationInfo.bookmark
 	} else {
-		retval = scanner.queryDefinition.startKey
+		retval = scanner.db.keyFromDocID(scanner.queryDefinition.startKey)
 	}
 	scanner.Close()
 	return retval
@@ -1076,7 +1214,7 @@ func newDBsScanner(dbsToScan []*namespaceDB, prefetchLimit int32, toSkipKeysFrom
 
 func (s *dbsScanner) beginNextDBScan() error {
 	dbUnderScan := s.dbs[s.nextDBToScanIndex]
-	queryScanner, err := newQueryScanner(dbUnderScan.ns, dbUnderScan.db, "", s.prefetchLimit, 0, "", "", "")
+	queryScanner, err := newQueryScanner(dbUnderScan.ns, dbUnderScan.db, "", "", s.prefetchLimit, 0, "", "", "")
 	if err != nil {
 		return errors.WithMessagef(
 			err,
@@ -1148,7 +1286,7 @@ func (s *dbsScanner) Next() (*statedb.CompositeKey, []byte, error) {
 		}
 		return &statedb.CompositeKey{
 			Namespace: s.currentNamespace,
-			Key:       fields.id,
+			Key:       s.resultItr.db.keyFromDocID(fields.id),
 		}, dbval, nil
 	}
 	return nil, nil, nil
@@ -1023,17 +1023,17 @@ func TestRangeScanWithCouchInternalDocsPresent(t *testing.T) {
 	// The Keys in db are in this order
 	// Key-1, Key-2, Key-3,_design/indexAssetNam, _design/indexAssetValue, key-1, key-2, key-3
 	// query different ranges and verify results
-	s, err := newQueryScanner("ns", couchDatabse, "", 3, 3, "", "", "")
+	s, err := newQueryScanner("ns", couchDatabse, "", "", 3, 3, "", "", "")
 	require.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-1", "Key-2", "Key-3"})
 	require.Equal(t, "key-1", s.queryDefinition.startKey)
 
-	s, err = newQueryScanner("ns", couchDatabse, "", 4, 4, "", "", "")
+	s, err = newQueryScanner("ns", couchDatabse, "", "", 4, 4, "", "", "")
 	require.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-1", "Key-2", "Key-3", "key-1"})
 	require.Equal(t, "key-2", s.queryDefinition.startKey)
 
-	s, err = newQueryScanner("ns", couchDatabse, "", 2, 2, "", "", "")
+	s, err = newQueryScanner("ns", couchDatabse, "", "", 2, 2, "", "", "")
 	require.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-1", "Key-2"})
 	require.Equal(t, "Key-3", s.queryDefinition.startKey)
@@ -1041,7 +1041,7 @@ func TestRangeScanWithCouchInternalDocsPresent(t *testing.T) {
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-3", "key-1"})
 	require.Equal(t, "key-2", s.queryDefinition.startKey)
 
-	s, err = newQueryScanner("ns", couchDatabse, "", 2, 2, "", "_", "")
+	s, err = newQueryScanner("ns", couchDatabse, "", "", 2, 2, "", "_", "")
 	require.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"key-1", "key-2"})
 	require.Equal(t, "key-3", s.queryDefinition.startKey)
@@ -1701,7 +1701,31 @@ func TestFullScanIterator(t *testing.T) {
 	commontests.TestFullScanIterator(
 		t,
 		vdbEnv.DBProvider,
-		byte(1),
+		byte(2),
+		constructVersionedValueForTest,
+	)
+}
+
+func TestImportFromSnapshot(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+	commontests.TestImportFromSnapshot(t, vdbEnv.DBProvider)
+}
+
+func TestDrop(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+	commontests.TestDrop(t, vdbEnv.DBProvider)
+}
+
+func TestConformance(t *testing.T) {
+	commontests.TestVersionedDBProviderConformance(
+		t,
+		func(t *testing.T) (statedb.VersionedDBProvider, func()) {
+			vdbEnv.init(t, nil)
+			return vdbEnv.DBProvider, vdbEnv.cleanup
+		},
+		byte(2),
 		constructVersionedValueForTest,
 	)
 }
@@ -21,6 +21,11 @@ import (
 type VersionedDBProvider interface {
 	// GetDBHandle returns a handle to a VersionedDB
 	GetDBHandle(id string, namespaceProvider NamespaceProvider) (VersionedDB, error)
+	// ImportFromSnapshot loads the data returned by the supplied FullScanIterator into the empty VersionedDB
+	// for the given id and records the given savepoint. The dbValueFormat is the format of the value bytes
+	// returned by the FullScanIterator, as returned by the function GetFullScanIterator of the VersionedDB
+	// that exported the data. If the FullScanIterator returns no data, only the savepoint is recorded
+	ImportFromSnapshot(id string, savepoint *version.Height, itr FullScanIterator, dbValueFormat byte) error
 	// Close closes all the VersionedDB instances and releases any resources held by VersionedDBProvider
 	Close()
 }
@@ -103,6 +108,20 @@ type IndexCapable interface {
 	ProcessIndexesForChaincodeDeploy(namespace string, indexFilesData map[string][]byte) error
 }
 
+// PartitionCapable interface provides additional functions for databases that
+// can partition the data of a namespace by a composite key prefix declared by the chaincode
+type PartitionCapable interface {
+	ProcessPartitioningForChaincodeDeploy(namespace string, partitioningData []byte) error
+}
+
+// Droppable interface provides an additional function for the providers of databases
+// that can drop the VersionedDB of a channel, which is used to clean up the ledger of a
+// channel whose creation did not complete
+type Droppable interface {
+	// Drop removes all the data of the VersionedDB for the given id. It is not an error if the VersionedDB does not exist
+	Drop(id string) error
+}
+
 // FullScanIterator provides a mean to iterate over entire statedb. The intended use of this iterator
 // is to generate the snapshot files for the statedb
 type FullScanIterator interface {
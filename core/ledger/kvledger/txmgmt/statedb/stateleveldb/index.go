@@ -0,0 +1,459 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+// The secondary indexes are declared by a chaincode in the same format as the CouchDB json indexes and are packaged
+// under "META-INF/statedb/leveldb/indexes" for the chaincode namespace and under
+// "META-INF/statedb/leveldb/collections/<collection>/indexes" for the private data namespace of a collection.
+// An index entry is maintained for each key whose value is a JSON object that carries a scalar value (i.e., null,
+// boolean, number, or string) for each of the indexed fields. The entries of an index are laid out as
+// `indexEntryKeyPrefix:ns:nsKeySep:indexName:nsKeySep:encodedFieldValues:key` and are ordered by the values of
+// the indexed fields, in the order of the fields in the index definition, followed by the key. The values are
+// ordered as in CouchDB, i.e., null < false < true < numbers < strings, except that the strings are ordered by
+// their UTF-8 bytes. The definitions of the indexes are persisted under `indexDefKeyPrefix:ns:nsKeySep:indexName`
+var (
+	indexEntryKeyPrefix = []byte{'i'}
+	indexDefKeyPrefix   = []byte{'x'}
+	indexDefKeyStopper  = []byte{'y'}
+)
+
+const (
+	valueTypeNull   = byte(0x01)
+	valueTypeFalse  = byte(0x02)
+	valueTypeTrue   = byte(0x03)
+	valueTypeNumber = byte(0x04)
+	valueTypeString = byte(0x05)
+
+	// a zero byte within a string value is escaped as {0x00, 0xff} and a string value is terminated
+	// by {0x00, 0x01} so that the encoded strings preserve their order and none is a prefix of another
+	stringEscapeByte     = byte(0x00)
+	stringEscapedZero    = byte(0xff)
+	stringTerminatorByte = byte(0x01)
+)
+
+// indexDefinition is the definition of a secondary index of a namespace
+type indexDefinition struct {
+	DesignDocument string   `json:"ddoc,omitempty"`
+	Name           string   `json:"name"`
+	Fields         []string `json:"fields"`
+}
+
+// parseIndexDefinition parses an index definition in the format of a CouchDB json index, e.g.,
+// {"index":{"fields":["docType","owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}.
+// The sort direction of a field, if specified, is ignored as an index is scanned in either direction
+func parseIndexDefinition(indexDefBytes []byte) (*indexDefinition, error) {
+	def := struct {
+		Index struct {
+			Fields                []interface{}   `json:"fields"`
+			PartialFilterSelector json.RawMessage `json:"partial_filter_selector"`
+		} `json:"index"`
+		DesignDocument string `json:"ddoc"`
+		Name           string `json:"name"`
+		Type           string `json:"type"`
+	}{}
+	if err := json.Unmarshal(indexDefBytes, &def); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the index definition")
+	}
+	if def.Type != "" && def.Type != "json" {
+		return nil, errors.Errorf("unsupported index type [%s], only json indexes are supported", def.Type)
+	}
+	if def.Index.PartialFilterSelector != nil {
+		return nil, errors.New("partial filter selectors are not supported")
+	}
+	if len(def.Index.Fields) == 0 {
+		return nil, errors.New("the index definition must include at least one field")
+	}
+
+	index := &indexDefinition{
+		DesignDocument: strings.TrimPrefix(def.DesignDocument, "_design/"),
+		Name:           def.Name,
+	}
+	for _, f := range def.Index.Fields {
+		switch f := f.(type) {
+		case string:
+			index.Fields = append(index.Fields, f)
+		case map[string]interface{}:
+			if len(f) != 1 {
+				return nil, errors.New("a field of the index must be either a field name or an object with a single field name and sort direction")
+			}
+			for field := range f {
+				index.Fields = append(index.Fields, field)
+			}
+		default:
+			return nil, errors.Errorf("invalid field [%v] in the index definition", f)
+		}
+	}
+	if index.Name == "" {
+		index.Name = strings.Join(index.Fields, "-")
+	}
+	if strings.ContainsRune(index.Name, rune(nsKeySep[0])) {
+		return nil, errors.Errorf("invalid index name [%s]", index.Name)
+	}
+	return index, nil
+}
+
+func (index *indexDefinition) sameAs(other *indexDefinition) bool {
+	if index.Name != other.Name || index.DesignDocument != other.DesignDocument || len(index.Fields) != len(other.Fields) {
+		return false
+	}
+	for i := range index.Fields {
+		if index.Fields[i] != other.Fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// entryKey returns the key of the index entry for the given key and value. Nil is returned if the value
+// is not indexed, i.e., if the value is not a JSON object or it lacks a scalar value for any indexed field
+func (index *indexDefinition) entryKey(ns, key string, value []byte) []byte {
+	doc := parseJSONObject(value)
+	if doc == nil {
+		return nil
+	}
+	entryKey := index.entryKeyPrefix(ns)
+	for _, field := range index.Fields {
+		fieldValue, ok := lookupField(doc, field)
+		if !ok {
+			return nil
+		}
+		encodedValue, ok := encodeIndexValue(fieldValue)
+		if !ok {
+			return nil
+		}
+		entryKey = append(entryKey, encodedValue...)
+	}
+	return append(entryKey, []byte(key)...)
+}
+
+func (index *indexDefinition) entryKeyPrefix(ns string) []byte {
+	k := append([]byte{}, indexEntryKeyPrefix...)
+	k = append(k, []byte(ns)...)
+	k = append(k, nsKeySep...)
+	k = append(k, []byte(index.Name)...)
+	return append(k, nsKeySep...)
+}
+
+// decodeKey returns the key from the given key of an index entry
+func (index *indexDefinition) decodeKey(ns string, entryKey []byte) (string, error) {
+	remaining := entryKey[len(index.entryKeyPrefix(ns)):]
+	for range index.Fields {
+		n, err := encodedIndexValueLen(remaining)
+		if err != nil {
+			return "", err
+		}
+		remaining = remaining[n:]
+	}
+	return string(remaining), nil
+}
+
+func encodeIndexDefKey(ns, indexName string) []byte {
+	k := append([]byte{}, indexDefKeyPrefix...)
+	k = append(k, []byte(ns)...)
+	k = append(k, nsKeySep...)
+	return append(k, []byte(indexName)...)
+}
+
+func decodeIndexDefKey(indexDefKey []byte) string {
+	split := bytes.SplitN(indexDefKey, nsKeySep, 2)
+	return string(split[0][len(indexDefKeyPrefix):])
+}
+
+// encodeIndexValue returns the order preserving encoding of a scalar JSON value. False is returned
+// for the values that are not indexed, i.e., arrays and objects
+func encodeIndexValue(v interface{}) ([]byte, bool) {
+	switch v := v.(type) {
+	case nil:
+		return []byte{valueTypeNull}, true
+	case bool:
+		if v {
+			return []byte{valueTypeTrue}, true
+		}
+		return []byte{valueTypeFalse}, true
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, false
+		}
+		return encodeNumber(f), true
+	case float64:
+		return encodeNumber(v), true
+	case string:
+		encoded := []byte{valueTypeString}
+		for _, b := range []byte(v) {
+			if b == stringEscapeByte {
+				encoded = append(encoded, stringEscapeByte, stringEscapedZero)
+				continue
+			}
+			encoded = append(encoded, b)
+		}
+		return append(encoded, stringEscapeByte, stringTerminatorByte), true
+	default:
+		return nil, false
+	}
+}
+
+// encodeNumber encodes a float64 such that the bytewise order of the encoded values matches the numeric order
+func encodeNumber(f float64) []byte {
+	bits := math.Float64bits(f)
+	if f >= 0 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	encoded := make([]byte, 9)
+	encoded[0] = valueTypeNumber
+	binary.BigEndian.PutUint64(encoded[1:], bits)
+	return encoded
+}
+
+// encodedIndexValueLen returns the number of bytes in the encoded value at the beginning of the given bytes
+func encodedIndexValueLen(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, errors.New("unexpected end of the index entry key")
+	}
+	switch b[0] {
+	case valueTypeNull, valueTypeFalse, valueTypeTrue:
+		return 1, nil
+	case valueTypeNumber:
+		if len(b) < 9 {
+			return 0, errors.New("unexpected end of the index entry key")
+		}
+		return 9, nil
+	case valueTypeString:
+		for i := 1; i < len(b)-1; i++ {
+			if b[i] != stringEscapeByte {
+				continue
+			}
+			if b[i+1] == stringTerminatorByte {
+				return i + 2, nil
+			}
+			i++
+		}
+		return 0, errors.New("unterminated string value in the index entry key")
+	default:
+		return 0, errors.Errorf("unexpected value type [%d] in the index entry key", b[0])
+	}
+}
+
+// parseJSONObject returns the JSON object in the given value. Nil is returned if the value is not a JSON object
+func parseJSONObject(value []byte) map[string]interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	doc := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil
+	}
+	return doc
+}
+
+// lookupField returns the value of a field, possibly nested via the dot notation such as "asset.owner"
+func lookupField(doc map[string]interface{}, field string) (interface{}, bool) {
+	path := strings.Split(field, ".")
+	var current interface{} = doc
+	for _, p := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[p]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// prefixEnd returns the smallest key that is greater than all the keys with the given prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// loadIndexes loads the definitions of the indexes of all the namespaces from the db
+func loadIndexes(db *leveldbhelper.DBHandle) (map[string][]*indexDefinition, error) {
+	itr, err := db.GetIterator(indexDefKeyPrefix, indexDefKeyStopper)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	indexes := map[string][]*indexDefinition{}
+	for itr.Next() {
+		index := &indexDefinition{}
+		if err := json.Unmarshal(itr.Value(), index); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling the persisted index definition")
+		}
+		ns := decodeIndexDefKey(itr.Key())
+		indexes[ns] = append(indexes[ns], index)
+	}
+	return indexes, errors.Wrap(itr.Error(), "internal leveldb error while loading the index definitions")
+}
+
+// ProcessIndexesForChaincodeDeploy implements method in IndexCapable interface. It creates the given indexes for the
+// namespace and builds them from the existing data of the namespace. An index that exists with the same definition is
+// left as is, whereas an index that exists under the same name with a different definition is rebuilt. An invalid index
+// definition is logged and skipped, as is the case for CouchDB
+func (vdb *versionedDB) ProcessIndexesForChaincodeDeploy(namespace string, indexFilesData map[string][]byte) error {
+	vdb.indexesLock.Lock()
+	defer vdb.indexesLock.Unlock()
+
+	var indexFilesName []string
+	for fileName := range indexFilesData {
+		indexFilesName = append(indexFilesName, fileName)
+	}
+	// process the files in the same order on all the peers, as a later definition replaces
+	// an earlier one with the same name
+	sort.Strings(indexFilesName)
+
+	for _, fileName := range indexFilesName {
+		index, err := parseIndexDefinition(indexFilesData[fileName])
+		if err != nil {
+			logger.Errorf("error creating index from file [%s] for chaincode [%s] on channel [%s]: %+v",
+				fileName, namespace, vdb.dbName, err)
+			continue
+		}
+		if err := vdb.createIndex(namespace, index); err != nil {
+			return err
+		}
+		logger.Infof("successfully created index present in the file [%s] for chaincode [%s] on channel [%s]",
+			fileName, namespace, vdb.dbName)
+	}
+	return nil
+}
+
+func (vdb *versionedDB) createIndex(ns string, index *indexDefinition) error {
+	existingIndexes := vdb.indexes[ns]
+	var remainingIndexes []*indexDefinition
+	for _, existing := range existingIndexes {
+		if existing.Name != index.Name {
+			remainingIndexes = append(remainingIndexes, existing)
+			continue
+		}
+		if existing.sameAs(index) {
+			return nil
+		}
+		if err := vdb.deleteIndexEntries(ns, existing); err != nil {
+			return err
+		}
+	}
+
+	defBytes, err := json.Marshal(index)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the index definition")
+	}
+	dbBatch := vdb.db.NewUpdateBatch()
+	dbBatch.Put(encodeIndexDefKey(ns, index.Name), defBytes)
+
+	dataStartKey := encodeDataKey(ns, "")
+	dataEndKey := dataKeyStarterForNextNamespace(ns)
+	itr, err := vdb.db.GetIterator(dataStartKey, dataEndKey)
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		_, key := decodeDataKey(itr.Key())
+		vv, err := decodeValue(itr.Value())
+		if err != nil {
+			return err
+		}
+		if entryKey := index.entryKey(ns, key, vv.Value); entryKey != nil {
+			dbBatch.Put(entryKey, []byte{})
+		}
+		if dbBatch.Len() >= maxDataImportBatchSize {
+			if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
+				return err
+			}
+			dbBatch = vdb.db.NewUpdateBatch()
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while building the index")
+	}
+	if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	vdb.indexes[ns] = append(remainingIndexes, index)
+	return nil
+}
+
+func (vdb *versionedDB) deleteIndexEntries(ns string, index *indexDefinition) error {
+	entryKeyPrefix := index.entryKeyPrefix(ns)
+	itr, err := vdb.db.GetIterator(entryKeyPrefix, prefixEnd(entryKeyPrefix))
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+	dbBatch := vdb.db.NewUpdateBatch()
+	for itr.Next() {
+		dbBatch.Delete(append([]byte{}, itr.Key()...))
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while deleting the index entries")
+	}
+	dbBatch.Delete(encodeIndexDefKey(ns, index.Name))
+	return vdb.db.WriteBatch(dbBatch, true)
+}
+
+// addIndexUpdates adds to the db batch the changes in the index entries that result from the updates
+// of the given namespace. The current values of the updated keys are read from the db
+func (vdb *versionedDB) addIndexUpdates(dbBatch *leveldbhelper.UpdateBatch, ns string, updates map[string]*statedb.VersionedValue) error {
+	indexes := vdb.indexes[ns]
+	if len(indexes) == 0 {
+		return nil
+	}
+	for key, vv := range updates {
+		var currentValue []byte
+		currentVV, err := vdb.GetState(ns, key)
+		if err != nil {
+			return err
+		}
+		if currentVV != nil {
+			currentValue = currentVV.Value
+		}
+		for _, index := range indexes {
+			currentEntryKey := index.entryKey(ns, key, currentValue)
+			newEntryKey := index.entryKey(ns, key, vv.Value)
+			if bytes.Equal(currentEntryKey, newEntryKey) {
+				continue
+			}
+			if currentEntryKey != nil {
+				dbBatch.Delete(currentEntryKey)
+			}
+			if newEntryKey != nil {
+				dbBatch.Put(newEntryKey, []byte{})
+			}
+		}
+	}
+	return nil
+}
+
+// GetDBType implements method in IndexCapable interface
+func (vdb *versionedDB) GetDBType() string {
+	return "leveldb"
+}
@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndexDefinition(t *testing.T) {
+	index, err := parseIndexDefinition([]byte(`{"index":{"fields":["docType",{"owner":"desc"}]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`))
+	require.NoError(t, err)
+	require.Equal(t, &indexDefinition{DesignDocument: "indexOwnerDoc", Name: "indexOwner", Fields: []string{"docType", "owner"}}, index)
+
+	index, err = parseIndexDefinition([]byte(`{"index":{"fields":["docType","owner"]}}`))
+	require.NoError(t, err)
+	require.Equal(t, &indexDefinition{Name: "docType-owner", Fields: []string{"docType", "owner"}}, index)
+
+	testCases := []struct {
+		indexDef    string
+		expectedErr string
+	}{
+		{`not-json`, "error unmarshalling the index definition: invalid character 'o' in literal null (expecting 'u')"},
+		{`{"index":{"fields":["owner"]},"type":"text"}`, "unsupported index type [text], only json indexes are supported"},
+		{`{"index":{"fields":["owner"],"partial_filter_selector":{"size":1}}}`, "partial filter selectors are not supported"},
+		{`{"index":{"fields":[]}}`, "the index definition must include at least one field"},
+		{`{"index":{"fields":[{"owner":"asc","size":"asc"}]}}`, "a field of the index must be either a field name or an object with a single field name and sort direction"},
+		{`{"index":{"fields":[1]}}`, "invalid field [1] in the index definition"},
+	}
+	for _, tc := range testCases {
+		_, err := parseIndexDefinition([]byte(tc.indexDef))
+		require.EqualError(t, err, tc.expectedErr)
+	}
+}
+
+func TestEncodeIndexValueOrder(t *testing.T) {
+	// the values in their expected order
+	values := []interface{}{
+		nil,
+		false,
+		true,
+		json.Number("-1e10"),
+		json.Number("-2.5"),
+		json.Number("-1"),
+		json.Number("0"),
+		json.Number("0.5"),
+		json.Number("1"),
+		json.Number("10"),
+		json.Number("1e10"),
+		"",
+		"\x00",
+		"\x00\x00",
+		"\x00a",
+		"a",
+		"a\x00",
+		"a\x00b",
+		"ab",
+		"b",
+	}
+	var encodedValues [][]byte
+	for _, v := range values {
+		encodedValue, ok := encodeIndexValue(v)
+		require.True(t, ok)
+		n, err := encodedIndexValueLen(append(encodedValue, []byte("key")...))
+		require.NoError(t, err)
+		require.Equal(t, len(encodedValue), n)
+		encodedValues = append(encodedValues, encodedValue)
+	}
+	require.True(t, sort.SliceIsSorted(encodedValues, func(i, j int) bool {
+		return bytes.Compare(encodedValues[i], encodedValues[j]) < 0
+	}))
+	for i := 1; i < len(encodedValues); i++ {
+		require.NotEqual(t, encodedValues[i-1], encodedValues[i])
+	}
+
+	for _, v := range []interface{}{[]interface{}{"a"}, map[string]interface{}{"a": "b"}} {
+		_, ok := encodeIndexValue(v)
+		require.False(t, ok)
+	}
+}
+
+func TestIndexEntryKey(t *testing.T) {
+	index := &indexDefinition{Name: "indexColorOwner", Fields: []string{"asset.color", "owner"}}
+	entryKey := index.entryKey("ns1", "key1", []byte(`{"asset":{"color":"blue"},"owner":"tom","size":1}`))
+	require.NotNil(t, entryKey)
+	key, err := index.decodeKey("ns1", entryKey)
+	require.NoError(t, err)
+	require.Equal(t, "key1", key)
+
+	// the values that lack a scalar value for an indexed field are not indexed
+	for _, value := range []string{
+		`not-json`,
+		`{"asset":{"color":"blue"}}`,
+		`{"asset":{"color":["blue"]},"owner":"tom"}`,
+		`{"asset":"blue","owner":"tom"}`,
+		``,
+	} {
+		require.Nil(t, index.entryKey("ns1", "key1", []byte(value)), value)
+	}
+}
+
+func TestProcessIndexesForChaincodeDeploy(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testprocessindexes", nil)
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"color":"blue","size":1,"owner":"tom"}`), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte(`{"color":"red","size":2,"owner":"jerry"}`), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte(`not-json`), version.NewHeight(1, 3))
+	batch.Put("ns2", "key1", []byte(`{"color":"blue","size":1,"owner":"tom"}`), version.NewHeight(1, 4))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)))
+
+	indexCapable, ok := db.(statedb.IndexCapable)
+	require.True(t, ok)
+	require.Equal(t, "leveldb", indexCapable.GetDBType())
+	require.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"META-INF/statedb/leveldb/indexes/indexOwner.json": []byte(`{"index":{"fields":["owner"]},"name":"indexOwner","type":"json"}`),
+		"META-INF/statedb/leveldb/indexes/invalid.json":    []byte(`{"index":{"fields":[]},"name":"invalid","type":"json"}`),
+	}))
+
+	vdb := db.(*versionedDB)
+	indexKeys := func(ns, indexName string) []string {
+		for _, index := range vdb.indexes[ns] {
+			if index.Name != indexName {
+				continue
+			}
+			entryKeyPrefix := index.entryKeyPrefix(ns)
+			itr, err := vdb.db.GetIterator(entryKeyPrefix, prefixEnd(entryKeyPrefix))
+			require.NoError(t, err)
+			defer itr.Release()
+			keys := []string{}
+			for itr.Next() {
+				key, err := index.decodeKey(ns, itr.Key())
+				require.NoError(t, err)
+				keys = append(keys, key)
+			}
+			return keys
+		}
+		return nil
+	}
+	// the entries are ordered by the owner
+	require.Equal(t, []string{"key2", "key1"}, indexKeys("ns1", "indexOwner"))
+	require.Nil(t, indexKeys("ns1", "invalid"))
+	require.Nil(t, indexKeys("ns2", "indexOwner"))
+
+	// the index is maintained by the updates
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"color":"blue","size":1,"owner":"alice"}`), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key2", version.NewHeight(2, 2))
+	batch.Put("ns1", "key3", []byte(`{"color":"green","size":3,"owner":"bob"}`), version.NewHeight(2, 3))
+	batch.Put("ns1", "key4", []byte(`{"color":"green","size":4}`), version.NewHeight(2, 4))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 4)))
+	require.Equal(t, []string{"key1", "key3"}, indexKeys("ns1", "indexOwner"))
+
+	// the definitions of the indexes are loaded by a new provider
+	env.DBProvider.Close()
+	env.DBProvider, err = NewVersionedDBProvider(env.dbPath)
+	require.NoError(t, err)
+	db, err = env.DBProvider.GetDBHandle("testprocessindexes", nil)
+	require.NoError(t, err)
+	vdb = db.(*versionedDB)
+	require.Equal(t, []*indexDefinition{{Name: "indexOwner", Fields: []string{"owner"}}}, vdb.indexes["ns1"])
+
+	// redefining the index rebuilds it whereas an unchanged definition is left as is
+	require.NoError(t, vdb.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"META-INF/statedb/leveldb/indexes/indexOwner.json": []byte(`{"index":{"fields":["size"]},"name":"indexOwner","type":"json"}`),
+		"META-INF/statedb/leveldb/indexes/indexColor.json": []byte(`{"index":{"fields":["color"]},"name":"indexColor","type":"json"}`),
+	}))
+	require.Equal(t, []string{"key1", "key3", "key4"}, indexKeys("ns1", "indexOwner"))
+	require.Equal(t, []string{"key1", "key3", "key4"}, indexKeys("ns1", "indexColor"))
+	require.NoError(t, vdb.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"META-INF/statedb/leveldb/indexes/indexColor.json": []byte(`{"index":{"fields":["color"]},"name":"indexColor","type":"json"}`),
+	}))
+	require.Len(t, vdb.indexes["ns1"], 2)
+
+	// the index data does not surface in the full scan of the state
+	fullScanItr, _, err := vdb.GetFullScanIterator(func(string) bool { return false })
+	require.NoError(t, err)
+	defer fullScanItr.Close()
+	var scannedKeys []string
+	for {
+		compositeKey, _, err := fullScanItr.Next()
+		require.NoError(t, err)
+		if compositeKey == nil {
+			break
+		}
+		scannedKeys = append(scannedKeys, compositeKey.Namespace+":"+compositeKey.Key)
+	}
+	require.Equal(t, []string{"ns1:key1", "ns1:key3", "ns1:key4", "ns2:key1"}, scannedKeys)
+}
@@ -0,0 +1,603 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+)
+
+// The rich queries on leveldb support a subset of the CouchDB query syntax. A query may include a "selector",
+// a "sort", a "fields" projection, and a "use_index" preference. The "limit" and the "bookmark", if present,
+// are ignored as these are governed by the pagination parameters, as is the case for CouchDB. The selector
+// may combine the conditions via an implicit or an explicit "$and" and each condition may use either an implicit
+// equality or one of the operators "$eq", "$gt", "$gte", "$lt", and "$lte". A nested field is specified either
+// via the dot notation or via nested objects. A condition is satisfied only by a scalar value (i.e., null, boolean,
+// number, or string) and the values are compared in the same order as they are laid out in an index.
+//
+// A query is served by the index of the namespace that constrains the longest prefix of its fields with equality
+// conditions and for which every indexed field is constrained by the selector. A query that includes a sort requires
+// an index whose fields, following the fields with equality conditions, match the sort fields. A query without a sort
+// that no index can serve is served by scanning the whole namespace
+const (
+	queryOpAnd = "$and"
+	queryOpEq  = "$eq"
+	queryOpGt  = "$gt"
+	queryOpGte = "$gte"
+	queryOpLt  = "$lt"
+	queryOpLte = "$lte"
+)
+
+type condition struct {
+	field string
+	op    string
+	value []byte
+}
+
+func (c *condition) isSatisfiedBy(doc map[string]interface{}) bool {
+	fieldValue, ok := lookupField(doc, c.field)
+	if !ok {
+		return false
+	}
+	encodedValue, ok := encodeIndexValue(fieldValue)
+	if !ok {
+		return false
+	}
+	cmp := bytes.Compare(encodedValue, c.value)
+	switch c.op {
+	case queryOpEq:
+		return cmp == 0
+	case queryOpGt:
+		return cmp > 0
+	case queryOpGte:
+		return cmp >= 0
+	case queryOpLt:
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+type sortField struct {
+	field      string
+	descending bool
+}
+
+type richQuery struct {
+	conditions []*condition
+	sort       []*sortField
+	fields     []string
+	useIndex   string
+}
+
+func parseRichQuery(query string) (*richQuery, error) {
+	jsonQueryMap := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewBufferString(query))
+	decoder.UseNumber()
+	if err := decoder.Decode(&jsonQueryMap); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the query")
+	}
+
+	q := &richQuery{}
+	for k, v := range jsonQueryMap {
+		var err error
+		switch k {
+		case "selector":
+			selector, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("the selector must be an object")
+			}
+			err = q.parseSelector(selector, "")
+		case "sort":
+			err = q.parseSort(v)
+		case "fields":
+			err = q.parseFields(v)
+		case "use_index":
+			err = q.parseUseIndex(v)
+		case "limit", "bookmark":
+			// overridden by the pagination parameters
+		default:
+			err = errors.Errorf("unsupported query option [%s]", k)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	// order the conditions for a deterministic choice of the index range
+	sort.SliceStable(q.conditions, func(i, j int) bool {
+		return q.conditions[i].field < q.conditions[j].field
+	})
+	return q, nil
+}
+
+func (q *richQuery) parseSelector(selector map[string]interface{}, prefix string) error {
+	for k, v := range selector {
+		if k == queryOpAnd {
+			subSelectors, ok := v.([]interface{})
+			if !ok {
+				return errors.Errorf("the operator [%s] requires an array of selectors", queryOpAnd)
+			}
+			for _, subSelector := range subSelectors {
+				subSelector, ok := subSelector.(map[string]interface{})
+				if !ok {
+					return errors.Errorf("the operator [%s] requires an array of selectors", queryOpAnd)
+				}
+				if err := q.parseSelector(subSelector, prefix); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(k, "$") {
+			return errors.Errorf("unsupported operator [%s] in the selector", k)
+		}
+
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+		subSelector, ok := v.(map[string]interface{})
+		if !ok {
+			if err := q.addCondition(field, queryOpEq, v); err != nil {
+				return err
+			}
+			continue
+		}
+		hasOperators, err := isOperatorObject(subSelector)
+		if err != nil {
+			return errors.WithMessagef(err, "invalid condition on the field [%s]", field)
+		}
+		if !hasOperators {
+			if err := q.parseSelector(subSelector, field); err != nil {
+				return err
+			}
+			continue
+		}
+		for op, operand := range subSelector {
+			switch op {
+			case queryOpEq, queryOpGt, queryOpGte, queryOpLt, queryOpLte:
+				if err := q.addCondition(field, op, operand); err != nil {
+					return err
+				}
+			default:
+				return errors.Errorf("unsupported operator [%s] in the selector", op)
+			}
+		}
+	}
+	return nil
+}
+
+func (q *richQuery) addCondition(field, op string, operand interface{}) error {
+	encodedValue, ok := encodeIndexValue(operand)
+	if !ok {
+		return errors.Errorf("the operator [%s] on the field [%s] requires a scalar value", op, field)
+	}
+	q.conditions = append(q.conditions, &condition{field: field, op: op, value: encodedValue})
+	return nil
+}
+
+func (q *richQuery) parseSort(v interface{}) error {
+	sortFields, ok := v.([]interface{})
+	if !ok {
+		return errors.New("the sort must be an array")
+	}
+	for _, s := range sortFields {
+		switch s := s.(type) {
+		case string:
+			q.sort = append(q.sort, &sortField{field: s})
+		case map[string]interface{}:
+			if len(s) != 1 {
+				return errors.New("a sort field must be either a field name or an object with a single field name and sort direction")
+			}
+			for field, direction := range s {
+				switch direction {
+				case "asc":
+					q.sort = append(q.sort, &sortField{field: field})
+				case "desc":
+					q.sort = append(q.sort, &sortField{field: field, descending: true})
+				default:
+					return errors.Errorf("invalid sort direction [%v] for the field [%s]", direction, field)
+				}
+			}
+		default:
+			return errors.Errorf("invalid sort field [%v]", s)
+		}
+	}
+	for i := 1; i < len(q.sort); i++ {
+		if q.sort[i].descending != q.sort[0].descending {
+			return errors.New("the sort fields must all be in the same direction")
+		}
+	}
+	return nil
+}
+
+func (q *richQuery) parseFields(v interface{}) error {
+	fields, ok := v.([]interface{})
+	if !ok {
+		return errors.New("fields definition must be an array")
+	}
+	for _, f := range fields {
+		field, ok := f.(string)
+		if !ok {
+			return errors.Errorf("invalid field [%v] in the fields definition", f)
+		}
+		q.fields = append(q.fields, field)
+	}
+	return nil
+}
+
+// parseUseIndex parses the "use_index" that is specified either as a design document or as an array of
+// a design document and an index name. Only the index name, or the design document if the name is not
+// specified, is retained as the name of an index is unique within a namespace
+func (q *richQuery) parseUseIndex(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		q.useIndex = "_design/" + strings.TrimPrefix(v, "_design/")
+		return nil
+	case []interface{}:
+		if len(v) == 1 || len(v) == 2 {
+			if ddoc, ok := v[0].(string); ok {
+				q.useIndex = "_design/" + strings.TrimPrefix(ddoc, "_design/")
+				if len(v) == 1 {
+					return nil
+				}
+				if name, ok := v[1].(string); ok {
+					q.useIndex = name
+					return nil
+				}
+			}
+		}
+	}
+	return errors.Errorf("invalid use_index [%v]", v)
+}
+
+func (q *richQuery) matches(doc map[string]interface{}) bool {
+	for _, c := range q.conditions {
+		if !c.isSatisfiedBy(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+// project returns the value restricted to the fields of the query, if specified
+func (q *richQuery) project(doc map[string]interface{}, value []byte) ([]byte, error) {
+	if len(q.fields) == 0 {
+		return value, nil
+	}
+	projected := map[string]interface{}{}
+	for _, field := range q.fields {
+		fieldValue, ok := lookupField(doc, field)
+		if !ok {
+			continue
+		}
+		path := strings.Split(field, ".")
+		target := projected
+		for _, p := range path[:len(path)-1] {
+			next, ok := target[p].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				target[p] = next
+			}
+			target = next
+		}
+		target[path[len(path)-1]] = fieldValue
+	}
+	return json.Marshal(projected)
+}
+
+// queryPlan captures the range of keys to scan for a query. If index is nil, the data keys of
+// the namespace are scanned, else the entries of the index are scanned
+type queryPlan struct {
+	index      *indexDefinition
+	startKey   []byte
+	endKey     []byte
+	descending bool
+}
+
+// planQuery selects the index, if any, and the range of keys to scan for the query
+func (vdb *versionedDB) planQuery(namespace string, q *richQuery) (*queryPlan, error) {
+	var best *queryPlan
+	bestScore := -1
+	for _, index := range vdb.indexes[namespace] {
+		if q.useIndex != "" && q.useIndex != index.Name && q.useIndex != "_design/"+index.DesignDocument {
+			continue
+		}
+		plan, score := q.planIndexScan(namespace, index)
+		if plan == nil {
+			continue
+		}
+		if score > bestScore || (score == bestScore && index.Name < best.index.Name) {
+			best, bestScore = plan, score
+		}
+	}
+	switch {
+	case best != nil:
+		return best, nil
+	case q.useIndex != "":
+		return nil, errors.Errorf("the index [%s] specified in use_index does not exist or is not usable for the query", q.useIndex)
+	case len(q.sort) > 0:
+		return nil, errors.New("no index exists for the sort fields of the query, try indexing by the sort fields")
+	}
+	return &queryPlan{
+		startKey: encodeDataKey(namespace, ""),
+		endKey:   dataKeyStarterForNextNamespace(namespace),
+	}, nil
+}
+
+// planIndexScan returns the plan for serving the query via the given index, along with a score
+// that favors the plans that scan a narrower range. Nil is returned if the index is not usable
+func (q *richQuery) planIndexScan(namespace string, index *indexDefinition) (*queryPlan, int) {
+	conditionsByField := map[string][]*condition{}
+	for _, c := range q.conditions {
+		conditionsByField[c.field] = append(conditionsByField[c.field], c)
+	}
+
+	prefix := index.entryKeyPrefix(namespace)
+	eqPrefixLen := 0
+	for i, field := range index.Fields {
+		conditions, ok := conditionsByField[field]
+		if !ok {
+			// every indexed field must be constrained so that the index covers all the matching keys
+			return nil, 0
+		}
+		if i != eqPrefixLen {
+			continue
+		}
+		if eq := findEqCondition(conditions); eq != nil {
+			prefix = append(prefix, eq.value...)
+			eqPrefixLen++
+		}
+	}
+
+	plan := &queryPlan{index: index}
+	if len(q.sort) > 0 {
+		if !sortMatchesIndex(q.sort, index.Fields, eqPrefixLen) {
+			return nil, 0
+		}
+		plan.descending = q.sort[0].descending
+	}
+
+	score := 2 * eqPrefixLen
+	plan.startKey, plan.endKey = prefix, prefixEnd(prefix)
+	if eqPrefixLen < len(index.Fields) {
+		startKey, endKey, bounded := rangeBounds(prefix, conditionsByField[index.Fields[eqPrefixLen]])
+		if bounded {
+			plan.startKey, plan.endKey = startKey, endKey
+			score++
+		}
+	}
+	return plan, score
+}
+
+func findEqCondition(conditions []*condition) *condition {
+	for _, c := range conditions {
+		if c.op == queryOpEq {
+			return c
+		}
+	}
+	return nil
+}
+
+// sortMatchesIndex returns true if the sort fields follow a prefix of the index fields
+// that is fixed by the equality conditions of the query
+func sortMatchesIndex(sortFields []*sortField, indexFields []string, eqPrefixLen int) bool {
+	for start := 0; start <= eqPrefixLen; start++ {
+		if start+len(sortFields) > len(indexFields) {
+			return false
+		}
+		matched := true
+		for i, s := range sortFields {
+			if indexFields[start+i] != s.field {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeBounds returns the tightest range of the index entries, under the given prefix,
+// that satisfies the range conditions on the field that follows the prefix
+func rangeBounds(prefix []byte, conditions []*condition) ([]byte, []byte, bool) {
+	startKey, endKey := prefix, prefixEnd(prefix)
+	bounded := false
+	for _, c := range conditions {
+		var k []byte
+		switch c.op {
+		case queryOpGt, queryOpGte, queryOpLt, queryOpLte:
+			k = append(append([]byte{}, prefix...), c.value...)
+		default:
+			continue
+		}
+		switch c.op {
+		case queryOpGt:
+			k = prefixEnd(k)
+			fallthrough
+		case queryOpGte:
+			if bytes.Compare(k, startKey) > 0 {
+				startKey = k
+			}
+		case queryOpLte:
+			k = prefixEnd(k)
+			fallthrough
+		case queryOpLt:
+			if bytes.Compare(k, endKey) < 0 {
+				endKey = k
+			}
+		}
+		bounded = true
+	}
+	return startKey, endKey, bounded
+}
+
+// ExecuteQuery implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	// pageSize = 0 denotes unlimited page size
+	return vdb.ExecuteQueryWithPagination(namespace, query, "", 0)
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface. The returned bookmark
+// is the hex encoded key, either of the data or of the index entry, to resume the scan from
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	q, err := parseRichQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	vdb.indexesLock.RLock()
+	plan, err := vdb.planQuery(namespace, q)
+	vdb.indexesLock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if plan.index != nil {
+		logger.Debugf("Channel [%s]: Executing query [%s] on namespace [%s] via the index [%s]", vdb.dbName, query, namespace, plan.index.Name)
+	} else {
+		logger.Debugf("Channel [%s]: Executing query [%s] on namespace [%s] via a scan of the namespace", vdb.dbName, query, namespace)
+	}
+
+	if bookmark != "" {
+		bookmarkKey, err := hex.DecodeString(bookmark)
+		if err != nil || bytes.Compare(bookmarkKey, plan.startKey) < 0 || bytes.Compare(bookmarkKey, plan.endKey) >= 0 {
+			return nil, errors.Errorf("invalid bookmark [%s] for the query", bookmark)
+		}
+		if plan.descending {
+			plan.endKey = append(bookmarkKey, 0x00)
+		} else {
+			plan.startKey = bookmarkKey
+		}
+	}
+
+	dbItr, err := vdb.db.GetIterator(plan.startKey, plan.endKey)
+	if err != nil {
+		return nil, err
+	}
+	return &queryScanner{
+		vdb:            vdb,
+		namespace:      namespace,
+		query:          q,
+		plan:           plan,
+		dbItr:          dbItr,
+		requestedLimit: pageSize,
+	}, nil
+}
+
+type queryScanner struct {
+	vdb                  *versionedDB
+	namespace            string
+	query                *richQuery
+	plan                 *queryPlan
+	dbItr                iterator.Iterator
+	started              bool
+	requestedLimit       int32
+	totalRecordsReturned int32
+}
+
+func (scanner *queryScanner) moveNext() bool {
+	if !scanner.plan.descending {
+		return scanner.dbItr.Next()
+	}
+	if !scanner.started {
+		scanner.started = true
+		return scanner.dbItr.Last()
+	}
+	return scanner.dbItr.Prev()
+}
+
+func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
+	for scanner.moveNext() {
+		key, vv, err := scanner.currentKV()
+		if err != nil {
+			return nil, err
+		}
+		if vv == nil {
+			continue
+		}
+		doc := parseJSONObject(vv.Value)
+		if doc == nil || !scanner.query.matches(doc) {
+			continue
+		}
+		if vv.Value, err = scanner.query.project(doc, vv.Value); err != nil {
+			return nil, errors.Wrap(err, "error marshalling the projected value")
+		}
+		scanner.totalRecordsReturned++
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
+			VersionedValue: *vv,
+		}, nil
+	}
+	return nil, errors.Wrap(scanner.dbItr.Error(), "internal leveldb error while executing the query")
+}
+
+// currentKV returns the key and the value at the current position of the scan
+func (scanner *queryScanner) currentKV() (string, *statedb.VersionedValue, error) {
+	dbKey := scanner.dbItr.Key()
+	if scanner.plan.index == nil {
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		_, key := decodeDataKey(dbKey)
+		vv, err := decodeValue(dbValCopy)
+		return key, vv, err
+	}
+	key, err := scanner.plan.index.decodeKey(scanner.namespace, dbKey)
+	if err != nil {
+		return "", nil, err
+	}
+	vv, err := scanner.vdb.GetState(scanner.namespace, key)
+	return key, vv, err
+}
+
+func (scanner *queryScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// GetBookmarkAndClose returns the bookmark for the next page, which is empty if no more results remain
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	retval := ""
+	for scanner.moveNext() {
+		key, vv, err := scanner.currentKV()
+		if err != nil || vv == nil {
+			continue
+		}
+		if doc := parseJSONObject(vv.Value); doc != nil && scanner.query.matches(doc) {
+			retval = hex.EncodeToString(scanner.dbItr.Key())
+			logger.Debugf("Next page of the query on namespace [%s] starts at the key [%s]", scanner.namespace, key)
+			break
+		}
+	}
+	scanner.Close()
+	return retval
+}
+
+// isOperatorObject returns true if all the keys of the given object are operators and false if none is.
+// An error is returned if the object mixes the operators and the fields
+func isOperatorObject(m map[string]interface{}) (bool, error) {
+	operators := 0
+	for k := range m {
+		if strings.HasPrefix(k, "$") {
+			operators++
+		}
+	}
+	if operators > 0 && operators < len(m) {
+		return false, errors.New("the operators and the fields cannot be mixed")
+	}
+	return operators > 0, nil
+}
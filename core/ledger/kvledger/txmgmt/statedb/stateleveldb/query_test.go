@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRichQueryErrors(t *testing.T) {
+	testCases := []struct {
+		query       string
+		expectedErr string
+	}{
+		{`not-json`, "error unmarshalling the query: invalid character 'o' in literal null (expecting 'u')"},
+		{`{"selector":[]}`, "the selector must be an object"},
+		{`{"selector":{"owner":"tom"},"skip":1}`, "unsupported query option [skip]"},
+		{`{"selector":{"$or":[{"owner":"tom"}]}}`, "unsupported operator [$or] in the selector"},
+		{`{"selector":{"size":{"$in":[1,2]}}}`, "unsupported operator [$in] in the selector"},
+		{`{"selector":{"size":{"$gt":1,"color":"red"}}}`, "invalid condition on the field [size]: the operators and the fields cannot be mixed"},
+		{`{"selector":{"$and":{"owner":"tom"}}}`, "the operator [$and] requires an array of selectors"},
+		{`{"selector":{"owner":["tom"]}}`, "the operator [$eq] on the field [owner] requires a scalar value"},
+		{`{"selector":{"owner":"tom"},"sort":"owner"}`, "the sort must be an array"},
+		{`{"selector":{"owner":"tom"},"sort":[{"owner":"up"}]}`, "invalid sort direction [up] for the field [owner]"},
+		{`{"selector":{"owner":"tom"},"sort":[{"owner":"asc"},{"size":"desc"}]}`, "the sort fields must all be in the same direction"},
+		{`{"selector":{"owner":"tom"},"fields":"owner"}`, "fields definition must be an array"},
+		{`{"selector":{"owner":"tom"},"use_index":1}`, "invalid use_index [1]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := parseRichQuery(tc.query)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestExecuteQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testexecutequery", nil)
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	owners := []string{"tom", "jerry", "tom", "fred", "tom", "jerry"}
+	for i, owner := range owners {
+		value := fmt.Sprintf(`{"asset":{"color":"%s"},"size":%d,"owner":"%s"}`, []string{"blue", "red"}[i%2], i+1, owner)
+		batch.Put("ns1", fmt.Sprintf("key%d", i+1), []byte(value), version.NewHeight(1, uint64(i+1)))
+	}
+	batch.Put("ns1", "key7", []byte(`{"asset":{"color":"blue"},"size":"large","owner":"tom"}`), version.NewHeight(1, 7))
+	batch.Put("ns1", "key8", []byte(`not-json`), version.NewHeight(1, 8))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 8)))
+
+	indexCapable := db.(statedb.IndexCapable)
+	require.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"indexOwnerSize.json": []byte(`{"index":{"fields":["owner","size"]},"ddoc":"indexOwnerSizeDoc","name":"indexOwnerSize","type":"json"}`),
+		"indexSize.json":      []byte(`{"index":{"fields":["size"]},"ddoc":"indexSizeDoc","name":"indexSize","type":"json"}`),
+	}))
+
+	queryKeys := func(query string) []string {
+		itr, err := db.ExecuteQuery("ns1", query)
+		require.NoError(t, err)
+		defer itr.Close()
+		keys := []string{}
+		for {
+			result, err := itr.Next()
+			require.NoError(t, err)
+			if result == nil {
+				return keys
+			}
+			keys = append(keys, result.(*statedb.VersionedKV).Key)
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedKeys []string
+	}{
+		{
+			name:         "equality-via-index",
+			query:        `{"selector":{"owner":"tom","size":{"$gte":0}}}`,
+			expectedKeys: []string{"key1", "key3", "key5", "key7"},
+		},
+		{
+			name:         "range-via-index",
+			query:        `{"selector":{"size":{"$gt":2,"$lte":5}}}`,
+			expectedKeys: []string{"key3", "key4", "key5"},
+		},
+		{
+			name:         "range-across-types",
+			query:        `{"selector":{"size":{"$gt":5}}}`,
+			expectedKeys: []string{"key6", "key7"},
+		},
+		{
+			name:         "sort-ascending",
+			query:        `{"selector":{"owner":"tom","size":{"$lt":"z"}},"sort":[{"size":"asc"}]}`,
+			expectedKeys: []string{"key1", "key3", "key5", "key7"},
+		},
+		{
+			name:         "sort-descending",
+			query:        `{"selector":{"owner":"tom","size":{"$lt":"z"}},"sort":[{"size":"desc"}]}`,
+			expectedKeys: []string{"key7", "key5", "key3", "key1"},
+		},
+		{
+			name:         "sort-by-multiple-fields",
+			query:        `{"selector":{"owner":{"$gt":"a"},"size":{"$gte":2}},"sort":["owner","size"]}`,
+			expectedKeys: []string{"key4", "key2", "key6", "key3", "key5", "key7"},
+		},
+		{
+			name:         "nested-field-without-index",
+			query:        `{"selector":{"$and":[{"asset":{"color":"red"}},{"asset.color":{"$eq":"red"}}]}}`,
+			expectedKeys: []string{"key2", "key4", "key6"},
+		},
+		{
+			name:         "filter-beyond-index",
+			query:        `{"selector":{"owner":"jerry","size":{"$gt":0},"asset.color":"red"}}`,
+			expectedKeys: []string{"key2", "key6"},
+		},
+		{
+			name:         "use-index",
+			query:        `{"selector":{"owner":"jerry","size":{"$gt":0}},"use_index":["indexSizeDoc","indexSize"]}`,
+			expectedKeys: []string{"key2", "key6"},
+		},
+		{
+			name:         "no-match",
+			query:        `{"selector":{"owner":"mary","size":{"$gt":0}}}`,
+			expectedKeys: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedKeys, queryKeys(tc.query))
+		})
+	}
+
+	t.Run("plan", func(t *testing.T) {
+		vdb := db.(*versionedDB)
+		for query, expectedIndex := range map[string]string{
+			`{"selector":{"owner":"tom","size":{"$gt":0}}}`:                                "indexOwnerSize",
+			`{"selector":{"owner":{"$gt":"a"},"size":1}}`:                                  "indexSize",
+			`{"selector":{"owner":"tom","size":1},"use_index":"_design/indexSizeDoc"}`:     "indexSize",
+			`{"selector":{"owner":"tom","size":{"$gt":0}},"sort":["size"]}`:                "indexOwnerSize",
+			`{"selector":{"owner":"tom","size":{"$gt":0}},"sort":["owner","size"]}`:        "indexOwnerSize",
+			`{"selector":{"owner":{"$gt":"a"},"size":{"$gt":0}},"sort":[{"size":"desc"}]}`: "indexSize",
+			`{"selector":{"owner":"tom"}}`:                                                 "",
+		} {
+			q, err := parseRichQuery(query)
+			require.NoError(t, err)
+			plan, err := vdb.planQuery("ns1", q)
+			require.NoError(t, err)
+			indexName := ""
+			if plan.index != nil {
+				indexName = plan.index.Name
+			}
+			require.Equal(t, expectedIndex, indexName, query)
+		}
+	})
+
+	t.Run("unusable-index", func(t *testing.T) {
+		_, err := db.ExecuteQuery("ns1", `{"selector":{"owner":"tom"},"sort":["size"]}`)
+		require.EqualError(t, err, "no index exists for the sort fields of the query, try indexing by the sort fields")
+		_, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"tom"},"use_index":"indexSizeDoc"}`)
+		require.EqualError(t, err, "the index [_design/indexSizeDoc] specified in use_index does not exist or is not usable for the query")
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		itr, err := db.ExecuteQuery("ns1", `{"selector":{"size":1},"fields":["owner","asset.color","missing"]}`)
+		require.NoError(t, err)
+		defer itr.Close()
+		result, err := itr.Next()
+		require.NoError(t, err)
+		require.Equal(t, "key1", result.(*statedb.VersionedKV).Key)
+		require.JSONEq(t, `{"owner":"tom","asset":{"color":"blue"}}`, string(result.(*statedb.VersionedKV).Value))
+		require.Equal(t, version.NewHeight(1, 1), result.(*statedb.VersionedKV).Version)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		for _, query := range []string{
+			`{"selector":{"owner":"tom","size":{"$lt":"z"}},"sort":[{"size":"desc"}]}`,
+			`{"selector":{"owner":"tom"}}`,
+		} {
+			var keys []string
+			bookmark := ""
+			for {
+				itr, err := db.ExecuteQueryWithPagination("ns1", query, bookmark, 3)
+				require.NoError(t, err)
+				for {
+					result, err := itr.Next()
+					require.NoError(t, err)
+					if result == nil {
+						break
+					}
+					keys = append(keys, result.(*statedb.VersionedKV).Key)
+				}
+				if bookmark = itr.GetBookmarkAndClose(); bookmark == "" {
+					break
+				}
+			}
+			require.ElementsMatch(t, []string{"key1", "key3", "key5", "key7"}, keys)
+		}
+
+		_, err := db.ExecuteQueryWithPagination("ns1", `{"selector":{"owner":"tom"}}`, "not-hex", 3)
+		require.EqualError(t, err, "invalid bookmark [not-hex] for the query")
+		_, err = db.ExecuteQueryWithPagination("ns1", `{"selector":{"owner":"tom"}}`, "00", 3)
+		require.EqualError(t, err, "invalid bookmark [00] for the query")
+	})
+}
@@ -8,6 +8,7 @@ package stateleveldb
 
 import (
 	"bytes"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/dataformat"
@@ -27,11 +28,15 @@ var (
 	lastKeyIndicator            = byte(0x01)
 	savePointKey                = []byte{'s'}
 	fullScanIteratorValueFormat = byte(1)
+	maxDataImportBatchSize      = 4 * 1024 * 1024
 )
 
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
+	// the VersionedDB instances are retained so that all the handles to a database share the definitions of its indexes
+	databases map[string]*versionedDB
+	mux       sync.Mutex
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
@@ -45,12 +50,91 @@ func NewVersionedDBProvider(dbPath string) (*VersionedDBProvider, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &VersionedDBProvider{dbProvider}, nil
+	return &VersionedDBProvider{
+		dbProvider: dbProvider,
+		databases:  make(map[string]*versionedDB),
+	}, nil
 }
 
 // GetDBHandle gets the handle to a named database
 func (provider *VersionedDBProvider) GetDBHandle(dbName string, namespaceProvider statedb.NamespaceProvider) (statedb.VersionedDB, error) {
-	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	vdb, ok := provider.databases[dbName]
+	if ok {
+		return vdb, nil
+	}
+	vdb, err := newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName)
+	if err != nil {
+		return nil, err
+	}
+	provider.databases[dbName] = vdb
+	return vdb, nil
+}
+
+// ImportFromSnapshot loads the data exported by the FullScanIterator of a stateleveldb into the empty
+// database for the given id and records the given savepoint
+func (provider *VersionedDBProvider) ImportFromSnapshot(
+	dbName string,
+	savepoint *version.Height,
+	itr statedb.FullScanIterator,
+	dbValueFormat byte,
+) error {
+	db := provider.dbProvider.GetDBHandle(dbName)
+	empty, err := isEmpty(db)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return errors.Errorf("statedb for channel [%s] is not empty. Import from snapshot is supported only for an empty statedb", dbName)
+	}
+
+	batch := db.NewUpdateBatch()
+	batchSize := 0
+	for {
+		compositeKey, dbValue, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if compositeKey == nil {
+			break
+		}
+		if dbValueFormat != fullScanIteratorValueFormat {
+			return errors.Errorf("unsupported db value format [%d] for importing the snapshot data in leveldb", dbValueFormat)
+		}
+		dataKey := encodeDataKey(compositeKey.Namespace, compositeKey.Key)
+		batch.Put(dataKey, dbValue)
+		batchSize += len(dataKey) + len(dbValue)
+		if batchSize >= maxDataImportBatchSize {
+			if err := db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = db.NewUpdateBatch()
+			batchSize = 0
+		}
+	}
+	batch.Put(savePointKey, savepoint.ToBytes())
+	return db.WriteBatch(batch, true)
+}
+
+// Drop drops the data of the database for the given id. It is not an error if the database does not exist
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	if err := provider.dbProvider.GetDBHandle(dbName).DeleteAll(); err != nil {
+		return errors.WithMessagef(err, "error while dropping the statedb for channel [%s]", dbName)
+	}
+	delete(provider.databases, dbName)
+	return nil
+}
+
+func isEmpty(db *leveldbhelper.DBHandle) (bool, error) {
+	itr, err := db.GetIterator(nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer itr.Release()
+	return !itr.Next(), itr.Error()
 }
 
 // Close closes the underlying db
@@ -62,11 +146,22 @@ func (provider *VersionedDBProvider) Close() {
 type versionedDB struct {
 	db     *leveldbhelper.DBHandle
 	dbName string
+	// indexes maps a namespace to the definitions of its indexes
+	indexes     map[string][]*indexDefinition
+	indexesLock sync.RWMutex
 }
 
 // newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) *versionedDB {
-	return &versionedDB{db, dbName}
+func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) (*versionedDB, error) {
+	indexes, err := loadIndexes(db)
+	if err != nil {
+		return nil, err
+	}
+	return &versionedDB{
+		db:      db,
+		dbName:  dbName,
+		indexes: indexes,
+	}, nil
 }
 
 // Open implements method in VersionedDB interface
@@ -150,22 +245,18 @@ func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string
 	return newKVScanner(namespace, dbItr, pageSize), nil
 }
 
-// ExecuteQuery implements method in VersionedDB interface
-func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
-	return nil, errors.New("ExecuteQuery not supported for leveldb")
-}
-
-// ExecuteQueryWithPagination implements method in VersionedDB interface
-func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
-	return nil, errors.New("ExecuteQueryWithMetadata not supported for leveldb")
-}
-
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	vdb.indexesLock.RLock()
+	defer vdb.indexesLock.RUnlock()
+
 	dbBatch := vdb.db.NewUpdateBatch()
 	namespaces := batch.GetUpdatedNamespaces()
 	for _, ns := range namespaces {
 		updates := batch.GetUpdates(ns)
+		if err := vdb.addIndexUpdates(dbBatch, ns, updates); err != nil {
+			return err
+		}
 		for k, vv := range updates {
 			dataKey := encodeDataKey(ns, k)
 			logger.Debugf("Channel [%s]: Applying key(string)=[%s] key(bytes)=[%#v]", vdb.dbName, string(dataKey), dataKey)
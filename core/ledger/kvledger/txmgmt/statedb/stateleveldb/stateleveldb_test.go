@@ -47,6 +47,18 @@ func TestIterator(t *testing.T) {
 	})
 }
 
+func TestConformance(t *testing.T) {
+	commontests.TestVersionedDBProviderConformance(
+		t,
+		func(t *testing.T) (statedb.VersionedDBProvider, func()) {
+			env := NewTestVDBEnv(t)
+			return env.DBProvider, env.Cleanup
+		},
+		TestEnvDBValueformat,
+		TestEnvDBValueDecoder,
+	)
+}
+
 func TestDataKeyEncoding(t *testing.T) {
 	testDataKeyEncoding(t, "ledger1", "ns", "key")
 	testDataKeyEncoding(t, "ledger2", "ns", "")
@@ -76,11 +88,29 @@ func TestQueryOnLevelDB(t *testing.T) {
 	db.ApplyUpdates(batch, savePoint)
 
 	// query for owner=jerry, use namespace "ns1"
-	// As queries are not supported in levelDB, call to ExecuteQuery()
-	// should return a error message
+	// As no index is defined for the namespace, the query scans the namespace
 	itr, err := db.ExecuteQuery("ns1", `{"selector":{"owner":"jerry"}}`)
-	require.Error(t, err, "ExecuteQuery not supported for leveldb")
-	require.Nil(t, itr)
+	require.NoError(t, err)
+	queryResult, err := itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, queryResult)
+	itr.Close()
+
+	// query for owner=tom
+	itr, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"tom"}}`)
+	require.NoError(t, err)
+	defer itr.Close()
+	queryResult, err = itr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "key1", queryResult.(*statedb.VersionedKV).Key)
+	require.Equal(t, jsonValue1, string(queryResult.(*statedb.VersionedKV).Value))
+	queryResult, err = itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, queryResult)
+
+	// queries with a sort require an index
+	_, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"tom"},"sort":["size"]}`)
+	require.EqualError(t, err, "no index exists for the sort fields of the query, try indexing by the sort fields")
 }
 
 func TestGetStateMultipleKeys(t *testing.T) {
@@ -147,6 +177,18 @@ func TestFullScanIterator(t *testing.T) {
 	)
 }
 
+func TestImportFromSnapshot(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestImportFromSnapshot(t, env.DBProvider)
+}
+
+func TestDrop(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDrop(t, env.DBProvider)
+}
+
 func TestFullScanIteratorErrorPropagation(t *testing.T) {
 	var env *TestVDBEnv
 	var cleanup func()
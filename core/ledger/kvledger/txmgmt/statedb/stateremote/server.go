@@ -0,0 +1,332 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateremote
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+// Server implements the StateDB service on top of a statedb.VersionedDBProvider. A remote state store can use
+// this for exposing its VersionedDBProvider to the peers. This also serves as a local stand-in for a remote
+// state store in tests
+type Server struct {
+	provider statedb.VersionedDBProvider
+	mutex    sync.Mutex
+	dbs      map[string]statedb.VersionedDB
+}
+
+// NewServer constructs a Server that serves the state databases from the given provider
+func NewServer(provider statedb.VersionedDBProvider) *Server {
+	return &Server{
+		provider: provider,
+		dbs:      map[string]statedb.VersionedDB{},
+	}
+}
+
+// GetDBInfo implements the method in the StateDBServer interface
+func (s *Server) GetDBInfo(ctx context.Context, req *DBRequest) (*DBInfo, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	return &DBInfo{BytesKeySupported: db.BytesKeySupported()}, nil
+}
+
+// GetState implements the method in the StateDBServer interface
+func (s *Server) GetState(ctx context.Context, req *GetStateRequest) (*GetStateResponse, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	vv, err := db.GetState(req.Namespace, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &GetStateResponse{Kv: toKV(req.Namespace, req.Key, vv)}, nil
+}
+
+// GetStateMultipleKeys implements the method in the StateDBServer interface
+func (s *Server) GetStateMultipleKeys(ctx context.Context, req *GetStateMultipleKeysRequest) (*GetStateMultipleKeysResponse, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	vvs, err := db.GetStateMultipleKeys(req.Namespace, req.Keys)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GetStateMultipleKeysResponse{}
+	for i, vv := range vvs {
+		resp.Values = append(resp.Values, &GetStateResponse{Kv: toKV(req.Namespace, req.Keys[i], vv)})
+	}
+	return resp, nil
+}
+
+// GetStateRange implements the method in the StateDBServer interface
+func (s *Server) GetStateRange(ctx context.Context, req *GetStateRangeRequest) (*QueryResponse, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	itr, err := db.GetStateRangeScanIteratorWithPagination(req.Namespace, req.StartKey, req.EndKey, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := readAll(itr)
+	if err != nil {
+		itr.Close()
+		return nil, err
+	}
+	return &QueryResponse{Kvs: kvs, Bookmark: itr.GetBookmarkAndClose()}, nil
+}
+
+// ExecuteQuery implements the method in the StateDBServer interface
+func (s *Server) ExecuteQuery(ctx context.Context, req *ExecuteQueryRequest) (*QueryResponse, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	if req.PageSize == 0 {
+		itr, err := db.ExecuteQuery(req.Namespace, req.Query)
+		if err != nil {
+			return nil, err
+		}
+		defer itr.Close()
+		kvs, err := readAll(itr)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryResponse{Kvs: kvs}, nil
+	}
+
+	itr, err := db.ExecuteQueryWithPagination(req.Namespace, req.Query, req.Bookmark, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := readAll(itr)
+	if err != nil {
+		itr.Close()
+		return nil, err
+	}
+	return &QueryResponse{Kvs: kvs, Bookmark: itr.GetBookmarkAndClose()}, nil
+}
+
+// ApplyUpdates implements the method in the StateDBServer interface
+func (s *Server) ApplyUpdates(ctx context.Context, req *ApplyUpdatesRequest) (*empty.Empty, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	batch := statedb.NewUpdateBatch()
+	for _, kv := range req.Updates {
+		vv, err := fromKV(kv)
+		if err != nil {
+			return nil, err
+		}
+		batch.Update(kv.Namespace, kv.Key, vv)
+	}
+	height, err := heightFromBytes(req.Height)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.ApplyUpdates(batch, height); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// GetLatestSavePoint implements the method in the StateDBServer interface
+func (s *Server) GetLatestSavePoint(ctx context.Context, req *DBRequest) (*SavePoint, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	height, err := db.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	return &SavePoint{Height: heightToBytes(height)}, nil
+}
+
+// ValidateKeyValue implements the method in the StateDBServer interface
+func (s *Server) ValidateKeyValue(ctx context.Context, req *ValidateKeyValueRequest) (*empty.Empty, error) {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.ValidateKeyValue(req.Key, req.Value); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// FullScan implements the method in the StateDBServer interface
+func (s *Server) FullScan(req *DBRequest, stream StateDB_FullScanServer) error {
+	db, err := s.getDB(req.DbName)
+	if err != nil {
+		return err
+	}
+	itr, valueFormat, err := db.GetFullScanIterator(func(string) bool { return false })
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	if err := stream.Send(
+		&FullScanResponse{
+			Content: &FullScanResponse_ValueFormat{ValueFormat: uint32(valueFormat)},
+		},
+	); err != nil {
+		return err
+	}
+	for {
+		compositeKey, value, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if compositeKey == nil {
+			return nil
+		}
+		if err := stream.Send(
+			&FullScanResponse{
+				Content: &FullScanResponse_Kv{
+					Kv: &FullScanKV{Namespace: compositeKey.Namespace, Key: compositeKey.Key, Value: value},
+				},
+			},
+		); err != nil {
+			return err
+		}
+	}
+}
+
+// ImportFromSnapshot implements the method in the StateDBServer interface
+func (s *Server) ImportFromSnapshot(stream StateDB_ImportFromSnapshotServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := req.GetHeader()
+	if header == nil {
+		return errors.New("the first message of the import stream must carry the header")
+	}
+	savepoint, err := heightFromBytes(header.Savepoint)
+	if err != nil {
+		return err
+	}
+	if err := s.provider.ImportFromSnapshot(
+		header.DbName,
+		savepoint,
+		&importStreamIterator{stream: stream},
+		byte(header.ValueFormat),
+	); err != nil {
+		return err
+	}
+	return stream.SendAndClose(&empty.Empty{})
+}
+
+func (s *Server) getDB(name string) (statedb.VersionedDB, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if db, ok := s.dbs[name]; ok {
+		return db, nil
+	}
+	db, err := s.provider.GetDBHandle(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.dbs[name] = db
+	return db, nil
+}
+
+// importStreamIterator implements statedb.FullScanIterator over the data received in an import stream
+type importStreamIterator struct {
+	stream StateDB_ImportFromSnapshotServer
+}
+
+func (i *importStreamIterator) Next() (*statedb.CompositeKey, []byte, error) {
+	req, err := i.stream.Recv()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	kv := req.GetKv()
+	if kv == nil {
+		return nil, nil, errors.New("unexpected message in the import stream, expected data")
+	}
+	return &statedb.CompositeKey{Namespace: kv.Namespace, Key: kv.Key}, kv.Value, nil
+}
+
+func (i *importStreamIterator) Close() {
+}
+
+func readAll(itr statedb.ResultsIterator) ([]*KV, error) {
+	var kvs []*KV
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return kvs, nil
+		}
+		versionedKV := res.(*statedb.VersionedKV)
+		kvs = append(kvs, toKV(versionedKV.Namespace, versionedKV.Key, &versionedKV.VersionedValue))
+	}
+}
+
+func toKV(ns, key string, vv *statedb.VersionedValue) *KV {
+	if vv == nil {
+		return nil
+	}
+	return &KV{
+		Namespace: ns,
+		Key:       key,
+		Value:     vv.Value,
+		Metadata:  vv.Metadata,
+		Version:   heightToBytes(vv.Version),
+		IsDelete:  vv.IsDelete(),
+	}
+}
+
+func fromKV(kv *KV) (*statedb.VersionedValue, error) {
+	ver, err := heightFromBytes(kv.Version)
+	if err != nil {
+		return nil, err
+	}
+	vv := &statedb.VersionedValue{Value: kv.Value, Metadata: kv.Metadata, Version: ver}
+	// protobuf always makes an empty byte array as nil
+	if !kv.IsDelete && vv.Value == nil {
+		vv.Value = []byte{}
+	}
+	if kv.IsDelete {
+		vv.Value = nil
+	}
+	return vv, nil
+}
+
+func heightToBytes(height *version.Height) []byte {
+	if height == nil {
+		return nil
+	}
+	return height.ToBytes()
+}
+
+func heightFromBytes(b []byte) (*version.Height, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	height, _, err := version.NewHeightFromBytes(b)
+	return height, err
+}
@@ -0,0 +1,524 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateremote
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var logger = flogging.MustGetLogger("stateremote")
+
+const (
+	// StateDatabaseName is the name under which the remote state database is registered with the ledger
+	StateDatabaseName = "Remote"
+
+	defaultDialTimeout = 3 * time.Second
+	// rangeQueryBatchSize is the maximum number of results fetched from the remote state store in
+	// a single call while iterating over the results of a range query
+	rangeQueryBatchSize = int32(1000)
+)
+
+func init() {
+	if err := statedb.RegisterVersionedDBProviderFactory(StateDatabaseName, newVersionedDBProviderFromPluginConfig); err != nil {
+		panic(err)
+	}
+}
+
+// Config is the configuration for connecting to the remote state store
+type Config struct {
+	// Address is the host:port of the remote state store
+	Address string `mapstructure:"address"`
+	// DialTimeout is the timeout for establishing the connection to the remote state store
+	DialTimeout time.Duration `mapstructure:"dialTimeout"`
+	// TLSEnabled enables TLS for the connection to the remote state store
+	TLSEnabled bool `mapstructure:"tlsEnabled"`
+	// TLSRootCertFile is the PEM-encoded root certificate that is used to verify the remote state store
+	TLSRootCertFile string `mapstructure:"tlsRootCertFile"`
+}
+
+// VersionedDBProvider implements interface statedb.VersionedDBProvider for the state databases that
+// are maintained in a remote state store, accessed via the StateDB gRPC service
+type VersionedDBProvider struct {
+	conn   *grpc.ClientConn
+	client StateDBClient
+}
+
+// NewVersionedDBProvider connects to the remote state store and instantiates VersionedDBProvider
+func NewVersionedDBProvider(conf *Config) (*VersionedDBProvider, error) {
+	if conf.Address == "" {
+		return nil, errors.New("address of the remote state store is not specified")
+	}
+	clientConfig := comm.ClientConfig{
+		KaOpts:  comm.DefaultKeepaliveOptions,
+		Timeout: conf.DialTimeout,
+	}
+	if clientConfig.Timeout == 0 {
+		clientConfig.Timeout = defaultDialTimeout
+	}
+	if conf.TLSEnabled {
+		rootCert, err := ioutil.ReadFile(conf.TLSRootCertFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error while reading the TLS root certificate file [%s] of the remote state store", conf.TLSRootCertFile)
+		}
+		clientConfig.SecOpts = comm.SecureOptions{
+			UseTLS:        true,
+			ServerRootCAs: [][]byte{rootCert},
+		}
+	}
+	grpcClient, err := comm.NewGRPCClient(clientConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while creating the client for the remote state store")
+	}
+	conn, err := grpcClient.NewConnection(conf.Address)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while connecting to the remote state store at [%s]", conf.Address)
+	}
+	logger.Infof("Connected to the remote state store at [%s]", conf.Address)
+	return newVersionedDBProvider(conn), nil
+}
+
+func newVersionedDBProvider(conn *grpc.ClientConn) *VersionedDBProvider {
+	return &VersionedDBProvider{
+		conn:   conn,
+		client: NewStateDBClient(conn),
+	}
+}
+
+func newVersionedDBProviderFromPluginConfig(conf *statedb.VersionedDBProviderConfig) (statedb.VersionedDBProvider, error) {
+	remoteConf := &Config{}
+	decoder, err := mapstructure.NewDecoder(
+		&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           remoteConf,
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while constructing the decoder for the configuration of the remote state store")
+	}
+	if err := decoder.Decode(conf.PluginConfig); err != nil {
+		return nil, errors.Wrap(err, "error while decoding the configuration of the remote state store")
+	}
+	return NewVersionedDBProvider(remoteConf)
+}
+
+// GetDBHandle gets the handle to a named database. The namespace provider is not used as the namespaces
+// are managed by the remote state store
+func (p *VersionedDBProvider) GetDBHandle(dbName string, namespaceProvider statedb.NamespaceProvider) (statedb.VersionedDB, error) {
+	info, err := p.client.GetDBInfo(context.Background(), &DBRequest{DbName: dbName})
+	if err != nil {
+		return nil, fromRPCError(err)
+	}
+	return &versionedDB{
+		client:            p.client,
+		dbName:            dbName,
+		bytesKeySupported: info.BytesKeySupported,
+	}, nil
+}
+
+// ImportFromSnapshot streams the data returned by the FullScanIterator to the remote state store
+func (p *VersionedDBProvider) ImportFromSnapshot(
+	dbName string,
+	savepoint *version.Height,
+	itr statedb.FullScanIterator,
+	dbValueFormat byte,
+) error {
+	stream, err := p.client.ImportFromSnapshot(context.Background())
+	if err != nil {
+		return fromRPCError(err)
+	}
+	if err := stream.Send(
+		&ImportFromSnapshotRequest{
+			Content: &ImportFromSnapshotRequest_Header{
+				Header: &ImportHeader{
+					DbName:      dbName,
+					Savepoint:   heightToBytes(savepoint),
+					ValueFormat: uint32(dbValueFormat),
+				},
+			},
+		},
+	); err != nil && err != io.EOF {
+		return fromRPCError(err)
+	}
+
+	for {
+		compositeKey, value, err := itr.Next()
+		if err != nil {
+			stream.CloseSend()
+			return err
+		}
+		if compositeKey == nil {
+			break
+		}
+		err = stream.Send(
+			&ImportFromSnapshotRequest{
+				Content: &ImportFromSnapshotRequest_Kv{
+					Kv: &FullScanKV{Namespace: compositeKey.Namespace, Key: compositeKey.Key, Value: value},
+				},
+			},
+		)
+		if err == io.EOF {
+			// the server has terminated the stream, the actual error is returned by CloseAndRecv
+			break
+		}
+		if err != nil {
+			return fromRPCError(err)
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	return fromRPCError(err)
+}
+
+// Close closes the connection to the remote state store
+func (p *VersionedDBProvider) Close() {
+	if err := p.conn.Close(); err != nil {
+		logger.Warningf("error while closing the connection to the remote state store: %s", err)
+	}
+}
+
+// versionedDB implements VersionedDB interface
+type versionedDB struct {
+	client            StateDBClient
+	dbName            string
+	bytesKeySupported bool
+}
+
+// Open implements method in VersionedDB interface
+func (vdb *versionedDB) Open() error {
+	// do nothing because shared db is used
+	return nil
+}
+
+// Close implements method in VersionedDB interface
+func (vdb *versionedDB) Close() {
+	// do nothing because shared db is used
+}
+
+// ValidateKeyValue implements method in VersionedDB interface
+func (vdb *versionedDB) ValidateKeyValue(key string, value []byte) error {
+	_, err := vdb.client.ValidateKeyValue(
+		context.Background(),
+		&ValidateKeyValueRequest{DbName: vdb.dbName, Key: key, Value: value},
+	)
+	return fromRPCError(err)
+}
+
+// BytesKeySupported implements method in VersionedDB interface
+func (vdb *versionedDB) BytesKeySupported() bool {
+	return vdb.bytesKeySupported
+}
+
+// GetState implements method in VersionedDB interface
+func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
+	resp, err := vdb.client.GetState(
+		context.Background(),
+		&GetStateRequest{DbName: vdb.dbName, Namespace: namespace, Key: key},
+	)
+	if err != nil {
+		return nil, fromRPCError(err)
+	}
+	if resp.Kv == nil {
+		return nil, nil
+	}
+	return fromKV(resp.Kv)
+}
+
+// GetVersion implements method in VersionedDB interface
+func (vdb *versionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
+	vv, err := vdb.GetState(namespace, key)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Version, nil
+}
+
+// GetStateMultipleKeys implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
+	resp, err := vdb.client.GetStateMultipleKeys(
+		context.Background(),
+		&GetStateMultipleKeysRequest{DbName: vdb.dbName, Namespace: namespace, Keys: keys},
+	)
+	if err != nil {
+		return nil, fromRPCError(err)
+	}
+	if len(resp.Values) != len(keys) {
+		return nil, errors.Errorf("remote state store returned [%d] values for [%d] keys", len(resp.Values), len(keys))
+	}
+	vals := make([]*statedb.VersionedValue, len(keys))
+	for i, val := range resp.Values {
+		if val.Kv == nil {
+			continue
+		}
+		if vals[i], err = fromKV(val.Kv); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// GetStateRangeScanIterator implements method in VersionedDB interface
+// startKey is inclusive
+// endKey is exclusive
+func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0)
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	itr := &rangeScanner{
+		vdb:       vdb,
+		namespace: namespace,
+		nextKey:   startKey,
+		endKey:    endKey,
+		pageSize:  pageSize,
+	}
+	if err := itr.fetch(); err != nil {
+		return nil, err
+	}
+	return itr, nil
+}
+
+// ExecuteQuery implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	return vdb.executeQuery(namespace, query, "", 0)
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	return vdb.executeQuery(namespace, query, bookmark, pageSize)
+}
+
+func (vdb *versionedDB) executeQuery(namespace, query, bookmark string, pageSize int32) (*queryScanner, error) {
+	resp, err := vdb.client.ExecuteQuery(
+		context.Background(),
+		&ExecuteQueryRequest{
+			DbName:    vdb.dbName,
+			Namespace: namespace,
+			Query:     query,
+			Bookmark:  bookmark,
+			PageSize:  pageSize,
+		},
+	)
+	if err != nil {
+		return nil, fromRPCError(err)
+	}
+	return &queryScanner{kvs: resp.Kvs, bookmark: resp.Bookmark}, nil
+}
+
+// ApplyUpdates implements method in VersionedDB interface
+func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	req := &ApplyUpdatesRequest{
+		DbName: vdb.dbName,
+		Height: heightToBytes(height),
+	}
+	for _, ns := range batch.GetUpdatedNamespaces() {
+		for key, vv := range batch.GetUpdates(ns) {
+			req.Updates = append(req.Updates, toKV(ns, key, vv))
+		}
+	}
+	_, err := vdb.client.ApplyUpdates(context.Background(), req)
+	return fromRPCError(err)
+}
+
+// GetLatestSavePoint implements method in VersionedDB interface
+func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
+	resp, err := vdb.client.GetLatestSavePoint(context.Background(), &DBRequest{DbName: vdb.dbName})
+	if err != nil {
+		return nil, fromRPCError(err)
+	}
+	return heightFromBytes(resp.Height)
+}
+
+// GetFullScanIterator implements method in VersionedDB interface. The namespaces to skip are filtered
+// on the peer side as the remote state store streams the entire data
+func (vdb *versionedDB) GetFullScanIterator(skipNamespace func(string) bool) (statedb.FullScanIterator, byte, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := vdb.client.FullScan(ctx, &DBRequest{DbName: vdb.dbName})
+	if err != nil {
+		cancel()
+		return nil, byte(0), fromRPCError(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, byte(0), fromRPCError(err)
+	}
+	valueFormat, ok := resp.Content.(*FullScanResponse_ValueFormat)
+	if !ok {
+		cancel()
+		return nil, byte(0), errors.New("the first message of the full scan stream does not carry the value format")
+	}
+	return &fullScanner{
+			stream: stream,
+			cancel: cancel,
+			toSkip: skipNamespace,
+		},
+		byte(valueFormat.ValueFormat),
+		nil
+}
+
+// rangeScanner iterates over the results of a range query by fetching them from the remote state store in batches
+type rangeScanner struct {
+	vdb                  *versionedDB
+	namespace            string
+	nextKey              string
+	endKey               string
+	pageSize             int32
+	totalRecordsReturned int32
+	buffer               []*KV
+	exhausted            bool
+}
+
+func (s *rangeScanner) fetch() error {
+	limit := rangeQueryBatchSize
+	if s.pageSize > 0 && s.pageSize-s.totalRecordsReturned < limit {
+		limit = s.pageSize - s.totalRecordsReturned
+	}
+	resp, err := s.vdb.client.GetStateRange(
+		context.Background(),
+		&GetStateRangeRequest{
+			DbName:    s.vdb.dbName,
+			Namespace: s.namespace,
+			StartKey:  s.nextKey,
+			EndKey:    s.endKey,
+			Limit:     limit,
+		},
+	)
+	if err != nil {
+		return fromRPCError(err)
+	}
+	s.buffer = resp.Kvs
+	s.nextKey = resp.Bookmark
+	s.exhausted = resp.Bookmark == ""
+	return nil
+}
+
+func (s *rangeScanner) Next() (statedb.QueryResult, error) {
+	if s.pageSize > 0 && s.totalRecordsReturned >= s.pageSize {
+		return nil, nil
+	}
+	if len(s.buffer) == 0 {
+		if s.exhausted {
+			return nil, nil
+		}
+		if err := s.fetch(); err != nil {
+			return nil, err
+		}
+		if len(s.buffer) == 0 {
+			return nil, nil
+		}
+	}
+	kv := s.buffer[0]
+	s.buffer = s.buffer[1:]
+	vv, err := fromKV(kv)
+	if err != nil {
+		return nil, err
+	}
+	s.totalRecordsReturned++
+	return &statedb.VersionedKV{
+		CompositeKey:   statedb.CompositeKey{Namespace: kv.Namespace, Key: kv.Key},
+		VersionedValue: *vv,
+	}, nil
+}
+
+func (s *rangeScanner) Close() {
+}
+
+func (s *rangeScanner) GetBookmarkAndClose() string {
+	if len(s.buffer) > 0 {
+		return s.buffer[0].Key
+	}
+	if s.exhausted {
+		return ""
+	}
+	return s.nextKey
+}
+
+// queryScanner iterates over the results of a query that are fetched from the remote state store in one go
+type queryScanner struct {
+	kvs      []*KV
+	bookmark string
+}
+
+func (s *queryScanner) Next() (statedb.QueryResult, error) {
+	if len(s.kvs) == 0 {
+		return nil, nil
+	}
+	kv := s.kvs[0]
+	s.kvs = s.kvs[1:]
+	vv, err := fromKV(kv)
+	if err != nil {
+		return nil, err
+	}
+	return &statedb.VersionedKV{
+		CompositeKey:   statedb.CompositeKey{Namespace: kv.Namespace, Key: kv.Key},
+		VersionedValue: *vv,
+	}, nil
+}
+
+func (s *queryScanner) Close() {
+}
+
+func (s *queryScanner) GetBookmarkAndClose() string {
+	return s.bookmark
+}
+
+// fullScanner implements statedb.FullScanIterator over the data streamed by the remote state store
+type fullScanner struct {
+	stream StateDB_FullScanClient
+	cancel context.CancelFunc
+	toSkip func(namespace string) bool
+}
+
+func (s *fullScanner) Next() (*statedb.CompositeKey, []byte, error) {
+	for {
+		resp, err := s.stream.Recv()
+		if err == io.EOF {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, fromRPCError(err)
+		}
+		kv := resp.GetKv()
+		if kv == nil {
+			return nil, nil, errors.New("unexpected message in the full scan stream, expected data")
+		}
+		if s.toSkip(kv.Namespace) {
+			continue
+		}
+		return &statedb.CompositeKey{Namespace: kv.Namespace, Key: kv.Key}, kv.Value, nil
+	}
+}
+
+func (s *fullScanner) Close() {
+	s.cancel()
+}
+
+// fromRPCError converts the error returned by a gRPC call to an error that carries the message of the
+// error returned by the remote state store, so that the errors are the same as of a local state database
+func fromRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return errors.New(st.Message())
+}
@@ -0,0 +1,1548 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: stateremote.proto
+
+package stateremote
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// KV carries a key of a namespace along with its versioned value. A delete of the key is
+// indicated by is_delete, so that an empty value can be distinguished from a delete
+type KV struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key       string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value     []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Metadata  []byte `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The bytes of version.Height
+	Version              []byte   `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	IsDelete             bool     `protobuf:"varint,6,opt,name=is_delete,json=isDelete,proto3" json:"is_delete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KV) Reset()         { *m = KV{} }
+func (m *KV) String() string { return proto.CompactTextString(m) }
+func (*KV) ProtoMessage()    {}
+func (*KV) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{0}
+}
+
+func (m *KV) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KV.Unmarshal(m, b)
+}
+func (m *KV) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KV.Marshal(b, m, deterministic)
+}
+func (m *KV) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KV.Merge(m, src)
+}
+func (m *KV) XXX_Size() int {
+	return xxx_messageInfo_KV.Size(m)
+}
+func (m *KV) XXX_DiscardUnknown() {
+	xxx_messageInfo_KV.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KV proto.InternalMessageInfo
+
+func (m *KV) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *KV) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KV) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *KV) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *KV) GetVersion() []byte {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
+
+func (m *KV) GetIsDelete() bool {
+	if m != nil {
+		return m.IsDelete
+	}
+	return false
+}
+
+// DBRequest identifies the state database of a channel
+type DBRequest struct {
+	DbName               string   `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DBRequest) Reset()         { *m = DBRequest{} }
+func (m *DBRequest) String() string { return proto.CompactTextString(m) }
+func (*DBRequest) ProtoMessage()    {}
+func (*DBRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{1}
+}
+
+func (m *DBRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DBRequest.Unmarshal(m, b)
+}
+func (m *DBRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DBRequest.Marshal(b, m, deterministic)
+}
+func (m *DBRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DBRequest.Merge(m, src)
+}
+func (m *DBRequest) XXX_Size() int {
+	return xxx_messageInfo_DBRequest.Size(m)
+}
+func (m *DBRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DBRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DBRequest proto.InternalMessageInfo
+
+func (m *DBRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+type DBInfo struct {
+	BytesKeySupported    bool     `protobuf:"varint,1,opt,name=bytes_key_supported,json=bytesKeySupported,proto3" json:"bytes_key_supported,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DBInfo) Reset()         { *m = DBInfo{} }
+func (m *DBInfo) String() string { return proto.CompactTextString(m) }
+func (*DBInfo) ProtoMessage()    {}
+func (*DBInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{2}
+}
+
+func (m *DBInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DBInfo.Unmarshal(m, b)
+}
+func (m *DBInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DBInfo.Marshal(b, m, deterministic)
+}
+func (m *DBInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DBInfo.Merge(m, src)
+}
+func (m *DBInfo) XXX_Size() int {
+	return xxx_messageInfo_DBInfo.Size(m)
+}
+func (m *DBInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_DBInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DBInfo proto.InternalMessageInfo
+
+func (m *DBInfo) GetBytesKeySupported() bool {
+	if m != nil {
+		return m.BytesKeySupported
+	}
+	return false
+}
+
+type GetStateRequest struct {
+	DbName               string   `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key                  string   `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateRequest) Reset()         { *m = GetStateRequest{} }
+func (m *GetStateRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateRequest) ProtoMessage()    {}
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{3}
+}
+
+func (m *GetStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateRequest.Unmarshal(m, b)
+}
+func (m *GetStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateRequest.Merge(m, src)
+}
+func (m *GetStateRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateRequest.Size(m)
+}
+func (m *GetStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateRequest proto.InternalMessageInfo
+
+func (m *GetStateRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *GetStateRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *GetStateRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type GetStateResponse struct {
+	// Not set if the key does not exist
+	Kv                   *KV      `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateResponse) Reset()         { *m = GetStateResponse{} }
+func (m *GetStateResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStateResponse) ProtoMessage()    {}
+func (*GetStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{4}
+}
+
+func (m *GetStateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateResponse.Unmarshal(m, b)
+}
+func (m *GetStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateResponse.Merge(m, src)
+}
+func (m *GetStateResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStateResponse.Size(m)
+}
+func (m *GetStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateResponse proto.InternalMessageInfo
+
+func (m *GetStateResponse) GetKv() *KV {
+	if m != nil {
+		return m.Kv
+	}
+	return nil
+}
+
+type GetStateMultipleKeysRequest struct {
+	DbName               string   `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Keys                 []string `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateMultipleKeysRequest) Reset()         { *m = GetStateMultipleKeysRequest{} }
+func (m *GetStateMultipleKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateMultipleKeysRequest) ProtoMessage()    {}
+func (*GetStateMultipleKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{5}
+}
+
+func (m *GetStateMultipleKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateMultipleKeysRequest.Unmarshal(m, b)
+}
+func (m *GetStateMultipleKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateMultipleKeysRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStateMultipleKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateMultipleKeysRequest.Merge(m, src)
+}
+func (m *GetStateMultipleKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateMultipleKeysRequest.Size(m)
+}
+func (m *GetStateMultipleKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateMultipleKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateMultipleKeysRequest proto.InternalMessageInfo
+
+func (m *GetStateMultipleKeysRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *GetStateMultipleKeysRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *GetStateMultipleKeysRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type GetStateMultipleKeysResponse struct {
+	// In the order of the keys in the request
+	Values               []*GetStateResponse `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *GetStateMultipleKeysResponse) Reset()         { *m = GetStateMultipleKeysResponse{} }
+func (m *GetStateMultipleKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStateMultipleKeysResponse) ProtoMessage()    {}
+func (*GetStateMultipleKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{6}
+}
+
+func (m *GetStateMultipleKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateMultipleKeysResponse.Unmarshal(m, b)
+}
+func (m *GetStateMultipleKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateMultipleKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStateMultipleKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateMultipleKeysResponse.Merge(m, src)
+}
+func (m *GetStateMultipleKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStateMultipleKeysResponse.Size(m)
+}
+func (m *GetStateMultipleKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateMultipleKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateMultipleKeysResponse proto.InternalMessageInfo
+
+func (m *GetStateMultipleKeysResponse) GetValues() []*GetStateResponse {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type GetStateRangeRequest struct {
+	DbName               string   `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	StartKey             string   `protobuf:"bytes,3,opt,name=start_key,json=startKey,proto3" json:"start_key,omitempty"`
+	EndKey               string   `protobuf:"bytes,4,opt,name=end_key,json=endKey,proto3" json:"end_key,omitempty"`
+	Limit                int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateRangeRequest) Reset()         { *m = GetStateRangeRequest{} }
+func (m *GetStateRangeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateRangeRequest) ProtoMessage()    {}
+func (*GetStateRangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{7}
+}
+
+func (m *GetStateRangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateRangeRequest.Unmarshal(m, b)
+}
+func (m *GetStateRangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateRangeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStateRangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateRangeRequest.Merge(m, src)
+}
+func (m *GetStateRangeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateRangeRequest.Size(m)
+}
+func (m *GetStateRangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateRangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateRangeRequest proto.InternalMessageInfo
+
+func (m *GetStateRangeRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *GetStateRangeRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *GetStateRangeRequest) GetStartKey() string {
+	if m != nil {
+		return m.StartKey
+	}
+	return ""
+}
+
+func (m *GetStateRangeRequest) GetEndKey() string {
+	if m != nil {
+		return m.EndKey
+	}
+	return ""
+}
+
+func (m *GetStateRangeRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ExecuteQueryRequest struct {
+	DbName    string `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Query     string `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	Bookmark  string `protobuf:"bytes,4,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	// Zero denotes a query without pagination
+	PageSize             int32    `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecuteQueryRequest) Reset()         { *m = ExecuteQueryRequest{} }
+func (m *ExecuteQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecuteQueryRequest) ProtoMessage()    {}
+func (*ExecuteQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{8}
+}
+
+func (m *ExecuteQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecuteQueryRequest.Unmarshal(m, b)
+}
+func (m *ExecuteQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecuteQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecuteQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecuteQueryRequest.Merge(m, src)
+}
+func (m *ExecuteQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecuteQueryRequest.Size(m)
+}
+func (m *ExecuteQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecuteQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecuteQueryRequest proto.InternalMessageInfo
+
+func (m *ExecuteQueryRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *ExecuteQueryRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ExecuteQueryRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *ExecuteQueryRequest) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
+func (m *ExecuteQueryRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+type QueryResponse struct {
+	Kvs []*KV `protobuf:"bytes,1,rep,name=kvs,proto3" json:"kvs,omitempty"`
+	// The bookmark for the next page, if any
+	Bookmark             string   `protobuf:"bytes,2,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
+func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryResponse) ProtoMessage()    {}
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{9}
+}
+
+func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
+}
+func (m *QueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryResponse.Marshal(b, m, deterministic)
+}
+func (m *QueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResponse.Merge(m, src)
+}
+func (m *QueryResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryResponse.Size(m)
+}
+func (m *QueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResponse proto.InternalMessageInfo
+
+func (m *QueryResponse) GetKvs() []*KV {
+	if m != nil {
+		return m.Kvs
+	}
+	return nil
+}
+
+func (m *QueryResponse) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
+type ApplyUpdatesRequest struct {
+	DbName  string `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	Updates []*KV  `protobuf:"bytes,2,rep,name=updates,proto3" json:"updates,omitempty"`
+	// The bytes of version.Height, not set if the savepoint is not to be updated
+	Height               []byte   `protobuf:"bytes,3,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApplyUpdatesRequest) Reset()         { *m = ApplyUpdatesRequest{} }
+func (m *ApplyUpdatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ApplyUpdatesRequest) ProtoMessage()    {}
+func (*ApplyUpdatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{10}
+}
+
+func (m *ApplyUpdatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplyUpdatesRequest.Unmarshal(m, b)
+}
+func (m *ApplyUpdatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplyUpdatesRequest.Marshal(b, m, deterministic)
+}
+func (m *ApplyUpdatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplyUpdatesRequest.Merge(m, src)
+}
+func (m *ApplyUpdatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ApplyUpdatesRequest.Size(m)
+}
+func (m *ApplyUpdatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplyUpdatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplyUpdatesRequest proto.InternalMessageInfo
+
+func (m *ApplyUpdatesRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *ApplyUpdatesRequest) GetUpdates() []*KV {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+func (m *ApplyUpdatesRequest) GetHeight() []byte {
+	if m != nil {
+		return m.Height
+	}
+	return nil
+}
+
+type SavePoint struct {
+	// The bytes of version.Height, not set if there is no savepoint
+	Height               []byte   `protobuf:"bytes,1,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SavePoint) Reset()         { *m = SavePoint{} }
+func (m *SavePoint) String() string { return proto.CompactTextString(m) }
+func (*SavePoint) ProtoMessage()    {}
+func (*SavePoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{11}
+}
+
+func (m *SavePoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SavePoint.Unmarshal(m, b)
+}
+func (m *SavePoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SavePoint.Marshal(b, m, deterministic)
+}
+func (m *SavePoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SavePoint.Merge(m, src)
+}
+func (m *SavePoint) XXX_Size() int {
+	return xxx_messageInfo_SavePoint.Size(m)
+}
+func (m *SavePoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_SavePoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SavePoint proto.InternalMessageInfo
+
+func (m *SavePoint) GetHeight() []byte {
+	if m != nil {
+		return m.Height
+	}
+	return nil
+}
+
+type ValidateKeyValueRequest struct {
+	DbName               string   `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateKeyValueRequest) Reset()         { *m = ValidateKeyValueRequest{} }
+func (m *ValidateKeyValueRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateKeyValueRequest) ProtoMessage()    {}
+func (*ValidateKeyValueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{12}
+}
+
+func (m *ValidateKeyValueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateKeyValueRequest.Unmarshal(m, b)
+}
+func (m *ValidateKeyValueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateKeyValueRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateKeyValueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateKeyValueRequest.Merge(m, src)
+}
+func (m *ValidateKeyValueRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateKeyValueRequest.Size(m)
+}
+func (m *ValidateKeyValueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateKeyValueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateKeyValueRequest proto.InternalMessageInfo
+
+func (m *ValidateKeyValueRequest) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *ValidateKeyValueRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ValidateKeyValueRequest) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// FullScanKV carries a key and the bytes of the versioned value in the format of the state database
+type FullScanKV struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FullScanKV) Reset()         { *m = FullScanKV{} }
+func (m *FullScanKV) String() string { return proto.CompactTextString(m) }
+func (*FullScanKV) ProtoMessage()    {}
+func (*FullScanKV) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{13}
+}
+
+func (m *FullScanKV) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FullScanKV.Unmarshal(m, b)
+}
+func (m *FullScanKV) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FullScanKV.Marshal(b, m, deterministic)
+}
+func (m *FullScanKV) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FullScanKV.Merge(m, src)
+}
+func (m *FullScanKV) XXX_Size() int {
+	return xxx_messageInfo_FullScanKV.Size(m)
+}
+func (m *FullScanKV) XXX_DiscardUnknown() {
+	xxx_messageInfo_FullScanKV.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FullScanKV proto.InternalMessageInfo
+
+func (m *FullScanKV) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *FullScanKV) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *FullScanKV) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// FullScanResponse is streamed by the FullScan rpc. The first message carries the value format and
+// the subsequent messages carry the data
+type FullScanResponse struct {
+	// Types that are valid to be assigned to Content:
+	//	*FullScanResponse_ValueFormat
+	//	*FullScanResponse_Kv
+	Content              isFullScanResponse_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *FullScanResponse) Reset()         { *m = FullScanResponse{} }
+func (m *FullScanResponse) String() string { return proto.CompactTextString(m) }
+func (*FullScanResponse) ProtoMessage()    {}
+func (*FullScanResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{14}
+}
+
+func (m *FullScanResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FullScanResponse.Unmarshal(m, b)
+}
+func (m *FullScanResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FullScanResponse.Marshal(b, m, deterministic)
+}
+func (m *FullScanResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FullScanResponse.Merge(m, src)
+}
+func (m *FullScanResponse) XXX_Size() int {
+	return xxx_messageInfo_FullScanResponse.Size(m)
+}
+func (m *FullScanResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FullScanResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FullScanResponse proto.InternalMessageInfo
+
+type isFullScanResponse_Content interface {
+	isFullScanResponse_Content()
+}
+
+type FullScanResponse_ValueFormat struct {
+	ValueFormat uint32 `protobuf:"varint,1,opt,name=value_format,json=valueFormat,proto3,oneof"`
+}
+
+type FullScanResponse_Kv struct {
+	Kv *FullScanKV `protobuf:"bytes,2,opt,name=kv,proto3,oneof"`
+}
+
+func (*FullScanResponse_ValueFormat) isFullScanResponse_Content() {}
+
+func (*FullScanResponse_Kv) isFullScanResponse_Content() {}
+
+func (m *FullScanResponse) GetContent() isFullScanResponse_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *FullScanResponse) GetValueFormat() uint32 {
+	if x, ok := m.GetContent().(*FullScanResponse_ValueFormat); ok {
+		return x.ValueFormat
+	}
+	return 0
+}
+
+func (m *FullScanResponse) GetKv() *FullScanKV {
+	if x, ok := m.GetContent().(*FullScanResponse_Kv); ok {
+		return x.Kv
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*FullScanResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*FullScanResponse_ValueFormat)(nil),
+		(*FullScanResponse_Kv)(nil),
+	}
+}
+
+type ImportHeader struct {
+	DbName string `protobuf:"bytes,1,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	// The bytes of version.Height
+	Savepoint            []byte   `protobuf:"bytes,2,opt,name=savepoint,proto3" json:"savepoint,omitempty"`
+	ValueFormat          uint32   `protobuf:"varint,3,opt,name=value_format,json=valueFormat,proto3" json:"value_format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportHeader) Reset()         { *m = ImportHeader{} }
+func (m *ImportHeader) String() string { return proto.CompactTextString(m) }
+func (*ImportHeader) ProtoMessage()    {}
+func (*ImportHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{15}
+}
+
+func (m *ImportHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportHeader.Unmarshal(m, b)
+}
+func (m *ImportHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportHeader.Marshal(b, m, deterministic)
+}
+func (m *ImportHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportHeader.Merge(m, src)
+}
+func (m *ImportHeader) XXX_Size() int {
+	return xxx_messageInfo_ImportHeader.Size(m)
+}
+func (m *ImportHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportHeader proto.InternalMessageInfo
+
+func (m *ImportHeader) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *ImportHeader) GetSavepoint() []byte {
+	if m != nil {
+		return m.Savepoint
+	}
+	return nil
+}
+
+func (m *ImportHeader) GetValueFormat() uint32 {
+	if m != nil {
+		return m.ValueFormat
+	}
+	return 0
+}
+
+// ImportFromSnapshotRequest is streamed to the ImportFromSnapshot rpc. The first message carries the header
+// and the subsequent messages carry the data
+type ImportFromSnapshotRequest struct {
+	// Types that are valid to be assigned to Content:
+	//	*ImportFromSnapshotRequest_Header
+	//	*ImportFromSnapshotRequest_Kv
+	Content              isImportFromSnapshotRequest_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}                            `json:"-"`
+	XXX_unrecognized     []byte                              `json:"-"`
+	XXX_sizecache        int32                               `json:"-"`
+}
+
+func (m *ImportFromSnapshotRequest) Reset()         { *m = ImportFromSnapshotRequest{} }
+func (m *ImportFromSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportFromSnapshotRequest) ProtoMessage()    {}
+func (*ImportFromSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f01884abf2d867f, []int{16}
+}
+
+func (m *ImportFromSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportFromSnapshotRequest.Unmarshal(m, b)
+}
+func (m *ImportFromSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportFromSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportFromSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportFromSnapshotRequest.Merge(m, src)
+}
+func (m *ImportFromSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportFromSnapshotRequest.Size(m)
+}
+func (m *ImportFromSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportFromSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportFromSnapshotRequest proto.InternalMessageInfo
+
+type isImportFromSnapshotRequest_Content interface {
+	isImportFromSnapshotRequest_Content()
+}
+
+type ImportFromSnapshotRequest_Header struct {
+	Header *ImportHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type ImportFromSnapshotRequest_Kv struct {
+	Kv *FullScanKV `protobuf:"bytes,2,opt,name=kv,proto3,oneof"`
+}
+
+func (*ImportFromSnapshotRequest_Header) isImportFromSnapshotRequest_Content() {}
+
+func (*ImportFromSnapshotRequest_Kv) isImportFromSnapshotRequest_Content() {}
+
+func (m *ImportFromSnapshotRequest) GetContent() isImportFromSnapshotRequest_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *ImportFromSnapshotRequest) GetHeader() *ImportHeader {
+	if x, ok := m.GetContent().(*ImportFromSnapshotRequest_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (m *ImportFromSnapshotRequest) GetKv() *FullScanKV {
+	if x, ok := m.GetContent().(*ImportFromSnapshotRequest_Kv); ok {
+		return x.Kv
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ImportFromSnapshotRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ImportFromSnapshotRequest_Header)(nil),
+		(*ImportFromSnapshotRequest_Kv)(nil),
+	}
+}
+
+func init() {
+	proto.RegisterType((*KV)(nil), "stateremote.KV")
+	proto.RegisterType((*DBRequest)(nil), "stateremote.DBRequest")
+	proto.RegisterType((*DBInfo)(nil), "stateremote.DBInfo")
+	proto.RegisterType((*GetStateRequest)(nil), "stateremote.GetStateRequest")
+	proto.RegisterType((*GetStateResponse)(nil), "stateremote.GetStateResponse")
+	proto.RegisterType((*GetStateMultipleKeysRequest)(nil), "stateremote.GetStateMultipleKeysRequest")
+	proto.RegisterType((*GetStateMultipleKeysResponse)(nil), "stateremote.GetStateMultipleKeysResponse")
+	proto.RegisterType((*GetStateRangeRequest)(nil), "stateremote.GetStateRangeRequest")
+	proto.RegisterType((*ExecuteQueryRequest)(nil), "stateremote.ExecuteQueryRequest")
+	proto.RegisterType((*QueryResponse)(nil), "stateremote.QueryResponse")
+	proto.RegisterType((*ApplyUpdatesRequest)(nil), "stateremote.ApplyUpdatesRequest")
+	proto.RegisterType((*SavePoint)(nil), "stateremote.SavePoint")
+	proto.RegisterType((*ValidateKeyValueRequest)(nil), "stateremote.ValidateKeyValueRequest")
+	proto.RegisterType((*FullScanKV)(nil), "stateremote.FullScanKV")
+	proto.RegisterType((*FullScanResponse)(nil), "stateremote.FullScanResponse")
+	proto.RegisterType((*ImportHeader)(nil), "stateremote.ImportHeader")
+	proto.RegisterType((*ImportFromSnapshotRequest)(nil), "stateremote.ImportFromSnapshotRequest")
+}
+
+func init() { proto.RegisterFile("stateremote.proto", fileDescriptor_8f01884abf2d867f) }
+
+var fileDescriptor_8f01884abf2d867f = []byte{
+	// 943 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x6d, 0x6f, 0xe2, 0x46,
+	0x10, 0xc6, 0x70, 0xe1, 0x65, 0x42, 0x74, 0xb9, 0x4d, 0x94, 0xf8, 0x48, 0x4e, 0x25, 0x7b, 0xa7,
+	0x8a, 0x7c, 0x81, 0x2a, 0x51, 0xa5, 0xaa, 0xdf, 0x8a, 0xf2, 0x5a, 0x7a, 0xe9, 0xd5, 0xe8, 0xa2,
+	0x2a, 0x5f, 0xd0, 0x82, 0x27, 0x60, 0x61, 0x7b, 0x1d, 0x7b, 0x8d, 0xce, 0xf7, 0x03, 0xfa, 0x1f,
+	0xaa, 0xaa, 0x7f, 0xa0, 0xbf, 0xb2, 0xda, 0xb5, 0x0d, 0x98, 0x33, 0xc9, 0xa9, 0xe9, 0x37, 0xcf,
+	0xcc, 0xc3, 0xcc, 0xb3, 0xb3, 0x33, 0x0f, 0x0b, 0xaf, 0x02, 0xc1, 0x04, 0xfa, 0xe8, 0x70, 0x81,
+	0x6d, 0xcf, 0xe7, 0x82, 0x93, 0xcd, 0x25, 0x57, 0xe3, 0x60, 0xcc, 0xf9, 0xd8, 0xc6, 0x8e, 0x0a,
+	0x0d, 0xc3, 0xfb, 0x0e, 0x3a, 0x9e, 0x88, 0x62, 0x24, 0xfd, 0x5b, 0x83, 0x62, 0xef, 0x96, 0x1c,
+	0x42, 0xcd, 0x65, 0x0e, 0x06, 0x1e, 0x1b, 0xa1, 0xae, 0x35, 0xb5, 0x56, 0xcd, 0x58, 0x38, 0xc8,
+	0x36, 0x94, 0xa6, 0x18, 0xe9, 0x45, 0xe5, 0x97, 0x9f, 0x64, 0x17, 0x36, 0x66, 0xcc, 0x0e, 0x51,
+	0x2f, 0x35, 0xb5, 0x56, 0xdd, 0x88, 0x0d, 0xd2, 0x80, 0xaa, 0x83, 0x82, 0x99, 0x4c, 0x30, 0xfd,
+	0x85, 0x0a, 0xcc, 0x6d, 0xa2, 0x43, 0x65, 0x86, 0x7e, 0x60, 0x71, 0x57, 0xdf, 0x50, 0xa1, 0xd4,
+	0x24, 0x07, 0x50, 0xb3, 0x82, 0x81, 0x89, 0x36, 0x0a, 0xd4, 0xcb, 0x4d, 0xad, 0x55, 0x35, 0xaa,
+	0x56, 0x70, 0xa6, 0x6c, 0xfa, 0x0e, 0x6a, 0x67, 0x5d, 0x03, 0x1f, 0x42, 0x0c, 0x04, 0xd9, 0x87,
+	0x8a, 0x39, 0x1c, 0x48, 0x5e, 0x09, 0xc7, 0xb2, 0x39, 0xbc, 0x61, 0x0e, 0xd2, 0x1f, 0xa0, 0x7c,
+	0xd6, 0xbd, 0x76, 0xef, 0x39, 0x69, 0xc3, 0xce, 0x30, 0x12, 0x18, 0x0c, 0xa6, 0x18, 0x0d, 0x82,
+	0xd0, 0xf3, 0xb8, 0x2f, 0xd0, 0x54, 0xf0, 0xaa, 0xf1, 0x4a, 0x85, 0x7a, 0x18, 0xf5, 0xd3, 0x00,
+	0xbd, 0x83, 0x97, 0x97, 0x28, 0xfa, 0xb2, 0x5d, 0x4f, 0x55, 0xc9, 0x36, 0xa9, 0xb8, 0xa6, 0x49,
+	0xa5, 0x79, 0x93, 0xe8, 0x29, 0x6c, 0x2f, 0x72, 0x07, 0x1e, 0x77, 0x03, 0x24, 0xdf, 0x40, 0x71,
+	0x3a, 0x53, 0x79, 0x37, 0x4f, 0x5e, 0xb6, 0x97, 0x6f, 0xae, 0x77, 0x6b, 0x14, 0xa7, 0x33, 0x3a,
+	0x81, 0x83, 0xf4, 0x47, 0xef, 0x43, 0x5b, 0x58, 0x9e, 0x8d, 0x3d, 0x8c, 0x82, 0x67, 0x92, 0x23,
+	0xf0, 0x62, 0x8a, 0x51, 0xa0, 0x97, 0x9a, 0xa5, 0x56, 0xcd, 0x50, 0xdf, 0xf4, 0x23, 0x1c, 0xe6,
+	0x57, 0x4a, 0xa8, 0x7e, 0x0f, 0x65, 0x75, 0xad, 0x81, 0xae, 0x35, 0x4b, 0xad, 0xcd, 0x93, 0x37,
+	0x19, 0xba, 0xab, 0x27, 0x33, 0x12, 0x30, 0xfd, 0x53, 0x83, 0xdd, 0x79, 0x90, 0xb9, 0xe3, 0xe7,
+	0xf6, 0xf5, 0x00, 0x6a, 0x81, 0x60, 0xbe, 0x18, 0x2c, 0xba, 0x5b, 0x55, 0x8e, 0x1e, 0x46, 0x32,
+	0x27, 0xba, 0xa6, 0x0a, 0xbd, 0x88, 0x73, 0xa2, 0x6b, 0xf6, 0xe2, 0x01, 0xb5, 0x2d, 0xc7, 0x12,
+	0x6a, 0xd8, 0x36, 0x8c, 0xd8, 0xa0, 0x7f, 0x69, 0xb0, 0x73, 0xfe, 0x09, 0x47, 0xa1, 0xc0, 0xdf,
+	0x42, 0xf4, 0xa3, 0x67, 0x52, 0xdb, 0x85, 0x8d, 0x07, 0x99, 0x26, 0xa1, 0x15, 0x1b, 0x72, 0x0b,
+	0x86, 0x9c, 0x4f, 0x1d, 0xe6, 0x4f, 0x13, 0x52, 0x73, 0x5b, 0x1e, 0xc6, 0x63, 0x63, 0x1c, 0x04,
+	0xd6, 0x67, 0x4c, 0xa8, 0x55, 0xa5, 0xa3, 0x6f, 0x7d, 0x46, 0x7a, 0x03, 0x5b, 0x09, 0xab, 0xe4,
+	0x06, 0x8e, 0xa0, 0x34, 0x9d, 0xa5, 0xed, 0xff, 0x62, 0x5a, 0x64, 0x2c, 0x53, 0xac, 0x98, 0x2d,
+	0x46, 0x1f, 0x60, 0xe7, 0x27, 0xcf, 0xb3, 0xa3, 0x8f, 0x9e, 0xc9, 0x04, 0x3e, 0x3d, 0x42, 0xc7,
+	0x50, 0x09, 0x63, 0xa8, 0x5e, 0xcc, 0x2f, 0x99, 0xc6, 0xc9, 0x1e, 0x94, 0x27, 0x68, 0x8d, 0x27,
+	0x22, 0x11, 0x80, 0xc4, 0xa2, 0x6f, 0xa1, 0xd6, 0x67, 0x33, 0xfc, 0xc0, 0x2d, 0x57, 0x2c, 0x81,
+	0xb4, 0x0c, 0xe8, 0x0e, 0xf6, 0x6f, 0x99, 0x6d, 0xc9, 0x4c, 0x3d, 0x8c, 0x6e, 0xe5, 0xd8, 0x3c,
+	0xc9, 0xed, 0x2b, 0x25, 0x88, 0x1a, 0x00, 0x17, 0xa1, 0x6d, 0xf7, 0x47, 0xcc, 0xfd, 0xbf, 0x64,
+	0x8d, 0x3a, 0xb0, 0x9d, 0xe6, 0x9c, 0x5f, 0xcd, 0x5b, 0xa8, 0xab, 0xe0, 0xe0, 0x9e, 0xfb, 0x0e,
+	0x8b, 0x4f, 0xb8, 0x75, 0x55, 0x30, 0x36, 0x95, 0xf7, 0x42, 0x39, 0xc9, 0xb1, 0x5a, 0xf6, 0xa2,
+	0x5a, 0xf6, 0xfd, 0x4c, 0x2f, 0x17, 0x1c, 0xaf, 0x0a, 0x72, 0xed, 0xbb, 0x35, 0xa8, 0x8c, 0xb8,
+	0x2b, 0xd0, 0x15, 0x74, 0x02, 0xf5, 0x6b, 0x47, 0xca, 0xd3, 0x15, 0x32, 0x13, 0xfd, 0x47, 0x87,
+	0x33, 0x60, 0x33, 0xf4, 0x64, 0xb3, 0x55, 0x95, 0xba, 0xb1, 0x70, 0x90, 0xa3, 0x15, 0x86, 0xf2,
+	0x48, 0x5b, 0x19, 0x7e, 0xf4, 0x0f, 0x0d, 0x5e, 0xc7, 0xa5, 0x2e, 0x7c, 0xee, 0xf4, 0x5d, 0xe6,
+	0x05, 0x13, 0x2e, 0xd2, 0xbb, 0x38, 0x95, 0xd7, 0x27, 0x19, 0x24, 0x72, 0xf5, 0x3a, 0x73, 0x82,
+	0x65, 0x8a, 0x57, 0x05, 0x23, 0x81, 0xfe, 0xb7, 0x23, 0x9f, 0xfc, 0x53, 0x86, 0x8a, 0x12, 0x8c,
+	0xb3, 0x2e, 0xf9, 0x11, 0x6a, 0x97, 0x28, 0x12, 0x39, 0xdf, 0xcb, 0xa4, 0x98, 0xff, 0x13, 0x34,
+	0x76, 0x56, 0xfc, 0x12, 0x4c, 0x0b, 0xe4, 0x1a, 0xaa, 0xa9, 0xf4, 0x90, 0xc3, 0x35, 0x72, 0x15,
+	0x27, 0x78, 0x5c, 0xcc, 0x68, 0x81, 0x38, 0xb0, 0x9b, 0xa7, 0x8e, 0xa4, 0x95, 0xfb, 0xc3, 0x1c,
+	0xa9, 0x6e, 0x1c, 0x7f, 0x05, 0x72, 0x5e, 0xee, 0x03, 0x6c, 0x65, 0x44, 0x93, 0x1c, 0xe5, 0x13,
+	0x5c, 0x12, 0xd4, 0x46, 0x23, 0x03, 0xc9, 0x48, 0x07, 0x2d, 0x90, 0x1b, 0xa8, 0x2f, 0x4b, 0x1d,
+	0x69, 0x66, 0xd0, 0x39, 0x2a, 0xf8, 0x44, 0xbe, 0x9f, 0xa1, 0xbe, 0xac, 0x26, 0x2b, 0xf9, 0x72,
+	0x84, 0xa6, 0xb1, 0xd7, 0x8e, 0x5f, 0x1e, 0xed, 0xf4, 0xe5, 0xd1, 0x3e, 0x97, 0x2f, 0x0f, 0x5a,
+	0x20, 0x17, 0x40, 0x2e, 0x51, 0xfc, 0x22, 0xc1, 0x62, 0x49, 0x2f, 0xd6, 0x5c, 0x76, 0xd6, 0x3f,
+	0xc7, 0xd3, 0x02, 0x31, 0x60, 0x7b, 0x55, 0x49, 0xc8, 0xbb, 0x0c, 0x7a, 0x8d, 0xd0, 0x3c, 0xc2,
+	0xed, 0x1c, 0xaa, 0xe9, 0xa8, 0xae, 0x65, 0xf4, 0x26, 0x77, 0xb2, 0x17, 0xcd, 0xfa, 0x4e, 0x23,
+	0xbf, 0x03, 0xf9, 0x72, 0xb5, 0xc8, 0xb7, 0x39, 0x3b, 0x94, 0xb3, 0x7b, 0xeb, 0xe9, 0xb5, 0xb4,
+	0xee, 0xaf, 0x77, 0xef, 0xc7, 0x96, 0x98, 0x84, 0xc3, 0xf6, 0x88, 0x3b, 0x9d, 0x49, 0xe4, 0xa1,
+	0x6f, 0xa3, 0x39, 0x46, 0xbf, 0x73, 0xcf, 0x86, 0xbe, 0x35, 0xea, 0x8c, 0xb8, 0x8f, 0x9d, 0xc4,
+	0x35, 0x9d, 0x25, 0x1f, 0xe2, 0x93, 0x33, 0x76, 0x44, 0x47, 0x95, 0x37, 0x87, 0x9d, 0x25, 0x1a,
+	0xc3, 0xb2, 0x2a, 0x72, 0xfa, 0xef, 0x00, 0xd0, 0x6a, 0x3e, 0xcb, 0x49, 0x0a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// StateDBClient is the client API for StateDB service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StateDBClient interface {
+	GetDBInfo(ctx context.Context, in *DBRequest, opts ...grpc.CallOption) (*DBInfo, error)
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error)
+	GetStateMultipleKeys(ctx context.Context, in *GetStateMultipleKeysRequest, opts ...grpc.CallOption) (*GetStateMultipleKeysResponse, error)
+	// GetStateRange returns at most limit results and a bookmark that points to the next result, if any
+	GetStateRange(ctx context.Context, in *GetStateRangeRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	ExecuteQuery(ctx context.Context, in *ExecuteQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	ApplyUpdates(ctx context.Context, in *ApplyUpdatesRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetLatestSavePoint(ctx context.Context, in *DBRequest, opts ...grpc.CallOption) (*SavePoint, error)
+	ValidateKeyValue(ctx context.Context, in *ValidateKeyValueRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	FullScan(ctx context.Context, in *DBRequest, opts ...grpc.CallOption) (StateDB_FullScanClient, error)
+	ImportFromSnapshot(ctx context.Context, opts ...grpc.CallOption) (StateDB_ImportFromSnapshotClient, error)
+}
+
+type stateDBClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStateDBClient(cc grpc.ClientConnInterface) StateDBClient {
+	return &stateDBClient{cc}
+}
+
+func (c *stateDBClient) GetDBInfo(ctx context.Context, in *DBRequest, opts ...grpc.CallOption) (*DBInfo, error) {
+	out := new(DBInfo)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/GetDBInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error) {
+	out := new(GetStateResponse)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/GetState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) GetStateMultipleKeys(ctx context.Context, in *GetStateMultipleKeysRequest, opts ...grpc.CallOption) (*GetStateMultipleKeysResponse, error) {
+	out := new(GetStateMultipleKeysResponse)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/GetStateMultipleKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) GetStateRange(ctx context.Context, in *GetStateRangeRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/GetStateRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) ExecuteQuery(ctx context.Context, in *ExecuteQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/ExecuteQuery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) ApplyUpdates(ctx context.Context, in *ApplyUpdatesRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/ApplyUpdates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) GetLatestSavePoint(ctx context.Context, in *DBRequest, opts ...grpc.CallOption) (*SavePoint, error) {
+	out := new(SavePoint)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/GetLatestSavePoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) ValidateKeyValue(ctx context.Context, in *ValidateKeyValueRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/stateremote.StateDB/ValidateKeyValue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateDBClient) FullScan(ctx context.Context, in *DBRequest, opts ...grpc.CallOption) (StateDB_FullScanClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StateDB_serviceDesc.Streams[0], "/stateremote.StateDB/FullScan", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &stateDBFullScanClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StateDB_FullScanClient interface {
+	Recv() (*FullScanResponse, error)
+	grpc.ClientStream
+}
+
+type stateDBFullScanClient struct {
+	grpc.ClientStream
+}
+
+func (x *stateDBFullScanClient) Recv() (*FullScanResponse, error) {
+	m := new(FullScanResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *stateDBClient) ImportFromSnapshot(ctx context.Context, opts ...grpc.CallOption) (StateDB_ImportFromSnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StateDB_serviceDesc.Streams[1], "/stateremote.StateDB/ImportFromSnapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &stateDBImportFromSnapshotClient{stream}
+	return x, nil
+}
+
+type StateDB_ImportFromSnapshotClient interface {
+	Send(*ImportFromSnapshotRequest) error
+	CloseAndRecv() (*empty.Empty, error)
+	grpc.ClientStream
+}
+
+type stateDBImportFromSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *stateDBImportFromSnapshotClient) Send(m *ImportFromSnapshotRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *stateDBImportFromSnapshotClient) CloseAndRecv() (*empty.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(empty.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StateDBServer is the server API for StateDB service.
+type StateDBServer interface {
+	GetDBInfo(context.Context, *DBRequest) (*DBInfo, error)
+	GetState(context.Context, *GetStateRequest) (*GetStateResponse, error)
+	GetStateMultipleKeys(context.Context, *GetStateMultipleKeysRequest) (*GetStateMultipleKeysResponse, error)
+	// GetStateRange returns at most limit results and a bookmark that points to the next result, if any
+	GetStateRange(context.Context, *GetStateRangeRequest) (*QueryResponse, error)
+	ExecuteQuery(context.Context, *ExecuteQueryRequest) (*QueryResponse, error)
+	ApplyUpdates(context.Context, *ApplyUpdatesRequest) (*empty.Empty, error)
+	GetLatestSavePoint(context.Context, *DBRequest) (*SavePoint, error)
+	ValidateKeyValue(context.Context, *ValidateKeyValueRequest) (*empty.Empty, error)
+	FullScan(*DBRequest, StateDB_FullScanServer) error
+	ImportFromSnapshot(StateDB_ImportFromSnapshotServer) error
+}
+
+// UnimplementedStateDBServer can be embedded to have forward compatible implementations.
+type UnimplementedStateDBServer struct {
+}
+
+func (*UnimplementedStateDBServer) GetDBInfo(ctx context.Context, req *DBRequest) (*DBInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDBInfo not implemented")
+}
+func (*UnimplementedStateDBServer) GetState(ctx context.Context, req *GetStateRequest) (*GetStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (*UnimplementedStateDBServer) GetStateMultipleKeys(ctx context.Context, req *GetStateMultipleKeysRequest) (*GetStateMultipleKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateMultipleKeys not implemented")
+}
+func (*UnimplementedStateDBServer) GetStateRange(ctx context.Context, req *GetStateRangeRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateRange not implemented")
+}
+func (*UnimplementedStateDBServer) ExecuteQuery(ctx context.Context, req *ExecuteQueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteQuery not implemented")
+}
+func (*UnimplementedStateDBServer) ApplyUpdates(ctx context.Context, req *ApplyUpdatesRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyUpdates not implemented")
+}
+func (*UnimplementedStateDBServer) GetLatestSavePoint(ctx context.Context, req *DBRequest) (*SavePoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestSavePoint not implemented")
+}
+func (*UnimplementedStateDBServer) ValidateKeyValue(ctx context.Context, req *ValidateKeyValueRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateKeyValue not implemented")
+}
+func (*UnimplementedStateDBServer) FullScan(req *DBRequest, srv StateDB_FullScanServer) error {
+	return status.Errorf(codes.Unimplemented, "method FullScan not implemented")
+}
+func (*UnimplementedStateDBServer) ImportFromSnapshot(srv StateDB_ImportFromSnapshotServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportFromSnapshot not implemented")
+}
+
+func RegisterStateDBServer(s *grpc.Server, srv StateDBServer) {
+	s.RegisterService(&_StateDB_serviceDesc, srv)
+}
+
+func _StateDB_GetDBInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).GetDBInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/GetDBInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).GetDBInfo(ctx, req.(*DBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/GetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_GetStateMultipleKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateMultipleKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).GetStateMultipleKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/GetStateMultipleKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).GetStateMultipleKeys(ctx, req.(*GetStateMultipleKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_GetStateRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).GetStateRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/GetStateRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).GetStateRange(ctx, req.(*GetStateRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_ExecuteQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).ExecuteQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/ExecuteQuery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).ExecuteQuery(ctx, req.(*ExecuteQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_ApplyUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).ApplyUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/ApplyUpdates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).ApplyUpdates(ctx, req.(*ApplyUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_GetLatestSavePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).GetLatestSavePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/GetLatestSavePoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).GetLatestSavePoint(ctx, req.(*DBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_ValidateKeyValue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateKeyValueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateDBServer).ValidateKeyValue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stateremote.StateDB/ValidateKeyValue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateDBServer).ValidateKeyValue(ctx, req.(*ValidateKeyValueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateDB_FullScan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DBRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StateDBServer).FullScan(m, &stateDBFullScanServer{stream})
+}
+
+type StateDB_FullScanServer interface {
+	Send(*FullScanResponse) error
+	grpc.ServerStream
+}
+
+type stateDBFullScanServer struct {
+	grpc.ServerStream
+}
+
+func (x *stateDBFullScanServer) Send(m *FullScanResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StateDB_ImportFromSnapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StateDBServer).ImportFromSnapshot(&stateDBImportFromSnapshotServer{stream})
+}
+
+type StateDB_ImportFromSnapshotServer interface {
+	SendAndClose(*empty.Empty) error
+	Recv() (*ImportFromSnapshotRequest, error)
+	grpc.ServerStream
+}
+
+type stateDBImportFromSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *stateDBImportFromSnapshotServer) SendAndClose(m *empty.Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *stateDBImportFromSnapshotServer) Recv() (*ImportFromSnapshotRequest, error) {
+	m := new(ImportFromSnapshotRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _StateDB_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "stateremote.StateDB",
+	HandlerType: (*StateDBServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDBInfo",
+			Handler:    _StateDB_GetDBInfo_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _StateDB_GetState_Handler,
+		},
+		{
+			MethodName: "GetStateMultipleKeys",
+			Handler:    _StateDB_GetStateMultipleKeys_Handler,
+		},
+		{
+			MethodName: "GetStateRange",
+			Handler:    _StateDB_GetStateRange_Handler,
+		},
+		{
+			MethodName: "ExecuteQuery",
+			Handler:    _StateDB_ExecuteQuery_Handler,
+		},
+		{
+			MethodName: "ApplyUpdates",
+			Handler:    _StateDB_ApplyUpdates_Handler,
+		},
+		{
+			MethodName: "GetLatestSavePoint",
+			Handler:    _StateDB_GetLatestSavePoint_Handler,
+		},
+		{
+			MethodName: "ValidateKeyValue",
+			Handler:    _StateDB_ValidateKeyValue_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FullScan",
+			Handler:       _StateDB_FullScan_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportFromSnapshot",
+			Handler:       _StateDB_ImportFromSnapshot_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "stateremote.proto",
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateremote
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	commontests.TestVersionedDBProviderConformance(
+		t,
+		func(t *testing.T) (statedb.VersionedDBProvider, func()) {
+			env := NewTestVDBEnv(t)
+			return env.DBProvider, env.Cleanup
+		},
+		stateleveldb.TestEnvDBValueformat,
+		stateleveldb.TestEnvDBValueDecoder,
+	)
+}
+
+func TestRegisteredFactory(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	factory, ok := statedb.GetVersionedDBProviderFactory(StateDatabaseName)
+	require.True(t, ok)
+
+	t.Run("valid-config", func(t *testing.T) {
+		dbProvider, err := factory(
+			&statedb.VersionedDBProviderConfig{
+				PluginConfig: map[string]interface{}{
+					"address":     env.DBProvider.conn.Target(),
+					"dialTimeout": "2s",
+				},
+			},
+		)
+		require.NoError(t, err)
+		defer dbProvider.Close()
+
+		db, err := dbProvider.GetDBHandle("testregisteredfactory", nil)
+		require.NoError(t, err)
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+		require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+		vv, err := db.GetState("ns1", "key1")
+		require.NoError(t, err)
+		require.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, vv)
+	})
+
+	t.Run("missing-address", func(t *testing.T) {
+		_, err := factory(&statedb.VersionedDBProviderConfig{})
+		require.EqualError(t, err, "address of the remote state store is not specified")
+	})
+
+	t.Run("invalid-dial-timeout", func(t *testing.T) {
+		_, err := factory(
+			&statedb.VersionedDBProviderConfig{
+				PluginConfig: map[string]interface{}{
+					"address":     env.DBProvider.conn.Target(),
+					"dialTimeout": "not-a-duration",
+				},
+			},
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error while decoding the configuration of the remote state store")
+	})
+
+	t.Run("missing-tls-root-cert", func(t *testing.T) {
+		_, err := factory(
+			&statedb.VersionedDBProviderConfig{
+				PluginConfig: map[string]interface{}{
+					"address":         env.DBProvider.conn.Target(),
+					"tlsEnabled":      true,
+					"tlsRootCertFile": "non-existent-file",
+				},
+			},
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error while reading the TLS root certificate file [non-existent-file] of the remote state store")
+	})
+}
+
+func TestErrorPropagation(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testerrorpropagation", nil)
+	require.NoError(t, err)
+
+	_, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"jerry"},"skip":1}`)
+	require.EqualError(t, err, "unsupported query option [skip]")
+
+	env.grpcServer.Stop()
+	_, err = db.GetState("ns1", "key1")
+	require.Error(t, err)
+}
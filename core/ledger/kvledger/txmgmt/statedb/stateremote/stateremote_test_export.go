@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateremote
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// TestVDBEnv provides a remote state store backed versioned db for testing. The remote state store
+// is a local stand-in that serves a level db backed versioned db over gRPC
+type TestVDBEnv struct {
+	t          testing.TB
+	DBProvider *VersionedDBProvider
+	Server     *Server
+	levelDBEnv *stateleveldb.TestVDBEnv
+	grpcServer *grpc.Server
+}
+
+// NewTestVDBEnv starts a local remote state store and instantiates a TestVDBEnv connected to it
+func NewTestVDBEnv(t testing.TB) *TestVDBEnv {
+	t.Logf("Creating new TestVDBEnv")
+	levelDBEnv := stateleveldb.NewTestVDBEnv(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewServer(levelDBEnv.DBProvider)
+	grpcServer := grpc.NewServer()
+	RegisterStateDBServer(grpcServer, server)
+	go grpcServer.Serve(lis)
+
+	dbProvider, err := NewVersionedDBProvider(&Config{Address: lis.Addr().String()})
+	require.NoError(t, err)
+	return &TestVDBEnv{
+		t:          t,
+		DBProvider: dbProvider,
+		Server:     server,
+		levelDBEnv: levelDBEnv,
+		grpcServer: grpcServer,
+	}
+}
+
+// Cleanup closes the connection, stops the local remote state store, and removes the db folder
+func (env *TestVDBEnv) Cleanup() {
+	env.t.Logf("Cleaningup TestVDBEnv")
+	env.DBProvider.Close()
+	env.grpcServer.Stop()
+	env.levelDBEnv.Cleanup()
+}
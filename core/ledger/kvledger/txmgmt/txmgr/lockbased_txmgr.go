@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/pvtstatepurgemgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/queryutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -44,6 +45,13 @@ type LockBasedTxMgr struct {
 	oldBlockCommit      sync.Mutex
 	current             *current
 	hashFunc            rwsetutil.HashFunc
+	updatesObserver     UpdatesObserver
+	pendingCommitLock   sync.Mutex
+	pendingCommit       *pendingCommit
+	// reconcileLock keeps the commit of the pvtdata of old blocks from interleaving with the validation of
+	// a block that overlaps with a background commit, as such a validation does not hold the lock on
+	// oldBlockCommit. When both the locks are needed, reconcileLock is acquired first
+	reconcileLock sync.Mutex
 }
 
 // pvtdataPurgeMgr wraps the actual purge manager and an additional flag 'usedOnce'
@@ -74,9 +82,10 @@ func (e *ErrPvtdataNotAvailable) Error() string {
 }
 
 type current struct {
-	block     *common.Block
-	batch     *privacyenabledstate.UpdateBatch
-	listeners []ledger.StateListener
+	block                       *common.Block
+	batch                       *privacyenabledstate.UpdateBatch
+	listeners                   []ledger.StateListener
+	updatesValidationParameters bool
 }
 
 func (c *current) blockNum() uint64 {
@@ -87,6 +96,39 @@ func (c *current) maxTxNumber() uint64 {
 	return uint64(len(c.block.Data.Data)) - 1
 }
 
+// pendingCommit tracks the commit of a block to the state database that proceeds in the background, see StartCommit
+type pendingCommit struct {
+	current *current
+	done    chan struct{}
+	err     error
+}
+
+// Batch implements method in interface `validation.PendingUpdates`
+func (p *pendingCommit) Batch() *privacyenabledstate.UpdateBatch {
+	return p.current.batch
+}
+
+// WaitForCommit implements method in interface `validation.PendingUpdates`
+func (p *pendingCommit) WaitForCommit() error {
+	<-p.done
+	return p.err
+}
+
+func (p *pendingCommit) isDone() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// UpdatesObserver gets notified of the final updates of a block, which include the deletes of the expired private data,
+// just before the updates are applied to the state database. An error returned by the observer fails the commit
+type UpdatesObserver interface {
+	HandleUpdates(updates *privacyenabledstate.UpdateBatch, height *version.Height) error
+}
+
 // Initializer captures the dependencies for tx manager
 type Initializer struct {
 	LedgerID            string
@@ -97,6 +139,7 @@ type Initializer struct {
 	CCInfoProvider      ledger.DeployedChaincodeInfoProvider
 	CustomTxProcessors  map[common.HeaderType]ledger.CustomTxProcessor
 	HashFunc            rwsetutil.HashFunc
+	UpdatesObserver     UpdatesObserver
 }
 
 // NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
@@ -110,11 +153,12 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		return nil, err
 	}
 	txmgr := &LockBasedTxMgr{
-		ledgerid:       initializer.LedgerID,
-		db:             initializer.DB,
-		stateListeners: initializer.StateListeners,
-		ccInfoProvider: initializer.CCInfoProvider,
-		hashFunc:       initializer.HashFunc,
+		ledgerid:        initializer.LedgerID,
+		db:              initializer.DB,
+		stateListeners:  initializer.StateListeners,
+		ccInfoProvider:  initializer.CCInfoProvider,
+		hashFunc:        initializer.HashFunc,
+		updatesObserver: initializer.UpdatesObserver,
 	}
 	pvtstatePurgeMgr, err := pvtstatepurgemgmt.InstantiatePurgeMgr(
 		initializer.LedgerID,
@@ -187,20 +231,43 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 	// Once the ledger cache (FAB-103) is introduced and existing
 	// LoadCommittedVersions() is refactored to return a map, we can allow
 	// these three functions to execute parallelly.
+	// The commit of the preceding block that proceeds in the background (see StartCommit) holds the lock on
+	// oldBlockCommit till it finishes. The validation overlaps with such a commit only for a statedb that does not
+	// support the bulk read API, and waits for the commit to finish otherwise. An overlapping validation cannot
+	// acquire the lock on oldBlockCommit and, instead, holds the lock on reconcileLock, as the pvtdata of old
+	// blocks would otherwise be committed, as soon as the background commit finishes, while the validation reads
+	// the state and the purge manager.
+	pendingCommit := txmgr.getPendingCommit()
+	if pendingCommit != nil && (pendingCommit.isDone() || txmgr.db.IsBulkOptimizable()) {
+		if err := pendingCommit.WaitForCommit(); err != nil {
+			return nil, nil, err
+		}
+		pendingCommit = nil
+	}
 	logger.Debugf("Waiting for purge mgr to finish the background job of computing expirying keys for the block")
 	txmgr.pvtdataPurgeMgr.WaitForPrepareToFinish()
-	txmgr.oldBlockCommit.Lock()
-	defer txmgr.oldBlockCommit.Unlock()
-	logger.Debug("lock acquired on oldBlockCommit for validating read set version against the committed version")
+	var pendingUpdates validation.PendingUpdates
+	if pendingCommit == nil {
+		txmgr.oldBlockCommit.Lock()
+		defer txmgr.oldBlockCommit.Unlock()
+		logger.Debug("lock acquired on oldBlockCommit for validating read set version against the committed version")
+	} else {
+		txmgr.reconcileLock.Lock()
+		defer txmgr.reconcileLock.Unlock()
+		logger.Debug("lock acquired on reconcileLock for validating read set version against the pending updates")
+		pendingUpdates = pendingCommit
+	}
 
 	block := blockAndPvtdata.Block
 	logger.Debugf("Validating new block with num trans = [%d]", len(block.Data.Data))
-	batch, txstatsInfo, err := txmgr.commitBatchPreparer.ValidateAndPrepareBatch(blockAndPvtdata, doMVCCValidation)
+	batch, txstatsInfo, updatesValidationParameters, err := txmgr.commitBatchPreparer.ValidateAndPrepareBatchWithPendingUpdates(
+		blockAndPvtdata, doMVCCValidation, pendingUpdates,
+	)
 	if err != nil {
 		txmgr.reset()
 		return nil, nil, err
 	}
-	txmgr.current = &current{block: block, batch: batch}
+	txmgr.current = &current{block: block, batch: batch, updatesValidationParameters: updatesValidationParameters}
 	if err := txmgr.invokeNamespaceListeners(); err != nil {
 		txmgr.reset()
 		return nil, nil, err
@@ -213,7 +280,7 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 // RemoveStaleAndCommitPvtDataOfOldBlocks implements method in interface `txmgmt.TxMgr`
 // The following six operations are performed:
 // (1) constructs the unique pvt data from the passed reconciledPvtdata
-// (2) acquire a lock on oldBlockCommit
+// (2) acquire a lock on reconcileLock and on oldBlockCommit
 // (3) checks for stale pvtData by comparing [version, valueHash] and removes stale data
 // (4) creates update batch from the the non-stale pvtData
 // (5) update the BTL bookkeeping managed by the purge manager and update expiring keys.
@@ -236,6 +303,8 @@ func (txmgr *LockBasedTxMgr) RemoveStaleAndCommitPvtDataOfOldBlocks(reconciledPv
 	// between Commit() and execution of this function for the correctness.
 	logger.Debug("Waiting for purge mgr to finish the background job of computing expirying keys for the block")
 	txmgr.pvtdataPurgeMgr.WaitForPrepareToFinish()
+	txmgr.reconcileLock.Lock()
+	defer txmgr.reconcileLock.Unlock()
 	txmgr.oldBlockCommit.Lock()
 	defer txmgr.oldBlockCommit.Unlock()
 	logger.Debug("lock acquired on oldBlockCommit for committing pvtData of old blocks to state database")
@@ -278,6 +347,19 @@ func (txmgr *LockBasedTxMgr) RemoveStaleAndCommitPvtDataOfOldBlocks(reconciledPv
 	return nil
 }
 
+// ForEachValueHash invokes the given function with each of the private data hashes of the collection
+// <namespace, collection> in the state
+func (txmgr *LockBasedTxMgr) ForEachValueHash(namespace, collection string, f func(keyHash []byte, vv *statedb.VersionedValue) error) error {
+	return txmgr.db.ForEachValueHash(namespace, collection, f)
+}
+
+// UpdateExpiryInfoOfImportedHashes records the expiry of the given private data hashes, which are imported
+// from a snapshot, in the bookkeeping of the purge manager, so that the hashes are purged at their expiry
+// like the hashes committed by the blocks
+func (txmgr *LockBasedTxMgr) UpdateExpiryInfoOfImportedHashes(hashedUpdates *privacyenabledstate.HashedUpdateBatch) error {
+	return txmgr.pvtdataPurgeMgr.UpdateExpiryInfo(privacyenabledstate.NewPvtUpdateBatch(), hashedUpdates)
+}
+
 type uniquePvtDataMap map[privacyenabledstate.HashedCompositeKey]*privacyenabledstate.PvtKVWrite
 
 func constructUniquePvtData(reconciledPvtdata map[uint64][]*ledger.TxPvtData) (uniquePvtDataMap, error) {
@@ -469,6 +551,10 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 		if len(stateUpdatesForListener) == 0 {
 			continue
 		}
+		// a listener expects the preceding block to be fully committed, including the invocation of StateCommitDone
+		if err := txmgr.WaitForPendingCommit(); err != nil {
+			return err
+		}
 		txmgr.current.listeners = append(txmgr.current.listeners, listener)
 
 		committedStateQueryExecuter := &queryutil.QECombiner{
@@ -501,14 +587,20 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
-	// wait for background go routine to finish else the timing issue causes a nil pointer inside goleveldb code
+	// wait for background go routines to finish else the timing issue causes a nil pointer inside goleveldb code
 	// see FAB-11974
+	if err := txmgr.WaitForPendingCommit(); err != nil {
+		logger.Errorf("Error while committing updates to state database: %s", err)
+	}
 	txmgr.pvtdataPurgeMgr.WaitForPrepareToFinish()
 	txmgr.db.Close()
 }
 
 // Commit implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Commit() error {
+	if err := txmgr.WaitForPendingCommit(); err != nil {
+		return err
+	}
 	// we need to acquire a lock on oldBlockCommit. The following are the two reasons:
 	// (1) the DeleteExpiredAndUpdateBookkeeping() would perform incorrect operation if
 	//        toPurgeList is updated by RemoveStaleAndCommitPvtDataOfOldBlocks().
@@ -520,38 +612,132 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	defer txmgr.oldBlockCommit.Unlock()
 	logger.Debug("lock acquired on oldBlockCommit for committing regular updates to state database")
 
+	c := txmgr.current
+	txmgr.reset()
+	if err := txmgr.finalizeBatch(c); err != nil {
+		return err
+	}
+	return txmgr.applyFinalBatch(c)
+}
+
+// StartCommit is same as Commit except that the final updates are applied to the state database in the background, so
+// that the validation of the next block can overlap with it. It returns as soon as the final update batch, which includes
+// the deletes of the expired private data, is constructed. The validation of the next block waits for the background
+// commit only if the validation reads any of these updates and, as a result, the outcome of the validation is the same
+// as if the block was fully committed. The function done is invoked with the outcome of the commit and a subsequent
+// commit starts only after it returns. However, the block is committed synchronously, i.e., before StartCommit returns,
+// if its updates are consumed by the endorsement policy evaluation of the subsequent blocks outside of the ledger. These
+// are the updates of the namespaces of interest to the state listeners, such as the chaincode definitions and the
+// collection configurations, and the updates of the validation parameters of the keys
+func (txmgr *LockBasedTxMgr) StartCommit(done func(error)) {
+	if err := txmgr.WaitForPendingCommit(); err != nil {
+		done(err)
+		return
+	}
+	// see the comments in Commit() for the reasons for acquiring the lock on oldBlockCommit. For a background
+	// commit, the lock is released by the background goroutine
+	txmgr.oldBlockCommit.Lock()
+	logger.Debug("lock acquired on oldBlockCommit for committing regular updates to state database")
+
+	c := txmgr.current
+	txmgr.reset()
+	if err := txmgr.finalizeBatch(c); err != nil {
+		txmgr.oldBlockCommit.Unlock()
+		done(err)
+		return
+	}
+	if len(c.listeners) > 0 || c.updatesValidationParameters {
+		logger.Debugf("Committing block [%d] synchronously as it updates the inputs of the endorsement policy evaluation", c.blockNum())
+		err := txmgr.applyFinalBatch(c)
+		txmgr.oldBlockCommit.Unlock()
+		done(err)
+		return
+	}
+
+	p := &pendingCommit{
+		current: c,
+		done:    make(chan struct{}),
+	}
+	txmgr.pendingCommitLock.Lock()
+	txmgr.pendingCommit = p
+	txmgr.pendingCommitLock.Unlock()
+	go func() {
+		// the commit is marked done before the lock on oldBlockCommit is released so that whoever
+		// acquires the lock next observes the commit as finished
+		defer txmgr.oldBlockCommit.Unlock()
+		defer close(p.done)
+		p.err = txmgr.applyFinalBatch(c)
+		done(p.err)
+	}()
+}
+
+// WaitForPendingCommit waits for the background commit started by StartCommit, if any, to finish and returns its error
+func (txmgr *LockBasedTxMgr) WaitForPendingCommit() error {
+	p := txmgr.getPendingCommit()
+	if p == nil {
+		return nil
+	}
+	if err := p.WaitForCommit(); err != nil {
+		return err
+	}
+	txmgr.pendingCommitLock.Lock()
+	defer txmgr.pendingCommitLock.Unlock()
+	if txmgr.pendingCommit == p {
+		txmgr.pendingCommit = nil
+	}
+	return nil
+}
+
+func (txmgr *LockBasedTxMgr) getPendingCommit() *pendingCommit {
+	txmgr.pendingCommitLock.Lock()
+	defer txmgr.pendingCommitLock.Unlock()
+	return txmgr.pendingCommit
+}
+
+// finalizeBatch adds the deletes of the expired private data to the update batch of the block.
+// The caller is expected to hold the lock on oldBlockCommit
+func (txmgr *LockBasedTxMgr) finalizeBatch(c *current) error {
+	if c == nil {
+		panic("validateAndPrepare() method should have been called before calling commit()")
+	}
 	// When using the purge manager for the first block commit after peer start, the asynchronous function
 	// 'PrepareForExpiringKeys' is invoked in-line. However, for the subsequent blocks commits, this function is invoked
 	// in advance for the next block
 	if !txmgr.pvtdataPurgeMgr.usedOnce {
-		txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(txmgr.current.blockNum())
+		txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(c.blockNum())
 		txmgr.pvtdataPurgeMgr.usedOnce = true
 	}
-	defer func() {
-		txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(txmgr.current.blockNum() + 1)
-		logger.Debugf("launched the background routine for preparing keys to purge with the next block")
-		txmgr.reset()
-	}()
-
-	logger.Debugf("Committing updates to state database")
-	if txmgr.current == nil {
-		panic("validateAndPrepare() method should have been called before calling commit()")
-	}
 
 	if err := txmgr.pvtdataPurgeMgr.UpdateExpiryInfo(
-		txmgr.current.batch.PvtUpdates, txmgr.current.batch.HashUpdates); err != nil {
+		c.batch.PvtUpdates, c.batch.HashUpdates); err != nil {
+		txmgr.prepareForExpiringKeysOfNextBlock(c)
 		return err
 	}
 
 	if err := txmgr.pvtdataPurgeMgr.AddExpiredEntriesToUpdateBatch(
-		txmgr.current.batch.PvtUpdates, txmgr.current.batch.HashUpdates); err != nil {
+		c.batch.PvtUpdates, c.batch.HashUpdates); err != nil {
+		txmgr.prepareForExpiringKeysOfNextBlock(c)
 		return err
 	}
+	return nil
+}
+
+// applyFinalBatch applies the final update batch of the block to the state database.
+// The caller is expected to hold the lock on oldBlockCommit
+func (txmgr *LockBasedTxMgr) applyFinalBatch(c *current) error {
+	defer txmgr.prepareForExpiringKeysOfNextBlock(c)
+
+	logger.Debugf("Committing updates to state database")
+	commitHeight := version.NewHeight(c.blockNum(), c.maxTxNumber())
+	if txmgr.updatesObserver != nil {
+		if err := txmgr.updatesObserver.HandleUpdates(c.batch, commitHeight); err != nil {
+			return err
+		}
+	}
 
-	commitHeight := version.NewHeight(txmgr.current.blockNum(), txmgr.current.maxTxNumber())
 	txmgr.commitRWLock.Lock()
 	logger.Debugf("Write lock acquired for committing updates to state database")
-	if err := txmgr.db.ApplyPrivacyAwareUpdates(txmgr.current.batch, commitHeight); err != nil {
+	if err := txmgr.db.ApplyPrivacyAwareUpdates(c.batch, commitHeight); err != nil {
 		txmgr.commitRWLock.Unlock()
 		return err
 	}
@@ -568,10 +754,15 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	}
 	// In the case of error state listeners will not receive this call - instead a peer panic is caused by the ledger upon receiving
 	// an error from this function
-	txmgr.updateStateListeners()
+	txmgr.updateStateListeners(c)
 	return nil
 }
 
+func (txmgr *LockBasedTxMgr) prepareForExpiringKeysOfNextBlock(c *current) {
+	txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(c.blockNum() + 1)
+	logger.Debugf("launched the background routine for preparing keys to purge with the next block")
+}
+
 // Rollback implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Rollback() {
 	txmgr.reset()
@@ -663,8 +854,8 @@ func extractStateUpdates(batch *privacyenabledstate.UpdateBatch, namespaces []st
 	return su
 }
 
-func (txmgr *LockBasedTxMgr) updateStateListeners() {
-	for _, l := range txmgr.current.listeners {
+func (txmgr *LockBasedTxMgr) updateStateListeners(c *current) {
+	for _, l := range c.listeners {
 		l.StateCommitDone(txmgr.ledgerid)
 	}
 }
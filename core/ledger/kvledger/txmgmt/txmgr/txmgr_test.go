@@ -12,19 +12,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1612,3 +1619,297 @@ func TestName(t *testing.T) {
 	txMgr := testEnv.getTxMgr()
 	require.Equal(t, "state", txMgr.Name())
 }
+
+func TestUpdatesObserver(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testLedger", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	observer := &testUpdatesObserver{}
+	txMgr.updatesObserver = observer
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	s, err := txMgr.NewTxSimulator("test_tx1")
+	require.NoError(t, err)
+	require.NoError(t, s.SetState("ns1", "key1", []byte("value1")))
+	s.Done()
+	txRWSet, err := s.GetTxSimulationResults()
+	require.NoError(t, err)
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	require.Len(t, observer.heights, 1)
+	require.Equal(t, version.NewHeight(1, 0), observer.heights[0])
+	require.Equal(t,
+		&statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 0)},
+		observer.updates[0].PubUpdates.Get("ns1", "key1"),
+	)
+
+	t.Run("error-fails-commit", func(t *testing.T) {
+		observer.err = errors.New("observer error")
+		s, err := txMgr.NewTxSimulator("test_tx2")
+		require.NoError(t, err)
+		require.NoError(t, s.SetState("ns1", "key1", []byte("value2")))
+		s.Done()
+		txRWSet, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		rwSetBytes, err := proto.Marshal(txRWSet.PubSimulationResults)
+		require.NoError(t, err)
+		block := txMgrHelper.bg.NextBlock([][]byte{rwSetBytes})
+		_, _, err = txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block}, true)
+		require.NoError(t, err)
+		require.EqualError(t, txMgr.Commit(), "observer error")
+
+		vv, err := txMgr.db.GetState("ns1", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), vv.Value)
+	})
+}
+
+type testUpdatesObserver struct {
+	updates []*privacyenabledstate.UpdateBatch
+	heights []*version.Height
+	err     error
+}
+
+func (o *testUpdatesObserver) HandleUpdates(updates *privacyenabledstate.UpdateBatch, height *version.Height) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.updates = append(o.updates, updates)
+	o.heights = append(o.heights, height)
+	return nil
+}
+
+func TestStartCommit(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testLedger", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	simulate := func(txid string, readKeys []string, writeKVs map[string]string) []byte {
+		s, err := txMgr.NewTxSimulator(txid)
+		require.NoError(t, err)
+		for _, k := range readKeys {
+			_, err := s.GetState("ns1", k)
+			require.NoError(t, err)
+		}
+		for k, v := range writeKVs {
+			require.NoError(t, s.SetState("ns1", k, []byte(v)))
+		}
+		s.Done()
+		simRes, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		return pubSimBytes
+	}
+	validationCode := func(blockAndPvtdata *ledger.BlockAndPvtData) peer.TxValidationCode {
+		metadata := blockAndPvtdata.Block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+		return txflags.ValidationFlags(metadata).Flag(0)
+	}
+
+	s, err := txMgr.NewTxSimulator("init")
+	require.NoError(t, err)
+	require.NoError(t, s.SetState("ns1", "key1", []byte("value1")))
+	require.NoError(t, s.SetState("ns1", "key2", []byte("value1")))
+	s.Done()
+	txRWSet, err := s.GetTxSimulationResults()
+	require.NoError(t, err)
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	observer := &blockingUpdatesObserver{}
+	txMgr.updatesObserver = observer
+	commitErrs := make(chan error, 10)
+	done := func(err error) { commitErrs <- err }
+
+	t.Run("validation-waits-for-pending-updates-it-reads", func(t *testing.T) {
+		blk1 := &ledger.BlockAndPvtData{Block: txMgrHelper.bg.NextBlock([][]byte{simulate("tx1", nil, map[string]string{"key1": "value2"})})}
+		blk2 := &ledger.BlockAndPvtData{Block: txMgrHelper.bg.NextBlock([][]byte{simulate("tx2", []string{"key1"}, map[string]string{"key3": "value1"})})}
+
+		observer.block()
+		_, _, err := txMgr.ValidateAndPrepare(blk1, true)
+		require.NoError(t, err)
+		txMgr.StartCommit(done)
+		require.NotNil(t, txMgr.getPendingCommit())
+
+		validated := make(chan error, 1)
+		go func() {
+			_, _, err := txMgr.ValidateAndPrepare(blk2, true)
+			validated <- err
+		}()
+		require.Never(t, func() bool { return len(validated) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+		observer.unblock()
+		require.NoError(t, <-validated)
+		require.NoError(t, <-commitErrs)
+		require.Equal(t, peer.TxValidationCode_MVCC_READ_CONFLICT, validationCode(blk2))
+		require.NoError(t, txMgr.Commit())
+	})
+
+	t.Run("validation-overlaps-with-commit-of-independent-updates", func(t *testing.T) {
+		blk3 := &ledger.BlockAndPvtData{Block: txMgrHelper.bg.NextBlock([][]byte{simulate("tx3", nil, map[string]string{"key1": "value3"})})}
+		blk4 := &ledger.BlockAndPvtData{Block: txMgrHelper.bg.NextBlock([][]byte{simulate("tx4", []string{"key2"}, map[string]string{"key4": "value1"})})}
+
+		observer.block()
+		_, _, err := txMgr.ValidateAndPrepare(blk3, true)
+		require.NoError(t, err)
+		txMgr.StartCommit(done)
+
+		_, _, err = txMgr.ValidateAndPrepare(blk4, true)
+		require.NoError(t, err)
+		require.Equal(t, peer.TxValidationCode_VALID, validationCode(blk4))
+		require.Len(t, commitErrs, 0)
+
+		observer.unblock()
+		txMgr.StartCommit(done)
+		require.NoError(t, <-commitErrs)
+		require.NoError(t, <-commitErrs)
+		require.NoError(t, txMgr.WaitForPendingCommit())
+		require.Nil(t, txMgr.getPendingCommit())
+
+		for key, expectedValue := range map[string]string{"key1": "value3", "key4": "value1"} {
+			vv, err := txMgr.db.GetState("ns1", key)
+			require.NoError(t, err)
+			require.Equal(t, []byte(expectedValue), vv.Value)
+		}
+	})
+
+	t.Run("updates-of-validation-parameters-committed-synchronously", func(t *testing.T) {
+		s, err := txMgr.NewTxSimulator("tx5")
+		require.NoError(t, err)
+		require.NoError(t, s.SetStateMetadata("ns1", "key2", map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")}))
+		s.Done()
+		simRes, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+
+		_, _, err = txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: txMgrHelper.bg.NextBlock([][]byte{pubSimBytes})}, true)
+		require.NoError(t, err)
+		txMgr.StartCommit(done)
+		require.Len(t, commitErrs, 1)
+		require.NoError(t, <-commitErrs)
+		require.Nil(t, txMgr.getPendingCommit())
+
+		metadata, err := txMgr.db.GetStateMetadata("ns1", "key2")
+		require.NoError(t, err)
+		require.NotNil(t, metadata)
+	})
+}
+
+// blockingUpdatesObserver holds the commits in progress, after the final update batch is constructed, till unblocked
+type blockingUpdatesObserver struct {
+	proceed chan struct{}
+}
+
+func (o *blockingUpdatesObserver) block() {
+	o.proceed = make(chan struct{})
+}
+
+func (o *blockingUpdatesObserver) unblock() {
+	close(o.proceed)
+}
+
+func (o *blockingUpdatesObserver) HandleUpdates(updates *privacyenabledstate.UpdateBatch, height *version.Height) error {
+	if o.proceed != nil {
+		<-o.proceed
+	}
+	return nil
+}
+
+// TestStartCommitWithCommitOfOldPvtData commits blocks in the pipelined fashion while the pvtdata of an old block
+// is committed concurrently. Run with the race detector, the test detects the validation of a block that
+// overlaps with a background commit not being serialized with the commit of the pvtdata of old blocks
+func TestStartCommitWithCommitOfOldPvtData(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns1", "coll1"}: 0,
+		},
+	)
+	testEnv.init(t, "testLedger", btlPolicy)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	populateCollConfigForTest(t, txMgr, []collConfigkey{{"ns1", "coll1"}}, version.NewHeight(1, 1))
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// commit the hashes of the private data, for which the pvtdata is committed later
+	s, err := txMgr.NewTxSimulator("init")
+	require.NoError(t, err)
+	require.NoError(t, s.SetState("ns1", "key", []byte("value")))
+	require.NoError(t, s.SetPrivateData("ns1", "coll1", "pvtkey1", []byte("pvtvalue1")))
+	require.NoError(t, s.SetPrivateData("ns1", "coll1", "pvtkey2", []byte("pvtvalue2")))
+	s.Done()
+	txRWSet, err := s.GetTxSimulationResults()
+	require.NoError(t, err)
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+	vv, err := txMgr.db.GetValueHash("ns1", "coll1", util.ComputeStringHash("pvtkey1"))
+	require.NoError(t, err)
+	oldBlockPvtData := map[uint64][]*ledger.TxPvtData{
+		vv.Version.BlockNum: {
+			producePvtdata(t, vv.Version.TxNum,
+				[]string{"ns1:coll1", "ns1:coll1"},
+				[]string{"pvtkey1", "pvtkey2"},
+				[][]byte{[]byte("pvtvalue1"), []byte("pvtvalue2")},
+			),
+		},
+	}
+
+	var blocks []*ledger.BlockAndPvtData
+	for i := 0; i < 20; i++ {
+		s, err := txMgr.NewTxSimulator(fmt.Sprintf("tx%d", i))
+		require.NoError(t, err)
+		_, err = s.GetState("ns1", "key")
+		require.NoError(t, err)
+		require.NoError(t, s.SetState("ns1", fmt.Sprintf("key%d", i), []byte("value")))
+		s.Done()
+		simRes, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		blocks = append(blocks, &ledger.BlockAndPvtData{Block: txMgrHelper.bg.NextBlock([][]byte{pubSimBytes})})
+	}
+
+	commitErrs := make(chan error, len(blocks))
+	pvtdataCommitErrs := make(chan error, len(blocks))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for _, blk := range blocks {
+			if _, _, err := txMgr.ValidateAndPrepare(blk, true); err != nil {
+				commitErrs <- err
+				return
+			}
+			txMgr.StartCommit(func(err error) { commitErrs <- err })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range blocks {
+			pvtdataCommitErrs <- txMgr.RemoveStaleAndCommitPvtDataOfOldBlocks(oldBlockPvtData)
+		}
+	}()
+	wg.Wait()
+	require.NoError(t, txMgr.WaitForPendingCommit())
+	close(commitErrs)
+	close(pvtdataCommitErrs)
+	for err := range commitErrs {
+		require.NoError(t, err)
+	}
+	for err := range pvtdataCommitErrs {
+		require.NoError(t, err)
+	}
+
+	for i := range blocks {
+		vv, err := txMgr.db.GetState("ns1", fmt.Sprintf("key%d", i))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), vv.Value)
+	}
+	for key, expectedValue := range map[string]string{"pvtkey1": "pvtvalue1", "pvtkey2": "pvtvalue2"} {
+		vv, err := txMgr.db.GetPrivateData("ns1", "coll1", key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(expectedValue), vv.Value)
+	}
+}
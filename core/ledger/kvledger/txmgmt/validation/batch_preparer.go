@@ -69,6 +69,18 @@ func NewCommitBatchPreparer(
 // ValidateAndPrepareBatch performs validation of transactions in the block and prepares the batch of final writes
 func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.BlockAndPvtData,
 	doMVCCValidation bool) (*privacyenabledstate.UpdateBatch, []*TxStatInfo, error) {
+	batch, txsStatInfo, _, err := p.ValidateAndPrepareBatchWithPendingUpdates(blockAndPvtdata, doMVCCValidation, nil)
+	return batch, txsStatInfo, err
+}
+
+// ValidateAndPrepareBatchWithPendingUpdates is same as ValidateAndPrepareBatch except that the updates of the preceding
+// block may still be in the process of being committed to the statedb. The validation proceeds against the committed
+// state if the transactions in the block do not read any of the pending updates and waits for the commit of the pending
+// updates to finish otherwise. As a result, the outcome of the validation is the same as if the preceding block was
+// fully committed. In addition, it returns true if a valid transaction in the block updates the validation parameters
+// of a key, in which case the endorsement policy evaluation of the subsequent blocks depends on the commit of this block
+func (p *CommitBatchPreparer) ValidateAndPrepareBatchWithPendingUpdates(blockAndPvtdata *ledger.BlockAndPvtData,
+	doMVCCValidation bool, pendingUpdates PendingUpdates) (*privacyenabledstate.UpdateBatch, []*TxStatInfo, bool, error) {
 	blk := blockAndPvtdata.Block
 	logger.Debugf("ValidateAndPrepareBatch() for block number = [%d]", blk.Header.Number)
 	var internalBlock *block
@@ -77,19 +89,32 @@ func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.Bl
 	var pvtUpdates *privacyenabledstate.PvtUpdateBatch
 	var err error
 
+	tracker := newPendingUpdatesTracker(pendingUpdates)
+	postOrderSimulatorProvider := p.postOrderSimulatorProvider
+	if tracker != nil {
+		postOrderSimulatorProvider = &waitingSimulatorProvider{postOrderSimulatorProvider, tracker}
+	}
+
 	logger.Debug("preprocessing ProtoBlock...")
 	if internalBlock, txsStatInfo, err = preprocessProtoBlock(
-		p.postOrderSimulatorProvider,
+		postOrderSimulatorProvider,
 		p.db.ValidateKeyValue,
 		blk,
 		doMVCCValidation,
 		p.customTxProcessors,
 	); err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
+	}
+
+	if tracker != nil && dependsOnUpdates(internalBlock, pendingUpdates.Batch()) {
+		logger.Debugf("Block [%d] reads the updates of the preceding block", blk.Header.Number)
+		if err = tracker.waitForCommit(); err != nil {
+			return nil, nil, false, err
+		}
 	}
 
 	if pubAndHashUpdates, err = p.validator.validateAndPrepareBatch(internalBlock, doMVCCValidation); err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
 	}
 	logger.Debug("validating rwset...")
 	if pvtUpdates, err = validateAndPreparePvtBatch(
@@ -99,7 +124,7 @@ func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.Bl
 		blockAndPvtdata.PvtData,
 		p.customTxProcessors,
 	); err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
 	}
 	logger.Debug("postprocessing ProtoBlock...")
 	postprocessProtoBlock(blk, internalBlock)
@@ -113,7 +138,7 @@ func (p *CommitBatchPreparer) ValidateAndPrepareBatch(blockAndPvtdata *ledger.Bl
 		PubUpdates:  pubAndHashUpdates.publicUpdates,
 		HashUpdates: pubAndHashUpdates.hashUpdates,
 		PvtUpdates:  pvtUpdates,
-	}, txsStatInfo, nil
+	}, txsStatInfo, updatesValidationParameters(internalBlock), nil
 }
 
 // validateAndPreparePvtBatch pulls out the private write-set for the transactions that are marked as valid
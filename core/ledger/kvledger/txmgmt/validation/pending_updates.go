@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+)
+
+// PendingUpdates represents the final updates of the preceding block while they are being committed to the statedb.
+// The validation of a block overlaps with such a commit only if the outcome of the validation does not depend on the
+// pending updates, i.e., none of the keys that the validation reads from the statedb is present in the pending updates
+type PendingUpdates interface {
+	// Batch returns the final updates of the preceding block
+	Batch() *privacyenabledstate.UpdateBatch
+	// WaitForCommit blocks until the updates are committed to the statedb
+	WaitForCommit() error
+}
+
+// pendingUpdatesTracker waits, at most once, for the commit of the pending updates to finish
+type pendingUpdatesTracker struct {
+	pendingUpdates PendingUpdates
+	once           sync.Once
+	err            error
+}
+
+func newPendingUpdatesTracker(pendingUpdates PendingUpdates) *pendingUpdatesTracker {
+	if pendingUpdates == nil {
+		return nil
+	}
+	return &pendingUpdatesTracker{pendingUpdates: pendingUpdates}
+}
+
+func (t *pendingUpdatesTracker) waitForCommit() error {
+	if t == nil {
+		return nil
+	}
+	t.once.Do(func() {
+		logger.Debug("Waiting for the pending updates of the preceding block to be committed to the statedb")
+		t.err = t.pendingUpdates.WaitForCommit()
+	})
+	return t.err
+}
+
+// waitingSimulatorProvider waits for the pending updates to be committed before handing out a tx simulator, as the
+// post order transactions are simulated against the latest state
+type waitingSimulatorProvider struct {
+	PostOrderSimulatorProvider
+	tracker *pendingUpdatesTracker
+}
+
+func (p *waitingSimulatorProvider) NewTxSimulator(txid string) (ledger.TxSimulator, error) {
+	if err := p.tracker.waitForCommit(); err != nil {
+		return nil, err
+	}
+	return p.PostOrderSimulatorProvider.NewTxSimulator(txid)
+}
+
+// dependsOnUpdates returns true if the validation of any transaction in the block reads a key that is present in the
+// given updates. Apart from the read set and the range queries, the validation reads the latest value or metadata of
+// each written key for merging it with the metadata or value in the write set. Hence, the write set is treated as read
+// set for this purpose. The validation status of the transactions is not considered, as it is not yet known
+func dependsOnUpdates(blk *block, updates *privacyenabledstate.UpdateBatch) bool {
+	for _, tx := range blk.txs {
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			ns := nsRWSet.NameSpace
+			kvRWSet := nsRWSet.KvRwSet
+			for _, kvRead := range kvRWSet.Reads {
+				if updates.PubUpdates.Exists(ns, kvRead.Key) {
+					return true
+				}
+			}
+			for _, kvWrite := range kvRWSet.Writes {
+				if updates.PubUpdates.Exists(ns, kvWrite.Key) {
+					return true
+				}
+			}
+			for _, kvMetadataWrite := range kvRWSet.MetadataWrites {
+				if updates.PubUpdates.Exists(ns, kvMetadataWrite.Key) {
+					return true
+				}
+			}
+			for _, rqi := range kvRWSet.RangeQueriesInfo {
+				if rangeContainsUpdates(updates.PubUpdates.UpdateBatch, ns, rqi.StartKey, rqi.EndKey, !rqi.ItrExhausted) {
+					return true
+				}
+			}
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				coll := collHashedRWSet.CollectionName
+				hashedRWSet := collHashedRWSet.HashedRwSet
+				for _, kvReadHash := range hashedRWSet.HashedReads {
+					if updates.HashUpdates.Contains(ns, coll, kvReadHash.KeyHash) {
+						return true
+					}
+				}
+				for _, kvWriteHash := range hashedRWSet.HashedWrites {
+					if updates.HashUpdates.Contains(ns, coll, kvWriteHash.KeyHash) {
+						return true
+					}
+				}
+				for _, kvMetadataWriteHash := range hashedRWSet.MetadataWrites {
+					if updates.HashUpdates.Contains(ns, coll, kvMetadataWriteHash.KeyHash) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// rangeContainsUpdates returns true if any key in the given range of the namespace is present in the updates
+func rangeContainsUpdates(updates *statedb.UpdateBatch, ns, startKey, endKey string, includeEndKey bool) bool {
+	if includeEndKey && endKey != "" && updates.Exists(ns, endKey) {
+		return true
+	}
+	itr := updates.GetRangeScanIterator(ns, startKey, endKey)
+	defer itr.Close()
+	result, err := itr.Next()
+	return err != nil || result != nil
+}
+
+// updatesValidationParameters returns true if any valid transaction in the block updates the metadata of a key,
+// which carries the key-level validation parameters consulted during the endorsement policy evaluation
+func updatesValidationParameters(blk *block) bool {
+	for _, tx := range blk.txs {
+		if tx.validationCode != peer.TxValidationCode_VALID {
+			continue
+		}
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			if len(nsRWSet.KvRwSet.MetadataWrites) > 0 {
+				return true
+			}
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				if len(collHashedRWSet.HashedRwSet.MetadataWrites) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependsOnUpdates(t *testing.T) {
+	updates := privacyenabledstate.NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(2, 0))
+	updates.PubUpdates.Delete("ns1", "key5", version.NewHeight(2, 1))
+	updates.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("pvtkey1"), []byte("value-hash"), version.NewHeight(2, 2))
+
+	rangeQuery := func(startKey, endKey string, itrExhausted bool) *rwsetutil.RWSetBuilder {
+		b := rwsetutil.NewRWSetBuilder()
+		b.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: startKey, EndKey: endKey, ItrExhausted: itrExhausted})
+		return b
+	}
+
+	testCases := []struct {
+		name            string
+		rwsetBuilder    func() *rwsetutil.RWSetBuilder
+		expectedOutcome bool
+	}{
+		{
+			name: "read-of-pending-key",
+			rwsetBuilder: func() *rwsetutil.RWSetBuilder {
+				b := rwsetutil.NewRWSetBuilder()
+				b.AddToReadSet("ns1", "key2", version.NewHeight(1, 0))
+				return b
+			},
+			expectedOutcome: true,
+		},
+		{
+			name: "read-of-other-key",
+			rwsetBuilder: func() *rwsetutil.RWSetBuilder {
+				b := rwsetutil.NewRWSetBuilder()
+				b.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+				b.AddToReadSet("ns2", "key2", version.NewHeight(1, 0))
+				return b
+			},
+			expectedOutcome: false,
+		},
+		{
+			name: "write-of-pending-key",
+			rwsetBuilder: func() *rwsetutil.RWSetBuilder {
+				b := rwsetutil.NewRWSetBuilder()
+				b.AddToWriteSet("ns1", "key5", []byte("value5"))
+				return b
+			},
+			expectedOutcome: true,
+		},
+		{
+			name: "metadata-write-of-pending-key",
+			rwsetBuilder: func() *rwsetutil.RWSetBuilder {
+				b := rwsetutil.NewRWSetBuilder()
+				b.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"metadata-key": []byte("metadata-value")})
+				return b
+			},
+			expectedOutcome: true,
+		},
+		{
+			name:            "range-query-covering-pending-key",
+			rwsetBuilder:    func() *rwsetutil.RWSetBuilder { return rangeQuery("key3", "key6", true) },
+			expectedOutcome: true,
+		},
+		{
+			name:            "exhausted-range-query-ending-at-pending-key",
+			rwsetBuilder:    func() *rwsetutil.RWSetBuilder { return rangeQuery("key3", "key5", true) },
+			expectedOutcome: false,
+		},
+		{
+			name:            "unexhausted-range-query-ending-at-pending-key",
+			rwsetBuilder:    func() *rwsetutil.RWSetBuilder { return rangeQuery("key3", "key5", false) },
+			expectedOutcome: true,
+		},
+		{
+			name:            "open-ended-range-query-after-pending-keys",
+			rwsetBuilder:    func() *rwsetutil.RWSetBuilder { return rangeQuery("key6", "", true) },
+			expectedOutcome: false,
+		},
+		{
+			name: "hashed-read-of-pending-key",
+			rwsetBuilder: func() *rwsetutil.RWSetBuilder {
+				b := rwsetutil.NewRWSetBuilder()
+				b.AddToHashedReadSet("ns1", "coll1", "pvtkey1", version.NewHeight(1, 0))
+				return b
+			},
+			expectedOutcome: true,
+		},
+		{
+			name: "hashed-write-of-other-key",
+			rwsetBuilder: func() *rwsetutil.RWSetBuilder {
+				b := rwsetutil.NewRWSetBuilder()
+				b.AddToPvtAndHashedWriteSet("ns1", "coll1", "pvtkey2", []byte("value"))
+				b.AddToPvtAndHashedWriteSet("ns1", "coll2", "pvtkey1", []byte("value"))
+				return b
+			},
+			expectedOutcome: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			blk := &block{num: 3}
+			for i, txRWSet := range getTestPubSimulationRWSet(t, tc.rwsetBuilder()) {
+				blk.txs = append(blk.txs, &transaction{indexInBlock: i, rwset: txRWSet})
+			}
+			require.Equal(t, tc.expectedOutcome, dependsOnUpdates(blk, updates))
+		})
+	}
+}
+
+func TestUpdatesValidationParameters(t *testing.T) {
+	metadataWriter := rwsetutil.NewRWSetBuilder()
+	metadataWriter.AddToMetadataWriteSet("ns1", "key1", map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")})
+	valueWriter := rwsetutil.NewRWSetBuilder()
+	valueWriter.AddToWriteSet("ns1", "key1", []byte("value1"))
+	txRWSets := getTestPubSimulationRWSet(t, metadataWriter, valueWriter)
+
+	blk := &block{
+		num: 1,
+		txs: []*transaction{
+			{indexInBlock: 0, rwset: txRWSets[0], validationCode: peer.TxValidationCode_VALID},
+			{indexInBlock: 1, rwset: txRWSets[1], validationCode: peer.TxValidationCode_VALID},
+		},
+	}
+	require.True(t, updatesValidationParameters(blk))
+
+	blk.txs[0].validationCode = peer.TxValidationCode_MVCC_READ_CONFLICT
+	require.False(t, updatesValidationParameters(blk))
+}
+
+func TestPendingUpdatesTracker(t *testing.T) {
+	require.Nil(t, newPendingUpdatesTracker(nil))
+	require.NoError(t, newPendingUpdatesTracker(nil).waitForCommit())
+
+	pendingUpdates := &testPendingUpdates{}
+	tracker := newPendingUpdatesTracker(pendingUpdates)
+	require.NoError(t, tracker.waitForCommit())
+	require.NoError(t, tracker.waitForCommit())
+	require.Equal(t, 1, pendingUpdates.waitCount)
+}
+
+type testPendingUpdates struct {
+	batch     *privacyenabledstate.UpdateBatch
+	waitCount int
+}
+
+func (p *testPendingUpdates) Batch() *privacyenabledstate.UpdateBatch {
+	return p.batch
+}
+
+func (p *testPendingUpdates) WaitForCommit() error {
+	p.waitCount++
+	return nil
+}
@@ -9,6 +9,7 @@ package ledger
 import (
 	"fmt"
 	"hash"
+	"net/http"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -30,6 +31,7 @@ type Initializer struct {
 	ChaincodeLifecycleEventProvider ChaincodeLifecycleEventProvider
 	MetricsProvider                 metrics.Provider
 	HealthCheckRegistry             HealthCheckRegistry
+	OperationsHandlerRegistry       OperationsHandlerRegistry
 	Config                          *Config
 	CustomTxProcessors              map[common.HeaderType]CustomTxProcessor
 	HashProvider                    HashProvider
@@ -47,16 +49,31 @@ type Config struct {
 	HistoryDBConfig *HistoryDBConfig
 	// SnapshotsConfig holds the configuration parameters for the snapshots.
 	SnapshotsConfig *SnapshotsConfig
+	// ExtensionIndexConfig holds the configuration parameters for the index of the block extension entries.
+	ExtensionIndexConfig *ExtensionIndexConfig
+	// StateCheckpointsConfig holds the configuration parameters for the state checkpoints.
+	StateCheckpointsConfig *StateCheckpointsConfig
+	// BlockRetentionConfig holds the configuration parameters for the pruning of the block files.
+	BlockRetentionConfig *BlockRetentionConfig
+	// BlockCompressionConfig holds the configuration parameters for the compression of the blocks in the block files.
+	BlockCompressionConfig *BlockCompressionConfig
+	// CommitPipelineConfig holds the configuration parameters for the pipelining of the block commits.
+	CommitPipelineConfig *CommitPipelineConfig
 }
 
 // StateDBConfig is a structure used to configure the state parameters for the ledger.
 type StateDBConfig struct {
 	// StateDatabase is the database to use for storing last known state.  The
-	// two supported options are "goleveldb" and "CouchDB".
+	// two built-in options are "goleveldb" and "CouchDB". In addition, a state
+	// database that is registered with the ledger can be used by specifying the
+	// name under which it is registered, for instance, "Remote".
 	StateDatabase string
 	// CouchDB is the configuration for CouchDB.  It is used when StateDatabase
 	// is set to "CouchDB".
 	CouchDB *CouchDBConfig
+	// PluginConfig is the configuration for a registered state database. It is
+	// passed as is to the registered state database.
+	PluginConfig map[string]interface{}
 }
 
 // CouchDBConfig is a structure used to configure a CouchInstance.
@@ -95,6 +112,12 @@ type CouchDBConfig struct {
 	// UserCacheSizeMBs needs to be a multiple of 32 MB. If it is not a multiple of 32 MB,
 	// the peer would round the size to the next multiple of 32 MB.
 	UserCacheSizeMBs int
+	// QueryPlanCheck determines how the rich queries that CouchDB would execute without a
+	// usable index are handled. It is one of "allow", "warn", and "reject". The query plan of
+	// each rich query is obtained from CouchDB and reported on the operations endpoint of the
+	// peer for all three values. Such queries are additionally logged for "warn" and fail for
+	// "reject". The check is disabled when empty.
+	QueryPlanCheck string
 }
 
 // PrivateDataConfig is a structure used to configure a private data storage provider.
@@ -129,12 +152,73 @@ type SnapshotsConfig struct {
 	RootDir string
 }
 
+// ExtensionIndexConfig is a structure used to configure the index of the block extension entries.
+type ExtensionIndexConfig struct {
+	// TypeURLs are the type URLs of the block extension entries that are indexed.
+	TypeURLs []string
+	// KeyFields maps some of the type URLs to the fields of the payloads under which their
+	// entries are indexed, given as paths of proto field names separated by dots, such as
+	// "timestamp.seconds". The payload type of such a type URL must be registered with
+	// blockextension.RegisterPayloadType. The entries of the other type URLs are indexed
+	// under their whole payload.
+	KeyFields map[string]string
+}
+
+// StateCheckpointsConfig is a structure used to configure the state checkpoints.
+type StateCheckpointsConfig struct {
+	// Interval is the number of blocks between two state checkpoints. A checkpoint is taken
+	// when a block with a number that is a multiple of the interval is committed.
+	// A zero interval disables the state checkpoints.
+	Interval uint64
+}
+
+// BlockRetentionConfig is a structure used to configure the pruning of the block files.
+// The block store prunes a block file only when all of its blocks fall outside of the
+// retained range, and the block file that is being written to is never pruned.
+type BlockRetentionConfig struct {
+	// RetainBlocks is the number of the most recent blocks that are retained in the block files.
+	// The older block files are pruned when a new block file is started.
+	// A zero value disables the pruning by the number of blocks.
+	RetainBlocks uint64
+	// PruneAtSnapshot, if true, prunes the block files that contain only the blocks
+	// up to the last block of a snapshot, once the snapshot is generated.
+	PruneAtSnapshot bool
+	// ArchiveDir is the directory to which the pruned block files are archived.
+	// The pruned block files are deleted without being archived if it is empty.
+	ArchiveDir string
+	// FetchArchived, if true, serves the requests for the pruned blocks from the archive.
+	// Otherwise, the requests for the pruned blocks fail with a BlockArchivedErr.
+	FetchArchived bool
+}
+
+// BlockCompressionConfig is a structure used to configure the compression of the blocks
+// that are added to the block files.
+type BlockCompressionConfig struct {
+	// Codec is the codec with which each block is compressed, one of "none" and "snappy".
+	// The blocks that are already present in the block files remain readable when it changes.
+	Codec string
+}
+
+// CommitPipelineConfig is a structure used to configure the pipelining of the block commits.
+type CommitPipelineConfig struct {
+	// Enabled lets the commit of the updates of a block to the state database proceed in the background
+	// once the block is added to the block store. This overlaps the state database commit with the validation
+	// of the next block, which waits for the commit only if it reads any of the updates. The blocks that update
+	// the chaincode definitions, the collection configurations, or the validation parameters of the keys
+	// are always committed synchronously.
+	Enabled bool
+}
+
 // PeerLedgerProvider provides handle to ledger instances
 type PeerLedgerProvider interface {
 	// Create creates a new ledger with the given genesis block.
 	// This function guarantees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from the files in the snapshot dir, which are generated by a
+	// peer via a snapshot request. The files are verified against the hashes recorded in the snapshot metadata.
+	// The ledger id is retrieved from the snapshot metadata and returned along with the ledger
+	CreateFromSnapshot(snapshotDir string) (PeerLedger, string, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
@@ -155,6 +239,9 @@ type PeerLedger interface {
 	GetBlockByHash(blockHash []byte) (*common.Block, error)
 	// GetBlockByTxID returns a block which contains a transaction
 	GetBlockByTxID(txID string) (*common.Block, error)
+	// GetBlockNumsByExtensionKey returns the numbers of the blocks whose extension carries
+	// an entry of the given type URL that is indexed under the given key
+	GetBlockNumsByExtensionKey(typeURL string, key []byte) ([]uint64, error)
 	// GetTxValidationCodeByTxID returns reason code of transaction validation
 	GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
 	// NewTxSimulator gives handle to a transaction simulator.
@@ -195,6 +282,26 @@ type PeerLedger interface {
 	//     missing info is recorded in the ledger (or)
 	// (3) the block is committed and does not contain any pvtData.
 	DoesPvtDataInfoExist(blockNum uint64) (bool, error)
+	// SubmitSnapshotRequest submits a request for generating a snapshot of the ledger when the block with
+	// the given number is committed. A block number 0 is interpreted as the last committed block, in which
+	// case the snapshot is generated right away. The request is persisted until the snapshot is generated
+	SubmitSnapshotRequest(blockNumber uint64) error
+	// CancelSnapshotRequest cancels a pending snapshot request for the given block number
+	CancelSnapshotRequest(blockNumber uint64) error
+	// PendingSnapshotRequests returns the block numbers of the pending snapshot requests in ascending order
+	PendingSnapshotRequests() ([]uint64, error)
+	// GetStateCheckpoint returns the state checkpoint that is taken when the block with the given number is committed.
+	// It returns nil if no checkpoint is taken for the block
+	GetStateCheckpoint(blockNumber uint64) (*StateCheckpoint, error)
+	// GetLatestStateCheckpoint returns the most recent state checkpoint. It returns nil if no checkpoint is taken yet
+	GetLatestStateCheckpoint() (*StateCheckpoint, error)
+}
+
+// StateCheckpoint is a commitment over the state of a ledger as of the commit of a block. As the commitment covers
+// the public data and the hashes of the private data, it is expected to be the same on all the peers of a channel
+type StateCheckpoint struct {
+	BlockNumber uint64
+	Commitment  []byte
 }
 
 // SimpleQueryExecutor encapsulates basic functions
@@ -271,6 +378,40 @@ type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in fabric-protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key that falls within the bounds specified
+	// in the options, in the order specified in the options. If a page size is specified in the options, the returned
+	// iterator returns at most those many results and the bookmark returned by the iterator can be passed in the
+	// options of a subsequent call to retrieve the next page.
+	// The returned QueryResultsIterator contains results of type *KeyModification which is defined in fabric-protos/ledger/queryresult.
+	GetHistoryForKeyWithOptions(namespace string, key string, options *HistoryQueryOptions) (commonledger.QueryResultsIterator, error)
+	// GetPrivateDataHistoryForKeyHash retrieves the history of the value hashes for a key hash of a private data collection,
+	// in the order of newest to oldest. This enables auditing the changes to private data without holding the data.
+	// The returned ResultsIterator contains results of type *KeyModification which is defined in fabric-protos/ledger/queryresult,
+	// with the field Value carrying the hash of the private value.
+	// For the blocks committed before this index was introduced, the history is available only after the history
+	// database is rebuilt (see `peer node rebuild-dbs`)
+	GetPrivateDataHistoryForKeyHash(namespace, collection string, keyHash []byte) (commonledger.ResultsIterator, error)
+}
+
+// HistoryQueryOptions bounds and orders the results of a history query. The zero value selects
+// the entire history of a key in the order of newest to oldest
+type HistoryQueryOptions struct {
+	// StartBlock is the lowest block number (inclusive) of the transactions to include
+	StartBlock uint64
+	// EndBlock is the highest block number (inclusive) of the transactions to include. It is used only if HasEndBlock is set
+	EndBlock uint64
+	// HasEndBlock indicates that EndBlock bounds the query, so that the block zero can be the highest block to include
+	HasEndBlock bool
+	// StartTime is the earliest timestamp (inclusive) of the transactions to include. Zero denotes no lower bound
+	StartTime time.Time
+	// EndTime is the latest timestamp (exclusive) of the transactions to include. Zero denotes no upper bound
+	EndTime time.Time
+	// Ascending orders the results from oldest to newest instead of the default newest to oldest
+	Ascending bool
+	// PageSize is the maximum number of results to return. Zero denotes no limit
+	PageSize int32
+	// Bookmark is the bookmark returned by the iterator of a previous query with the same options
+	Bookmark string
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -569,6 +710,17 @@ func (NotFoundInIndexErr) Error() string {
 	return "Entry not found in index"
 }
 
+// BlockArchivedErr is returned when a block, or the block of a transaction, is requested that
+// has been pruned from the block files and cannot be fetched from the archive
+type BlockArchivedErr struct {
+	BlockNum               uint64
+	FirstAvailableBlockNum uint64
+}
+
+func (e *BlockArchivedErr) Error() string {
+	return fmt.Sprintf("block [%d] is archived and pruned from the block store. First available block = [%d]", e.BlockNum, e.FirstAvailableBlockNum)
+}
+
 // CollConfigNotDefinedError is returned whenever an operation
 // is requested on a collection whose config has not been defined
 type CollConfigNotDefinedError struct {
@@ -655,6 +807,11 @@ type HealthCheckRegistry interface {
 	RegisterChecker(string, healthz.HealthChecker) error
 }
 
+// OperationsHandlerRegistry registers the handlers that the ledger components serve on the operations endpoint of the peer
+type OperationsHandlerRegistry interface {
+	RegisterHandler(pattern string, handler http.Handler)
+}
+
 // ChaincodeLifecycleEventListener interface enables ledger components (mainly, intended for statedb)
 // to be able to listen to chaincode lifecycle events. 'dbArtifactsTar' represents db specific artifacts
 // (such as index specs) packaged in a tar. Note that this interface is redefined here (in addition to
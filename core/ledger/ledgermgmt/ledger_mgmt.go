@@ -50,6 +50,7 @@ type Initializer struct {
 	ChaincodeLifecycleEventProvider ledger.ChaincodeLifecycleEventProvider
 	MetricsProvider                 metrics.Provider
 	HealthCheckRegistry             ledger.HealthCheckRegistry
+	OperationsHandlerRegistry       ledger.OperationsHandlerRegistry
 	Config                          *ledger.Config
 	HashProvider                    ledger.HashProvider
 	EbMetadataProvider              MetadataProvider
@@ -70,6 +71,7 @@ func NewLedgerMgr(initializer *Initializer) *LedgerMgr {
 			ChaincodeLifecycleEventProvider: initializer.ChaincodeLifecycleEventProvider,
 			MetricsProvider:                 initializer.MetricsProvider,
 			HealthCheckRegistry:             initializer.HealthCheckRegistry,
+			OperationsHandlerRegistry:       initializer.OperationsHandlerRegistry,
 			Config:                          initializer.Config,
 			CustomTxProcessors:              initializer.CustomTxProcessors,
 			HashProvider:                    initializer.HashProvider,
@@ -112,6 +114,25 @@ func (m *LedgerMgr) CreateLedger(id string, genesisBlock *common.Block) (ledger.
 	}, nil
 }
 
+// CreateLedgerFromSnapshot creates a new ledger from the snapshot files in the given dir.
+// The ledger id is retrieved from the snapshot metadata and returned along with the ledger
+func (m *LedgerMgr) CreateLedgerFromSnapshot(snapshotDir string) (ledger.PeerLedger, string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	logger.Infof("Creating ledger from snapshot at [%s]", snapshotDir)
+	l, id, err := m.ledgerProvider.CreateFromSnapshot(snapshotDir)
+	if err != nil {
+		return nil, "", err
+	}
+	m.openedLedgers[id] = l
+	logger.Infof("Created ledger [%s] from snapshot", id)
+	return &closableLedger{
+		ledgerMgr:  m,
+		id:         id,
+		PeerLedger: l,
+	}, id, nil
+}
+
 // OpenLedger returns a ledger for the given id
 func (m *LedgerMgr) OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening ledger with id = %s", id)
@@ -12,12 +12,14 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -78,6 +80,47 @@ func TestLedgerMgmt(t *testing.T) {
 	ledgerMgr.Close()
 }
 
+func TestCreateLedgerFromSnapshot(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "ledgermgmt")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	initializer, err := constructDefaultInitializer(filepath.Join(testDir, "source"))
+	require.NoError(t, err)
+	ledgerMgr := NewLedgerMgr(initializer)
+	defer ledgerMgr.Close()
+	gb, _ := test.MakeGenesisBlock("ledger1")
+	_, err = ledgerMgr.CreateLedger("ledger1", gb)
+	require.NoError(t, err)
+	l, err := ledgerMgr.getOpenedLedger("ledger1")
+	require.NoError(t, err)
+	require.NoError(t, l.SubmitSnapshotRequest(0))
+	snapshotDir := kvledger.SnapshotDirForLedgerHeight(initializer.Config.SnapshotsConfig.RootDir, "ledger1", 1)
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(snapshotDir)
+		return err == nil
+	}, time.Minute, 100*time.Millisecond)
+
+	destInitializer, err := constructDefaultInitializer(filepath.Join(testDir, "dest"))
+	require.NoError(t, err)
+	destLedgerMgr := NewLedgerMgr(destInitializer)
+	defer destLedgerMgr.Close()
+	destLedger, ledgerID, err := destLedgerMgr.CreateLedgerFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	require.Equal(t, "ledger1", ledgerID)
+	bcInfo, err := destLedger.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), bcInfo.Height)
+
+	ids, err := destLedgerMgr.GetLedgerIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{"ledger1"}, ids)
+	_, err = destLedgerMgr.OpenLedger("ledger1")
+	require.Equal(t, ErrLedgerAlreadyOpened, err)
+	_, _, err = destLedgerMgr.CreateLedgerFromSnapshot(snapshotDir)
+	require.Equal(t, kvledger.ErrLedgerIDExists, err)
+}
+
 func TestChaincodeInfoProvider(t *testing.T) {
 	testDir, err := ioutil.TempDir("", "ledgermgmt")
 	if err != nil {
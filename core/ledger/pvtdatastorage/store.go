@@ -152,6 +152,12 @@ func (p *Provider) OpenStore(ledgerid string) (*Store, error) {
 	return s, nil
 }
 
+// Drop drops the data of the store for the given ledger. The store is expected to be closed
+// before this function is invoked. It is not an error if the store does not exist
+func (p *Provider) Drop(ledgerid string) error {
+	return p.dbProvider.GetDBHandle(ledgerid).DeleteAll()
+}
+
 // Close closes the store
 func (p *Provider) Close() {
 	p.dbProvider.Close()
@@ -202,6 +208,98 @@ func (s *Store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 	s.btlPolicy = btlPolicy
 }
 
+// InitLastCommittedBlock sets the last committed block of an empty store. This is used for a ledger that is
+// created from a snapshot, so that the store expects the private data of the block next to the last block in
+// the snapshot. The private data of the blocks up to the last block in the snapshot is not present in the store
+func (s *Store) InitLastCommittedBlock(blockNum uint64) error {
+	if !s.isEmpty {
+		return &ErrIllegalCall{"The private data store is not empty. InitLastCommittedBlock() function call is not allowed"}
+	}
+	batch := s.db.NewUpdateBatch()
+	batch.Put(lastCommittedBlkkey, encodeLastCommittedBlockVal(blockNum))
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	s.isEmpty = false
+	s.lastCommittedBlock = blockNum
+	logger.Debugf("InitLastCommittedBlock set to block [%d]", blockNum)
+	return nil
+}
+
+// CommitMissingPvtdataOfSnapshot records, as missing, the private data of the given blocks that precede the
+// snapshot from which the ledger is created, along with their expiry as per the BTL policy. The snapshot carries
+// only the hashes of the private data, hence the eligible missing private data is then fetched by the reconciler,
+// and the entries are purged at their expiry like the entries of the committed blocks. The function can be called
+// more than once, for instance for one collection at a time, as the entries of the calls are merged
+func (s *Store) CommitMissingPvtdataOfSnapshot(missingPvtData map[uint64]ledger.TxMissingPvtDataMap) error {
+	batch := s.db.NewUpdateBatch()
+	for blkNum, txMissingPvtData := range missingPvtData {
+		if s.isEmpty || blkNum > s.lastCommittedBlock {
+			return &ErrIllegalArgs{fmt.Sprintf("Missing private data of block [%d] is not preceding the last committed block", blkNum)}
+		}
+		storeEntries, err := prepareStoreEntries(blkNum, nil, s.btlPolicy, txMissingPvtData)
+		if err != nil {
+			return err
+		}
+		for _, expiryEntry := range storeEntries.expiryEntries {
+			key := encodeExpiryKey(expiryEntry.key)
+			existingVal, err := s.db.Get(key)
+			if err != nil {
+				return err
+			}
+			if existingVal != nil {
+				existingData, err := decodeExpiryValue(existingVal)
+				if err != nil {
+					return err
+				}
+				for ns, colls := range expiryEntry.value.Map {
+					for coll := range colls.MissingDataMap {
+						existingData.addMissingData(ns, coll)
+					}
+				}
+				expiryEntry.value = existingData
+			}
+			val, err := encodeExpiryValue(expiryEntry.value)
+			if err != nil {
+				return err
+			}
+			batch.Put(key, val)
+		}
+		if err := s.addMissingDataEntries(batch, storeEntries.elgMissingDataEntries, encodeElgPrioMissingDataKey); err != nil {
+			return err
+		}
+		if err := s.addMissingDataEntries(batch, storeEntries.inelgMissingDataEntries, encodeInelgMissingDataKey); err != nil {
+			return err
+		}
+	}
+	return s.db.WriteBatch(batch, true)
+}
+
+// addMissingDataEntries adds the given missing data entries to the batch, merged with the existing ones
+func (s *Store) addMissingDataEntries(batch *leveldbhelper.UpdateBatch, entries map[missingDataKey]*bitset.BitSet,
+	encodeKey func(*missingDataKey) []byte) error {
+	for missingDataKey, missingDataValue := range entries {
+		key := encodeKey(&missingDataKey)
+		existingVal, err := s.db.Get(key)
+		if err != nil {
+			return err
+		}
+		if existingVal != nil {
+			existingBitmap, err := decodeMissingDataValue(existingVal)
+			if err != nil {
+				return err
+			}
+			missingDataValue.InPlaceUnion(existingBitmap)
+		}
+		val, err := encodeMissingDataValue(missingDataValue)
+		if err != nil {
+			return err
+		}
+		batch.Put(key, val)
+	}
+	return nil
+}
+
 // Commit commits the pvt data as well as both the eligible and ineligible
 // missing private data --- `eligible` denotes that the missing private data belongs to a collection
 // for which this peer is a member; `ineligible` denotes that the missing private data belong to a
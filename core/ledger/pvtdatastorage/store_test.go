@@ -34,6 +34,94 @@ func TestEmptyStore(t *testing.T) {
 	require.True(t, store.isEmpty)
 }
 
+func TestInitLastCommittedBlock(t *testing.T) {
+	env := NewTestStoreEnv(t, "TestInitLastCommittedBlock", nil, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+	require.NoError(t, store.InitLastCommittedBlock(5))
+	height, err := store.LastCommittedBlockHeight()
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), height)
+
+	_, ok := store.InitLastCommittedBlock(6).(*ErrIllegalCall)
+	require.True(t, ok)
+	_, ok = store.Commit(5, nil, nil).(*ErrIllegalArgs)
+	require.True(t, ok)
+	require.NoError(t, store.Commit(6, nil, nil))
+
+	// the state is persisted
+	env.CloseAndReopen()
+	height, err = env.TestStore.LastCommittedBlockHeight()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), height)
+}
+
+func TestCommitMissingPvtdataOfSnapshot(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 3,
+			{"ns-1", "coll-2"}: 0,
+			{"ns-2", "coll-1"}: 3,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestCommitMissingPvtdataOfSnapshot", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	s := env.TestStore
+
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(1, "ns-1", "coll-1", true)
+	_, ok := s.CommitMissingPvtdataOfSnapshot(map[uint64]ledger.TxMissingPvtDataMap{1: blk1MissingData}).(*ErrIllegalArgs)
+	require.True(t, ok)
+
+	require.NoError(t, s.InitLastCommittedBlock(2))
+	_, ok = s.CommitMissingPvtdataOfSnapshot(map[uint64]ledger.TxMissingPvtDataMap{3: blk1MissingData}).(*ErrIllegalArgs)
+	require.True(t, ok)
+
+	// the missing data is recorded one collection at a time
+	blk1MissingData.Add(2, "ns-1", "coll-1", true)
+	blk2MissingData := make(ledger.TxMissingPvtDataMap)
+	blk2MissingData.Add(0, "ns-1", "coll-1", true)
+	require.NoError(t, s.CommitMissingPvtdataOfSnapshot(map[uint64]ledger.TxMissingPvtDataMap{1: blk1MissingData, 2: blk2MissingData}))
+	blk1MissingData = make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(1, "ns-1", "coll-2", true)
+	require.NoError(t, s.CommitMissingPvtdataOfSnapshot(map[uint64]ledger.TxMissingPvtDataMap{1: blk1MissingData}))
+	blk1MissingData = make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(3, "ns-2", "coll-1", false)
+	require.NoError(t, s.CommitMissingPvtdataOfSnapshot(map[uint64]ledger.TxMissingPvtDataMap{1: blk1MissingData}))
+
+	// the reconciler is asked for the eligible missing data
+	missingDataInfo, err := s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	require.NoError(t, err)
+	expectedMissingDataInfo := make(ledger.MissingPvtDataInfo)
+	expectedMissingDataInfo.Add(1, 1, "ns-1", "coll-1")
+	expectedMissingDataInfo.Add(1, 1, "ns-1", "coll-2")
+	expectedMissingDataInfo.Add(1, 2, "ns-1", "coll-1")
+	expectedMissingDataInfo.Add(2, 0, "ns-1", "coll-1")
+	require.Equal(t, expectedMissingDataInfo, missingDataInfo)
+
+	ns1Coll1Blk1 := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}}
+	ns1Coll2Blk1 := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}}
+	ns2Coll1Blk1 := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-2", coll: "coll-1", blkNum: 1}}
+	ns1Coll1Blk2 := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 2}}
+	require.True(t, testInelgMissingDataKeyExists(t, s, ns2Coll1Blk1))
+
+	// the entries of block 1 of ns-1:coll-1 and ns-2:coll-1 expire at block 5 and the entry of block 2 at
+	// block 6, while the purger runs every second block
+	for blkNum := uint64(3); blkNum <= 5; blkNum++ {
+		require.NoError(t, s.Commit(blkNum, nil, nil))
+	}
+	testWaitForPurgerRoutineToFinish(s)
+	require.True(t, testElgPrioMissingDataKeyExists(t, s, ns1Coll1Blk1))
+	require.True(t, testElgPrioMissingDataKeyExists(t, s, ns1Coll1Blk2))
+	require.NoError(t, s.Commit(6, nil, nil))
+	testWaitForPurgerRoutineToFinish(s)
+	require.False(t, testElgPrioMissingDataKeyExists(t, s, ns1Coll1Blk1))
+	require.False(t, testInelgMissingDataKeyExists(t, s, ns2Coll1Blk1))
+	require.False(t, testElgPrioMissingDataKeyExists(t, s, ns1Coll1Blk2))
+	// ns-1:coll-2 never expires
+	require.True(t, testElgPrioMissingDataKeyExists(t, s, ns1Coll2Blk1))
+}
+
 func TestStoreBasicCommitAndRetrieval(t *testing.T) {
 	btlPolicy := btltestutil.SampleBTLPolicy(
 		map[[2]string]uint64{
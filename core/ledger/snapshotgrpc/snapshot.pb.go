@@ -0,0 +1,421 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: snapshot.proto
+
+package snapshotgrpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// SnapshotRequest contains information for a submit or cancel snapshot request
+type SnapshotRequest struct {
+	// The signature header that contains creator identity and nonce
+	SignatureHeader *common.SignatureHeader `protobuf:"bytes,1,opt,name=signature_header,json=signatureHeader,proto3" json:"signature_header,omitempty"`
+	// The channel ID
+	ChannelId string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// The block number at which the snapshot is generated, 0 denotes the last committed block
+	BlockNumber          uint64   `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c8aab8e59648e0b, []int{0}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+func (m *SnapshotRequest) GetSignatureHeader() *common.SignatureHeader {
+	if m != nil {
+		return m.SignatureHeader
+	}
+	return nil
+}
+
+func (m *SnapshotRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *SnapshotRequest) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// SnapshotQuery contains information for a query snapshot request
+type SnapshotQuery struct {
+	// The signature header that contains creator identity and nonce
+	SignatureHeader *common.SignatureHeader `protobuf:"bytes,1,opt,name=signature_header,json=signatureHeader,proto3" json:"signature_header,omitempty"`
+	// The channel ID
+	ChannelId            string   `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotQuery) Reset()         { *m = SnapshotQuery{} }
+func (m *SnapshotQuery) String() string { return proto.CompactTextString(m) }
+func (*SnapshotQuery) ProtoMessage()    {}
+func (*SnapshotQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c8aab8e59648e0b, []int{1}
+}
+
+func (m *SnapshotQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotQuery.Unmarshal(m, b)
+}
+func (m *SnapshotQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotQuery.Marshal(b, m, deterministic)
+}
+func (m *SnapshotQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotQuery.Merge(m, src)
+}
+func (m *SnapshotQuery) XXX_Size() int {
+	return xxx_messageInfo_SnapshotQuery.Size(m)
+}
+func (m *SnapshotQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotQuery proto.InternalMessageInfo
+
+func (m *SnapshotQuery) GetSignatureHeader() *common.SignatureHeader {
+	if m != nil {
+		return m.SignatureHeader
+	}
+	return nil
+}
+
+func (m *SnapshotQuery) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// SignedSnapshotRequest contains marshalled request bytes and signature
+type SignedSnapshotRequest struct {
+	// The bytes of SnapshotRequest or SnapshotQuery
+	Request []byte `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Signature over request bytes; this signature is to be verified against the client identity
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedSnapshotRequest) Reset()         { *m = SignedSnapshotRequest{} }
+func (m *SignedSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SignedSnapshotRequest) ProtoMessage()    {}
+func (*SignedSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c8aab8e59648e0b, []int{2}
+}
+
+func (m *SignedSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedSnapshotRequest.Unmarshal(m, b)
+}
+func (m *SignedSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SignedSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedSnapshotRequest.Merge(m, src)
+}
+func (m *SignedSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SignedSnapshotRequest.Size(m)
+}
+func (m *SignedSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedSnapshotRequest proto.InternalMessageInfo
+
+func (m *SignedSnapshotRequest) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SignedSnapshotRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// QueryPendingSnapshotsResponse specifies the response payload of a query pending snapshots request
+type QueryPendingSnapshotsResponse struct {
+	BlockNumbers         []uint64 `protobuf:"varint,1,rep,packed,name=block_numbers,json=blockNumbers,proto3" json:"block_numbers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryPendingSnapshotsResponse) Reset()         { *m = QueryPendingSnapshotsResponse{} }
+func (m *QueryPendingSnapshotsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryPendingSnapshotsResponse) ProtoMessage()    {}
+func (*QueryPendingSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c8aab8e59648e0b, []int{3}
+}
+
+func (m *QueryPendingSnapshotsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryPendingSnapshotsResponse.Unmarshal(m, b)
+}
+func (m *QueryPendingSnapshotsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryPendingSnapshotsResponse.Marshal(b, m, deterministic)
+}
+func (m *QueryPendingSnapshotsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryPendingSnapshotsResponse.Merge(m, src)
+}
+func (m *QueryPendingSnapshotsResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryPendingSnapshotsResponse.Size(m)
+}
+func (m *QueryPendingSnapshotsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryPendingSnapshotsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryPendingSnapshotsResponse proto.InternalMessageInfo
+
+func (m *QueryPendingSnapshotsResponse) GetBlockNumbers() []uint64 {
+	if m != nil {
+		return m.BlockNumbers
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*SnapshotRequest)(nil), "snapshotgrpc.SnapshotRequest")
+	proto.RegisterType((*SnapshotQuery)(nil), "snapshotgrpc.SnapshotQuery")
+	proto.RegisterType((*SignedSnapshotRequest)(nil), "snapshotgrpc.SignedSnapshotRequest")
+	proto.RegisterType((*QueryPendingSnapshotsResponse)(nil), "snapshotgrpc.QueryPendingSnapshotsResponse")
+}
+
+func init() { proto.RegisterFile("snapshot.proto", fileDescriptor_0c8aab8e59648e0b) }
+
+var fileDescriptor_0c8aab8e59648e0b = []byte{
+	// 383 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x92, 0xcf, 0x8b, 0x9b, 0x40,
+	0x14, 0xc7, 0x63, 0x12, 0xd2, 0xe4, 0xc5, 0x34, 0x65, 0x4a, 0x5b, 0x49, 0x1b, 0xb0, 0xe6, 0x22,
+	0x14, 0x14, 0x52, 0x28, 0x3d, 0xa7, 0x2d, 0x69, 0x2e, 0xfd, 0x31, 0xb9, 0xf5, 0x12, 0x74, 0x7c,
+	0x51, 0xa9, 0xce, 0xd8, 0x19, 0x3d, 0xe4, 0xdc, 0x3f, 0xa2, 0xff, 0x6e, 0xc9, 0xa8, 0xbb, 0x66,
+	0x59, 0x96, 0x85, 0x85, 0x3d, 0xe9, 0xfb, 0xbe, 0xf1, 0x33, 0x5f, 0xbf, 0xef, 0xc1, 0x53, 0xc5,
+	0x83, 0x42, 0x25, 0xa2, 0xf4, 0x0a, 0x29, 0x4a, 0x41, 0xcc, 0xb6, 0x8e, 0x65, 0xc1, 0x16, 0xcf,
+	0x99, 0xc8, 0x73, 0xc1, 0xfd, 0xfa, 0x51, 0x1f, 0x59, 0xbc, 0x8e, 0x85, 0x88, 0x33, 0xf4, 0x75,
+	0x15, 0x56, 0x47, 0x1f, 0xf3, 0xa2, 0x3c, 0xd5, 0x4d, 0xe7, 0x9f, 0x01, 0xf3, 0x7d, 0x83, 0xa0,
+	0xf8, 0xa7, 0x42, 0x55, 0x92, 0x0d, 0x3c, 0x53, 0x69, 0xcc, 0x83, 0xb2, 0x92, 0x78, 0x48, 0x30,
+	0x88, 0x50, 0x5a, 0x86, 0x6d, 0xb8, 0xd3, 0xf5, 0x2b, 0xaf, 0x21, 0xef, 0xdb, 0xfe, 0x57, 0xdd,
+	0xa6, 0x73, 0x75, 0x29, 0x90, 0x25, 0x00, 0x4b, 0x02, 0xce, 0x31, 0x3b, 0xa4, 0x91, 0xd5, 0xb7,
+	0x0d, 0x77, 0x42, 0x27, 0x8d, 0xb2, 0x8b, 0xc8, 0x5b, 0x30, 0xc3, 0x4c, 0xb0, 0xdf, 0x07, 0x5e,
+	0xe5, 0x21, 0x4a, 0x6b, 0x60, 0x1b, 0xee, 0x90, 0x4e, 0xb5, 0xf6, 0x4d, 0x4b, 0x8e, 0x84, 0x59,
+	0x6b, 0xec, 0x67, 0x85, 0xf2, 0xf4, 0x08, 0xb6, 0x9c, 0xef, 0xf0, 0xe2, 0x8c, 0xc0, 0xe8, 0x66,
+	0x24, 0x16, 0x3c, 0x91, 0xf5, 0xab, 0xbe, 0xd2, 0xa4, 0x6d, 0x49, 0xde, 0xc0, 0xe4, 0xea, 0x12,
+	0x0d, 0x34, 0xe9, 0xb5, 0xe0, 0x7c, 0x86, 0xa5, 0x36, 0xff, 0x03, 0x79, 0x94, 0xf2, 0xb8, 0xc5,
+	0x2a, 0x8a, 0xaa, 0x10, 0x5c, 0x21, 0x59, 0xc1, 0xac, 0x1b, 0x84, 0xb2, 0x0c, 0x7b, 0xe0, 0x0e,
+	0xa9, 0xd9, 0x49, 0x42, 0xad, 0xff, 0xf6, 0x61, 0xdc, 0x7e, 0x4a, 0x76, 0x30, 0xde, 0x22, 0x47,
+	0x19, 0x94, 0x48, 0x56, 0x5e, 0x77, 0xfc, 0xde, 0xad, 0xde, 0x17, 0x2f, 0xbd, 0x7a, 0x01, 0xbc,
+	0x76, 0x01, 0xbc, 0x2f, 0xe7, 0x05, 0x70, 0x7a, 0x64, 0x0b, 0xa3, 0x4f, 0x01, 0x67, 0x98, 0x3d,
+	0x14, 0xc4, 0x60, 0xd6, 0xfd, 0x4d, 0x75, 0x3f, 0xde, 0xbb, 0xcb, 0x43, 0x77, 0x06, 0xe5, 0xf4,
+	0x36, 0x1f, 0x7f, 0x7d, 0x88, 0xd3, 0x32, 0xa9, 0xc2, 0xf3, 0xb4, 0xfd, 0xe4, 0x54, 0xa0, 0xcc,
+	0x30, 0x8a, 0x51, 0xfa, 0xc7, 0x20, 0x94, 0x29, 0xf3, 0x99, 0x90, 0xe8, 0x37, 0x52, 0x97, 0x1c,
+	0x8e, 0xb4, 0xe1, 0xf7, 0xff, 0x07, 0x00, 0x55, 0xbf, 0x21, 0x11, 0x3d, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SnapshotClient is the client API for Snapshot service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SnapshotClient interface {
+	// Generate a snapshot request. SignedSnapshotRequest contains marshalled bytes for SnapshotRequest
+	Generate(ctx context.Context, in *SignedSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Cancel a snapshot request. SignedSnapshotRequest contains marshalled bytes for SnapshotRequest
+	Cancel(ctx context.Context, in *SignedSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Query pending snapshots query. SignedSnapshotRequest contains marshalled bytes for SnapshotQuery
+	QueryPendings(ctx context.Context, in *SignedSnapshotRequest, opts ...grpc.CallOption) (*QueryPendingSnapshotsResponse, error)
+}
+
+type snapshotClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSnapshotClient(cc grpc.ClientConnInterface) SnapshotClient {
+	return &snapshotClient{cc}
+}
+
+func (c *snapshotClient) Generate(ctx context.Context, in *SignedSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/snapshotgrpc.Snapshot/Generate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snapshotClient) Cancel(ctx context.Context, in *SignedSnapshotRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/snapshotgrpc.Snapshot/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snapshotClient) QueryPendings(ctx context.Context, in *SignedSnapshotRequest, opts ...grpc.CallOption) (*QueryPendingSnapshotsResponse, error) {
+	out := new(QueryPendingSnapshotsResponse)
+	err := c.cc.Invoke(ctx, "/snapshotgrpc.Snapshot/QueryPendings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SnapshotServer is the server API for Snapshot service.
+type SnapshotServer interface {
+	// Generate a snapshot request. SignedSnapshotRequest contains marshalled bytes for SnapshotRequest
+	Generate(context.Context, *SignedSnapshotRequest) (*empty.Empty, error)
+	// Cancel a snapshot request. SignedSnapshotRequest contains marshalled bytes for SnapshotRequest
+	Cancel(context.Context, *SignedSnapshotRequest) (*empty.Empty, error)
+	// Query pending snapshots query. SignedSnapshotRequest contains marshalled bytes for SnapshotQuery
+	QueryPendings(context.Context, *SignedSnapshotRequest) (*QueryPendingSnapshotsResponse, error)
+}
+
+// UnimplementedSnapshotServer can be embedded to have forward compatible implementations.
+type UnimplementedSnapshotServer struct {
+}
+
+func (*UnimplementedSnapshotServer) Generate(ctx context.Context, req *SignedSnapshotRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (*UnimplementedSnapshotServer) Cancel(ctx context.Context, req *SignedSnapshotRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (*UnimplementedSnapshotServer) QueryPendings(ctx context.Context, req *SignedSnapshotRequest) (*QueryPendingSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryPendings not implemented")
+}
+
+func RegisterSnapshotServer(s *grpc.Server, srv SnapshotServer) {
+	s.RegisterService(&_Snapshot_serviceDesc, srv)
+}
+
+func _Snapshot_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snapshotgrpc.Snapshot/Generate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotServer).Generate(ctx, req.(*SignedSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snapshot_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snapshotgrpc.Snapshot/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotServer).Cancel(ctx, req.(*SignedSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snapshot_QueryPendings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotServer).QueryPendings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snapshotgrpc.Snapshot/QueryPendings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotServer).QueryPendings(ctx, req.(*SignedSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Snapshot_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "snapshotgrpc.Snapshot",
+	HandlerType: (*SnapshotServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _Snapshot_Generate_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Snapshot_Cancel_Handler,
+		},
+		{
+			MethodName: "QueryPendings",
+			Handler:    _Snapshot_QueryPendings_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "snapshot.proto",
+}
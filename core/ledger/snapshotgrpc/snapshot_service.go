@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshotgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("snapshotgrpc")
+
+// LedgerGetter gets the PeerLedger associated with a channel
+type LedgerGetter interface {
+	GetLedger(cid string) ledger.PeerLedger
+}
+
+// ACLProvider checks the access control of a resource
+type ACLProvider interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// SnapshotService implements the SnapshotServer admin service through which the snapshots of the
+// channel ledgers are requested
+type SnapshotService struct {
+	LedgerGetter LedgerGetter
+	ACLProvider  ACLProvider
+}
+
+// Generate submits a request for generating a snapshot of a channel ledger at a block number
+func (s *SnapshotService) Generate(ctx context.Context, signedRequest *SignedSnapshotRequest) (*empty.Empty, error) {
+	request := &SnapshotRequest{}
+	if err := proto.Unmarshal(signedRequest.Request, request); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot request")
+	}
+	if err := s.checkACL(resources.Snapshot_submitrequest, request.SignatureHeader, signedRequest); err != nil {
+		return nil, err
+	}
+	lgr, err := s.getLedger(request.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	if err := lgr.SubmitSnapshotRequest(request.BlockNumber); err != nil {
+		return nil, errors.WithMessage(err, "failed to submit snapshot request")
+	}
+	logger.Infof("Submitted snapshot request for block number [%d] on channel [%s]", request.BlockNumber, request.ChannelId)
+	return &empty.Empty{}, nil
+}
+
+// Cancel cancels a pending request for generating a snapshot of a channel ledger at a block number
+func (s *SnapshotService) Cancel(ctx context.Context, signedRequest *SignedSnapshotRequest) (*empty.Empty, error) {
+	request := &SnapshotRequest{}
+	if err := proto.Unmarshal(signedRequest.Request, request); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot request")
+	}
+	if err := s.checkACL(resources.Snapshot_cancelrequest, request.SignatureHeader, signedRequest); err != nil {
+		return nil, err
+	}
+	lgr, err := s.getLedger(request.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	if err := lgr.CancelSnapshotRequest(request.BlockNumber); err != nil {
+		return nil, errors.WithMessage(err, "failed to cancel snapshot request")
+	}
+	logger.Infof("Cancelled snapshot request for block number [%d] on channel [%s]", request.BlockNumber, request.ChannelId)
+	return &empty.Empty{}, nil
+}
+
+// QueryPendings returns the block numbers of the pending snapshot requests of a channel ledger
+func (s *SnapshotService) QueryPendings(ctx context.Context, signedRequest *SignedSnapshotRequest) (*QueryPendingSnapshotsResponse, error) {
+	query := &SnapshotQuery{}
+	if err := proto.Unmarshal(signedRequest.Request, query); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot query")
+	}
+	if err := s.checkACL(resources.Snapshot_listpending, query.SignatureHeader, signedRequest); err != nil {
+		return nil, err
+	}
+	lgr, err := s.getLedger(query.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	blockNumbers, err := lgr.PendingSnapshotRequests()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list pending snapshot requests")
+	}
+	return &QueryPendingSnapshotsResponse{BlockNumbers: blockNumbers}, nil
+}
+
+func (s *SnapshotService) getLedger(channelID string) (ledger.PeerLedger, error) {
+	if channelID == "" {
+		return nil, errors.New("missing channel ID")
+	}
+	lgr := s.LedgerGetter.GetLedger(channelID)
+	if lgr == nil {
+		return nil, errors.Errorf("cannot find ledger for channel %s", channelID)
+	}
+	return lgr, nil
+}
+
+func (s *SnapshotService) checkACL(resName string, signatureHdr *common.SignatureHeader, signedRequest *SignedSnapshotRequest) error {
+	if signatureHdr == nil {
+		return errors.New("missing signature header")
+	}
+	expirationTime := crypto.ExpiresAt(signatureHdr.Creator)
+	if !expirationTime.IsZero() && time.Now().After(expirationTime) {
+		return errors.New("client identity expired")
+	}
+	return s.ACLProvider.CheckACL(
+		resName,
+		"",
+		[]*protoutil.SignedData{{
+			Identity:  signatureHdr.Creator,
+			Data:      signedRequest.Request,
+			Signature: signedRequest.Signature,
+		}},
+	)
+}
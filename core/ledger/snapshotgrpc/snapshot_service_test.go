@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshotgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate counterfeiter -o mock/ledger_getter.go -fake-name LedgerGetter . ledgerGetter
+type ledgerGetter interface {
+	LedgerGetter
+}
+
+//go:generate counterfeiter -o mock/acl_provider.go -fake-name ACLProvider . aclProvider
+type aclProvider interface {
+	ACLProvider
+}
+
+//go:generate counterfeiter -o mock/peer_ledger.go -fake-name PeerLedger . peerLedger
+type peerLedger interface {
+	ledger.PeerLedger
+}
+
+func TestSnapshot(t *testing.T) {
+	fakeLedger := &mock.PeerLedger{}
+	fakeLedger.PendingSnapshotRequestsReturns([]uint64{10, 20}, nil)
+	fakeLedgerGetter := &mock.LedgerGetter{}
+	fakeLedgerGetter.GetLedgerReturns(fakeLedger)
+	fakeACLProvider := &mock.ACLProvider{}
+	snapshotSvc := &SnapshotService{LedgerGetter: fakeLedgerGetter, ACLProvider: fakeACLProvider}
+
+	signatureHdr := &common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")}
+	request := &SnapshotRequest{SignatureHeader: signatureHdr, ChannelId: "testchannel", BlockNumber: 100}
+	signedRequest := &SignedSnapshotRequest{Request: protoutil.MarshalOrPanic(request), Signature: []byte("signature")}
+
+	_, err := snapshotSvc.Generate(context.Background(), signedRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, fakeLedger.SubmitSnapshotRequestCallCount())
+	require.Equal(t, uint64(100), fakeLedger.SubmitSnapshotRequestArgsForCall(0))
+	require.Equal(t, "testchannel", fakeLedgerGetter.GetLedgerArgsForCall(0))
+	resName, channelID, idinfo := fakeACLProvider.CheckACLArgsForCall(0)
+	require.Equal(t, resources.Snapshot_submitrequest, resName)
+	require.Equal(t, "", channelID)
+	require.Equal(t, []*protoutil.SignedData{{
+		Identity:  []byte("creator"),
+		Data:      signedRequest.Request,
+		Signature: []byte("signature"),
+	}}, idinfo)
+
+	_, err = snapshotSvc.Cancel(context.Background(), signedRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, fakeLedger.CancelSnapshotRequestCallCount())
+	require.Equal(t, uint64(100), fakeLedger.CancelSnapshotRequestArgsForCall(0))
+	resName, _, _ = fakeACLProvider.CheckACLArgsForCall(1)
+	require.Equal(t, resources.Snapshot_cancelrequest, resName)
+
+	query := &SnapshotQuery{SignatureHeader: signatureHdr, ChannelId: "testchannel"}
+	signedQuery := &SignedSnapshotRequest{Request: protoutil.MarshalOrPanic(query), Signature: []byte("signature")}
+	resp, err := snapshotSvc.QueryPendings(context.Background(), signedQuery)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 20}, resp.BlockNumbers)
+	resName, _, _ = fakeACLProvider.CheckACLArgsForCall(2)
+	require.Equal(t, resources.Snapshot_listpending, resName)
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	fakeLedger := &mock.PeerLedger{}
+	fakeLedgerGetter := &mock.LedgerGetter{}
+	fakeLedgerGetter.GetLedgerReturns(fakeLedger)
+	fakeACLProvider := &mock.ACLProvider{}
+	snapshotSvc := &SnapshotService{LedgerGetter: fakeLedgerGetter, ACLProvider: fakeACLProvider}
+
+	signatureHdr := &common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")}
+	newSignedRequest := func(request proto.Message) *SignedSnapshotRequest {
+		return &SignedSnapshotRequest{Request: protoutil.MarshalOrPanic(request), Signature: []byte("signature")}
+	}
+	validRequest := newSignedRequest(&SnapshotRequest{SignatureHeader: signatureHdr, ChannelId: "testchannel"})
+	validQuery := newSignedRequest(&SnapshotQuery{SignatureHeader: signatureHdr, ChannelId: "testchannel"})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		invalidRequest := &SignedSnapshotRequest{Request: []byte("garbage")}
+		_, err := snapshotSvc.Generate(context.Background(), invalidRequest)
+		require.Contains(t, err.Error(), "failed to unmarshal snapshot request")
+		_, err = snapshotSvc.Cancel(context.Background(), invalidRequest)
+		require.Contains(t, err.Error(), "failed to unmarshal snapshot request")
+		_, err = snapshotSvc.QueryPendings(context.Background(), invalidRequest)
+		require.Contains(t, err.Error(), "failed to unmarshal snapshot query")
+	})
+
+	t.Run("missing signature header", func(t *testing.T) {
+		_, err := snapshotSvc.Generate(context.Background(), newSignedRequest(&SnapshotRequest{ChannelId: "testchannel"}))
+		require.EqualError(t, err, "missing signature header")
+		_, err = snapshotSvc.QueryPendings(context.Background(), newSignedRequest(&SnapshotQuery{ChannelId: "testchannel"}))
+		require.EqualError(t, err, "missing signature header")
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		fakeACLProvider.CheckACLReturns(errors.New("access denied"))
+		defer fakeACLProvider.CheckACLReturns(nil)
+		_, err := snapshotSvc.Generate(context.Background(), validRequest)
+		require.EqualError(t, err, "access denied")
+		_, err = snapshotSvc.Cancel(context.Background(), validRequest)
+		require.EqualError(t, err, "access denied")
+		_, err = snapshotSvc.QueryPendings(context.Background(), validQuery)
+		require.EqualError(t, err, "access denied")
+	})
+
+	t.Run("missing channel", func(t *testing.T) {
+		_, err := snapshotSvc.Generate(context.Background(), newSignedRequest(&SnapshotRequest{SignatureHeader: signatureHdr}))
+		require.EqualError(t, err, "missing channel ID")
+	})
+
+	t.Run("ledger not found", func(t *testing.T) {
+		fakeLedgerGetter.GetLedgerReturns(nil)
+		defer fakeLedgerGetter.GetLedgerReturns(fakeLedger)
+		_, err := snapshotSvc.Generate(context.Background(), validRequest)
+		require.EqualError(t, err, "cannot find ledger for channel testchannel")
+	})
+
+	t.Run("ledger error", func(t *testing.T) {
+		fakeLedger.SubmitSnapshotRequestReturns(errors.New("duplicate snapshot request"))
+		fakeLedger.CancelSnapshotRequestReturns(errors.New("no snapshot request exists"))
+		fakeLedger.PendingSnapshotRequestsReturns(nil, errors.New("leveldb error"))
+		_, err := snapshotSvc.Generate(context.Background(), validRequest)
+		require.EqualError(t, err, "failed to submit snapshot request: duplicate snapshot request")
+		_, err = snapshotSvc.Cancel(context.Background(), validRequest)
+		require.EqualError(t, err, "failed to cancel snapshot request: no snapshot request exists")
+		_, err = snapshotSvc.QueryPendings(context.Background(), validQuery)
+		require.EqualError(t, err, "failed to list pending snapshot requests: leveldb error")
+	})
+}
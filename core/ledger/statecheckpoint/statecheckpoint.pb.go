@@ -0,0 +1,92 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: statecheckpoint.proto
+
+package statecheckpoint
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// StateCheckpoint is the commitment over the state of a channel after the
+// block with the given number is committed. Two peers of a channel have
+// the same commitment at a block only if they have the same public data
+// and the same hashes of the private data after committing the block.
+type StateCheckpoint struct {
+	BlockNum             uint64   `protobuf:"varint,1,opt,name=block_num,json=blockNum,proto3" json:"block_num,omitempty"`
+	Commitment           []byte   `protobuf:"bytes,2,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateCheckpoint) Reset()         { *m = StateCheckpoint{} }
+func (m *StateCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*StateCheckpoint) ProtoMessage()    {}
+func (*StateCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0413463ee84834dd, []int{0}
+}
+
+func (m *StateCheckpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateCheckpoint.Unmarshal(m, b)
+}
+func (m *StateCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateCheckpoint.Marshal(b, m, deterministic)
+}
+func (m *StateCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateCheckpoint.Merge(m, src)
+}
+func (m *StateCheckpoint) XXX_Size() int {
+	return xxx_messageInfo_StateCheckpoint.Size(m)
+}
+func (m *StateCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateCheckpoint proto.InternalMessageInfo
+
+func (m *StateCheckpoint) GetBlockNum() uint64 {
+	if m != nil {
+		return m.BlockNum
+	}
+	return 0
+}
+
+func (m *StateCheckpoint) GetCommitment() []byte {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*StateCheckpoint)(nil), "statecheckpoint.StateCheckpoint")
+}
+
+func init() { proto.RegisterFile("statecheckpoint.proto", fileDescriptor_0413463ee84834dd) }
+
+var fileDescriptor_0413463ee84834dd = []byte{
+	// 155 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2d, 0x2e, 0x49, 0x2c,
+	0x49, 0x4d, 0xce, 0x48, 0x4d, 0xce, 0x2e, 0xc8, 0xcf, 0xcc, 0x2b, 0xd1, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x17, 0xe2, 0x47, 0x13, 0x56, 0xf2, 0xe3, 0xe2, 0x0f, 0x06, 0x09, 0x39, 0xc3, 0x85, 0x84,
+	0xa4, 0xb9, 0x38, 0x93, 0x72, 0xf2, 0x93, 0xb3, 0xe3, 0xf3, 0x4a, 0x73, 0x25, 0x18, 0x15, 0x18,
+	0x35, 0x58, 0x82, 0x38, 0xc0, 0x02, 0x7e, 0xa5, 0xb9, 0x42, 0x72, 0x5c, 0x5c, 0xc9, 0xf9, 0xb9,
+	0xb9, 0x99, 0x25, 0xb9, 0xa9, 0x79, 0x25, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c, 0x41, 0x48, 0x22,
+	0x4e, 0xd6, 0x51, 0x96, 0xe9, 0x99, 0x25, 0x19, 0xa5, 0x49, 0x7a, 0xc9, 0xf9, 0xb9, 0xfa, 0x19,
+	0x95, 0x05, 0xa9, 0x45, 0x39, 0xa9, 0x29, 0xe9, 0xa9, 0x45, 0xfa, 0x69, 0x89, 0x49, 0x45, 0x99,
+	0xc9, 0xfa, 0xc9, 0xf9, 0x45, 0xa9, 0xfa, 0x50, 0x21, 0x34, 0xc7, 0x24, 0xb1, 0x81, 0x1d, 0x69,
+	0x0c, 0x18, 0x00, 0xa4, 0xd3, 0x01, 0xe0, 0xbd, 0x00, 0x00, 0x00,
+}
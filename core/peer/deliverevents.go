@@ -9,9 +9,12 @@ package peer
 import (
 	"runtime/debug"
 
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
@@ -229,6 +232,123 @@ func (bprs *blockAndPrivateDataResponseSender) getPrivateData(
 	return seqs2Namespaces.asPrivateDataMap(), nil
 }
 
+// blockExtensionResponseSender structure used to send block extension responses
+type blockExtensionResponseSender struct {
+	blockextension.ExtensionDeliver_DeliverExtensionsServer
+	// typeURLs are the type URLs of the entries selected by the current request
+	typeURLs []string
+	// filterErr is the error extracting the extension filter of the current request
+	filterErr error
+}
+
+// SendStatusResponse generates status reply proto message
+func (ebrs *blockExtensionResponseSender) SendStatusResponse(status cb.Status) error {
+	response := &blockextension.DeliverExtensionsResponse{
+		Type: &blockextension.DeliverExtensionsResponse_Status{Status: common.Status(status)},
+	}
+	return ebrs.Send(response)
+}
+
+// IsFiltered is a marker method which indicates that this response sender
+// sends filtered blocks.
+func (ebrs *blockExtensionResponseSender) IsFiltered() bool {
+	return true
+}
+
+// SendBlockResponse generates deliver response with the selected entries of the block extension
+func (ebrs *blockExtensionResponseSender) SendBlockResponse(
+	block *cb.Block,
+	channelID string,
+	chain deliver.Chain,
+	signedData *protoutil.SignedData,
+) error {
+	if ebrs.filterErr != nil {
+		logger.Warningf("Failed to select block extension entries due to: %s", ebrs.filterErr)
+		return ebrs.SendStatusResponse(cb.Status_BAD_REQUEST)
+	}
+	response := &blockextension.DeliverExtensionsResponse{
+		Type: &blockextension.DeliverExtensionsResponse_FilteredBlockExtension{
+			FilteredBlockExtension: toFilteredBlockExtension(block, channelID, ebrs.typeURLs),
+		},
+	}
+	return ebrs.Send(response)
+}
+
+func (ebrs *blockExtensionResponseSender) DataType() string {
+	return "block_extension"
+}
+
+// extensionFilterReceiver receives the requests for block extensions, and
+// passes the extension filter of each request to the response sender.
+type extensionFilterReceiver struct {
+	blockextension.ExtensionDeliver_DeliverExtensionsServer
+	responseSender *blockExtensionResponseSender
+}
+
+// Recv receives the next request and sets its extension filter on the response sender
+func (efr *extensionFilterReceiver) Recv() (*cb.Envelope, error) {
+	envelope, err := efr.ExtensionDeliver_DeliverExtensionsServer.Recv()
+	if err != nil {
+		return nil, err
+	}
+	efr.responseSender.typeURLs, efr.responseSender.filterErr = extensionFilter(envelope.Payload)
+	return &cb.Envelope{
+		Payload:   envelope.Payload,
+		Signature: envelope.Signature,
+	}, nil
+}
+
+// extensionFilter returns the type URLs selected by the extension filter in the
+// channel header of the request, if any.
+func extensionFilter(payloadBytes []byte) ([]string, error) {
+	payload, err := protoutil.UnmarshalPayload(payloadBytes)
+	if err != nil || payload.Header == nil {
+		// the request is rejected by the deliver handler
+		return nil, nil
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || len(chdr.Extension) == 0 {
+		return nil, nil
+	}
+	filter := &blockextension.ExtensionFilter{}
+	if err := proto.Unmarshal(chdr.Extension, filter); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling ExtensionFilter")
+	}
+	return filter.TypeUrls, nil
+}
+
+// toFilteredBlockExtension returns the entries of the block extension with the
+// given type URLs, or all of them if no type URL is given. The whole extension is
+// returned along with the entries, with the parts of the block needed to verify it,
+// as the selected entries alone cannot be checked against the extension hash.
+func toFilteredBlockExtension(block *cb.Block, channelID string, typeURLs []string) *blockextension.FilteredBlockExtension {
+	filteredBlockExtension := &blockextension.FilteredBlockExtension{
+		ChannelId:           channelID,
+		Number:              block.Header.Number,
+		VerifiableExtension: protoutil.NewVerifiableBlockExtension(block),
+	}
+	if block.Extension == nil {
+		return filteredBlockExtension
+	}
+
+	selected := make(map[string]struct{}, len(typeURLs))
+	for _, typeURL := range typeURLs {
+		selected[typeURL] = struct{}{}
+	}
+	for i, data := range block.Extension.ExtensionData {
+		entry, err := protoutil.UnmarshalBlockExtensionEntry(data)
+		if err != nil || entry.TypeUrl == "" {
+			logger.Debugf("block extension data %d of block num %d is not an entry, skipping", i, block.Header.Number)
+			continue
+		}
+		if _, ok := selected[entry.TypeUrl]; len(selected) != 0 && !ok {
+			continue
+		}
+		filteredBlockExtension.Entries = append(filteredBlockExtension.Entries, entry)
+	}
+	return filteredBlockExtension
+}
+
 // transactionActions aliasing for peer.TransactionAction pointers slice
 type transactionActions []*peer.TransactionAction
 
@@ -290,6 +410,25 @@ func (s *DeliverServer) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPriva
 	return err
 }
 
+// DeliverExtensions sends a stream of the extensions of the blocks to a client after commitment
+func (s *DeliverServer) DeliverExtensions(srv blockextension.ExtensionDeliver_DeliverExtensionsServer) error {
+	logger.Debugf("Starting new DeliverExtensions handler")
+	defer dumpStacktraceOnPanic()
+	responseSender := &blockExtensionResponseSender{
+		ExtensionDeliver_DeliverExtensionsServer: srv,
+	}
+	// getting policy checker based on resources.Event_BlockExtension resource name
+	deliverServer := &deliver.Server{
+		Receiver: &extensionFilterReceiver{
+			ExtensionDeliver_DeliverExtensionsServer: srv,
+			responseSender:                           responseSender,
+		},
+		PolicyChecker:  s.PolicyCheckerProvider(resources.Event_BlockExtension),
+		ResponseSender: responseSender,
+	}
+	return s.DeliverHandler.Handle(srv.Context(), deliverServer)
+}
+
 func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	filteredBlock := &peer.FilteredBlock{
 		Number: block.Header.Number,
@@ -15,10 +15,12 @@ import (
 	"testing"
 	"time"
 
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
@@ -199,6 +201,82 @@ func TestFilteredBlockResponseSenderIsFiltered(t *testing.T) {
 	assert.True(t, filtered.IsFiltered(), "should return true from IsFiltered")
 }
 
+func TestBlockExtensionResponseSenderIsFiltered(t *testing.T) {
+	var ebrs interface{} = &blockExtensionResponseSender{}
+	filtered, ok := ebrs.(deliver.Filtered)
+	assert.True(t, ok, "should be filtered")
+	assert.True(t, filtered.IsFiltered(), "should return true from IsFiltered")
+}
+
+func TestExtensionFilter(t *testing.T) {
+	payload := func(extension []byte) []byte {
+		return protoutil.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+					ChannelId: "testChannelID",
+					Extension: extension,
+				}),
+			},
+		})
+	}
+
+	typeURLs, err := extensionFilter(payload(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, typeURLs)
+
+	typeURLs, err = extensionFilter(payload(protoutil.MarshalOrPanic(&blockextension.ExtensionFilter{
+		TypeUrls: []string{blockextension.TxIDMerkleRootType},
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{blockextension.TxIDMerkleRootType}, typeURLs)
+
+	_, err = extensionFilter(payload([]byte{10, 10}))
+	assert.EqualError(t, err, "error unmarshaling ExtensionFilter: unexpected EOF")
+
+	typeURLs, err = extensionFilter([]byte("garbage"))
+	assert.NoError(t, err)
+	assert.Nil(t, typeURLs)
+}
+
+func TestToFilteredBlockExtension(t *testing.T) {
+	block := &cb.Block{
+		Header: &cb.BlockHeader{Number: 5},
+		Extension: &cb.BlockExtension{
+			ExtensionData: [][]byte{
+				protoutil.MarshalOrPanic(&blockextension.Entry{
+					TypeUrl: blockextension.TxIDMerkleRootType,
+					Payload: []byte("root"),
+				}),
+				[]byte("not an entry"),
+				protoutil.MarshalOrPanic(&blockextension.Entry{
+					TypeUrl: blockextension.BatchTimestampType,
+					Payload: []byte("timestamp"),
+				}),
+			},
+		},
+	}
+
+	protoutil.SetExtensionHashInBlock(block)
+
+	filteredBlockExtension := toFilteredBlockExtension(block, "testChannelID", nil)
+	assert.Equal(t, "testChannelID", filteredBlockExtension.ChannelId)
+	assert.Equal(t, uint64(5), filteredBlockExtension.Number)
+	assert.Len(t, filteredBlockExtension.Entries, 2)
+	assert.Equal(t, protoutil.NewVerifiableBlockExtension(block), filteredBlockExtension.VerifiableExtension)
+
+	// the whole extension is returned with the selected entries so that it can be verified
+	filteredBlockExtension = toFilteredBlockExtension(block, "testChannelID", []string{blockextension.BatchTimestampType})
+	assert.Len(t, filteredBlockExtension.Entries, 1)
+	assert.Equal(t, []byte("timestamp"), filteredBlockExtension.Entries[0].Payload)
+	assert.Equal(t, block.Extension.ExtensionData, filteredBlockExtension.VerifiableExtension.Extension.ExtensionData)
+	assert.Equal(t, block.Metadata.Metadata[protoutil.BlockMetadataIndexExtensionHash], filteredBlockExtension.VerifiableExtension.ExtensionHash)
+
+	block.Extension = nil
+	filteredBlockExtension = toFilteredBlockExtension(block, "testChannelID", nil)
+	assert.Equal(t, uint64(5), filteredBlockExtension.Number)
+	assert.Empty(t, filteredBlockExtension.Entries)
+}
+
 func TestEventsServer_DeliverFiltered(t *testing.T) {
 	tests := []testCase{
 		{
@@ -11,6 +11,17 @@ import (
 )
 
 type PeerLedger struct {
+	CancelSnapshotRequestStub        func(uint64) error
+	cancelSnapshotRequestMutex       sync.RWMutex
+	cancelSnapshotRequestArgsForCall []struct {
+		arg1 uint64
+	}
+	cancelSnapshotRequestReturns struct {
+		result1 error
+	}
+	cancelSnapshotRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CloseStub        func()
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -93,6 +104,20 @@ type PeerLedger struct {
 		result1 *common.Block
 		result2 error
 	}
+	GetBlockNumsByExtensionKeyStub        func(string, []byte) ([]uint64, error)
+	getBlockNumsByExtensionKeyMutex       sync.RWMutex
+	getBlockNumsByExtensionKeyArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+	}
+	getBlockNumsByExtensionKeyReturns struct {
+		result1 []uint64
+		result2 error
+	}
+	getBlockNumsByExtensionKeyReturnsOnCall map[int]struct {
+		result1 []uint64
+		result2 error
+	}
 	GetBlockchainInfoStub        func() (*common.BlockchainInfo, error)
 	getBlockchainInfoMutex       sync.RWMutex
 	getBlockchainInfoArgsForCall []struct {
@@ -130,6 +155,18 @@ type PeerLedger struct {
 		result1 ledger.ConfigHistoryRetriever
 		result2 error
 	}
+	GetLatestStateCheckpointStub        func() (*ledger.StateCheckpoint, error)
+	getLatestStateCheckpointMutex       sync.RWMutex
+	getLatestStateCheckpointArgsForCall []struct {
+	}
+	getLatestStateCheckpointReturns struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}
+	getLatestStateCheckpointReturnsOnCall map[int]struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}
 	GetMissingPvtDataTrackerStub        func() (ledger.MissingPvtDataTracker, error)
 	getMissingPvtDataTrackerMutex       sync.RWMutex
 	getMissingPvtDataTrackerArgsForCall []struct {
@@ -170,6 +207,19 @@ type PeerLedger struct {
 		result1 []*ledger.TxPvtData
 		result2 error
 	}
+	GetStateCheckpointStub        func(uint64) (*ledger.StateCheckpoint, error)
+	getStateCheckpointMutex       sync.RWMutex
+	getStateCheckpointArgsForCall []struct {
+		arg1 uint64
+	}
+	getStateCheckpointReturns struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}
+	getStateCheckpointReturnsOnCall map[int]struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}
 	GetTransactionByIDStub        func(string) (*peera.ProcessedTransaction, error)
 	getTransactionByIDMutex       sync.RWMutex
 	getTransactionByIDArgsForCall []struct {
@@ -233,10 +283,93 @@ type PeerLedger struct {
 		result1 ledger.TxSimulator
 		result2 error
 	}
+	PendingSnapshotRequestsStub        func() ([]uint64, error)
+	pendingSnapshotRequestsMutex       sync.RWMutex
+	pendingSnapshotRequestsArgsForCall []struct {
+	}
+	pendingSnapshotRequestsReturns struct {
+		result1 []uint64
+		result2 error
+	}
+	pendingSnapshotRequestsReturnsOnCall map[int]struct {
+		result1 []uint64
+		result2 error
+	}
+	SubmitSnapshotRequestStub        func(uint64) error
+	submitSnapshotRequestMutex       sync.RWMutex
+	submitSnapshotRequestArgsForCall []struct {
+		arg1 uint64
+	}
+	submitSnapshotRequestReturns struct {
+		result1 error
+	}
+	submitSnapshotRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *PeerLedger) CancelSnapshotRequest(arg1 uint64) error {
+	fake.cancelSnapshotRequestMutex.Lock()
+	ret, specificReturn := fake.cancelSnapshotRequestReturnsOnCall[len(fake.cancelSnapshotRequestArgsForCall)]
+	fake.cancelSnapshotRequestArgsForCall = append(fake.cancelSnapshotRequestArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	fake.recordInvocation("CancelSnapshotRequest", []interface{}{arg1})
+	fake.cancelSnapshotRequestMutex.Unlock()
+	if fake.CancelSnapshotRequestStub != nil {
+		return fake.CancelSnapshotRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.cancelSnapshotRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *PeerLedger) CancelSnapshotRequestCallCount() int {
+	fake.cancelSnapshotRequestMutex.RLock()
+	defer fake.cancelSnapshotRequestMutex.RUnlock()
+	return len(fake.cancelSnapshotRequestArgsForCall)
+}
+
+func (fake *PeerLedger) CancelSnapshotRequestCalls(stub func(uint64) error) {
+	fake.cancelSnapshotRequestMutex.Lock()
+	defer fake.cancelSnapshotRequestMutex.Unlock()
+	fake.CancelSnapshotRequestStub = stub
+}
+
+func (fake *PeerLedger) CancelSnapshotRequestArgsForCall(i int) uint64 {
+	fake.cancelSnapshotRequestMutex.RLock()
+	defer fake.cancelSnapshotRequestMutex.RUnlock()
+	argsForCall := fake.cancelSnapshotRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PeerLedger) CancelSnapshotRequestReturns(result1 error) {
+	fake.cancelSnapshotRequestMutex.Lock()
+	defer fake.cancelSnapshotRequestMutex.Unlock()
+	fake.CancelSnapshotRequestStub = nil
+	fake.cancelSnapshotRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PeerLedger) CancelSnapshotRequestReturnsOnCall(i int, result1 error) {
+	fake.cancelSnapshotRequestMutex.Lock()
+	defer fake.cancelSnapshotRequestMutex.Unlock()
+	fake.CancelSnapshotRequestStub = nil
+	if fake.cancelSnapshotRequestReturnsOnCall == nil {
+		fake.cancelSnapshotRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cancelSnapshotRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *PeerLedger) Close() {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
@@ -647,6 +780,75 @@ func (fake *PeerLedger) GetBlockByTxIDReturnsOnCall(i int, result1 *common.Block
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetBlockNumsByExtensionKey(arg1 string, arg2 []byte) ([]uint64, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.getBlockNumsByExtensionKeyMutex.Lock()
+	ret, specificReturn := fake.getBlockNumsByExtensionKeyReturnsOnCall[len(fake.getBlockNumsByExtensionKeyArgsForCall)]
+	fake.getBlockNumsByExtensionKeyArgsForCall = append(fake.getBlockNumsByExtensionKeyArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+	}{arg1, arg2Copy})
+	fake.recordInvocation("GetBlockNumsByExtensionKey", []interface{}{arg1, arg2Copy})
+	fake.getBlockNumsByExtensionKeyMutex.Unlock()
+	if fake.GetBlockNumsByExtensionKeyStub != nil {
+		return fake.GetBlockNumsByExtensionKeyStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getBlockNumsByExtensionKeyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetBlockNumsByExtensionKeyCallCount() int {
+	fake.getBlockNumsByExtensionKeyMutex.RLock()
+	defer fake.getBlockNumsByExtensionKeyMutex.RUnlock()
+	return len(fake.getBlockNumsByExtensionKeyArgsForCall)
+}
+
+func (fake *PeerLedger) GetBlockNumsByExtensionKeyCalls(stub func(string, []byte) ([]uint64, error)) {
+	fake.getBlockNumsByExtensionKeyMutex.Lock()
+	defer fake.getBlockNumsByExtensionKeyMutex.Unlock()
+	fake.GetBlockNumsByExtensionKeyStub = stub
+}
+
+func (fake *PeerLedger) GetBlockNumsByExtensionKeyArgsForCall(i int) (string, []byte) {
+	fake.getBlockNumsByExtensionKeyMutex.RLock()
+	defer fake.getBlockNumsByExtensionKeyMutex.RUnlock()
+	argsForCall := fake.getBlockNumsByExtensionKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PeerLedger) GetBlockNumsByExtensionKeyReturns(result1 []uint64, result2 error) {
+	fake.getBlockNumsByExtensionKeyMutex.Lock()
+	defer fake.getBlockNumsByExtensionKeyMutex.Unlock()
+	fake.GetBlockNumsByExtensionKeyStub = nil
+	fake.getBlockNumsByExtensionKeyReturns = struct {
+		result1 []uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetBlockNumsByExtensionKeyReturnsOnCall(i int, result1 []uint64, result2 error) {
+	fake.getBlockNumsByExtensionKeyMutex.Lock()
+	defer fake.getBlockNumsByExtensionKeyMutex.Unlock()
+	fake.GetBlockNumsByExtensionKeyStub = nil
+	if fake.getBlockNumsByExtensionKeyReturnsOnCall == nil {
+		fake.getBlockNumsByExtensionKeyReturnsOnCall = make(map[int]struct {
+			result1 []uint64
+			result2 error
+		})
+	}
+	fake.getBlockNumsByExtensionKeyReturnsOnCall[i] = struct {
+		result1 []uint64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	fake.getBlockchainInfoMutex.Lock()
 	ret, specificReturn := fake.getBlockchainInfoReturnsOnCall[len(fake.getBlockchainInfoArgsForCall)]
@@ -820,6 +1022,61 @@ func (fake *PeerLedger) GetConfigHistoryRetrieverReturnsOnCall(i int, result1 le
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	fake.getLatestStateCheckpointMutex.Lock()
+	ret, specificReturn := fake.getLatestStateCheckpointReturnsOnCall[len(fake.getLatestStateCheckpointArgsForCall)]
+	fake.getLatestStateCheckpointArgsForCall = append(fake.getLatestStateCheckpointArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetLatestStateCheckpoint", []interface{}{})
+	fake.getLatestStateCheckpointMutex.Unlock()
+	if fake.GetLatestStateCheckpointStub != nil {
+		return fake.GetLatestStateCheckpointStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getLatestStateCheckpointReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetLatestStateCheckpointCallCount() int {
+	fake.getLatestStateCheckpointMutex.RLock()
+	defer fake.getLatestStateCheckpointMutex.RUnlock()
+	return len(fake.getLatestStateCheckpointArgsForCall)
+}
+
+func (fake *PeerLedger) GetLatestStateCheckpointCalls(stub func() (*ledger.StateCheckpoint, error)) {
+	fake.getLatestStateCheckpointMutex.Lock()
+	defer fake.getLatestStateCheckpointMutex.Unlock()
+	fake.GetLatestStateCheckpointStub = stub
+}
+
+func (fake *PeerLedger) GetLatestStateCheckpointReturns(result1 *ledger.StateCheckpoint, result2 error) {
+	fake.getLatestStateCheckpointMutex.Lock()
+	defer fake.getLatestStateCheckpointMutex.Unlock()
+	fake.GetLatestStateCheckpointStub = nil
+	fake.getLatestStateCheckpointReturns = struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetLatestStateCheckpointReturnsOnCall(i int, result1 *ledger.StateCheckpoint, result2 error) {
+	fake.getLatestStateCheckpointMutex.Lock()
+	defer fake.getLatestStateCheckpointMutex.Unlock()
+	fake.GetLatestStateCheckpointStub = nil
+	if fake.getLatestStateCheckpointReturnsOnCall == nil {
+		fake.getLatestStateCheckpointReturnsOnCall = make(map[int]struct {
+			result1 *ledger.StateCheckpoint
+			result2 error
+		})
+	}
+	fake.getLatestStateCheckpointReturnsOnCall[i] = struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
 	fake.getMissingPvtDataTrackerMutex.Lock()
 	ret, specificReturn := fake.getMissingPvtDataTrackerReturnsOnCall[len(fake.getMissingPvtDataTrackerArgsForCall)]
@@ -1003,6 +1260,69 @@ func (fake *PeerLedger) GetPvtDataByNumReturnsOnCall(i int, result1 []*ledger.Tx
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetStateCheckpoint(arg1 uint64) (*ledger.StateCheckpoint, error) {
+	fake.getStateCheckpointMutex.Lock()
+	ret, specificReturn := fake.getStateCheckpointReturnsOnCall[len(fake.getStateCheckpointArgsForCall)]
+	fake.getStateCheckpointArgsForCall = append(fake.getStateCheckpointArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	fake.recordInvocation("GetStateCheckpoint", []interface{}{arg1})
+	fake.getStateCheckpointMutex.Unlock()
+	if fake.GetStateCheckpointStub != nil {
+		return fake.GetStateCheckpointStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStateCheckpointReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetStateCheckpointCallCount() int {
+	fake.getStateCheckpointMutex.RLock()
+	defer fake.getStateCheckpointMutex.RUnlock()
+	return len(fake.getStateCheckpointArgsForCall)
+}
+
+func (fake *PeerLedger) GetStateCheckpointCalls(stub func(uint64) (*ledger.StateCheckpoint, error)) {
+	fake.getStateCheckpointMutex.Lock()
+	defer fake.getStateCheckpointMutex.Unlock()
+	fake.GetStateCheckpointStub = stub
+}
+
+func (fake *PeerLedger) GetStateCheckpointArgsForCall(i int) uint64 {
+	fake.getStateCheckpointMutex.RLock()
+	defer fake.getStateCheckpointMutex.RUnlock()
+	argsForCall := fake.getStateCheckpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PeerLedger) GetStateCheckpointReturns(result1 *ledger.StateCheckpoint, result2 error) {
+	fake.getStateCheckpointMutex.Lock()
+	defer fake.getStateCheckpointMutex.Unlock()
+	fake.GetStateCheckpointStub = nil
+	fake.getStateCheckpointReturns = struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetStateCheckpointReturnsOnCall(i int, result1 *ledger.StateCheckpoint, result2 error) {
+	fake.getStateCheckpointMutex.Lock()
+	defer fake.getStateCheckpointMutex.Unlock()
+	fake.GetStateCheckpointStub = nil
+	if fake.getStateCheckpointReturnsOnCall == nil {
+		fake.getStateCheckpointReturnsOnCall = make(map[int]struct {
+			result1 *ledger.StateCheckpoint
+			result2 error
+		})
+	}
+	fake.getStateCheckpointReturnsOnCall[i] = struct {
+		result1 *ledger.StateCheckpoint
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetTransactionByID(arg1 string) (*peera.ProcessedTransaction, error) {
 	fake.getTransactionByIDMutex.Lock()
 	ret, specificReturn := fake.getTransactionByIDReturnsOnCall[len(fake.getTransactionByIDArgsForCall)]
@@ -1302,9 +1622,126 @@ func (fake *PeerLedger) NewTxSimulatorReturnsOnCall(i int, result1 ledger.TxSimu
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) PendingSnapshotRequests() ([]uint64, error) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	ret, specificReturn := fake.pendingSnapshotRequestsReturnsOnCall[len(fake.pendingSnapshotRequestsArgsForCall)]
+	fake.pendingSnapshotRequestsArgsForCall = append(fake.pendingSnapshotRequestsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("PendingSnapshotRequests", []interface{}{})
+	fake.pendingSnapshotRequestsMutex.Unlock()
+	if fake.PendingSnapshotRequestsStub != nil {
+		return fake.PendingSnapshotRequestsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.pendingSnapshotRequestsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsCallCount() int {
+	fake.pendingSnapshotRequestsMutex.RLock()
+	defer fake.pendingSnapshotRequestsMutex.RUnlock()
+	return len(fake.pendingSnapshotRequestsArgsForCall)
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsCalls(stub func() ([]uint64, error)) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	defer fake.pendingSnapshotRequestsMutex.Unlock()
+	fake.PendingSnapshotRequestsStub = stub
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsReturns(result1 []uint64, result2 error) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	defer fake.pendingSnapshotRequestsMutex.Unlock()
+	fake.PendingSnapshotRequestsStub = nil
+	fake.pendingSnapshotRequestsReturns = struct {
+		result1 []uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) PendingSnapshotRequestsReturnsOnCall(i int, result1 []uint64, result2 error) {
+	fake.pendingSnapshotRequestsMutex.Lock()
+	defer fake.pendingSnapshotRequestsMutex.Unlock()
+	fake.PendingSnapshotRequestsStub = nil
+	if fake.pendingSnapshotRequestsReturnsOnCall == nil {
+		fake.pendingSnapshotRequestsReturnsOnCall = make(map[int]struct {
+			result1 []uint64
+			result2 error
+		})
+	}
+	fake.pendingSnapshotRequestsReturnsOnCall[i] = struct {
+		result1 []uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequest(arg1 uint64) error {
+	fake.submitSnapshotRequestMutex.Lock()
+	ret, specificReturn := fake.submitSnapshotRequestReturnsOnCall[len(fake.submitSnapshotRequestArgsForCall)]
+	fake.submitSnapshotRequestArgsForCall = append(fake.submitSnapshotRequestArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	fake.recordInvocation("SubmitSnapshotRequest", []interface{}{arg1})
+	fake.submitSnapshotRequestMutex.Unlock()
+	if fake.SubmitSnapshotRequestStub != nil {
+		return fake.SubmitSnapshotRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.submitSnapshotRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestCallCount() int {
+	fake.submitSnapshotRequestMutex.RLock()
+	defer fake.submitSnapshotRequestMutex.RUnlock()
+	return len(fake.submitSnapshotRequestArgsForCall)
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestCalls(stub func(uint64) error) {
+	fake.submitSnapshotRequestMutex.Lock()
+	defer fake.submitSnapshotRequestMutex.Unlock()
+	fake.SubmitSnapshotRequestStub = stub
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestArgsForCall(i int) uint64 {
+	fake.submitSnapshotRequestMutex.RLock()
+	defer fake.submitSnapshotRequestMutex.RUnlock()
+	argsForCall := fake.submitSnapshotRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestReturns(result1 error) {
+	fake.submitSnapshotRequestMutex.Lock()
+	defer fake.submitSnapshotRequestMutex.Unlock()
+	fake.SubmitSnapshotRequestStub = nil
+	fake.submitSnapshotRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PeerLedger) SubmitSnapshotRequestReturnsOnCall(i int, result1 error) {
+	fake.submitSnapshotRequestMutex.Lock()
+	defer fake.submitSnapshotRequestMutex.Unlock()
+	fake.SubmitSnapshotRequestStub = nil
+	if fake.submitSnapshotRequestReturnsOnCall == nil {
+		fake.submitSnapshotRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.submitSnapshotRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cancelSnapshotRequestMutex.RLock()
+	defer fake.cancelSnapshotRequestMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
 	fake.commitLegacyMutex.RLock()
@@ -1319,18 +1756,24 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.getBlockByNumberMutex.RUnlock()
 	fake.getBlockByTxIDMutex.RLock()
 	defer fake.getBlockByTxIDMutex.RUnlock()
+	fake.getBlockNumsByExtensionKeyMutex.RLock()
+	defer fake.getBlockNumsByExtensionKeyMutex.RUnlock()
 	fake.getBlockchainInfoMutex.RLock()
 	defer fake.getBlockchainInfoMutex.RUnlock()
 	fake.getBlocksIteratorMutex.RLock()
 	defer fake.getBlocksIteratorMutex.RUnlock()
 	fake.getConfigHistoryRetrieverMutex.RLock()
 	defer fake.getConfigHistoryRetrieverMutex.RUnlock()
+	fake.getLatestStateCheckpointMutex.RLock()
+	defer fake.getLatestStateCheckpointMutex.RUnlock()
 	fake.getMissingPvtDataTrackerMutex.RLock()
 	defer fake.getMissingPvtDataTrackerMutex.RUnlock()
 	fake.getPvtDataAndBlockByNumMutex.RLock()
 	defer fake.getPvtDataAndBlockByNumMutex.RUnlock()
 	fake.getPvtDataByNumMutex.RLock()
 	defer fake.getPvtDataByNumMutex.RUnlock()
+	fake.getStateCheckpointMutex.RLock()
+	defer fake.getStateCheckpointMutex.RUnlock()
 	fake.getTransactionByIDMutex.RLock()
 	defer fake.getTransactionByIDMutex.RUnlock()
 	fake.getTxValidationCodeByTxIDMutex.RLock()
@@ -1341,6 +1784,10 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.newQueryExecutorMutex.RUnlock()
 	fake.newTxSimulatorMutex.RLock()
 	defer fake.newTxSimulatorMutex.RUnlock()
+	fake.pendingSnapshotRequestsMutex.RLock()
+	defer fake.pendingSnapshotRequestsMutex.RUnlock()
+	fake.submitSnapshotRequestMutex.RLock()
+	defer fake.submitSnapshotRequestMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	cc "github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
@@ -224,6 +225,26 @@ func (p *Peer) CreateChannel(
 	return nil
 }
 
+// CreateChannelFromSnapshot creates a channel from the specified snapshot.
+func (p *Peer) CreateChannelFromSnapshot(
+	snapshotDir string,
+	deployedCCInfoProvider ledger.DeployedChaincodeInfoProvider,
+	legacyLifecycleValidation plugindispatcher.LifecycleResources,
+	newLifecycleValidation plugindispatcher.CollectionAndLifecycleResources,
+) error {
+	l, cid, err := p.LedgerMgr.CreateLedgerFromSnapshot(snapshotDir)
+	if err != nil {
+		return errors.WithMessage(err, "cannot create ledger from snapshot")
+	}
+
+	if err := p.createChannel(cid, l, deployedCCInfoProvider, legacyLifecycleValidation, newLifecycleValidation); err != nil {
+		return err
+	}
+
+	p.initChannel(cid)
+	return nil
+}
+
 // retrievePersistedChannelConfig retrieves the persisted channel config from statedb
 func retrievePersistedChannelConfig(ledger ledger.PeerLedger) (*common.Config, error) {
 	qe, err := ledger.NewQueryExecutor()
@@ -455,6 +476,42 @@ func (p *Peer) GetPolicyManager(cid string) policies.Manager {
 	return nil
 }
 
+// GetBlockExtensionLimits returns the limits that the config of the channel with channel ID in effect
+// for the block with number blockNum sets on the extension of the block, given the number of the last
+// config block recorded in the block. The orderer bounds the extension of a block with the config that
+// precedes the block, hence the current config of the channel is in effect for the block only if the
+// last config block precedes the block and has been committed, and if the block has not been committed
+// yet, so that no later config has been applied. Otherwise, the config in effect for the block is not
+// known and nil is returned, as it is if channel cid has not been created.
+func (p *Peer) GetBlockExtensionLimits(cid string, blockNum, lastConfigBlockNum uint64) *blockextension.ExtensionLimits {
+	c := p.Channel(cid)
+	if c == nil || lastConfigBlockNum >= blockNum {
+		return nil
+	}
+	// The height is read before and after the config, as a config block is applied before it is committed
+	heightBefore, err := ledgerHeight(c.Ledger())
+	if err != nil || lastConfigBlockNum >= heightBefore {
+		return nil
+	}
+	oc, ok := c.Resources().OrdererConfig()
+	if !ok {
+		return nil
+	}
+	heightAfter, err := ledgerHeight(c.Ledger())
+	if err != nil || heightAfter > blockNum {
+		return nil
+	}
+	return oc.BlockExtensionLimits()
+}
+
+func ledgerHeight(l ledger.PeerLedger) (uint64, error) {
+	info, err := l.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
 // initChannel takes care to initialize channel after peer joined, for example deploys system CCs
 func (p *Peer) initChannel(cid string) {
 	if p.channelInitializer != nil {
@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/core/deliverservice"
@@ -250,6 +251,30 @@ func TestConfigerInvokeJoinChainWrongParams(t *testing.T) {
 	)
 }
 
+func TestConfigerInvokeJoinChainBySnapshotWrongParams(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	mockACLProvider := &mocks.ACLProvider{}
+	cscc := &PeerConfiger{
+		aclProvider: mockACLProvider,
+		bccsp:       cryptoProvider,
+	}
+	mockStub := &mocks.ChaincodeStub{}
+	mockStub.GetArgsReturns([][]byte{[]byte("JoinChainBySnapshot"), []byte("")})
+	mockStub.GetSignedProposalReturns(validSignedProposal(), nil)
+	res := cscc.Invoke(mockStub)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot join the channel, no snapshot directory provided", res.Message)
+
+	mockACLProvider.CheckACLReturns(errors.New("Failed authorization"))
+	mockStub.GetArgsReturns([][]byte{[]byte("JoinChainBySnapshot"), []byte("/snapshot/dir")})
+	res = cscc.Invoke(mockStub)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "access denied for [JoinChainBySnapshot][/snapshot/dir]")
+	resource, _, _ := mockACLProvider.CheckACLArgsForCall(0)
+	assert.Equal(t, resources.Cscc_JoinChainBySnapshot, resource)
+}
+
 func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	viper.Set("chaincode.executetimeout", "3s")
 
@@ -278,7 +303,7 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 
 	signer := mgmt.GetLocalSigningIdentityOrPanic(cryptoProvider)
 
-	messageCryptoService := peergossip.NewMCS(&mocks.ChannelPolicyManagerGetter{}, signer, mgmt.NewDeserializersManager(cryptoProvider), cryptoProvider)
+	messageCryptoService := peergossip.NewMCS(&mocks.ChannelPolicyManagerGetter{}, signer, mgmt.NewDeserializersManager(cryptoProvider), cryptoProvider, nil)
 	secAdv := peergossip.NewSecurityAdvisor(mgmt.NewDeserializersManager(cryptoProvider))
 	var defaultSecureDialOpts = func() []grpc.DialOption {
 		return []grpc.DialOption{grpc.WithInsecure()}
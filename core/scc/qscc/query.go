@@ -12,9 +12,11 @@ import (
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/protoutil"
 )
 
@@ -40,6 +42,9 @@ func (e *LedgerQuerier) Chaincode() shim.Chaincode { return e }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetBlockExtension returns the extension of a block, along with what is needed to verify it
+// - GetBlockNumsByExtensionKey returns the numbers of the blocks whose extension carries a key
+// - GetStateCheckpoint returns a state checkpoint
 type LedgerQuerier struct {
 	aclProvider aclmgmt.ACLProvider
 	ledgers     LedgerGetter
@@ -49,11 +54,14 @@ var qscclogger = flogging.MustGetLogger("qscc")
 
 // These are function names from Invoke first parameter
 const (
-	GetChainInfo       string = "GetChainInfo"
-	GetBlockByNumber   string = "GetBlockByNumber"
-	GetBlockByHash     string = "GetBlockByHash"
-	GetTransactionByID string = "GetTransactionByID"
-	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo               string = "GetChainInfo"
+	GetBlockByNumber           string = "GetBlockByNumber"
+	GetBlockByHash             string = "GetBlockByHash"
+	GetTransactionByID         string = "GetTransactionByID"
+	GetBlockByTxID             string = "GetBlockByTxID"
+	GetBlockExtension          string = "GetBlockExtension"
+	GetBlockNumsByExtensionKey string = "GetBlockNumsByExtensionKey"
+	GetStateCheckpoint         string = "GetStateCheckpoint"
 )
 
 // Init is called once per chain when the chain is created.
@@ -72,6 +80,9 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetBlockExtension: Return the verifiable extension of the block specified by block number in args[2]
+// # GetBlockNumsByExtensionKey: Return the numbers of the blocks whose extension has the type URL in args[2] and key in args[3]
+// # GetStateCheckpoint: Return the state checkpoint for the block number in args[2], or the latest state checkpoint if args[2] is not specified
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -96,7 +107,7 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return shim.Error(fmt.Sprintf("Rejecting invoke of QSCC from another chaincode because of potential for deadlocks, original invocation for '%s'", name))
 	}
 
-	if fname != GetChainInfo && len(args) < 3 {
+	if fname != GetChainInfo && fname != GetStateCheckpoint && len(args) < 3 {
 		return shim.Error(fmt.Sprintf("missing 3rd argument for %s", fname))
 	}
 
@@ -124,6 +135,18 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetBlockExtension:
+		return getBlockExtension(targetLedger, args[2])
+	case GetBlockNumsByExtensionKey:
+		if len(args) < 4 {
+			return shim.Error(fmt.Sprintf("missing 4th argument for %s", fname))
+		}
+		return getBlockNumsByExtensionKey(targetLedger, args[2], args[3])
+	case GetStateCheckpoint:
+		if len(args) < 3 {
+			return getStateCheckpoint(targetLedger, nil)
+		}
+		return getStateCheckpoint(targetLedger, args[2])
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -223,6 +246,85 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func getBlockExtension(vledger ledger.PeerLedger, number []byte) pb.Response {
+	if number == nil {
+		return shim.Error("Block number must not be nil.")
+	}
+	bnum, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+	}
+	block, err := vledger.GetBlockByNumber(bnum)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+	}
+
+	// The extension is returned in full, rather than as decoded entries, so that the
+	// elements which are not entries are preserved. It is returned along with the header
+	// of the block and the block metadata signed by the orderers, so that the clients can
+	// verify it with protoutil.VerifiableBlockExtensionSignatureSet without the whole block.
+	extension := protoutil.NewVerifiableBlockExtension(block)
+	bytes, err := protoutil.Marshal(extension)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getBlockNumsByExtensionKey(vledger ledger.PeerLedger, typeURL []byte, key []byte) pb.Response {
+	if len(typeURL) == 0 {
+		return shim.Error("Extension type URL must not be empty.")
+	}
+	blockNums, err := vledger.GetBlockNumsByExtensionKey(string(typeURL), key)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get blocks for extension key of type %s, error %s", string(typeURL), err))
+	}
+
+	bytes, err := protoutil.Marshal(&blockextension.BlockNumbers{Numbers: blockNums})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getStateCheckpoint(vledger ledger.PeerLedger, number []byte) pb.Response {
+	var checkpoint *ledger.StateCheckpoint
+	var err error
+	if number == nil {
+		checkpoint, err = vledger.GetLatestStateCheckpoint()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get the latest state checkpoint, error %s", err))
+		}
+		if checkpoint == nil {
+			return shim.Error("No state checkpoint found.")
+		}
+	} else {
+		bnum, err := strconv.ParseUint(string(number), 10, 64)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+		}
+		checkpoint, err = vledger.GetStateCheckpoint(bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get the state checkpoint for block number %d, error %s", bnum, err))
+		}
+		if checkpoint == nil {
+			return shim.Error(fmt.Sprintf("No state checkpoint found for block number %d.", bnum))
+		}
+	}
+
+	bytes, err := protoutil.Marshal(&statecheckpoint.StateCheckpoint{
+		BlockNum:   checkpoint.BlockNumber,
+		Commitment: checkpoint.Commitment,
+	})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
@@ -12,11 +12,13 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-chaincode-go/shimtest"
 	"github.com/hyperledger/fabric-protos-go/common"
 	peer2 "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
@@ -24,6 +26,7 @@ import (
 	ledger2 "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -47,6 +50,12 @@ func setupTestLedger(chainid string, path string) (*shimtest.MockStub, *peer.Pee
 	}
 
 	initializer := ledgermgmttest.NewInitializer(testDir)
+	initializer.Config.ExtensionIndexConfig = &ledger2.ExtensionIndexConfig{
+		TypeURLs: []string{blockextension.ConfigEnvelopeType},
+	}
+	initializer.Config.StateCheckpointsConfig = &ledger2.StateCheckpointsConfig{
+		Interval: 1,
+	}
 
 	ledgerMgr := ledgermgmt.NewLedgerMgr(initializer)
 
@@ -213,6 +222,130 @@ func TestQueryGetBlockByTxID(t *testing.T) {
 	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxID should have failed with blank txId.")
 }
 
+func TestQueryGetBlockExtension(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := tempDir(t, "test9")
+	defer os.RemoveAll(path)
+
+	stub, _, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	// the genesis block carries its config envelope in its extension
+	args := [][]byte{[]byte(GetBlockExtension), []byte(chainid), []byte("0")}
+	prop := resetProvider(resources.Qscc_GetBlockExtension, chainid, nil, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockExtension should have succeeded for block number: 0")
+
+	extension := &blockextension.VerifiableBlockExtension{}
+	err = proto.Unmarshal(res.Payload, extension)
+	require.NoError(t, err)
+	require.Len(t, extension.Extension.ExtensionData, 1)
+	entry, err := protoutil.UnmarshalBlockExtensionEntry(extension.Extension.ExtensionData[0])
+	require.NoError(t, err)
+	assert.Equal(t, blockextension.ConfigEnvelopeType, entry.TypeUrl)
+	assert.Equal(t, uint64(0), extension.Header.Number)
+	// the genesis block is not signed by the orderers, hence its extension cannot be verified
+	_, err = protoutil.VerifiableBlockExtensionSignatureSet(extension)
+	assert.EqualError(t, err, "block [0] does not commit to its extension")
+
+	// block number 1 should not be present in the ledger
+	args = [][]byte{[]byte(GetBlockExtension), []byte(chainid), []byte("1")}
+	res = stub.MockInvoke("2", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockExtension should have failed with invalid number: 1")
+
+	// block number cannot be nil
+	args = [][]byte{[]byte(GetBlockExtension), []byte(chainid), []byte(nil)}
+	res = stub.MockInvoke("3", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockExtension should have failed with nil block number")
+}
+
+func TestQueryGetBlockNumsByExtensionKey(t *testing.T) {
+	chainid := "mytestchainid10"
+	path := tempDir(t, "test10")
+	defer os.RemoveAll(path)
+
+	stub, _, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	args := [][]byte{[]byte(GetBlockExtension), []byte(chainid), []byte("0")}
+	prop := resetProvider(resources.Qscc_GetBlockExtension, chainid, nil, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockExtension should have succeeded for block number: 0")
+	extension := &blockextension.VerifiableBlockExtension{}
+	require.NoError(t, proto.Unmarshal(res.Payload, extension))
+	entry, err := protoutil.UnmarshalBlockExtensionEntry(extension.Extension.ExtensionData[0])
+	require.NoError(t, err)
+
+	// the genesis block is indexed under its config envelope
+	args = [][]byte{[]byte(GetBlockNumsByExtensionKey), []byte(chainid), []byte(blockextension.ConfigEnvelopeType), entry.Payload}
+	prop = resetProvider(resources.Qscc_GetBlockNumsByExtensionKey, chainid, nil, nil)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockNumsByExtensionKey should have succeeded for the config envelope")
+	blockNums := &blockextension.BlockNumbers{}
+	require.NoError(t, proto.Unmarshal(res.Payload, blockNums))
+	assert.Equal(t, []uint64{0}, blockNums.Numbers)
+
+	// unknown key
+	args = [][]byte{[]byte(GetBlockNumsByExtensionKey), []byte(chainid), []byte(blockextension.ConfigEnvelopeType), []byte("unknown")}
+	res = stub.MockInvoke("3", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockNumsByExtensionKey should have failed with unknown key")
+
+	// type URL that is not indexed
+	args = [][]byte{[]byte(GetBlockNumsByExtensionKey), []byte(chainid), []byte(blockextension.TxIDMerkleRootType), []byte("key")}
+	res = stub.MockInvoke("4", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockNumsByExtensionKey should have failed with type URL that is not indexed")
+
+	// key is required
+	args = [][]byte{[]byte(GetBlockNumsByExtensionKey), []byte(chainid), []byte(blockextension.ConfigEnvelopeType)}
+	res = stub.MockInvoke("5", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockNumsByExtensionKey should have failed with missing key")
+	assert.Equal(t, "missing 4th argument for GetBlockNumsByExtensionKey", res.Message)
+}
+
+func TestQueryGetStateCheckpoint(t *testing.T) {
+	chainid := "mytestchainid11"
+	path := tempDir(t, "test11")
+	defer os.RemoveAll(path)
+
+	stub, _, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	// the latest state checkpoint is returned when no block number is specified
+	args := [][]byte{[]byte(GetStateCheckpoint), []byte(chainid)}
+	prop := resetProvider(resources.Qscc_GetStateCheckpoint, chainid, nil, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetStateCheckpoint failed with err: %s", res.Message)
+	latestCheckpoint := &statecheckpoint.StateCheckpoint{}
+	require.NoError(t, proto.Unmarshal(res.Payload, latestCheckpoint))
+	assert.Equal(t, uint64(0), latestCheckpoint.BlockNum)
+	assert.NotEmpty(t, latestCheckpoint.Commitment)
+
+	args = [][]byte{[]byte(GetStateCheckpoint), []byte(chainid), []byte("0")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetStateCheckpoint should have succeeded for block number: 0")
+	checkpoint := &statecheckpoint.StateCheckpoint{}
+	require.NoError(t, proto.Unmarshal(res.Payload, checkpoint))
+	assert.True(t, proto.Equal(latestCheckpoint, checkpoint))
+
+	// block number 1 should not be present in the ledger
+	args = [][]byte{[]byte(GetStateCheckpoint), []byte(chainid), []byte("1")}
+	res = stub.MockInvoke("3", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetStateCheckpoint should have failed with invalid number: 1")
+
+	args = [][]byte{[]byte(GetStateCheckpoint), []byte(chainid), []byte("abc")}
+	res = stub.MockInvoke("4", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetStateCheckpoint should have failed with unparsable block number")
+}
+
 func TestFailingCC2CC(t *testing.T) {
 	t.Run("BadProposal", func(t *testing.T) {
 		stub := shimtest.NewMockStub("testchannel", &LedgerQuerier{})
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+type stats struct {
+	entries         metrics.Gauge
+	size            metrics.Gauge
+	quotaRejections metrics.Counter
+}
+
+func newStats(metricsProvider metrics.Provider) *stats {
+	stats := &stats{}
+	stats.entries = metricsProvider.NewGauge(entriesOpts)
+	stats.size = metricsProvider.NewGauge(sizeOpts)
+	stats.quotaRejections = metricsProvider.NewCounter(quotaRejectionsOpts)
+	return stats
+}
+
+// storeStats defines the metrics of the transient store of a channel
+type storeStats struct {
+	stats    *stats
+	ledgerID string
+}
+
+func (s *stats) storeStats(ledgerID string) *storeStats {
+	return &storeStats{
+		s, ledgerID,
+	}
+}
+
+func (s *storeStats) updateUsage(u *usage) {
+	s.stats.entries.With("channel", s.ledgerID).Set(float64(u.entries))
+	s.stats.size.With("channel", s.ledgerID).Set(float64(u.bytes))
+}
+
+func (s *storeStats) addQuotaRejection(quota string) {
+	s.stats.quotaRejections.With("channel", s.ledgerID, "quota", quota).Add(1)
+}
+
+var (
+	entriesOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Subsystem:    "",
+		Name:         "entries",
+		Help:         "Number of private write sets held in the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	sizeOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Subsystem:    "",
+		Name:         "size_bytes",
+		Help:         "Size in bytes of the private write sets held in the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	quotaRejectionsOpts = metrics.CounterOpts{
+		Namespace:    "transientstore",
+		Subsystem:    "",
+		Name:         "quota_rejections",
+		Help:         "Number of private write sets rejected because they would exceed a quota.",
+		LabelNames:   []string{"channel", "quota"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{quota}",
+	}
+)
@@ -0,0 +1,273 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/pkg/errors"
+)
+
+// Config holds the limits enforced by the transient store on the private write sets
+// of the transactions that are not yet committed
+type Config struct {
+	// MaxAge is the age after which a private write set is expired by PurgeExpired,
+	// irrespective of the block height it was received at. Zero disables the expiry by age.
+	MaxAge time.Duration
+	// CollectionQuotaBytes is the maximum number of bytes of private write sets that
+	// a collection may hold in the transient store of a channel. Zero means no quota.
+	CollectionQuotaBytes uint64
+	// ClientQuotaBytes is the maximum number of bytes of private write sets that the
+	// transactions of a client, endorsed by this peer, may hold in the transient store
+	// of a channel. Zero means no quota.
+	ClientQuotaBytes uint64
+}
+
+// QuotaExceededError is returned by PersistForClient when persisting a private write set
+// would exceed the quota of one of its collections or of its client
+type QuotaExceededError struct {
+	// Quota is either "collection" or "client"
+	Quota string
+	// Owner is the collection, in the form <namespace>/<collection>, or the client
+	// whose quota would be exceeded
+	Owner     string
+	Used      uint64
+	Requested uint64
+	Limit     uint64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("transient store quota of %s [%s] exceeded: %d bytes in use, %d bytes requested, quota is %d bytes",
+		e.Quota, e.Owner, e.Used, e.Requested, e.Limit)
+}
+
+// CollectionSize is the number of bytes of the private write set of a collection
+type CollectionSize struct {
+	Namespace  string
+	Collection string
+	Bytes      uint64
+}
+
+// EntryInfo describes a private write set stored in the transient store
+type EntryInfo struct {
+	TxID                  string
+	UUID                  string
+	ReceivedAtBlockHeight uint64
+	// PersistedAt is zero for the private write sets persisted before the transient
+	// store started recording it
+	PersistedAt time.Time
+	// Client is empty for the private write sets that were received from other peers
+	Client      string
+	Bytes       uint64
+	Collections []*CollectionSize
+}
+
+// ClientID returns the identifier under which the transient store accounts the private
+// write sets of the transactions created by the given serialized identity. The identifier
+// is composed of the MSP ID of the creator and of the SHA256 hash of the serialized identity.
+func ClientID(creator []byte) string {
+	if len(creator) == 0 {
+		return ""
+	}
+	hash := sha256.Sum256(creator)
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, sID); err != nil {
+		return hex.EncodeToString(hash[:])
+	}
+	return sID.Mspid + ":" + hex.EncodeToString(hash[:])
+}
+
+func newEntryInfo(txid, uuid string, blockHeight uint64, persistedAt time.Time, client string,
+	value []byte, pvtSimulationResults *transientstore.TxPvtReadWriteSetWithConfigInfo) *EntryInfo {
+	info := &EntryInfo{
+		TxID:                  txid,
+		UUID:                  uuid,
+		ReceivedAtBlockHeight: blockHeight,
+		PersistedAt:           persistedAt,
+		Client:                client,
+		Bytes:                 uint64(len(value)),
+	}
+	for _, ns := range pvtSimulationResults.GetPvtRwset().GetNsPvtRwset() {
+		for _, coll := range ns.CollectionPvtRwset {
+			info.Collections = append(info.Collections, &CollectionSize{
+				Namespace:  ns.Namespace,
+				Collection: coll.CollectionName,
+				Bytes:      uint64(len(coll.Rwset)),
+			})
+		}
+	}
+	return info
+}
+
+// encodeEntryInfo encodes the details of an entry that are not part of its keys, i.e., the time
+// it was persisted at, its client, and its size. The encoded bytes are stored as the value of
+// the purge index by txid of the entry.
+func encodeEntryInfo(info *EntryInfo) ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	var persistedAt uint64
+	if !info.PersistedAt.IsZero() {
+		persistedAt = uint64(info.PersistedAt.UnixNano())
+	}
+	if err := buf.EncodeVarint(persistedAt); err != nil {
+		return nil, err
+	}
+	if err := buf.EncodeStringBytes(info.Client); err != nil {
+		return nil, err
+	}
+	if err := buf.EncodeVarint(info.Bytes); err != nil {
+		return nil, err
+	}
+	if err := buf.EncodeVarint(uint64(len(info.Collections))); err != nil {
+		return nil, err
+	}
+	for _, coll := range info.Collections {
+		if err := buf.EncodeStringBytes(coll.Namespace); err != nil {
+			return nil, err
+		}
+		if err := buf.EncodeStringBytes(coll.Collection); err != nil {
+			return nil, err
+		}
+		if err := buf.EncodeVarint(coll.Bytes); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeEntryInfo decodes the bytes produced by encodeEntryInfo into the given info
+func decodeEntryInfo(b []byte, info *EntryInfo) error {
+	buf := proto.NewBuffer(b)
+	persistedAt, err := buf.DecodeVarint()
+	if err != nil {
+		return errors.Wrap(err, "error decoding the persisted at time of the entry")
+	}
+	if persistedAt != 0 {
+		info.PersistedAt = time.Unix(0, int64(persistedAt))
+	}
+	if info.Client, err = buf.DecodeStringBytes(); err != nil {
+		return errors.Wrap(err, "error decoding the client of the entry")
+	}
+	if info.Bytes, err = buf.DecodeVarint(); err != nil {
+		return errors.Wrap(err, "error decoding the size of the entry")
+	}
+	numCollections, err := buf.DecodeVarint()
+	if err != nil {
+		return errors.Wrap(err, "error decoding the number of collections of the entry")
+	}
+	info.Collections = nil
+	for i := uint64(0); i < numCollections; i++ {
+		coll := &CollectionSize{}
+		if coll.Namespace, err = buf.DecodeStringBytes(); err != nil {
+			return errors.Wrap(err, "error decoding the namespace of the entry")
+		}
+		if coll.Collection, err = buf.DecodeStringBytes(); err != nil {
+			return errors.Wrap(err, "error decoding the collection of the entry")
+		}
+		if coll.Bytes, err = buf.DecodeVarint(); err != nil {
+			return errors.Wrap(err, "error decoding the collection size of the entry")
+		}
+		info.Collections = append(info.Collections, coll)
+	}
+	return nil
+}
+
+type collectionKey struct {
+	namespace  string
+	collection string
+}
+
+func (k collectionKey) String() string {
+	return k.namespace + "/" + k.collection
+}
+
+// usage keeps track of the number and the size of the entries held in the transient store of a
+// channel, in total, per collection, and per client
+type usage struct {
+	entries     uint64
+	bytes       uint64
+	collections map[collectionKey]uint64
+	clients     map[string]uint64
+}
+
+func newUsage() *usage {
+	return &usage{
+		collections: map[collectionKey]uint64{},
+		clients:     map[string]uint64{},
+	}
+}
+
+// checkQuotas returns a QuotaExceededError if adding the given entry would exceed a quota
+func (u *usage) checkQuotas(config *Config, info *EntryInfo) error {
+	if config.CollectionQuotaBytes > 0 {
+		for _, coll := range info.Collections {
+			key := collectionKey{coll.Namespace, coll.Collection}
+			if used := u.collections[key]; used+coll.Bytes > config.CollectionQuotaBytes {
+				return &QuotaExceededError{
+					Quota:     "collection",
+					Owner:     key.String(),
+					Used:      used,
+					Requested: coll.Bytes,
+					Limit:     config.CollectionQuotaBytes,
+				}
+			}
+		}
+	}
+	if config.ClientQuotaBytes > 0 && info.Client != "" {
+		if used := u.clients[info.Client]; used+info.Bytes > config.ClientQuotaBytes {
+			return &QuotaExceededError{
+				Quota:     "client",
+				Owner:     info.Client,
+				Used:      used,
+				Requested: info.Bytes,
+				Limit:     config.ClientQuotaBytes,
+			}
+		}
+	}
+	return nil
+}
+
+func (u *usage) add(info *EntryInfo) {
+	u.entries++
+	u.bytes += info.Bytes
+	for _, coll := range info.Collections {
+		u.collections[collectionKey{coll.Namespace, coll.Collection}] += coll.Bytes
+	}
+	if info.Client != "" {
+		u.clients[info.Client] += info.Bytes
+	}
+}
+
+func (u *usage) remove(info *EntryInfo) {
+	u.entries = subtract(u.entries, 1)
+	u.bytes = subtract(u.bytes, info.Bytes)
+	for _, coll := range info.Collections {
+		key := collectionKey{coll.Namespace, coll.Collection}
+		if u.collections[key] = subtract(u.collections[key], coll.Bytes); u.collections[key] == 0 {
+			delete(u.collections, key)
+		}
+	}
+	if info.Client != "" {
+		if u.clients[info.Client] = subtract(u.clients[info.Client], info.Bytes); u.clients[info.Client] == 0 {
+			delete(u.clients, info.Client)
+		}
+	}
+}
+
+// subtract returns a-b, or zero if b is greater than a, so that purging an entry that was
+// not accounted cannot underflow the usage
+func subtract(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,284 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStoreWithConfig(t *testing.T, config *Config) (*Store, StoreProvider, func()) {
+	tempdir, err := ioutil.TempDir("", "ts")
+	require.NoError(t, err)
+	storeProvider, err := NewStoreProviderWithConfig(tempdir, config, &disabled.Provider{})
+	require.NoError(t, err)
+	store, err := storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	return store, storeProvider, func() {
+		storeProvider.Close()
+		os.RemoveAll(tempdir)
+	}
+}
+
+func pvtDataOfCollection(namespace, collection string, size int) *transientstore.TxPvtReadWriteSetWithConfigInfo {
+	return &transientstore.TxPvtReadWriteSetWithConfigInfo{
+		PvtRwset: &rwset.TxPvtReadWriteSet{
+			DataModel: rwset.TxReadWriteSet_KV,
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+				{
+					Namespace: namespace,
+					CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+						{
+							CollectionName: collection,
+							Rwset:          make([]byte, size),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func serializedIdentity(t *testing.T, mspID string, idBytes string) []byte {
+	creator, err := proto.Marshal(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(idBytes)})
+	require.NoError(t, err)
+	return creator
+}
+
+func TestEntryInfoEncoding(t *testing.T) {
+	info := &EntryInfo{
+		PersistedAt: time.Unix(0, 1600000000123456789),
+		Client:      "Org1MSP:0a1b",
+		Bytes:       120,
+		Collections: []*CollectionSize{
+			{Namespace: "ns-1", Collection: "coll-1", Bytes: 30},
+			{Namespace: "ns-2", Collection: "coll-2", Bytes: 40},
+		},
+	}
+	infoBytes, err := encodeEntryInfo(info)
+	require.NoError(t, err)
+	decodedInfo := &EntryInfo{}
+	require.NoError(t, decodeEntryInfo(infoBytes, decodedInfo))
+	require.Equal(t, info, decodedInfo)
+
+	infoBytes, err = encodeEntryInfo(&EntryInfo{})
+	require.NoError(t, err)
+	decodedInfo = &EntryInfo{}
+	require.NoError(t, decodeEntryInfo(infoBytes, decodedInfo))
+	require.True(t, decodedInfo.PersistedAt.IsZero())
+
+	err = decodeEntryInfo([]byte{0x01}, &EntryInfo{})
+	require.EqualError(t, err, "error decoding the client of the entry: unexpected EOF")
+}
+
+func TestPurgeIndexByAgeKeyEncoding(t *testing.T) {
+	for _, txid := range []string{"txid", ""} {
+		for _, uuid := range []string{"uuid", ""} {
+			key := createCompositeKeyForPurgeIndexByAge(1600000000123456789, txid, uuid, 20000)
+			txid1, uuid1, blkHt1, err := splitCompositeKeyOfPurgeIndexByAge(key)
+			require.NoError(t, err)
+			require.Equal(t, txid, txid1)
+			require.Equal(t, uuid, uuid1)
+			require.Equal(t, uint64(20000), blkHt1)
+
+			key = createCompositeKeyForPurgeIndexByTxid(txid, uuid, 20000)
+			txid1, uuid1, blkHt1, err = splitCompositeKeyOfPurgeIndexByTxidWithTxid(key)
+			require.NoError(t, err)
+			require.Equal(t, txid, txid1)
+			require.Equal(t, uuid, uuid1)
+			require.Equal(t, uint64(20000), blkHt1)
+		}
+	}
+}
+
+func TestClientID(t *testing.T) {
+	require.Equal(t, "", ClientID(nil))
+	creator := serializedIdentity(t, "Org1MSP", "cert")
+	clientID := ClientID(creator)
+	require.Regexp(t, "^Org1MSP:[0-9a-f]{64}$", clientID)
+	require.Equal(t, clientID, ClientID(creator))
+	require.NotEqual(t, clientID, ClientID(serializedIdentity(t, "Org1MSP", "another-cert")))
+	require.Regexp(t, "^[0-9a-f]{64}$", ClientID([]byte("not-a-serialized-identity")))
+}
+
+func TestCollectionQuota(t *testing.T) {
+	store, _, cleanup := newTestStoreWithConfig(t, &Config{CollectionQuotaBytes: 250})
+	defer cleanup()
+
+	require.NoError(t, store.Persist("tx1", 10, pvtDataOfCollection("ns-1", "coll-1", 100)))
+	require.NoError(t, store.Persist("tx2", 10, pvtDataOfCollection("ns-1", "coll-1", 100)))
+	// the quota is per collection
+	require.NoError(t, store.Persist("tx3", 10, pvtDataOfCollection("ns-1", "coll-2", 200)))
+
+	err := store.Persist("tx4", 10, pvtDataOfCollection("ns-1", "coll-1", 100))
+	require.EqualError(t, err, "transient store quota of collection [ns-1/coll-1] exceeded: 200 bytes in use, 100 bytes requested, quota is 250 bytes")
+	require.IsType(t, &QuotaExceededError{}, err)
+	entries, err := store.ListEntries("tx4")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// purging releases the usage of the collection
+	require.NoError(t, store.PurgeByTxids([]string{"tx1"}))
+	require.NoError(t, store.Persist("tx4", 10, pvtDataOfCollection("ns-1", "coll-1", 100)))
+}
+
+func TestClientQuota(t *testing.T) {
+	store, _, cleanup := newTestStoreWithConfig(t, &Config{ClientQuotaBytes: 300})
+	defer cleanup()
+
+	client1 := serializedIdentity(t, "Org1MSP", "client1")
+	client2 := serializedIdentity(t, "Org1MSP", "client2")
+	require.NoError(t, store.PersistForClient("tx1", 10, pvtDataOfCollection("ns-1", "coll-1", 200), client1))
+	require.NoError(t, store.PersistForClient("tx2", 10, pvtDataOfCollection("ns-1", "coll-1", 200), client2))
+	// private data received from other peers is not accounted to any client
+	require.NoError(t, store.Persist("tx3", 10, pvtDataOfCollection("ns-1", "coll-1", 200)))
+	require.NoError(t, store.Persist("tx4", 10, pvtDataOfCollection("ns-1", "coll-1", 200)))
+
+	err := store.PersistForClient("tx5", 10, pvtDataOfCollection("ns-1", "coll-2", 200), client1)
+	require.IsType(t, &QuotaExceededError{}, err)
+	require.Equal(t, "client", err.(*QuotaExceededError).Quota)
+	require.Equal(t, ClientID(client1), err.(*QuotaExceededError).Owner)
+
+	require.NoError(t, store.PurgeByClient(ClientID(client1)))
+	entries, err := store.ListEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, e := range entries {
+		require.NotEqual(t, ClientID(client1), e.Client)
+	}
+	require.NoError(t, store.PersistForClient("tx5", 10, pvtDataOfCollection("ns-1", "coll-2", 200), client1))
+}
+
+func TestPurgeOlderThan(t *testing.T) {
+	store, _, cleanup := newTestStoreWithConfig(t, &Config{MaxAge: time.Hour})
+	defer cleanup()
+
+	require.NoError(t, store.Persist("tx1", 10, pvtDataOfCollection("ns-1", "coll-1", 10)))
+	require.NoError(t, store.Persist("tx2", 20, pvtDataOfCollection("ns-1", "coll-1", 10)))
+	entries, err := store.ListEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	tx2PersistedAt := entries[1].PersistedAt
+	require.False(t, tx2PersistedAt.IsZero())
+	require.False(t, tx2PersistedAt.Before(entries[0].PersistedAt))
+
+	// none of the entries has expired
+	require.NoError(t, store.PurgeExpired())
+	entries, err = store.ListEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	require.NoError(t, store.PurgeOlderThan(tx2PersistedAt))
+	entries, err = store.ListEntries("")
+	require.NoError(t, err)
+	if len(entries) == 1 {
+		require.Equal(t, "tx2", entries[0].TxID)
+	} else {
+		// both entries were persisted within the same clock tick
+		require.Len(t, entries, 2)
+	}
+
+	require.NoError(t, store.PurgeOlderThan(time.Now().Add(time.Second)))
+	entries, err = store.ListEntries("")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, uint64(0), store.usage.entries)
+	require.Equal(t, uint64(0), store.usage.bytes)
+
+	// the purge index by age is removed along with the entry by the other purges
+	require.NoError(t, store.Persist("tx3", 30, pvtDataOfCollection("ns-1", "coll-1", 10)))
+	require.NoError(t, store.PurgeBelowHeight(31))
+	iter, err := store.db.GetIterator(createPurgeIndexByAgeRangeStartKey(), createPurgeIndexByAgeRangeEndKey(uint64(time.Now().Add(time.Hour).UnixNano())))
+	require.NoError(t, err)
+	defer iter.Release()
+	require.False(t, iter.Next())
+}
+
+func TestUsageAndMetrics(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "ts")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	fakeEntriesGauge := &metricsfakes.Gauge{}
+	fakeEntriesGauge.WithReturns(fakeEntriesGauge)
+	fakeSizeGauge := &metricsfakes.Gauge{}
+	fakeSizeGauge.WithReturns(fakeSizeGauge)
+	fakeQuotaRejectionsCounter := &metricsfakes.Counter{}
+	fakeQuotaRejectionsCounter.WithReturns(fakeQuotaRejectionsCounter)
+	metricsProvider := &metricsfakes.Provider{}
+	metricsProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
+		switch opts.Name {
+		case entriesOpts.Name:
+			return fakeEntriesGauge
+		case sizeOpts.Name:
+			return fakeSizeGauge
+		}
+		return nil
+	}
+	metricsProvider.NewCounterReturns(fakeQuotaRejectionsCounter)
+
+	config := &Config{CollectionQuotaBytes: 150}
+	storeProvider, err := NewStoreProviderWithConfig(tempdir, config, metricsProvider)
+	require.NoError(t, err)
+	store, err := storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	require.Equal(t, []string{"channel", "TestStore"}, fakeEntriesGauge.WithArgsForCall(0))
+	require.Equal(t, float64(0), fakeEntriesGauge.SetArgsForCall(0))
+
+	require.NoError(t, store.Persist("tx1", 10, pvtDataOfCollection("ns-1", "coll-1", 100)))
+	require.NoError(t, store.persistOldProto("tx2", 10, pvtDataOfCollection("ns-1", "coll-2", 100).PvtRwset))
+	require.Error(t, store.Persist("tx3", 10, pvtDataOfCollection("ns-1", "coll-1", 100)))
+	require.Equal(t, 1, fakeQuotaRejectionsCounter.AddCallCount())
+	require.Equal(t, []string{"channel", "TestStore", "quota", "collection"}, fakeQuotaRejectionsCounter.WithArgsForCall(0))
+
+	entries, err := store.ListEntries("")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	var expectedBytes uint64
+	for _, e := range entries {
+		expectedBytes += e.Bytes
+		require.Equal(t, uint64(100), e.Collections[0].Bytes)
+	}
+	// the entry persisted with the old proto does not record the time it was persisted at
+	require.True(t, entries[1].PersistedAt.IsZero())
+
+	// the usage, including the entry persisted without recording its size, is computed when the store is reopened
+	storeProvider.Close()
+	storeProvider, err = NewStoreProviderWithConfig(tempdir, config, metricsProvider)
+	require.NoError(t, err)
+	defer storeProvider.Close()
+	store, err = storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), store.usage.entries)
+	require.Equal(t, expectedBytes, store.usage.bytes)
+	require.Equal(t, float64(2), fakeEntriesGauge.SetArgsForCall(fakeEntriesGauge.SetCallCount()-1))
+	require.Equal(t, float64(expectedBytes), fakeSizeGauge.SetArgsForCall(fakeSizeGauge.SetCallCount()-1))
+	require.EqualError(t, store.Persist("tx3", 10, pvtDataOfCollection("ns-1", "coll-2", 100)),
+		"transient store quota of collection [ns-1/coll-2] exceeded: 100 bytes in use, 100 bytes requested, quota is 150 bytes")
+
+	require.NoError(t, store.PurgeBelowHeight(11))
+	require.Equal(t, uint64(0), store.usage.entries)
+	require.Empty(t, store.usage.collections)
+	require.Equal(t, float64(0), fakeEntriesGauge.SetArgsForCall(fakeEntriesGauge.SetCallCount()-1))
+	require.Equal(t, float64(0), fakeSizeGauge.SetArgsForCall(fakeSizeGauge.SetCallCount()-1))
+}
+
+func TestNegativeMaxAge(t *testing.T) {
+	_, err := NewStoreProviderWithConfig("", &Config{MaxAge: -time.Second}, &disabled.Provider{})
+	require.EqualError(t, err, "the max age of the transient store cannot be negative")
+}
@@ -8,12 +8,16 @@ package transientstore
 
 import (
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -63,12 +67,23 @@ type EndorserPvtSimulationResults struct {
 // interface.
 type storeProvider struct {
 	dbProvider *leveldbhelper.Provider
+	config     *Config
+	stats      *stats
 }
 
 // store holds an instance of a levelDB.
 type Store struct {
 	db       *leveldbhelper.DBHandle
 	ledgerID string
+	config   *Config
+	stats    *storeStats
+
+	// usageMutex guards the usage, which is reserved before persisting an entry
+	// and released after purging it
+	usageMutex sync.Mutex
+	usage      *usage
+	// purgeMutex serializes the purges so that the usage of an entry is released only once
+	purgeMutex sync.Mutex
 }
 
 // RwsetScanner helps iterating over results
@@ -80,17 +95,46 @@ type RwsetScanner struct {
 
 // NewStoreProvider instantiates TransientStoreProvider
 func NewStoreProvider(path string) (StoreProvider, error) {
+	return NewStoreProviderWithConfig(path, &Config{}, &disabled.Provider{})
+}
+
+// NewStoreProviderWithConfig instantiates TransientStoreProvider that enforces the
+// limits of the given config and reports its usage to the given metrics provider
+func NewStoreProviderWithConfig(path string, config *Config, metricsProvider metrics.Provider) (StoreProvider, error) {
+	if config.MaxAge < 0 {
+		return nil, errors.New("the max age of the transient store cannot be negative")
+	}
 	dbProvider, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: path})
 	if err != nil {
 		return nil, err
 	}
-	return &storeProvider{dbProvider: dbProvider}, nil
+	return &storeProvider{
+		dbProvider: dbProvider,
+		config:     config,
+		stats:      newStats(metricsProvider),
+	}, nil
 }
 
 // OpenStore returns a handle to a ledgerId in Store
 func (provider *storeProvider) OpenStore(ledgerID string) (*Store, error) {
 	dbHandle := provider.dbProvider.GetDBHandle(ledgerID)
-	return &Store{db: dbHandle, ledgerID: ledgerID}, nil
+	s := &Store{
+		db:       dbHandle,
+		ledgerID: ledgerID,
+		config:   provider.config,
+		stats:    provider.stats.storeStats(ledgerID),
+		usage:    newUsage(),
+	}
+	// the usage is not persisted, it is computed from the entries held in the store
+	entries, err := s.ListEntries("")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		s.usage.add(e)
+	}
+	s.stats.updateUsage(s.usage)
+	return s, nil
 }
 
 // Close closes the TransientStoreProvider
@@ -102,6 +146,20 @@ func (provider *storeProvider) Close() {
 // in the transient store based on txid and the block height the private data was received at
 func (s *Store) Persist(txid string, blockHeight uint64,
 	privateSimulationResultsWithConfig *transientstore.TxPvtReadWriteSetWithConfigInfo) error {
+	return s.persist(txid, blockHeight, privateSimulationResultsWithConfig, "")
+}
+
+// PersistForClient stores the private write set of a transaction endorsed by this peer on
+// behalf of the client identified by the given creator. In addition to the quotas of the
+// collections, the private write set is accounted against the quota of the client.
+// A QuotaExceededError is returned if either quota would be exceeded.
+func (s *Store) PersistForClient(txid string, blockHeight uint64,
+	privateSimulationResultsWithConfig *transientstore.TxPvtReadWriteSetWithConfigInfo, creator []byte) error {
+	return s.persist(txid, blockHeight, privateSimulationResultsWithConfig, ClientID(creator))
+}
+
+func (s *Store) persist(txid string, blockHeight uint64,
+	privateSimulationResultsWithConfig *transientstore.TxPvtReadWriteSetWithConfigInfo, client string) error {
 
 	logger.Debugf("Persisting private data to transient store for txid [%s] at block height [%d]", txid, blockHeight)
 
@@ -136,19 +194,41 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 	dbBatch.Put(compositeKeyPurgeIndexByHeight, emptyValue)
 
 	// Create compositeKey for purge index by txid with appropriate prefix, txid, uuid,
-	// blockHeight and store the compositeKey (purge index) with a nil byte as value.
+	// blockHeight and store the compositeKey (purge index) with the encoded entry info as value.
 	// Though compositeKeyPvtRWSet itself can be used to purge private write set by txid,
-	// we create a separate composite key with a nil byte as value. The reason is that
+	// we create a separate composite key with a small value. The reason is that
 	// if we use compositeKeyPvtRWSet, we unnecessarily read (potentially large) private write
 	// set associated with the key from db. Note that this purge index is used to remove non-orphan
-	// entries in the transient store and is used by PurgeTxids()
+	// entries in the transient store and is used by PurgeTxids(). The entry info carries the time
+	// the entry was persisted at, its client and its size, which are needed to release its share
+	// of the quotas and to remove its purge index by age when the entry is purged.
 	// Note: We can create compositeKeyPurgeIndexByTxid by just replacing the prefix of compositeKeyPvtRWSet
 	// with purgeIndexByTxidPrefix. For code readability and to be expressive, we use a
 	// createCompositeKeyForPurgeIndexByTxid() instead.
+	persistedAt := time.Now()
+	info := newEntryInfo(txid, uuid, blockHeight, persistedAt, client, value, privateSimulationResultsWithConfig)
+	infoBytes, err := encodeEntryInfo(info)
+	if err != nil {
+		return err
+	}
 	compositeKeyPurgeIndexByTxid := createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight)
-	dbBatch.Put(compositeKeyPurgeIndexByTxid, emptyValue)
+	dbBatch.Put(compositeKeyPurgeIndexByTxid, infoBytes)
 
-	return s.db.WriteBatch(dbBatch, true)
+	// Create compositeKey for purge index by age with appropriate prefix, the time the entry is
+	// persisted at, txid, uuid, blockHeight and store the compositeKey (purge index) with a nil byte
+	// as value. This purge index is used by PurgeExpired() to remove the entries that are older than
+	// the max age, irrespective of the block height they were received at.
+	compositeKeyPurgeIndexByAge := createCompositeKeyForPurgeIndexByAge(uint64(persistedAt.UnixNano()), txid, uuid, blockHeight)
+	dbBatch.Put(compositeKeyPurgeIndexByAge, emptyValue)
+
+	if err := s.reserve(info); err != nil {
+		return err
+	}
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		s.release(info)
+		return err
+	}
+	return nil
 }
 
 // GetTxPvtRWSetByTxid returns an iterator due to the fact that the txid may have multiple private
@@ -175,8 +255,10 @@ func (s *Store) PurgeByTxids(txids []string) error {
 
 	logger.Debug("Purging private data from transient store for committed txids")
 
-	dbBatch := s.db.NewUpdateBatch()
+	s.purgeMutex.Lock()
+	defer s.purgeMutex.Unlock()
 
+	var entries []*EntryInfo
 	for _, txid := range txids {
 		// Construct startKey and endKey to do an range query
 		startKey := createPurgeIndexByTxidRangeStartKey(txid)
@@ -190,31 +272,25 @@ func (s *Store) PurgeByTxids(txids []string) error {
 		// Get all txid and uuid from above result and remove it from transient store (both
 		// write set and the corresponding indexes.
 		for iter.Next() {
-			// For each entry, remove the private read-write set and corresponding indexes
-
-			// Remove private write set
-			compositeKeyPurgeIndexByTxid := iter.Key()
 			// Note: We can create compositeKeyPvtRWSet by just replacing the prefix of compositeKeyPurgeIndexByTxid
 			// with  prwsetPrefix. For code readability and to be expressive, we split and create again.
-			uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByTxid(compositeKeyPurgeIndexByTxid)
+			uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByTxid(iter.Key())
 			if err != nil {
+				iter.Release()
 				return err
 			}
-			compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
-			dbBatch.Delete(compositeKeyPvtRWSet)
-
-			// Remove purge index -- purgeIndexByHeight
-			compositeKeyPurgeIndexByHeight := createCompositeKeyForPurgeIndexByHeight(blockHeight, txid, uuid)
-			dbBatch.Delete(compositeKeyPurgeIndexByHeight)
-
-			// Remove purge index -- purgeIndexByTxid
-			dbBatch.Delete(compositeKeyPurgeIndexByTxid)
+			entry, err := s.retrieveEntryInfo(txid, uuid, blockHeight, iter.Value())
+			if err != nil {
+				iter.Release()
+				return err
+			}
+			entries = append(entries, entry)
 		}
 		iter.Release()
 	}
 	// If peer fails before/while writing the batch to golevelDB, these entries will be
 	// removed as per BTL policy later by PurgeBelowHeight()
-	return s.db.WriteBatch(dbBatch, true)
+	return s.purgeEntries(entries)
 }
 
 // PurgeBelowHeight removes private write sets at block height lesser than
@@ -227,6 +303,9 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 
 	logger.Debugf("Purging orphaned private data from transient store received prior to block [%d]", maxBlockNumToRetain)
 
+	s.purgeMutex.Lock()
+	defer s.purgeMutex.Unlock()
+
 	// Do a range query with 0 as startKey and maxBlockNumToRetain-1 as endKey
 	startKey := createPurgeIndexByHeightRangeStartKey(0)
 	endKey := createPurgeIndexByHeightRangeEndKey(maxBlockNumToRetain - 1)
@@ -234,35 +313,216 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 	if err != nil {
 		return err
 	}
-
-	dbBatch := s.db.NewUpdateBatch()
+	defer iter.Release()
 
 	// Get all txid and uuid from above result and remove it from transient store (both
 	// write set and the corresponding index.
+	var entries []*EntryInfo
 	for iter.Next() {
-		// For each entry, remove the private read-write set and corresponding indexes
-
-		// Remove private write set
-		compositeKeyPurgeIndexByHeight := iter.Key()
-		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByHeight(compositeKeyPurgeIndexByHeight)
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByHeight(iter.Key())
 		if err != nil {
 			return err
 		}
 		logger.Debugf("Purging from transient store private data simulated at block [%d]: txid [%s] uuid [%s]", blockHeight, txid, uuid)
 
-		compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
-		dbBatch.Delete(compositeKeyPvtRWSet)
+		entry, err := s.retrieveEntryInfoByKey(txid, uuid, blockHeight)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
 
-		// Remove purge index -- purgeIndexByTxid
-		compositeKeyPurgeIndexByTxid := createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight)
-		dbBatch.Delete(compositeKeyPurgeIndexByTxid)
+	return s.purgeEntries(entries)
+}
+
+// PurgeExpired removes the private write sets that were persisted longer than the max age of
+// the transient store ago. It does nothing when the max age is not configured.
+func (s *Store) PurgeExpired() error {
+	if s.config.MaxAge == 0 {
+		return nil
+	}
+	return s.PurgeOlderThan(time.Now().Add(-s.config.MaxAge))
+}
+
+// PurgeOlderThan removes the private write sets that were persisted before the given time.
+// The private write sets persisted before the transient store started recording the time
+// they were persisted at are not removed.
+func (s *Store) PurgeOlderThan(persistedBefore time.Time) error {
+
+	logger.Debugf("Purging private data from transient store persisted before [%s]", persistedBefore)
+
+	s.purgeMutex.Lock()
+	defer s.purgeMutex.Unlock()
+
+	startKey := createPurgeIndexByAgeRangeStartKey()
+	endKey := createPurgeIndexByAgeRangeEndKey(uint64(persistedBefore.UnixNano()))
+	iter, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	var entries []*EntryInfo
+	for iter.Next() {
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByAge(iter.Key())
+		if err != nil {
+			return err
+		}
+		entry, err := s.retrieveEntryInfoByKey(txid, uuid, blockHeight)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	return s.purgeEntries(entries)
+}
 
+// PurgeByClient removes the private write sets of the transactions of the given client,
+// as identified by ClientID
+func (s *Store) PurgeByClient(client string) error {
+
+	logger.Debugf("Purging private data from transient store for client [%s]", client)
+
+	s.purgeMutex.Lock()
+	defer s.purgeMutex.Unlock()
+
+	entries, err := s.ListEntries("")
+	if err != nil {
+		return err
+	}
+	var clientEntries []*EntryInfo
+	for _, e := range entries {
+		if e.Client == client {
+			clientEntries = append(clientEntries, e)
+		}
+	}
+	return s.purgeEntries(clientEntries)
+}
+
+// ListEntries returns the details of the private write sets of the given transaction, or of
+// all the private write sets held in the transient store when the txid is empty
+func (s *Store) ListEntries(txid string) ([]*EntryInfo, error) {
+	startKey := createPurgeIndexByTxidRangeStartKeyForAll()
+	endKey := createPurgeIndexByTxidRangeEndKeyForAll()
+	if txid != "" {
+		startKey = createPurgeIndexByTxidRangeStartKey(txid)
+		endKey = createPurgeIndexByTxidRangeEndKey(txid)
+	}
+	iter, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	var entries []*EntryInfo
+	for iter.Next() {
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByTxidWithTxid(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		entry, err := s.retrieveEntryInfo(txid, uuid, blockHeight, iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// retrieveEntryInfoByKey returns the details of the given entry from the value of its purge index by txid
+func (s *Store) retrieveEntryInfoByKey(txid, uuid string, blockHeight uint64) (*EntryInfo, error) {
+	purgeIndexValue, err := s.db.Get(createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight))
+	if err != nil {
+		return nil, err
+	}
+	return s.retrieveEntryInfo(txid, uuid, blockHeight, purgeIndexValue)
+}
+
+// retrieveEntryInfo returns the details of the given entry by decoding the given value of its purge
+// index by txid. For the entries persisted before the value was recorded, the value is empty and
+// the size of the entry is computed from its private write set.
+func (s *Store) retrieveEntryInfo(txid, uuid string, blockHeight uint64, purgeIndexValue []byte) (*EntryInfo, error) {
+	if len(purgeIndexValue) > 0 {
+		info := &EntryInfo{
+			TxID:                  txid,
+			UUID:                  uuid,
+			ReceivedAtBlockHeight: blockHeight,
+		}
+		if err := decodeEntryInfo(purgeIndexValue, info); err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+
+	value, err := s.db.Get(createCompositeKeyForPvtRWSet(txid, uuid, blockHeight))
+	if err != nil {
+		return nil, err
+	}
+	pvtSimulationResults := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
+	if len(value) > 0 && value[0] == nilByte {
+		if err := proto.Unmarshal(value[1:], pvtSimulationResults); err != nil {
+			return nil, err
+		}
+	} else {
+		pvtSimulationResults.PvtRwset = &rwset.TxPvtReadWriteSet{}
+		if err := proto.Unmarshal(value, pvtSimulationResults.PvtRwset); err != nil {
+			return nil, err
+		}
+	}
+	return newEntryInfo(txid, uuid, blockHeight, time.Time{}, "", value, pvtSimulationResults), nil
+}
+
+// purgeEntries removes the given entries from the transient store (both the private write sets
+// and the corresponding indexes) and releases their usage. It is expected to be called with the
+// purgeMutex held.
+func (s *Store) purgeEntries(entries []*EntryInfo) error {
+	dbBatch := s.db.NewUpdateBatch()
+	for _, e := range entries {
+		// Remove private write set
+		dbBatch.Delete(createCompositeKeyForPvtRWSet(e.TxID, e.UUID, e.ReceivedAtBlockHeight))
 		// Remove purge index -- purgeIndexByHeight
-		dbBatch.Delete(compositeKeyPurgeIndexByHeight)
+		dbBatch.Delete(createCompositeKeyForPurgeIndexByHeight(e.ReceivedAtBlockHeight, e.TxID, e.UUID))
+		// Remove purge index -- purgeIndexByTxid
+		dbBatch.Delete(createCompositeKeyForPurgeIndexByTxid(e.TxID, e.UUID, e.ReceivedAtBlockHeight))
+		// Remove purge index -- purgeIndexByAge
+		if !e.PersistedAt.IsZero() {
+			dbBatch.Delete(createCompositeKeyForPurgeIndexByAge(uint64(e.PersistedAt.UnixNano()), e.TxID, e.UUID, e.ReceivedAtBlockHeight))
+		}
 	}
-	iter.Release()
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.release(entries...)
+	return nil
+}
+
+// reserve accounts the given entry in the usage of the transient store, provided that it does
+// not exceed any quota
+func (s *Store) reserve(info *EntryInfo) error {
+	s.usageMutex.Lock()
+	defer s.usageMutex.Unlock()
 
-	return s.db.WriteBatch(dbBatch, true)
+	if err := s.usage.checkQuotas(s.config, info); err != nil {
+		if quotaErr, ok := err.(*QuotaExceededError); ok {
+			s.stats.addQuotaRejection(quotaErr.Quota)
+		}
+		return err
+	}
+	s.usage.add(info)
+	s.stats.updateUsage(s.usage)
+	return nil
+}
+
+// release removes the given entries from the usage of the transient store
+func (s *Store) release(entries ...*EntryInfo) {
+	s.usageMutex.Lock()
+	defer s.usageMutex.Unlock()
+
+	for _, e := range entries {
+		s.usage.remove(e)
+	}
+	s.stats.updateUsage(s.usage)
 }
 
 // GetMinTransientBlkHt returns the lowest block height remaining in transient store
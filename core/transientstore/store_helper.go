@@ -20,6 +20,7 @@ var (
 	prwsetPrefix             = []byte("P")[0] // key prefix for storing private write set in transient store.
 	purgeIndexByHeightPrefix = []byte("H")[0] // key prefix for storing index on private write set using received at block height.
 	purgeIndexByTxidPrefix   = []byte("T")[0] // key prefix for storing index on private write set using txid
+	purgeIndexByAgePrefix    = []byte("A")[0] // key prefix for storing index on private write set using the time it was persisted at
 	compositeKeySep          = byte(0x00)
 )
 
@@ -74,6 +75,20 @@ func createCompositeKeyForPurgeIndexByHeight(blockHeight uint64, txid string, uu
 	return compositeKey
 }
 
+// createCompositeKeyForPurgeIndexByAge creates a key to index private write set based on
+// the time it was persisted at such that purge based on age can be achieved. The structure
+// of the key is <purgeIndexByAgePrefix>~persistedAt~txid~uuid~blockHeight.
+func createCompositeKeyForPurgeIndexByAge(persistedAt uint64, txid string, uuid string, blockHeight uint64) []byte {
+	var compositeKey []byte
+	compositeKey = append(compositeKey, purgeIndexByAgePrefix)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(persistedAt)...)
+	compositeKey = append(compositeKey, compositeKeySep)
+	compositeKey = append(compositeKey, createCompositeKeyWithoutPrefixForTxid(txid, uuid, blockHeight)...)
+
+	return compositeKey
+}
+
 // splitCompositeKeyOfPvtRWSet splits the compositeKey (<prwsetPrefix>~txid~uuid~blockHeight)
 // into uuid and blockHeight.
 func splitCompositeKeyOfPvtRWSet(compositeKey []byte) (uuid string, blockHeight uint64, err error) {
@@ -100,6 +115,28 @@ func splitCompositeKeyOfPurgeIndexByHeight(compositeKey []byte) (txid string, uu
 	return
 }
 
+// splitCompositeKeyOfPurgeIndexByTxidWithTxid splits the compositeKey (<purgeIndexByTxidPrefix>~txid~uuid~blockHeight)
+// into txid, uuid and blockHeight.
+func splitCompositeKeyOfPurgeIndexByTxidWithTxid(compositeKey []byte) (txid string, uuid string, blockHeight uint64, err error) {
+	txid = string(compositeKey[2 : bytes.IndexByte(compositeKey[2:], compositeKeySep)+2])
+	uuid, blockHeight, err = splitCompositeKeyWithoutPrefixForTxid(compositeKey[2:])
+	return
+}
+
+// splitCompositeKeyOfPurgeIndexByAge splits the compositeKey (<purgeIndexByAgePrefix>~persistedAt~txid~uuid~blockHeight)
+// into txid, uuid and blockHeight.
+func splitCompositeKeyOfPurgeIndexByAge(compositeKey []byte) (txid string, uuid string, blockHeight uint64, err error) {
+	var n int
+	_, n, err = util.DecodeOrderPreservingVarUint64(compositeKey[2:])
+	if err != nil {
+		return
+	}
+	compositeKeyWithoutPrefix := compositeKey[n+3:]
+	txid = string(compositeKeyWithoutPrefix[:bytes.IndexByte(compositeKeyWithoutPrefix, compositeKeySep)])
+	uuid, blockHeight, err = splitCompositeKeyWithoutPrefixForTxid(compositeKeyWithoutPrefix)
+	return
+}
+
 // splitCompositeKeyWithoutPrefixForTxid splits the composite key txid~uuid~blockHeight into
 // uuid and blockHeight
 func splitCompositeKeyWithoutPrefixForTxid(compositeKey []byte) (uuid string, blockHeight uint64, err error) {
@@ -179,6 +216,35 @@ func createPurgeIndexByTxidRangeEndKey(txid string) []byte {
 	return endKey
 }
 
+// createPurgeIndexByTxidRangeStartKeyForAll returns a startKey to do a range query on the index stored in
+// transient store using txid, covering all the txids
+func createPurgeIndexByTxidRangeStartKeyForAll() []byte {
+	return []byte{purgeIndexByTxidPrefix, compositeKeySep}
+}
+
+// createPurgeIndexByTxidRangeEndKeyForAll returns an endKey to do a range query on the index stored in
+// transient store using txid, covering all the txids
+func createPurgeIndexByTxidRangeEndKeyForAll() []byte {
+	return []byte{purgeIndexByTxidPrefix, compositeKeySep + 1}
+}
+
+// createPurgeIndexByAgeRangeStartKey returns a startKey to do a range query on the index stored in
+// transient store using the time the private write sets were persisted at
+func createPurgeIndexByAgeRangeStartKey() []byte {
+	return []byte{purgeIndexByAgePrefix, compositeKeySep}
+}
+
+// createPurgeIndexByAgeRangeEndKey returns an endKey to do a range query on the index stored in transient
+// store using the time the private write sets were persisted at. The range query covers the private
+// write sets persisted before the given time.
+func createPurgeIndexByAgeRangeEndKey(persistedBefore uint64) []byte {
+	var endKey []byte
+	endKey = append(endKey, purgeIndexByAgePrefix)
+	endKey = append(endKey, compositeKeySep)
+	endKey = append(endKey, util.EncodeOrderPreservingVarUint64(persistedBefore)...)
+	return endKey
+}
+
 // trimPvtWSet returns a `TxPvtReadWriteSet` that retains only list of 'ns/collections' supplied in the filter
 // A nil filter does not filter any results and returns the original `pvtWSet` as is
 func trimPvtWSet(pvtWSet *rwset.TxPvtReadWriteSet, filter ledger.PvtNsCollFilter) *rwset.TxPvtReadWriteSet {
@@ -18,6 +18,7 @@ import (
 
 	proto "github.com/hyperledger/fabric-protos-go/gossip"
 	common_utils "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
@@ -75,6 +76,10 @@ type GossipChannel interface {
 	// to other peers in the channel
 	UpdateChaincodes(chaincode []*proto.Chaincode)
 
+	// UpdateStateCheckpoint updates the state checkpoint the peer
+	// publishes to other peers in the channel
+	UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint)
+
 	// IsOrgInChannel returns whether the given organization is in the channel
 	IsOrgInChannel(membersOrg api.OrgIdentityType) bool
 
@@ -159,9 +164,11 @@ type gossipChannel struct {
 	stateInfoRequestScheduler *time.Ticker
 	memFilter                 *membershipFilter
 	ledgerHeight              uint64
+	stateCheckpoint           *statecheckpoint.StateCheckpoint
 	incTime                   uint64
 	leftChannel               int32
 	membershipTracker         *membershipTracker
+	stateMetrics              *metrics.StateMetrics
 }
 
 type membershipFilter struct {
@@ -187,7 +194,7 @@ func (mf *membershipFilter) GetMembership() []discovery.NetworkMember {
 // NewGossipChannel creates a new GossipChannel
 func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.MessageCryptoService,
 	channelID common.ChannelID, adapter Adapter, joinMsg api.JoinChannelMessage,
-	metrics *metrics.MembershipMetrics, logger util.Logger) GossipChannel {
+	metrics *metrics.GossipMetrics, logger util.Logger) GossipChannel {
 	gc := &gossipChannel{
 		incTime:                   uint64(time.Now().UnixNano()),
 		selfOrg:                   org,
@@ -200,6 +207,7 @@ func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.M
 		stateInfoRequestScheduler: time.NewTicker(adapter.GetConf().RequestStateInfoInterval),
 		orgs:                      []api.OrgIdentityType{},
 		chainID:                   channelID,
+		stateMetrics:              metrics.StateMetrics,
 	}
 
 	if logger == nil {
@@ -296,7 +304,7 @@ func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.M
 		report:          gc.reportMembershipChanges,
 		stopChan:        make(chan struct{}, 1),
 		tickerChannel:   ticker.C,
-		metrics:         metrics,
+		metrics:         metrics.MembershipMetrics,
 		chainID:         channelID,
 	}
 
@@ -819,6 +827,9 @@ func (gc *gossipChannel) verifyBlock(msg *proto.GossipMessage, sender common.PKI
 
 	err = gc.mcs.VerifyBlock(msg.Channel, seqNum, block)
 	if err != nil {
+		if protoutil.IsBlockExtensionMismatch(err) {
+			gc.stateMetrics.ExtensionRejections.With("channel", string(gc.chainID)).Add(1)
+		}
 		gc.logger.Warningf("Received fabricated block from %v in DataUpdate: %+v", sender, err)
 		return false
 	}
@@ -953,6 +964,25 @@ func (gc *gossipChannel) UpdateChaincodes(chaincodes []*proto.Chaincode) {
 	atomic.StoreInt32(&gc.shouldGossipStateInfo, int32(1))
 }
 
+// UpdateStateCheckpoint updates the state checkpoint the peer
+// publishes to other peers in the channel
+func (gc *gossipChannel) UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint) {
+	gc.Lock()
+	defer gc.Unlock()
+
+	gc.stateCheckpoint = checkpoint
+	var ledgerHeight uint64 = 1
+	var chaincodes []*proto.Chaincode
+	var leftChannel bool
+	if prevMsg := gc.selfStateInfoMsg; prevMsg != nil {
+		ledgerHeight = prevMsg.GetStateInfo().Properties.LedgerHeight
+		leftChannel = prevMsg.GetStateInfo().Properties.LeftChannel
+		chaincodes = prevMsg.GetStateInfo().Properties.Chaincodes
+	}
+	gc.updateProperties(ledgerHeight, chaincodes, leftChannel)
+	atomic.StoreInt32(&gc.shouldGossipStateInfo, int32(1))
+}
+
 // UpdateStateInfo updates this channel's StateInfo message
 // that is periodically published
 func (gc *gossipChannel) updateStateInfo(msg *proto.GossipMessage) {
@@ -974,6 +1004,9 @@ func (gc *gossipChannel) updateProperties(ledgerHeight uint64, chaincodes []*pro
 			Chaincodes:   chaincodes,
 		},
 	}
+	if err := protoext.SetStateCheckpoint(stateInfMsg.Properties, gc.stateCheckpoint); err != nil {
+		gc.logger.Warningf("Failed setting the state checkpoint: %+v", err)
+	}
 	m := &proto.GossipMessage{
 		Nonce: 0,
 		Tag:   proto.GossipMessage_CHAN_OR_ORG,
@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/gossip/metrics/mocks"
 	"github.com/hyperledger/fabric/gossip/protoext"
 	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -55,7 +56,7 @@ var conf = Config{
 	MsgExpirationTimeout:        DefMsgExpirationTimeout,
 }
 
-var disabledMetrics = metrics.NewGossipMetrics(&disabled.Provider{}).MembershipMetrics
+var disabledMetrics = metrics.NewGossipMetrics(&disabled.Provider{})
 
 func init() {
 	util.SetupTestLogging()
@@ -1057,7 +1058,9 @@ func TestChannelBadBlocks(t *testing.T) {
 	configureAdapter(adapter, discovery.NetworkMember{PKIid: pkiIDInOrg1})
 	adapter.On("Gossip", mock.Anything)
 	adapter.On("Forward", mock.Anything)
-	gc := NewGossipChannel(pkiIDInOrg1, orgInChannelA, cs, channelA, adapter, &joinChanMsg{}, disabledMetrics, nil)
+	testMetricProvider := mocks.TestUtilConstructMetricProvider()
+	gc := NewGossipChannel(pkiIDInOrg1, orgInChannelA, cs, channelA, adapter, &joinChanMsg{},
+		metrics.NewGossipMetrics(testMetricProvider.FakeProvider), nil)
 
 	adapter.On("DeMultiplex", mock.Anything).Run(func(args mock.Arguments) {
 		receivedMessages <- args.Get(0).(*protoext.SignedGossipMessage)
@@ -1083,6 +1086,15 @@ func TestChannelBadBlocks(t *testing.T) {
 	cs.On("VerifyBlock", mock.Anything).Return(errors.New("Bad signature"))
 	gc.HandleMessage(&receivedMsg{msg: createDataMsg(4, channelA), PKIID: pkiIDInOrg1})
 	assert.Len(t, receivedMessages, 0)
+	assert.Equal(t, 0, testMetricProvider.FakeExtensionRejections.AddCallCount())
+
+	// Send a block with an extension that does not match its extension hash
+	cs.Mock = mock.Mock{}
+	cs.On("VerifyBlock", mock.Anything).Return(&protoutil.BlockExtensionMismatchError{})
+	gc.HandleMessage(&receivedMsg{msg: createDataMsg(5, channelA), PKIID: pkiIDInOrg1})
+	assert.Len(t, receivedMessages, 0)
+	assert.Equal(t, 1, testMetricProvider.FakeExtensionRejections.AddCallCount())
+	assert.Equal(t, []string{"channel", string(channelA)}, testMetricProvider.FakeExtensionRejections.WithArgsForCall(0))
 }
 
 func TestNoGossipOrSigningWhenEmptyMembership(t *testing.T) {
@@ -96,7 +96,7 @@ func (cs *channelState) getGossipChannelByChainID(channelID common.ChannelID) ch
 }
 
 func (cs *channelState) joinChannel(joinMsg api.JoinChannelMessage, channelID common.ChannelID,
-	metrics *metrics.MembershipMetrics) {
+	metrics *metrics.GossipMetrics) {
 	if cs.isStopping() {
 		return
 	}
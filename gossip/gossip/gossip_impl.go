@@ -17,6 +17,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	pg "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	"github.com/hyperledger/fabric/gossip/common"
@@ -190,7 +191,7 @@ func (g *Node) toDie() bool {
 // JoinChan makes gossip participate in the given channel, or update it.
 func (g *Node) JoinChan(joinMsg api.JoinChannelMessage, channelID common.ChannelID) {
 	// joinMsg is supposed to have been already verified
-	g.chanState.joinChannel(joinMsg, channelID, g.gossipMetrics.MembershipMetrics)
+	g.chanState.joinChannel(joinMsg, channelID, g.gossipMetrics)
 
 	g.logger.Info("Joining gossip network of channel", string(channelID), "with", len(joinMsg.Members()), "organizations")
 	for _, org := range joinMsg.Members() {
@@ -793,6 +794,17 @@ func (g *Node) UpdateChaincodes(chaincodes []*pg.Chaincode, channelID common.Cha
 	gc.UpdateChaincodes(chaincodes)
 }
 
+// UpdateStateCheckpoint updates the state checkpoint the peer
+// publishes to other peers in the channel
+func (g *Node) UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint, channelID common.ChannelID) {
+	gc := g.chanState.getGossipChannelByChainID(channelID)
+	if gc == nil {
+		g.logger.Warning("No such channel", channelID)
+		return
+	}
+	gc.UpdateStateCheckpoint(checkpoint)
+}
+
 // Accept returns a dedicated read-only channel for messages sent by other nodes that match a certain predicate.
 // If passThrough is false, the messages are processed by the gossip layer beforehand.
 // If passThrough is true, the gossip layer doesn't intervene and the messages
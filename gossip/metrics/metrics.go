@@ -29,16 +29,20 @@ func NewGossipMetrics(p metrics.Provider) *GossipMetrics {
 
 // StateMetrics encapsulates gossip state related metrics
 type StateMetrics struct {
-	Height            metrics.Gauge
-	CommitDuration    metrics.Histogram
-	PayloadBufferSize metrics.Gauge
+	Height               metrics.Gauge
+	CommitDuration       metrics.Histogram
+	PayloadBufferSize    metrics.Gauge
+	ExtensionRejections  metrics.Counter
+	CheckpointMismatches metrics.Counter
 }
 
 func newStateMetrics(p metrics.Provider) *StateMetrics {
 	return &StateMetrics{
-		Height:            p.NewGauge(HeightOpts),
-		CommitDuration:    p.NewHistogram(CommitDurationOpts),
-		PayloadBufferSize: p.NewGauge(PayloadBufferSizeOpts),
+		Height:               p.NewGauge(HeightOpts),
+		CommitDuration:       p.NewHistogram(CommitDurationOpts),
+		PayloadBufferSize:    p.NewGauge(PayloadBufferSizeOpts),
+		ExtensionRejections:  p.NewCounter(ExtensionRejectionsOpts),
+		CheckpointMismatches: p.NewCounter(CheckpointMismatchesOpts),
 	}
 }
 
@@ -69,6 +73,24 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+
+	ExtensionRejectionsOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "state",
+		Name:         "extension_rejections",
+		Help:         "Number of blocks rejected because their extension does not match the committed extension hash",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	CheckpointMismatchesOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "state",
+		Name:         "checkpoint_mismatches",
+		Help:         "Number of state checkpoints of peers in the same organization that differ from the state checkpoint of the peer at the same block",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 // ElectionMetrics encapsulates gossip leader election related metrics
@@ -34,6 +34,8 @@ func TestMetrics(t *testing.T) {
 	assert.NotNil(t, gossipMetrics.StateMetrics.Height)
 	assert.NotNil(t, gossipMetrics.StateMetrics.CommitDuration)
 	assert.NotNil(t, gossipMetrics.StateMetrics.PayloadBufferSize)
+	assert.NotNil(t, gossipMetrics.StateMetrics.ExtensionRejections)
+	assert.NotNil(t, gossipMetrics.StateMetrics.CheckpointMismatches)
 
 	assert.NotNil(t, gossipMetrics.ElectionMetrics)
 	assert.NotNil(t, gossipMetrics.ElectionMetrics.Declaration)
@@ -18,6 +18,8 @@ type TestMetricProvider struct {
 	FakeHeightGauge            *metricsfakes.Gauge
 	FakeCommitDurationHist     *metricsfakes.Histogram
 	FakePayloadBufferSizeGauge *metricsfakes.Gauge
+	FakeExtensionRejections    *metricsfakes.Counter
+	FakeCheckpointMismatches   *metricsfakes.Counter
 
 	FakeDeclarationGauge *metricsfakes.Gauge
 
@@ -44,6 +46,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 	fakeHeightGauge := testUtilConstructGauge()
 	fakeCommitDurationHist := testUtilConstructHist()
 	fakePayloadBufferSizeGauge := testUtilConstructGauge()
+	fakeExtensionRejections := testUtilConstructCounter()
+	fakeCheckpointMismatches := testUtilConstructCounter()
 
 	fakeDeclarationGauge := testUtilConstructGauge()
 
@@ -71,6 +75,10 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 			return fakeSentMessages
 		case gmetrics.ReceivedMessagesOpts.Name:
 			return fakeReceivedMessages
+		case gmetrics.ExtensionRejectionsOpts.Name:
+			return fakeExtensionRejections
+		case gmetrics.CheckpointMismatchesOpts.Name:
+			return fakeCheckpointMismatches
 		}
 		return nil
 	}
@@ -118,6 +126,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 		fakeHeightGauge,
 		fakeCommitDurationHist,
 		fakePayloadBufferSizeGauge,
+		fakeExtensionRejections,
+		fakeCheckpointMismatches,
 		fakeDeclarationGauge,
 		fakeSentMessages,
 		fakeBufferOverflow,
@@ -56,6 +56,10 @@ type Coordinator interface {
 	// StorePvtData used to persist private data into transient store
 	StorePvtData(txid string, privData *protostransientstore.TxPvtReadWriteSetWithConfigInfo, blckHeight uint64) error
 
+	// StorePvtDataForClient used to persist into transient store the private data of a transaction
+	// endorsed by this peer on behalf of the client identified by the given creator
+	StorePvtDataForClient(txid string, privData *protostransientstore.TxPvtReadWriteSetWithConfigInfo, blckHeight uint64, creator []byte) error
+
 	// GetPvtDataAndBlockByNum gets block by number and also returns all related private data
 	// that requesting peer is eligible for.
 	// The order of private data in slice of PvtDataCollections doesn't imply the order of
@@ -66,6 +70,13 @@ type Coordinator interface {
 	// Get recent block sequence number
 	LedgerHeight() (uint64, error)
 
+	// GetStateCheckpoint returns the state checkpoint recorded at the given block,
+	// or nil if no state checkpoint is recorded at the block
+	GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error)
+
+	// GetLatestStateCheckpoint returns the latest recorded state checkpoint, or nil if none
+	GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error)
+
 	// Close coordinator, shuts down coordinator service
 	Close()
 }
@@ -240,6 +251,11 @@ func (c *coordinator) StorePvtData(txID string, privData *protostransientstore.T
 	return c.store.Persist(txID, blkHeight, privData)
 }
 
+// StorePvtDataForClient used to persist private data of a transaction endorsed on behalf of a client into transient store
+func (c *coordinator) StorePvtDataForClient(txID string, privData *protostransientstore.TxPvtReadWriteSetWithConfigInfo, blkHeight uint64, creator []byte) error {
+	return c.store.PersistForClient(txID, blkHeight, privData, creator)
+}
+
 // GetPvtDataAndBlockByNum gets block by number and also returns all related private data
 // that requesting peer is eligible for.
 // The order of private data in slice of PvtDataCollections doesn't imply the order of
@@ -113,6 +113,29 @@ func (_m *Committer) GetConfigHistoryRetriever() (ledger.ConfigHistoryRetriever,
 	return r0, r1
 }
 
+// GetLatestStateCheckpoint provides a mock function with given fields:
+func (_m *Committer) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	ret := _m.Called()
+
+	var r0 *ledger.StateCheckpoint
+	if rf, ok := ret.Get(0).(func() *ledger.StateCheckpoint); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ledger.StateCheckpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMissingPvtDataTracker provides a mock function with given fields:
 func (_m *Committer) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
 	ret := _m.Called()
@@ -182,6 +205,29 @@ func (_m *Committer) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFil
 	return r0, r1
 }
 
+// GetStateCheckpoint provides a mock function with given fields: blockNumber
+func (_m *Committer) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	ret := _m.Called(blockNumber)
+
+	var r0 *ledger.StateCheckpoint
+	if rf, ok := ret.Get(0).(func(uint64) *ledger.StateCheckpoint); ok {
+		r0 = rf(blockNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ledger.StateCheckpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LedgerHeight provides a mock function with given fields:
 func (_m *Committer) LedgerHeight() (uint64, error) {
 	ret := _m.Called()
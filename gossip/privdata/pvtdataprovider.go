@@ -55,7 +55,8 @@ func (r *RetrievedPvtdata) GetBlockPvtdata() *ledger.BlockPvtdata {
 }
 
 // Purge purges private data for transactions in the block from the transient store.
-// Transactions older than the retention period are considered orphaned and also purged.
+// Transactions older than the retention period are considered orphaned and also purged,
+// as well as the private data persisted longer than the max age of the transient store ago.
 func (r *RetrievedPvtdata) Purge() {
 	purgeStart := time.Now()
 
@@ -74,6 +75,10 @@ func (r *RetrievedPvtdata) Purge() {
 		}
 	}
 
+	if err := r.transientStore.PurgeExpired(); err != nil {
+		r.logger.Errorf("Failed purging expired data from transient store at block [%d]: %s", blockNum, err)
+	}
+
 	r.purgeDurationHistogram.Observe(time.Since(purgeStart).Seconds())
 }
 
@@ -53,6 +53,10 @@ type PvtDataReconciler interface {
 	Start()
 	// Stop function stops reconciler
 	Stop()
+	// Reconcile requests an on-demand reconciliation pass over the missing private data that matches the filter
+	Reconcile(filter *ReconciliationFilter) error
+	// Status returns the state of the reconciler and the progress of its current or last on-demand pass
+	Status() *ReconciliationStatus
 }
 
 type Reconciler struct {
@@ -62,10 +66,17 @@ type Reconciler struct {
 	ReconcileSleepInterval time.Duration
 	ReconcileBatchSize     int
 	stopChan               chan struct{}
+	triggerChan            chan struct{}
 	startOnce              sync.Once
 	stopOnce               sync.Once
 	ReconciliationFetcher
 	committer.Committer
+
+	// statusMutex guards the on-demand pass that is requested, and the one that is running or completed last
+	statusMutex   sync.Mutex
+	pendingFilter *ReconciliationFilter
+	running       bool
+	lastPass      *ReconciliationPass
 }
 
 // NoOpReconciler non functional reconciler to be used
@@ -82,6 +93,14 @@ func (*NoOpReconciler) Stop() {
 	// do nothing
 }
 
+func (*NoOpReconciler) Reconcile(filter *ReconciliationFilter) error {
+	return errors.New("private data reconciliation is disabled")
+}
+
+func (*NoOpReconciler) Status() *ReconciliationStatus {
+	return &ReconciliationStatus{State: ReconciliationIdle}
+}
+
 // NewReconciler creates a new instance of reconciler
 func NewReconciler(channel string, metrics *metrics.PrivdataMetrics, c committer.Committer,
 	fetcher ReconciliationFetcher, config *PrivdataConfig) *Reconciler {
@@ -96,6 +115,7 @@ func NewReconciler(channel string, metrics *metrics.PrivdataMetrics, c committer
 		Committer:              c,
 		ReconciliationFetcher:  fetcher,
 		stopChan:               make(chan struct{}),
+		triggerChan:            make(chan struct{}, 1),
 	}
 }
 
@@ -116,6 +136,8 @@ func (r *Reconciler) run() {
 		select {
 		case <-r.stopChan:
 			return
+		case <-r.triggerChan:
+			r.reconcileOnDemand()
 		case <-time.After(r.ReconcileSleepInterval):
 			r.logger.Debug("Start reconcile missing private info")
 			if err := r.reconcile(); err != nil {
@@ -159,28 +181,49 @@ func (r *Reconciler) reconcile() error {
 
 		r.logger.Debug("got from ledger", len(missingPvtDataInfo), "blocks with missing private data, trying to reconcile...")
 
-		dig2collectionCfg, minB, maxB := r.getDig2CollectionConfig(missingPvtDataInfo)
-		fetchedData, err := r.FetchReconciledItems(dig2collectionCfg)
+		result, err := r.reconcileBatch(missingPvtDataInfo)
 		if err != nil {
-			r.logger.Error("reconciliation error when trying to fetch missing items from different peers:", err)
 			return err
 		}
-
-		pvtDataToCommit := r.preparePvtDataToCommit(fetchedData.AvailableElements)
-		unreconciled := constructUnreconciledMissingData(dig2collectionCfg, fetchedData.AvailableElements)
-		pvtdataHashMismatch, err := r.CommitPvtDataOfOldBlocks(pvtDataToCommit, unreconciled)
-		if err != nil {
-			return errors.Wrap(err, "failed to commit private data")
+		if result.minBlock < minBlock {
+			minBlock = result.minBlock
 		}
-		r.logMismatched(pvtdataHashMismatch)
-		if minB < minBlock {
-			minBlock = minB
+		if result.maxBlock > maxBlock {
+			maxBlock = result.maxBlock
 		}
-		if maxB > maxBlock {
-			maxBlock = maxB
-		}
-		totalReconciled += len(fetchedData.AvailableElements)
+		totalReconciled += len(result.fetched)
+	}
+}
+
+// batchResult holds the outcome of the reconciliation of a batch of missing private data
+type batchResult struct {
+	fetched            []*protosgossip.PvtDataElement
+	hashMismatches     []*ledger.PvtdataHashMismatch
+	minBlock, maxBlock uint64
+}
+
+// reconcileBatch fetches the given missing private data from the other peers and commits it
+func (r *Reconciler) reconcileBatch(missingPvtDataInfo ledger.MissingPvtDataInfo) (*batchResult, error) {
+	dig2collectionCfg, minB, maxB := r.getDig2CollectionConfig(missingPvtDataInfo)
+	fetchedData, err := r.FetchReconciledItems(dig2collectionCfg)
+	if err != nil {
+		r.logger.Error("reconciliation error when trying to fetch missing items from different peers:", err)
+		return nil, err
+	}
+
+	pvtDataToCommit := r.preparePvtDataToCommit(fetchedData.AvailableElements)
+	unreconciled := constructUnreconciledMissingData(dig2collectionCfg, fetchedData.AvailableElements)
+	pvtdataHashMismatch, err := r.CommitPvtDataOfOldBlocks(pvtDataToCommit, unreconciled)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit private data")
 	}
+	r.logMismatched(pvtdataHashMismatch)
+	return &batchResult{
+		fetched:        fetchedData.AvailableElements,
+		hashMismatches: pvtdataHashMismatch,
+		minBlock:       minB,
+		maxBlock:       maxB,
+	}, nil
 }
 
 func (r *Reconciler) reportReconciliationDuration(startTime time.Time) {
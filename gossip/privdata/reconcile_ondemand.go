@@ -0,0 +1,368 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// The states of the on-demand reconciliation of a channel
+const (
+	// ReconciliationIdle means that no on-demand reconciliation pass is requested or running
+	ReconciliationIdle = "idle"
+	// ReconciliationPending means that an on-demand reconciliation pass is requested and
+	// waits for the running periodic reconciliation to complete
+	ReconciliationPending = "pending"
+	// ReconciliationRunning means that an on-demand reconciliation pass is running
+	ReconciliationRunning = "running"
+)
+
+// ReconciliationFilter selects the missing private data reconciled by an on-demand reconciliation
+// pass. The empty fields match any namespace, collection, or block.
+type ReconciliationFilter struct {
+	Namespace  string
+	Collection string
+	StartBlock uint64
+	// EndBlock is the last block whose missing private data is reconciled, zero means no bound
+	EndBlock uint64
+}
+
+func (f *ReconciliationFilter) matches(blockNum uint64, info *ledger.MissingCollectionPvtDataInfo) bool {
+	if f == nil {
+		return true
+	}
+	if f.Namespace != "" && f.Namespace != info.Namespace {
+		return false
+	}
+	if f.Collection != "" && f.Collection != info.Collection {
+		return false
+	}
+	if blockNum < f.StartBlock {
+		return false
+	}
+	return f.EndBlock == 0 || blockNum <= f.EndBlock
+}
+
+// ReconciliationStatus is the state of the reconciler of a channel and the progress of
+// its current or last on-demand reconciliation pass
+type ReconciliationStatus struct {
+	Enabled bool
+	State   string
+	// LastPass is the running on-demand pass, or the last completed one. It is nil if
+	// no on-demand pass ran since the peer started.
+	LastPass *ReconciliationPass
+}
+
+// ReconciliationPass describes an on-demand reconciliation pass
+type ReconciliationPass struct {
+	Filter     *ReconciliationFilter
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Err is the error that aborted the pass, if any
+	Err string
+	// Collections lists the progress of each collection with missing private data that
+	// matches the filter, sorted by namespace and collection
+	Collections []*CollectionReconciliationProgress
+
+	collectionIndex map[collectionKey]*CollectionReconciliationProgress
+}
+
+// CollectionReconciliationProgress is the progress of the reconciliation of the missing private
+// data of a collection. The entries counted are the missing private write sets of a collection in
+// a transaction. Missing minus the other counters is the number of entries that remain to be tried.
+type CollectionReconciliationProgress struct {
+	Namespace  string
+	Collection string
+	// MinBlock and MaxBlock are the range of blocks with missing private data of the collection
+	MinBlock uint64
+	MaxBlock uint64
+	// Missing is the number of entries found missing when the pass started
+	Missing uint64
+	// Reconciled is the number of entries fetched from other peers and committed
+	Reconciled uint64
+	// Unreconciled is the number of entries that could not be fetched from other peers,
+	// or whose collection config is not available
+	Unreconciled uint64
+	// HashMismatches is the number of entries fetched from other peers whose hash did not
+	// match the hash committed in the block
+	HashMismatches uint64
+}
+
+type collectionKey struct {
+	namespace, collection string
+}
+
+func newReconciliationPass(filter *ReconciliationFilter) *ReconciliationPass {
+	return &ReconciliationPass{
+		Filter:          filter,
+		StartedAt:       time.Now(),
+		collectionIndex: map[collectionKey]*CollectionReconciliationProgress{},
+	}
+}
+
+func (p *ReconciliationPass) collection(namespace, collection string) *CollectionReconciliationProgress {
+	key := collectionKey{namespace, collection}
+	progress, ok := p.collectionIndex[key]
+	if !ok {
+		progress = &CollectionReconciliationProgress{
+			Namespace:  namespace,
+			Collection: collection,
+			MinBlock:   math.MaxUint64,
+		}
+		p.collectionIndex[key] = progress
+		p.Collections = append(p.Collections, progress)
+		sort.Slice(p.Collections, func(i, j int) bool {
+			if p.Collections[i].Namespace != p.Collections[j].Namespace {
+				return p.Collections[i].Namespace < p.Collections[j].Namespace
+			}
+			return p.Collections[i].Collection < p.Collections[j].Collection
+		})
+	}
+	return progress
+}
+
+func (p *ReconciliationPass) addMissing(missingPvtDataInfo ledger.MissingPvtDataInfo) {
+	for blockNum, blockPvtDataInfo := range missingPvtDataInfo {
+		for _, collectionPvtDataInfo := range blockPvtDataInfo {
+			for _, info := range collectionPvtDataInfo {
+				progress := p.collection(info.Namespace, info.Collection)
+				progress.Missing++
+				if blockNum < progress.MinBlock {
+					progress.MinBlock = blockNum
+				}
+				if blockNum > progress.MaxBlock {
+					progress.MaxBlock = blockNum
+				}
+			}
+		}
+	}
+}
+
+// addBatchResult accounts the outcome of the reconciliation of a batch of missing private data.
+// The entries of the batch that were not fetched are accounted as unreconciled.
+func (p *ReconciliationPass) addBatchResult(batch ledger.MissingPvtDataInfo, result *batchResult) {
+	notFetched := map[collectionKey]uint64{}
+	for _, blockPvtDataInfo := range batch {
+		for _, collectionPvtDataInfo := range blockPvtDataInfo {
+			for _, info := range collectionPvtDataInfo {
+				notFetched[collectionKey{info.Namespace, info.Collection}]++
+			}
+		}
+	}
+	for _, element := range result.fetched {
+		key := collectionKey{element.Digest.Namespace, element.Digest.Collection}
+		notFetched[key] = subtract(notFetched[key], 1)
+		p.collection(key.namespace, key.collection).Reconciled++
+	}
+	for _, mismatch := range result.hashMismatches {
+		progress := p.collection(mismatch.Namespace, mismatch.Collection)
+		progress.Reconciled = subtract(progress.Reconciled, 1)
+		progress.HashMismatches++
+	}
+	for key, count := range notFetched {
+		p.collection(key.namespace, key.collection).Unreconciled += count
+	}
+}
+
+func (p *ReconciliationPass) clone() *ReconciliationPass {
+	c := &ReconciliationPass{
+		StartedAt:  p.StartedAt,
+		FinishedAt: p.FinishedAt,
+		Err:        p.Err,
+	}
+	if p.Filter != nil {
+		filter := *p.Filter
+		c.Filter = &filter
+	}
+	for _, progress := range p.Collections {
+		collection := *progress
+		c.Collections = append(c.Collections, &collection)
+	}
+	return c
+}
+
+// Reconcile requests an on-demand reconciliation pass over the missing private data that matches
+// the given filter. The pass runs in the background once the running periodic reconciliation, if
+// any, completes, and its progress is reported by Status. Unlike the periodic reconciliation, the
+// pass reads the whole inventory of missing private data from the ledger at once, and it tries each
+// entry of the inventory that matches the filter once.
+func (r *Reconciler) Reconcile(filter *ReconciliationFilter) error {
+	if filter != nil && filter.EndBlock != 0 && filter.EndBlock < filter.StartBlock {
+		return errors.Errorf("the end block [%d] is lower than the start block [%d]", filter.EndBlock, filter.StartBlock)
+	}
+	select {
+	case <-r.stopChan:
+		return errors.New("the reconciler is stopped")
+	default:
+	}
+
+	r.statusMutex.Lock()
+	defer r.statusMutex.Unlock()
+	if r.pendingFilter != nil || r.running {
+		return errors.New("an on-demand reconciliation pass is already in progress")
+	}
+	if filter == nil {
+		filter = &ReconciliationFilter{}
+	} else {
+		f := *filter
+		filter = &f
+	}
+	r.pendingFilter = filter
+	select {
+	case r.triggerChan <- struct{}{}:
+	default:
+	}
+	r.logger.Infof("On-demand reconciliation requested for namespace [%s], collection [%s], blocks [%d - %d]",
+		filter.Namespace, filter.Collection, filter.StartBlock, filter.EndBlock)
+	return nil
+}
+
+// Status returns the state of the reconciler and the progress of its current or last on-demand pass
+func (r *Reconciler) Status() *ReconciliationStatus {
+	r.statusMutex.Lock()
+	defer r.statusMutex.Unlock()
+
+	status := &ReconciliationStatus{
+		Enabled: true,
+		State:   ReconciliationIdle,
+	}
+	switch {
+	case r.running:
+		status.State = ReconciliationRunning
+	case r.pendingFilter != nil:
+		status.State = ReconciliationPending
+	}
+	if r.lastPass != nil {
+		status.LastPass = r.lastPass.clone()
+	}
+	return status
+}
+
+func (r *Reconciler) reconcileOnDemand() {
+	r.statusMutex.Lock()
+	pass := newReconciliationPass(r.pendingFilter)
+	r.pendingFilter = nil
+	r.running = true
+	r.lastPass = pass
+	r.statusMutex.Unlock()
+
+	err := r.reconcileFiltered(pass)
+
+	r.statusMutex.Lock()
+	pass.FinishedAt = time.Now()
+	if err != nil {
+		pass.Err = err.Error()
+	}
+	r.running = false
+	r.statusMutex.Unlock()
+
+	if err != nil {
+		r.logger.Errorf("On-demand reconciliation failed: %s", err)
+		return
+	}
+	r.logger.Infof("On-demand reconciliation finished in %s", pass.FinishedAt.Sub(pass.StartedAt))
+}
+
+// reconcileFiltered reconciles the missing private data that matches the filter of the given
+// pass, in batches of ReconcileBatchSize blocks starting from the most recent block
+func (r *Reconciler) reconcileFiltered(pass *ReconciliationPass) error {
+	missingPvtDataTracker, err := r.GetMissingPvtDataTracker()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get the missing private data tracker")
+	}
+	if missingPvtDataTracker == nil {
+		return errors.New("got nil as MissingPvtDataTracker")
+	}
+
+	defer r.reportReconciliationDuration(time.Now())
+
+	inventory, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(math.MaxInt32)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get the missing private data from the ledger")
+	}
+	selected := filterMissingPvtDataInfo(inventory, pass.Filter)
+
+	r.statusMutex.Lock()
+	pass.addMissing(selected)
+	r.statusMutex.Unlock()
+
+	for _, batch := range splitMissingPvtDataInfo(selected, r.ReconcileBatchSize) {
+		select {
+		case <-r.stopChan:
+			return errors.New("the reconciler is stopped")
+		default:
+		}
+
+		result, err := r.reconcileBatch(batch)
+		if err != nil {
+			return err
+		}
+
+		r.statusMutex.Lock()
+		pass.addBatchResult(batch, result)
+		r.statusMutex.Unlock()
+	}
+	return nil
+}
+
+// filterMissingPvtDataInfo returns the missing private data that matches the given filter
+func filterMissingPvtDataInfo(missingPvtDataInfo ledger.MissingPvtDataInfo, filter *ReconciliationFilter) ledger.MissingPvtDataInfo {
+	selected := make(ledger.MissingPvtDataInfo)
+	for blockNum, blockPvtDataInfo := range missingPvtDataInfo {
+		for seqInBlock, collectionPvtDataInfo := range blockPvtDataInfo {
+			for _, info := range collectionPvtDataInfo {
+				if filter.matches(blockNum, info) {
+					selected.Add(blockNum, seqInBlock, info.Namespace, info.Collection)
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// splitMissingPvtDataInfo splits the missing private data into batches of at most batchSize blocks,
+// the batch of the most recent blocks first
+func splitMissingPvtDataInfo(missingPvtDataInfo ledger.MissingPvtDataInfo, batchSize int) []ledger.MissingPvtDataInfo {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	var blockNums []uint64
+	for blockNum := range missingPvtDataInfo {
+		blockNums = append(blockNums, blockNum)
+	}
+	sort.Slice(blockNums, func(i, j int) bool { return blockNums[i] > blockNums[j] })
+
+	var batches []ledger.MissingPvtDataInfo
+	for i := 0; i < len(blockNums); i += batchSize {
+		batch := make(ledger.MissingPvtDataInfo)
+		for _, blockNum := range blockNums[i:minInt(i+batchSize, len(blockNums))] {
+			batch[blockNum] = missingPvtDataInfo[blockNum]
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// subtract returns a-b, or zero if b is greater than a
+func subtract(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,233 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	gossip2 "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/gossip/metrics"
+	privdatacommon "github.com/hyperledger/fabric/gossip/privdata/common"
+	"github.com/hyperledger/fabric/gossip/privdata/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterAndSplitMissingPvtDataInfo(t *testing.T) {
+	missingInfo := ledger.MissingPvtDataInfo{}
+	missingInfo.Add(3, 1, "ns1", "col1")
+	missingInfo.Add(4, 0, "ns2", "col1")
+	missingInfo.Add(5, 0, "ns1", "col1")
+	missingInfo.Add(5, 2, "ns1", "col2")
+	missingInfo.Add(7, 1, "ns1", "col1")
+
+	selected := filterMissingPvtDataInfo(missingInfo, &ReconciliationFilter{Namespace: "ns1", Collection: "col1", StartBlock: 4, EndBlock: 7})
+	expected := ledger.MissingPvtDataInfo{}
+	expected.Add(5, 0, "ns1", "col1")
+	expected.Add(7, 1, "ns1", "col1")
+	require.Equal(t, expected, selected)
+
+	require.Equal(t, missingInfo, filterMissingPvtDataInfo(missingInfo, &ReconciliationFilter{}))
+	require.Equal(t, missingInfo, filterMissingPvtDataInfo(missingInfo, nil))
+	require.Empty(t, filterMissingPvtDataInfo(missingInfo, &ReconciliationFilter{StartBlock: 8}))
+
+	batches := splitMissingPvtDataInfo(missingInfo, 3)
+	require.Len(t, batches, 2)
+	require.Equal(t, ledger.MissingPvtDataInfo{7: missingInfo[7], 5: missingInfo[5], 4: missingInfo[4]}, batches[0])
+	require.Equal(t, ledger.MissingPvtDataInfo{3: missingInfo[3]}, batches[1])
+
+	require.Len(t, splitMissingPvtDataInfo(missingInfo, 0), 4)
+	require.Empty(t, splitMissingPvtDataInfo(ledger.MissingPvtDataInfo{}, 3))
+}
+
+func TestOnDemandReconciliation(t *testing.T) {
+	// Scenario: an on-demand reconciliation of the namespace ns1 fetches the missing private data
+	// of col1, one of which does not match its hash, and skips col2 whose config is not available.
+	committer := &mocks.Committer{}
+	fetcher := &mocks.ReconciliationFetcher{}
+	configHistoryRetriever := &mocks.ConfigHistoryRetriever{}
+	missingPvtDataTracker := &mocks.MissingPvtDataTracker{}
+
+	missingInfo := ledger.MissingPvtDataInfo{}
+	missingInfo.Add(3, 1, "ns1", "col1")
+	missingInfo.Add(4, 0, "ns2", "col1")
+	missingInfo.Add(5, 0, "ns1", "col1")
+	missingInfo.Add(5, 2, "ns1", "col2")
+
+	collectionConfigInfo := &ledger.CollectionConfigInfo{
+		CollectionConfig: &peer.CollectionConfigPackage{
+			Config: []*peer.CollectionConfig{
+				{Payload: &peer.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &peer.StaticCollectionConfig{
+						Name: "col1",
+					},
+				}},
+			},
+		},
+		CommittingBlockNum: 1,
+	}
+
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", mock.Anything).Return(missingInfo, nil)
+	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(collectionConfigInfo, nil)
+	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
+	committer.On("GetConfigHistoryRetriever").Return(configHistoryRetriever, nil)
+
+	var fetchedDigests []privdatacommon.DigKey
+	fetcher.On("FetchReconciledItems", mock.Anything).Return(func(dig2CollectionConfig privdatacommon.Dig2CollectionConfig) *privdatacommon.FetchedPvtDataContainer {
+		result := &privdatacommon.FetchedPvtDataContainer{}
+		for digest := range dig2CollectionConfig {
+			fetchedDigests = append(fetchedDigests, digest)
+			result.AvailableElements = append(result.AvailableElements, &gossip2.PvtDataElement{
+				Digest: &gossip2.PvtDataDigest{
+					TxId:       digest.TxId,
+					BlockSeq:   digest.BlockSeq,
+					Collection: digest.Collection,
+					Namespace:  digest.Namespace,
+					SeqInBlock: digest.SeqInBlock,
+				},
+				Payload: [][]byte{[]byte("rws-pre-image")},
+			})
+		}
+		return result
+	}, nil)
+	committer.On("CommitPvtDataOfOldBlocks", mock.Anything, mock.Anything).Return(func(reconciledPvtdata []*ledger.ReconciledPvtdata, _ ledger.MissingPvtDataInfo) []*ledger.PvtdataHashMismatch {
+		if reconciledPvtdata[0].BlockNum != 5 {
+			return nil
+		}
+		return []*ledger.PvtdataHashMismatch{{BlockNum: 5, TxNum: 0, Namespace: "ns1", Collection: "col1"}}
+	}, nil)
+
+	r := NewReconciler(
+		"",
+		metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics,
+		committer,
+		fetcher,
+		&PrivdataConfig{
+			ReconcileSleepInterval: time.Hour,
+			ReconcileBatchSize:     1,
+			ReconciliationEnabled:  true,
+		})
+
+	status := r.Status()
+	require.True(t, status.Enabled)
+	require.Equal(t, ReconciliationIdle, status.State)
+	require.Nil(t, status.LastPass)
+
+	require.NoError(t, r.Reconcile(&ReconciliationFilter{Namespace: "ns1"}))
+	require.Equal(t, ReconciliationPending, r.Status().State)
+	require.EqualError(t, r.Reconcile(nil), "an on-demand reconciliation pass is already in progress")
+
+	r.reconcileOnDemand()
+
+	require.ElementsMatch(t, []privdatacommon.DigKey{
+		{Namespace: "ns1", Collection: "col1", BlockSeq: 5, SeqInBlock: 0},
+		{Namespace: "ns1", Collection: "col1", BlockSeq: 3, SeqInBlock: 1},
+	}, fetchedDigests)
+
+	status = r.Status()
+	require.Equal(t, ReconciliationIdle, status.State)
+	require.NotNil(t, status.LastPass)
+	require.Equal(t, &ReconciliationFilter{Namespace: "ns1"}, status.LastPass.Filter)
+	require.False(t, status.LastPass.FinishedAt.Before(status.LastPass.StartedAt))
+	require.Empty(t, status.LastPass.Err)
+	require.Equal(t, []*CollectionReconciliationProgress{
+		{Namespace: "ns1", Collection: "col1", MinBlock: 3, MaxBlock: 5, Missing: 2, Reconciled: 1, HashMismatches: 1},
+		{Namespace: "ns1", Collection: "col2", MinBlock: 5, MaxBlock: 5, Missing: 1, Unreconciled: 1},
+	}, status.LastPass.Collections)
+
+	// The status is a copy that is not modified by a later pass
+	status.LastPass.Collections[0].Missing = 10
+	require.Equal(t, uint64(2), r.Status().LastPass.Collections[0].Missing)
+
+	require.NoError(t, r.Reconcile(&ReconciliationFilter{Namespace: "ns2"}))
+	r.reconcileOnDemand()
+	require.Equal(t, []*CollectionReconciliationProgress{
+		{Namespace: "ns2", Collection: "col1", MinBlock: 4, MaxBlock: 4, Missing: 1, Reconciled: 1},
+	}, r.Status().LastPass.Collections)
+}
+
+func TestOnDemandReconciliationFailures(t *testing.T) {
+	newReconciler := func(committer *mocks.Committer, fetcher *mocks.ReconciliationFetcher) *Reconciler {
+		return NewReconciler("", metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics, committer, fetcher,
+			&PrivdataConfig{ReconcileSleepInterval: time.Hour, ReconcileBatchSize: 1, ReconciliationEnabled: true})
+	}
+
+	t.Run("end block lower than start block", func(t *testing.T) {
+		r := newReconciler(&mocks.Committer{}, &mocks.ReconciliationFetcher{})
+		err := r.Reconcile(&ReconciliationFilter{StartBlock: 10, EndBlock: 5})
+		require.EqualError(t, err, "the end block [5] is lower than the start block [10]")
+		require.Equal(t, ReconciliationIdle, r.Status().State)
+	})
+
+	t.Run("stopped reconciler", func(t *testing.T) {
+		r := newReconciler(&mocks.Committer{}, &mocks.ReconciliationFetcher{})
+		r.Stop()
+		require.EqualError(t, r.Reconcile(nil), "the reconciler is stopped")
+	})
+
+	t.Run("missing pvt data tracker error", func(t *testing.T) {
+		committer := &mocks.Committer{}
+		committer.On("GetMissingPvtDataTracker").Return(nil, errors.New("failed to obtain missing pvt data tracker"))
+		r := newReconciler(committer, &mocks.ReconciliationFetcher{})
+		require.NoError(t, r.Reconcile(nil))
+		r.reconcileOnDemand()
+		status := r.Status()
+		require.Equal(t, ReconciliationIdle, status.State)
+		require.Equal(t, "failed to get the missing private data tracker: failed to obtain missing pvt data tracker", status.LastPass.Err)
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		committer := &mocks.Committer{}
+		fetcher := &mocks.ReconciliationFetcher{}
+		missingPvtDataTracker := &mocks.MissingPvtDataTracker{}
+		missingInfo := ledger.MissingPvtDataInfo{}
+		missingInfo.Add(3, 1, "ns1", "col1")
+		missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", mock.Anything).Return(missingInfo, nil)
+		committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
+		committer.On("GetConfigHistoryRetriever").Return(nil, errors.New("config history is not available"))
+		fetcher.On("FetchReconciledItems", mock.Anything).Return(nil, errors.New("failed to fetch"))
+
+		r := newReconciler(committer, fetcher)
+		require.NoError(t, r.Reconcile(nil))
+		r.reconcileOnDemand()
+		lastPass := r.Status().LastPass
+		require.Equal(t, "failed to fetch", lastPass.Err)
+		require.Equal(t, []*CollectionReconciliationProgress{
+			{Namespace: "ns1", Collection: "col1", MinBlock: 3, MaxBlock: 3, Missing: 1},
+		}, lastPass.Collections)
+	})
+}
+
+func TestOnDemandReconciliationTrigger(t *testing.T) {
+	committer := &mocks.Committer{}
+	missingPvtDataTracker := &mocks.MissingPvtDataTracker{}
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", mock.Anything).Return(nil, nil)
+	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
+
+	r := NewReconciler("", metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics, committer, &mocks.ReconciliationFetcher{},
+		&PrivdataConfig{ReconcileSleepInterval: time.Hour, ReconcileBatchSize: 1, ReconciliationEnabled: true})
+	r.Start()
+	defer r.Stop()
+
+	require.NoError(t, r.Reconcile(&ReconciliationFilter{Collection: "col1"}))
+	require.Eventually(t, func() bool {
+		status := r.Status()
+		return status.State == ReconciliationIdle && status.LastPass != nil && !status.LastPass.FinishedAt.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, r.Status().LastPass.Collections)
+}
+
+func TestNoOpReconciler(t *testing.T) {
+	r := &NoOpReconciler{}
+	require.EqualError(t, r.Reconcile(&ReconciliationFilter{}), "private data reconciliation is disabled")
+	require.Equal(t, &ReconciliationStatus{State: ReconciliationIdle}, r.Status())
+}
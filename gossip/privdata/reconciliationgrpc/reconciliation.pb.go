@@ -0,0 +1,666 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: reconciliation.proto
+
+package reconciliationgrpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ReconciliationRequest contains information for a request of an on-demand reconciliation pass
+type ReconciliationRequest struct {
+	// The signature header that contains creator identity and nonce
+	SignatureHeader *common.SignatureHeader `protobuf:"bytes,1,opt,name=signature_header,json=signatureHeader,proto3" json:"signature_header,omitempty"`
+	// The channel ID
+	ChannelId string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// The filter that selects the missing private data to reconcile
+	Filter               *ReconciliationFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ReconciliationRequest) Reset()         { *m = ReconciliationRequest{} }
+func (m *ReconciliationRequest) String() string { return proto.CompactTextString(m) }
+func (*ReconciliationRequest) ProtoMessage()    {}
+func (*ReconciliationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{0}
+}
+
+func (m *ReconciliationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconciliationRequest.Unmarshal(m, b)
+}
+func (m *ReconciliationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconciliationRequest.Marshal(b, m, deterministic)
+}
+func (m *ReconciliationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconciliationRequest.Merge(m, src)
+}
+func (m *ReconciliationRequest) XXX_Size() int {
+	return xxx_messageInfo_ReconciliationRequest.Size(m)
+}
+func (m *ReconciliationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconciliationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconciliationRequest proto.InternalMessageInfo
+
+func (m *ReconciliationRequest) GetSignatureHeader() *common.SignatureHeader {
+	if m != nil {
+		return m.SignatureHeader
+	}
+	return nil
+}
+
+func (m *ReconciliationRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ReconciliationRequest) GetFilter() *ReconciliationFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+// ReconciliationQuery contains information for a query of the reconciliation status
+type ReconciliationQuery struct {
+	// The signature header that contains creator identity and nonce
+	SignatureHeader *common.SignatureHeader `protobuf:"bytes,1,opt,name=signature_header,json=signatureHeader,proto3" json:"signature_header,omitempty"`
+	// The channel ID
+	ChannelId            string   `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReconciliationQuery) Reset()         { *m = ReconciliationQuery{} }
+func (m *ReconciliationQuery) String() string { return proto.CompactTextString(m) }
+func (*ReconciliationQuery) ProtoMessage()    {}
+func (*ReconciliationQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{1}
+}
+
+func (m *ReconciliationQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconciliationQuery.Unmarshal(m, b)
+}
+func (m *ReconciliationQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconciliationQuery.Marshal(b, m, deterministic)
+}
+func (m *ReconciliationQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconciliationQuery.Merge(m, src)
+}
+func (m *ReconciliationQuery) XXX_Size() int {
+	return xxx_messageInfo_ReconciliationQuery.Size(m)
+}
+func (m *ReconciliationQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconciliationQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconciliationQuery proto.InternalMessageInfo
+
+func (m *ReconciliationQuery) GetSignatureHeader() *common.SignatureHeader {
+	if m != nil {
+		return m.SignatureHeader
+	}
+	return nil
+}
+
+func (m *ReconciliationQuery) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// SignedReconciliationRequest contains marshalled request bytes and signature
+type SignedReconciliationRequest struct {
+	// The bytes of ReconciliationRequest or ReconciliationQuery
+	Request []byte `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Signature over request bytes; this signature is to be verified against the client identity
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedReconciliationRequest) Reset()         { *m = SignedReconciliationRequest{} }
+func (m *SignedReconciliationRequest) String() string { return proto.CompactTextString(m) }
+func (*SignedReconciliationRequest) ProtoMessage()    {}
+func (*SignedReconciliationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{2}
+}
+
+func (m *SignedReconciliationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedReconciliationRequest.Unmarshal(m, b)
+}
+func (m *SignedReconciliationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedReconciliationRequest.Marshal(b, m, deterministic)
+}
+func (m *SignedReconciliationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedReconciliationRequest.Merge(m, src)
+}
+func (m *SignedReconciliationRequest) XXX_Size() int {
+	return xxx_messageInfo_SignedReconciliationRequest.Size(m)
+}
+func (m *SignedReconciliationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedReconciliationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedReconciliationRequest proto.InternalMessageInfo
+
+func (m *SignedReconciliationRequest) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SignedReconciliationRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// ReconciliationFilter selects the missing private data reconciled by an on-demand reconciliation pass,
+// the empty fields match any namespace, collection, or block
+type ReconciliationFilter struct {
+	Namespace  string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Collection string `protobuf:"bytes,2,opt,name=collection,proto3" json:"collection,omitempty"`
+	StartBlock uint64 `protobuf:"varint,3,opt,name=start_block,json=startBlock,proto3" json:"start_block,omitempty"`
+	// The last block whose missing private data is reconciled, 0 denotes no bound
+	EndBlock             uint64   `protobuf:"varint,4,opt,name=end_block,json=endBlock,proto3" json:"end_block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReconciliationFilter) Reset()         { *m = ReconciliationFilter{} }
+func (m *ReconciliationFilter) String() string { return proto.CompactTextString(m) }
+func (*ReconciliationFilter) ProtoMessage()    {}
+func (*ReconciliationFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{3}
+}
+
+func (m *ReconciliationFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconciliationFilter.Unmarshal(m, b)
+}
+func (m *ReconciliationFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconciliationFilter.Marshal(b, m, deterministic)
+}
+func (m *ReconciliationFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconciliationFilter.Merge(m, src)
+}
+func (m *ReconciliationFilter) XXX_Size() int {
+	return xxx_messageInfo_ReconciliationFilter.Size(m)
+}
+func (m *ReconciliationFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconciliationFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconciliationFilter proto.InternalMessageInfo
+
+func (m *ReconciliationFilter) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReconciliationFilter) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *ReconciliationFilter) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *ReconciliationFilter) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+// CollectionReconciliationProgress specifies the progress of the reconciliation of the missing private data of a collection
+type CollectionReconciliationProgress struct {
+	Namespace  string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Collection string `protobuf:"bytes,2,opt,name=collection,proto3" json:"collection,omitempty"`
+	// The range of blocks with missing private data of the collection
+	MinBlock uint64 `protobuf:"varint,3,opt,name=min_block,json=minBlock,proto3" json:"min_block,omitempty"`
+	MaxBlock uint64 `protobuf:"varint,4,opt,name=max_block,json=maxBlock,proto3" json:"max_block,omitempty"`
+	// The number of missing private write sets found when the pass started
+	Missing uint64 `protobuf:"varint,5,opt,name=missing,proto3" json:"missing,omitempty"`
+	// The number of private write sets fetched from other peers and committed
+	Reconciled uint64 `protobuf:"varint,6,opt,name=reconciled,proto3" json:"reconciled,omitempty"`
+	// The number of private write sets that could not be fetched from other peers
+	Unreconciled uint64 `protobuf:"varint,7,opt,name=unreconciled,proto3" json:"unreconciled,omitempty"`
+	// The number of private write sets fetched from other peers that did not match their hash
+	HashMismatches       uint64   `protobuf:"varint,8,opt,name=hash_mismatches,json=hashMismatches,proto3" json:"hash_mismatches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CollectionReconciliationProgress) Reset()         { *m = CollectionReconciliationProgress{} }
+func (m *CollectionReconciliationProgress) String() string { return proto.CompactTextString(m) }
+func (*CollectionReconciliationProgress) ProtoMessage()    {}
+func (*CollectionReconciliationProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{4}
+}
+
+func (m *CollectionReconciliationProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CollectionReconciliationProgress.Unmarshal(m, b)
+}
+func (m *CollectionReconciliationProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CollectionReconciliationProgress.Marshal(b, m, deterministic)
+}
+func (m *CollectionReconciliationProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CollectionReconciliationProgress.Merge(m, src)
+}
+func (m *CollectionReconciliationProgress) XXX_Size() int {
+	return xxx_messageInfo_CollectionReconciliationProgress.Size(m)
+}
+func (m *CollectionReconciliationProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_CollectionReconciliationProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CollectionReconciliationProgress proto.InternalMessageInfo
+
+func (m *CollectionReconciliationProgress) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CollectionReconciliationProgress) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *CollectionReconciliationProgress) GetMinBlock() uint64 {
+	if m != nil {
+		return m.MinBlock
+	}
+	return 0
+}
+
+func (m *CollectionReconciliationProgress) GetMaxBlock() uint64 {
+	if m != nil {
+		return m.MaxBlock
+	}
+	return 0
+}
+
+func (m *CollectionReconciliationProgress) GetMissing() uint64 {
+	if m != nil {
+		return m.Missing
+	}
+	return 0
+}
+
+func (m *CollectionReconciliationProgress) GetReconciled() uint64 {
+	if m != nil {
+		return m.Reconciled
+	}
+	return 0
+}
+
+func (m *CollectionReconciliationProgress) GetUnreconciled() uint64 {
+	if m != nil {
+		return m.Unreconciled
+	}
+	return 0
+}
+
+func (m *CollectionReconciliationProgress) GetHashMismatches() uint64 {
+	if m != nil {
+		return m.HashMismatches
+	}
+	return 0
+}
+
+// ReconciliationPass specifies an on-demand reconciliation pass
+type ReconciliationPass struct {
+	Filter    *ReconciliationFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	StartedAt *timestamp.Timestamp  `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// Not set while the pass is running
+	FinishedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	// The error that aborted the pass, if any
+	Error                string                              `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Collections          []*CollectionReconciliationProgress `protobuf:"bytes,5,rep,name=collections,proto3" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                            `json:"-"`
+	XXX_unrecognized     []byte                              `json:"-"`
+	XXX_sizecache        int32                               `json:"-"`
+}
+
+func (m *ReconciliationPass) Reset()         { *m = ReconciliationPass{} }
+func (m *ReconciliationPass) String() string { return proto.CompactTextString(m) }
+func (*ReconciliationPass) ProtoMessage()    {}
+func (*ReconciliationPass) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{5}
+}
+
+func (m *ReconciliationPass) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconciliationPass.Unmarshal(m, b)
+}
+func (m *ReconciliationPass) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconciliationPass.Marshal(b, m, deterministic)
+}
+func (m *ReconciliationPass) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconciliationPass.Merge(m, src)
+}
+func (m *ReconciliationPass) XXX_Size() int {
+	return xxx_messageInfo_ReconciliationPass.Size(m)
+}
+func (m *ReconciliationPass) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconciliationPass.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconciliationPass proto.InternalMessageInfo
+
+func (m *ReconciliationPass) GetFilter() *ReconciliationFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *ReconciliationPass) GetStartedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartedAt
+	}
+	return nil
+}
+
+func (m *ReconciliationPass) GetFinishedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return nil
+}
+
+func (m *ReconciliationPass) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ReconciliationPass) GetCollections() []*CollectionReconciliationProgress {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+// ReconciliationStatusResponse specifies the response payload of a query of the reconciliation status
+type ReconciliationStatusResponse struct {
+	// Whether the private data reconciliation is enabled on the peer
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// The state of the on-demand reconciliation: idle, pending, or running
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// The running on-demand pass, or the last completed one, not set if no on-demand pass ran since the peer started
+	LastPass             *ReconciliationPass `protobuf:"bytes,3,opt,name=last_pass,json=lastPass,proto3" json:"last_pass,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ReconciliationStatusResponse) Reset()         { *m = ReconciliationStatusResponse{} }
+func (m *ReconciliationStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*ReconciliationStatusResponse) ProtoMessage()    {}
+func (*ReconciliationStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3e03b45029e326de, []int{6}
+}
+
+func (m *ReconciliationStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconciliationStatusResponse.Unmarshal(m, b)
+}
+func (m *ReconciliationStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconciliationStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *ReconciliationStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconciliationStatusResponse.Merge(m, src)
+}
+func (m *ReconciliationStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_ReconciliationStatusResponse.Size(m)
+}
+func (m *ReconciliationStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconciliationStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconciliationStatusResponse proto.InternalMessageInfo
+
+func (m *ReconciliationStatusResponse) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *ReconciliationStatusResponse) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ReconciliationStatusResponse) GetLastPass() *ReconciliationPass {
+	if m != nil {
+		return m.LastPass
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ReconciliationRequest)(nil), "reconciliationgrpc.ReconciliationRequest")
+	proto.RegisterType((*ReconciliationQuery)(nil), "reconciliationgrpc.ReconciliationQuery")
+	proto.RegisterType((*SignedReconciliationRequest)(nil), "reconciliationgrpc.SignedReconciliationRequest")
+	proto.RegisterType((*ReconciliationFilter)(nil), "reconciliationgrpc.ReconciliationFilter")
+	proto.RegisterType((*CollectionReconciliationProgress)(nil), "reconciliationgrpc.CollectionReconciliationProgress")
+	proto.RegisterType((*ReconciliationPass)(nil), "reconciliationgrpc.ReconciliationPass")
+	proto.RegisterType((*ReconciliationStatusResponse)(nil), "reconciliationgrpc.ReconciliationStatusResponse")
+}
+
+func init() { proto.RegisterFile("reconciliation.proto", fileDescriptor_3e03b45029e326de) }
+
+var fileDescriptor_3e03b45029e326de = []byte{
+	// 673 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0xcd, 0x6e, 0xdb, 0x38,
+	0x10, 0x8e, 0xf2, 0x6b, 0x8d, 0x83, 0x64, 0xc1, 0x64, 0x77, 0x05, 0x3b, 0xbb, 0x31, 0x74, 0xd8,
+	0xf5, 0xc9, 0x5a, 0x78, 0x7b, 0x29, 0x7a, 0x49, 0x1c, 0xf4, 0xef, 0x50, 0xa0, 0x65, 0xda, 0x1c,
+	0x7a, 0x31, 0x68, 0x69, 0x2c, 0x11, 0x95, 0x28, 0x95, 0xa4, 0x8b, 0xe4, 0x35, 0xda, 0x27, 0xe8,
+	0x7b, 0xe4, 0x49, 0xfa, 0x34, 0x05, 0x29, 0xc9, 0xb6, 0x12, 0xa3, 0x46, 0x5b, 0xa0, 0x27, 0x69,
+	0xbe, 0xef, 0x9b, 0xe1, 0x37, 0xc3, 0x1f, 0x38, 0x96, 0x18, 0xe6, 0x22, 0xe4, 0x29, 0x67, 0x9a,
+	0xe7, 0x62, 0x50, 0xc8, 0x5c, 0xe7, 0x84, 0x34, 0xd1, 0x58, 0x16, 0x61, 0xe7, 0x28, 0xcc, 0xb3,
+	0x2c, 0x17, 0x41, 0xf9, 0x29, 0x85, 0x9d, 0x6e, 0x9c, 0xe7, 0x71, 0x8a, 0x81, 0x8d, 0x26, 0xb3,
+	0x69, 0x80, 0x59, 0xa1, 0x6f, 0x2a, 0xf2, 0xf4, 0x2e, 0xa9, 0x79, 0x86, 0x4a, 0xb3, 0xac, 0x28,
+	0x05, 0xfe, 0xad, 0x03, 0xbf, 0xd3, 0xc6, 0x4a, 0x14, 0xdf, 0xcf, 0x50, 0x69, 0x32, 0x82, 0xdf,
+	0x14, 0x8f, 0x05, 0xd3, 0x33, 0x89, 0xe3, 0x04, 0x59, 0x84, 0xd2, 0x73, 0x7a, 0x4e, 0xbf, 0x3d,
+	0xfc, 0x73, 0x50, 0x19, 0xb8, 0xac, 0xf9, 0x67, 0x96, 0xa6, 0x87, 0xaa, 0x09, 0x90, 0xbf, 0x00,
+	0xc2, 0x84, 0x09, 0x81, 0xe9, 0x98, 0x47, 0xde, 0x66, 0xcf, 0xe9, 0xbb, 0xd4, 0xad, 0x90, 0xe7,
+	0x11, 0x39, 0x83, 0xdd, 0x29, 0x4f, 0x35, 0x4a, 0x6f, 0xcb, 0x16, 0xee, 0x0f, 0xee, 0x37, 0x3d,
+	0x68, 0xba, 0x7b, 0x62, 0xf5, 0xb4, 0xca, 0xf3, 0xaf, 0xe1, 0xa8, 0xc9, 0xbf, 0x9a, 0xa1, 0xbc,
+	0xf9, 0x05, 0xde, 0xfd, 0x37, 0xd0, 0x35, 0x25, 0x30, 0x5a, 0x3d, 0x3d, 0x0f, 0xf6, 0x64, 0xf9,
+	0x6b, 0x17, 0xde, 0xa7, 0x75, 0x48, 0x4e, 0xc0, 0x9d, 0x2f, 0x65, 0xcb, 0xee, 0xd3, 0x05, 0xe0,
+	0x7f, 0x74, 0xe0, 0x78, 0x55, 0xc7, 0x26, 0x4d, 0xb0, 0x0c, 0x55, 0xc1, 0x42, 0xb4, 0x25, 0x5d,
+	0xba, 0x00, 0xc8, 0xdf, 0x00, 0x61, 0x9e, 0xa6, 0x18, 0x9a, 0x8c, 0xca, 0xec, 0x12, 0x42, 0x4e,
+	0xa1, 0xad, 0x34, 0x93, 0x7a, 0x3c, 0x49, 0xf3, 0xf0, 0x9d, 0x1d, 0xf7, 0x36, 0x05, 0x0b, 0x8d,
+	0x0c, 0x42, 0xba, 0xe0, 0xa2, 0x88, 0x2a, 0x7a, 0xdb, 0xd2, 0x2d, 0x14, 0x91, 0x25, 0xfd, 0xcf,
+	0x9b, 0xd0, 0xbb, 0x98, 0x17, 0x6b, 0xda, 0x7b, 0x29, 0xf3, 0x58, 0xa2, 0x52, 0x3f, 0x69, 0xb0,
+	0x0b, 0x6e, 0xc6, 0x45, 0xc3, 0x5e, 0x2b, 0xe3, 0x62, 0x6e, 0x2e, 0x63, 0xd7, 0x4d, 0x73, 0x19,
+	0xbb, 0x2e, 0x49, 0x0f, 0xf6, 0x32, 0xae, 0x14, 0x17, 0xb1, 0xb7, 0x63, 0xa9, 0x3a, 0x34, 0x6b,
+	0xd6, 0xe7, 0x09, 0x23, 0x6f, 0xb7, 0xec, 0x79, 0x81, 0x10, 0x1f, 0xf6, 0x67, 0x62, 0x49, 0xb1,
+	0x67, 0x15, 0x0d, 0x8c, 0xfc, 0x0b, 0x87, 0x09, 0x53, 0xc9, 0x38, 0xe3, 0x2a, 0x63, 0x3a, 0x4c,
+	0x50, 0x79, 0x2d, 0x2b, 0x3b, 0x30, 0xf0, 0x8b, 0x39, 0xea, 0xdf, 0x6e, 0x02, 0xb9, 0x33, 0x19,
+	0xa6, 0xd4, 0xd2, 0x11, 0x77, 0x7e, 0xec, 0x88, 0x93, 0x87, 0x50, 0xee, 0x13, 0x46, 0x63, 0xa6,
+	0xed, 0xe4, 0xda, 0xc3, 0xce, 0xa0, 0xbc, 0xd7, 0x83, 0xfa, 0x5e, 0x0f, 0x5e, 0xd7, 0xf7, 0x9a,
+	0xba, 0x95, 0xfa, 0x5c, 0x93, 0x47, 0xd0, 0x9e, 0x72, 0xc1, 0x55, 0x52, 0xe6, 0x6e, 0xad, 0xcd,
+	0x85, 0x5a, 0x7e, 0xae, 0xc9, 0x31, 0xec, 0xa0, 0x94, 0xb9, 0xb4, 0x03, 0x77, 0x69, 0x19, 0x90,
+	0x2b, 0x68, 0x2f, 0x76, 0x4d, 0x79, 0x3b, 0xbd, 0xad, 0x7e, 0x7b, 0xf8, 0x60, 0x55, 0x53, 0xeb,
+	0x0e, 0x0c, 0x5d, 0x2e, 0xe4, 0x7f, 0x72, 0xe0, 0xa4, 0xa9, 0xbb, 0xd4, 0x4c, 0xcf, 0x14, 0x45,
+	0x55, 0xe4, 0x42, 0xa1, 0xd9, 0x66, 0x14, 0x6c, 0x62, 0xf6, 0xc9, 0x4c, 0xb2, 0x45, 0xeb, 0xd0,
+	0x18, 0x55, 0x9a, 0x69, 0xac, 0x4e, 0x55, 0x19, 0x90, 0x0b, 0x70, 0x53, 0xa6, 0xf4, 0xb8, 0x60,
+	0x4a, 0x55, 0x9d, 0xff, 0xb3, 0x7e, 0xf6, 0x66, 0xcf, 0x68, 0xcb, 0x24, 0x9a, 0xbf, 0xe1, 0x17,
+	0x07, 0x0e, 0x9a, 0x02, 0x72, 0x05, 0x6e, 0x8d, 0x20, 0x09, 0x56, 0x55, 0xfc, 0xc6, 0xb3, 0xd0,
+	0xf9, 0xe3, 0xde, 0xf0, 0x1f, 0x9b, 0xd7, 0xda, 0xdf, 0x20, 0x05, 0xb8, 0x4f, 0x51, 0x97, 0x4d,
+	0x7f, 0x7f, 0xdd, 0xff, 0xd6, 0xb7, 0xd6, 0x9c, 0xa7, 0xbf, 0x31, 0x1a, 0xbd, 0x3d, 0x8b, 0xb9,
+	0x4e, 0x66, 0x13, 0xf3, 0x24, 0x06, 0xc9, 0x4d, 0x81, 0x32, 0xc5, 0x28, 0x46, 0x19, 0x4c, 0xd9,
+	0x44, 0xf2, 0x30, 0x88, 0x73, 0xa5, 0x78, 0x11, 0x14, 0x92, 0x7f, 0x88, 0x98, 0x66, 0xc1, 0xfd,
+	0x25, 0x26, 0xbb, 0xb6, 0x8f, 0xff, 0xbf, 0x0e, 0x00, 0x25, 0x82, 0xbd, 0xc6, 0xc4, 0x06, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ReconciliationClient is the client API for Reconciliation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ReconciliationClient interface {
+	// Trigger an on-demand reconciliation pass. SignedReconciliationRequest contains marshalled bytes for ReconciliationRequest
+	Reconcile(ctx context.Context, in *SignedReconciliationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Query the reconciliation status. SignedReconciliationRequest contains marshalled bytes for ReconciliationQuery
+	GetStatus(ctx context.Context, in *SignedReconciliationRequest, opts ...grpc.CallOption) (*ReconciliationStatusResponse, error)
+}
+
+type reconciliationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReconciliationClient(cc grpc.ClientConnInterface) ReconciliationClient {
+	return &reconciliationClient{cc}
+}
+
+func (c *reconciliationClient) Reconcile(ctx context.Context, in *SignedReconciliationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/reconciliationgrpc.Reconciliation/Reconcile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reconciliationClient) GetStatus(ctx context.Context, in *SignedReconciliationRequest, opts ...grpc.CallOption) (*ReconciliationStatusResponse, error) {
+	out := new(ReconciliationStatusResponse)
+	err := c.cc.Invoke(ctx, "/reconciliationgrpc.Reconciliation/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReconciliationServer is the server API for Reconciliation service.
+type ReconciliationServer interface {
+	// Trigger an on-demand reconciliation pass. SignedReconciliationRequest contains marshalled bytes for ReconciliationRequest
+	Reconcile(context.Context, *SignedReconciliationRequest) (*empty.Empty, error)
+	// Query the reconciliation status. SignedReconciliationRequest contains marshalled bytes for ReconciliationQuery
+	GetStatus(context.Context, *SignedReconciliationRequest) (*ReconciliationStatusResponse, error)
+}
+
+// UnimplementedReconciliationServer can be embedded to have forward compatible implementations.
+type UnimplementedReconciliationServer struct {
+}
+
+func (*UnimplementedReconciliationServer) Reconcile(ctx context.Context, req *SignedReconciliationRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reconcile not implemented")
+}
+func (*UnimplementedReconciliationServer) GetStatus(ctx context.Context, req *SignedReconciliationRequest) (*ReconciliationStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+
+func RegisterReconciliationServer(s *grpc.Server, srv ReconciliationServer) {
+	s.RegisterService(&_Reconciliation_serviceDesc, srv)
+}
+
+func _Reconciliation_Reconcile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedReconciliationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReconciliationServer).Reconcile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reconciliationgrpc.Reconciliation/Reconcile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReconciliationServer).Reconcile(ctx, req.(*SignedReconciliationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reconciliation_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedReconciliationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReconciliationServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reconciliationgrpc.Reconciliation/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReconciliationServer).GetStatus(ctx, req.(*SignedReconciliationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Reconciliation_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "reconciliationgrpc.Reconciliation",
+	HandlerType: (*ReconciliationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Reconcile",
+			Handler:    _Reconciliation_Reconcile_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Reconciliation_GetStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reconciliation.proto",
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliationgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/gossip/privdata"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("reconciliationgrpc")
+
+// ReconcilerGetter gets the private data reconciler associated with a channel
+type ReconcilerGetter interface {
+	Reconciler(channelID string) privdata.PvtDataReconciler
+}
+
+// ACLProvider checks the access control of a resource
+type ACLProvider interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// ReconciliationService implements the ReconciliationServer admin service through which the
+// reconciliation of the missing private data of the channels is triggered and monitored
+type ReconciliationService struct {
+	ReconcilerGetter ReconcilerGetter
+	ACLProvider      ACLProvider
+}
+
+// Reconcile triggers an on-demand reconciliation pass over the missing private data of a channel
+func (s *ReconciliationService) Reconcile(ctx context.Context, signedRequest *SignedReconciliationRequest) (*empty.Empty, error) {
+	request := &ReconciliationRequest{}
+	if err := proto.Unmarshal(signedRequest.Request, request); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal reconciliation request")
+	}
+	if err := s.checkACL(resources.Reconciliation_reconcile, request.SignatureHeader, signedRequest); err != nil {
+		return nil, err
+	}
+	reconciler, err := s.getReconciler(request.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	filter := &privdata.ReconciliationFilter{
+		Namespace:  request.Filter.GetNamespace(),
+		Collection: request.Filter.GetCollection(),
+		StartBlock: request.Filter.GetStartBlock(),
+		EndBlock:   request.Filter.GetEndBlock(),
+	}
+	if err := reconciler.Reconcile(filter); err != nil {
+		return nil, errors.WithMessage(err, "failed to trigger reconciliation")
+	}
+	logger.Infof("Triggered reconciliation on channel [%s]", request.ChannelId)
+	return &empty.Empty{}, nil
+}
+
+// GetStatus returns the state of the reconciler of a channel and the progress of its current or
+// last on-demand reconciliation pass
+func (s *ReconciliationService) GetStatus(ctx context.Context, signedRequest *SignedReconciliationRequest) (*ReconciliationStatusResponse, error) {
+	query := &ReconciliationQuery{}
+	if err := proto.Unmarshal(signedRequest.Request, query); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal reconciliation query")
+	}
+	if err := s.checkACL(resources.Reconciliation_getstatus, query.SignatureHeader, signedRequest); err != nil {
+		return nil, err
+	}
+	reconciler, err := s.getReconciler(query.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	return newStatusResponse(reconciler.Status())
+}
+
+func newStatusResponse(status *privdata.ReconciliationStatus) (*ReconciliationStatusResponse, error) {
+	resp := &ReconciliationStatusResponse{
+		Enabled: status.Enabled,
+		State:   status.State,
+	}
+	lastPass := status.LastPass
+	if lastPass == nil {
+		return resp, nil
+	}
+
+	startedAt, err := ptypes.TimestampProto(lastPass.StartedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the start time of the reconciliation pass")
+	}
+	resp.LastPass = &ReconciliationPass{
+		StartedAt: startedAt,
+		Error:     lastPass.Err,
+	}
+	if !lastPass.FinishedAt.IsZero() {
+		if resp.LastPass.FinishedAt, err = ptypes.TimestampProto(lastPass.FinishedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to convert the finish time of the reconciliation pass")
+		}
+	}
+	if lastPass.Filter != nil {
+		resp.LastPass.Filter = &ReconciliationFilter{
+			Namespace:  lastPass.Filter.Namespace,
+			Collection: lastPass.Filter.Collection,
+			StartBlock: lastPass.Filter.StartBlock,
+			EndBlock:   lastPass.Filter.EndBlock,
+		}
+	}
+	for _, c := range lastPass.Collections {
+		resp.LastPass.Collections = append(resp.LastPass.Collections, &CollectionReconciliationProgress{
+			Namespace:      c.Namespace,
+			Collection:     c.Collection,
+			MinBlock:       c.MinBlock,
+			MaxBlock:       c.MaxBlock,
+			Missing:        c.Missing,
+			Reconciled:     c.Reconciled,
+			Unreconciled:   c.Unreconciled,
+			HashMismatches: c.HashMismatches,
+		})
+	}
+	return resp, nil
+}
+
+func (s *ReconciliationService) getReconciler(channelID string) (privdata.PvtDataReconciler, error) {
+	if channelID == "" {
+		return nil, errors.New("missing channel ID")
+	}
+	reconciler := s.ReconcilerGetter.Reconciler(channelID)
+	if reconciler == nil {
+		return nil, errors.Errorf("cannot find reconciler for channel %s", channelID)
+	}
+	return reconciler, nil
+}
+
+func (s *ReconciliationService) checkACL(resName string, signatureHdr *common.SignatureHeader, signedRequest *SignedReconciliationRequest) error {
+	if signatureHdr == nil {
+		return errors.New("missing signature header")
+	}
+	expirationTime := crypto.ExpiresAt(signatureHdr.Creator)
+	if !expirationTime.IsZero() && time.Now().After(expirationTime) {
+		return errors.New("client identity expired")
+	}
+	return s.ACLProvider.CheckACL(
+		resName,
+		"",
+		[]*protoutil.SignedData{{
+			Identity:  signatureHdr.Creator,
+			Data:      signedRequest.Request,
+			Signature: signedRequest.Signature,
+		}},
+	)
+}
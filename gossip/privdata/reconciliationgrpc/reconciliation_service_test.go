@@ -0,0 +1,206 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliationgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/gossip/privdata"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate counterfeiter -o mock/reconciler_getter.go -fake-name ReconcilerGetter . reconcilerGetter
+type reconcilerGetter interface {
+	ReconcilerGetter
+}
+
+//go:generate counterfeiter -o mock/acl_provider.go -fake-name ACLProvider . aclProvider
+type aclProvider interface {
+	ACLProvider
+}
+
+//go:generate counterfeiter -o mock/pvt_data_reconciler.go -fake-name PvtDataReconciler . pvtDataReconciler
+type pvtDataReconciler interface {
+	privdata.PvtDataReconciler
+}
+
+func TestReconciliation(t *testing.T) {
+	startedAt := time.Unix(1000, 0)
+	finishedAt := time.Unix(1060, 0)
+	fakeReconciler := &mock.PvtDataReconciler{}
+	fakeReconciler.StatusReturns(&privdata.ReconciliationStatus{
+		Enabled: true,
+		State:   privdata.ReconciliationIdle,
+		LastPass: &privdata.ReconciliationPass{
+			Filter:     &privdata.ReconciliationFilter{Namespace: "ns1", StartBlock: 10, EndBlock: 20},
+			StartedAt:  startedAt,
+			FinishedAt: finishedAt,
+			Err:        "failed to commit private data",
+			Collections: []*privdata.CollectionReconciliationProgress{
+				{
+					Namespace:      "ns1",
+					Collection:     "coll1",
+					MinBlock:       12,
+					MaxBlock:       18,
+					Missing:        5,
+					Reconciled:     2,
+					Unreconciled:   1,
+					HashMismatches: 1,
+				},
+			},
+		},
+	})
+	fakeReconcilerGetter := &mock.ReconcilerGetter{}
+	fakeReconcilerGetter.ReconcilerReturns(fakeReconciler)
+	fakeACLProvider := &mock.ACLProvider{}
+	reconciliationSvc := &ReconciliationService{ReconcilerGetter: fakeReconcilerGetter, ACLProvider: fakeACLProvider}
+
+	signatureHdr := &common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")}
+	request := &ReconciliationRequest{
+		SignatureHeader: signatureHdr,
+		ChannelId:       "testchannel",
+		Filter:          &ReconciliationFilter{Namespace: "ns1", Collection: "coll1", StartBlock: 10, EndBlock: 20},
+	}
+	signedRequest := &SignedReconciliationRequest{Request: protoutil.MarshalOrPanic(request), Signature: []byte("signature")}
+
+	_, err := reconciliationSvc.Reconcile(context.Background(), signedRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, fakeReconciler.ReconcileCallCount())
+	require.Equal(t,
+		&privdata.ReconciliationFilter{Namespace: "ns1", Collection: "coll1", StartBlock: 10, EndBlock: 20},
+		fakeReconciler.ReconcileArgsForCall(0),
+	)
+	require.Equal(t, "testchannel", fakeReconcilerGetter.ReconcilerArgsForCall(0))
+	resName, channelID, idinfo := fakeACLProvider.CheckACLArgsForCall(0)
+	require.Equal(t, resources.Reconciliation_reconcile, resName)
+	require.Equal(t, "", channelID)
+	require.Equal(t, []*protoutil.SignedData{{
+		Identity:  []byte("creator"),
+		Data:      signedRequest.Request,
+		Signature: []byte("signature"),
+	}}, idinfo)
+
+	query := &ReconciliationQuery{SignatureHeader: signatureHdr, ChannelId: "testchannel"}
+	signedQuery := &SignedReconciliationRequest{Request: protoutil.MarshalOrPanic(query), Signature: []byte("signature")}
+	resp, err := reconciliationSvc.GetStatus(context.Background(), signedQuery)
+	require.NoError(t, err)
+	resName, _, _ = fakeACLProvider.CheckACLArgsForCall(1)
+	require.Equal(t, resources.Reconciliation_getstatus, resName)
+
+	expectedStartedAt, err := ptypes.TimestampProto(startedAt)
+	require.NoError(t, err)
+	expectedFinishedAt, err := ptypes.TimestampProto(finishedAt)
+	require.NoError(t, err)
+	expectedResp := &ReconciliationStatusResponse{
+		Enabled: true,
+		State:   "idle",
+		LastPass: &ReconciliationPass{
+			Filter:     &ReconciliationFilter{Namespace: "ns1", StartBlock: 10, EndBlock: 20},
+			StartedAt:  expectedStartedAt,
+			FinishedAt: expectedFinishedAt,
+			Error:      "failed to commit private data",
+			Collections: []*CollectionReconciliationProgress{
+				{
+					Namespace:      "ns1",
+					Collection:     "coll1",
+					MinBlock:       12,
+					MaxBlock:       18,
+					Missing:        5,
+					Reconciled:     2,
+					Unreconciled:   1,
+					HashMismatches: 1,
+				},
+			},
+		},
+	}
+	require.True(t, proto.Equal(expectedResp, resp))
+}
+
+func TestReconciliationStatusWithoutPass(t *testing.T) {
+	fakeReconciler := &mock.PvtDataReconciler{}
+	fakeReconciler.StatusReturns(&privdata.ReconciliationStatus{State: privdata.ReconciliationIdle})
+	fakeReconcilerGetter := &mock.ReconcilerGetter{}
+	fakeReconcilerGetter.ReconcilerReturns(fakeReconciler)
+	reconciliationSvc := &ReconciliationService{ReconcilerGetter: fakeReconcilerGetter, ACLProvider: &mock.ACLProvider{}}
+
+	query := &ReconciliationQuery{
+		SignatureHeader: &common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")},
+		ChannelId:       "testchannel",
+	}
+	signedQuery := &SignedReconciliationRequest{Request: protoutil.MarshalOrPanic(query), Signature: []byte("signature")}
+	resp, err := reconciliationSvc.GetStatus(context.Background(), signedQuery)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&ReconciliationStatusResponse{State: "idle"}, resp))
+}
+
+func TestReconciliationErrors(t *testing.T) {
+	fakeReconciler := &mock.PvtDataReconciler{}
+	fakeReconcilerGetter := &mock.ReconcilerGetter{}
+	fakeReconcilerGetter.ReconcilerReturns(fakeReconciler)
+	fakeACLProvider := &mock.ACLProvider{}
+	reconciliationSvc := &ReconciliationService{ReconcilerGetter: fakeReconcilerGetter, ACLProvider: fakeACLProvider}
+
+	signatureHdr := &common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")}
+	newSignedRequest := func(request proto.Message) *SignedReconciliationRequest {
+		return &SignedReconciliationRequest{Request: protoutil.MarshalOrPanic(request), Signature: []byte("signature")}
+	}
+	validRequest := newSignedRequest(&ReconciliationRequest{SignatureHeader: signatureHdr, ChannelId: "testchannel"})
+	validQuery := newSignedRequest(&ReconciliationQuery{SignatureHeader: signatureHdr, ChannelId: "testchannel"})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		invalidRequest := &SignedReconciliationRequest{Request: []byte("garbage")}
+		_, err := reconciliationSvc.Reconcile(context.Background(), invalidRequest)
+		require.Contains(t, err.Error(), "failed to unmarshal reconciliation request")
+		_, err = reconciliationSvc.GetStatus(context.Background(), invalidRequest)
+		require.Contains(t, err.Error(), "failed to unmarshal reconciliation query")
+	})
+
+	t.Run("missing signature header", func(t *testing.T) {
+		_, err := reconciliationSvc.Reconcile(context.Background(), newSignedRequest(&ReconciliationRequest{ChannelId: "testchannel"}))
+		require.EqualError(t, err, "missing signature header")
+		_, err = reconciliationSvc.GetStatus(context.Background(), newSignedRequest(&ReconciliationQuery{ChannelId: "testchannel"}))
+		require.EqualError(t, err, "missing signature header")
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		fakeACLProvider.CheckACLReturns(errors.New("access denied"))
+		defer fakeACLProvider.CheckACLReturns(nil)
+		_, err := reconciliationSvc.Reconcile(context.Background(), validRequest)
+		require.EqualError(t, err, "access denied")
+		_, err = reconciliationSvc.GetStatus(context.Background(), validQuery)
+		require.EqualError(t, err, "access denied")
+	})
+
+	t.Run("missing channel", func(t *testing.T) {
+		_, err := reconciliationSvc.Reconcile(context.Background(), newSignedRequest(&ReconciliationRequest{SignatureHeader: signatureHdr}))
+		require.EqualError(t, err, "missing channel ID")
+	})
+
+	t.Run("reconciler not found", func(t *testing.T) {
+		fakeReconcilerGetter.ReconcilerReturns(nil)
+		defer fakeReconcilerGetter.ReconcilerReturns(fakeReconciler)
+		_, err := reconciliationSvc.Reconcile(context.Background(), validRequest)
+		require.EqualError(t, err, "cannot find reconciler for channel testchannel")
+		_, err = reconciliationSvc.GetStatus(context.Background(), validQuery)
+		require.EqualError(t, err, "cannot find reconciler for channel testchannel")
+	})
+
+	t.Run("reconciler error", func(t *testing.T) {
+		fakeReconciler.ReconcileReturns(errors.New("an on-demand reconciliation pass is already in progress"))
+		_, err := reconciliationSvc.Reconcile(context.Background(), validRequest)
+		require.EqualError(t, err, "failed to trigger reconciliation: an on-demand reconciliation pass is already in progress")
+	})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoext
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
+	"github.com/pkg/errors"
+)
+
+// SetStateCheckpoint sets the state checkpoint that the peer publishes along with its ledger height.
+// A nil checkpoint removes the previously set checkpoint
+func SetStateCheckpoint(props *gossip.Properties, checkpoint *statecheckpoint.StateCheckpoint) error {
+	if checkpoint == nil {
+		props.StateCheckpoint = nil
+		return nil
+	}
+	checkpointBytes, err := proto.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling state checkpoint")
+	}
+	props.StateCheckpoint = checkpointBytes
+	return nil
+}
+
+// GetStateCheckpoint returns the state checkpoint published in the properties,
+// or nil if the peer does not publish a state checkpoint
+func GetStateCheckpoint(props *gossip.Properties) (*statecheckpoint.StateCheckpoint, error) {
+	if len(props.GetStateCheckpoint()) == 0 {
+		return nil, nil
+	}
+	checkpoint := &statecheckpoint.StateCheckpoint{}
+	if err := proto.Unmarshal(props.StateCheckpoint, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling state checkpoint")
+	}
+	return checkpoint, nil
+}
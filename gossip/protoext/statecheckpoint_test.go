@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoext_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateCheckpoint(t *testing.T) {
+	props := &gossip.Properties{
+		LedgerHeight: 11,
+		Chaincodes:   []*gossip.Chaincode{{Name: "cc", Version: "1.0"}},
+	}
+	checkpoint, err := protoext.GetStateCheckpoint(props)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+
+	// the checkpoint survives the marshaling and unmarshaling of the properties
+	checkpoint10 := &statecheckpoint.StateCheckpoint{BlockNum: 10, Commitment: []byte("commitment-10")}
+	require.NoError(t, protoext.SetStateCheckpoint(props, checkpoint10))
+	propsBytes, err := proto.Marshal(props)
+	require.NoError(t, err)
+	props = &gossip.Properties{}
+	require.NoError(t, proto.Unmarshal(propsBytes, props))
+	require.Equal(t, uint64(11), props.LedgerHeight)
+	checkpoint, err = protoext.GetStateCheckpoint(props)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(checkpoint10, checkpoint))
+
+	// the checkpoint is carried in its own field of the properties
+	require.NotEmpty(t, props.StateCheckpoint)
+	require.Empty(t, props.XXX_unrecognized)
+
+	// setting a new checkpoint replaces the previous one
+	checkpoint20 := &statecheckpoint.StateCheckpoint{BlockNum: 20, Commitment: []byte("commitment-20")}
+	require.NoError(t, protoext.SetStateCheckpoint(props, checkpoint20))
+	checkpoint, err = protoext.GetStateCheckpoint(props)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(checkpoint20, checkpoint))
+
+	require.NoError(t, protoext.SetStateCheckpoint(props, nil))
+	checkpoint, err = protoext.GetStateCheckpoint(props)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+	require.Nil(t, props.StateCheckpoint)
+
+	checkpoint, err = protoext.GetStateCheckpoint(nil)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+
+	props.StateCheckpoint = []byte{1<<3 | proto.WireBytes, 10, 1}
+	_, err = protoext.GetStateCheckpoint(props)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed unmarshaling state checkpoint")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/deliverservice"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
@@ -73,6 +74,10 @@ type gossipSvc interface {
 	// to other peers in the channel
 	UpdateChaincodes(chaincode []*gproto.Chaincode, channelID common.ChannelID)
 
+	// UpdateStateCheckpoint updates the state checkpoint the peer
+	// publishes to other peers in the channel
+	UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint, channelID common.ChannelID)
+
 	// Gossip sends a message to other peers to the network
 	Gossip(msg *gproto.GossipMessage)
 
@@ -288,8 +293,9 @@ func New(
 	}, nil
 }
 
-// DistributePrivateData distribute private read write set inside the channel based on the collections policies
-func (g *GossipService) DistributePrivateData(channelID string, txID string, privData *tspb.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error {
+// DistributePrivateData distribute private read write set inside the channel based on the collections policies,
+// and stores it in the transient store on behalf of the client identified by the given creator
+func (g *GossipService) DistributePrivateData(channelID string, txID string, privData *tspb.TxPvtReadWriteSetWithConfigInfo, blkHt uint64, creator []byte) error {
 	g.lock.RLock()
 	handler, exists := g.privateHandlers[channelID]
 	g.lock.RUnlock()
@@ -297,15 +303,18 @@ func (g *GossipService) DistributePrivateData(channelID string, txID string, pri
 		return errors.Errorf("No private data handler for %s", channelID)
 	}
 
-	if err := handler.distributor.Distribute(txID, privData, blkHt); err != nil {
-		err := errors.WithMessagef(err, "failed to distribute private collection, txID %s, channel %s", txID, channelID)
-		logger.Error(err)
+	// The private data is stored before it is distributed so that the private data that exceeds
+	// the quotas of the transient store is not pushed to other peers either. If the distribution
+	// fails, the stored private data is purged as orphaned.
+	if err := handler.coordinator.StorePvtDataForClient(txID, privData, blkHt, creator); err != nil {
+		logger.Error("Failed to store private data into transient store, txID",
+			txID, "channel", channelID, "due to", err)
 		return err
 	}
 
-	if err := handler.coordinator.StorePvtData(txID, privData, blkHt); err != nil {
-		logger.Error("Failed to store private data into transient store, txID",
-			txID, "channel", channelID, "due to", err)
+	if err := handler.distributor.Distribute(txID, privData, blkHt); err != nil {
+		err := errors.WithMessagef(err, "failed to distribute private collection, txID %s, channel %s", txID, channelID)
+		logger.Error(err)
 		return err
 	}
 	return nil
@@ -470,6 +479,18 @@ func (g *GossipService) AddPayload(channelID string, payload *gproto.Payload) er
 	return g.chains[channelID].AddPayload(payload)
 }
 
+// Reconciler returns the private data reconciler of the given channel, or nil if the
+// gossip service is not initialized for the channel
+func (g *GossipService) Reconciler(channelID string) gossipprivdata.PvtDataReconciler {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	handler, exists := g.privateHandlers[channelID]
+	if !exists {
+		return nil
+	}
+	return handler.reconciler
+}
+
 // Stop stops the gossip component
 func (g *GossipService) Stop() {
 	g.lock.Lock()
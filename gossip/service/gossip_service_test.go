@@ -115,7 +115,7 @@ func TestInitGossipService(t *testing.T) {
 	require.NoError(t, err)
 	signer := mgmt.GetLocalSigningIdentityOrPanic(cryptoProvider)
 
-	messageCryptoService := peergossip.NewMCS(&mocks.ChannelPolicyManagerGetter{}, signer, mgmt.NewDeserializersManager(cryptoProvider), cryptoProvider)
+	messageCryptoService := peergossip.NewMCS(&mocks.ChannelPolicyManagerGetter{}, signer, mgmt.NewDeserializersManager(cryptoProvider), cryptoProvider, nil)
 	secAdv := peergossip.NewSecurityAdvisor(mgmt.NewDeserializersManager(cryptoProvider))
 	gossipConfig, err := gossip.GlobalConfig(endpoint, nil)
 	assert.NoError(t, err)
@@ -437,6 +437,14 @@ func (li *mockLedgerInfo) DoesPvtDataInfoExistInLedger(blkNum uint64) (bool, err
 	return false, nil
 }
 
+func (li *mockLedgerInfo) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
+func (li *mockLedgerInfo) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
 // Commit block to the ledger
 func (li *mockLedgerInfo) Commit(block *common.Block) error {
 	return nil
@@ -14,6 +14,7 @@ import (
 	proto "github.com/hyperledger/fabric-protos-go/gossip"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	"github.com/hyperledger/fabric/gossip/common"
@@ -86,6 +87,12 @@ func (*gossipMock) UpdateChaincodes(chaincode []*proto.Chaincode, channelID comm
 	panic("implement me")
 }
 
+// UpdateStateCheckpoint updates the state checkpoint the peer
+// publishes to other peers in the channel
+func (*gossipMock) UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint, channelID common.ChannelID) {
+	panic("implement me")
+}
+
 func (*gossipMock) Gossip(msg *proto.GossipMessage) {
 	panic("implement me")
 }
@@ -8,6 +8,7 @@ package mocks
 
 import (
 	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	"github.com/hyperledger/fabric/gossip/common"
@@ -50,6 +51,12 @@ func (g *GossipMock) UpdateChaincodes(chaincode []*proto.Chaincode, channelID co
 
 }
 
+// UpdateStateCheckpoint updates the state checkpoint the peer
+// publishes to other peers in the channel
+func (g *GossipMock) UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint, channelID common.ChannelID) {
+
+}
+
 func (g *GossipMock) LeaveChan(_ common.ChannelID) {
 	panic("implement me")
 }
@@ -92,7 +99,7 @@ func (g *GossipMock) IdentityInfo() api.PeerIdentitySet {
 }
 
 func (g *GossipMock) IsInMyOrg(member discovery.NetworkMember) bool {
-	panic("not implemented")
+	return g.Called(member).Bool(0)
 }
 
 func (g *GossipMock) Stop() {
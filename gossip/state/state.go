@@ -19,6 +19,8 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
 	vsccErrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	common2 "github.com/hyperledger/fabric/gossip/common"
@@ -83,9 +85,16 @@ type GossipAdapter interface {
 	// publishes to other peers in the channel
 	UpdateLedgerHeight(height uint64, channelID common2.ChannelID)
 
+	// UpdateStateCheckpoint updates the state checkpoint the peer
+	// publishes to other peers in the channel
+	UpdateStateCheckpoint(checkpoint *statecheckpoint.StateCheckpoint, channelID common2.ChannelID)
+
 	// PeersOfChannel returns the NetworkMembers considered alive
 	// and also subscribed to the channel given
 	PeersOfChannel(common2.ChannelID) []discovery.NetworkMember
+
+	// IsInMyOrg checks whether a network member is in this peer's org
+	IsInMyOrg(member discovery.NetworkMember) bool
 }
 
 // MCSAdapter adapter of message crypto service interface to bound
@@ -122,6 +131,13 @@ type ledgerResources interface {
 	// Get recent block sequence number
 	LedgerHeight() (uint64, error)
 
+	// GetStateCheckpoint returns the state checkpoint recorded at the given block,
+	// or nil if no state checkpoint is recorded at the block
+	GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error)
+
+	// GetLatestStateCheckpoint returns the latest recorded state checkpoint, or nil if none
+	GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error)
+
 	// Close ledgerResources
 	Close()
 }
@@ -166,6 +182,9 @@ type GossipStateProviderImpl struct {
 	blockingMode bool
 
 	config *StateConfig
+
+	// publishedCheckpoint is the state checkpoint last published to the peers of the channel
+	publishedCheckpoint *ledger.StateCheckpoint
 }
 
 // stateRequestValidator facilitates validation of the state request messages
@@ -266,6 +285,7 @@ func NewGossipStateProvider(
 		"current ledger sequence is at = %d, next expected block is = %d", chainID, height-1, s.payloads.Next())
 	logger.Debug("Updating gossip ledger height to", height)
 	services.UpdateLedgerHeight(height, common2.ChannelID(s.chainID))
+	s.publishStateCheckpoint()
 
 	// Listen for incoming communication
 	go s.receiveAndQueueGossipMessages(gossipChan)
@@ -278,6 +298,8 @@ func NewGossipStateProvider(
 	}
 	// Taking care of state request messages
 	go s.processStateRequests()
+	// Compare the state checkpoints of the peers of the organization
+	go s.checkStateCheckpoints()
 
 	return s
 }
@@ -516,6 +538,9 @@ func (s *GossipStateProviderImpl) handleStateResponse(msg protoext.ReceivedMessa
 		}
 
 		if err := s.mediator.VerifyBlock(common2.ChannelID(s.chainID), payload.SeqNum, block); err != nil {
+			if protoutil.IsBlockExtensionMismatch(err) {
+				s.stateMetrics.ExtensionRejections.With("channel", s.chainID).Add(1)
+			}
 			err = errors.WithStack(err)
 			s.logger.Warningf("Error verifying block with sequence number %d, due to %+v", payload.SeqNum, err)
 			return uint64(0), err
@@ -761,6 +786,9 @@ func (s *GossipStateProviderImpl) addPayload(payload *proto.Payload, blockingMod
 		return errors.New("Given payload is nil")
 	}
 	s.logger.Debugf("[%s] Adding payload to local buffer, blockNum = [%d]", s.chainID, payload.SeqNum)
+	if err := s.verifyBlockExtension(payload); err != nil {
+		return err
+	}
 	height, err := s.ledger.LedgerHeight()
 	if err != nil {
 		return errors.Wrap(err, "Failed obtaining ledger height")
@@ -786,6 +814,24 @@ func (s *GossipStateProviderImpl) addPayload(payload *proto.Payload, blockingMod
 	return nil
 }
 
+// verifyBlockExtension checks that the extension of the block carried by the payload matches
+// the extension hash committed by the orderer, so that a block with a forged extension cannot
+// take the place of the genuine block in the payloads buffer, whichever way it comes in.
+// Payloads that do not carry a proper block are dropped once they are popped out of the buffer.
+func (s *GossipStateProviderImpl) verifyBlockExtension(payload *proto.Payload) error {
+	block, err := protoutil.UnmarshalBlock(payload.Data)
+	if err != nil {
+		return nil
+	}
+	if _, err := protoutil.VerifyBlockExtensionHash(block); err != nil {
+		if protoutil.IsBlockExtensionMismatch(err) {
+			s.stateMetrics.ExtensionRejections.With("channel", s.chainID).Add(1)
+		}
+		return errors.WithMessagef(err, "block [%d] carries an invalid extension", payload.SeqNum)
+	}
+	return nil
+}
+
 func (s *GossipStateProviderImpl) straggler(currHeight uint64, receivedPayload *proto.Payload) bool {
 	// If state transfer is disabled, there is no way to request blocks from peers that their ledger has advanced too far.
 	stateDisabled := !s.config.StateEnabled
@@ -816,9 +862,86 @@ func (s *GossipStateProviderImpl) commitBlock(block *common.Block, pvtData util.
 
 	s.stateMetrics.Height.With("channel", s.chainID).Set(float64(block.Header.Number + 1))
 
+	s.publishStateCheckpoint()
+
 	return nil
 }
 
+// publishStateCheckpoint publishes the latest state checkpoint of the ledger
+// to the peers of the channel, if it changed since it was last published
+func (s *GossipStateProviderImpl) publishStateCheckpoint() {
+	checkpoint, err := s.ledger.GetLatestStateCheckpoint()
+	if err != nil {
+		s.logger.Warningf("[%s] Failed retrieving the latest state checkpoint: %+v", s.chainID, err)
+		return
+	}
+	if checkpoint == nil || (s.publishedCheckpoint != nil && s.publishedCheckpoint.BlockNumber == checkpoint.BlockNumber) {
+		return
+	}
+	s.mediator.UpdateStateCheckpoint(
+		&statecheckpoint.StateCheckpoint{
+			BlockNum:   checkpoint.BlockNumber,
+			Commitment: checkpoint.Commitment,
+		},
+		common2.ChannelID(s.chainID),
+	)
+	s.publishedCheckpoint = checkpoint
+}
+
+// checkStateCheckpoints periodically compares the state checkpoints published by the peers
+// of the organization with the state checkpoints of this peer at the same blocks
+func (s *GossipStateProviderImpl) checkStateCheckpoints() {
+	// the block of the last state checkpoint compared, for each peer
+	compared := make(map[string]uint64)
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(s.config.StateCheckInterval):
+			s.compareStateCheckpoints(compared)
+		}
+	}
+}
+
+func (s *GossipStateProviderImpl) compareStateCheckpoints(compared map[string]uint64) {
+	if latest, err := s.ledger.GetLatestStateCheckpoint(); err != nil || latest == nil {
+		// state checkpoints are disabled on this peer, or are not yet recorded
+		return
+	}
+	for _, p := range s.mediator.PeersOfChannel(common2.ChannelID(s.chainID)) {
+		if !s.mediator.IsInMyOrg(p) {
+			continue
+		}
+		peerCheckpoint, err := protoext.GetStateCheckpoint(p.Properties)
+		if err != nil {
+			s.logger.Warningf("[%s] Peer %s published a malformed state checkpoint: %+v", s.chainID, p.PreferredEndpoint(), err)
+			continue
+		}
+		if peerCheckpoint == nil {
+			continue
+		}
+		if blockNum, ok := compared[string(p.PKIid)]; ok && blockNum == peerCheckpoint.BlockNum {
+			continue
+		}
+		checkpoint, err := s.ledger.GetStateCheckpoint(peerCheckpoint.BlockNum)
+		if err != nil {
+			s.logger.Errorf("[%s] Failed retrieving the state checkpoint for block [%d]: %+v", s.chainID, peerCheckpoint.BlockNum, err)
+			return
+		}
+		if checkpoint == nil {
+			// the block is not yet committed, or no checkpoint is recorded at the block
+			continue
+		}
+		compared[string(p.PKIid)] = peerCheckpoint.BlockNum
+		if !bytes.Equal(checkpoint.Commitment, peerCheckpoint.Commitment) {
+			s.logger.Errorf("[%s] The state of peer %s diverges from the state of this peer at block [%d]: "+
+				"commitment of the peer=[%x], commitment of this peer=[%x]",
+				s.chainID, p.PreferredEndpoint(), peerCheckpoint.BlockNum, peerCheckpoint.Commitment, checkpoint.Commitment)
+			s.stateMetrics.CheckpointMismatches.With("channel", s.chainID).Add(1)
+		}
+	}
+}
+
 func min(a uint64, b uint64) uint64 {
 	return b ^ ((a ^ b) & (-(uint64(a-b) >> 63)))
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/statecheckpoint"
 	"github.com/hyperledger/fabric/core/mocks/validator"
 	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/gossip/api"
@@ -40,6 +41,7 @@ import (
 	"github.com/hyperledger/fabric/gossip/gossip/algo"
 	"github.com/hyperledger/fabric/gossip/gossip/channel"
 	"github.com/hyperledger/fabric/gossip/metrics"
+	gmetricsmocks "github.com/hyperledger/fabric/gossip/metrics/mocks"
 	"github.com/hyperledger/fabric/gossip/privdata"
 	capabilitymock "github.com/hyperledger/fabric/gossip/privdata/mocks"
 	"github.com/hyperledger/fabric/gossip/protoext"
@@ -121,7 +123,8 @@ func (*cryptoServiceMock) GetPKIidOfCert(peerIdentity api.PeerIdentityType) comm
 // VerifyBlock returns nil if the block is properly signed,
 // else returns error
 func (*cryptoServiceMock) VerifyBlock(channelID common.ChannelID, seqNum uint64, signedBlock *pcomm.Block) error {
-	return nil
+	_, err := protoutil.VerifyBlockExtensionHash(signedBlock)
+	return err
 }
 
 // Sign signs msg with this peer's signing key and outputs
@@ -230,6 +233,14 @@ func (mc *mockCommitter) LedgerHeight() (uint64, error) {
 	return args.Get(0).(uint64), args.Get(1).(error)
 }
 
+func (mc *mockCommitter) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
+func (mc *mockCommitter) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
 func (mc *mockCommitter) DoesPvtDataInfoExistInLedger(blkNum uint64) (bool, error) {
 	mc.Lock()
 	m := mc.Mock
@@ -325,6 +336,14 @@ func (mock *ramLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return false, nil
 }
 
+func (mock *ramLedger) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
+func (mock *ramLedger) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
 func (mock *ramLedger) GetBlockByNumber(blockNumber uint64) (*pcomm.Block, error) {
 	mock.RLock()
 	defer mock.RUnlock()
@@ -595,6 +614,114 @@ func TestAddPayloadLedgerUnavailable(t *testing.T) {
 	assert.Contains(t, err.Error(), "cannot query ledger")
 }
 
+func TestAddPayloadForgedExtension(t *testing.T) {
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, make(chan protoext.ReceivedMessage))
+	testMetricProvider := gmetricsmocks.TestUtilConstructMetricProvider()
+	p := newPeerNodeWithGossipWithMetrics(0, mc, noopPeerIdentityAcceptor, g, metrics.NewGossipMetrics(testMetricProvider.FakeProvider))
+	defer p.shutdown()
+
+	rawblock := protoutil.NewBlock(uint64(1), []byte{})
+	protoutil.SetExtensionHashInBlock(rawblock)
+	rawblock.Extension.ExtensionData = [][]byte{[]byte("forged extension")}
+	b, _ := pb.Marshal(rawblock)
+
+	// The payloads buffer does not take a block whose extension was tampered with, such as
+	// one handed over by the deliver client, even though it bypasses the gossip channel
+	err := p.s.AddPayload(&proto.Payload{SeqNum: uint64(1), Data: b})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "block [1] carries an invalid extension")
+	assert.True(t, protoutil.IsBlockExtensionMismatch(err))
+	assert.Equal(t, 0, p.s.payloads.Size())
+	assert.Equal(t, 1, testMetricProvider.FakeExtensionRejections.AddCallCount())
+	assert.Equal(t, []string{"channel", "testchannelid"}, testMetricProvider.FakeExtensionRejections.WithArgsForCall(0))
+}
+
+func TestStateResponseForgedExtension(t *testing.T) {
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, make(chan protoext.ReceivedMessage))
+	testMetricProvider := gmetricsmocks.TestUtilConstructMetricProvider()
+	p := newPeerNodeWithGossipWithMetrics(0, mc, noopPeerIdentityAcceptor, g, metrics.NewGossipMetrics(testMetricProvider.FakeProvider))
+	defer p.shutdown()
+
+	rawblock := protoutil.NewBlock(uint64(1), []byte{})
+	protoutil.SetExtensionHashInBlock(rawblock)
+	rawblock.Extension.ExtensionData = [][]byte{[]byte("forged extension")}
+	b, _ := pb.Marshal(rawblock)
+	msg, _ := protoext.NoopSign(&proto.GossipMessage{
+		Channel: []byte("testchannelid"),
+		Content: &proto.GossipMessage_StateResponse{StateResponse: &proto.RemoteStateResponse{
+			Payloads: []*proto.Payload{{SeqNum: uint64(1), Data: b}},
+		}},
+	})
+	response := &receivedMessageMock{}
+	response.On("GetGossipMessage").Return(msg)
+
+	// The block is rejected by the message crypto service, which counts the extension mismatch
+	_, err := p.s.handleStateResponse(response)
+	assert.Error(t, err)
+	assert.True(t, protoutil.IsBlockExtensionMismatch(err))
+	assert.Equal(t, 0, p.s.payloads.Size())
+	assert.Equal(t, 1, testMetricProvider.FakeExtensionRejections.AddCallCount())
+	assert.Equal(t, []string{"channel", "testchannelid"}, testMetricProvider.FakeExtensionRejections.WithArgsForCall(0))
+}
+
+func TestStateCheckpoints(t *testing.T) {
+	ldgr := &stateCheckpointsLedger{
+		coordinatorMock: new(coordinatorMock),
+		checkpoints: map[uint64][]byte{
+			10: []byte("commitment-10"),
+			20: []byte("commitment-20"),
+		},
+	}
+	g := &mocks.GossipMock{}
+	testMetricProvider := gmetricsmocks.TestUtilConstructMetricProvider()
+	s := &GossipStateProviderImpl{
+		logger:       flogging.MustGetLogger(gutil.StateLogger),
+		chainID:      "testchannelid",
+		mediator:     &ServicesMediator{GossipAdapter: g},
+		ledger:       ldgr,
+		stateMetrics: metrics.NewGossipMetrics(testMetricProvider.FakeProvider).StateMetrics,
+	}
+
+	s.publishStateCheckpoint()
+	assert.Equal(t, uint64(20), s.publishedCheckpoint.BlockNumber)
+
+	peerWithCheckpoint := func(endpoint string, blockNum uint64, commitment string) discovery.NetworkMember {
+		props := &proto.Properties{LedgerHeight: blockNum + 1}
+		assert.NoError(t, protoext.SetStateCheckpoint(props, &statecheckpoint.StateCheckpoint{
+			BlockNum:   blockNum,
+			Commitment: []byte(commitment),
+		}))
+		return discovery.NetworkMember{PKIid: common.PKIidType(endpoint), Endpoint: endpoint, Properties: props}
+	}
+	matchingPeer := peerWithCheckpoint("p1", 10, "commitment-10")
+	divergedPeer := peerWithCheckpoint("p2", 20, "diverged-commitment-20")
+	aheadPeer := peerWithCheckpoint("p3", 30, "commitment-30")
+	otherOrgPeer := peerWithCheckpoint("p4", 20, "other-org-commitment-20")
+	g.On("PeersOfChannel", common.ChannelID("testchannelid")).Return(
+		[]discovery.NetworkMember{matchingPeer, divergedPeer, aheadPeer, otherOrgPeer},
+	)
+	g.On("IsInMyOrg", otherOrgPeer).Return(false)
+	g.On("IsInMyOrg", mock.Anything).Return(true)
+
+	compared := map[string]uint64{}
+	s.compareStateCheckpoints(compared)
+	assert.Equal(t, map[string]uint64{"p1": 10, "p2": 20}, compared)
+	assert.Equal(t, 1, testMetricProvider.FakeCheckpointMismatches.AddCallCount())
+	assert.Equal(t, []string{"channel", "testchannelid"}, testMetricProvider.FakeCheckpointMismatches.WithArgsForCall(0))
+
+	// the mismatch is reported once per checkpoint
+	s.compareStateCheckpoints(compared)
+	assert.Equal(t, 1, testMetricProvider.FakeCheckpointMismatches.AddCallCount())
+}
+
 func TestLargeBlockGap(t *testing.T) {
 	// Scenario: the peer knows of a peer who has a ledger height much higher
 	// than itself (500 blocks higher).
@@ -1309,6 +1436,38 @@ func (mock *coordinatorMock) LedgerHeight() (uint64, error) {
 	return args.Get(0).(uint64), args.Error(1)
 }
 
+// stateCheckpointsLedger is a coordinatorMock that returns the state checkpoints it is given
+type stateCheckpointsLedger struct {
+	*coordinatorMock
+	checkpoints map[uint64][]byte
+}
+
+func (l *stateCheckpointsLedger) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	commitment, ok := l.checkpoints[blockNumber]
+	if !ok {
+		return nil, nil
+	}
+	return &ledger.StateCheckpoint{BlockNumber: blockNumber, Commitment: commitment}, nil
+}
+
+func (l *stateCheckpointsLedger) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	var latest *ledger.StateCheckpoint
+	for blockNum := range l.checkpoints {
+		if latest == nil || blockNum > latest.BlockNumber {
+			latest, _ = l.GetStateCheckpoint(blockNum)
+		}
+	}
+	return latest, nil
+}
+
+func (mock *coordinatorMock) GetStateCheckpoint(blockNumber uint64) (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
+func (mock *coordinatorMock) GetLatestStateCheckpoint() (*ledger.StateCheckpoint, error) {
+	return nil, nil
+}
+
 func (mock *coordinatorMock) Close() {
 	mock.Called()
 }
@@ -25,17 +25,27 @@ type fileValidator func(fileName string, fileBytes []byte) error
 // AllowedCharsCollectionName captures the regex pattern for a valid collection name
 const AllowedCharsCollectionName = "[A-Za-z0-9_-]+"
 
-// Currently, the only metadata expected and allowed is for META-INF/statedb/couchdb/indexes.
+// PartitioningFileName is the name of the file that declares the partitioning of the couchdb database
+// of a chaincode or a collection
+const PartitioningFileName = "partitioning.json"
+
+// Currently, the only metadata expected and allowed is for META-INF/statedb/couchdb/indexes,
+// META-INF/statedb/couchdb/partitioning and META-INF/statedb/leveldb/indexes. The leveldb indexes
+// are defined in the same format as the couchdb indexes.
 var fileValidators = map[*regexp.Regexp]fileValidator{
-	regexp.MustCompile("^META-INF/statedb/couchdb/indexes/.*[.]json"):                                                couchdbIndexFileValidator,
-	regexp.MustCompile("^META-INF/statedb/couchdb/collections/" + AllowedCharsCollectionName + "/indexes/.*[.]json"): couchdbIndexFileValidator,
+	regexp.MustCompile("^META-INF/statedb/couchdb/indexes/.*[.]json"):                                                                         couchdbIndexFileValidator,
+	regexp.MustCompile("^META-INF/statedb/couchdb/collections/" + AllowedCharsCollectionName + "/indexes/.*[.]json"):                          couchdbIndexFileValidator,
+	regexp.MustCompile("^META-INF/statedb/couchdb/partitioning/" + PartitioningFileName + "$"):                                                couchdbPartitioningFileValidator,
+	regexp.MustCompile("^META-INF/statedb/couchdb/collections/" + AllowedCharsCollectionName + "/partitioning/" + PartitioningFileName + "$"): couchdbPartitioningFileValidator,
+	regexp.MustCompile("^META-INF/statedb/leveldb/indexes/.*[.]json"):                                                                         couchdbIndexFileValidator,
+	regexp.MustCompile("^META-INF/statedb/leveldb/collections/" + AllowedCharsCollectionName + "/indexes/.*[.]json"):                          couchdbIndexFileValidator,
 }
 
 var collectionNameValid = regexp.MustCompile("^" + AllowedCharsCollectionName)
 
 var fileNameValid = regexp.MustCompile("^.*[.]json")
 
-var validDatabases = []string{"couchdb"}
+var validDatabases = []string{"couchdb", "leveldb"}
 
 // UnhandledDirectoryError is returned for metadata files in unhandled directories
 type UnhandledDirectoryError struct {
@@ -55,6 +65,15 @@ func (e *InvalidIndexContentError) Error() string {
 	return e.err
 }
 
+// InvalidPartitioningContentError is returned for partitioning files with invalid content
+type InvalidPartitioningContentError struct {
+	err string
+}
+
+func (e *InvalidPartitioningContentError) Error() string {
+	return e.err
+}
+
 // ValidateMetadataFile checks that metadata files are valid
 // according to the validation rules of the file's directory
 func ValidateMetadataFile(filePathName string, fileBytes []byte) error {
@@ -92,6 +111,11 @@ func buildMetadataFileErrorMessage(filePathName string) string {
 	if !contains(validDatabases, directoryArray[2]) {
 		return fmt.Sprintf("database name [%s] is not supported, valid options: %s", directoryArray[2], validDatabases)
 	}
+	// verify the name of a partitioning file
+	if (len(directoryArray) == 4 && directoryArray[3] == "partitioning") ||
+		(len(directoryArray) == 6 && directoryArray[5] == "partitioning") {
+		return fmt.Sprintf("partitioning file name must be %s, found: %s", PartitioningFileName, filename)
+	}
 	// verify "indexes" is under the database name
 	if len(directoryArray) == 4 && directoryArray[3] != "indexes" {
 		return fmt.Sprintf("metadata file path does not have an indexes directory: %s", dir)
@@ -156,6 +180,25 @@ func couchdbIndexFileValidator(fileName string, fileBytes []byte) error {
 
 }
 
+// couchdbPartitioningFileValidator implements fileValidator
+func couchdbPartitioningFileValidator(fileName string, fileBytes []byte) error {
+
+	// if the content does not validate as JSON, return err to invalidate the file
+	boolIsJSON, partitioningDefinition := isJSON(fileBytes)
+	if !boolIsJSON {
+		return &InvalidPartitioningContentError{fmt.Sprintf("Partitioning metadata file [%s] is not a valid JSON", fileName)}
+	}
+
+	// validate the partitioning definition
+	err := validatePartitioningJSON(partitioningDefinition)
+	if err != nil {
+		return &InvalidPartitioningContentError{fmt.Sprintf("Partitioning metadata file [%s] is not a valid partitioning definition: %s", fileName, err)}
+	}
+
+	return nil
+
+}
+
 // isJSON tests a string to determine if it can be parsed as valid JSON
 func isJSON(s []byte) (bool, map[string]interface{}) {
 	var js map[string]interface{}
@@ -228,6 +271,42 @@ func validateIndexJSON(indexDefinition map[string]interface{}) error {
 
 }
 
+//validatePartitioningJSON validates that the partitioning definition consists of a positive
+//"compositeKeyPrefixLength", the number of leading components of a composite key, including
+//the object type, that determine the partition of the key
+func validatePartitioningJSON(partitioningDefinition map[string]interface{}) error {
+
+	prefixLengthIncluded := false
+
+	for jsonKey, jsonValue := range partitioningDefinition {
+
+		switch jsonKey {
+
+		case "compositeKeyPrefixLength":
+
+			prefixLength, ok := jsonValue.(float64)
+			if !ok || prefixLength < 1 || prefixLength != float64(int(prefixLength)) {
+				return fmt.Errorf("Invalid entry, \"compositeKeyPrefixLength\" must be a positive integer")
+			}
+
+			prefixLengthIncluded = true
+
+		default:
+
+			return fmt.Errorf("Invalid Entry.  Entry %s", jsonKey)
+
+		}
+
+	}
+
+	if !prefixLengthIncluded {
+		return fmt.Errorf("Partitioning definition must include a \"compositeKeyPrefixLength\" definition")
+	}
+
+	return nil
+
+}
+
 //processIndexMap processes an interface map and wraps field names or traverses
 //the next level of the json query
 func processIndexMap(jsonFragment map[string]interface{}) error {
@@ -27,6 +27,14 @@ func TestGoodIndexJSON(t *testing.T) {
 
 	err := ValidateMetadataFile(fileName, fileBytes)
 	assert.NoError(t, err, "Error validating a good index")
+
+	fileName = "META-INF/statedb/leveldb/indexes/myIndex.json"
+	err = ValidateMetadataFile(fileName, fileBytes)
+	assert.NoError(t, err, "Error validating a good leveldb index")
+
+	fileName = "META-INF/statedb/leveldb/collections/testcoll/indexes/myIndex.json"
+	err = ValidateMetadataFile(fileName, fileBytes)
+	assert.NoError(t, err, "Error validating a good leveldb collection index")
 }
 
 func TestBadIndexJSON(t *testing.T) {
@@ -48,6 +56,45 @@ func TestBadIndexJSON(t *testing.T) {
 	t.Log("SAMPLE ERROR STRING:", err.Error())
 }
 
+func TestGoodPartitioningJSON(t *testing.T) {
+	fileBytes := []byte(`{"compositeKeyPrefixLength":2}`)
+
+	err := ValidateMetadataFile("META-INF/statedb/couchdb/partitioning/partitioning.json", fileBytes)
+	assert.NoError(t, err, "Error validating a good partitioning")
+
+	err = ValidateMetadataFile("META-INF/statedb/couchdb/collections/testcoll/partitioning/partitioning.json", fileBytes)
+	assert.NoError(t, err, "Error validating a good collection partitioning")
+}
+
+func TestBadPartitioningJSON(t *testing.T) {
+	fileName := "META-INF/statedb/couchdb/partitioning/partitioning.json"
+	for _, fileBytes := range []string{
+		`invalid json`,
+		`{}`,
+		`{"compositeKeyPrefixLength":0}`,
+		`{"compositeKeyPrefixLength":1.5}`,
+		`{"compositeKeyPrefixLength":"2"}`,
+		`{"compositeKeyPrefixLength":2,"partitions":4}`,
+	} {
+		err := ValidateMetadataFile(fileName, []byte(fileBytes))
+		_, ok := err.(*InvalidPartitioningContentError)
+		assert.True(t, ok, "Should have received an InvalidPartitioningContentError for %s", fileBytes)
+	}
+
+	// the partitioning is supported for couchdb only and must be named partitioning.json
+	for _, fileName := range []string{
+		"META-INF/statedb/leveldb/partitioning/partitioning.json",
+		"META-INF/statedb/couchdb/partitioning/myPartitioning.json",
+		"META-INF/statedb/couchdb/collections/testcoll/partitioning/myPartitioning.json",
+	} {
+		err := ValidateMetadataFile(fileName, []byte(`{"compositeKeyPrefixLength":2}`))
+		_, ok := err.(*UnhandledDirectoryError)
+		assert.True(t, ok, "Should have received an UnhandledDirectoryError for %s", fileName)
+	}
+	err := ValidateMetadataFile("META-INF/statedb/couchdb/partitioning/myPartitioning.json", []byte(`{"compositeKeyPrefixLength":2}`))
+	assert.EqualError(t, err, "partitioning file name must be partitioning.json, found: myPartitioning.json")
+}
+
 func TestIndexWrongLocation(t *testing.T) {
 	testDir := filepath.Join(packageTestDir, "IndexWrongLocation")
 	cleanupDir(testDir)
@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package encoder
 
 import (
+	"io/ioutil"
+
 	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/genesis"
@@ -191,6 +194,13 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 	addValue(ordererGroup, channelconfig.BatchTimeoutValue(conf.BatchTimeout.String()), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.ChannelRestrictionsValue(conf.MaxChannels), channelconfig.AdminsPolicyKey)
 
+	if conf.BlockExtensionLimits != nil {
+		addValue(ordererGroup, channelconfig.BlockExtensionLimitsValue(
+			conf.BlockExtensionLimits.MaxBytes,
+			conf.BlockExtensionLimits.MaxEntries,
+		), channelconfig.AdminsPolicyKey)
+	}
+
 	if len(conf.Capabilities) > 0 {
 		addValue(ordererGroup, channelconfig.CapabilitiesValue(conf.Capabilities), channelconfig.AdminsPolicyKey)
 	}
@@ -583,7 +593,8 @@ func HasSkippedForeignOrgs(conf *genesisconfig.Profile) error {
 
 // Bootstrapper is a wrapper around NewChannelConfigGroup which can produce genesis blocks
 type Bootstrapper struct {
-	channelGroup *cb.ConfigGroup
+	channelGroup    *cb.ConfigGroup
+	extensionConfig genesis.ExtensionConfig
 }
 
 // NewBootstrapper creates a bootstrapper but returns an error instead of panic-ing
@@ -597,11 +608,40 @@ func NewBootstrapper(config *genesisconfig.Profile) (*Bootstrapper, error) {
 		return nil, errors.WithMessage(err, "could not create channel group")
 	}
 
+	extensionConfig, err := newGenesisExtensionConfig(config.Genesis)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not create genesis block extension")
+	}
+
 	return &Bootstrapper{
-		channelGroup: channelGroup,
+		channelGroup:    channelGroup,
+		extensionConfig: extensionConfig,
 	}, nil
 }
 
+// newGenesisExtensionConfig reads the payloads of the extension entries of the genesis block.
+func newGenesisExtensionConfig(conf *genesisconfig.Genesis) (genesis.ExtensionConfig, error) {
+	if conf == nil {
+		return genesis.ExtensionConfig{}, nil
+	}
+
+	extensionConfig := genesis.ExtensionConfig{
+		OmitConfigEnvelope: conf.OmitConfigEnvelope,
+	}
+	for _, entry := range conf.ExtensionEntries {
+		payload, err := ioutil.ReadFile(entry.File)
+		if err != nil {
+			return genesis.ExtensionConfig{}, errors.Wrapf(err, "could not read payload of extension entry of type %s", entry.TypeURL)
+		}
+		extensionConfig.Entries = append(extensionConfig.Entries, &blockextension.Entry{
+			TypeUrl: entry.TypeURL,
+			Version: entry.Version,
+			Payload: payload,
+		})
+	}
+	return extensionConfig, nil
+}
+
 // New creates a new Bootstrapper for generating genesis blocks
 func New(config *genesisconfig.Profile) *Bootstrapper {
 	bs, err := NewBootstrapper(config)
@@ -614,10 +654,10 @@ func New(config *genesisconfig.Profile) *Bootstrapper {
 // GenesisBlock produces a genesis block for the default test channel id
 func (bs *Bootstrapper) GenesisBlock() *cb.Block {
 	// TODO(mjs): remove
-	return genesis.NewFactoryImpl(bs.channelGroup).Block("testchannelid")
+	return genesis.NewFactoryImplWithExtension(bs.channelGroup, bs.extensionConfig).Block("testchannelid")
 }
 
 // GenesisBlockForChannel produces a genesis block for a given channel ID
 func (bs *Bootstrapper) GenesisBlockForChannel(channelID string) *cb.Block {
-	return genesis.NewFactoryImpl(bs.channelGroup).Block(channelID)
+	return genesis.NewFactoryImplWithExtension(bs.channelGroup, bs.extensionConfig).Block(channelID)
 }
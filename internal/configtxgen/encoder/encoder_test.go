@@ -8,6 +8,9 @@ package encoder_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,6 +19,7 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder/fakes"
@@ -394,6 +398,27 @@ var _ = Describe("Encoder", func() {
 			})
 		})
 
+		Context("when block extension limits are set", func() {
+			BeforeEach(func() {
+				conf.BlockExtensionLimits = &genesisconfig.BlockExtensionLimits{
+					MaxBytes:   1024,
+					MaxEntries: 4,
+				}
+			})
+
+			It("adds the block extension limits key", func() {
+				cg, err := encoder.NewOrdererGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(cg.Values)).To(Equal(6))
+				Expect(cg.Values["BlockExtensionLimits"]).NotTo(BeNil())
+				limits := &blockextension.ExtensionLimits{}
+				err = proto.Unmarshal(cg.Values["BlockExtensionLimits"].Value, limits)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(limits.MaxBytes).To(Equal(uint32(1024)))
+				Expect(limits.MaxEntries).To(Equal(uint32(4)))
+			})
+		})
+
 		Context("when the consensus type is Kafka", func() {
 			BeforeEach(func() {
 				conf.OrdererType = "kafka"
@@ -1493,6 +1518,58 @@ var _ = Describe("Encoder", func() {
 					Expect(err).To(MatchError("all org definitions must be local during bootstrapping: organization 'MyOrg' is marked to be skipped as foreign"))
 				})
 			})
+
+			Context("when the genesis config has extension entries", func() {
+				var (
+					tempDir string
+				)
+
+				BeforeEach(func() {
+					var err error
+					tempDir, err = ioutil.TempDir("", "encoder")
+					Expect(err).NotTo(HaveOccurred())
+					err = ioutil.WriteFile(filepath.Join(tempDir, "payload.bin"), []byte("payload"), 0600)
+					Expect(err).NotTo(HaveOccurred())
+
+					conf.Genesis = &genesisconfig.Genesis{
+						OmitConfigEnvelope: true,
+						ExtensionEntries: []*genesisconfig.ExtensionEntry{
+							{
+								TypeURL: "example.com/Payload",
+								Version: 2,
+								File:    filepath.Join(tempDir, "payload.bin"),
+							},
+						},
+					}
+				})
+
+				AfterEach(func() {
+					os.RemoveAll(tempDir)
+				})
+
+				It("adds the entries to the extension of the genesis block", func() {
+					bs, err := encoder.NewBootstrapper(conf)
+					Expect(err).NotTo(HaveOccurred())
+					block := bs.GenesisBlockForChannel("channel-id")
+					Expect(block.Extension.ExtensionData).To(HaveLen(1))
+					entry, err := protoutil.UnmarshalBlockExtensionEntry(block.Extension.ExtensionData[0])
+					Expect(err).NotTo(HaveOccurred())
+					Expect(entry.TypeUrl).To(Equal("example.com/Payload"))
+					Expect(entry.Version).To(Equal(uint32(2)))
+					Expect(entry.Payload).To(Equal([]byte("payload")))
+				})
+
+				Context("when the payload file cannot be read", func() {
+					BeforeEach(func() {
+						conf.Genesis.ExtensionEntries[0].File = filepath.Join(tempDir, "missing.bin")
+					})
+
+					It("wraps and returns the error", func() {
+						_, err := encoder.NewBootstrapper(conf)
+						Expect(err).To(MatchError(ContainSubstring("could not create genesis block extension: could not read payload of extension entry of type example.com/Payload")))
+					})
+				})
+			})
 		})
 
 		Describe("New", func() {
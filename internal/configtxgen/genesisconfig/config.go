@@ -87,6 +87,24 @@ type Profile struct {
 	Consortiums  map[string]*Consortium `yaml:"Consortiums"`
 	Capabilities map[string]bool        `yaml:"Capabilities"`
 	Policies     map[string]*Policy     `yaml:"Policies"`
+	Genesis      *Genesis               `yaml:"Genesis"`
+}
+
+// Genesis contains configuration affecting the extension of the genesis
+// block generated for a profile.
+type Genesis struct {
+	// OmitConfigEnvelope prevents the config envelope of the genesis block
+	// from being duplicated into the block extension.
+	OmitConfigEnvelope bool              `yaml:"OmitConfigEnvelope"`
+	ExtensionEntries   []*ExtensionEntry `yaml:"ExtensionEntries"`
+}
+
+// ExtensionEntry encodes an entry of the extension of the genesis block,
+// whose payload is read from a file.
+type ExtensionEntry struct {
+	TypeURL string `yaml:"TypeURL"`
+	Version uint32 `yaml:"Version"`
+	File    string `yaml:"File"`
 }
 
 // Policy encodes a channel config policy
@@ -143,16 +161,17 @@ type AnchorPeer struct {
 
 // Orderer contains configuration associated to a channel.
 type Orderer struct {
-	OrdererType   string                   `yaml:"OrdererType"`
-	Addresses     []string                 `yaml:"Addresses"`
-	BatchTimeout  time.Duration            `yaml:"BatchTimeout"`
-	BatchSize     BatchSize                `yaml:"BatchSize"`
-	Kafka         Kafka                    `yaml:"Kafka"`
-	EtcdRaft      *etcdraft.ConfigMetadata `yaml:"EtcdRaft"`
-	Organizations []*Organization          `yaml:"Organizations"`
-	MaxChannels   uint64                   `yaml:"MaxChannels"`
-	Capabilities  map[string]bool          `yaml:"Capabilities"`
-	Policies      map[string]*Policy       `yaml:"Policies"`
+	OrdererType          string                   `yaml:"OrdererType"`
+	Addresses            []string                 `yaml:"Addresses"`
+	BatchTimeout         time.Duration            `yaml:"BatchTimeout"`
+	BatchSize            BatchSize                `yaml:"BatchSize"`
+	BlockExtensionLimits *BlockExtensionLimits    `yaml:"BlockExtensionLimits"`
+	Kafka                Kafka                    `yaml:"Kafka"`
+	EtcdRaft             *etcdraft.ConfigMetadata `yaml:"EtcdRaft"`
+	Organizations        []*Organization          `yaml:"Organizations"`
+	MaxChannels          uint64                   `yaml:"MaxChannels"`
+	Capabilities         map[string]bool          `yaml:"Capabilities"`
+	Policies             map[string]*Policy       `yaml:"Policies"`
 }
 
 // BatchSize contains configuration affecting the size of batches.
@@ -162,6 +181,13 @@ type BatchSize struct {
 	PreferredMaxBytes uint32 `yaml:"PreferredMaxBytes"`
 }
 
+// BlockExtensionLimits contains configuration bounding the extension of the blocks.
+// A limit of zero means that the extension is not bounded in that respect.
+type BlockExtensionLimits struct {
+	MaxBytes   uint32 `yaml:"MaxBytes"`
+	MaxEntries uint32 `yaml:"MaxEntries"`
+}
+
 // Kafka contains configuration for the Kafka-based orderer.
 type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
@@ -293,6 +319,22 @@ func (p *Profile) completeInitialization(configDir string) {
 		// Some profiles will not define orderer parameters
 		p.Orderer.completeInitialization(configDir)
 	}
+
+	if p.Genesis != nil {
+		p.Genesis.completeInitialization(configDir)
+	}
+}
+
+func (g *Genesis) completeInitialization(configDir string) {
+	for _, entry := range g.ExtensionEntries {
+		if entry.TypeURL == "" {
+			logger.Panicf("Genesis extension entry did not specify a type URL")
+		}
+		if entry.File == "" {
+			logger.Panicf("Genesis extension entry of type %s did not specify a file", entry.TypeURL)
+		}
+		cf.TranslatePathInPlace(configDir, &entry.File)
+	}
 }
 
 func (org *Organization) completeInitialization(configDir string) {
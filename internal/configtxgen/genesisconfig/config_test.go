@@ -270,6 +270,42 @@ func TestConsensusSpecificInit(t *testing.T) {
 	})
 }
 
+func TestGenesisInit(t *testing.T) {
+	t.Run("extension entry", func(t *testing.T) {
+		profile := &Profile{
+			Genesis: &Genesis{
+				ExtensionEntries: []*ExtensionEntry{
+					{TypeURL: "example.com/Payload", File: "payload.bin"},
+				},
+			},
+		}
+		profile.completeInitialization("/config")
+		assert.Equal(t, "/config/payload.bin", profile.Genesis.ExtensionEntries[0].File)
+	})
+
+	t.Run("missing type URL", func(t *testing.T) {
+		profile := &Profile{
+			Genesis: &Genesis{
+				ExtensionEntries: []*ExtensionEntry{{File: "payload.bin"}},
+			},
+		}
+		assert.Panics(t, func() {
+			profile.completeInitialization("/config")
+		})
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		profile := &Profile{
+			Genesis: &Genesis{
+				ExtensionEntries: []*ExtensionEntry{{TypeURL: "example.com/Payload"}},
+			},
+		}
+		assert.Panics(t, func() {
+			profile.completeInitialization("/config")
+		})
+	})
+}
+
 func TestLoadConfigCache(t *testing.T) {
 	cleanup := configtest.SetDevFabricConfigPath(t)
 	defer cleanup()
@@ -16,6 +16,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/mux"
 	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric/common/blockextension"
 )
 
 func getMsgType(r *http.Request) (proto.Message, error) {
@@ -26,7 +27,7 @@ func getMsgType(r *http.Request) (proto.Message, error) {
 	if msgType == nil {
 		return nil, fmt.Errorf("message name not found")
 	}
-	return reflect.New(msgType.Elem()).Interface().(proto.Message), nil
+	return blockextension.ProtolatorMessage(reflect.New(msgType.Elem()).Interface().(proto.Message)), nil
 }
 
 func Decode(w http.ResponseWriter, r *http.Request) {
@@ -80,7 +80,12 @@ func executeJoin(cf *ChannelCmdFactory) (err error) {
 	if err != nil {
 		return err
 	}
+	return submitJoinProposal(cf, spec)
+}
 
+// submitJoinProposal sends a proposal to cscc with the given spec and
+// checks the response. It is shared by join and joinbysnapshot.
+func submitJoinProposal(cf *ChannelCmdFactory, spec *pb.ChaincodeSpec) (err error) {
 	// Build the ChaincodeInvocationSpec message
 	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
 
@@ -14,6 +14,8 @@ import (
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -133,6 +135,24 @@ func (pc *PeerClient) PeerDeliver() (pb.DeliverClient, error) {
 	return pb.NewDeliverClient(conn), nil
 }
 
+// Snapshot returns a client for the Snapshot service
+func (pc *PeerClient) Snapshot() (snapshotgrpc.SnapshotClient, error) {
+	conn, err := pc.CommonClient.NewConnection(pc.Address, comm.ServerNameOverride(pc.sn))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "snapshot client failed to connect to %s", pc.Address)
+	}
+	return snapshotgrpc.NewSnapshotClient(conn), nil
+}
+
+// Reconciliation returns a client for the Reconciliation service
+func (pc *PeerClient) Reconciliation() (reconciliationgrpc.ReconciliationClient, error) {
+	conn, err := pc.CommonClient.NewConnection(pc.Address, comm.ServerNameOverride(pc.sn))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "reconciliation client failed to connect to %s", pc.Address)
+	}
+	return reconciliationgrpc.NewReconciliationClient(conn), nil
+}
+
 // Certificate returns the TLS client certificate (if available)
 func (pc *PeerClient) Certificate() tls.Certificate {
 	return pc.CommonClient.Certificate()
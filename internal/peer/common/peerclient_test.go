@@ -112,6 +112,14 @@ func TestPeerClient(t *testing.T) {
 	dClient, err = common.GetDeliverClient("", "")
 	assert.NoError(t, err)
 	assert.NotNil(t, dClient)
+
+	sClient, err := pClient1.Snapshot()
+	assert.NoError(t, err)
+	assert.NotNil(t, sClient)
+
+	rClient, err := pClient1.Reconciliation()
+	assert.NoError(t, err)
+	assert.NotNil(t, rClient)
 }
 
 func TestPeerClientTimeout(t *testing.T) {
@@ -12,7 +12,9 @@ import (
 	"time"
 
 	pcommon "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
@@ -46,25 +48,36 @@ type MSPMessageCryptoService struct {
 	localSigner                identity.SignerSerializer
 	deserializer               mgmt.DeserializersManager
 	hasher                     Hasher
+	extensionLimitsGetter      ExtensionLimitsGetter
 }
 
+// ExtensionLimitsGetter returns the limits that the config of a channel in effect for the
+// block with the given number sets on the extension of the block, given the number of the
+// last config block recorded in the block. It returns nil if the extension of the block is
+// not bounded, or if the config in effect for the block is not known.
+type ExtensionLimitsGetter func(channelID string, blockNum, lastConfigBlockNum uint64) *blockextension.ExtensionLimits
+
 // NewMCS creates a new instance of MSPMessageCryptoService
 // that implements MessageCryptoService.
 // The method takes in input:
 // 1. a policies.ChannelPolicyManagerGetter that gives access to the policy manager of a given channel via the Manager method.
 // 2. an instance of identity.SignerSerializer
 // 3. an identity deserializer manager
+// 4. a hasher
+// 5. an ExtensionLimitsGetter that gives the limits on the block extension of a given channel, or nil
 func NewMCS(
 	channelPolicyManagerGetter policies.ChannelPolicyManagerGetter,
 	localSigner identity.SignerSerializer,
 	deserializer mgmt.DeserializersManager,
 	hasher Hasher,
+	extensionLimitsGetter ExtensionLimitsGetter,
 ) *MSPMessageCryptoService {
 	return &MSPMessageCryptoService{
 		channelPolicyManagerGetter: channelPolicyManagerGetter,
 		localSigner:                localSigner,
 		deserializer:               deserializer,
 		hasher:                     hasher,
+		extensionLimitsGetter:      extensionLimitsGetter,
 	}
 }
 
@@ -157,6 +170,15 @@ func (s *MSPMessageCryptoService) VerifyBlock(chainID common.ChannelID, seqNum u
 		return fmt.Errorf("Header.DataHash is different from Hash(block.Data) for block with id [%d] on channel [%s]", block.Header.Number, chainID)
 	}
 
+	// - Verify that the block extension is consistent with the extension hash committed by the orderer, if any.
+	// The extension hash is part of the data signed by the orderer, hence a block whose extension or
+	// extension hash has been altered fails either this check or the signature verification below
+	extensionHash, err := protoutil.VerifyBlockExtensionHash(block)
+	if err != nil {
+		// The error is wrapped so that callers can tell extension mismatches apart from other failures
+		return errors.WithMessagef(err, "Invalid block extension for block with id [%d] on channel [%s]", block.Header.Number, chainID)
+	}
+
 	// - Get Policy for block validation
 
 	// Get the policy manager for channelID
@@ -182,14 +204,41 @@ func (s *MSPMessageCryptoService) VerifyBlock(chainID common.ChannelID, seqNum u
 			signatureSet,
 			&protoutil.SignedData{
 				Identity:  shdr.Creator,
-				Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)),
+				Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header), extensionHash),
 				Signature: metadataSignature.Signature,
 			},
 		)
 	}
 
 	// - Evaluate policy
-	return policy.EvaluateSignedData(signatureSet)
+	if err := policy.EvaluateSignedData(signatureSet); err != nil {
+		return err
+	}
+
+	// - Verify that the block extension does not exceed the limits set by the channel config in effect
+	// for the block. The last config index is taken from the signed metadata, which is now trusted
+	return s.verifyBlockExtensionLimits(channelID, block, metadata)
+}
+
+// verifyBlockExtensionLimits checks the block extension against the limits of the channel config
+// in effect for the block. The check is skipped if the signed metadata does not record the last
+// config index, which is the case for the blocks created by the orderers that predate the limits.
+func (s *MSPMessageCryptoService) verifyBlockExtensionLimits(channelID string, block *pcommon.Block, metadata *pcommon.Metadata) error {
+	if s.extensionLimitsGetter == nil || len(metadata.Value) == 0 {
+		return nil
+	}
+	obm := &pcommon.OrdererBlockMetadata{}
+	if err := proto.Unmarshal(metadata.Value, obm); err != nil {
+		return fmt.Errorf("Failed unmarshalling orderer block metadata for block with id [%d] on channel [%s]: [%s]", block.Header.Number, channelID, err)
+	}
+	if obm.LastConfig == nil {
+		return nil
+	}
+	limits := s.extensionLimitsGetter(channelID, block.Header.Number, obm.LastConfig.Index)
+	if err := protoutil.VerifyBlockExtensionLimits(block, limits); err != nil {
+		return errors.WithMessagef(err, "Invalid block extension for block with id [%d] on channel [%s]", block.Header.Number, channelID)
+	}
+	return nil
 }
 
 // Sign signs msg with this peer's signing key and outputs
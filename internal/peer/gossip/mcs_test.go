@@ -19,6 +19,7 @@ import (
 	pmsp "github.com/hyperledger/fabric-protos-go/msp"
 	protospeer "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/gossip/api"
@@ -56,6 +57,7 @@ func TestPKIidOfCert(t *testing.T) {
 		signer,
 		deserializersManager,
 		cryptoProvider,
+		nil,
 	)
 
 	peerIdentity := []byte("Alice")
@@ -89,7 +91,7 @@ func TestPKIidOfNil(t *testing.T) {
 	signer := &mocks.SignerSerializer{}
 	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
 	assert.NoError(t, err)
-	msgCryptoService := NewMCS(&mocks.ChannelPolicyManagerGetter{}, signer, mgmt.NewDeserializersManager(cryptoProvider), cryptoProvider)
+	msgCryptoService := NewMCS(&mocks.ChannelPolicyManagerGetter{}, signer, mgmt.NewDeserializersManager(cryptoProvider), cryptoProvider, nil)
 
 	pkid := msgCryptoService.GetPKIidOfCert(nil)
 	// Check pkid is not nil
@@ -112,6 +114,7 @@ func TestValidateIdentity(t *testing.T) {
 		signer,
 		deserializersManager,
 		cryptoProvider,
+		nil,
 	)
 
 	err = msgCryptoService.ValidateIdentity([]byte("Alice"))
@@ -149,6 +152,7 @@ func TestSign(t *testing.T) {
 		signer,
 		mgmt.NewDeserializersManager(cryptoProvider),
 		cryptoProvider,
+		nil,
 	)
 
 	msg := []byte("Hello World!!!")
@@ -185,6 +189,7 @@ func TestVerify(t *testing.T) {
 			},
 		},
 		cryptoProvider,
+		nil,
 	)
 
 	msg := []byte("msg1")
@@ -243,6 +248,7 @@ func TestVerifyBlock(t *testing.T) {
 			},
 		},
 		cryptoProvider,
+		nil,
 	)
 
 	// - Prepare testing valid block, Alice signs it.
@@ -275,6 +281,101 @@ func TestVerifyBlock(t *testing.T) {
 	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), 42, &common.Block{}))
 }
 
+func TestVerifyBlockExtension(t *testing.T) {
+	aliceSigner := &mocks.SignerSerializer{}
+	aliceSigner.SerializeReturns([]byte("Alice"), nil)
+	policyManagerGetter := &mocks.ChannelPolicyManagerGetterWithManager{
+		Managers: map[string]policies.Manager{
+			"C": &mocks.ChannelPolicyManager{
+				Policy: &mocks.Policy{Deserializer: &mocks.IdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1"), Mock: mock.Mock{}}},
+			},
+		},
+	}
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	msgCryptoService := NewMCS(
+		policyManagerGetter,
+		aliceSigner,
+		&mocks.DeserializersManager{
+			LocalDeserializer: &mocks.IdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1"), Mock: mock.Mock{}},
+		},
+		cryptoProvider,
+		func(channelID string, blockNum, lastConfigBlockNum uint64) *blockextension.ExtensionLimits {
+			assert.Equal(t, "C", channelID)
+			assert.Equal(t, uint64(42), blockNum)
+			if lastConfigBlockNum != 7 {
+				return nil
+			}
+			return &blockextension.ExtensionLimits{MaxBytes: 16, MaxEntries: 1}
+		},
+	)
+
+	// - Alice signs the metadata, header and extension hash of a block, which records block 7 as the last config block
+	sign := func(block *common.Block, lastConfigBlockNum uint64) {
+		md := protoutil.GetMetadataFromBlockOrPanic(block, common.BlockMetadataIndex_SIGNATURES)
+		md.Value = protoutil.MarshalOrPanic(&common.OrdererBlockMetadata{LastConfig: &common.LastConfig{Index: lastConfigBlockNum}})
+		msg := util.ConcatenateBytes(md.Value, md.Signatures[0].SignatureHeader, protoutil.BlockHeaderBytes(block.Header), protoutil.BlockExtensionHash(block.Extension))
+		aliceSigner.SignReturns(msg, nil)
+		md.Signatures[0].Signature, err = aliceSigner.Sign(msg)
+		assert.NoError(t, err)
+		block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(md)
+		policyManagerGetter.Managers["C"].(*mocks.ChannelPolicyManager).Policy.(*mocks.Policy).Deserializer.(*mocks.IdentityDeserializer).Msg = msg
+	}
+
+	// - Prepare a block with an extension whose hash is committed to by Alice's signature
+	blockRaw, _ := mockBlock(t, "C", 42, aliceSigner, nil)
+	blockRaw.Extension.ExtensionData = [][]byte{[]byte("extension")}
+	protoutil.SetExtensionHashInBlock(blockRaw)
+	sign(blockRaw, 7)
+
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), 42, blockRaw))
+
+	// - Tampering with the extension is detected
+	tamperedBlock := proto.Clone(blockRaw).(*common.Block)
+	tamperedBlock.Extension.ExtensionData = [][]byte{[]byte("forged extension")}
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, tamperedBlock)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid block extension for block with id [42] on channel [C]")
+	assert.True(t, protoutil.IsBlockExtensionMismatch(err))
+
+	// - Replacing the extension hash along with the extension fails the signature verification
+	protoutil.SetExtensionHashInBlock(tamperedBlock)
+	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), 42, tamperedBlock))
+
+	// - Stripping the extension hash fails the signature verification
+	strippedBlock := proto.Clone(blockRaw).(*common.Block)
+	strippedBlock.Metadata.Metadata[protoutil.BlockMetadataIndexExtensionHash] = nil
+	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), 42, strippedBlock))
+
+	// - An extension exceeding the limits of the config in effect for the block is rejected
+	oversizedBlock := proto.Clone(blockRaw).(*common.Block)
+	oversizedBlock.Extension.ExtensionData = [][]byte{[]byte("entry1"), []byte("entry2")}
+	protoutil.SetExtensionHashInBlock(oversizedBlock)
+	sign(oversizedBlock, 7)
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, oversizedBlock)
+	assert.EqualError(t, err, "Invalid block extension for block with id [42] on channel [C]: block extension carries 2 entries, exceeding the maximum of 1 entries")
+	oversizedBlock.Extension.ExtensionData = [][]byte{[]byte("an extension entry over 16 bytes")}
+	protoutil.SetExtensionHashInBlock(oversizedBlock)
+	sign(oversizedBlock, 7)
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, oversizedBlock)
+	assert.EqualError(t, err, "Invalid block extension for block with id [42] on channel [C]: block extension is 32 bytes, exceeding the maximum of 16 bytes")
+
+	// - The extension is not checked if the config in effect for the block is not known
+	sign(oversizedBlock, 12)
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), 42, oversizedBlock))
+
+	// - The extension is not checked if the signed metadata does not record the last config block
+	sign(oversizedBlock, 7)
+	md := protoutil.GetMetadataFromBlockOrPanic(oversizedBlock, common.BlockMetadataIndex_SIGNATURES)
+	md.Value = nil
+	msg := util.ConcatenateBytes(md.Value, md.Signatures[0].SignatureHeader, protoutil.BlockHeaderBytes(oversizedBlock.Header), protoutil.BlockExtensionHash(oversizedBlock.Extension))
+	md.Signatures[0].Signature = msg
+	oversizedBlock.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(md)
+	policyManagerGetter.Managers["C"].(*mocks.ChannelPolicyManager).Policy.(*mocks.Policy).Deserializer.(*mocks.IdentityDeserializer).Msg = msg
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), 42, oversizedBlock))
+}
+
 func mockBlock(t *testing.T, channel string, seqNum uint64, localSigner *mocks.SignerSerializer, dataHash []byte) (*common.Block, []byte) {
 	block := protoutil.NewBlock(seqNum, nil)
 
@@ -360,6 +461,7 @@ func TestExpiration(t *testing.T) {
 		&mocks.SignerSerializer{},
 		deserializersManager,
 		cryptoProvider,
+		nil,
 	)
 
 	// Green path I check the expiration date is as expected
@@ -12,23 +12,12 @@ import (
 
 	coreconfig "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/spf13/viper"
 )
 
 func ledgerConfig() *ledger.Config {
 	// set defaults
-	warmAfterNBlocks := 1
-	if viper.IsSet("ledger.state.couchDBConfig.warmIndexesAfterNBlocks") {
-		warmAfterNBlocks = viper.GetInt("ledger.state.couchDBConfig.warmIndexesAfterNBlocks")
-	}
-	internalQueryLimit := 1000
-	if viper.IsSet("ledger.state.couchDBConfig.internalQueryLimit") {
-		internalQueryLimit = viper.GetInt("ledger.state.couchDBConfig.internalQueryLimit")
-	}
-	maxBatchUpdateSize := 500
-	if viper.IsSet("ledger.state.couchDBConfig.maxBatchUpdateSize") {
-		maxBatchUpdateSize = viper.GetInt("ledger.state.couchDBConfig.maxBatchUpdateSize")
-	}
 	collElgProcMaxDbBatchSize := 5000
 	if viper.IsSet("ledger.pvtdataStore.collElgProcMaxDbBatchSize") {
 		collElgProcMaxDbBatchSize = viper.GetInt("ledger.pvtdataStore.collElgProcMaxDbBatchSize")
@@ -69,23 +58,97 @@ func ledgerConfig() *ledger.Config {
 		SnapshotsConfig: &ledger.SnapshotsConfig{
 			RootDir: snapshotsRootDir,
 		},
+		ExtensionIndexConfig: &ledger.ExtensionIndexConfig{
+			TypeURLs:  viper.GetStringSlice("ledger.blockchain.extensionIndex.typeURLs"),
+			KeyFields: extensionIndexKeyFields(),
+		},
+		StateCheckpointsConfig: &ledger.StateCheckpointsConfig{
+			Interval: uint64(viper.GetInt("ledger.state.checkpoints.interval")),
+		},
+		BlockRetentionConfig: &ledger.BlockRetentionConfig{
+			RetainBlocks:    uint64(viper.GetInt("ledger.blockchain.retention.retainBlocks")),
+			PruneAtSnapshot: viper.GetBool("ledger.blockchain.retention.pruneAtSnapshot"),
+			ArchiveDir:      viper.GetString("ledger.blockchain.retention.archiveDir"),
+			FetchArchived:   viper.GetBool("ledger.blockchain.retention.fetchArchived"),
+		},
+		BlockCompressionConfig: &ledger.BlockCompressionConfig{
+			Codec: viper.GetString("ledger.blockchain.compression.codec"),
+		},
+		CommitPipelineConfig: &ledger.CommitPipelineConfig{
+			Enabled: viper.GetBool("ledger.state.commitPipeline.enabled"),
+		},
 	}
 
 	if conf.StateDBConfig.StateDatabase == "CouchDB" {
-		conf.StateDBConfig.CouchDB = &ledger.CouchDBConfig{
-			Address:                 viper.GetString("ledger.state.couchDBConfig.couchDBAddress"),
-			Username:                viper.GetString("ledger.state.couchDBConfig.username"),
-			Password:                viper.GetString("ledger.state.couchDBConfig.password"),
-			MaxRetries:              viper.GetInt("ledger.state.couchDBConfig.maxRetries"),
-			MaxRetriesOnStartup:     viper.GetInt("ledger.state.couchDBConfig.maxRetriesOnStartup"),
-			RequestTimeout:          viper.GetDuration("ledger.state.couchDBConfig.requestTimeout"),
-			InternalQueryLimit:      internalQueryLimit,
-			MaxBatchUpdateSize:      maxBatchUpdateSize,
-			WarmIndexesAfterNBlocks: warmAfterNBlocks,
-			CreateGlobalChangesDB:   viper.GetBool("ledger.state.couchDBConfig.createGlobalChangesDB"),
-			RedoLogPath:             filepath.Join(rootFSPath, "couchdbRedoLogs"),
-			UserCacheSizeMBs:        viper.GetInt("ledger.state.couchDBConfig.cacheSize"),
-		}
+		conf.StateDBConfig.CouchDB = couchDBConfig(rootFSPath)
+	}
+	if sdb := conf.StateDBConfig.StateDatabase; sdb != "goleveldb" && sdb != "CouchDB" {
+		conf.StateDBConfig.PluginConfig = viper.GetStringMap("ledger.state.pluginConfig")
 	}
 	return conf
 }
+
+func couchDBConfig(rootFSPath string) *ledger.CouchDBConfig {
+	// set defaults
+	warmAfterNBlocks := 1
+	if viper.IsSet("ledger.state.couchDBConfig.warmIndexesAfterNBlocks") {
+		warmAfterNBlocks = viper.GetInt("ledger.state.couchDBConfig.warmIndexesAfterNBlocks")
+	}
+	internalQueryLimit := 1000
+	if viper.IsSet("ledger.state.couchDBConfig.internalQueryLimit") {
+		internalQueryLimit = viper.GetInt("ledger.state.couchDBConfig.internalQueryLimit")
+	}
+	maxBatchUpdateSize := 500
+	if viper.IsSet("ledger.state.couchDBConfig.maxBatchUpdateSize") {
+		maxBatchUpdateSize = viper.GetInt("ledger.state.couchDBConfig.maxBatchUpdateSize")
+	}
+
+	return &ledger.CouchDBConfig{
+		Address:                 viper.GetString("ledger.state.couchDBConfig.couchDBAddress"),
+		Username:                viper.GetString("ledger.state.couchDBConfig.username"),
+		Password:                viper.GetString("ledger.state.couchDBConfig.password"),
+		MaxRetries:              viper.GetInt("ledger.state.couchDBConfig.maxRetries"),
+		MaxRetriesOnStartup:     viper.GetInt("ledger.state.couchDBConfig.maxRetriesOnStartup"),
+		RequestTimeout:          viper.GetDuration("ledger.state.couchDBConfig.requestTimeout"),
+		InternalQueryLimit:      internalQueryLimit,
+		MaxBatchUpdateSize:      maxBatchUpdateSize,
+		WarmIndexesAfterNBlocks: warmAfterNBlocks,
+		CreateGlobalChangesDB:   viper.GetBool("ledger.state.couchDBConfig.createGlobalChangesDB"),
+		RedoLogPath:             filepath.Join(rootFSPath, "couchdbRedoLogs"),
+		UserCacheSizeMBs:        viper.GetInt("ledger.state.couchDBConfig.cacheSize"),
+		QueryPlanCheck:          viper.GetString("ledger.state.couchDBConfig.queryPlanCheck"),
+	}
+}
+
+func transientStorePath() string {
+	return filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore")
+}
+
+func transientStoreConfig() *transientstore.Config {
+	return &transientstore.Config{
+		MaxAge:               viper.GetDuration("peer.gossip.pvtData.transientstoreMaxAge"),
+		CollectionQuotaBytes: uint64(viper.GetSizeInBytes("peer.gossip.pvtData.transientstoreCollectionQuota")),
+		ClientQuotaBytes:     uint64(viper.GetSizeInBytes("peer.gossip.pvtData.transientstoreClientQuota")),
+	}
+}
+
+// extensionIndexKeyFields returns the fields under which the block extension entries of
+// the listed type URLs are indexed. They are configured as a list rather than as a map,
+// as viper lowercases the keys of the maps while the type URLs are case sensitive.
+func extensionIndexKeyFields() map[string]string {
+	var configured []struct {
+		TypeURL string
+		Field   string
+	}
+	if err := viper.UnmarshalKey("ledger.blockchain.extensionIndex.keyFields", &configured); err != nil {
+		logger.Panicf("Invalid ledger.blockchain.extensionIndex.keyFields: %s", err)
+	}
+	if len(configured) == 0 {
+		return nil
+	}
+	keyFields := make(map[string]string, len(configured))
+	for _, keyField := range configured {
+		keyFields[keyField.TypeURL] = keyField.Field
+	}
+	return keyFields
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,6 +47,11 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/ledgersData/snapshots",
 				},
+				ExtensionIndexConfig:   &ledger.ExtensionIndexConfig{},
+				StateCheckpointsConfig: &ledger.StateCheckpointsConfig{},
+				BlockRetentionConfig:   &ledger.BlockRetentionConfig{},
+				BlockCompressionConfig: &ledger.BlockCompressionConfig{},
+				CommitPipelineConfig:   &ledger.CommitPipelineConfig{},
 			},
 		},
 		{
@@ -93,6 +99,11 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/ledgersData/snapshots",
 				},
+				ExtensionIndexConfig:   &ledger.ExtensionIndexConfig{},
+				StateCheckpointsConfig: &ledger.StateCheckpointsConfig{},
+				BlockRetentionConfig:   &ledger.BlockRetentionConfig{},
+				BlockCompressionConfig: &ledger.BlockCompressionConfig{},
+				CommitPipelineConfig:   &ledger.CommitPipelineConfig{},
 			},
 		},
 		{
@@ -110,6 +121,7 @@ func TestLedgerConfig(t *testing.T) {
 				"ledger.state.couchDBConfig.maxBatchUpdateSize":           600,
 				"ledger.state.couchDBConfig.warmIndexesAfterNBlocks":      5,
 				"ledger.state.couchDBConfig.createGlobalChangesDB":        true,
+				"ledger.state.couchDBConfig.queryPlanCheck":               "warn",
 				"ledger.state.couchDBConfig.cacheSize":                    64,
 				"ledger.pvtdataStore.collElgProcMaxDbBatchSize":           50000,
 				"ledger.pvtdataStore.collElgProcDbBatchesInterval":        10000,
@@ -117,6 +129,17 @@ func TestLedgerConfig(t *testing.T) {
 				"ledger.pvtdataStore.deprioritizedDataReconcilerInterval": "180m",
 				"ledger.history.enableHistoryDatabase":                    true,
 				"ledger.snapshots.rootDir":                                "/peerfs/snapshots",
+				"ledger.blockchain.extensionIndex.typeURLs":               []string{"hyperledger.org/fabric/blockextension/TxIDMerkleRoot"},
+				"ledger.blockchain.extensionIndex.keyFields": []interface{}{
+					map[string]interface{}{"typeURL": "hyperledger.org/fabric/blockextension/TxIDMerkleRoot", "field": "root"},
+				},
+				"ledger.state.checkpoints.interval":           100,
+				"ledger.blockchain.retention.retainBlocks":    10000,
+				"ledger.blockchain.retention.pruneAtSnapshot": true,
+				"ledger.blockchain.retention.archiveDir":      "/archive",
+				"ledger.blockchain.retention.fetchArchived":   true,
+				"ledger.blockchain.compression.codec":         "snappy",
+				"ledger.state.commitPipeline.enabled":         true,
 			},
 			expected: &ledger.Config{
 				RootFSPath: "/peerfs/ledgersData",
@@ -135,6 +158,7 @@ func TestLedgerConfig(t *testing.T) {
 						CreateGlobalChangesDB:   true,
 						RedoLogPath:             "/peerfs/ledgersData/couchdbRedoLogs",
 						UserCacheSizeMBs:        64,
+						QueryPlanCheck:          "warn",
 					},
 				},
 				PrivateDataConfig: &ledger.PrivateDataConfig{
@@ -149,6 +173,76 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
+				ExtensionIndexConfig: &ledger.ExtensionIndexConfig{
+					TypeURLs:  []string{"hyperledger.org/fabric/blockextension/TxIDMerkleRoot"},
+					KeyFields: map[string]string{"hyperledger.org/fabric/blockextension/TxIDMerkleRoot": "root"},
+				},
+				StateCheckpointsConfig: &ledger.StateCheckpointsConfig{
+					Interval: 100,
+				},
+				BlockRetentionConfig: &ledger.BlockRetentionConfig{
+					RetainBlocks:    10000,
+					PruneAtSnapshot: true,
+					ArchiveDir:      "/archive",
+					FetchArchived:   true,
+				},
+				BlockCompressionConfig: &ledger.BlockCompressionConfig{
+					Codec: "snappy",
+				},
+				CommitPipelineConfig: &ledger.CommitPipelineConfig{
+					Enabled: true,
+				},
+			},
+		},
+		{
+			name: "Registered State Database",
+			config: map[string]interface{}{
+				"peer.fileSystemPath":        "/peerfs",
+				"ledger.state.stateDatabase": "Remote",
+				"ledger.state.pluginConfig": map[string]interface{}{
+					"address":     "localhost:7070",
+					"dialTimeout": "5s",
+				},
+				"ledger.history.enableHistoryDatabase":      false,
+				"ledger.snapshots.rootDir":                  "",
+				"ledger.blockchain.extensionIndex.typeURLs": []string{},
+			},
+			expected: &ledger.Config{
+				RootFSPath: "/peerfs/ledgersData",
+				StateDBConfig: &ledger.StateDBConfig{
+					StateDatabase: "Remote",
+					CouchDB:       &ledger.CouchDBConfig{},
+					PluginConfig: map[string]interface{}{
+						"address":     "localhost:7070",
+						"dialTimeout": "5s",
+					},
+				},
+				PrivateDataConfig: &ledger.PrivateDataConfig{
+					MaxBatchSize:                        50000,
+					BatchesInterval:                     10000,
+					PurgeInterval:                       1000,
+					DeprioritizedDataReconcilerInterval: 180 * time.Minute,
+				},
+				HistoryDBConfig: &ledger.HistoryDBConfig{
+					Enabled: false,
+				},
+				SnapshotsConfig: &ledger.SnapshotsConfig{
+					RootDir: "/peerfs/ledgersData/snapshots",
+				},
+				ExtensionIndexConfig: &ledger.ExtensionIndexConfig{
+					TypeURLs: []string{},
+				},
+				StateCheckpointsConfig: &ledger.StateCheckpointsConfig{},
+				BlockRetentionConfig: &ledger.BlockRetentionConfig{
+					RetainBlocks:    10000,
+					PruneAtSnapshot: true,
+					ArchiveDir:      "/archive",
+					FetchArchived:   true,
+				},
+				BlockCompressionConfig: &ledger.BlockCompressionConfig{
+					Codec: "snappy",
+				},
+				CommitPipelineConfig: &ledger.CommitPipelineConfig{},
 			},
 		},
 	}
@@ -164,3 +258,20 @@ func TestLedgerConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestTransientStoreConfig(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("peer.fileSystemPath", "/peerfs")
+	assert.Equal(t, "/peerfs/transientstore", transientStorePath())
+	assert.Equal(t, &transientstore.Config{}, transientStoreConfig())
+
+	viper.Set("peer.gossip.pvtData.transientstoreMaxAge", "48h")
+	viper.Set("peer.gossip.pvtData.transientstoreCollectionQuota", "64MB")
+	viper.Set("peer.gossip.pvtData.transientstoreClientQuota", "8MB")
+	assert.Equal(t, &transientstore.Config{
+		MaxAge:               48 * time.Hour,
+		CollectionQuotaBytes: 64 * 1024 * 1024,
+		ClientQuotaBytes:     8 * 1024 * 1024,
+	}, transientStoreConfig())
+}
@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	startBlockNumber uint64
+	endBlockNumber   uint64
+	archivePath      string
+)
+
+func exportBlocksCmd() *cobra.Command {
+	nodeExportBlocksCmd.ResetFlags()
+	flags := nodeExportBlocksCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose blocks are exported.")
+	flags.Uint64VarP(&startBlockNumber, "startBlockNumber", "s", 0, "Number of the first block to export.")
+	flags.Uint64VarP(&endBlockNumber, "endBlockNumber", "e", 0, "Number of the last block to export.")
+	flags.StringVarP(&archivePath, "archivePath", "o", "", "Path of the block archive to create.")
+
+	return nodeExportBlocksCmd
+}
+
+var nodeExportBlocksCmd = &cobra.Command{
+	Use:   "export-blocks",
+	Short: "Exports a range of blocks of a channel to a block archive.",
+	Long:  `Exports a range of blocks of a channel to a block archive. The blocks are exported with their header, data, metadata, and extension. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		if archivePath == "" {
+			return errors.New("Must supply archive path")
+		}
+
+		config := ledgerConfig()
+		archiveHash, err := kvledger.ExportBlocks(config, channelID, startBlockNumber, endBlockNumber, archivePath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported blocks [%d] to [%d] of channel [%s] to [%s], archive hash: %x\n",
+			startBlockNumber, endBlockNumber, channelID, archivePath, archiveHash)
+		return nil
+	},
+}
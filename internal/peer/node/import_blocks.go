@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func importBlocksCmd() *cobra.Command {
+	nodeImportBlocksCmd.ResetFlags()
+	flags := nodeImportBlocksCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to which the blocks are imported.")
+	flags.StringVarP(&archivePath, "archivePath", "i", "", "Path of the block archive to import.")
+
+	return nodeImportBlocksCmd
+}
+
+var nodeImportBlocksCmd = &cobra.Command{
+	Use:   "import-blocks",
+	Short: "Imports the blocks of a block archive to a channel.",
+	Long:  `Imports the blocks of a block archive, created by the export-blocks command, to a channel. The channel must be joined and the first block of the archive must follow the last block of the channel. The hash chaining, the data hash, and the extension hash of all the blocks are verified before any block is imported, but the signatures of the blocks are not, so the archive must come from a trusted source. When the command is executed, the peer must be offline. When the peer starts after the import, it will rebuild the state database from the imported blocks and record the private data of the imported blocks as missing, to be fetched from the other peers.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		if archivePath == "" {
+			return errors.New("Must supply archive path")
+		}
+
+		config := ledgerConfig()
+		return kvledger.ImportBlocks(config, channelID, archivePath)
+	},
+}
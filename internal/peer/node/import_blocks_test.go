@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportBlocksCmd(t *testing.T) {
+	t.Run("when the channelID is not supplied", func(t *testing.T) {
+		cmd := importBlocksCmd()
+		args := []string{"-i", "archive"}
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		require.EqualError(t, err, "Must supply channel ID")
+	})
+
+	t.Run("when the archive path is not supplied", func(t *testing.T) {
+		cmd := importBlocksCmd()
+		args := []string{"-c", "ch1"}
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		require.EqualError(t, err, "Must supply archive path")
+	})
+
+	t.Run("when the specified channelID does not exist", func(t *testing.T) {
+		testPath := "/tmp/hyperledger/test"
+		os.RemoveAll(testPath)
+		viper.Set("peer.fileSystemPath", testPath)
+		defer os.RemoveAll(testPath)
+
+		cmd := importBlocksCmd()
+		args := []string{"-c", "ch1", "-i", "archive"}
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		require.EqualError(t, err, "ledgerID [ch1] does not exist")
+	})
+}
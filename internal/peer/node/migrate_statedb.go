@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	coreconfig "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var targetStateDatabase string
+
+func migrateStateDBCmd() *cobra.Command {
+	nodeMigrateStateDBCmd.ResetFlags()
+	flags := nodeMigrateStateDBCmd.Flags()
+	flags.StringVarP(&targetStateDatabase, "to", "t", "", "State database to migrate to: couchdb or goleveldb.")
+
+	return nodeMigrateStateDBCmd
+}
+
+var nodeMigrateStateDBCmd = &cobra.Command{
+	Use:   "migrate-statedb",
+	Short: "Migrates the state database.",
+	Long:  `Migrates the state databases of all the channels from the configured state database (ledger.state.stateDatabase) to another state database, without replaying the blocks. The indexes of the chaincodes installed on the peer are created in the target state database. When the command is executed, the peer must be offline. Before starting the peer after the migration, ledger.state.stateDatabase must be set to the target state database.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config := ledgerConfig()
+		targetConfig := &ledger.StateDBConfig{
+			CouchDB: &ledger.CouchDBConfig{},
+		}
+		switch strings.ToLower(targetStateDatabase) {
+		case "couchdb":
+			targetConfig.StateDatabase = "CouchDB"
+			targetConfig.CouchDB = couchDBConfig(config.RootFSPath)
+		case "goleveldb":
+			targetConfig.StateDatabase = "goleveldb"
+		case "":
+			return errors.New("Must supply the state database to migrate to")
+		default:
+			return errors.Errorf("Invalid state database [%s], it must be either couchdb or goleveldb", targetStateDatabase)
+		}
+
+		return kvledger.MigrateStateDB(config, targetConfig, newChaincodeDeployEventsReplayer())
+	},
+}
+
+// chaincodeDeployEventsReplayer replays the deploy events for the chaincodes that are installed on the peer,
+// for both the _lifecycle and the legacy lifecycle (lscc)
+type chaincodeDeployEventsReplayer struct {
+	lifecycleCache *lifecycle.Cache
+	initialized    bool
+}
+
+func newChaincodeDeployEventsReplayer() *chaincodeDeployEventsReplayer {
+	chaincodeInstallPath := filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "lifecycle", "chaincodes")
+	lifecycleResources := &lifecycle.Resources{
+		Serializer:     &lifecycle.Serializer{},
+		ChaincodeStore: persistence.NewStore(chaincodeInstallPath),
+		PackageParser: &persistence.ChaincodePackageParser{
+			MetadataProvider: ccprovider.PersistenceAdapter(ccprovider.MetadataAsTarEntries),
+		},
+	}
+
+	lsccInstallPath := filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "chaincodes")
+	ccprovider.SetChaincodesPath(lsccInstallPath)
+
+	externalBuilderOutput := filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "externalbuilder", "builds")
+	return &chaincodeDeployEventsReplayer{
+		lifecycleCache: lifecycle.NewCache(
+			lifecycleResources,
+			viper.GetString("peer.localMspId"),
+			lifecycle.NewMetadataManager(),
+			lifecycle.NewChaincodeCustodian(),
+			&externalbuilder.MetadataProvider{DurablePath: externalBuilderOutput},
+		),
+	}
+}
+
+// ReplayChaincodeDeployEvents implements the method in the interface kvledger.ChaincodeDeployEventsReplayer
+func (r *chaincodeDeployEventsReplayer) ReplayChaincodeDeployEvents(
+	channelID string,
+	qe ledger.SimpleQueryExecutor,
+	listener ledger.ChaincodeLifecycleEventListener,
+) error {
+	if !r.initialized {
+		if err := r.lifecycleCache.InitializeLocalChaincodes(); err != nil {
+			return errors.WithMessage(err, "could not initialize local chaincodes")
+		}
+		r.initialized = true
+	}
+
+	r.lifecycleCache.RegisterListener(channelID, listener)
+	if err := r.lifecycleCache.Initialize(channelID, qe); err != nil {
+		return errors.WithMessagef(err, "could not initialize the lifecycle cache for channel [%s]", channelID)
+	}
+	r.lifecycleCache.ProcessInstalledChaincodes(channelID)
+
+	legacyChaincodes, err := (&lscc.DeployedCCInfoProvider{}).AllChaincodesInfo(channelID, qe)
+	if err != nil {
+		return errors.WithMessagef(err, "could not retrieve the legacy chaincodes for channel [%s]", channelID)
+	}
+	for name, info := range legacyChaincodes {
+		if !info.IsLegacy {
+			continue
+		}
+		if _, err := r.lifecycleCache.ChaincodeInfo(channelID, name); err == nil {
+			// the chaincode has been upgraded to the _lifecycle
+			continue
+		}
+		installed, dbArtifacts, err := ccprovider.ExtractStatedbArtifactsForChaincode(name + ":" + info.Version)
+		if err != nil {
+			return errors.WithMessagef(err, "could not extract the statedb artifacts for chaincode [%s:%s]", name, info.Version)
+		}
+		if !installed {
+			continue
+		}
+		if err := listener.HandleChaincodeDeploy(
+			&ledger.ChaincodeDefinition{
+				Name:              name,
+				Version:           info.Version,
+				Hash:              info.Hash,
+				CollectionConfigs: info.ExplicitCollectionConfigPkg,
+			},
+			dbArtifacts,
+		); err != nil {
+			return errors.WithMessagef(err, "could not create the statedb artifacts for chaincode [%s:%s]", name, info.Version)
+		}
+		listener.ChaincodeDeployDone(true)
+	}
+	return nil
+}
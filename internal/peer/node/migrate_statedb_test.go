@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateStateDBCmd(t *testing.T) {
+	testPath := "/tmp/hyperledger/test"
+	os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	viper.Set("ledger.state.stateDatabase", "goleveldb")
+	defer os.RemoveAll(testPath)
+	defer viper.Reset()
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "missing-target",
+			args:          []string{},
+			expectedError: "Must supply the state database to migrate to",
+		},
+		{
+			name:          "invalid-target",
+			args:          []string{"--to", "mongodb"},
+			expectedError: "Invalid state database [mongodb], it must be either couchdb or goleveldb",
+		},
+		{
+			name:          "same-as-configured",
+			args:          []string{"--to", "GoLevelDB"},
+			expectedError: "the state database is already [goleveldb]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := migrateStateDBCmd()
+			cmd.SetArgs(tc.args)
+			require.EqualError(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	discprotos "github.com/hyperledger/fabric-protos-go/discovery"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	ccdef "github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -66,7 +67,9 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateremote" // registers the "Remote" state database
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
@@ -86,6 +89,7 @@ import (
 	gossipgossip "github.com/hyperledger/fabric/gossip/gossip"
 	gossipmetrics "github.com/hyperledger/fabric/gossip/metrics"
 	gossipprivdata "github.com/hyperledger/fabric/gossip/privdata"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
 	"github.com/hyperledger/fabric/gossip/service"
 	gossipservice "github.com/hyperledger/fabric/gossip/service"
 	peergossip "github.com/hyperledger/fabric/internal/peer/gossip"
@@ -279,8 +283,10 @@ func serve(args []string) error {
 		cs.SetClientCertificate(clientCert)
 	}
 
-	transientStoreProvider, err := transientstore.NewStoreProvider(
-		filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore"),
+	transientStoreProvider, err := transientstore.NewStoreProviderWithConfig(
+		transientStorePath(),
+		transientStoreConfig(),
+		metricsProvider,
 	)
 	if err != nil {
 		return errors.WithMessage(err, "failed to open transient store")
@@ -436,6 +442,7 @@ func serve(args []string) error {
 			ChaincodeLifecycleEventProvider: lifecycleCache,
 			MetricsProvider:                 metricsProvider,
 			HealthCheckRegistry:             opsSystem,
+			OperationsHandlerRegistry:       opsSystem,
 			StateListeners:                  []ledger.StateListener{lifecycleCache},
 			Config:                          ledgerConfig(),
 			HashProvider:                    factory.GetDefault(),
@@ -452,6 +459,7 @@ func serve(args []string) error {
 	// of go routines and registration with the grpc server.
 	gossipService, err := initGossipService(
 		policyMgr,
+		peerInstance.GetBlockExtensionLimits,
 		metricsProvider,
 		peerServer,
 		signingIdentity,
@@ -500,6 +508,7 @@ func serve(args []string) error {
 		PolicyCheckerProvider: policyCheckerProvider,
 	}
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
+	blockextension.RegisterExtensionDeliverServer(peerServer.Server(), abServer)
 
 	// Create a self-signed CA for chaincode service
 	ca, err := tlsgen.NewCA()
@@ -874,6 +883,14 @@ func serve(args []string) error {
 	// Register the Endorser server
 	pb.RegisterEndorserServer(peerServer.Server(), auth)
 
+	// Register the snapshot server
+	snapshotSvc := &snapshotgrpc.SnapshotService{LedgerGetter: peerInstance, ACLProvider: aclProvider}
+	snapshotgrpc.RegisterSnapshotServer(peerServer.Server(), snapshotSvc)
+
+	// Register the reconciliation server
+	reconciliationSvc := &reconciliationgrpc.ReconciliationService{ReconcilerGetter: gossipService, ACLProvider: aclProvider}
+	reconciliationgrpc.RegisterReconciliationServer(peerServer.Server(), reconciliationSvc)
+
 	go func() {
 		var grpcErr error
 		if grpcErr = peerServer.Start(); grpcErr != nil {
@@ -1165,6 +1182,7 @@ func secureDialOpts(credSupport *comm.CredentialSupport) func() []grpc.DialOptio
 // 4. Init gossip related struct.
 func initGossipService(
 	policyMgr policies.ChannelPolicyManagerGetter,
+	extensionLimitsGetter peergossip.ExtensionLimitsGetter,
 	metricsProvider metrics.Provider,
 	peerServer *comm.GRPCServer,
 	signer msp.SigningIdentity,
@@ -1192,6 +1210,7 @@ func initGossipService(
 		signer,
 		mgmt.NewDeserializersManager(factory.GetDefault()),
 		factory.GetDefault(),
+		extensionLimitsGetter,
 	)
 	secAdv := peergossip.NewSecurityAdvisor(mgmt.NewDeserializersManager(factory.GetDefault()))
 	bootstrap := viper.GetStringSlice("peer.gossip.bootstrap")
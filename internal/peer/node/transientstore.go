@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	transientTxID        string
+	transientBelowHeight uint64
+	transientOlderThan   time.Duration
+	transientClient      string
+)
+
+func transientStoreCmd() *cobra.Command {
+	nodeTransientStoreCmd.ResetCommands()
+	nodeTransientStoreCmd.AddCommand(transientStoreListCmd())
+	nodeTransientStoreCmd.AddCommand(transientStorePurgeCmd())
+
+	return nodeTransientStoreCmd
+}
+
+func transientStoreListCmd() *cobra.Command {
+	nodeTransientStoreListCmd.ResetFlags()
+	flags := nodeTransientStoreListCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose transient store is listed.")
+	flags.StringVarP(&transientTxID, "txid", "t", "", "Transaction whose private data is listed. All the private data is listed if not supplied.")
+
+	return nodeTransientStoreListCmd
+}
+
+func transientStorePurgeCmd() *cobra.Command {
+	nodeTransientStorePurgeCmd.ResetFlags()
+	flags := nodeTransientStorePurgeCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose transient store is purged.")
+	flags.StringVarP(&transientTxID, "txid", "t", "", "Transaction whose private data is purged.")
+	flags.Uint64VarP(&transientBelowHeight, "belowHeight", "b", 0, "Block height below which the private data received is purged.")
+	flags.DurationVarP(&transientOlderThan, "olderThan", "o", 0, "Age, e.g. 48h, above which the private data is purged.")
+	flags.StringVarP(&transientClient, "client", "", "", "Client, as listed by the list command, whose private data is purged.")
+
+	return nodeTransientStorePurgeCmd
+}
+
+var nodeTransientStoreCmd = &cobra.Command{
+	Use:   "transientstore",
+	Short: "Lists or purges the private data held in the transient store.",
+	Long:  `Lists or purges the private data of the transactions not yet committed that is held in the transient store of a channel. When the command is executed, the peer must be offline.`,
+}
+
+var nodeTransientStoreListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the private data held in the transient store of a channel.",
+	Long:  `Lists the private data held in the transient store of a channel, with the transaction, the block height at which it was received, the time it was persisted at, the client that submitted it to this peer, and the size of each of its collections. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+
+		return withTransientStore(channelID, func(store *transientstore.Store) error {
+			entries, err := store.ListEntries(transientTxID)
+			if err != nil {
+				return err
+			}
+			printTransientStoreEntries(cmd.OutOrStdout(), entries)
+			return nil
+		})
+	},
+}
+
+var nodeTransientStorePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Purges private data from the transient store of a channel.",
+	Long:  `Purges private data from the transient store of a channel, either of a transaction, or received below a block height, or older than an age, or of a client. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		flags := cmd.Flags()
+		criteria := 0
+		for _, name := range []string{"txid", "belowHeight", "olderThan", "client"} {
+			if flags.Changed(name) {
+				criteria++
+			}
+		}
+		if criteria != 1 {
+			return errors.New("Must supply exactly one of txid, belowHeight, olderThan, or client")
+		}
+
+		return withTransientStore(channelID, func(store *transientstore.Store) error {
+			before, err := store.ListEntries("")
+			if err != nil {
+				return err
+			}
+			switch {
+			case flags.Changed("txid"):
+				err = store.PurgeByTxids([]string{transientTxID})
+			case flags.Changed("belowHeight"):
+				err = store.PurgeBelowHeight(transientBelowHeight)
+			case flags.Changed("olderThan"):
+				err = store.PurgeOlderThan(time.Now().Add(-transientOlderThan))
+			default:
+				err = store.PurgeByClient(transientClient)
+			}
+			if err != nil {
+				return err
+			}
+			after, err := store.ListEntries("")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Purged [%d] private write sets from the transient store of channel [%s], [%d] remaining\n",
+				len(before)-len(after), channelID, len(after))
+			return nil
+		})
+	},
+}
+
+// withTransientStore opens the transient store of the given channel, which fails if the
+// peer is running as the underlying leveldb is locked by the peer process or if the
+// channel does not exist on the peer
+func withTransientStore(channelID string, f func(store *transientstore.Store) error) error {
+	exists, err := kvledger.LedgerExists(ledgerConfig().RootFSPath, channelID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("channel [%s] does not exist on the peer", channelID)
+	}
+
+	provider, err := transientstore.NewStoreProvider(transientStorePath())
+	if err != nil {
+		return errors.WithMessage(err, "failed to open transient store, the peer must be offline")
+	}
+	defer provider.Close()
+
+	store, err := provider.OpenStore(channelID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to open transient store of channel [%s]", channelID)
+	}
+	return f(store)
+}
+
+func printTransientStoreEntries(w io.Writer, entries []*transientstore.EntryInfo) {
+	for _, e := range entries {
+		persistedAt := "-"
+		if !e.PersistedAt.IsZero() {
+			persistedAt = e.PersistedAt.UTC().Format(time.RFC3339)
+		}
+		client := e.Client
+		if client == "" {
+			client = "-"
+		}
+		var collections []string
+		for _, coll := range e.Collections {
+			collections = append(collections, fmt.Sprintf("%s/%s:%d", coll.Namespace, coll.Collection, coll.Bytes))
+		}
+		fmt.Fprintf(w, "txid=%s uuid=%s height=%d persistedAt=%s client=%s bytes=%d collections=[%s]\n",
+			e.TxID, e.UUID, e.ReceivedAtBlockHeight, persistedAt, client, e.Bytes, strings.Join(collections, " "))
+	}
+	fmt.Fprintf(w, "Total: [%d] private write sets\n", len(entries))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	tspb "github.com/hyperledger/fabric-protos-go/transientstore"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransientStoreCmd(t *testing.T) {
+	t.Run("when the channelID is not supplied to list", func(t *testing.T) {
+		cmd := transientStoreCmd()
+		cmd.SetArgs([]string{"list"})
+		err := cmd.Execute()
+		require.EqualError(t, err, "Must supply channel ID")
+	})
+
+	t.Run("when the channelID is not supplied to purge", func(t *testing.T) {
+		cmd := transientStoreCmd()
+		cmd.SetArgs([]string{"purge", "-t", "txid-1"})
+		err := cmd.Execute()
+		require.EqualError(t, err, "Must supply channel ID")
+	})
+
+	t.Run("when no purge criterion is supplied", func(t *testing.T) {
+		cmd := transientStoreCmd()
+		cmd.SetArgs([]string{"purge", "-c", "ch1"})
+		err := cmd.Execute()
+		require.EqualError(t, err, "Must supply exactly one of txid, belowHeight, olderThan, or client")
+	})
+
+	t.Run("when several purge criteria are supplied", func(t *testing.T) {
+		cmd := transientStoreCmd()
+		cmd.SetArgs([]string{"purge", "-c", "ch1", "-t", "txid-1", "-b", "10"})
+		err := cmd.Execute()
+		require.EqualError(t, err, "Must supply exactly one of txid, belowHeight, olderThan, or client")
+	})
+
+	t.Run("when the channel does not exist on the peer", func(t *testing.T) {
+		testPath, err := ioutil.TempDir("", "transientstorecmd")
+		require.NoError(t, err)
+		defer os.RemoveAll(testPath)
+		viper.Set("peer.fileSystemPath", testPath)
+		defer viper.Reset()
+
+		createTestLedger(t, "ch1")
+
+		cmd := transientStoreCmd()
+		cmd.SetArgs([]string{"list", "-c", "ch2"})
+		require.EqualError(t, cmd.Execute(), "channel [ch2] does not exist on the peer")
+
+		cmd = transientStoreCmd()
+		cmd.SetArgs([]string{"purge", "-c", "ch2", "-t", "txid-1"})
+		require.EqualError(t, cmd.Execute(), "channel [ch2] does not exist on the peer")
+	})
+
+	t.Run("list and purge", func(t *testing.T) {
+		testPath, err := ioutil.TempDir("", "transientstorecmd")
+		require.NoError(t, err)
+		defer os.RemoveAll(testPath)
+		viper.Set("peer.fileSystemPath", testPath)
+		defer viper.Reset()
+
+		createTestLedger(t, "ch1")
+		persistTransientEntries(t, "ch1", "txid-1", "txid-2")
+
+		out := &bytes.Buffer{}
+		cmd := transientStoreCmd()
+		cmd.SetOutput(out)
+		cmd.SetArgs([]string{"list", "-c", "ch1"})
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "txid=txid-1")
+		require.Contains(t, out.String(), "txid=txid-2")
+		require.Contains(t, out.String(), "collections=[ns-1/coll-1:")
+		require.Contains(t, out.String(), "Total: [2] private write sets")
+
+		out.Reset()
+		cmd = transientStoreCmd()
+		cmd.SetOutput(out)
+		cmd.SetArgs([]string{"purge", "-c", "ch1", "-t", "txid-1"})
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "Purged [1] private write sets from the transient store of channel [ch1], [1] remaining\n", out.String())
+
+		out.Reset()
+		cmd = transientStoreCmd()
+		cmd.SetOutput(out)
+		cmd.SetArgs([]string{"list", "-c", "ch1", "-t", "txid-2"})
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "txid=txid-2")
+		require.Contains(t, out.String(), "Total: [1] private write sets")
+	})
+}
+
+func createTestLedger(t *testing.T, channelID string) {
+	ledgerMgr := ledgermgmt.NewLedgerMgr(ledgermgmttest.NewInitializer(ledgerConfig().RootFSPath))
+	defer ledgerMgr.Close()
+	genesisBlock, err := configtxtest.MakeGenesisBlock(channelID)
+	require.NoError(t, err)
+	_, err = ledgerMgr.CreateLedger(channelID, genesisBlock)
+	require.NoError(t, err)
+}
+
+func persistTransientEntries(t *testing.T, channelID string, txids ...string) {
+	provider, err := transientstore.NewStoreProvider(transientStorePath())
+	require.NoError(t, err)
+	defer provider.Close()
+	store, err := provider.OpenStore(channelID)
+	require.NoError(t, err)
+
+	for _, txid := range txids {
+		err := store.Persist(txid, 10, &tspb.TxPvtReadWriteSetWithConfigInfo{
+			PvtRwset: &rwset.TxPvtReadWriteSet{
+				DataModel: rwset.TxReadWriteSet_KV,
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{
+						Namespace: "ns-1",
+						CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+							{CollectionName: "coll-1", Rwset: []byte("rwset-" + txid)},
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliation
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var logger = flogging.MustGetLogger("cli.reconciliation")
+
+// Cmd returns the cobra command for reconciliation
+func Cmd() *cobra.Command {
+	reconciliationCmd.AddCommand(TriggerCmd(nil))
+	reconciliationCmd.AddCommand(StatusCmd(nil))
+
+	return reconciliationCmd
+}
+
+// Reconciliation-related variables.
+var (
+	channelID       string
+	namespace       string
+	collection      string
+	startBlock      uint64
+	endBlock        uint64
+	peerAddress     string
+	tlsRootCertFile string
+)
+
+var reconciliationCmd = &cobra.Command{
+	Use:   "reconciliation",
+	Short: "Manage the private data reconciliation of a peer: trigger|status",
+	Long:  "Manage the private data reconciliation of a peer: trigger|status",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		common.InitCmd(cmd, args)
+	},
+}
+
+var flags *pflag.FlagSet
+
+func init() {
+	ResetFlags()
+}
+
+// ResetFlags resets the values of these flags to facilitate tests
+func ResetFlags() {
+	flags = &pflag.FlagSet{}
+
+	flags.StringVarP(&channelID, "channelID", "c", "", "The channel on which this command should be executed")
+	flags.StringVarP(&namespace, "namespace", "n", "", "The namespace (chaincode name) whose missing private data is reconciled, all namespaces if not provided")
+	flags.StringVarP(&collection, "collection", "", "", "The collection whose missing private data is reconciled, all collections if not provided")
+	flags.Uint64VarP(&startBlock, "startBlock", "s", 0, "The first block whose missing private data is reconciled")
+	flags.Uint64VarP(&endBlock, "endBlock", "e", 0, "The last block whose missing private data is reconciled, no bound if set to 0 or not provided")
+	flags.StringVarP(&peerAddress, "peerAddress", "", "", "The address of the peer to connect to")
+	flags.StringVarP(&tlsRootCertFile, "tlsRootCertFile", "", "",
+		"The path to the TLS root cert file of the peer to connect to, required if TLS is enabled and ignored if TLS is disabled.")
+}
+
+func attachFlags(cmd *cobra.Command, names []string) {
+	cmdFlags := cmd.Flags()
+	for _, name := range names {
+		if flag := flags.Lookup(name); flag != nil {
+			cmdFlags.AddFlag(flag)
+		} else {
+			logger.Fatalf("Could not find flag '%s' to attach to command '%s'", name, cmd.Name())
+		}
+	}
+}
+
+// Client holds the dependencies needed to send a reconciliation request to a peer
+type Client struct {
+	ReconciliationClient reconciliationgrpc.ReconciliationClient
+	Signer               identity.SignerSerializer
+}
+
+// NewClient creates a client for the reconciliation service of the peer set by the
+// peerAddress flag, signing the requests with the default signer
+func NewClient() (*Client, error) {
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to retrieve default signer")
+	}
+	peerClient, err := common.NewPeerClientForAddress(peerAddress, tlsRootCertFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create peer client")
+	}
+	reconciliationClient, err := peerClient.Reconciliation()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create reconciliation client")
+	}
+	return &Client{
+		ReconciliationClient: reconciliationClient,
+		Signer:               signer,
+	}, nil
+}
+
+func validateInput() error {
+	if channelID == "" {
+		return errors.New("the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	}
+	return nil
+}
+
+func newSignatureHeader(signer identity.SignerSerializer) (*cb.SignatureHeader, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate nonce")
+	}
+	return &cb.SignatureHeader{
+		Creator: creator,
+		Nonce:   nonce,
+	}, nil
+}
+
+func signReconciliationRequest(signer identity.SignerSerializer, request proto.Message) (*reconciliationgrpc.SignedReconciliationRequest, error) {
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal reconciliation request")
+	}
+	signature, err := signer.Sign(requestBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign reconciliation request")
+	}
+	return &reconciliationgrpc.SignedReconciliationRequest{
+		Request:   requestBytes,
+		Signature: signature,
+	}, nil
+}
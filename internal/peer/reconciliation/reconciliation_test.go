@@ -0,0 +1,22 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliation_test
+
+import (
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+)
+
+//go:generate counterfeiter -o mock/reconciliation_client.go -fake-name ReconciliationClient . reconciliationClient
+type reconciliationClient interface {
+	reconciliationgrpc.ReconciliationClient
+}
+
+//go:generate counterfeiter -o mock/signer.go -fake-name Signer . signer
+type signer interface {
+	identity.SignerSerializer
+}
@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// StatusCmd returns the cobra command for querying the reconciliation status
+func StatusCmd(cl *Client) *cobra.Command {
+	reconciliationStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the reconciliation of the missing private data of a channel.",
+		Long: "Show the status of the reconciliation of the missing private data of a channel, with the missing private data " +
+			"found by the current or last on-demand reconciliation, and the progress and failures of each collection.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return status(cmd, cl, os.Stdout)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"peerAddress",
+		"tlsRootCertFile",
+	}
+	attachFlags(reconciliationStatusCmd, flagList)
+
+	return reconciliationStatusCmd
+}
+
+func status(cmd *cobra.Command, cl *Client, out io.Writer) error {
+	if err := validateInput(); err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cl == nil {
+		var err error
+		if cl, err = NewClient(); err != nil {
+			return err
+		}
+	}
+
+	signatureHdr, err := newSignatureHeader(cl.Signer)
+	if err != nil {
+		return err
+	}
+	query := &reconciliationgrpc.ReconciliationQuery{
+		SignatureHeader: signatureHdr,
+		ChannelId:       channelID,
+	}
+	signedRequest, err := signReconciliationRequest(cl.Signer, query)
+	if err != nil {
+		return err
+	}
+
+	resp, err := cl.ReconciliationClient.GetStatus(context.Background(), signedRequest)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get reconciliation status")
+	}
+
+	printStatus(out, resp)
+	return nil
+}
+
+func printStatus(out io.Writer, resp *reconciliationgrpc.ReconciliationStatusResponse) {
+	if !resp.Enabled {
+		fmt.Fprintf(out, "Reconciliation of channel %s is disabled\n", channelID)
+		return
+	}
+	fmt.Fprintf(out, "Reconciliation of channel %s is %s\n", channelID, resp.State)
+
+	lastPass := resp.LastPass
+	if lastPass == nil {
+		fmt.Fprint(out, "No on-demand reconciliation since the peer started\n")
+		return
+	}
+	filter := lastPass.Filter
+	fmt.Fprintf(out, "On-demand reconciliation of namespace [%s], collection [%s], blocks [%d - %s]\n",
+		orAll(filter.GetNamespace()), orAll(filter.GetCollection()), filter.GetStartBlock(), blockOrLatest(filter.GetEndBlock()))
+	fmt.Fprintf(out, "Started at: %s\n", formatTimestamp(lastPass.StartedAt))
+	if lastPass.FinishedAt != nil {
+		fmt.Fprintf(out, "Finished at: %s\n", formatTimestamp(lastPass.FinishedAt))
+	}
+	if lastPass.Error != "" {
+		fmt.Fprintf(out, "Error: %s\n", lastPass.Error)
+	}
+	if len(lastPass.Collections) == 0 {
+		fmt.Fprint(out, "No missing private data found\n")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tCOLLECTION\tBLOCKS\tMISSING\tRECONCILED\tUNRECONCILED\tHASH MISMATCHES\tREMAINING")
+	for _, c := range lastPass.Collections {
+		remaining := c.Missing - min(c.Missing, c.Reconciled+c.Unreconciled+c.HashMismatches)
+		fmt.Fprintf(w, "%s\t%s\t%d - %d\t%d\t%d\t%d\t%d\t%d\n",
+			c.Namespace, c.Collection, c.MinBlock, c.MaxBlock, c.Missing, c.Reconciled, c.Unreconciled, c.HashMismatches, remaining)
+	}
+	w.Flush()
+}
+
+func orAll(s string) string {
+	if s == "" {
+		return "*"
+	}
+	return s
+}
+
+func blockOrLatest(blockNum uint64) string {
+	if blockNum == 0 {
+		return "latest"
+	}
+	return fmt.Sprintf("%d", blockNum)
+}
+
+func formatTimestamp(ts *timestamp.Timestamp) string {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return "unknown"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func min(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
+	"github.com/hyperledger/fabric/internal/peer/reconciliation"
+	"github.com/hyperledger/fabric/internal/peer/reconciliation/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCmd(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSigner.SerializeReturns([]byte("creator"), nil)
+	mockSigner.SignReturns([]byte("signature"), nil)
+	mockReconciliationClient := &mock.ReconciliationClient{}
+	client := &reconciliation.Client{ReconciliationClient: mockReconciliationClient, Signer: mockSigner}
+
+	responses := []*reconciliationgrpc.ReconciliationStatusResponse{
+		{},
+		{Enabled: true, State: "idle"},
+		{
+			Enabled: true,
+			State:   "running",
+			LastPass: &reconciliationgrpc.ReconciliationPass{
+				Filter:    &reconciliationgrpc.ReconciliationFilter{Namespace: "ns1"},
+				StartedAt: &timestamp.Timestamp{Seconds: 1000},
+				Collections: []*reconciliationgrpc.CollectionReconciliationProgress{
+					{Namespace: "ns1", Collection: "coll1", MinBlock: 12, MaxBlock: 18, Missing: 5, Reconciled: 2, Unreconciled: 1},
+				},
+			},
+		},
+		{
+			Enabled: true,
+			State:   "idle",
+			LastPass: &reconciliationgrpc.ReconciliationPass{
+				StartedAt:  &timestamp.Timestamp{Seconds: 1000},
+				FinishedAt: &timestamp.Timestamp{Seconds: 1060},
+				Error:      "failed to commit private data",
+			},
+		},
+	}
+	for i, resp := range responses {
+		mockReconciliationClient.GetStatusReturnsOnCall(i, resp, nil)
+
+		reconciliation.ResetFlags()
+		cmd := reconciliation.StatusCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.NoError(t, cmd.Execute())
+	}
+
+	require.Equal(t, len(responses), mockReconciliationClient.GetStatusCallCount())
+	_, signedRequest, _ := mockReconciliationClient.GetStatusArgsForCall(0)
+	query := &reconciliationgrpc.ReconciliationQuery{}
+	require.NoError(t, proto.Unmarshal(signedRequest.Request, query))
+	require.Equal(t, "testchannel", query.ChannelId)
+	require.Equal(t, []byte("creator"), query.SignatureHeader.Creator)
+}
+
+func TestStatusCmdErrors(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockReconciliationClient := &mock.ReconciliationClient{}
+	client := &reconciliation.Client{ReconciliationClient: mockReconciliationClient, Signer: mockSigner}
+
+	t.Run("missing channel", func(t *testing.T) {
+		reconciliation.ResetFlags()
+		cmd := reconciliation.StatusCmd(client)
+		cmd.SetArgs([]string{})
+		require.EqualError(t, cmd.Execute(), "the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	})
+
+	t.Run("reconciliation service error", func(t *testing.T) {
+		mockReconciliationClient.GetStatusReturns(nil, errors.New("cannot find reconciler for channel testchannel"))
+		reconciliation.ResetFlags()
+		cmd := reconciliation.StatusCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to get reconciliation status: cannot find reconciler for channel testchannel")
+	})
+}
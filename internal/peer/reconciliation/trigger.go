@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// TriggerCmd returns the cobra command for triggering an on-demand reconciliation pass
+func TriggerCmd(cl *Client) *cobra.Command {
+	reconciliationTriggerCmd := &cobra.Command{
+		Use:   "trigger",
+		Short: "Trigger an on-demand reconciliation of the missing private data of a channel.",
+		Long: "Trigger an on-demand reconciliation of the missing private data of a channel, optionally restricted to a namespace, " +
+			"a collection, and a range of blocks. The reconciliation runs in the background, use the status command to follow its progress.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return trigger(cmd, cl, os.Stdout)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"namespace",
+		"collection",
+		"startBlock",
+		"endBlock",
+		"peerAddress",
+		"tlsRootCertFile",
+	}
+	attachFlags(reconciliationTriggerCmd, flagList)
+
+	return reconciliationTriggerCmd
+}
+
+func trigger(cmd *cobra.Command, cl *Client, out io.Writer) error {
+	if err := validateInput(); err != nil {
+		return err
+	}
+	if endBlock != 0 && endBlock < startBlock {
+		return errors.Errorf("the endBlock [%d] is lower than the startBlock [%d]", endBlock, startBlock)
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cl == nil {
+		var err error
+		if cl, err = NewClient(); err != nil {
+			return err
+		}
+	}
+
+	signatureHdr, err := newSignatureHeader(cl.Signer)
+	if err != nil {
+		return err
+	}
+	request := &reconciliationgrpc.ReconciliationRequest{
+		SignatureHeader: signatureHdr,
+		ChannelId:       channelID,
+		Filter: &reconciliationgrpc.ReconciliationFilter{
+			Namespace:  namespace,
+			Collection: collection,
+			StartBlock: startBlock,
+			EndBlock:   endBlock,
+		},
+	}
+	signedRequest, err := signReconciliationRequest(cl.Signer, request)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cl.ReconciliationClient.Reconcile(context.Background(), signedRequest); err != nil {
+		return errors.WithMessage(err, "failed to trigger reconciliation")
+	}
+
+	fmt.Fprint(out, "Reconciliation triggered successfully\n")
+	return nil
+}
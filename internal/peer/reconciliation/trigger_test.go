@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciliation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/gossip/privdata/reconciliationgrpc"
+	"github.com/hyperledger/fabric/internal/peer/reconciliation"
+	"github.com/hyperledger/fabric/internal/peer/reconciliation/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerCmd(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSigner.SerializeReturns([]byte("creator"), nil)
+	mockSigner.SignReturns([]byte("signature"), nil)
+	mockReconciliationClient := &mock.ReconciliationClient{}
+	client := &reconciliation.Client{ReconciliationClient: mockReconciliationClient, Signer: mockSigner}
+
+	reconciliation.ResetFlags()
+	cmd := reconciliation.TriggerCmd(client)
+	cmd.SetArgs([]string{"-c", "testchannel", "-n", "ns1", "--collection", "coll1", "-s", "10", "-e", "20"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, 1, mockReconciliationClient.ReconcileCallCount())
+	_, signedRequest, _ := mockReconciliationClient.ReconcileArgsForCall(0)
+	require.Equal(t, []byte("signature"), signedRequest.Signature)
+	request := &reconciliationgrpc.ReconciliationRequest{}
+	require.NoError(t, proto.Unmarshal(signedRequest.Request, request))
+	require.Equal(t, "testchannel", request.ChannelId)
+	require.Equal(t, []byte("creator"), request.SignatureHeader.Creator)
+	require.True(t, proto.Equal(
+		&reconciliationgrpc.ReconciliationFilter{Namespace: "ns1", Collection: "coll1", StartBlock: 10, EndBlock: 20},
+		request.Filter,
+	))
+}
+
+func TestTriggerCmdErrors(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockReconciliationClient := &mock.ReconciliationClient{}
+	client := &reconciliation.Client{ReconciliationClient: mockReconciliationClient, Signer: mockSigner}
+
+	t.Run("missing channel", func(t *testing.T) {
+		reconciliation.ResetFlags()
+		cmd := reconciliation.TriggerCmd(client)
+		cmd.SetArgs([]string{})
+		require.EqualError(t, cmd.Execute(), "the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	})
+
+	t.Run("end block lower than start block", func(t *testing.T) {
+		reconciliation.ResetFlags()
+		cmd := reconciliation.TriggerCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel", "-s", "20", "-e", "10"})
+		require.EqualError(t, cmd.Execute(), "the endBlock [10] is lower than the startBlock [20]")
+	})
+
+	t.Run("signer error", func(t *testing.T) {
+		mockSigner.SerializeReturns(nil, errors.New("cannot serialize"))
+		defer mockSigner.SerializeReturns([]byte("creator"), nil)
+		reconciliation.ResetFlags()
+		cmd := reconciliation.TriggerCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to serialize identity: cannot serialize")
+	})
+
+	t.Run("reconciliation service error", func(t *testing.T) {
+		mockReconciliationClient.ReconcileReturns(nil, errors.New("an on-demand reconciliation pass is already in progress"))
+		reconciliation.ResetFlags()
+		cmd := reconciliation.TriggerCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to trigger reconciliation: an on-demand reconciliation pass is already in progress")
+	})
+}
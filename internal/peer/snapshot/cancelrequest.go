@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CancelRequestCmd returns the cobra command for cancelling a snapshot request
+func CancelRequestCmd(cl *Client) *cobra.Command {
+	snapshotCancelRequestCmd := &cobra.Command{
+		Use:   "cancelrequest",
+		Short: "Cancel a request for a snapshot at the specified block.",
+		Long:  "Cancel a request for a snapshot at the specified block.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cancelRequest(cmd, cl, os.Stdout)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"blockNumber",
+		"peerAddress",
+		"tlsRootCertFile",
+	}
+	attachFlags(snapshotCancelRequestCmd, flagList)
+
+	return snapshotCancelRequestCmd
+}
+
+func cancelRequest(cmd *cobra.Command, cl *Client, out io.Writer) error {
+	if err := validateInput(); err != nil {
+		return err
+	}
+	if blockNumber == 0 {
+		return errors.New("the required parameter 'blockNumber' is empty or set to 0. Rerun the command with -b flag")
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cl == nil {
+		var err error
+		if cl, err = NewClient(); err != nil {
+			return err
+		}
+	}
+
+	signatureHdr, err := newSignatureHeader(cl.Signer)
+	if err != nil {
+		return err
+	}
+	request := &snapshotgrpc.SnapshotRequest{
+		SignatureHeader: signatureHdr,
+		ChannelId:       channelID,
+		BlockNumber:     blockNumber,
+	}
+	signedRequest, err := signSnapshotRequest(cl.Signer, request)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cl.SnapshotClient.Cancel(context.Background(), signedRequest); err != nil {
+		return errors.WithMessage(err, "failed to cancel snapshot request")
+	}
+
+	fmt.Fprint(out, "Snapshot request cancelled successfully\n")
+	return nil
+}
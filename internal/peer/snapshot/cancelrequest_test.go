@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/internal/peer/snapshot"
+	"github.com/hyperledger/fabric/internal/peer/snapshot/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelRequestCmd(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSigner.SerializeReturns([]byte("creator"), nil)
+	mockSigner.SignReturns([]byte("signature"), nil)
+	mockSnapshotClient := &mock.SnapshotClient{}
+	client := &snapshot.Client{SnapshotClient: mockSnapshotClient, Signer: mockSigner}
+
+	snapshot.ResetFlags()
+	cmd := snapshot.CancelRequestCmd(client)
+	cmd.SetArgs([]string{"-c", "testchannel", "-b", "100"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, 1, mockSnapshotClient.CancelCallCount())
+	_, signedRequest, _ := mockSnapshotClient.CancelArgsForCall(0)
+	require.Equal(t, []byte("signature"), signedRequest.Signature)
+	request := &snapshotgrpc.SnapshotRequest{}
+	require.NoError(t, proto.Unmarshal(signedRequest.Request, request))
+	require.Equal(t, "testchannel", request.ChannelId)
+	require.Equal(t, uint64(100), request.BlockNumber)
+	require.Equal(t, []byte("creator"), request.SignatureHeader.Creator)
+}
+
+func TestCancelRequestCmdErrors(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSnapshotClient := &mock.SnapshotClient{}
+	client := &snapshot.Client{SnapshotClient: mockSnapshotClient, Signer: mockSigner}
+
+	t.Run("missing channel", func(t *testing.T) {
+		snapshot.ResetFlags()
+		cmd := snapshot.CancelRequestCmd(client)
+		cmd.SetArgs([]string{"-b", "100"})
+		require.EqualError(t, cmd.Execute(), "the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	})
+
+	t.Run("missing block number", func(t *testing.T) {
+		snapshot.ResetFlags()
+		cmd := snapshot.CancelRequestCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "the required parameter 'blockNumber' is empty or set to 0. Rerun the command with -b flag")
+	})
+
+	t.Run("snapshot service error", func(t *testing.T) {
+		mockSnapshotClient.CancelReturns(nil, errors.New("no snapshot request exists"))
+		snapshot.ResetFlags()
+		cmd := snapshot.CancelRequestCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel", "-b", "100"})
+		require.EqualError(t, cmd.Execute(), "failed to cancel snapshot request: no snapshot request exists")
+	})
+}
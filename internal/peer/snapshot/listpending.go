@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ListPendingCmd returns the cobra command for listing the pending snapshot requests
+func ListPendingCmd(cl *Client) *cobra.Command {
+	snapshotListPendingCmd := &cobra.Command{
+		Use:   "listpending",
+		Short: "List pending requests for snapshots.",
+		Long:  "List pending requests for snapshots.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listPending(cmd, cl, os.Stdout)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"peerAddress",
+		"tlsRootCertFile",
+	}
+	attachFlags(snapshotListPendingCmd, flagList)
+
+	return snapshotListPendingCmd
+}
+
+func listPending(cmd *cobra.Command, cl *Client, out io.Writer) error {
+	if err := validateInput(); err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cl == nil {
+		var err error
+		if cl, err = NewClient(); err != nil {
+			return err
+		}
+	}
+
+	signatureHdr, err := newSignatureHeader(cl.Signer)
+	if err != nil {
+		return err
+	}
+	query := &snapshotgrpc.SnapshotQuery{
+		SignatureHeader: signatureHdr,
+		ChannelId:       channelID,
+	}
+	signedRequest, err := signSnapshotRequest(cl.Signer, query)
+	if err != nil {
+		return err
+	}
+
+	resp, err := cl.SnapshotClient.QueryPendings(context.Background(), signedRequest)
+	if err != nil {
+		return errors.WithMessage(err, "failed to list pending snapshot requests")
+	}
+
+	fmt.Fprintf(out, "Successfully got pending snapshot requests: %v\n", resp.BlockNumbers)
+	return nil
+}
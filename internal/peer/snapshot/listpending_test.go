@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/internal/peer/snapshot"
+	"github.com/hyperledger/fabric/internal/peer/snapshot/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPendingCmd(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSigner.SerializeReturns([]byte("creator"), nil)
+	mockSigner.SignReturns([]byte("signature"), nil)
+	mockSnapshotClient := &mock.SnapshotClient{}
+	mockSnapshotClient.QueryPendingsReturns(&snapshotgrpc.QueryPendingSnapshotsResponse{BlockNumbers: []uint64{10, 20}}, nil)
+	client := &snapshot.Client{SnapshotClient: mockSnapshotClient, Signer: mockSigner}
+
+	snapshot.ResetFlags()
+	cmd := snapshot.ListPendingCmd(client)
+	cmd.SetArgs([]string{"-c", "testchannel"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, 1, mockSnapshotClient.QueryPendingsCallCount())
+	_, signedRequest, _ := mockSnapshotClient.QueryPendingsArgsForCall(0)
+	query := &snapshotgrpc.SnapshotQuery{}
+	require.NoError(t, proto.Unmarshal(signedRequest.Request, query))
+	require.Equal(t, "testchannel", query.ChannelId)
+	require.Equal(t, []byte("creator"), query.SignatureHeader.Creator)
+}
+
+func TestListPendingCmdErrors(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSnapshotClient := &mock.SnapshotClient{}
+	client := &snapshot.Client{SnapshotClient: mockSnapshotClient, Signer: mockSigner}
+
+	t.Run("missing channel", func(t *testing.T) {
+		snapshot.ResetFlags()
+		cmd := snapshot.ListPendingCmd(client)
+		cmd.SetArgs([]string{})
+		require.EqualError(t, cmd.Execute(), "the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	})
+
+	t.Run("snapshot service error", func(t *testing.T) {
+		mockSnapshotClient.QueryPendingsReturns(nil, errors.New("leveldb error"))
+		snapshot.ResetFlags()
+		cmd := snapshot.ListPendingCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to list pending snapshot requests: leveldb error")
+	})
+}
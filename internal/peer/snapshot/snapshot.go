@@ -0,0 +1,140 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var logger = flogging.MustGetLogger("cli.snapshot")
+
+// Cmd returns the cobra command for snapshot
+func Cmd() *cobra.Command {
+	snapshotCmd.AddCommand(SubmitRequestCmd(nil))
+	snapshotCmd.AddCommand(CancelRequestCmd(nil))
+	snapshotCmd.AddCommand(ListPendingCmd(nil))
+
+	return snapshotCmd
+}
+
+// Snapshot-related variables.
+var (
+	channelID       string
+	blockNumber     uint64
+	peerAddress     string
+	tlsRootCertFile string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Manage the snapshot requests of a peer: submitrequest|cancelrequest|listpending",
+	Long:  "Manage the snapshot requests of a peer: submitrequest|cancelrequest|listpending",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		common.InitCmd(cmd, args)
+	},
+}
+
+var flags *pflag.FlagSet
+
+func init() {
+	ResetFlags()
+}
+
+// ResetFlags resets the values of these flags to facilitate tests
+func ResetFlags() {
+	flags = &pflag.FlagSet{}
+
+	flags.StringVarP(&channelID, "channelID", "c", "", "The channel on which this command should be executed")
+	flags.Uint64VarP(&blockNumber, "blockNumber", "b", 0, "The block number for which a snapshot is requested")
+	flags.StringVarP(&peerAddress, "peerAddress", "", "", "The address of the peer to connect to")
+	flags.StringVarP(&tlsRootCertFile, "tlsRootCertFile", "", "",
+		"The path to the TLS root cert file of the peer to connect to, required if TLS is enabled and ignored if TLS is disabled.")
+}
+
+func attachFlags(cmd *cobra.Command, names []string) {
+	cmdFlags := cmd.Flags()
+	for _, name := range names {
+		if flag := flags.Lookup(name); flag != nil {
+			cmdFlags.AddFlag(flag)
+		} else {
+			logger.Fatalf("Could not find flag '%s' to attach to command '%s'", name, cmd.Name())
+		}
+	}
+}
+
+// Client holds the dependencies needed to send a snapshot request to a peer
+type Client struct {
+	SnapshotClient snapshotgrpc.SnapshotClient
+	Signer         identity.SignerSerializer
+}
+
+// NewClient creates a client for the snapshot service of the peer set by the
+// peerAddress flag, signing the requests with the default signer
+func NewClient() (*Client, error) {
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to retrieve default signer")
+	}
+	peerClient, err := common.NewPeerClientForAddress(peerAddress, tlsRootCertFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create peer client")
+	}
+	snapshotClient, err := peerClient.Snapshot()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create snapshot client")
+	}
+	return &Client{
+		SnapshotClient: snapshotClient,
+		Signer:         signer,
+	}, nil
+}
+
+func validateInput() error {
+	if channelID == "" {
+		return errors.New("the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	}
+	return nil
+}
+
+func newSignatureHeader(signer identity.SignerSerializer) (*cb.SignatureHeader, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate nonce")
+	}
+	return &cb.SignatureHeader{
+		Creator: creator,
+		Nonce:   nonce,
+	}, nil
+}
+
+func signSnapshotRequest(signer identity.SignerSerializer, request proto.Message) (*snapshotgrpc.SignedSnapshotRequest, error) {
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal snapshot request")
+	}
+	signature, err := signer.Sign(requestBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign snapshot request")
+	}
+	return &snapshotgrpc.SignedSnapshotRequest{
+		Request:   requestBytes,
+		Signature: signature,
+	}, nil
+}
@@ -0,0 +1,22 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot_test
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+)
+
+//go:generate counterfeiter -o mock/snapshot_client.go -fake-name SnapshotClient . snapshotClient
+type snapshotClient interface {
+	snapshotgrpc.SnapshotClient
+}
+
+//go:generate counterfeiter -o mock/signer.go -fake-name Signer . signer
+type signer interface {
+	identity.SignerSerializer
+}
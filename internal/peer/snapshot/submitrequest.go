@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// SubmitRequestCmd returns the cobra command for submitting a snapshot request
+func SubmitRequestCmd(cl *Client) *cobra.Command {
+	snapshotSubmitRequestCmd := &cobra.Command{
+		Use:   "submitrequest",
+		Short: "Submit a request for a snapshot at the specified block.",
+		Long: "Submit a request for a snapshot at the specified block. When the blockNumber parameter is set to 0 or not provided, " +
+			"the snapshot is generated at the last committed block.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return submitRequest(cmd, cl, os.Stdout)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"blockNumber",
+		"peerAddress",
+		"tlsRootCertFile",
+	}
+	attachFlags(snapshotSubmitRequestCmd, flagList)
+
+	return snapshotSubmitRequestCmd
+}
+
+func submitRequest(cmd *cobra.Command, cl *Client, out io.Writer) error {
+	if err := validateInput(); err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cl == nil {
+		var err error
+		if cl, err = NewClient(); err != nil {
+			return err
+		}
+	}
+
+	signatureHdr, err := newSignatureHeader(cl.Signer)
+	if err != nil {
+		return err
+	}
+	request := &snapshotgrpc.SnapshotRequest{
+		SignatureHeader: signatureHdr,
+		ChannelId:       channelID,
+		BlockNumber:     blockNumber,
+	}
+	signedRequest, err := signSnapshotRequest(cl.Signer, request)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cl.SnapshotClient.Generate(context.Background(), signedRequest); err != nil {
+		return errors.WithMessage(err, "failed to submit snapshot request")
+	}
+
+	fmt.Fprint(out, "Snapshot request submitted successfully\n")
+	return nil
+}
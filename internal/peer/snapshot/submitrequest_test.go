@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/internal/peer/snapshot"
+	"github.com/hyperledger/fabric/internal/peer/snapshot/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitRequestCmd(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSigner.SerializeReturns([]byte("creator"), nil)
+	mockSigner.SignReturns([]byte("signature"), nil)
+	mockSnapshotClient := &mock.SnapshotClient{}
+	client := &snapshot.Client{SnapshotClient: mockSnapshotClient, Signer: mockSigner}
+
+	snapshot.ResetFlags()
+	cmd := snapshot.SubmitRequestCmd(client)
+	cmd.SetArgs([]string{"-c", "testchannel", "-b", "100"})
+	require.NoError(t, cmd.Execute())
+
+	require.Equal(t, 1, mockSnapshotClient.GenerateCallCount())
+	_, signedRequest, _ := mockSnapshotClient.GenerateArgsForCall(0)
+	require.Equal(t, []byte("signature"), signedRequest.Signature)
+	require.Equal(t, signedRequest.Request, mockSigner.SignArgsForCall(0))
+	request := &snapshotgrpc.SnapshotRequest{}
+	require.NoError(t, proto.Unmarshal(signedRequest.Request, request))
+	require.Equal(t, "testchannel", request.ChannelId)
+	require.Equal(t, uint64(100), request.BlockNumber)
+	require.Equal(t, []byte("creator"), request.SignatureHeader.Creator)
+	require.NotEmpty(t, request.SignatureHeader.Nonce)
+}
+
+func TestSubmitRequestCmdErrors(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSnapshotClient := &mock.SnapshotClient{}
+	client := &snapshot.Client{SnapshotClient: mockSnapshotClient, Signer: mockSigner}
+
+	t.Run("missing channel", func(t *testing.T) {
+		snapshot.ResetFlags()
+		cmd := snapshot.SubmitRequestCmd(client)
+		cmd.SetArgs([]string{"-b", "100"})
+		require.EqualError(t, cmd.Execute(), "the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	})
+
+	t.Run("serialize error", func(t *testing.T) {
+		mockSigner.SerializeReturns(nil, errors.New("serialize error"))
+		defer mockSigner.SerializeReturns([]byte("creator"), nil)
+		snapshot.ResetFlags()
+		cmd := snapshot.SubmitRequestCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to serialize identity: serialize error")
+	})
+
+	t.Run("sign error", func(t *testing.T) {
+		mockSigner.SignReturns(nil, errors.New("sign error"))
+		defer mockSigner.SignReturns([]byte("signature"), nil)
+		snapshot.ResetFlags()
+		cmd := snapshot.SubmitRequestCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to sign snapshot request: sign error")
+	})
+
+	t.Run("snapshot service error", func(t *testing.T) {
+		mockSnapshotClient.GenerateReturns(nil, errors.New("duplicate snapshot request"))
+		snapshot.ResetFlags()
+		cmd := snapshot.SubmitRequestCmd(client)
+		cmd.SetArgs([]string{"-c", "testchannel"})
+		require.EqualError(t, cmd.Execute(), "failed to submit snapshot request: duplicate snapshot request")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/channelconfig"
 )
 
@@ -30,6 +31,16 @@ type OrdererConfig struct {
 	batchTimeoutReturnsOnCall map[int]struct {
 		result1 time.Duration
 	}
+	BlockExtensionLimitsStub        func() *blockextension.ExtensionLimits
+	blockExtensionLimitsMutex       sync.RWMutex
+	blockExtensionLimitsArgsForCall []struct {
+	}
+	blockExtensionLimitsReturns struct {
+		result1 *blockextension.ExtensionLimits
+	}
+	blockExtensionLimitsReturnsOnCall map[int]struct {
+		result1 *blockextension.ExtensionLimits
+	}
 	CapabilitiesStub        func() channelconfig.OrdererCapabilities
 	capabilitiesMutex       sync.RWMutex
 	capabilitiesArgsForCall []struct {
@@ -208,6 +219,58 @@ func (fake *OrdererConfig) BatchTimeoutReturnsOnCall(i int, result1 time.Duratio
 	}{result1}
 }
 
+func (fake *OrdererConfig) BlockExtensionLimits() *blockextension.ExtensionLimits {
+	fake.blockExtensionLimitsMutex.Lock()
+	ret, specificReturn := fake.blockExtensionLimitsReturnsOnCall[len(fake.blockExtensionLimitsArgsForCall)]
+	fake.blockExtensionLimitsArgsForCall = append(fake.blockExtensionLimitsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BlockExtensionLimits", []interface{}{})
+	fake.blockExtensionLimitsMutex.Unlock()
+	if fake.BlockExtensionLimitsStub != nil {
+		return fake.BlockExtensionLimitsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.blockExtensionLimitsReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererConfig) BlockExtensionLimitsCallCount() int {
+	fake.blockExtensionLimitsMutex.RLock()
+	defer fake.blockExtensionLimitsMutex.RUnlock()
+	return len(fake.blockExtensionLimitsArgsForCall)
+}
+
+func (fake *OrdererConfig) BlockExtensionLimitsCalls(stub func() *blockextension.ExtensionLimits) {
+	fake.blockExtensionLimitsMutex.Lock()
+	defer fake.blockExtensionLimitsMutex.Unlock()
+	fake.BlockExtensionLimitsStub = stub
+}
+
+func (fake *OrdererConfig) BlockExtensionLimitsReturns(result1 *blockextension.ExtensionLimits) {
+	fake.blockExtensionLimitsMutex.Lock()
+	defer fake.blockExtensionLimitsMutex.Unlock()
+	fake.BlockExtensionLimitsStub = nil
+	fake.blockExtensionLimitsReturns = struct {
+		result1 *blockextension.ExtensionLimits
+	}{result1}
+}
+
+func (fake *OrdererConfig) BlockExtensionLimitsReturnsOnCall(i int, result1 *blockextension.ExtensionLimits) {
+	fake.blockExtensionLimitsMutex.Lock()
+	defer fake.blockExtensionLimitsMutex.Unlock()
+	fake.BlockExtensionLimitsStub = nil
+	if fake.blockExtensionLimitsReturnsOnCall == nil {
+		fake.blockExtensionLimitsReturnsOnCall = make(map[int]struct {
+			result1 *blockextension.ExtensionLimits
+		})
+	}
+	fake.blockExtensionLimitsReturnsOnCall[i] = struct {
+		result1 *blockextension.ExtensionLimits
+	}{result1}
+}
+
 func (fake *OrdererConfig) Capabilities() channelconfig.OrdererCapabilities {
 	fake.capabilitiesMutex.Lock()
 	ret, specificReturn := fake.capabilitiesReturnsOnCall[len(fake.capabilitiesArgsForCall)]
@@ -579,6 +642,8 @@ func (fake *OrdererConfig) Invocations() map[string][][]interface{} {
 	defer fake.batchSizeMutex.RUnlock()
 	fake.batchTimeoutMutex.RLock()
 	defer fake.batchTimeoutMutex.RUnlock()
+	fake.blockExtensionLimitsMutex.RLock()
+	defer fake.blockExtensionLimitsMutex.RUnlock()
 	fake.capabilitiesMutex.RLock()
 	defer fake.capabilitiesMutex.RUnlock()
 	fake.consensusMetadataMutex.RLock()
@@ -611,3 +676,5 @@ func (fake *OrdererConfig) recordInvocation(key string, args []interface{}) {
 	}
 	fake.invocations[key] = append(fake.invocations[key], args)
 }
+
+var _ channelconfig.Orderer = new(OrdererConfig)
@@ -326,6 +326,10 @@ func VerifyBlockHash(indexInBuffer int, blockBuff []*common.Block) error {
 		return errors.Errorf("computed hash of block (%d) (%s) doesn't match claimed hash (%s)",
 			seq, computedHash, claimedHash)
 	}
+	// Verify the block extension matches the extension hash in the metadata, if any
+	if _, err := protoutil.VerifyBlockExtensionHash(block); err != nil {
+		return errors.Wrapf(err, "invalid extension of block (%d)", seq)
+	}
 	// We have a previous block in the buffer, ensure current block's previous hash matches the previous one.
 	if indexInBuffer > 0 {
 		prevBlock := blockBuff[indexInBuffer-1]
@@ -357,6 +361,11 @@ func SignatureSetFromBlock(block *common.Block) ([]*protoutil.SignedData, error)
 		return nil, errors.Errorf("failed unmarshaling medatata for signatures: %v", err)
 	}
 
+	extensionHash, err := protoutil.GetExtensionHashFromBlock(block)
+	if err != nil {
+		return nil, errors.Errorf("failed retrieving extension hash for block with id %d: %v", block.Header.Number, err)
+	}
+
 	var signatureSet []*protoutil.SignedData
 	for _, metadataSignature := range metadata.Signatures {
 		sigHdr, err := protoutil.UnmarshalSignatureHeader(metadataSignature.SignatureHeader)
@@ -368,7 +377,7 @@ func SignatureSetFromBlock(block *common.Block) ([]*protoutil.SignedData, error)
 			&protoutil.SignedData{
 				Identity: sigHdr.Creator,
 				Data: util.ConcatenateBytes(metadata.Value,
-					metadataSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)),
+					metadataSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header), extensionHash),
 				Signature: metadataSignature.Signature,
 			},
 		)
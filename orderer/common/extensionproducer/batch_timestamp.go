@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// BatchTimestamp is the name of the producer of the timestamp of the batch of the block.
+const BatchTimestamp = "BatchTimestamp"
+
+type batchTimestampProducer struct{}
+
+// NewBatchTimestampProducer creates a producer of the timestamp of the batch, which
+// is the latest timestamp among the channel headers of the messages. Unlike the time
+// at which the block is cut, it is the same for every orderer that cuts the block.
+// It takes no options.
+func NewBatchTimestampProducer(options map[string]interface{}) (Producer, error) {
+	if len(options) != 0 {
+		return nil, errors.Errorf("%s takes no options", BatchTimestamp)
+	}
+	return &batchTimestampProducer{}, nil
+}
+
+func (p *batchTimestampProducer) TypeURL() string {
+	return blockextension.BatchTimestampType
+}
+
+func (p *batchTimestampProducer) Version() uint32 {
+	return 1
+}
+
+func (p *batchTimestampProducer) Produce(messages []*cb.Envelope) ([]byte, error) {
+	var latest *timestamp.Timestamp
+	for i, msg := range messages {
+		chdr, err := protoutil.ChannelHeader(msg)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed extracting the channel header of message %d", i)
+		}
+		ts := chdr.Timestamp
+		if ts == nil {
+			continue
+		}
+		if latest == nil || ts.Seconds > latest.Seconds || (ts.Seconds == latest.Seconds && ts.Nanos > latest.Nanos) {
+			latest = ts
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("none of the messages carries a timestamp")
+	}
+	return proto.Marshal(&blockextension.BatchTimestamp{Timestamp: latest})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	"testing"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTimestampProducer(t *testing.T) {
+	p, err := NewBatchTimestampProducer(nil)
+	require.NoError(t, err)
+	assert.Equal(t, blockextension.BatchTimestampType, p.TypeURL())
+	assert.Equal(t, uint32(1), p.Version())
+
+	payload, err := p.Produce([]*cb.Envelope{
+		envelope(t, &cb.ChannelHeader{Timestamp: &timestamp.Timestamp{Seconds: 10, Nanos: 5}}, nil),
+		envelope(t, &cb.ChannelHeader{Timestamp: &timestamp.Timestamp{Seconds: 10, Nanos: 7}}, nil),
+		envelope(t, &cb.ChannelHeader{ChannelId: "mychannel"}, nil),
+		envelope(t, &cb.ChannelHeader{Timestamp: &timestamp.Timestamp{Seconds: 9, Nanos: 9}}, nil),
+	})
+	require.NoError(t, err)
+	ts := &blockextension.BatchTimestamp{}
+	require.NoError(t, proto.Unmarshal(payload, ts))
+	assert.True(t, proto.Equal(&timestamp.Timestamp{Seconds: 10, Nanos: 7}, ts.Timestamp))
+
+	_, err = p.Produce([]*cb.Envelope{envelope(t, &cb.ChannelHeader{ChannelId: "mychannel"}, nil)})
+	assert.EqualError(t, err, "none of the messages carries a timestamp")
+
+	_, err = p.Produce([]*cb.Envelope{{Payload: []byte("garbage")}})
+	assert.Error(t, err)
+}
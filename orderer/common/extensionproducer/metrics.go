@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	extensionSize = metrics.HistogramOpts{
+		Namespace:    "extensionproducer",
+		Name:         "extension_size",
+		Help:         "The total size in bytes of the entries of the extension of the blocks cut.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+		Buckets:      []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576},
+	}
+	extensionEntries = metrics.HistogramOpts{
+		Namespace:    "extensionproducer",
+		Name:         "extension_entries",
+		Help:         "The number of entries of the extension of the blocks cut.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+		Buckets:      []float64{0, 1, 2, 4, 8, 16, 32},
+	}
+	entriesLeftOut = metrics.CounterOpts{
+		Namespace:    "extensionproducer",
+		Name:         "entries_left_out",
+		Help:         "The number of extension entries left out of the blocks cut because of the block extension limits.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+)
+
+type Metrics struct {
+	ExtensionSize    metrics.Histogram
+	ExtensionEntries metrics.Histogram
+	EntriesLeftOut   metrics.Counter
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		ExtensionSize:    p.NewHistogram(extensionSize),
+		ExtensionEntries: p.NewHistogram(extensionEntries),
+		EntriesLeftOut:   p.NewCounter(entriesLeftOut),
+	}
+}
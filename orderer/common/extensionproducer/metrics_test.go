@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetrics(t *testing.T) {
+	provider := &metricsfakes.Provider{}
+	provider.NewHistogramReturns(&metricsfakes.Histogram{})
+	provider.NewCounterReturns(&metricsfakes.Counter{})
+
+	metrics := NewMetrics(provider)
+	assert.Equal(t, &metricsfakes.Histogram{}, metrics.ExtensionSize)
+	assert.Equal(t, &metricsfakes.Histogram{}, metrics.ExtensionEntries)
+	assert.Equal(t, &metricsfakes.Counter{}, metrics.EntriesLeftOut)
+	assert.Equal(t, 2, provider.NewHistogramCallCount())
+	assert.Equal(t, 1, provider.NewCounterCallCount())
+	assert.Equal(t, extensionSize, provider.NewHistogramArgsForCall(0))
+	assert.Equal(t, extensionEntries, provider.NewHistogramArgsForCall(1))
+	assert.Equal(t, entriesLeftOut, provider.NewCounterArgsForCall(0))
+}
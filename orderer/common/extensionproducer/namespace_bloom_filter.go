@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// NamespaceBloomFilter is the name of the producer of a bloom filter of the chaincode
+// namespaces touched by the transactions of the block.
+const NamespaceBloomFilter = "NamespaceBloomFilter"
+
+// BloomFilterOptions are the options of the NamespaceBloomFilter producer.
+type BloomFilterOptions struct {
+	// Bits is the size of the filter, rounded up to a multiple of 8.
+	Bits uint32
+	// HashFunctions is the number of bits set for each namespace.
+	HashFunctions uint32
+}
+
+// DefaultBloomFilterOptions are used for the options that are not configured.
+var DefaultBloomFilterOptions = BloomFilterOptions{
+	Bits:          2048,
+	HashFunctions: 3,
+}
+
+type namespaceBloomFilterProducer struct {
+	options BloomFilterOptions
+}
+
+// NewNamespaceBloomFilterProducer creates a producer of a bloom filter over the chaincode
+// namespaces touched by the transactions of the block. See BloomFilterOptions for the options.
+func NewNamespaceBloomFilterProducer(options map[string]interface{}) (Producer, error) {
+	bfo := DefaultBloomFilterOptions
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           &bfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(options); err != nil {
+		return nil, errors.Wrap(err, "invalid options")
+	}
+	if bfo.Bits == 0 || bfo.HashFunctions == 0 {
+		return nil, errors.New("Bits and HashFunctions must be positive")
+	}
+	return &namespaceBloomFilterProducer{options: bfo}, nil
+}
+
+func (p *namespaceBloomFilterProducer) TypeURL() string {
+	return blockextension.NamespaceBloomFilterType
+}
+
+func (p *namespaceBloomFilterProducer) Version() uint32 {
+	return 1
+}
+
+func (p *namespaceBloomFilterProducer) Produce(messages []*cb.Envelope) ([]byte, error) {
+	filter := blockextension.NewBloomFilter(p.options.Bits, p.options.HashFunctions)
+	for i, msg := range messages {
+		namespaces, err := touchedNamespaces(msg)
+		if err != nil {
+			// The orderer does not validate the content of transactions, hence
+			// malformed ones are left for the peers to invalidate.
+			logger.Debugf("Skipping message %d of the block: %s", i, err)
+			continue
+		}
+		for _, ns := range namespaces {
+			filter.Add(ns)
+		}
+	}
+	return proto.Marshal(filter)
+}
+
+func touchedNamespaces(msg *cb.Envelope) ([]string, error) {
+	payload, err := protoutil.UnmarshalPayload(msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("header not set")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, action := range tx.Actions {
+		_, ccAction, err := protoutil.GetPayloads(action)
+		if err != nil {
+			return nil, err
+		}
+		if ccAction.ChaincodeId != nil {
+			namespaces = append(namespaces, ccAction.ChaincodeId.Name)
+		}
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+			return nil, errors.Wrap(err, "error unmarshaling TxReadWriteSet")
+		}
+		for _, nsRWSet := range txRWSet.NsRwset {
+			namespaces = append(namespaces, nsRWSet.Namespace)
+		}
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	"testing"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func endorserTx(t *testing.T, chaincode string, namespaces ...string) *cb.Envelope {
+	txRWSet := &rwset.TxReadWriteSet{}
+	for _, ns := range namespaces {
+		txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{Namespace: ns})
+	}
+	ccAction := &peer.ChaincodeAction{
+		ChaincodeId: &peer.ChaincodeID{Name: chaincode},
+		Results:     protoutil.MarshalOrPanic(txRWSet),
+	}
+	prp := &peer.ProposalResponsePayload{Extension: protoutil.MarshalOrPanic(ccAction)}
+	cap := &peer.ChaincodeActionPayload{
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: protoutil.MarshalOrPanic(prp),
+		},
+	}
+	tx := &peer.Transaction{
+		Actions: []*peer.TransactionAction{{Payload: protoutil.MarshalOrPanic(cap)}},
+	}
+	chdr := &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}
+	return envelope(t, chdr, protoutil.MarshalOrPanic(tx))
+}
+
+func TestNewNamespaceBloomFilterProducer(t *testing.T) {
+	p, err := NewNamespaceBloomFilterProducer(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, blockextension.NamespaceBloomFilterType, p.TypeURL())
+	assert.Equal(t, uint32(1), p.Version())
+	assert.Equal(t, DefaultBloomFilterOptions, p.(*namespaceBloomFilterProducer).options)
+
+	p, err = NewNamespaceBloomFilterProducer(map[string]interface{}{"Bits": float64(100), "HashFunctions": "5"})
+	assert.NoError(t, err)
+	assert.Equal(t, BloomFilterOptions{Bits: 100, HashFunctions: 5}, p.(*namespaceBloomFilterProducer).options)
+
+	_, err = NewNamespaceBloomFilterProducer(map[string]interface{}{"Foo": 1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid options")
+
+	_, err = NewNamespaceBloomFilterProducer(map[string]interface{}{"Bits": 0})
+	assert.EqualError(t, err, "Bits and HashFunctions must be positive")
+}
+
+func TestNamespaceBloomFilterProducer(t *testing.T) {
+	p, err := NewNamespaceBloomFilterProducer(nil)
+	require.NoError(t, err)
+
+	payload, err := p.Produce([]*cb.Envelope{
+		endorserTx(t, "mycc", "mycc", "lscc"),
+		envelope(t, &cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG)}, []byte("config")),
+		envelope(t, &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}, []byte("garbage")),
+		endorserTx(t, "othercc", "othercc"),
+	})
+	require.NoError(t, err)
+
+	filter := &blockextension.BloomFilter{}
+	require.NoError(t, proto.Unmarshal(payload, filter))
+	assert.Len(t, filter.Bits, 256)
+	assert.Equal(t, uint32(3), filter.HashFunctions)
+	for _, ns := range []string{"mycc", "lscc", "othercc"} {
+		assert.True(t, filter.MayContain(ns), "namespace %s", ns)
+	}
+	assert.False(t, filter.MayContain("absentcc"))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	"sync"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("orderer.common.extensionproducer")
+
+// Producer computes an entry of the extension of the blocks that are cut by the orderer.
+type Producer interface {
+	// TypeURL returns the type URL of the entries computed by the producer.
+	TypeURL() string
+
+	// Version returns the version of the encoding of the payloads computed by the producer.
+	Version() uint32
+
+	// Produce computes the payload of the entry for a block that carries the
+	// given messages.
+	Produce(messages []*cb.Envelope) ([]byte, error)
+}
+
+// Factory creates a Producer out of the options configured for it.
+type Factory func(options map[string]interface{}) (Producer, error)
+
+// ProducerConfig names a registered producer along with its options.
+type ProducerConfig struct {
+	Name    string
+	Options map[string]interface{}
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]Factory{
+		TxIDMerkleRoot:       NewTxIDMerkleRootProducer,
+		NamespaceBloomFilter: NewNamespaceBloomFilterProducer,
+		BatchTimestamp:       NewBatchTimestampProducer,
+	}
+)
+
+// Register makes a producer available under the given name.
+// It panics if a producer with the same name is already registered.
+func Register(name string, factory Factory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, exists := registry[name]; exists {
+		logger.Panicf("block extension producer %s is already registered", name)
+	}
+	registry[name] = factory
+}
+
+// NewProducers creates the producers with the given configuration, in the given order.
+func NewProducers(configs []ProducerConfig) ([]Producer, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	var producers []Producer
+	names := map[string]struct{}{}
+	for _, config := range configs {
+		if _, exists := names[config.Name]; exists {
+			return nil, errors.Errorf("block extension producer %s is configured more than once", config.Name)
+		}
+		names[config.Name] = struct{}{}
+
+		factory, exists := registry[config.Name]
+		if !exists {
+			return nil, errors.Errorf("unknown block extension producer %s", config.Name)
+		}
+		producer, err := factory(config.Options)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed creating block extension producer %s", config.Name)
+		}
+		producers = append(producers, producer)
+	}
+	return producers, nil
+}
+
+// Extend runs the producers over the messages of the block and adds their entries to the
+// extension of the block, in the order of the producers. The entries do not carry the identity
+// of the orderer that cuts the block, as every orderer must produce the same extension, and
+// hence the same extension hash, for a block. As the block must be cut regardless, a producer that fails
+// contributes an entry that carries the error in place of the payload, so that the blocks
+// carry the same type URLs whether or not their producers fail. An entry that would make
+// the extension exceed the given limits is left out. Extend returns the number of entries
+// left out because of the limits.
+func Extend(block *cb.Block, producers []Producer, messages []*cb.Envelope, limits *blockextension.ExtensionLimits) int {
+	leftOut := 0
+	for _, producer := range producers {
+		entry := &blockextension.Entry{
+			TypeUrl: producer.TypeURL(),
+			Version: producer.Version(),
+		}
+		payload, err := producer.Produce(messages)
+		if err != nil {
+			logger.Warningf("Block extension producer of %s failed on block [%d]: %s", producer.TypeURL(), block.Header.Number, err)
+			entry.Error = err.Error()
+		} else {
+			entry.Payload = payload
+		}
+		if err := protoutil.AddBlockExtensionEntry(block, entry); err != nil {
+			logger.Panicf("Could not add block extension entry: %s", err)
+		}
+		if err := protoutil.VerifyBlockExtensionLimits(block, limits); err != nil {
+			block.Extension.ExtensionData = block.Extension.ExtensionData[:len(block.Extension.ExtensionData)-1]
+			logger.Warningf("Block extension entry of %s is left out of block [%d]: %s", producer.TypeURL(), block.Header.Number, err)
+			leftOut++
+		}
+	}
+	return leftOut
+}
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	"testing"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProducer struct {
+	typeURL string
+	payload []byte
+	err     error
+}
+
+func (p *testProducer) TypeURL() string {
+	return p.typeURL
+}
+
+func (p *testProducer) Version() uint32 {
+	return 2
+}
+
+func (p *testProducer) Produce(messages []*cb.Envelope) ([]byte, error) {
+	return p.payload, p.err
+}
+
+func envelope(t *testing.T, chdr *cb.ChannelHeader, data []byte) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(chdr),
+			},
+			Data: data,
+		}),
+	}
+}
+
+func TestNewProducers(t *testing.T) {
+	producers, err := NewProducers(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, producers)
+
+	producers, err = NewProducers([]ProducerConfig{
+		{Name: BatchTimestamp},
+		{Name: TxIDMerkleRoot},
+		{Name: NamespaceBloomFilter, Options: map[string]interface{}{"Bits": 64}},
+	})
+	assert.NoError(t, err)
+	require.Len(t, producers, 3)
+	assert.Equal(t, blockextension.BatchTimestampType, producers[0].TypeURL())
+	assert.Equal(t, blockextension.TxIDMerkleRootType, producers[1].TypeURL())
+	assert.Equal(t, blockextension.NamespaceBloomFilterType, producers[2].TypeURL())
+
+	_, err = NewProducers([]ProducerConfig{{Name: "Unknown"}})
+	assert.EqualError(t, err, "unknown block extension producer Unknown")
+
+	_, err = NewProducers([]ProducerConfig{{Name: TxIDMerkleRoot}, {Name: TxIDMerkleRoot}})
+	assert.EqualError(t, err, "block extension producer TxIDMerkleRoot is configured more than once")
+
+	_, err = NewProducers([]ProducerConfig{{Name: TxIDMerkleRoot, Options: map[string]interface{}{"Foo": "bar"}}})
+	assert.EqualError(t, err, "failed creating block extension producer TxIDMerkleRoot: TxIDMerkleRoot takes no options")
+}
+
+func TestRegister(t *testing.T) {
+	Register("TestProducer", func(options map[string]interface{}) (Producer, error) {
+		return &testProducer{typeURL: "test/TestProducer", payload: []byte("payload")}, nil
+	})
+	defer func() {
+		registryLock.Lock()
+		delete(registry, "TestProducer")
+		registryLock.Unlock()
+	}()
+
+	producers, err := NewProducers([]ProducerConfig{{Name: "TestProducer"}})
+	assert.NoError(t, err)
+	require.Len(t, producers, 1)
+	assert.Equal(t, "test/TestProducer", producers[0].TypeURL())
+
+	assert.Panics(t, func() {
+		Register(TxIDMerkleRoot, NewTxIDMerkleRootProducer)
+	})
+}
+
+func TestExtend(t *testing.T) {
+	block := protoutil.NewBlock(0, nil)
+	assert.Zero(t, Extend(block, nil, nil, nil))
+	assert.Empty(t, block.Extension.ExtensionData)
+
+	leftOut := Extend(block, []Producer{
+		&testProducer{typeURL: "test/first", payload: []byte("foo")},
+		&testProducer{typeURL: "test/failing", err: errors.New("oops")},
+		&testProducer{typeURL: "test/second", payload: []byte("bar")},
+	}, nil, &blockextension.ExtensionLimits{})
+	assert.Zero(t, leftOut)
+	require.Len(t, block.Extension.ExtensionData, 3)
+
+	entry, err := protoutil.UnmarshalBlockExtensionEntry(block.Extension.ExtensionData[0])
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&blockextension.Entry{
+		TypeUrl: "test/first",
+		Version: 2,
+		Payload: []byte("foo"),
+	}, entry))
+	entry, err = protoutil.UnmarshalBlockExtensionEntry(block.Extension.ExtensionData[1])
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&blockextension.Entry{
+		TypeUrl: "test/failing",
+		Version: 2,
+		Error:   "oops",
+	}, entry))
+	entry, err = protoutil.UnmarshalBlockExtensionEntry(block.Extension.ExtensionData[2])
+	require.NoError(t, err)
+	assert.Equal(t, "test/second", entry.TypeUrl)
+	assert.Equal(t, []byte("bar"), entry.Payload)
+}
+
+func TestExtendWithinLimits(t *testing.T) {
+	producers := []Producer{
+		&testProducer{typeURL: "test/first", payload: []byte("foo")},
+		&testProducer{typeURL: "test/large", payload: make([]byte, 100)},
+		&testProducer{typeURL: "test/second", payload: []byte("bar")},
+		&testProducer{typeURL: "test/third", payload: []byte("baz")},
+	}
+
+	t.Run("max bytes", func(t *testing.T) {
+		block := protoutil.NewBlock(0, nil)
+		leftOut := Extend(block, producers, nil, &blockextension.ExtensionLimits{MaxBytes: 100})
+		assert.Equal(t, 1, leftOut)
+		require.Len(t, block.Extension.ExtensionData, 3)
+		assert.Len(t, protoutil.FindBlockExtensionEntries(block.Extension, "test/large"), 0)
+		assert.LessOrEqual(t, protoutil.BlockExtensionSize(block.Extension), 100)
+	})
+
+	t.Run("max entries", func(t *testing.T) {
+		block := protoutil.NewBlock(0, nil)
+		leftOut := Extend(block, producers, nil, &blockextension.ExtensionLimits{MaxEntries: 2})
+		assert.Equal(t, 2, leftOut)
+		require.Len(t, block.Extension.ExtensionData, 2)
+		assert.Len(t, protoutil.FindBlockExtensionEntries(block.Extension, "test/first"), 1)
+		assert.Len(t, protoutil.FindBlockExtensionEntries(block.Extension, "test/large"), 1)
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// TxIDMerkleRoot is the name of the producer of the Merkle root over the
+// transaction IDs of the block.
+const TxIDMerkleRoot = "TxIDMerkleRoot"
+
+type txIDMerkleRootProducer struct{}
+
+// NewTxIDMerkleRootProducer creates a producer of the Merkle root over the
+// transaction IDs of the block. It takes no options.
+func NewTxIDMerkleRootProducer(options map[string]interface{}) (Producer, error) {
+	if len(options) != 0 {
+		return nil, errors.Errorf("%s takes no options", TxIDMerkleRoot)
+	}
+	return &txIDMerkleRootProducer{}, nil
+}
+
+func (p *txIDMerkleRootProducer) TypeURL() string {
+	return blockextension.TxIDMerkleRootType
+}
+
+func (p *txIDMerkleRootProducer) Version() uint32 {
+	return 1
+}
+
+func (p *txIDMerkleRootProducer) Produce(messages []*cb.Envelope) ([]byte, error) {
+	txIDs := make([]string, len(messages))
+	for i, msg := range messages {
+		chdr, err := protoutil.ChannelHeader(msg)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed extracting the channel header of message %d", i)
+		}
+		txIDs[i] = chdr.TxId
+	}
+	return proto.Marshal(&blockextension.MerkleRoot{
+		Root: blockextension.TxIDMerkleRoot(txIDs),
+	})
+}
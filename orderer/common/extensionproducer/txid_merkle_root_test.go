@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensionproducer
+
+import (
+	"testing"
+
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/blockextension"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxIDMerkleRootProducer(t *testing.T) {
+	p, err := NewTxIDMerkleRootProducer(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, blockextension.TxIDMerkleRootType, p.TypeURL())
+	assert.Equal(t, uint32(1), p.Version())
+
+	payload, err := p.Produce([]*cb.Envelope{
+		envelope(t, &cb.ChannelHeader{TxId: "tx1"}, nil),
+		envelope(t, &cb.ChannelHeader{TxId: "tx2"}, nil),
+	})
+	require.NoError(t, err)
+	root := &blockextension.MerkleRoot{}
+	require.NoError(t, proto.Unmarshal(payload, root))
+	assert.Equal(t, blockextension.TxIDMerkleRoot([]string{"tx1", "tx2"}), root.Root)
+
+	_, err = p.Produce([]*cb.Envelope{{Payload: []byte("garbage")}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed extracting the channel header of message 0")
+}
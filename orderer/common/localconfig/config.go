@@ -39,6 +39,7 @@ type TopLevel struct {
 	Operations           Operations
 	Metrics              Metrics
 	ChannelParticipation ChannelParticipation
+	BlockExtensions      BlockExtensions
 }
 
 // General contains config which should be common among all orderer types.
@@ -217,6 +218,17 @@ type ChannelParticipation struct {
 	RemoveStorage bool // Whether to permanently remove storage on channel removal.
 }
 
+// BlockExtensions configures the producers of the extension of the blocks cut by the orderer.
+type BlockExtensions struct {
+	Producers []BlockExtensionProducer
+}
+
+// BlockExtensionProducer names a block extension producer along with its options.
+type BlockExtensionProducer struct {
+	Name    string
+	Options map[string]interface{}
+}
+
 // Defaults carries the default orderer configuration values.
 var Defaults = TopLevel{
 	General: General{
@@ -271,3 +271,39 @@ func TestChannelParticipationDefaults(t *testing.T) {
 	assert.Equal(t, cfg.ChannelParticipation.Enabled, Defaults.ChannelParticipation.Enabled)
 	assert.Equal(t, cfg.ChannelParticipation.RemoveStorage, Defaults.ChannelParticipation.RemoveStorage)
 }
+
+func TestBlockExtensionsConfig(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.Nil(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(name)
+
+	content := `---
+BlockExtensions:
+  Producers:
+    - Name: TxIDMerkleRoot
+    - Name: NamespaceBloomFilter
+      Options:
+        Bits: 4096
+        HashFunctions: 4
+`
+
+	err = ioutil.WriteFile(filepath.Join(name, "orderer.yaml"), []byte(content), 0600)
+	assert.NoError(t, err, "Error creating file: %s", err)
+
+	os.Setenv("FABRIC_CFG_PATH", name)
+	defer os.Unsetenv("FABRIC_CFG_PATH")
+
+	cc := &configCache{}
+	conf, err := cc.load()
+	assert.NoError(t, err, "Load good config returned unexpected error")
+	assert.Equal(t, []BlockExtensionProducer{
+		{Name: "TxIDMerkleRoot"},
+		{
+			Name: "NamespaceBloomFilter",
+			Options: map[string]interface{}{
+				"Bits":          float64(4096),
+				"HashFunctions": float64(4),
+			},
+		},
+	}, conf.BlockExtensions.Producers)
+}
@@ -9,13 +9,14 @@ package multichannel
 import (
 	"sync"
 
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
-	cb "github.com/hyperledger/fabric-protos-go/common"
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/extensionproducer"
 	"github.com/hyperledger/fabric/protoutil"
 )
 
@@ -26,6 +27,7 @@ type blockWriterSupport interface {
 	Update(*newchannelconfig.Bundle)
 	CreateBundle(channelID string, config *cb.Config) (*newchannelconfig.Bundle, error)
 	SharedConfig() newchannelconfig.Orderer
+	ChannelConfig() newchannelconfig.Channel
 }
 
 // BlockWriter efficiently writes the blockchain to disk.
@@ -38,15 +40,19 @@ type BlockWriter struct {
 	lastConfigBlockNum uint64
 	lastConfigSeq      uint64
 	lastBlock          *cb.Block
+	extensionProducers []extensionproducer.Producer
+	extensionMetrics   *extensionproducer.Metrics
 	committingBlock    sync.Mutex
 }
 
-func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSupport) *BlockWriter {
+func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSupport, extensionProducers []extensionproducer.Producer, extensionMetrics *extensionproducer.Metrics) *BlockWriter {
 	bw := &BlockWriter{
-		support:       support,
-		lastConfigSeq: support.Sequence(),
-		lastBlock:     lastBlock,
-		registrar:     r,
+		support:            support,
+		lastConfigSeq:      support.Sequence(),
+		lastBlock:          lastBlock,
+		registrar:          r,
+		extensionProducers: extensionProducers,
+		extensionMetrics:   extensionMetrics,
 	}
 
 	// If this is the genesis block, the lastconfig field may be empty, and, the last config block is necessarily block 0
@@ -83,6 +89,18 @@ func (bw *BlockWriter) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
 	block.Header.DataHash = protoutil.BlockDataHash(data)
 	block.Data = data
 
+	if len(bw.extensionProducers) > 0 {
+		limits := bw.support.SharedConfig().BlockExtensionLimits()
+		leftOut := extensionproducer.Extend(block, bw.extensionProducers, messages, limits)
+
+		channelID := bw.support.ChannelID()
+		bw.extensionMetrics.ExtensionSize.With("channel", channelID).Observe(float64(protoutil.BlockExtensionSize(block.Extension)))
+		bw.extensionMetrics.ExtensionEntries.With("channel", channelID).Observe(float64(len(block.Extension.ExtensionData)))
+		if leftOut > 0 {
+			bw.extensionMetrics.EntriesLeftOut.With("channel", channelID).Add(float64(leftOut))
+		}
+	}
+
 	return block
 }
 
@@ -198,9 +216,18 @@ func (bw *BlockWriter) addBlockSignature(block *cb.Block, consenterMetadata []by
 		ConsenterMetadata: protoutil.MarshalOrPanic(&cb.Metadata{Value: consenterMetadata}),
 	})
 
+	// Once all the nodes of the channel are capable of verifying it, the hash of the block
+	// extension is recorded in the metadata and covered by the signature, so that the
+	// extension cannot be altered without invalidating the block signature.
+	var extensionHash []byte
+	if bw.support.ChannelConfig().Capabilities().BlockExtensionHash() {
+		protoutil.SetExtensionHashInBlock(block)
+		extensionHash = protoutil.BlockExtensionHash(block.Extension)
+	}
+
 	blockSignature.Signature = protoutil.SignOrPanic(
 		bw.support,
-		util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)),
+		util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header), extensionHash),
 	)
 
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
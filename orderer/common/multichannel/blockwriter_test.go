@@ -16,17 +16,21 @@ import (
 	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
+	"github.com/hyperledger/fabric/orderer/common/extensionproducer"
 	"github.com/hyperledger/fabric/orderer/common/multichannel/mocks"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
@@ -43,8 +47,9 @@ type mockBlockWriterSupport struct {
 	*mocks.ConfigTXValidator
 	identity.SignerSerializer
 	blockledger.ReadWriter
-	fakeConfig *mock.OrdererConfig
-	bccsp      bccsp.BCCSP
+	fakeConfig        *mock.OrdererConfig
+	fakeChannelConfig *mocks.ChannelConfig
+	bccsp             bccsp.BCCSP
 }
 
 func (mbws mockBlockWriterSupport) Update(bundle *newchannelconfig.Bundle) {}
@@ -57,6 +62,15 @@ func (mbws mockBlockWriterSupport) SharedConfig() newchannelconfig.Orderer {
 	return mbws.fakeConfig
 }
 
+func (mbws mockBlockWriterSupport) ChannelConfig() newchannelconfig.Channel {
+	if mbws.fakeChannelConfig == nil {
+		fakeChannelConfig := &mocks.ChannelConfig{}
+		fakeChannelConfig.CapabilitiesReturns(&mocks.ChannelCapabilities{})
+		return fakeChannelConfig
+	}
+	return mbws.fakeChannelConfig
+}
+
 func TestCreateBlock(t *testing.T) {
 	seedBlock := protoutil.NewBlock(7, []byte("lasthash"))
 	seedBlock.Data.Data = [][]byte{[]byte("somebytes")}
@@ -71,6 +85,111 @@ func TestCreateBlock(t *testing.T) {
 	assert.Equal(t, protoutil.BlockHeaderHash(seedBlock.Header), block.Header.PreviousHash)
 }
 
+func TestCreateBlockWithExtension(t *testing.T) {
+	producers, err := extensionproducer.NewProducers([]extensionproducer.ProducerConfig{
+		{Name: extensionproducer.TxIDMerkleRoot},
+	})
+	require.NoError(t, err)
+
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{TxId: "tx1"}),
+			},
+		}),
+	}
+	signer := mockCrypto()
+	fakeConfig := &mock.OrdererConfig{}
+	mockValidator := &mocks.ConfigTXValidator{}
+	mockValidator.ChannelIDReturns("testchannelid")
+	extensionSize := &metricsfakes.Histogram{}
+	extensionSize.WithReturns(extensionSize)
+	extensionEntries := &metricsfakes.Histogram{}
+	extensionEntries.WithReturns(extensionEntries)
+	entriesLeftOut := &metricsfakes.Counter{}
+	entriesLeftOut.WithReturns(entriesLeftOut)
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  signer,
+			ConfigTXValidator: mockValidator,
+			fakeConfig:        fakeConfig,
+		},
+		lastBlock:          protoutil.NewBlock(7, nil),
+		extensionProducers: producers,
+		extensionMetrics: &extensionproducer.Metrics{
+			ExtensionSize:    extensionSize,
+			ExtensionEntries: extensionEntries,
+			EntriesLeftOut:   entriesLeftOut,
+		},
+	}
+	block := bw.CreateNextBlock([]*cb.Envelope{env})
+	require.Equal(t, 1, extensionSize.ObserveCallCount())
+	assert.Equal(t, float64(protoutil.BlockExtensionSize(block.Extension)), extensionSize.ObserveArgsForCall(0))
+	assert.Equal(t, []string{"channel", "testchannelid"}, extensionSize.WithArgsForCall(0))
+	require.Equal(t, 1, extensionEntries.ObserveCallCount())
+	assert.Equal(t, float64(1), extensionEntries.ObserveArgsForCall(0))
+	assert.Equal(t, 0, entriesLeftOut.AddCallCount())
+
+	entries := protoutil.FindBlockExtensionEntries(block.Extension, blockextension.TxIDMerkleRootType)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint32(1), entries[0].Version)
+	assert.Empty(t, entries[0].Producer)
+	root := &blockextension.MerkleRoot{}
+	require.NoError(t, proto.Unmarshal(entries[0].Payload, root))
+	assert.Equal(t, blockextension.TxIDMerkleRoot([]string{"tx1"}), root.Root)
+
+	// another orderer cuts the same block with the same extension hash
+	otherSigner := mockCrypto()
+	otherSigner.SerializeReturns([]byte("other-orderer"), nil)
+	otherBW := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  otherSigner,
+			ConfigTXValidator: mockValidator,
+			fakeConfig:        fakeConfig,
+		},
+		lastBlock:          protoutil.NewBlock(7, nil),
+		extensionProducers: producers,
+		extensionMetrics:   bw.extensionMetrics,
+	}
+	otherBlock := otherBW.CreateNextBlock([]*cb.Envelope{env})
+	assert.Equal(t, protoutil.BlockExtensionHash(block.Extension), protoutil.BlockExtensionHash(otherBlock.Extension))
+}
+
+func TestCreateBlockWithExtensionLimits(t *testing.T) {
+	producers, err := extensionproducer.NewProducers([]extensionproducer.ProducerConfig{
+		{Name: extensionproducer.TxIDMerkleRoot},
+		{Name: extensionproducer.BatchTimestamp},
+	})
+	require.NoError(t, err)
+
+	fakeConfig := &mock.OrdererConfig{}
+	fakeConfig.BlockExtensionLimitsReturns(&blockextension.ExtensionLimits{MaxEntries: 1})
+	mockValidator := &mocks.ConfigTXValidator{}
+	mockValidator.ChannelIDReturns("testchannelid")
+	entriesLeftOut := &metricsfakes.Counter{}
+	entriesLeftOut.WithReturns(entriesLeftOut)
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  mockCrypto(),
+			ConfigTXValidator: mockValidator,
+			fakeConfig:        fakeConfig,
+		},
+		lastBlock:          protoutil.NewBlock(7, nil),
+		extensionProducers: producers,
+		extensionMetrics: &extensionproducer.Metrics{
+			ExtensionSize:    &disabled.Histogram{},
+			ExtensionEntries: &disabled.Histogram{},
+			EntriesLeftOut:   entriesLeftOut,
+		},
+	}
+	block := bw.CreateNextBlock(nil)
+
+	require.Len(t, block.Extension.ExtensionData, 1)
+	assert.Len(t, protoutil.FindBlockExtensionEntries(block.Extension, blockextension.TxIDMerkleRootType), 1)
+	require.Equal(t, 1, entriesLeftOut.AddCallCount())
+	assert.Equal(t, float64(1), entriesLeftOut.AddArgsForCall(0))
+}
+
 func TestBlockSignature(t *testing.T) {
 	dir, err := ioutil.TempDir("", "file-ledger")
 	require.NoError(t, err)
@@ -113,6 +232,58 @@ func TestBlockSignature(t *testing.T) {
 	assert.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestBlockSignatureWithExtensionHash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rlf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+
+	l, err := rlf.GetOrCreate("mychannel")
+	assert.NoError(t, err)
+	lastBlock := protoutil.NewBlock(0, nil)
+	l.Append(lastBlock)
+
+	fakeChannelCapabilities := &mocks.ChannelCapabilities{}
+	fakeChannelCapabilities.BlockExtensionHashReturns(true)
+	fakeChannelConfig := &mocks.ChannelConfig{}
+	fakeChannelConfig.CapabilitiesReturns(fakeChannelCapabilities)
+
+	signer := mockCrypto()
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  signer,
+			ConfigTXValidator: &mocks.ConfigTXValidator{},
+			ReadWriter:        l,
+			fakeChannelConfig: fakeChannelConfig,
+		},
+		lastBlock: protoutil.NewBlock(1, protoutil.BlockHeaderHash(lastBlock.Header)),
+	}
+	bw.lastBlock.Extension.ExtensionData = [][]byte{[]byte("extension")}
+
+	bw.commitBlock([]byte("bar"))
+
+	it, seq := l.Iterator(&orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{}})
+	assert.Equal(t, uint64(1), seq)
+	committedBlock, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+
+	extensionHash, err := protoutil.VerifyBlockExtensionHash(committedBlock)
+	require.NoError(t, err)
+	assert.Equal(t, protoutil.BlockExtensionHash(committedBlock.Extension), extensionHash)
+
+	md := protoutil.GetMetadataFromBlockOrPanic(committedBlock, cb.BlockMetadataIndex_SIGNATURES)
+	require.Len(t, md.Signatures, 1)
+	signedData := util.ConcatenateBytes(md.Value, md.Signatures[0].SignatureHeader, protoutil.BlockHeaderBytes(committedBlock.Header), extensionHash)
+	require.Equal(t, 1, signer.SignCallCount())
+	assert.Equal(t, signedData, signer.SignArgsForCall(0))
+
+	committedBlock.Extension.ExtensionData = [][]byte{[]byte("tampered extension")}
+	_, err = protoutil.VerifyBlockExtensionHash(committedBlock)
+	assert.Error(t, err)
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
@@ -233,6 +404,8 @@ func TestGoodWriteConfig(t *testing.T) {
 			fakeConfig:        fakeConfig,
 			bccsp:             cryptoProvider,
 		},
+		nil,
+		nil,
 	)
 
 	ctx := makeConfigTxFull("testchannelid", 1)
@@ -281,6 +454,8 @@ func TestMigrationWriteConfig(t *testing.T) {
 			fakeConfig:        fakeConfig,
 			bccsp:             cryptoProvider,
 		},
+		nil,
+		nil,
 	)
 
 	ctx := makeConfigTxMig("testchannelid", 1)
@@ -327,6 +502,8 @@ func TestRaceWriteConfig(t *testing.T) {
 			fakeConfig:        fakeConfig,
 			bccsp:             cryptoProvider,
 		},
+		nil,
+		nil,
 	)
 
 	ctx := makeConfigTxFull("testchannelid", 1)
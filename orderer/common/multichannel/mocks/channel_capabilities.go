@@ -8,6 +8,16 @@ import (
 )
 
 type ChannelCapabilities struct {
+	BlockExtensionHashStub        func() bool
+	blockExtensionHashMutex       sync.RWMutex
+	blockExtensionHashArgsForCall []struct {
+	}
+	blockExtensionHashReturns struct {
+		result1 bool
+	}
+	blockExtensionHashReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ConsensusTypeMigrationStub        func() bool
 	consensusTypeMigrationMutex       sync.RWMutex
 	consensusTypeMigrationArgsForCall []struct {
@@ -52,6 +62,58 @@ type ChannelCapabilities struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ChannelCapabilities) BlockExtensionHash() bool {
+	fake.blockExtensionHashMutex.Lock()
+	ret, specificReturn := fake.blockExtensionHashReturnsOnCall[len(fake.blockExtensionHashArgsForCall)]
+	fake.blockExtensionHashArgsForCall = append(fake.blockExtensionHashArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BlockExtensionHash", []interface{}{})
+	fake.blockExtensionHashMutex.Unlock()
+	if fake.BlockExtensionHashStub != nil {
+		return fake.BlockExtensionHashStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.blockExtensionHashReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) BlockExtensionHashCallCount() int {
+	fake.blockExtensionHashMutex.RLock()
+	defer fake.blockExtensionHashMutex.RUnlock()
+	return len(fake.blockExtensionHashArgsForCall)
+}
+
+func (fake *ChannelCapabilities) BlockExtensionHashCalls(stub func() bool) {
+	fake.blockExtensionHashMutex.Lock()
+	defer fake.blockExtensionHashMutex.Unlock()
+	fake.BlockExtensionHashStub = stub
+}
+
+func (fake *ChannelCapabilities) BlockExtensionHashReturns(result1 bool) {
+	fake.blockExtensionHashMutex.Lock()
+	defer fake.blockExtensionHashMutex.Unlock()
+	fake.BlockExtensionHashStub = nil
+	fake.blockExtensionHashReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) BlockExtensionHashReturnsOnCall(i int, result1 bool) {
+	fake.blockExtensionHashMutex.Lock()
+	defer fake.blockExtensionHashMutex.Unlock()
+	fake.BlockExtensionHashStub = nil
+	if fake.blockExtensionHashReturnsOnCall == nil {
+		fake.blockExtensionHashReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.blockExtensionHashReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ChannelCapabilities) ConsensusTypeMigration() bool {
 	fake.consensusTypeMigrationMutex.Lock()
 	ret, specificReturn := fake.consensusTypeMigrationReturnsOnCall[len(fake.consensusTypeMigrationArgsForCall)]
@@ -263,6 +325,8 @@ func (fake *ChannelCapabilities) SupportedReturnsOnCall(i int, result1 error) {
 func (fake *ChannelCapabilities) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.blockExtensionHashMutex.RLock()
+	defer fake.blockExtensionHashMutex.RUnlock()
 	fake.consensusTypeMigrationMutex.RLock()
 	defer fake.consensusTypeMigrationMutex.RUnlock()
 	fake.mSPVersionMutex.RLock()
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/extensionproducer"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/common/types"
@@ -108,6 +109,8 @@ type Registrar struct {
 	templator          msgprocessor.ChannelConfigTemplator
 	callbacks          []channelconfig.BundleActor
 	bccsp              bccsp.BCCSP
+	extensionProducers []extensionproducer.Producer
+	extensionMetrics   *extensionproducer.Metrics
 }
 
 // ConfigBlock retrieves the last configuration block from the given ledger.
@@ -147,10 +150,24 @@ func NewRegistrar(
 		ledgerFactory:      ledgerFactory,
 		signer:             signer,
 		blockcutterMetrics: blockcutter.NewMetrics(metricsProvider),
+		extensionMetrics:   extensionproducer.NewMetrics(metricsProvider),
 		callbacks:          callbacks,
 		bccsp:              bccsp,
 	}
 
+	var producerConfigs []extensionproducer.ProducerConfig
+	for _, producer := range config.BlockExtensions.Producers {
+		producerConfigs = append(producerConfigs, extensionproducer.ProducerConfig{
+			Name:    producer.Name,
+			Options: producer.Options,
+		})
+	}
+	var err error
+	r.extensionProducers, err = extensionproducer.NewProducers(producerConfigs)
+	if err != nil {
+		logger.Panicf("Failed creating block extension producers: %s", err)
+	}
+
 	return r
 }
 
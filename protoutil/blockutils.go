@@ -10,10 +10,13 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/asn1"
+	"fmt"
 	"math/big"
 
 	gurkhaB "github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/pkg/errors"
 )
 
@@ -68,6 +71,42 @@ func BlockDataHash(b *gurkhaB.BlockData) []byte {
 	return sum[:]
 }
 
+// BlockMetadataIndexExtensionHash is the index of the block metadata entry in which
+// the orderer records the hash of the block extension. It follows the last index
+// defined by common.BlockMetadataIndex.
+const BlockMetadataIndexExtensionHash = gurkhaB.BlockMetadataIndex_COMMIT_HASH + 1
+
+type asn1Extension struct {
+	ExtensionData [][]byte
+}
+
+// BlockExtensionBytes returns the ASN.1 encoding of the block extension over which
+// the extension hash is computed. A nil extension encodes the same as an empty one.
+func BlockExtensionBytes(e *gurkhaB.BlockExtension) []byte {
+	asn1Extension := asn1Extension{
+		ExtensionData: [][]byte{},
+	}
+	if e != nil && e.ExtensionData != nil {
+		asn1Extension.ExtensionData = e.ExtensionData
+	}
+	result, err := asn1.Marshal(asn1Extension)
+	if err != nil {
+		// The extension only consists of byte slices which are always encodable,
+		// hence an error here is fatal and should not be propagated
+		panic(err)
+	}
+	return result
+}
+
+// BlockExtensionHash returns the SHA-256 hash of the ASN.1 encoding of the entries of the
+// block extension, as returned by BlockExtensionBytes. The result is never nil: a nil
+// extension hashes the same as an empty one, so the hash of a block without an extension
+// is the hash of the encoding of an empty list of entries.
+func BlockExtensionHash(e *gurkhaB.BlockExtension) []byte {
+	sum := sha256.Sum256(BlockExtensionBytes(e))
+	return sum[:]
+}
+
 // GetChannelIDFromBlockBytes returns channel ID given byte array which represents
 // the block
 func GetChannelIDFromBlockBytes(bytes []byte) (string, error) {
@@ -201,6 +240,228 @@ func GetLastConfigIndexFromBlockOrPanic(block *gurkhaB.Block) uint64 {
 	return index
 }
 
+// SetExtensionHashInBlock records the hash of the block extension in the block metadata
+func SetExtensionHashInBlock(block *gurkhaB.Block) {
+	InitBlockMetadata(block)
+	for len(block.Metadata.Metadata) <= int(BlockMetadataIndexExtensionHash) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	block.Metadata.Metadata[BlockMetadataIndexExtensionHash] = MarshalOrPanic(&gurkhaB.Metadata{
+		Value: BlockExtensionHash(block.Extension),
+	})
+}
+
+// GetExtensionHashFromBlock retrieves the hash of the block extension that is recorded
+// in the block metadata. A nil hash is returned if the block does not carry the hash,
+// which is the case for the blocks created before the orderer started committing to
+// the block extension.
+func GetExtensionHashFromBlock(block *gurkhaB.Block) ([]byte, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(BlockMetadataIndexExtensionHash) ||
+		len(block.Metadata.Metadata[BlockMetadataIndexExtensionHash]) == 0 {
+		return nil, nil
+	}
+	md, err := GetMetadataFromBlock(block, BlockMetadataIndexExtensionHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(md.Value) == 0 {
+		return nil, errors.New("empty block extension hash in block metadata")
+	}
+	return md.Value, nil
+}
+
+// VerifyBlockExtensionHash checks that the hash of the block extension matches the hash
+// recorded in the block metadata, if any. It returns the recorded hash, which is expected
+// to be included in the data that is verified against the orderer signatures so that
+// the hash itself cannot be tampered with.
+func VerifyBlockExtensionHash(block *gurkhaB.Block) ([]byte, error) {
+	extensionHash, err := GetExtensionHashFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if extensionHash == nil {
+		return nil, nil
+	}
+	if actualHash := BlockExtensionHash(block.Extension); !bytes.Equal(extensionHash, actualHash) {
+		return nil, &BlockExtensionMismatchError{
+			ExtensionHash: extensionHash,
+			ActualHash:    actualHash,
+		}
+	}
+	return extensionHash, nil
+}
+
+// BlockExtensionMismatchError is returned when the extension of a block does not match
+// the hash of the extension that is recorded in the block metadata.
+type BlockExtensionMismatchError struct {
+	ExtensionHash []byte
+	ActualHash    []byte
+}
+
+func (e *BlockExtensionMismatchError) Error() string {
+	return fmt.Sprintf("block extension hash [%x] does not match the hash of the block extension [%x]", e.ExtensionHash, e.ActualHash)
+}
+
+// IsBlockExtensionMismatch returns true if the cause of the error is a
+// BlockExtensionMismatchError.
+func IsBlockExtensionMismatch(err error) bool {
+	_, ok := errors.Cause(err).(*BlockExtensionMismatchError)
+	return ok
+}
+
+// NewVerifiableBlockExtension returns the extension of the block along with the header of
+// the block and the entries of the block metadata needed to verify it against the orderer
+// signatures with VerifiableBlockExtensionSignatureSet.
+func NewVerifiableBlockExtension(block *gurkhaB.Block) *blockextension.VerifiableBlockExtension {
+	verifiableExtension := &blockextension.VerifiableBlockExtension{
+		Extension: &blockextension.BlockExtension{},
+	}
+	if block.Header != nil {
+		verifiableExtension.Header = &common.BlockHeader{
+			Number:       block.Header.Number,
+			PreviousHash: block.Header.PreviousHash,
+			DataHash:     block.Header.DataHash,
+		}
+	}
+	if block.Extension != nil {
+		verifiableExtension.Extension.ExtensionData = block.Extension.ExtensionData
+	}
+	if block.Metadata != nil {
+		if len(block.Metadata.Metadata) > int(gurkhaB.BlockMetadataIndex_SIGNATURES) {
+			verifiableExtension.Signatures = block.Metadata.Metadata[gurkhaB.BlockMetadataIndex_SIGNATURES]
+		}
+		if len(block.Metadata.Metadata) > int(BlockMetadataIndexExtensionHash) {
+			verifiableExtension.ExtensionHash = block.Metadata.Metadata[BlockMetadataIndexExtensionHash]
+		}
+	}
+	return verifiableExtension
+}
+
+// VerifiableBlockExtensionSignatureSet checks that the extension matches the extension hash
+// it is delivered with, and returns the set of the orderer signatures over the header of the
+// block and the extension hash. The extension is verified once the signature set satisfies
+// the block validation policy of the channel. An error is returned if the extension hash is
+// missing, as the signatures of the blocks that predate it do not cover the extension.
+func VerifiableBlockExtensionSignatureSet(verifiableExtension *blockextension.VerifiableBlockExtension) ([]*SignedData, error) {
+	if verifiableExtension.Header == nil {
+		return nil, errors.New("verifiable block extension has no block header")
+	}
+	if len(verifiableExtension.ExtensionHash) == 0 {
+		return nil, errors.Errorf("block [%d] does not commit to its extension", verifiableExtension.Header.Number)
+	}
+	block := &gurkhaB.Block{
+		Header: &gurkhaB.BlockHeader{
+			Number:       verifiableExtension.Header.Number,
+			PreviousHash: verifiableExtension.Header.PreviousHash,
+			DataHash:     verifiableExtension.Header.DataHash,
+		},
+		Metadata: &gurkhaB.BlockMetadata{Metadata: make([][]byte, BlockMetadataIndexExtensionHash+1)},
+	}
+	if verifiableExtension.Extension != nil {
+		block.Extension = &gurkhaB.BlockExtension{ExtensionData: verifiableExtension.Extension.ExtensionData}
+	}
+	block.Metadata.Metadata[gurkhaB.BlockMetadataIndex_SIGNATURES] = verifiableExtension.Signatures
+	block.Metadata.Metadata[BlockMetadataIndexExtensionHash] = verifiableExtension.ExtensionHash
+
+	extensionHash, err := VerifyBlockExtensionHash(block)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := GetMetadataFromBlock(block, gurkhaB.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return nil, err
+	}
+	var signatureSet []*SignedData
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := UnmarshalSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed unmarshaling signature header for block [%d]", block.Header.Number)
+		}
+		signatureSet = append(signatureSet, &SignedData{
+			Identity:  shdr.Creator,
+			Data:      bytes.Join([][]byte{metadata.Value, metadataSignature.SignatureHeader, BlockHeaderBytes(block.Header), extensionHash}, nil),
+			Signature: metadataSignature.Signature,
+		})
+	}
+	return signatureSet, nil
+}
+
+// BlockExtensionSize returns the total size of the elements of the block extension,
+// which is the size that is bounded by the block extension limits of the channel.
+func BlockExtensionSize(extension *gurkhaB.BlockExtension) int {
+	if extension == nil {
+		return 0
+	}
+	size := 0
+	for _, data := range extension.ExtensionData {
+		size += len(data)
+	}
+	return size
+}
+
+// VerifyBlockExtensionLimits checks that the extension of the block does not exceed the
+// given limits. A nil limits, or a limit of zero, does not bound the extension.
+func VerifyBlockExtensionLimits(block *gurkhaB.Block, limits *blockextension.ExtensionLimits) error {
+	if limits == nil || block.Extension == nil {
+		return nil
+	}
+	if numEntries := len(block.Extension.ExtensionData); limits.MaxEntries != 0 && numEntries > int(limits.MaxEntries) {
+		return errors.Errorf("block extension carries %d entries, exceeding the maximum of %d entries", numEntries, limits.MaxEntries)
+	}
+	if size := BlockExtensionSize(block.Extension); limits.MaxBytes != 0 && size > int(limits.MaxBytes) {
+		return errors.Errorf("block extension is %d bytes, exceeding the maximum of %d bytes", size, limits.MaxBytes)
+	}
+	return nil
+}
+
+// AddBlockExtensionEntry appends the entry to the extension of the block
+func AddBlockExtensionEntry(block *gurkhaB.Block, entry *blockextension.Entry) error {
+	entryBytes, err := proto.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling block extension Entry")
+	}
+	if block.Extension == nil {
+		block.Extension = &gurkhaB.BlockExtension{}
+	}
+	block.Extension.ExtensionData = append(block.Extension.ExtensionData, entryBytes)
+	return nil
+}
+
+// FindBlockExtensionEntries returns the entries of the block extension with the given
+// type URL, in order. The elements of the extension which are not entries are skipped.
+func FindBlockExtensionEntries(extension *gurkhaB.BlockExtension, typeURL string) []*blockextension.Entry {
+	if extension == nil {
+		return nil
+	}
+	var entries []*blockextension.Entry
+	for _, data := range extension.ExtensionData {
+		entry, err := UnmarshalBlockExtensionEntry(data)
+		if err != nil || entry.TypeUrl != typeURL {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// GetBlockExtensionPayload unmarshals the payload of the first entry of the block
+// extension with the given type URL into payload, and returns the entry. A nil entry
+// is returned if the block extension does not have an entry with the given type URL.
+// An error is returned if the producer of the entry failed to compute the payload.
+func GetBlockExtensionPayload(extension *gurkhaB.BlockExtension, typeURL string, payload proto.Message) (*blockextension.Entry, error) {
+	entries := FindBlockExtensionEntries(extension, typeURL)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	if entries[0].Error != "" {
+		return nil, errors.Errorf("block extension entry %s carries no payload, its producer failed: %s", typeURL, entries[0].Error)
+	}
+	if err := proto.Unmarshal(entries[0].Payload, payload); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling the payload of block extension entry %s", typeURL)
+	}
+	return entries[0], nil
+}
+
 // CopyBlockMetadata copies metadata from one block into another
 func CopyBlockMetadata(src *gurkhaB.Block, dst *gurkhaB.Block) {
 	dst.Metadata = src.Metadata
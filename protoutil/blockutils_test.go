@@ -7,16 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package protoutil_test
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/asn1"
 	"math"
 	"testing"
 
+	"github.com/arogyaGurkha/fabric-protos-go/common"
+	cb "github.com/arogyaGurkha/fabric-protos-go/common"
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric-protos-go/common"
-	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/blockextension"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -71,6 +74,187 @@ func TestGoodBlockHeaderBytes(t *testing.T) {
 	_ = protoutil.BlockHeaderBytes(goodBlockHeaderMaxNumber) // Should not panic
 }
 
+func TestBlockExtensionHash(t *testing.T) {
+	asn1Bytes, err := asn1.Marshal(struct {
+		ExtensionData [][]byte
+	}{
+		ExtensionData: [][]byte{[]byte("foo"), []byte("bar")},
+	})
+	require.NoError(t, err)
+	extension := &cb.BlockExtension{ExtensionData: [][]byte{[]byte("foo"), []byte("bar")}}
+	extensionHash := sha256.Sum256(asn1Bytes)
+	assert.Equal(t, asn1Bytes, protoutil.BlockExtensionBytes(extension))
+	assert.Equal(t, extensionHash[:], protoutil.BlockExtensionHash(extension))
+
+	// the boundaries between the entries are part of the hash
+	assert.NotEqual(t,
+		protoutil.BlockExtensionHash(extension),
+		protoutil.BlockExtensionHash(&cb.BlockExtension{ExtensionData: [][]byte{[]byte("foobar")}}),
+	)
+
+	// nil and empty extensions hash the same
+	assert.Equal(t, protoutil.BlockExtensionHash(nil), protoutil.BlockExtensionHash(&cb.BlockExtension{}))
+}
+
+func TestBlockExtensionHashInMetadata(t *testing.T) {
+	block := protoutil.NewBlock(0, nil)
+	block.Extension.ExtensionData = [][]byte{[]byte("foo")}
+
+	extensionHash, err := protoutil.GetExtensionHashFromBlock(block)
+	assert.NoError(t, err)
+	assert.Nil(t, extensionHash)
+	extensionHash, err = protoutil.VerifyBlockExtensionHash(block)
+	assert.NoError(t, err)
+	assert.Nil(t, extensionHash)
+
+	protoutil.SetExtensionHashInBlock(block)
+	assert.Len(t, block.Metadata.Metadata, int(protoutil.BlockMetadataIndexExtensionHash)+1)
+	extensionHash, err = protoutil.GetExtensionHashFromBlock(block)
+	assert.NoError(t, err)
+	assert.Equal(t, protoutil.BlockExtensionHash(block.Extension), extensionHash)
+	extensionHash, err = protoutil.VerifyBlockExtensionHash(block)
+	assert.NoError(t, err)
+	assert.Equal(t, protoutil.BlockExtensionHash(block.Extension), extensionHash)
+
+	block.Extension.ExtensionData = [][]byte{[]byte("bar")}
+	_, err = protoutil.VerifyBlockExtensionHash(block)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the hash of the block extension")
+	assert.True(t, protoutil.IsBlockExtensionMismatch(err))
+	assert.True(t, protoutil.IsBlockExtensionMismatch(errors.WithMessage(err, "invalid block")))
+	assert.False(t, protoutil.IsBlockExtensionMismatch(errors.New("invalid block")))
+
+	block.Metadata.Metadata[protoutil.BlockMetadataIndexExtensionHash] = []byte{10, 10}
+	_, err = protoutil.GetExtensionHashFromBlock(block)
+	assert.Error(t, err)
+
+	block.Metadata.Metadata[protoutil.BlockMetadataIndexExtensionHash] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Signatures: []*cb.MetadataSignature{{Signature: []byte("sig")}},
+	})
+	_, err = protoutil.GetExtensionHashFromBlock(block)
+	assert.EqualError(t, err, "empty block extension hash in block metadata")
+}
+
+func TestVerifiableBlockExtension(t *testing.T) {
+	block := protoutil.NewBlock(4, []byte("previous hash"))
+	block.Header.DataHash = []byte("data hash")
+	block.Extension.ExtensionData = [][]byte{[]byte("foo"), []byte("bar")}
+
+	// the block does not commit to its extension
+	verifiableExtension := protoutil.NewVerifiableBlockExtension(block)
+	assert.Equal(t, block.Extension.ExtensionData, verifiableExtension.Extension.ExtensionData)
+	_, err := protoutil.VerifiableBlockExtensionSignatureSet(verifiableExtension)
+	assert.EqualError(t, err, "block [4] does not commit to its extension")
+
+	signatureHeader := protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("orderer"), Nonce: []byte("nonce")})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value: []byte("value"),
+		Signatures: []*cb.MetadataSignature{{
+			SignatureHeader: signatureHeader,
+			Signature:       []byte("signature"),
+		}},
+	})
+	protoutil.SetExtensionHashInBlock(block)
+	verifiableExtension = protoutil.NewVerifiableBlockExtension(block)
+	assert.Equal(t, uint64(4), verifiableExtension.Header.Number)
+	assert.Equal(t, block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES], verifiableExtension.Signatures)
+	assert.Equal(t, block.Metadata.Metadata[protoutil.BlockMetadataIndexExtensionHash], verifiableExtension.ExtensionHash)
+
+	signatureSet, err := protoutil.VerifiableBlockExtensionSignatureSet(verifiableExtension)
+	require.NoError(t, err)
+	require.Len(t, signatureSet, 1)
+	assert.Equal(t, []byte("orderer"), signatureSet[0].Identity)
+	assert.Equal(t, []byte("signature"), signatureSet[0].Signature)
+	expectedData := bytes.Join([][]byte{
+		[]byte("value"),
+		signatureHeader,
+		protoutil.BlockHeaderBytes(block.Header),
+		protoutil.BlockExtensionHash(block.Extension),
+	}, nil)
+	assert.Equal(t, expectedData, signatureSet[0].Data)
+
+	// a tampered extension does not match the extension hash
+	verifiableExtension.Extension.ExtensionData = [][]byte{[]byte("foo")}
+	_, err = protoutil.VerifiableBlockExtensionSignatureSet(verifiableExtension)
+	assert.True(t, protoutil.IsBlockExtensionMismatch(err))
+
+	verifiableExtension.Header = nil
+	_, err = protoutil.VerifiableBlockExtensionSignatureSet(verifiableExtension)
+	assert.EqualError(t, err, "verifiable block extension has no block header")
+}
+
+func TestBlockExtensionEntries(t *testing.T) {
+	block := protoutil.NewBlock(0, nil)
+	assert.Empty(t, protoutil.FindBlockExtensionEntries(block.Extension, "test/type"))
+	assert.Empty(t, protoutil.FindBlockExtensionEntries(nil, "test/type"))
+
+	block.Extension = nil
+	err := protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+		TypeUrl: "test/type",
+		Version: 1,
+		Payload: protoutil.MarshalOrPanic(&cb.LastConfig{Index: 1}),
+	})
+	require.NoError(t, err)
+	block.Extension.ExtensionData = append(block.Extension.ExtensionData, []byte("not an entry"))
+	err = protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+		TypeUrl: "test/other",
+		Payload: []byte("other"),
+	})
+	require.NoError(t, err)
+	err = protoutil.AddBlockExtensionEntry(block, &blockextension.Entry{
+		TypeUrl: "test/type",
+		Version: 2,
+		Payload: protoutil.MarshalOrPanic(&cb.LastConfig{Index: 2}),
+	})
+	require.NoError(t, err)
+	require.Len(t, block.Extension.ExtensionData, 4)
+
+	entry, err := protoutil.UnmarshalBlockExtensionEntry(block.Extension.ExtensionData[2])
+	require.NoError(t, err)
+	assert.Equal(t, "test/other", entry.TypeUrl)
+	_, err = protoutil.UnmarshalBlockExtensionEntry([]byte{10, 10})
+	assert.Error(t, err)
+
+	entries := protoutil.FindBlockExtensionEntries(block.Extension, "test/type")
+	require.Len(t, entries, 2)
+	assert.Equal(t, uint32(1), entries[0].Version)
+	assert.Equal(t, uint32(2), entries[1].Version)
+
+	lastConfig := &cb.LastConfig{}
+	entry, err = protoutil.GetBlockExtensionPayload(block.Extension, "test/type", lastConfig)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), entry.Version)
+	assert.Equal(t, uint64(1), lastConfig.Index)
+
+	entry, err = protoutil.GetBlockExtensionPayload(block.Extension, "test/missing", lastConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	_, err = protoutil.GetBlockExtensionPayload(block.Extension, "test/other", lastConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error unmarshaling the payload of block extension entry test/other")
+}
+
+func TestBlockExtensionLimits(t *testing.T) {
+	block := protoutil.NewBlock(0, nil)
+	block.Extension = nil
+	assert.Equal(t, 0, protoutil.BlockExtensionSize(block.Extension))
+	assert.NoError(t, protoutil.VerifyBlockExtensionLimits(block, &blockextension.ExtensionLimits{MaxBytes: 1, MaxEntries: 1}))
+
+	block.Extension = &cb.BlockExtension{
+		ExtensionData: [][]byte{[]byte("entry1"), []byte("entry2"), []byte("entry3")},
+	}
+	assert.Equal(t, 18, protoutil.BlockExtensionSize(block.Extension))
+	assert.NoError(t, protoutil.VerifyBlockExtensionLimits(block, nil))
+	assert.NoError(t, protoutil.VerifyBlockExtensionLimits(block, &blockextension.ExtensionLimits{}))
+	assert.NoError(t, protoutil.VerifyBlockExtensionLimits(block, &blockextension.ExtensionLimits{MaxBytes: 18, MaxEntries: 3}))
+
+	err := protoutil.VerifyBlockExtensionLimits(block, &blockextension.ExtensionLimits{MaxEntries: 2})
+	assert.EqualError(t, err, "block extension carries 3 entries, exceeding the maximum of 2 entries")
+	err = protoutil.VerifyBlockExtensionLimits(block, &blockextension.ExtensionLimits{MaxBytes: 17})
+	assert.EqualError(t, err, "block extension is 18 bytes, exceeding the maximum of 17 bytes")
+}
+
 func TestGetChannelIDFromBlockBytes(t *testing.T) {
 	gb, err := configtxtest.MakeGenesisBlock(testChannelID)
 	assert.NoError(t, err, "Failed to create test configuration block")
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/blockextension"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +27,12 @@ func UnmarshalBlock(encoded []byte) (*gurkhaB.Block, error) {
 	return block, errors.Wrap(err, "error unmarshaling Block")
 }
 
+// UnmarshalBlockExtensionEntry unmarshals bytes to a block extension Entry
+func UnmarshalBlockExtensionEntry(encoded []byte) (*blockextension.Entry, error) {
+	entry, err := blockextension.UnmarshalEntry(encoded)
+	return entry, errors.Wrap(err, "error unmarshaling block extension Entry")
+}
+
 // UnmarshalChaincodeDeploymentSpec unmarshals bytes to a ChaincodeDeploymentSpec
 func UnmarshalChaincodeDeploymentSpec(code []byte) (*peer.ChaincodeDeploymentSpec, error) {
 	cds := &peer.ChaincodeDeploymentSpec{}
@@ -58,20 +58,34 @@ generateHelpText \
         docs/wrappers/peer_lifecycle_chaincode_postscript.md \
         "${commands[@]}"
 
-commands=("peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel list" "peer channel signconfigtx" "peer channel update")
+commands=("peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel joinbysnapshot" "peer channel list" "peer channel signconfigtx" "peer channel update")
 generateHelpText \
         docs/source/commands/peerchannel.md \
         docs/wrappers/peer_channel_preamble.md \
         docs/wrappers/peer_channel_postscript.md \
         "${commands[@]}"
 
-commands=("peer node start" "peer node reset" "peer node rollback")
+commands=("peer node start" "peer node reset" "peer node rollback" "peer node export-blocks" "peer node import-blocks" "peer node transientstore list" "peer node transientstore purge")
 generateHelpText \
         docs/source/commands/peernode.md \
         docs/wrappers/peer_node_preamble.md \
         docs/wrappers/peer_node_postscript.md \
         "${commands[@]}"
 
+commands=("peer snapshot" "peer snapshot submitrequest" "peer snapshot cancelrequest" "peer snapshot listpending")
+generateHelpText \
+        docs/source/commands/peersnapshot.md \
+        docs/wrappers/peer_snapshot_preamble.md \
+        docs/wrappers/peer_snapshot_postscript.md \
+        "${commands[@]}"
+
+commands=("peer reconciliation" "peer reconciliation trigger" "peer reconciliation status")
+generateHelpText \
+        docs/source/commands/peerreconciliation.md \
+        docs/wrappers/peer_reconciliation_preamble.md \
+        docs/wrappers/peer_reconciliation_postscript.md \
+        "${commands[@]}"
+
 commands=("configtxgen")
 generateHelpText \
         docs/source/commands/configtxgen.md \